@@ -74,11 +74,27 @@ type Routes struct {
 	NotificationSettingForUser *mux.Router // 'api/v1/users/{user_id:[A-Za-z0-9]+}/notification_setting'
 
 	Hooks *mux.Router // 'api/v1/hooks'
-	Hook  *mux.Router // 'api/v1/hooks/{hook_id:[A-Za-z0-9]+}'
+	Hook  *mux.Router // 'api/v1/hooks/{hook_id:[A-Za-z0-9]{26}}'
+
+	HooksHistory *mux.Router // 'api/v1/hooks/history'
+	HookHistory  *mux.Router // 'api/v1/hooks/history/{history_id:[A-Za-z0-9]+}'
+
+	IncomingHooks *mux.Router // 'api/v1/hooks/incoming'
+	IncomingHook  *mux.Router // 'api/v1/hooks/incoming/{incoming_hook_id:[A-Za-z0-9]{26}}'
+
+	Bots *mux.Router // 'api/v1/bots'
+	Bot  *mux.Router // 'api/v1/bots/{bot_user_id:[A-Za-z0-9]+}'
 
 	OAuth     *mux.Router // 'api/v1/oauth'
 	OAuthApps *mux.Router // 'api/v1/oauth/apps'
 	OAuthApp  *mux.Router // 'api/v1/oauth/apps/{app_id:[A-Za-z0-9]+}'
+
+	Audits *mux.Router // 'api/v1/audits'
+
+	Health *mux.Router // 'health'
+	System *mux.Router // 'api/v1/system'
+
+	Config *mux.Router // 'api/v1/config'
 }
 
 type API struct {
@@ -156,12 +172,27 @@ func Init(globalOptionsFunc app.AppOptionCreator, root *mux.Router) *API {
 	api.BaseRoutes.NotificationSettingForUser = api.BaseRoutes.User.PathPrefix("/notification_setting").Subrouter()
 
 	api.BaseRoutes.Hooks = api.BaseRoutes.ApiRoot.PathPrefix("/hooks").Subrouter()
-	api.BaseRoutes.Hook = api.BaseRoutes.Hooks.PathPrefix("/{hook_id:[A-Za-z0-9]+}").Subrouter()
+	// /hooks/history 等と衝突しないよう、idの長さまで指定する
+	api.BaseRoutes.Hook = api.BaseRoutes.Hooks.PathPrefix("/{hook_id:[A-Za-z0-9]{26}}").Subrouter()
+	api.BaseRoutes.HooksHistory = api.BaseRoutes.Hooks.PathPrefix("/history").Subrouter()
+	api.BaseRoutes.HookHistory = api.BaseRoutes.HooksHistory.PathPrefix("/{history_id:[A-Za-z0-9]+}").Subrouter()
+	api.BaseRoutes.IncomingHooks = api.BaseRoutes.Hooks.PathPrefix("/incoming").Subrouter()
+	api.BaseRoutes.IncomingHook = api.BaseRoutes.IncomingHooks.PathPrefix("/{incoming_hook_id:[A-Za-z0-9]{26}}").Subrouter()
+
+	api.BaseRoutes.Bots = api.BaseRoutes.ApiRoot.PathPrefix("/bots").Subrouter()
+	api.BaseRoutes.Bot = api.BaseRoutes.Bots.PathPrefix("/{bot_user_id:[A-Za-z0-9]+}").Subrouter()
 
 	api.BaseRoutes.OAuth = api.BaseRoutes.ApiRoot.PathPrefix("/oauth").Subrouter()
 	api.BaseRoutes.OAuthApps = api.BaseRoutes.OAuth.PathPrefix("/apps").Subrouter()
 	api.BaseRoutes.OAuthApp = api.BaseRoutes.OAuthApps.PathPrefix("/{app_id:[A-Za-z0-9]+}").Subrouter()
 
+	api.BaseRoutes.Audits = api.BaseRoutes.ApiRoot.PathPrefix("/audits").Subrouter()
+
+	api.BaseRoutes.Health = root.PathPrefix("/health").Subrouter()
+	api.BaseRoutes.System = api.BaseRoutes.ApiRoot.PathPrefix("/system").Subrouter()
+
+	api.BaseRoutes.Config = api.BaseRoutes.ApiRoot.PathPrefix("/config").Subrouter()
+
 	api.InitTeam()
 	api.InitUserGroup()
 	api.InitCollection()
@@ -173,7 +204,12 @@ func Init(globalOptionsFunc app.AppOptionCreator, root *mux.Router) *API {
 	api.InitNotificationSetting()
 	api.InitReview()
 	api.InitWebhook()
+	api.InitIncomingWebhook()
+	api.InitBot()
 	api.InitOAuth()
+	api.InitAudit()
+	api.InitSystem()
+	api.InitConfig()
 
 	root.Handle("/api/v1/{anything:.*}", http.HandlerFunc(hello))
 
@@ -186,3 +222,4 @@ func hello(w http.ResponseWriter, _ *http.Request) {
 }
 
 var ReturnStatusOK = web.ReturnStatusOK
+var WriteCursorHeaders = web.WriteCursorHeaders
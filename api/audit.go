@@ -0,0 +1,46 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/clear-ness/qa-discussion/audit"
+	"github.com/clear-ness/qa-discussion/model"
+)
+
+func (api *API) InitAudit() {
+	api.BaseRoutes.Audits.Handle("", api.ApiSessionRequired(searchAudits)).Methods("GET")
+}
+
+func searchAudits(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !c.App.SessionHasPermissionTo(c.App.Session, model.PERMISSION_READ_AUDITS) {
+		c.SetPermissionError(model.PERMISSION_READ_AUDITS)
+		return
+	}
+
+	query := r.URL.Query()
+
+	status := query.Get("status")
+	if status != "" && status != audit.Success && status != audit.Attempt && status != audit.Fail {
+		c.SetInvalidParam("status")
+		return
+	}
+
+	options := &model.SearchAuditsOptions{
+		UserId:    query.Get("user_id"),
+		Action:    query.Get("action"),
+		Status:    status,
+		IpAddress: query.Get("ip_address"),
+		FromDate:  c.Params.FromDate,
+		ToDate:    c.Params.ToDate,
+		Page:      c.Params.Page,
+		PerPage:   c.Params.PerPage,
+	}
+
+	audits, err := c.App.SearchAudits(options)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.Write([]byte(audits.ToJson()))
+}
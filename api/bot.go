@@ -0,0 +1,184 @@
+package api
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/clear-ness/qa-discussion/audit"
+	"github.com/clear-ness/qa-discussion/model"
+)
+
+func (api *API) InitBot() {
+	api.BaseRoutes.Bots.Handle("", api.ApiSessionRequired(createBot)).Methods("POST")
+	api.BaseRoutes.Bots.Handle("", api.ApiSessionRequired(getBots)).Methods("GET")
+
+	api.BaseRoutes.Bot.Handle("", api.ApiSessionRequired(getBot)).Methods("GET")
+	api.BaseRoutes.Bot.Handle("/patch", api.ApiSessionRequired(patchBot)).Methods("PUT")
+	api.BaseRoutes.Bot.Handle("", api.ApiSessionRequired(deleteBot)).Methods("DELETE")
+	api.BaseRoutes.Bot.Handle("/icon", api.ApiSessionRequired(setBotIconImage)).Methods("POST")
+}
+
+func createBot(c *Context, w http.ResponseWriter, r *http.Request) {
+	bot := model.BotFromJson(r.Body)
+	if bot == nil {
+		c.SetInvalidParam("bot")
+		return
+	}
+
+	if !c.App.SessionHasPermissionToTeam(c.App.Session, bot.TeamId, model.PERMISSION_MANAGE_BOTS) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_BOTS)
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("createBot", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("team_id", bot.TeamId)
+	auditRec.AddMeta("username", bot.Username)
+
+	bot.OwnerId = c.App.Session.UserId
+
+	rbot, err := c.App.CreateBot(bot)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	auditRec.AddMeta("bot_user_id", rbot.UserId)
+	auditRec.Success()
+
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte(rbot.ToJson()))
+}
+
+func getBots(c *Context, w http.ResponseWriter, r *http.Request) {
+	teamId := r.URL.Query().Get("team_id")
+	if len(teamId) != 26 {
+		c.SetInvalidParam("team_id")
+		return
+	}
+
+	if !c.App.SessionHasPermissionToTeam(c.App.Session, teamId, model.PERMISSION_MANAGE_BOTS) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_BOTS)
+		return
+	}
+
+	bots, err := c.App.GetBotsForTeamPage(teamId, c.Params.Page, c.Params.PerPage)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.Write([]byte(model.BotListToJson(bots)))
+}
+
+func getBot(c *Context, w http.ResponseWriter, r *http.Request) {
+	bot := getBotForManage(c)
+	if c.Err != nil {
+		return
+	}
+
+	w.Write([]byte(bot.ToJson()))
+}
+
+func patchBot(c *Context, w http.ResponseWriter, r *http.Request) {
+	patch := model.BotPatchFromJson(r.Body)
+	if patch == nil {
+		c.SetInvalidParam("bot")
+		return
+	}
+
+	bot := getBotForManage(c)
+	if c.Err != nil {
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("patchBot", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("team_id", bot.TeamId)
+	auditRec.AddMeta("bot_user_id", bot.UserId)
+
+	rbot, err := c.App.PatchBot(bot, patch)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	auditRec.Success()
+
+	w.Write([]byte(rbot.ToJson()))
+}
+
+func deleteBot(c *Context, w http.ResponseWriter, r *http.Request) {
+	bot := getBotForManage(c)
+	if c.Err != nil {
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("deleteBot", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("team_id", bot.TeamId)
+	auditRec.AddMeta("bot_user_id", bot.UserId)
+
+	if err := c.App.DeleteBot(bot, c.App.Session.UserId); err != nil {
+		c.Err = err
+		return
+	}
+
+	auditRec.Success()
+	ReturnStatusOK(w)
+}
+
+func setBotIconImage(c *Context, w http.ResponseWriter, r *http.Request) {
+	defer io.Copy(ioutil.Discard, r.Body)
+
+	bot := getBotForManage(c)
+	if c.Err != nil {
+		return
+	}
+
+	if r.ContentLength > *c.App.Config().FileSettings.MaxFileSize {
+		c.Err = model.NewAppError("setBotIconImage", "api.bot.set_bot_icon_image.too_large.app_error", nil, "", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	if err := r.ParseMultipartForm(*c.App.Config().FileSettings.MaxFileSize); err != nil {
+		c.Err = model.NewAppError("setBotIconImage", "api.bot.set_bot_icon_image.parse.app_error", nil, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	imageArray, ok := r.MultipartForm.File["image"]
+	if !ok || len(imageArray) <= 0 {
+		c.Err = model.NewAppError("setBotIconImage", "api.bot.set_bot_icon_image.no_file.app_error", nil, "", http.StatusBadRequest)
+		return
+	}
+
+	link, err := c.App.SetBotIconImage(bot.UserId, imageArray[0])
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.Write([]byte(model.MapToJson(map[string]string{"user_id": bot.UserId, "profile_image_link": link})))
+}
+
+// botは作成されたteamのadminが管理する
+func getBotForManage(c *Context) *model.Bot {
+	c.RequireBotUserId()
+	if c.Err != nil {
+		return nil
+	}
+
+	bot, err := c.App.GetBot(c.Params.BotUserId)
+	if err != nil {
+		c.Err = err
+		return nil
+	}
+
+	if !c.App.SessionHasPermissionToTeam(c.App.Session, bot.TeamId, model.PERMISSION_MANAGE_BOTS) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_BOTS)
+		return nil
+	}
+
+	return bot
+}
@@ -11,7 +11,7 @@ func (api *API) InitCollection() {
 	api.BaseRoutes.Collections.Handle("", api.ApiSessionRequired(createCollection)).Methods("POST")
 
 	api.BaseRoutes.CollectionsForTeam.Handle("", api.ApiSessionRequired(getCollectionsForTeam)).Methods("GET")
-	api.BaseRoutes.CollectionsForTeam.Handle("", api.ApiSessionRequired(searchCollectionsForTeam)).Methods("POST")
+	api.BaseRoutes.CollectionsForTeam.Handle("", api.ApiSessionRequiredReadOnly(searchCollectionsForTeam)).Methods("POST")
 
 	//api.BaseRoutes.Collection.Handle("", api.ApiSessionRequired(getCollection)).Methods("GET")
 	//api.BaseRoutes.Collection.Handle("", api.ApiSessionRequired(updateCollection)).Methods("PUT")
@@ -137,12 +137,18 @@ func getCollectionsForTeam(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	cols, err := c.App.GetCollectionsForTeam(c.Params.TeamId, c.Params.Page*c.Params.PerPage, c.Params.PerPage, "")
+	cursor := c.CursorParam(model.CURSOR_TYPE_COLLECTIONS, "")
+	if c.Err != nil {
+		return
+	}
+
+	cols, cursorPage, err := c.App.GetCollectionsForTeam(c.Params.TeamId, c.Params.Page*c.Params.PerPage, c.Params.PerPage, "", cursor)
 	if err != nil {
 		c.Err = err
 		return
 	}
 
+	WriteCursorHeaders(w, cursorPage)
 	w.Write([]byte(cols.ToJson()))
 }
 
@@ -170,12 +176,18 @@ func searchCollectionsForTeam(c *Context, w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	cols, err := c.App.GetCollectionsForTeam(c.Params.TeamId, c.Params.Page*c.Params.PerPage, c.Params.PerPage, title)
+	cursor := c.CursorParam(model.CURSOR_TYPE_COLLECTIONS, "")
+	if c.Err != nil {
+		return
+	}
+
+	cols, cursorPage, err := c.App.GetCollectionsForTeam(c.Params.TeamId, c.Params.Page*c.Params.PerPage, c.Params.PerPage, title, cursor)
 	if err != nil {
 		c.Err = err
 		return
 	}
 
+	WriteCursorHeaders(w, cursorPage)
 	w.Write([]byte(cols.ToJson()))
 }
 
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/clear-ness/qa-discussion/audit"
+	"github.com/clear-ness/qa-discussion/model"
+)
+
+func (api *API) InitConfig() {
+	api.BaseRoutes.Config.Handle("", api.ApiSessionRequired(getConfig)).Methods("GET")
+	api.BaseRoutes.Config.Handle("", api.ApiSessionRequired(updateConfig)).Methods("PUT")
+	api.BaseRoutes.Config.Handle("/reload", api.ApiSessionRequired(reloadConfig)).Methods("POST")
+	api.BaseRoutes.Config.Handle("/environment", api.ApiSessionRequired(getEnvironmentConfig)).Methods("GET")
+}
+
+func getConfig(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !c.App.SessionHasPermissionTo(c.App.Session, model.PERMISSION_MANAGE_CONFIG) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_CONFIG)
+		return
+	}
+
+	w.Write([]byte(c.App.GetSanitizedConfig().ToJson()))
+}
+
+func updateConfig(c *Context, w http.ResponseWriter, r *http.Request) {
+	cfg := model.ConfigFromJson(r.Body)
+	if cfg == nil {
+		c.SetInvalidParam("config")
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("updateConfig", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+
+	if !c.App.SessionHasPermissionTo(c.App.Session, model.PERMISSION_MANAGE_CONFIG) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_CONFIG)
+		return
+	}
+
+	// 他のサーバーにもクラスタメッセージで読み直させる
+	newCfg, err := c.App.SaveConfig(cfg, true)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	auditRec.Success()
+
+	w.Write([]byte(newCfg.ToJson()))
+}
+
+func reloadConfig(c *Context, w http.ResponseWriter, r *http.Request) {
+	auditRec := c.MakeAuditRecord("reloadConfig", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+
+	if !c.App.SessionHasPermissionTo(c.App.Session, model.PERMISSION_MANAGE_CONFIG) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_CONFIG)
+		return
+	}
+
+	if err := c.App.ReloadConfig(); err != nil {
+		c.Err = err
+		return
+	}
+
+	auditRec.Success()
+
+	ReturnStatusOK(w)
+}
+
+func getEnvironmentConfig(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !c.App.SessionHasPermissionTo(c.App.Session, model.PERMISSION_MANAGE_CONFIG) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_CONFIG)
+		return
+	}
+
+	w.Write([]byte(model.StringInterfaceToJson(c.App.GetEnvironmentConfig())))
+}
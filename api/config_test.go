@@ -0,0 +1,45 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/clear-ness/qa-discussion/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateConfig(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+	Client := th.Client
+
+	_, resp := Client.GetConfig()
+	CheckForbiddenStatus(t, resp)
+
+	_, appErr := th.App.UpdateUserType(th.BasicUser2.Id, model.USER_TYPE_ADMIN)
+	require.Nil(t, appErr)
+	defer th.App.UpdateUserType(th.BasicUser2.Id, model.USER_TYPE_NORMAL)
+
+	th.LoginBasic2()
+
+	cfg, resp := Client.GetConfig()
+	CheckNoError(t, resp)
+	assert.Nil(t, cfg.SqlSettings.DataSource)
+	assert.Nil(t, cfg.RateLimitSettings.Enable)
+
+	originalCfg := th.App.Config().Clone()
+	defer th.App.SaveConfig(originalCfg, false)
+
+	cfg.TeamSettings.MaxUsersPerTeam = model.NewInt(*originalCfg.TeamSettings.MaxUsersPerTeam + 1)
+	// restrictedな項目は無視される
+	cfg.RateLimitSettings.Enable = model.NewBool(!*originalCfg.RateLimitSettings.Enable)
+
+	updated, resp := Client.UpdateConfig(cfg)
+	CheckNoError(t, resp)
+	assert.Equal(t, *originalCfg.TeamSettings.MaxUsersPerTeam+1, *updated.TeamSettings.MaxUsersPerTeam)
+
+	assert.Equal(t, *originalCfg.TeamSettings.MaxUsersPerTeam+1, *th.App.Config().TeamSettings.MaxUsersPerTeam)
+	assert.Equal(t, *originalCfg.RateLimitSettings.Enable, *th.App.Config().RateLimitSettings.Enable)
+	assert.Equal(t, *originalCfg.SqlSettings.DataSource, *th.App.Config().SqlSettings.DataSource)
+}
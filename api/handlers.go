@@ -36,6 +36,36 @@ func (api *API) ApiSessionRequired(h func(*Context, http.ResponseWriter, *http.R
 	return handler
 }
 
+// session not requires, and csrfCheckNeeded, and not recorded as a write
+func (api *API) ApiHandlerReadOnly(h func(*Context, http.ResponseWriter, *http.Request)) http.Handler {
+	handler := &web.Handler{
+		GetGlobalAppOptions: api.GetGlobalAppOptions,
+		HandleFunc:          h,
+		HandlerName:         web.GetHandlerName(h),
+		RequireSession:      false,
+		TrustRequester:      false,
+		IsStatic:            false,
+		ReadOnly:            true,
+	}
+
+	return handler
+}
+
+// session requires, and csrfCheckNeeded, and not recorded as a write
+func (api *API) ApiSessionRequiredReadOnly(h func(*Context, http.ResponseWriter, *http.Request)) http.Handler {
+	handler := &web.Handler{
+		GetGlobalAppOptions: api.GetGlobalAppOptions,
+		HandleFunc:          h,
+		HandlerName:         web.GetHandlerName(h),
+		RequireSession:      true,
+		TrustRequester:      false,
+		IsStatic:            false,
+		ReadOnly:            true,
+	}
+
+	return handler
+}
+
 // session requires, and not csrfCheckNeeded
 func (api *API) ApiSessionRequiredTrustRequester(h func(*Context, http.ResponseWriter, *http.Request)) http.Handler {
 	handler := &web.Handler{
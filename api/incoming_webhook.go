@@ -0,0 +1,211 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/clear-ness/qa-discussion/audit"
+	"github.com/clear-ness/qa-discussion/model"
+)
+
+func (api *API) InitIncomingWebhook() {
+	api.BaseRoutes.IncomingHooks.Handle("", api.ApiSessionRequired(createIncomingHook)).Methods("POST")
+	api.BaseRoutes.IncomingHooks.Handle("", api.ApiSessionRequired(getIncomingHooks)).Methods("GET")
+
+	api.BaseRoutes.IncomingHook.Handle("", api.ApiSessionRequired(getIncomingHook)).Methods("GET")
+	api.BaseRoutes.IncomingHook.Handle("", api.ApiSessionRequired(updateIncomingHook)).Methods("PUT")
+	api.BaseRoutes.IncomingHook.Handle("", api.ApiSessionRequired(deleteIncomingHook)).Methods("DELETE")
+	api.BaseRoutes.IncomingHook.Handle("/regen_token", api.ApiSessionRequired(regenIncomingHookToken)).Methods("POST")
+
+	// 外部のサービスから呼ばれるので、セッションではなくtokenで認証する
+	api.BaseRoutes.IncomingHooks.Handle("/execute/{token:[A-Za-z0-9]+}", api.ApiHandler(executeIncomingHook)).Methods("POST")
+}
+
+func createIncomingHook(c *Context, w http.ResponseWriter, r *http.Request) {
+	hook := model.IncomingWebhookFromJson(r.Body)
+	if hook == nil {
+		c.SetInvalidParam("incoming_webhook")
+		return
+	}
+
+	hook.CreatorId = c.App.Session.UserId
+
+	if !c.App.SessionHasPermissionToTeam(c.App.Session, hook.TeamId, model.PERMISSION_MANAGE_WEBHOOKS) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_WEBHOOKS)
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("createIncomingHook", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("team_id", hook.TeamId)
+	auditRec.AddMeta("bot_user_id", hook.BotUserId)
+
+	rhook, err := c.App.CreateIncomingWebhook(hook)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	auditRec.AddMeta("incoming_hook_id", rhook.Id)
+	auditRec.Success()
+
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte(rhook.ToJson()))
+}
+
+func getIncomingHooks(c *Context, w http.ResponseWriter, r *http.Request) {
+	teamId := r.URL.Query().Get("team_id")
+	if len(teamId) != 26 {
+		c.SetInvalidParam("team_id")
+		return
+	}
+
+	if !c.App.SessionHasPermissionToTeam(c.App.Session, teamId, model.PERMISSION_MANAGE_WEBHOOKS) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_WEBHOOKS)
+		return
+	}
+
+	hooks, err := c.App.GetIncomingWebhooksForTeamPage(teamId, c.Params.Page, c.Params.PerPage)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.Write([]byte(model.IncomingWebhookListToJson(hooks)))
+}
+
+func getIncomingHook(c *Context, w http.ResponseWriter, r *http.Request) {
+	hook := getIncomingHookForManage(c)
+	if c.Err != nil {
+		return
+	}
+
+	w.Write([]byte(hook.ToJson()))
+}
+
+func updateIncomingHook(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireIncomingHookId()
+	if c.Err != nil {
+		return
+	}
+
+	updatedHook := model.IncomingWebhookFromJson(r.Body)
+	if updatedHook == nil {
+		c.SetInvalidParam("incoming_webhook")
+		return
+	}
+
+	if updatedHook.Id != c.Params.IncomingHookId {
+		c.SetInvalidParam("incoming_hook_id")
+		return
+	}
+
+	oldHook := getIncomingHookForManage(c)
+	if c.Err != nil {
+		return
+	}
+
+	if updatedHook.TeamId != "" && updatedHook.TeamId != oldHook.TeamId {
+		c.Err = model.NewAppError("updateIncomingHook", "api.incoming_webhook.team_mismatch.app_error", nil, "user_id="+c.App.Session.UserId, http.StatusBadRequest)
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("updateIncomingHook", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("team_id", oldHook.TeamId)
+	auditRec.AddMeta("incoming_hook_id", oldHook.Id)
+
+	rhook, err := c.App.UpdateIncomingWebhook(oldHook, updatedHook)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	auditRec.Success()
+
+	w.Write([]byte(rhook.ToJson()))
+}
+
+func deleteIncomingHook(c *Context, w http.ResponseWriter, r *http.Request) {
+	hook := getIncomingHookForManage(c)
+	if c.Err != nil {
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("deleteIncomingHook", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("team_id", hook.TeamId)
+	auditRec.AddMeta("incoming_hook_id", hook.Id)
+
+	if err := c.App.DeleteIncomingWebhook(hook.Id); err != nil {
+		c.Err = err
+		return
+	}
+
+	auditRec.Success()
+	ReturnStatusOK(w)
+}
+
+func regenIncomingHookToken(c *Context, w http.ResponseWriter, r *http.Request) {
+	hook := getIncomingHookForManage(c)
+	if c.Err != nil {
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("regenIncomingHookToken", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("team_id", hook.TeamId)
+	auditRec.AddMeta("incoming_hook_id", hook.Id)
+
+	rhook, err := c.App.RegenIncomingWebhookToken(hook)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	auditRec.Success()
+
+	w.Write([]byte(rhook.ToJson()))
+}
+
+// incoming webhookはbotとして投稿するので、作成者に関わらずteamのwebhook管理者が扱える
+func getIncomingHookForManage(c *Context) *model.IncomingWebhook {
+	c.RequireIncomingHookId()
+	if c.Err != nil {
+		return nil
+	}
+
+	hook, err := c.App.GetIncomingWebhook(c.Params.IncomingHookId)
+	if err != nil {
+		c.Err = err
+		return nil
+	}
+
+	if !c.App.SessionHasPermissionToTeam(c.App.Session, hook.TeamId, model.PERMISSION_MANAGE_WEBHOOKS) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_WEBHOOKS)
+		return nil
+	}
+
+	return hook
+}
+
+func executeIncomingHook(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireHookToken()
+	if c.Err != nil {
+		return
+	}
+
+	req, jsonErr := model.IncomingWebhookRequestFromJson(r.Body)
+	if jsonErr != nil || req == nil {
+		c.SetInvalidParam("incoming_webhook_request")
+		return
+	}
+
+	post, err := c.App.ExecuteIncomingWebhook(c.Params.HookToken, req)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte(post.ToJson()))
+}
@@ -3,6 +3,7 @@ package api
 import (
 	"net/http"
 
+	"github.com/clear-ness/qa-discussion/audit"
 	"github.com/clear-ness/qa-discussion/model"
 )
 
@@ -31,6 +32,10 @@ func createOAuthApp(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	auditRec := c.MakeAuditRecord("createOAuthApp", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("name", oauthApp.Name)
+
 	oauthApp.UserId = c.App.Session.UserId
 
 	rapp, err := c.App.CreateOAuthApp(oauthApp)
@@ -39,6 +44,9 @@ func createOAuthApp(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	auditRec.AddMeta("app_id", rapp.Id)
+	auditRec.Success()
+
 	w.WriteHeader(http.StatusCreated)
 	w.Write([]byte(rapp.ToJson()))
 }
@@ -76,12 +84,18 @@ func updateOAuthApp(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	auditRec := c.MakeAuditRecord("updateOAuthApp", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("app_id", oldOauthApp.Id)
+
 	updatedOauthApp, err := c.App.UpdateOauthApp(oldOauthApp, oauthApp)
 	if err != nil {
 		c.Err = err
 		return
 	}
 
+	auditRec.Success()
+
 	w.Write([]byte(updatedOauthApp.ToJson()))
 }
 
@@ -150,12 +164,17 @@ func deleteOAuthApp(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	auditRec := c.MakeAuditRecord("deleteOAuthApp", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("app_id", oauthApp.Id)
+
 	err = c.App.DeleteOAuthApp(oauthApp.Id)
 	if err != nil {
 		c.Err = err
 		return
 	}
 
+	auditRec.Success()
 	ReturnStatusOK(w)
 }
 
@@ -181,12 +200,18 @@ func regenerateOAuthAppSecret(c *Context, w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	auditRec := c.MakeAuditRecord("regenerateOAuthAppSecret", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("app_id", oauthApp.Id)
+
 	oauthApp, err = c.App.RegenerateOAuthAppSecret(oauthApp)
 	if err != nil {
 		c.Err = err
 		return
 	}
 
+	auditRec.Success()
+
 	w.Write([]byte(oauthApp.ToJson()))
 }
 
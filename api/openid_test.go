@@ -0,0 +1,298 @@
+package api
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/clear-ness/qa-discussion/audit"
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/clear-ness/qa-discussion/web"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testOpenIdClientId = "qa-discussion-test"
+
+type mockIdP struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	nonce  string
+	claims map[string]interface{}
+}
+
+func newMockIdP(t *testing.T) *mockIdP {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err)
+
+	idp := &mockIdP{key: key}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 idp.server.URL,
+			"authorization_endpoint": idp.server.URL + "/authorize",
+			"token_endpoint":         idp.server.URL + "/token",
+			"jwks_uri":               idp.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kid": "test-key",
+				"kty": "RSA",
+				"alg": "RS256",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.PublicKey.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.PublicKey.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		clientId, _, ok := r.BasicAuth()
+		if !ok || clientId != testOpenIdClientId || r.FormValue("code") != "valid-code" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": model.NewId(),
+			"token_type":   "Bearer",
+			"id_token":     idp.signIdToken(t),
+		})
+	})
+
+	idp.server = httptest.NewServer(mux)
+	return idp
+}
+
+func (idp *mockIdP) signIdToken(t *testing.T) string {
+	claims := map[string]interface{}{
+		"iss":   idp.server.URL,
+		"aud":   []string{testOpenIdClientId},
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"iat":   time.Now().Unix(),
+		"nonce": idp.nonce,
+	}
+	for k, v := range idp.claims {
+		claims[k] = v
+	}
+
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": "test-key", "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	hashed := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, idp.key, crypto.SHA256, hashed[:])
+	require.Nil(t, err)
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+type auditCapture struct {
+	records chan *audit.Record
+}
+
+func (c *auditCapture) Log(rec *audit.Record) error {
+	c.records <- rec
+	return nil
+}
+
+func (c *auditCapture) Shutdown() error {
+	return nil
+}
+
+// 条件に合うレコードが書き出されるまで待つ
+func (c *auditCapture) next(t *testing.T, match func(*audit.Record) bool) *audit.Record {
+	for {
+		select {
+		case rec := <-c.records:
+			if match(rec) {
+				return rec
+			}
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "audit record was not logged")
+			return nil
+		}
+	}
+}
+
+func TestOpenIdLogin(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	web.New(th.Server, th.Server.AppOptions, th.Server.Router)
+
+	capture := &auditCapture{records: make(chan *audit.Record, 100)}
+	th.Server.Audit.AddTarget(capture)
+
+	idp := newMockIdP(t)
+	defer idp.server.Close()
+
+	settings := &th.App.Config().OpenIdSettings
+	oldSettings := *settings
+	defer func() { *settings = oldSettings }()
+	settings.Enable = model.NewBool(true)
+	settings.Issuer = model.NewString(idp.server.URL)
+	settings.ClientId = model.NewString(testOpenIdClientId)
+	settings.ClientSecret = model.NewString("secret")
+	settings.AutoJoinTeams = model.NewBool(true)
+
+	team, err := th.App.CreateTeam(&model.Team{
+		Name:           "openid" + model.NewRandomString(10),
+		Type:           model.TEAM_TYPE_PUBLIC,
+		Email:          "admin@example.com",
+		AllowedDomains: "example.com",
+	})
+	require.Nil(t, err)
+
+	jar, _ := cookiejar.New(nil)
+	httpClient := &http.Client{
+		Jar: jar,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	startLogin := func(t *testing.T) url.Values {
+		resp, err := httpClient.Get(th.Client.Url + "/login/openid?redirect_to=/questions")
+		require.Nil(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusFound, resp.StatusCode)
+
+		location, err := url.Parse(resp.Header.Get("Location"))
+		require.Nil(t, err)
+		assert.Equal(t, idp.server.URL+"/authorize", location.Scheme+"://"+location.Host+location.Path)
+		assert.Equal(t, testOpenIdClientId, location.Query().Get("client_id"))
+
+		return location.Query()
+	}
+
+	completeLogin := func(t *testing.T, code, state string) *http.Response {
+		resp, err := httpClient.Get(th.Client.Url + "/login/openid/complete?code=" + code + "&state=" + url.QueryEscape(state))
+		require.Nil(t, err)
+		resp.Body.Close()
+		return resp
+	}
+
+	t.Run("invalid redirect", func(t *testing.T) {
+		resp, err := httpClient.Get(th.Client.Url + "/login/openid?redirect_to=//evil.example.com")
+		require.Nil(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("provisions user and joins team", func(t *testing.T) {
+		idp.claims = map[string]interface{}{
+			"sub":                "idp-user-1",
+			"email":              "openid-user@example.com",
+			"email_verified":     true,
+			"preferred_username": "openid-user",
+		}
+
+		query := startLogin(t)
+		idp.nonce = query.Get("nonce")
+
+		resp := completeLogin(t, "valid-code", query.Get("state"))
+		require.Equal(t, http.StatusFound, resp.StatusCode)
+		assert.Contains(t, resp.Header.Get("Location"), "/questions")
+		assert.NotEmpty(t, resp.Header.Get(model.HEADER_TOKEN))
+
+		authData := "idp-user-1"
+		user, err := th.App.Srv.Store.User().GetByAuth(&authData, model.USER_AUTH_SERVICE_OPENID)
+		require.Nil(t, err)
+		assert.Equal(t, "openid-user@example.com", user.Email)
+		assert.Equal(t, "openid-user", user.Username)
+		assert.True(t, user.EmailVerified)
+
+		rec := capture.next(t, func(rec *audit.Record) bool { return rec.Event == "completeOpenIdLogin" })
+		assert.Equal(t, audit.Success, rec.Status)
+		assert.Equal(t, user.Id, rec.UserID)
+		assert.NotEmpty(t, rec.SessionID)
+
+		member, err := th.App.GetTeamMember(team.Id, user.Id)
+		require.Nil(t, err)
+		assert.Equal(t, int64(0), member.DeleteAt)
+
+		// パスワードではログインできない
+		_, loginResp := th.CreateClient().Login(user.Email, "Password1@")
+		CheckBadRequestStatus(t, loginResp)
+
+		// 2回目以降は同じユーザーとしてログインする
+		query = startLogin(t)
+		idp.nonce = query.Get("nonce")
+
+		resp = completeLogin(t, "valid-code", query.Get("state"))
+		require.Equal(t, http.StatusFound, resp.StatusCode)
+
+		sessions, err := th.App.GetSessions(user.Id)
+		require.Nil(t, err)
+		assert.Len(t, sessions, 2)
+	})
+
+	t.Run("state mismatch", func(t *testing.T) {
+		startLogin(t)
+
+		resp := completeLogin(t, "valid-code", model.NewRandomString(model.TOKEN_SIZE))
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("state cannot be reused", func(t *testing.T) {
+		idp.claims = map[string]interface{}{"sub": "idp-user-1", "email": "openid-user@example.com"}
+
+		query := startLogin(t)
+		idp.nonce = query.Get("nonce")
+
+		resp := completeLogin(t, "valid-code", query.Get("state"))
+		require.Equal(t, http.StatusFound, resp.StatusCode)
+
+		u, _ := url.Parse(th.Client.Url)
+		jar.SetCookies(u, []*http.Cookie{{Name: model.SESSION_COOKIE_OPENID, Value: query.Get("state"), Path: "/"}})
+
+		resp = completeLogin(t, "valid-code", query.Get("state"))
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("nonce mismatch", func(t *testing.T) {
+		query := startLogin(t)
+		idp.nonce = "wrong-nonce"
+
+		resp := completeLogin(t, "valid-code", query.Get("state"))
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("email taken by password user", func(t *testing.T) {
+		idp.claims = map[string]interface{}{"sub": "idp-user-2", "email": th.BasicUser.Email}
+
+		query := startLogin(t)
+		idp.nonce = query.Get("nonce")
+
+		resp := completeLogin(t, "valid-code", query.Get("state"))
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+		rec := capture.next(t, func(rec *audit.Record) bool {
+			return rec.Event == "completeOpenIdLogin" && rec.Meta["err"] == "api.openid.complete.email_taken.app_error"
+		})
+		assert.Equal(t, audit.Fail, rec.Status)
+		assert.Empty(t, rec.UserID)
+	})
+
+	t.Run("invalid code", func(t *testing.T) {
+		query := startLogin(t)
+		idp.nonce = query.Get("nonce")
+
+		resp := completeLogin(t, "invalid-code", query.Get("state"))
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+}
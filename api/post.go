@@ -5,9 +5,15 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/clear-ness/qa-discussion/audit"
+	"github.com/clear-ness/qa-discussion/mlog"
 	"github.com/clear-ness/qa-discussion/model"
 )
 
+const (
+	POST_DAILY_VIEWS_DEFAULT_DAYS = 7
+)
+
 func (api *API) InitPost() {
 	// GUIの左サイドバーで特定Teamを選んだ状態で「private ask」すると、
 	// そのteamに紐づくプライベートなpostとなる。
@@ -35,7 +41,9 @@ func (api *API) InitPost() {
 	api.BaseRoutes.Post.Handle("", api.ApiHandler(getPost)).Methods("GET")
 	api.BaseRoutes.PostForTeam.Handle("", api.ApiSessionRequired(getTeamPost)).Methods("GET")
 
-	api.BaseRoutes.Post.Handle("/view", api.ApiHandler(viewPost)).Methods("POST")
+	api.BaseRoutes.Post.Handle("/view", api.ApiHandlerReadOnly(viewPost)).Methods("POST")
+	api.BaseRoutes.Post.Handle("/views/daily", api.ApiHandler(getPostDailyViews)).Methods("GET")
+	api.BaseRoutes.PostForTeam.Handle("/views/daily", api.ApiSessionRequired(getTeamPostDailyViews)).Methods("GET")
 
 	api.BaseRoutes.Post.Handle("/comments", api.ApiHandler(getCommentsForPost)).Methods("GET")
 	api.BaseRoutes.PostForTeam.Handle("/comments", api.ApiSessionRequired(getCommentsForTeamPost)).Methods("GET")
@@ -65,11 +73,11 @@ func (api *API) InitPost() {
 	api.BaseRoutes.Post.Handle("/cancel_flag", api.ApiSessionRequired(cancelFlagPost)).Methods("POST")
 
 	// TODO: sort by post.views
-	api.BaseRoutes.Posts.Handle("/search", api.ApiHandler(searchPosts)).Methods("POST")
-	api.BaseRoutes.PostsForTeam.Handle("/search", api.ApiSessionRequired(searchPostsForTeam)).Methods("POST")
+	api.BaseRoutes.Posts.Handle("/search", api.ApiHandlerReadOnly(searchPosts)).Methods("POST")
+	api.BaseRoutes.PostsForTeam.Handle("/search", api.ApiSessionRequiredReadOnly(searchPostsForTeam)).Methods("POST")
 
-	api.BaseRoutes.Posts.Handle("/advanced_search", api.ApiHandler(advancedSearchPosts)).Methods("POST")
-	api.BaseRoutes.PostsForTeam.Handle("/advanced_search", api.ApiSessionRequired(advancedSearchPostsForTeam)).Methods("POST")
+	api.BaseRoutes.Posts.Handle("/advanced_search", api.ApiHandlerReadOnly(advancedSearchPosts)).Methods("POST")
+	api.BaseRoutes.PostsForTeam.Handle("/advanced_search", api.ApiSessionRequiredReadOnly(advancedSearchPostsForTeam)).Methods("POST")
 
 	api.BaseRoutes.Posts.Handle("/similar", api.ApiHandler(similarPosts)).Methods("POST")
 	api.BaseRoutes.PostsForTeam.Handle("/similar", api.ApiSessionRequired(similarPostsForTeam)).Methods("POST")
@@ -385,14 +393,20 @@ func getPosts(c *Context, w http.ResponseWriter, r *http.Request, options *model
 		options.Max = c.Params.Max
 	}
 
-	posts, totalCount, err := c.App.GetPosts(options, getComments, getParent, checkVoted, limitContent)
+	options.Cursor = c.CursorParam(model.CURSOR_TYPE_POSTS, options.SortType)
+	if c.Err != nil {
+		return
+	}
+
+	posts, totalCount, cursorPage, err := c.App.GetPosts(options, getComments, getParent, checkVoted, limitContent)
 	if err != nil {
 		c.Err = err
 		return
 	}
 
-	data := model.PostsWithCount{Posts: posts, TotalCount: totalCount}
+	data := model.PostsWithCount{Posts: posts, TotalCount: totalCount, CursorPage: cursorPage}
 
+	WriteCursorHeaders(w, cursorPage)
 	w.Write([]byte(data.ToJson()))
 }
 
@@ -459,7 +473,67 @@ func viewPost(c *Context, w http.ResponseWriter, r *http.Request) {
 		userId = c.App.Session.UserId
 	}
 
-	c.App.ViewPost(post, userId, ipAddress)
+	// 閲覧数の記録に失敗しても閲覧自体は妨げない
+	if err := c.App.ViewPost(post, userId, ipAddress); err != nil {
+		mlog.Warn("Failed to record post view", mlog.String("post_id", post.Id), mlog.Err(err))
+	}
+
+	ReturnStatusOK(w)
+}
+
+func getPostDailyViews(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequirePostId()
+	if c.Err != nil {
+		return
+	}
+
+	writePostDailyViews(c, w, r, "")
+}
+
+func getTeamPostDailyViews(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireTeamId().RequirePostId()
+	if c.Err != nil {
+		return
+	}
+
+	if !c.App.SessionHasPermissionToTeam(c.App.Session, c.Params.TeamId, model.PERMISSION_VIEW_TEAM_POST) {
+		c.SetPermissionError(model.PERMISSION_VIEW_TEAM_POST)
+		return
+	}
+
+	writePostDailyViews(c, w, r, c.Params.TeamId)
+}
+
+// daysを省略すると過去7日分を返す
+func writePostDailyViews(c *Context, w http.ResponseWriter, r *http.Request, teamId string) {
+	days := POST_DAILY_VIEWS_DEFAULT_DAYS
+	if val := r.URL.Query().Get("days"); len(val) > 0 {
+		var err error
+		if days, err = strconv.Atoi(val); err != nil {
+			c.SetInvalidUrlParam("days")
+			return
+		}
+	}
+
+	post, err := c.App.GetPost(c.Params.PostId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	// チームの投稿はチームのAPIからのみ参照させる
+	if post.Type != model.POST_TYPE_QUESTION || post.TeamId != teamId {
+		c.Err = model.NewAppError("getPostDailyViews", "api.post.get_post_daily_views.not_found.app_error", nil, "", http.StatusNotFound)
+		return
+	}
+
+	rows, err := c.App.GetPostDailyUniqueViews(post.Id, days)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.Write([]byte(rows.ToJson()))
 }
 
 func createQuestionPost(c *Context, w http.ResponseWriter, r *http.Request) {
@@ -726,11 +800,19 @@ func deletePost(c *Context, w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	auditRec := c.MakeAuditRecord("deletePost", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("post_id", post.Id)
+	auditRec.AddMeta("post_type", post.Type)
+	auditRec.AddMeta("author_id", post.UserId)
+	auditRec.AddMeta("team_id", post.TeamId)
+
 	if _, err := c.App.DeletePost(post, c.App.Session.UserId); err != nil {
 		c.Err = err
 		return
 	}
 
+	auditRec.Success()
 	ReturnStatusOK(w)
 }
 
@@ -1297,11 +1379,16 @@ func lockPost(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	auditRec := c.MakeAuditRecord("lockPost", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("post_id", c.Params.PostId)
+
 	if err := c.App.LockPost(c.Params.PostId, c.App.Session.UserId); err != nil {
 		c.Err = err
 		return
 	}
 
+	auditRec.Success()
 	ReturnStatusOK(w)
 }
 
@@ -1316,11 +1403,16 @@ func cancelLockPost(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	auditRec := c.MakeAuditRecord("cancelLockPost", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("post_id", c.Params.PostId)
+
 	if err := c.App.CancelLockPost(c.Params.PostId, c.App.Session.UserId); err != nil {
 		c.Err = err
 		return
 	}
 
+	auditRec.Success()
 	ReturnStatusOK(w)
 }
 
@@ -1335,11 +1427,16 @@ func protectPost(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	auditRec := c.MakeAuditRecord("protectPost", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("post_id", c.Params.PostId)
+
 	if err := c.App.ProtectPost(c.Params.PostId, c.App.Session.UserId); err != nil {
 		c.Err = err
 		return
 	}
 
+	auditRec.Success()
 	ReturnStatusOK(w)
 }
 
@@ -1354,10 +1451,15 @@ func cancelProtectPost(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	auditRec := c.MakeAuditRecord("cancelProtectPost", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("post_id", c.Params.PostId)
+
 	if err := c.App.CancelProtectPost(c.Params.PostId, c.App.Session.UserId); err != nil {
 		c.Err = err
 		return
 	}
 
+	auditRec.Success()
 	ReturnStatusOK(w)
 }
@@ -1192,3 +1192,43 @@ func TestAdvancedSearchPosts(t *testing.T) {
 	CheckNoError(t, resp)
 	require.Len(t, data.Posts, 0, "invalid search")
 }
+
+func TestViewPost(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+	Client := th.Client
+
+	question, resp := Client.CreateQuestion(&model.Post{Title: "view title", Content: "view content"})
+	CheckNoError(t, resp)
+
+	// 同じユーザーの閲覧は1日に1回だけ数える
+	for i := 0; i < 3; i++ {
+		_, resp = Client.ViewPost(question.Id)
+		CheckNoError(t, resp)
+	}
+
+	Client.HttpHeader = map[string]string{"User-Agent": "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)"}
+	th.LoginBasic2()
+	_, resp = Client.ViewPost(question.Id)
+	CheckNoError(t, resp)
+	Client.HttpHeader = nil
+
+	rows, resp := Client.GetPostDailyViews(question.Id, 3)
+	CheckNoError(t, resp)
+	require.Len(t, rows, 3)
+	assert.Equal(t, float64(1), rows[0].Value)
+
+	post, resp := Client.GetPost(question.Id)
+	CheckNoError(t, resp)
+	assert.Equal(t, 0, post.Views)
+
+	_, err := th.App.Srv.Store.Post().FlushBufferedViews(100)
+	require.Nil(t, err)
+
+	post, resp = Client.GetPost(question.Id)
+	CheckNoError(t, resp)
+	assert.Equal(t, 1, post.Views)
+
+	_, resp = Client.GetPostDailyViews(question.Id, 0)
+	CheckBadRequestStatus(t, resp)
+}
@@ -10,7 +10,7 @@ import (
 func (api *API) InitReview() {
 	api.BaseRoutes.ReviewsForPost.Handle("", api.ApiSessionRequired(createReviewVote)).Methods("POST")
 
-	api.BaseRoutes.Reviews.Handle("", api.ApiSessionRequired(searchReviews)).Methods("POST")
+	api.BaseRoutes.Reviews.Handle("", api.ApiSessionRequiredReadOnly(searchReviews)).Methods("POST")
 	api.BaseRoutes.ReviewsForPost.Handle("", api.ApiSessionRequired(getReviewsForPost)).Methods("GET")
 	api.BaseRoutes.ReviewsForUser.Handle("", api.ApiSessionRequired(getReviewsForUser)).Methods("GET")
 
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/clear-ness/qa-discussion/audit"
+	"github.com/clear-ness/qa-discussion/model"
+)
+
+func (api *API) InitSystem() {
+	// ロードバランサーやk8sのprobeから叩かれるので、セッション無しで応答する
+	api.BaseRoutes.Health.Handle("/live", api.ApiHandler(healthLive)).Methods("GET")
+	api.BaseRoutes.Health.Handle("/ready", api.ApiHandler(healthReady)).Methods("GET")
+
+	api.BaseRoutes.System.Handle("/status", api.ApiSessionRequired(getSystemStatus)).Methods("GET")
+	api.BaseRoutes.System.Handle("/jobs/reconcile_counters", api.ApiSessionRequired(reconcileCounters)).Methods("POST")
+	api.BaseRoutes.System.Handle("/jobs/{job_id:[A-Za-z0-9]+}", api.ApiSessionRequired(getSystemJob)).Methods("GET")
+}
+
+// プロセスが応答できるかだけを返し、依存先は見ない
+func healthLive(c *Context, w http.ResponseWriter, r *http.Request) {
+	ReturnStatusOK(w)
+}
+
+// 依存先のいずれかが落ちていれば503を返し、振り分け対象から外してもらう
+func healthReady(c *Context, w http.ResponseWriter, r *http.Request) {
+	status := c.App.CheckReadiness()
+	if !status.IsReady() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	w.Write([]byte(status.ToJson()))
+}
+
+func getSystemStatus(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !c.App.SessionHasPermissionTo(c.App.Session, model.PERMISSION_READ_SYSTEM_STATUS) {
+		c.SetPermissionError(model.PERMISSION_READ_SYSTEM_STATUS)
+		return
+	}
+
+	w.Write([]byte(c.App.GetSystemStatus().ToJson()))
+}
+
+// 非正規化したカウンタのずれを非同期で照合する。
+// dry_runを指定すると修正せず、ずれはjobのレポートで確認できる。
+func reconcileCounters(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !c.App.SessionHasPermissionTo(c.App.Session, model.PERMISSION_MANAGE_JOBS) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_JOBS)
+		return
+	}
+
+	dryRun, _ := strconv.ParseBool(r.URL.Query().Get("dry_run"))
+
+	auditRec := c.MakeAuditRecord("reconcileCounters", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("dry_run", dryRun)
+
+	job, err := c.App.CreateReconcileCountersJob(c.App.Session.UserId, dryRun)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	auditRec.AddMeta("job_id", job.Id)
+	auditRec.Success()
+
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte(job.ToJson()))
+}
+
+func getSystemJob(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireJobId()
+	if c.Err != nil {
+		return
+	}
+
+	if !c.App.SessionHasPermissionTo(c.App.Session, model.PERMISSION_MANAGE_JOBS) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_JOBS)
+		return
+	}
+
+	job, err := c.App.GetJob(c.Params.JobId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	// チームのjobはチームのAPIから参照する
+	if len(job.TeamId) != 0 {
+		c.Err = model.NewAppError("getSystemJob", "api.system.get_system_job.not_found.app_error", nil, "", http.StatusNotFound)
+		return
+	}
+
+	w.Write([]byte(job.ToJson()))
+}
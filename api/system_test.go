@@ -0,0 +1,109 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/clear-ness/qa-discussion/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthLive(t *testing.T) {
+	th := Setup(t)
+	defer th.TearDown()
+
+	r, err := http.Get(th.Client.Url + "/health/live")
+	require.Nil(t, err)
+	defer r.Body.Close()
+	assert.Equal(t, http.StatusOK, r.StatusCode)
+}
+
+func TestGetSystemStatus(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+	Client := th.Client
+
+	_, resp := Client.GetSystemStatus()
+	CheckForbiddenStatus(t, resp)
+
+	_, appErr := th.App.UpdateUserType(th.BasicUser2.Id, model.USER_TYPE_ADMIN)
+	require.Nil(t, appErr)
+	defer th.App.UpdateUserType(th.BasicUser2.Id, model.USER_TYPE_NORMAL)
+
+	th.LoginBasic2()
+
+	status, resp := Client.GetSystemStatus()
+	CheckNoError(t, resp)
+	assert.Equal(t, model.CurrentVersion, status.Version)
+	assert.NotEmpty(t, status.ConfigHash)
+	require.NotNil(t, status.Health)
+
+	var master *model.DependencyStatus
+	for _, dep := range status.Health.Dependencies {
+		if dep.Name == model.HEALTH_CHECK_MYSQL_MASTER {
+			master = dep
+		}
+	}
+	require.NotNil(t, master)
+	assert.Equal(t, model.HEALTH_STATUS_OK, master.Status)
+}
+
+func TestReconcileCounters(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+	Client := th.Client
+
+	question, resp := Client.CreateQuestion(&model.Post{Title: "reconcile title", Content: "reconcile content"})
+	CheckNoError(t, resp)
+	require.Nil(t, th.App.Srv.Store.Post().SetVoteCounts(question.Id, 3, 0))
+
+	_, resp = Client.ReconcileCounters(true)
+	CheckForbiddenStatus(t, resp)
+
+	_, appErr := th.App.UpdateUserType(th.BasicUser2.Id, model.USER_TYPE_ADMIN)
+	require.Nil(t, appErr)
+	defer th.App.UpdateUserType(th.BasicUser2.Id, model.USER_TYPE_NORMAL)
+
+	th.LoginBasic2()
+
+	waitForJob := func(job *model.Job) *model.Job {
+		for i := 0; i < 100; i++ {
+			rjob, resp := Client.GetSystemJob(job.Id)
+			CheckNoError(t, resp)
+			if rjob.IsFinished() {
+				return rjob
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+		require.Fail(t, "reconcile job did not finish")
+		return nil
+	}
+
+	job, resp := Client.ReconcileCounters(true)
+	CheckNoError(t, resp)
+	CheckCreatedStatus(t, resp)
+	assert.Equal(t, model.JOB_TYPE_RECONCILE_COUNTERS, job.Type)
+	assert.Empty(t, job.TeamId)
+
+	job = waitForJob(job)
+	require.Equal(t, model.JOB_STATUS_SUCCESS, job.Status)
+	assert.Contains(t, job.Data[model.JOB_DATA_RECONCILE_REPORT], `"key":"`+question.Id+`","column":"UpVotes","actual":3,"expected":0`)
+
+	post, appErr := th.App.GetSinglePost(question.Id, false)
+	require.Nil(t, appErr)
+	assert.Equal(t, 3, post.UpVotes)
+
+	job, resp = Client.ReconcileCounters(false)
+	CheckNoError(t, resp)
+
+	job = waitForJob(job)
+	require.Equal(t, model.JOB_STATUS_SUCCESS, job.Status)
+
+	post, appErr = th.App.GetSinglePost(question.Id, false)
+	require.Nil(t, appErr)
+	assert.Equal(t, 0, post.UpVotes)
+	assert.Equal(t, 0, post.Points)
+}
@@ -7,9 +7,14 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/clear-ness/qa-discussion/audit"
 	"github.com/clear-ness/qa-discussion/model"
 )
 
+const (
+	MAXIMUM_BULK_IMPORT_SIZE = 1024 * 1024 * 1024 // 1Gb
+)
+
 func (api *API) InitTeam() {
 	api.BaseRoutes.Teams.Handle("", api.ApiSessionRequired(createTeam)).Methods("POST")
 	// (左サイドバーでチーム無選択状態で) public teamは検索出来る。
@@ -41,7 +46,7 @@ func (api *API) InitTeam() {
 	// Get a page team members list based on query string parameters - team id, page and per page.
 	api.BaseRoutes.TeamMembers.Handle("", api.ApiSessionRequired(getTeamMembers)).Methods("GET")
 	// Get a list of team members based on a provided array of user ids.
-	api.BaseRoutes.TeamMembers.Handle("/ids", api.ApiSessionRequired(getTeamMembersByIds)).Methods("POST")
+	api.BaseRoutes.TeamMembers.Handle("/ids", api.ApiSessionRequiredReadOnly(getTeamMembersByIds)).Methods("POST")
 	// Get a list of team members for a user. Useful for getting the ids of teams the user is on and the types in those teams.
 	api.BaseRoutes.TeamMembersForUser.Handle("", api.ApiSessionRequired(getTeamMembersForUser)).Methods("GET")
 
@@ -60,11 +65,12 @@ func (api *API) InitTeam() {
 	// (team毎に)回数制限、同時多発防止を別に設ける。
 	api.BaseRoutes.Team.Handle("/import", api.ApiSessionRequired(importTeam)).Methods("POST")
 
-	// TODO: データダウンロードをRESTで提供 (for team admins)
+	// データダウンロード (for team admins)
 	// https://slack.com/intl/en-se/help/articles/201658943-Export-your-workspace-data
-	// Slack同様、import完了すればメール送信→リンク経由でダウンロード、
-	// ファイルをブラウザに出力させる。
-	// (team毎に)回数制限を別に設ける。
+	// Slack同様、export完了すればメール送信→署名付きリンク経由でダウンロードさせる。
+	// 出力したzipはそのままimportに使える。
+	api.BaseRoutes.Team.Handle("/export", api.ApiSessionRequired(exportTeam)).Methods("POST")
+	api.BaseRoutes.Team.Handle("/jobs/{job_id:[A-Za-z0-9]+}", api.ApiSessionRequired(getTeamJob)).Methods("GET")
 }
 
 func createTeam(c *Context, w http.ResponseWriter, r *http.Request) {
@@ -196,6 +202,11 @@ func deleteTeam(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	auditRec := c.MakeAuditRecord("deleteTeam", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("team_id", c.Params.TeamId)
+	auditRec.AddMeta("permanent", c.Params.Permanent)
+
 	var err *model.AppError
 	if c.Params.Permanent {
 		err = c.App.PermanentDeleteTeamId(c.Params.TeamId)
@@ -208,6 +219,7 @@ func deleteTeam(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	auditRec.Success()
 	ReturnStatusOK(w)
 }
 
@@ -222,12 +234,18 @@ func regenerateTeamInviteId(c *Context, w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	auditRec := c.MakeAuditRecord("regenerateTeamInviteId", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("team_id", c.Params.TeamId)
+
 	patchedTeam, err := c.App.RegenerateTeamInviteId(c.Params.TeamId)
 	if err != nil {
 		c.Err = err
 		return
 	}
 
+	auditRec.Success()
+
 	c.App.SanitizeTeam(c.App.Session, patchedTeam)
 
 	w.Write([]byte(patchedTeam.ToJson()))
@@ -275,6 +293,10 @@ func addUserToTeamFromInvite(c *Context, w http.ResponseWriter, r *http.Request)
 	// チームのlink id 経由で参加する場合 (teamのlinkを知ってさえいれば参加出来る)
 	inviteId := r.URL.Query().Get("invite_id")
 
+	auditRec := c.MakeAuditRecord("addUserToTeamFromInvite", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("invite_id", inviteId)
+
 	var member *model.TeamMember
 	var err *model.AppError
 
@@ -291,6 +313,9 @@ func addUserToTeamFromInvite(c *Context, w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	auditRec.AddMeta("team_id", member.TeamId)
+	auditRec.Success()
+
 	w.WriteHeader(http.StatusCreated)
 	w.Write([]byte(member.ToJson()))
 }
@@ -335,13 +360,18 @@ func getTeamMembers(c *Context, w http.ResponseWriter, r *http.Request) {
 		Sort:                sort,
 		ExcludeDeletedUsers: excludeDeletedUsersBool,
 	}
+	teamMembersGetOptions.Cursor = c.CursorParam(model.CURSOR_TYPE_TEAM_MEMBERS, teamMembersGetOptions.CursorSort())
+	if c.Err != nil {
+		return
+	}
 
-	members, err := c.App.GetTeamMembers(c.Params.TeamId, c.Params.Page*c.Params.PerPage, c.Params.PerPage, teamMembersGetOptions)
+	members, cursorPage, err := c.App.GetTeamMembers(c.Params.TeamId, c.Params.Page*c.Params.PerPage, c.Params.PerPage, teamMembersGetOptions)
 	if err != nil {
 		c.Err = err
 		return
 	}
 
+	WriteCursorHeaders(w, cursorPage)
 	w.Write([]byte(model.TeamMembersToJson(members)))
 }
 
@@ -476,11 +506,18 @@ func updateTeamMemberType(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	auditRec := c.MakeAuditRecord("updateTeamMemberType", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("team_id", c.Params.TeamId)
+	auditRec.AddMeta("user_id", c.Params.UserId)
+	auditRec.AddMeta("type", newType)
+
 	if _, err := c.App.UpdateTeamMemberType(c.Params.TeamId, c.Params.UserId, newType); err != nil {
 		c.Err = err
 		return
 	}
 
+	auditRec.Success()
 	ReturnStatusOK(w)
 }
 
@@ -496,11 +533,18 @@ func removeTeamMember(c *Context, w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	}
+
+	auditRec := c.MakeAuditRecord("removeTeamMember", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("team_id", c.Params.TeamId)
+	auditRec.AddMeta("user_id", c.Params.UserId)
+
 	if err := c.App.RemoveUserFromTeam(c.Params.TeamId, c.Params.UserId, c.App.Session.UserId); err != nil {
 		c.Err = err
 		return
 	}
 
+	auditRec.Success()
 	ReturnStatusOK(w)
 }
 
@@ -541,33 +585,84 @@ func getAnalytics(c *Context, w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte(rows.ToJson()))
 }
 
-// zip形式でjonl 1ファイルを読み取り、
-// 複数goルーチンで高速並列処理させる。
-func importTeam(c *Context, w http.ResponseWriter, r *http.Request) {
+func exportTeam(c *Context, w http.ResponseWriter, r *http.Request) {
 	c.RequireTeamId()
 	if c.Err != nil {
 		return
 	}
 
-	if !c.App.SessionHasPermissionToTeam(c.App.Session, c.Params.TeamId, model.PERMISSION_IMPORT_TEAM) {
-		c.SetPermissionError(model.PERMISSION_IMPORT_TEAM)
+	if !c.App.SessionHasPermissionToTeam(c.App.Session, c.Params.TeamId, model.PERMISSION_EXPORT_TEAM) {
+		c.SetPermissionError(model.PERMISSION_EXPORT_TEAM)
 		return
 	}
 
-	if err := r.ParseMultipartForm(MAXIMUM_BULK_IMPORT_SIZE); err != nil {
-		c.Err = model.NewAppError("importTeam", "api.team.import_team.parse.app_error", nil, err.Error(), http.StatusInternalServerError)
+	auditRec := c.MakeAuditRecord("exportTeam", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("team_id", c.Params.TeamId)
+
+	job, err := c.App.CreateTeamExportJob(c.Params.TeamId, c.App.Session.UserId)
+	if err != nil {
+		c.Err = err
 		return
 	}
 
-	fileSizeStr, ok := r.MultipartForm.Value["filesize"]
-	if !ok || len(fileSizeStr) < 1 {
-		c.Err = model.NewAppError("importTeam", "api.team.import_team.unavailable.app_error", nil, "", http.StatusBadRequest)
+	auditRec.AddMeta("job_id", job.Id)
+	auditRec.Success()
+
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte(job.ToJson()))
+}
+
+func getTeamJob(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireTeamId().RequireJobId()
+	if c.Err != nil {
 		return
 	}
 
-	fileSize, err := strconv.ParseInt(fileSizeStr[0], 10, 64)
+	if !c.App.SessionHasPermissionToTeam(c.App.Session, c.Params.TeamId, model.PERMISSION_MANAGE_TEAM) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_TEAM)
+		return
+	}
+
+	job, err := c.App.GetJob(c.Params.JobId)
 	if err != nil {
-		c.Err = model.NewAppError("importTeam", "api.team.import_team.integer.app_error", nil, "", http.StatusBadRequest)
+		c.Err = err
+		return
+	}
+
+	if job.TeamId != c.Params.TeamId {
+		c.Err = model.NewAppError("getTeamJob", "api.team.get_team_job.not_found.app_error", nil, "", http.StatusNotFound)
+		return
+	}
+
+	w.Write([]byte(job.ToJson()))
+}
+
+// zip形式のJSONLと添付ファイルを受け取り、非同期でインポートする。
+// dry_runを指定すると検証のみ行い、結果はjobのレポートで確認できる。
+func importTeam(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireTeamId()
+	if c.Err != nil {
+		return
+	}
+
+	if !c.App.SessionHasPermissionToTeam(c.App.Session, c.Params.TeamId, model.PERMISSION_IMPORT_TEAM) {
+		c.SetPermissionError(model.PERMISSION_IMPORT_TEAM)
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("importTeam", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("team_id", c.Params.TeamId)
+
+	if r.ContentLength > MAXIMUM_BULK_IMPORT_SIZE {
+		c.Err = model.NewAppError("importTeam", "api.team.import_team.too_large.app_error", nil, "", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	// 大きいファイルはメモリではなく一時ファイルに置かれる
+	if err := r.ParseMultipartForm(maxUploadDrainBytes); err != nil {
+		c.Err = model.NewAppError("importTeam", "api.team.import_team.parse.app_error", nil, err.Error(), http.StatusBadRequest)
 		return
 	}
 
@@ -582,30 +677,33 @@ func importTeam(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	fileInfo := fileInfoArray[0]
+	dryRun := false
+	if values, ok := r.MultipartForm.Value["dry_run"]; ok && len(values) > 0 {
+		dryRun, _ = strconv.ParseBool(values[0])
+	}
 
-	fileData, err := fileInfo.Open()
+	fileData, err := fileInfoArray[0].Open()
 	if err != nil {
 		c.Err = model.NewAppError("importTeam", "api.team.import_team.open.app_error", nil, err.Error(), http.StatusBadRequest)
 		return
 	}
 	defer fileData.Close()
 
-	var log *bytes.Buffer
-	data := map[string]string{}
+	auditRec.AddMeta("filename", fileInfoArray[0].Filename)
+	auditRec.AddMeta("dry_run", dryRun)
 
-	var err *model.AppError
-	// slack exportファイル(zip形式)をRESTアップロード
-	if err, log = c.App.SlackImport(fileData, fileSize, c.Params.TeamId); err != nil {
-		c.Err = err
-		c.Err.StatusCode = http.StatusBadRequest
-	}
-	data["results"] = base64.StdEncoding.EncodeToString(log.Bytes())
+	// 外部認証の情報を含むユーザーはシステム管理者のみ取り込める
+	allowAuthData := c.App.SessionHasPermissionTo(c.App.Session, model.PERMISSION_EDIT_OTHER_USERS)
 
-	if c.Err != nil {
-		w.WriteHeader(c.Err.StatusCode)
+	job, appErr := c.App.CreateTeamImportJob(c.Params.TeamId, c.App.Session.UserId, fileData, dryRun, allowAuthData)
+	if appErr != nil {
+		c.Err = appErr
 		return
 	}
 
-	w.Write([]byte(model.MapToJson(data)))
+	auditRec.AddMeta("job_id", job.Id)
+	auditRec.Success()
+
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte(job.ToJson()))
 }
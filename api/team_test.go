@@ -0,0 +1,192 @@
+package api
+
+import (
+	"archive/zip"
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/clear-ness/qa-discussion/app"
+	"github.com/clear-ness/qa-discussion/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportTeam(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+	Client := th.Client
+
+	team, err := th.App.CreateTeamWithUser(&model.Team{
+		Name: "export" + model.NewRandomString(10),
+		Type: model.TEAM_TYPE_PUBLIC,
+	}, th.BasicUser.Id)
+	require.Nil(t, err)
+
+	require.Nil(t, th.App.JoinUserToTeam(team, th.BasicUser2, false))
+
+	t.Run("file storage not configured", func(t *testing.T) {
+		th.Server.UpdateConfig(func(cfg *model.Config) {
+			*cfg.FileSettings.AmazonS3Bucket = ""
+		})
+
+		_, resp := Client.ExportTeam(team.Id)
+		require.NotNil(t, resp.Error)
+		assert.Equal(t, http.StatusNotImplemented, resp.StatusCode)
+		assert.Equal(t, "app.export.file_storage.not_configured.app_error", resp.Error.Id)
+	})
+
+	th.Server.UpdateConfig(func(cfg *model.Config) {
+		*cfg.FileSettings.AmazonS3Bucket = "qa-discussion-test"
+	})
+
+	job, resp := Client.ExportTeam(team.Id)
+	CheckNoError(t, resp)
+	CheckCreatedStatus(t, resp)
+	assert.Equal(t, model.JOB_TYPE_EXPORT_TEAM, job.Type)
+	assert.Equal(t, team.Id, job.TeamId)
+	assert.Equal(t, th.BasicUser.Id, job.UserId)
+
+	rjob, resp := Client.GetTeamJob(team.Id, job.Id)
+	CheckNoError(t, resp)
+	assert.Equal(t, job.Id, rjob.Id)
+
+	t.Run("job of another team", func(t *testing.T) {
+		otherTeam, err := th.App.CreateTeamWithUser(&model.Team{
+			Name: "export" + model.NewRandomString(10),
+			Type: model.TEAM_TYPE_PUBLIC,
+		}, th.BasicUser.Id)
+		require.Nil(t, err)
+
+		_, resp := Client.GetTeamJob(otherTeam.Id, job.Id)
+		CheckNotFoundStatus(t, resp)
+	})
+
+	t.Run("normal member cannot export", func(t *testing.T) {
+		Client.Logout()
+		th.LoginBasic2()
+
+		_, resp := Client.ExportTeam(team.Id)
+		CheckForbiddenStatus(t, resp)
+
+		_, resp = Client.GetTeamJob(team.Id, job.Id)
+		CheckForbiddenStatus(t, resp)
+	})
+}
+
+func TestImportTeam(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+	Client := th.Client
+
+	team, err := th.App.CreateTeamWithUser(&model.Team{
+		Name: "import" + model.NewRandomString(10),
+		Type: model.TEAM_TYPE_PUBLIC,
+	}, th.BasicUser.Id)
+	require.Nil(t, err)
+
+	username := "import" + strings.ToLower(model.NewRandomString(10))
+	lines := []string{
+		`{"type": "version", "version": 1}`,
+		`{"type": "user", "user": {"username": "` + username + `", "email": "` + username + `@example.com", "team_member_type": "normal"}}`,
+		`{"type": "question", "question": {"id": "q1", "user": "` + username + `", "title": "imported question", "content": "imported question content", "tags": "import"}}`,
+		`{"type": "answer", "answer": {"id": "a1", "question": "q1", "user": "` + th.BasicUser.Username + `", "content": "imported answer", "is_best": true}}`,
+		`{"type": "comment", "comment": {"id": "c1", "parent": "missing", "user": "` + username + `", "content": "imported comment"}}`,
+	}
+
+	makeZip := func(lines []string) *bytes.Buffer {
+		buf := &bytes.Buffer{}
+		zipWriter := zip.NewWriter(buf)
+		w, zipErr := zipWriter.Create(app.IMPORT_DATA_FILE_NAME)
+		require.Nil(t, zipErr)
+		_, zipErr = w.Write([]byte(strings.Join(lines, "\n")))
+		require.Nil(t, zipErr)
+		require.Nil(t, zipWriter.Close())
+		return buf
+	}
+	buf := makeZip(lines)
+
+	waitForJob := func(job *model.Job) *model.Job {
+		for i := 0; i < 100; i++ {
+			rjob, resp := Client.GetTeamJob(team.Id, job.Id)
+			CheckNoError(t, resp)
+			if rjob.IsFinished() {
+				return rjob
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+		require.Fail(t, "import job did not finish")
+		return nil
+	}
+
+	t.Run("dry run", func(t *testing.T) {
+		job, resp := Client.ImportTeam(team.Id, buf.Bytes(), "import.zip", true)
+		CheckNoError(t, resp)
+		CheckCreatedStatus(t, resp)
+		assert.Equal(t, model.JOB_TYPE_IMPORT_TEAM, job.Type)
+		assert.Equal(t, "true", job.Data[model.JOB_DATA_DRY_RUN])
+
+		job = waitForJob(job)
+		require.Equal(t, model.JOB_STATUS_SUCCESS, job.Status)
+
+		report := job.Data[model.JOB_DATA_IMPORT_REPORT]
+		assert.Contains(t, report, `"line_count":5`)
+		assert.Contains(t, report, `"error_count":1`)
+		assert.Contains(t, report, `"line_number":5`)
+
+		_, err := th.App.Srv.Store.User().GetByUsername(username)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("import", func(t *testing.T) {
+		job, resp := Client.ImportTeam(team.Id, buf.Bytes(), "import.zip", false)
+		CheckNoError(t, resp)
+
+		job = waitForJob(job)
+		require.Equal(t, model.JOB_STATUS_SUCCESS, job.Status)
+		assert.Contains(t, job.Data[model.JOB_DATA_IMPORT_REPORT], `"error_count":1`)
+
+		user, err := th.App.Srv.Store.User().GetByUsername(username)
+		require.Nil(t, err)
+
+		_, err = th.App.Srv.Store.Team().GetMember(team.Id, user.Id)
+		assert.Nil(t, err)
+	})
+
+	t.Run("not a zip file", func(t *testing.T) {
+		job, resp := Client.ImportTeam(team.Id, []byte("invalid"), "import.zip", true)
+		CheckNoError(t, resp)
+
+		job = waitForJob(job)
+		assert.Equal(t, model.JOB_STATUS_ERROR, job.Status)
+	})
+
+	t.Run("team admin cannot import auth data", func(t *testing.T) {
+		ssoUsername := "import" + strings.ToLower(model.NewRandomString(10))
+		sso := makeZip([]string{
+			`{"type": "version", "version": 1}`,
+			`{"type": "user", "user": {"username": "` + ssoUsername + `", "email": "` + ssoUsername + `@example.com", "auth_service": "openid", "auth_data": "victim", "team_member_type": "normal"}}`,
+		})
+
+		job, resp := Client.ImportTeam(team.Id, sso.Bytes(), "import.zip", false)
+		CheckNoError(t, resp)
+
+		job = waitForJob(job)
+		require.Equal(t, model.JOB_STATUS_SUCCESS, job.Status)
+		assert.Contains(t, job.Data[model.JOB_DATA_IMPORT_REPORT], "app.import.import_user.auth_not_allowed.error")
+
+		_, err := th.App.Srv.Store.User().GetByUsername(ssoUsername)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("normal member cannot import", func(t *testing.T) {
+		Client.Logout()
+		th.LoginBasic2()
+
+		_, resp := Client.ImportTeam(team.Id, buf.Bytes(), "import.zip", true)
+		CheckForbiddenStatus(t, resp)
+	})
+}
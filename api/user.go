@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/clear-ness/qa-discussion/audit"
 	"github.com/clear-ness/qa-discussion/mlog"
 	"github.com/clear-ness/qa-discussion/model"
 )
@@ -18,7 +19,7 @@ func (api *API) InitUser() {
 	api.BaseRoutes.Users.Handle("/login", api.ApiHandler(login)).Methods("POST")
 	api.BaseRoutes.Users.Handle("/logout", api.ApiHandler(logout)).Methods("POST")
 	api.BaseRoutes.Users.Handle("", api.ApiHandler(getUsers)).Methods("GET")
-	api.BaseRoutes.Users.Handle("/ids", api.ApiHandler(getUsersByIds)).Methods("POST")
+	api.BaseRoutes.Users.Handle("/ids", api.ApiHandlerReadOnly(getUsersByIds)).Methods("POST")
 
 	api.BaseRoutes.User.Handle("", api.ApiHandler(getUser)).Methods("GET")
 	api.BaseRoutes.UserForTeam.Handle("", api.ApiSessionRequired(getTeamUser)).Methods("GET")
@@ -46,6 +47,10 @@ func (api *API) InitUser() {
 
 	api.BaseRoutes.User.Handle("/image", api.ApiSessionRequired(setProfileImage)).Methods("POST")
 
+	api.BaseRoutes.User.Handle("/sessions", api.ApiSessionRequired(getSessions)).Methods("GET")
+	api.BaseRoutes.User.Handle("/sessions/revoke", api.ApiSessionRequired(revokeSession)).Methods("POST")
+	api.BaseRoutes.User.Handle("/sessions/revoke/all", api.ApiSessionRequired(revokeAllSessionsExceptCurrent)).Methods("POST")
+
 	// TODO: search users
 
 }
@@ -71,7 +76,7 @@ func createUser(c *Context, w http.ResponseWriter, r *http.Request) {
 
 	if len(tokenId) > 0 {
 		var token *model.Token
-		token, err = c.App.Srv.Store.Token().GetByToken(tokenId)
+		token, err = c.App.Store().Token().GetByToken(tokenId)
 		if err != nil {
 			c.Err = model.NewAppError("CreateUserWithToken", "api.user.create_user.signup_link_invalid.app_error", nil, err.Error(), http.StatusBadRequest)
 			return
@@ -140,18 +145,27 @@ func login(c *Context, w http.ResponseWriter, r *http.Request) {
 	loginId := props["login_id"]
 	password := props["password"]
 
+	auditRec := c.MakeAuditRecord("login", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("login_id", loginId)
+
 	user, err := c.App.AuthenticateUserForLogin(loginId, password)
 	if err != nil {
 		c.Err = err
 		return
 	}
 
+	auditRec.UserID = user.Id
+
 	err = c.App.DoLogin(w, r, user)
 	if err != nil {
 		c.Err = err
 		return
 	}
 
+	auditRec.SessionID = c.App.Session.Id
+	auditRec.Success()
+
 	if r.Header.Get(model.HEADER_REQUESTED_WITH) == model.HEADER_REQUESTED_WITH_XML {
 		c.App.AttachSessionCookies(w, r)
 	}
@@ -162,6 +176,9 @@ func login(c *Context, w http.ResponseWriter, r *http.Request) {
 }
 
 func logout(c *Context, w http.ResponseWriter, r *http.Request) {
+	auditRec := c.MakeAuditRecord("logout", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+
 	c.RemoveSessionCookie(w, r)
 	if c.App.Session.Id != "" {
 		if err := c.App.RevokeSessionById(c.App.Session.Id); err != nil {
@@ -170,6 +187,7 @@ func logout(c *Context, w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	auditRec.Success()
 	ReturnStatusOK(w)
 }
 
@@ -342,14 +360,20 @@ func getInboxMessagesForUser(c *Context, w http.ResponseWriter, r *http.Request)
 		teamId = c.Params.TeamId
 	}
 
+	cursor := c.CursorParam(model.CURSOR_TYPE_INBOX_MESSAGES, "")
+	if c.Err != nil {
+		return
+	}
+
 	curTime := model.GetMillis()
 
-	messages, err := c.App.GetInboxMessagesForUserToDate(curTime, c.Params.UserId, c.Params.Page, c.Params.PerPage, teamId)
+	messages, cursorPage, err := c.App.GetInboxMessagesForUserToDate(curTime, c.Params.UserId, c.Params.Page, c.Params.PerPage, teamId, cursor)
 	if err != nil {
 		c.Err = err
 		return
 	}
 
+	WriteCursorHeaders(w, cursorPage)
 	w.Write([]byte(model.InboxMessageListToJson(messages)))
 }
 
@@ -483,15 +507,21 @@ func getVotesForUser(c *Context, w http.ResponseWriter, r *http.Request) {
 		teamId = c.Params.TeamId
 	}
 
+	cursor := c.CursorParam(model.CURSOR_TYPE_VOTES, "")
+	if c.Err != nil {
+		return
+	}
+
 	curTime := model.GetMillis()
 
-	votes, totalCount, err := c.App.GetVotesForUser(curTime, c.Params.UserId, c.Params.Page, c.Params.PerPage, true, true, teamId)
+	votes, totalCount, cursorPage, err := c.App.GetVotesForUser(curTime, c.Params.UserId, c.Params.Page, c.Params.PerPage, true, true, teamId, cursor)
 	if err != nil {
 		c.Err = err
 		return
 	}
 
-	data := model.VotesWithCount{Votes: votes, TotalCount: totalCount}
+	data := model.VotesWithCount{Votes: votes, TotalCount: totalCount, CursorPage: cursorPage}
+	WriteCursorHeaders(w, cursorPage)
 	w.Write([]byte(data.ToJson()))
 }
 
@@ -502,6 +532,11 @@ func suspendUser(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	auditRec := c.MakeAuditRecord("suspendUser", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("user_id", c.Params.UserId)
+	auditRec.AddMeta("span", c.Params.SuspendSpanType)
+
 	if !c.App.SessionHasPermissionTo(c.App.Session, model.PERMISSION_SUSPEND_USER) {
 		c.SetPermissionError(model.PERMISSION_SUSPEND_USER)
 		return
@@ -524,6 +559,7 @@ func suspendUser(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	auditRec.Success()
 	ReturnStatusOK(w)
 }
 
@@ -586,6 +622,11 @@ func updateUserType(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	auditRec := c.MakeAuditRecord("updateUserType", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("user_id", c.Params.UserId)
+	auditRec.AddMeta("type", c.Params.UserType)
+
 	if !c.App.SessionHasPermissionTo(c.App.Session, model.PERMISSION_EDIT_USER_TYPE) {
 		c.SetPermissionError(model.PERMISSION_EDIT_USER_TYPE)
 		return
@@ -596,6 +637,7 @@ func updateUserType(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	auditRec.Success()
 	ReturnStatusOK(w)
 }
 
@@ -605,6 +647,10 @@ func updatePassword(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	auditRec := c.MakeAuditRecord("updatePassword", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("user_id", c.Params.UserId)
+
 	props := model.MapFromJson(r.Body)
 	newPassword := props["new_password"]
 
@@ -628,6 +674,7 @@ func updatePassword(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	auditRec.Success()
 	ReturnStatusOK(w)
 }
 
@@ -640,6 +687,9 @@ func resetPassword(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	auditRec := c.MakeAuditRecord("resetPassword", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+
 	newPassword := props["new_password"]
 
 	if err := c.App.ResetPasswordFromToken(token, newPassword); err != nil {
@@ -647,6 +697,7 @@ func resetPassword(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	auditRec.Success()
 	ReturnStatusOK(w)
 }
 
@@ -660,12 +711,17 @@ func sendPasswordReset(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	auditRec := c.MakeAuditRecord("sendPasswordReset", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("email", email)
+
 	err := c.App.SendPasswordReset(email, c.App.GetSiteURL())
 	if err != nil {
 		c.Err = err
 		return
 	}
 
+	auditRec.Success()
 	ReturnStatusOK(w)
 }
 
@@ -698,11 +754,16 @@ func deleteUser(c *Context, w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	auditRec := c.MakeAuditRecord("deleteUser", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("user_id", user.Id)
+
 	if err := c.App.DeleteUser(user.Id, c.App.Session.UserId); err != nil {
 		c.Err = err
 		return
 	}
 
+	auditRec.Success()
 	ReturnStatusOK(w)
 }
 
@@ -749,3 +810,84 @@ func setProfileImage(c *Context, w http.ResponseWriter, r *http.Request) {
 		w.Write([]byte(model.MapToJson(map[string]string{"user_id": c.Params.UserId, "profile_image_link": link})))
 	}
 }
+
+func getSessions(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	if !c.App.SessionHasPermissionToUser(c.App.Session, c.Params.UserId) {
+		c.SetPermissionError(model.PERMISSION_EDIT_OTHER_USERS)
+		return
+	}
+
+	sessions, err := c.App.GetSessions(c.Params.UserId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	for _, session := range sessions {
+		session.Sanitize()
+	}
+
+	w.Write([]byte(model.SessionsToJson(sessions)))
+}
+
+func revokeSession(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	if !c.App.SessionHasPermissionToUser(c.App.Session, c.Params.UserId) {
+		c.SetPermissionError(model.PERMISSION_EDIT_OTHER_USERS)
+		return
+	}
+
+	props := model.MapFromJson(r.Body)
+	sessionId := props["session_id"]
+	if len(sessionId) != 26 {
+		c.SetInvalidParam("session_id")
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("revokeSession", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("user_id", c.Params.UserId)
+	auditRec.AddMeta("revoked_session_id", sessionId)
+
+	if err := c.App.RevokeSessionForUser(c.Params.UserId, sessionId); err != nil {
+		c.Err = err
+		return
+	}
+
+	auditRec.Success()
+	ReturnStatusOK(w)
+}
+
+// 現在のセッション以外を全てログアウトさせる
+func revokeAllSessionsExceptCurrent(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	if !c.App.SessionHasPermissionToUser(c.App.Session, c.Params.UserId) {
+		c.SetPermissionError(model.PERMISSION_EDIT_OTHER_USERS)
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("revokeAllSessionsExceptCurrent", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("user_id", c.Params.UserId)
+
+	if err := c.App.RevokeSessionsExcept(c.Params.UserId, c.App.Session.Id); err != nil {
+		c.Err = err
+		return
+	}
+
+	auditRec.Success()
+	ReturnStatusOK(w)
+}
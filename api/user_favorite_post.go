@@ -20,15 +20,21 @@ func getUserFavoritePosts(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	cursor := c.CursorParam(model.CURSOR_TYPE_FAVORITE_POSTS, "")
+	if c.Err != nil {
+		return
+	}
+
 	curTime := model.GetMillis()
 	// TODO: sortable by added, creation, votes
-	uPosts, totalCount, err := c.App.GetUserFavoritePostsForUser(curTime, c.Params.UserId, c.Params.Page, c.Params.PerPage, true, "")
+	uPosts, totalCount, cursorPage, err := c.App.GetUserFavoritePostsForUser(curTime, c.Params.UserId, c.Params.Page, c.Params.PerPage, true, "", cursor)
 	if err != nil {
 		c.Err = err
 		return
 	}
 
-	data := model.UserFavoritePostsWithCount{UserFavoritePosts: uPosts, TotalCount: totalCount}
+	data := model.UserFavoritePostsWithCount{UserFavoritePosts: uPosts, TotalCount: totalCount, CursorPage: cursorPage}
+	WriteCursorHeaders(w, cursorPage)
 	w.Write([]byte(data.ToJson()))
 }
 
@@ -43,15 +49,21 @@ func getTeamMemberFavoritePosts(c *Context, w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	cursor := c.CursorParam(model.CURSOR_TYPE_FAVORITE_POSTS, "")
+	if c.Err != nil {
+		return
+	}
+
 	curTime := model.GetMillis()
 	// TODO: sortable by added, creation, votes
-	uPosts, totalCount, err := c.App.GetUserFavoritePostsForUser(curTime, c.Params.UserId, c.Params.Page, c.Params.PerPage, true, c.Params.TeamId)
+	uPosts, totalCount, cursorPage, err := c.App.GetUserFavoritePostsForUser(curTime, c.Params.UserId, c.Params.Page, c.Params.PerPage, true, c.Params.TeamId, cursor)
 	if err != nil {
 		c.Err = err
 		return
 	}
 
-	data := model.UserFavoritePostsWithCount{UserFavoritePosts: uPosts, TotalCount: totalCount}
+	data := model.UserFavoritePostsWithCount{UserFavoritePosts: uPosts, TotalCount: totalCount, CursorPage: cursorPage}
+	WriteCursorHeaders(w, cursorPage)
 	w.Write([]byte(data.ToJson()))
 }
 
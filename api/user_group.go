@@ -328,12 +328,18 @@ func getGroupMembers(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	members, err := c.App.GetGroupMembersPage(c.Params.GroupId, "", c.Params.Page, c.Params.PerPage)
+	cursor := c.CursorParam(model.CURSOR_TYPE_GROUP_MEMBERS, "")
+	if c.Err != nil {
+		return
+	}
+
+	members, cursorPage, err := c.App.GetGroupMembersPage(c.Params.GroupId, "", c.Params.Page, c.Params.PerPage, cursor)
 	if err != nil {
 		c.Err = err
 		return
 	}
 
+	WriteCursorHeaders(w, cursorPage)
 	w.Write([]byte(members.ToJson()))
 }
 
@@ -3,6 +3,7 @@ package api
 import (
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/clear-ness/qa-discussion/model"
 
@@ -400,8 +401,117 @@ func TestGetUserVotes(t *testing.T) {
 	assert.Equal(t, model.VOTE_TYPE_UP_VOTE, data.Votes[1].Vote.Type, "failed to get vote type")
 	assert.Equal(t, model.VOTE_TYPE_UP_VOTE, data.Votes[2].Vote.Type, "failed to get vote type")
 
+	data, resp = Client.GetUserVotesPage(model.ME, "", 2)
+	CheckNoError(t, resp)
+	require.Len(t, data.Votes, 2, "invalid posts")
+	require.NotEmpty(t, data.NextCursor, "missing next cursor")
+	assert.Equal(t, data.NextCursor, resp.NextCursor(), "cursor header differs from body")
+	assert.Empty(t, data.PrevCursor, "first page has no previous page")
+
+	data, resp = Client.GetUserVotesPage(model.ME, data.NextCursor, 2)
+	CheckNoError(t, resp)
+	require.Len(t, data.Votes, 1, "invalid posts")
+	assert.Equal(t, rpost.Id, data.Votes[0].Post.Id, "failed to get post")
+	assert.Empty(t, data.NextCursor, "last page has no next page")
+	require.NotEmpty(t, data.PrevCursor, "missing prev cursor")
+
+	data, resp = Client.GetUserVotesPage(model.ME, data.PrevCursor, 2)
+	CheckNoError(t, resp)
+	require.Len(t, data.Votes, 2, "invalid posts")
+	assert.Equal(t, rpost3.Id, data.Votes[0].Post.Id, "failed to get post")
+	assert.Equal(t, rpost2.Id, data.Votes[1].Post.Id, "failed to get post")
+
+	_, resp = Client.GetUserVotesPage(model.ME, "invalid", 2)
+	CheckBadRequestStatus(t, resp)
+
+	// 他の一覧のカーソルは使えない
+	_, resp = Client.GetUserVotesPage(model.ME, model.NewCursor(model.CURSOR_TYPE_POSTS, "", 0, rpost.Id).Encode(), 2)
+	CheckBadRequestStatus(t, resp)
+
 	Client.Logout()
 
 	_, resp = Client.GetUserFavoritePosts(model.ME)
 	CheckBadRequestStatus(t, resp)
 }
+
+func TestSessions(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+	Client := th.Client
+
+	Client2 := th.CreateClient()
+	th.LoginBasicWithClient(Client2)
+
+	sessions, resp := Client.GetSessions(model.ME)
+	CheckNoError(t, resp)
+	require.Len(t, sessions, 2, "should have two sessions")
+	for _, session := range sessions {
+		assert.Equal(t, th.BasicUser.Id, session.UserId)
+		assert.Empty(t, session.Token, "token should be sanitized")
+		assert.NotZero(t, session.LastActivityAt)
+	}
+
+	currentSession, _ := th.App.GetSession(Client.AuthToken)
+	otherSession, _ := th.App.GetSession(Client2.AuthToken)
+
+	_, resp = Client.RevokeSession(model.ME, "junk")
+	CheckBadRequestStatus(t, resp)
+
+	pass, resp := Client.RevokeSession(model.ME, otherSession.Id)
+	CheckNoError(t, resp)
+	require.True(t, pass, "should revoke session")
+
+	_, resp = Client2.GetSessions(model.ME)
+	CheckUnauthorizedStatus(t, resp)
+
+	th.LoginBasicWithClient(Client2)
+	th.LoginBasicWithClient(th.CreateClient())
+
+	pass, resp = Client.RevokeAllSessionsExceptCurrent(model.ME)
+	CheckNoError(t, resp)
+	require.True(t, pass, "should revoke sessions")
+
+	sessions, resp = Client.GetSessions(model.ME)
+	CheckNoError(t, resp)
+	require.Len(t, sessions, 1, "only current session should remain")
+	assert.Equal(t, currentSession.Id, sessions[0].Id)
+
+	_, resp = Client.GetSessions(th.BasicUser2.Id)
+	CheckForbiddenStatus(t, resp)
+}
+
+func TestSearchAudits(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+	Client := th.Client
+
+	_, resp := Client.SearchAudits(&model.SearchAuditsOptions{PerPage: 10})
+	CheckForbiddenStatus(t, resp)
+
+	_, appErr := th.App.UpdateUserType(th.BasicUser2.Id, model.USER_TYPE_ADMIN)
+	require.Nil(t, appErr)
+	defer th.App.UpdateUserType(th.BasicUser2.Id, model.USER_TYPE_NORMAL)
+
+	_, resp = Client.Login(th.BasicUser.Email, "wrong password")
+	require.NotNil(t, resp.Error)
+
+	th.LoginBasic2()
+
+	// recordは非同期に書き出される
+	options := &model.SearchAuditsOptions{UserId: th.BasicUser2.Id, Action: "login", Status: "success", PerPage: 10}
+	require.Eventually(t, func() bool {
+		audits, resp := Client.SearchAudits(options)
+		return resp.Error == nil && len(audits) > 0
+	}, 5*time.Second, 100*time.Millisecond)
+
+	audits, resp := Client.SearchAudits(options)
+	CheckNoError(t, resp)
+	assert.Equal(t, "login", audits[0].Action)
+	assert.NotEmpty(t, audits[0].SessionId)
+	assert.NotEmpty(t, audits[0].ApiPath)
+
+	audits, resp = Client.SearchAudits(&model.SearchAuditsOptions{Action: "login", Status: "fail", PerPage: 10})
+	CheckNoError(t, resp)
+	require.NotEmpty(t, audits)
+	assert.Contains(t, audits[0].ExtraInfo, th.BasicUser.Email)
+}
@@ -3,6 +3,7 @@ package api
 import (
 	"net/http"
 
+	"github.com/clear-ness/qa-discussion/audit"
 	"github.com/clear-ness/qa-discussion/model"
 )
 
@@ -14,8 +15,14 @@ func (api *API) InitWebhook() {
 	api.BaseRoutes.Hook.Handle("", api.ApiSessionRequired(updateHook)).Methods("PUT")
 	api.BaseRoutes.Hook.Handle("", api.ApiSessionRequired(deleteHook)).Methods("DELETE")
 	api.BaseRoutes.Hook.Handle("/regen_token", api.ApiSessionRequired(regenHookToken)).Methods("POST")
+	api.BaseRoutes.Hook.Handle("/test", api.ApiSessionRequired(testHook)).Methods("POST")
 
-	api.BaseRoutes.Hooks.Handle("/history", api.ApiSessionRequired(getHooksHistory)).Methods("GET")
+	api.BaseRoutes.Hooks.Handle("/events", api.ApiSessionRequired(getHookEvents)).Methods("GET")
+	api.BaseRoutes.Hooks.Handle("/preview", api.ApiSessionRequiredReadOnly(previewHook)).Methods("POST")
+
+	api.BaseRoutes.HooksHistory.Handle("", api.ApiSessionRequired(getHooksHistory)).Methods("GET")
+	api.BaseRoutes.HookHistory.Handle("", api.ApiSessionRequired(getHookHistory)).Methods("GET")
+	api.BaseRoutes.HookHistory.Handle("/redeliver", api.ApiSessionRequired(redeliverHook)).Methods("POST")
 }
 
 func createHook(c *Context, w http.ResponseWriter, r *http.Request) {
@@ -32,6 +39,10 @@ func createHook(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	auditRec := c.MakeAuditRecord("createHook", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("team_id", hook.TeamId)
+
 	rhook, err := c.App.CreateWebhook(hook)
 	if err != nil {
 		c.Err = err
@@ -39,6 +50,9 @@ func createHook(c *Context, w http.ResponseWriter, r *http.Request) {
 	}
 	// TODO: SanitizeInput
 
+	auditRec.AddMeta("hook_id", rhook.Id)
+	auditRec.Success()
+
 	w.WriteHeader(http.StatusCreated)
 	w.Write([]byte(rhook.ToJson()))
 }
@@ -141,6 +155,11 @@ func updateHook(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	auditRec := c.MakeAuditRecord("updateHook", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("team_id", oldHook.TeamId)
+	auditRec.AddMeta("hook_id", oldHook.Id)
+
 	updatedHook.UserId = c.App.Session.UserId
 
 	rhook, err := c.App.UpdateWebhook(oldHook, updatedHook)
@@ -149,6 +168,8 @@ func updateHook(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	auditRec.Success()
+
 	w.Write([]byte(rhook.ToJson()))
 }
 
@@ -174,11 +195,17 @@ func deleteHook(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	auditRec := c.MakeAuditRecord("deleteHook", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("team_id", hook.TeamId)
+	auditRec.AddMeta("hook_id", hook.Id)
+
 	if err := c.App.DeleteWebhook(hook.Id); err != nil {
 		c.Err = err
 		return
 	}
 
+	auditRec.Success()
 	ReturnStatusOK(w)
 }
 
@@ -204,19 +231,88 @@ func regenHookToken(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	auditRec := c.MakeAuditRecord("regenHookToken", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("team_id", hook.TeamId)
+	auditRec.AddMeta("hook_id", hook.Id)
+
 	rhook, err := c.App.RegenWebhookToken(hook)
 	if err != nil {
 		c.Err = err
 		return
 	}
 
+	auditRec.Success()
+
 	w.Write([]byte(rhook.ToJson()))
 }
 
+func testHook(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireHookId()
+	if c.Err != nil {
+		return
+	}
+
+	hook, err := c.App.GetWebhook(c.Params.HookId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	if !c.App.SessionHasPermissionToTeam(c.App.Session, hook.TeamId, model.PERMISSION_MANAGE_WEBHOOKS) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_WEBHOOKS)
+		return
+	}
+
+	if c.App.Session.UserId != hook.UserId && !c.App.SessionHasPermissionToTeam(c.App.Session, hook.TeamId, model.PERMISSION_MANAGE_OTHERS_WEBHOOKS) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_OTHERS_WEBHOOKS)
+		return
+	}
+
+	deliveries, err := c.App.SendTestWebhookEvent(hook, c.App.Session.UserId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	// 送信は非同期なので、結果は履歴から確認する
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte(model.WebhookDeliveryListToJson(deliveries)))
+}
+
+func getHookEvents(c *Context, w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte(model.WebhookEventCatalogToJson()))
+}
+
+// 保存前の設定でサンプルのイベントを描画する。実データは使わないので権限は問わない
+func previewHook(c *Context, w http.ResponseWriter, r *http.Request) {
+	hook := model.WebhookFromJson(r.Body)
+	if hook == nil {
+		c.SetInvalidParam("webhook")
+		return
+	}
+
+	event := r.URL.Query().Get("event")
+	if event == "" {
+		event = model.WEBHOOK_EVENT_QUESTION_CREATED
+	}
+
+	preview, err := c.App.PreviewWebhookPayload(hook, event)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.Write([]byte(preview.ToJson()))
+}
+
 func getHooksHistory(c *Context, w http.ResponseWriter, r *http.Request) {
-	teamId := r.URL.Query().Get("team_id")
+	query := r.URL.Query()
+
+	teamId := query.Get("team_id")
 	if len(teamId) <= 0 {
 		c.SetInvalidParam("team_id")
+		return
 	}
 
 	if !c.App.SessionHasPermissionToTeam(c.App.Session, teamId, model.PERMISSION_MANAGE_WEBHOOKS) {
@@ -224,11 +320,91 @@ func getHooksHistory(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	histories, err := c.App.GetWebhooksHistoriesPage(teamId, c.Params.Page, c.Params.PerPage)
+	// min/maxはレスポンスのステータスコードの範囲
+	options := &model.SearchWebhooksHistoryOptions{
+		TeamId:    teamId,
+		WebhookId: query.Get("hook_id"),
+		PostId:    query.Get("post_id"),
+		FromDate:  c.Params.FromDate,
+		ToDate:    c.Params.ToDate,
+		Page:      c.Params.Page,
+		PerPage:   c.Params.PerPage,
+		Cursor:    c.CursorParam(model.CURSOR_TYPE_WEBHOOKS_HISTORY, ""),
+	}
+	if c.Err != nil {
+		return
+	}
+	if c.Params.Min != nil {
+		options.MinStatus = *c.Params.Min
+	}
+	if c.Params.Max != nil {
+		options.MaxStatus = *c.Params.Max
+	}
+
+	histories, cursorPage, err := c.App.SearchWebhooksHistory(options)
 	if err != nil {
 		c.Err = err
 		return
 	}
 
+	WriteCursorHeaders(w, cursorPage)
 	w.Write([]byte(model.WebhooksHistoryListToJson(histories)))
 }
+
+func getHookHistory(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireHistoryId()
+	if c.Err != nil {
+		return
+	}
+
+	history, err := c.App.GetWebhooksHistory(c.Params.HistoryId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	if !c.App.SessionHasPermissionToTeam(c.App.Session, history.TeamId, model.PERMISSION_MANAGE_WEBHOOKS) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_WEBHOOKS)
+		return
+	}
+
+	w.Write([]byte(history.ToJson()))
+}
+
+func redeliverHook(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireHistoryId()
+	if c.Err != nil {
+		return
+	}
+
+	history, err := c.App.GetWebhooksHistory(c.Params.HistoryId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	if !c.App.SessionHasPermissionToTeam(c.App.Session, history.TeamId, model.PERMISSION_MANAGE_WEBHOOKS) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_WEBHOOKS)
+		return
+	}
+
+	hook, err := c.App.GetWebhook(history.WebhookId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	if c.App.Session.UserId != hook.UserId && !c.App.SessionHasPermissionToTeam(c.App.Session, hook.TeamId, model.PERMISSION_MANAGE_OTHERS_WEBHOOKS) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_OTHERS_WEBHOOKS)
+		return
+	}
+
+	delivery, err := c.App.RedeliverWebhook(history, hook)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte(delivery.ToJson()))
+}
@@ -0,0 +1,239 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/clear-ness/qa-discussion/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookEvents(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+	Client := th.Client
+
+	team, err := th.App.CreateTeamWithUser(&model.Team{
+		Name: "hooks" + model.NewRandomString(10),
+		Type: model.TEAM_TYPE_PUBLIC,
+	}, th.BasicUser.Id)
+	require.Nil(t, err)
+
+	events, resp := Client.GetWebhookEvents()
+	CheckNoError(t, resp)
+	assert.Len(t, events, len(model.WebhookEventCatalog))
+
+	hook, resp := Client.CreateWebhook(&model.Webhook{
+		TeamId: team.Id,
+		Events: []string{model.WEBHOOK_EVENT_POST_EDITED, model.WEBHOOK_EVENT_MEMBER_JOINED},
+		URLs:   []string{"http://localhost:8065/hook"},
+	})
+	CheckNoError(t, resp)
+	CheckCreatedStatus(t, resp)
+	assert.True(t, hook.IsSubscribed(model.WEBHOOK_EVENT_POST_EDITED))
+
+	t.Run("unknown event", func(t *testing.T) {
+		_, resp := Client.CreateWebhook(&model.Webhook{
+			TeamId: team.Id,
+			Events: []string{"no_such_event"},
+			URLs:   []string{"http://localhost:8065/other"},
+		})
+		CheckBadRequestStatus(t, resp)
+	})
+
+	t.Run("send test event", func(t *testing.T) {
+		deliveries, resp := Client.TestWebhook(hook.Id)
+		CheckNoError(t, resp)
+		CheckCreatedStatus(t, resp)
+		require.Len(t, deliveries, 1)
+		assert.Equal(t, hook.Id, deliveries[0].WebhookId)
+		assert.Contains(t, deliveries[0].RequestBody, model.WEBHOOK_EVENT_PING)
+	})
+
+	t.Run("preview payload formats", func(t *testing.T) {
+		preview, resp := Client.PreviewWebhook(&model.Webhook{PayloadFormat: model.WEBHOOK_PAYLOAD_FORMAT_SLACK}, model.WEBHOOK_EVENT_QUESTION_CREATED)
+		CheckNoError(t, resp)
+		assert.Equal(t, "application/json", preview.ContentType)
+		assert.Contains(t, preview.Body, `"blocks"`)
+
+		preview, resp = Client.PreviewWebhook(&model.Webhook{
+			PayloadFormat:   model.WEBHOOK_PAYLOAD_FORMAT_TEMPLATE,
+			PayloadTemplate: `{"text": "{{.Title}} by {{.AuthorName}}"}`,
+		}, model.WEBHOOK_EVENT_ANSWER_CREATED)
+		CheckNoError(t, resp)
+		assert.Contains(t, preview.Body, "by sample-user")
+
+		_, resp = Client.CreateWebhook(&model.Webhook{
+			TeamId:          team.Id,
+			Events:          []string{model.WEBHOOK_EVENT_POST_EDITED},
+			URLs:            []string{"http://localhost:8065/template"},
+			PayloadFormat:   model.WEBHOOK_PAYLOAD_FORMAT_TEMPLATE,
+			PayloadTemplate: "{{.Title",
+		})
+		CheckBadRequestStatus(t, resp)
+	})
+
+	t.Run("normal member cannot send test event", func(t *testing.T) {
+		require.Nil(t, th.App.JoinUserToTeam(team, th.BasicUser2, false))
+
+		Client.Logout()
+		th.LoginBasic2()
+
+		_, resp := Client.TestWebhook(hook.Id)
+		CheckForbiddenStatus(t, resp)
+	})
+}
+
+func TestIncomingWebhook(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+	Client := th.Client
+
+	team, err := th.App.CreateTeamWithUser(&model.Team{
+		Name: "incoming" + model.NewRandomString(10),
+		Type: model.TEAM_TYPE_PUBLIC,
+	}, th.BasicUser.Id)
+	require.Nil(t, err)
+
+	bot, resp := Client.CreateBot(&model.Bot{
+		TeamId:      team.Id,
+		Username:    "bot" + model.NewRandomString(10),
+		DisplayName: "CI Bot",
+	})
+	CheckNoError(t, resp)
+	CheckCreatedStatus(t, resp)
+
+	botUser, err := th.App.GetUser(bot.UserId)
+	require.Nil(t, err)
+	assert.True(t, botUser.IsBot())
+
+	hook, resp := Client.CreateIncomingWebhook(&model.IncomingWebhook{
+		TeamId:    team.Id,
+		BotUserId: bot.UserId,
+		Name:      "ci",
+	})
+	CheckNoError(t, resp)
+	CheckCreatedStatus(t, resp)
+
+	t.Run("create question and answer as bot", func(t *testing.T) {
+		question, resp := Client.ExecuteIncomingWebhook(hook.Token, &model.IncomingWebhookRequest{
+			Type:    model.POST_TYPE_QUESTION,
+			Title:   "build failed",
+			Content: "the nightly build failed",
+		})
+		CheckNoError(t, resp)
+		CheckCreatedStatus(t, resp)
+		assert.Equal(t, bot.UserId, question.UserId)
+		assert.Equal(t, team.Id, question.TeamId)
+
+		answer, resp := Client.ExecuteIncomingWebhook(hook.Token, &model.IncomingWebhookRequest{
+			Type:     model.POST_TYPE_ANSWER,
+			Content:  "the build was fixed",
+			ParentId: question.Id,
+		})
+		CheckNoError(t, resp)
+		assert.Equal(t, question.Id, answer.ParentId)
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		_, resp := Client.ExecuteIncomingWebhook(model.NewId(), &model.IncomingWebhookRequest{
+			Type:    model.POST_TYPE_QUESTION,
+			Title:   "title",
+			Content: "content",
+		})
+		CheckUnauthorizedStatus(t, resp)
+	})
+
+	t.Run("normal member cannot manage bots", func(t *testing.T) {
+		require.Nil(t, th.App.JoinUserToTeam(team, th.BasicUser2, false))
+
+		Client.Logout()
+		th.LoginBasic2()
+
+		_, resp := Client.GetBot(bot.UserId)
+		CheckForbiddenStatus(t, resp)
+	})
+}
+
+func TestWebhookHistory(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+	Client := th.Client
+
+	team, err := th.App.CreateTeamWithUser(&model.Team{
+		Name: "history" + model.NewRandomString(10),
+		Type: model.TEAM_TYPE_PUBLIC,
+	}, th.BasicUser.Id)
+	require.Nil(t, err)
+
+	hook, resp := Client.CreateWebhook(&model.Webhook{
+		TeamId: team.Id,
+		Events: []string{model.WEBHOOK_EVENT_POST_EDITED},
+		URLs:   []string{"http://localhost:8065/hook"},
+	})
+	CheckNoError(t, resp)
+
+	history := &model.WebhooksHistory{
+		Id:             model.NewId(),
+		WebhookId:      hook.Id,
+		TeamId:         team.Id,
+		URL:            hook.URLs[0],
+		ContentType:    "application/json",
+		RequestBody:    `{"event":"post_edited"}`,
+		ResponseStatus: 500,
+		RequestHeaders: model.StringMap{},
+		DeliveryId:     model.NewId(),
+		Attempt:        1,
+		CreateAt:       model.GetMillis(),
+	}
+	require.NoError(t, th.Server.Store.WebhooksHistory().LogWebhookEvent(history))
+
+	t.Run("get", func(t *testing.T) {
+		got, resp := Client.GetWebhookHistory(history.Id)
+		CheckNoError(t, resp)
+		assert.Equal(t, history.Id, got.Id)
+		assert.Equal(t, hook.Id, got.WebhookId)
+		assert.Equal(t, 500, got.ResponseStatus)
+
+		_, resp = Client.GetWebhookHistory(model.NewId())
+		CheckNotFoundStatus(t, resp)
+	})
+
+	t.Run("redeliver", func(t *testing.T) {
+		delivery, resp := Client.RedeliverWebhook(history.Id)
+		CheckNoError(t, resp)
+		CheckCreatedStatus(t, resp)
+		assert.Equal(t, hook.Id, delivery.WebhookId)
+		assert.Equal(t, history.URL, delivery.URL)
+		assert.Equal(t, history.RequestBody, delivery.RequestBody)
+		assert.Equal(t, model.WEBHOOK_DELIVERY_STATUS_PENDING, delivery.Status)
+
+		_, resp = Client.RedeliverWebhook(model.NewId())
+		CheckNotFoundStatus(t, resp)
+	})
+
+	t.Run("normal member cannot read or redeliver", func(t *testing.T) {
+		require.Nil(t, th.App.JoinUserToTeam(team, th.BasicUser2, false))
+
+		Client.Logout()
+		th.LoginBasic2()
+		defer func() {
+			Client.Logout()
+			th.LoginBasic()
+		}()
+
+		_, resp := Client.GetWebhookHistory(history.Id)
+		CheckForbiddenStatus(t, resp)
+
+		_, resp = Client.RedeliverWebhook(history.Id)
+		CheckForbiddenStatus(t, resp)
+	})
+
+	t.Run("disabled webhook cannot be redelivered", func(t *testing.T) {
+		require.Nil(t, th.Server.Store.Webhook().Disable(hook.Id, model.GetMillis()))
+
+		_, resp := Client.RedeliverWebhook(history.Id)
+		CheckBadRequestStatus(t, resp)
+	})
+}
@@ -1,10 +1,13 @@
 package app
 
 import (
+	"context"
+
 	"github.com/clear-ness/qa-discussion/clusters"
 	"github.com/clear-ness/qa-discussion/mlog"
 	"github.com/clear-ness/qa-discussion/model"
 	"github.com/clear-ness/qa-discussion/services/httpservice"
+	"github.com/clear-ness/qa-discussion/store"
 )
 
 type App struct {
@@ -24,6 +27,10 @@ type App struct {
 	Path           string
 	UserAgent      string
 	AcceptLanguage string
+
+	context context.Context
+	// リクエスト単位のstore(トレース用)。nilならSrv.Storeを使う
+	store store.Store
 }
 
 func New(options ...AppOption) *App {
@@ -36,6 +43,40 @@ func New(options ...AppOption) *App {
 	return app
 }
 
+func (a *App) Context() context.Context {
+	if a.context == nil {
+		return context.Background()
+	}
+
+	return a.context
+}
+
+func (a *App) SetContext(ctx context.Context) {
+	a.context = ctx
+}
+
+func (a *App) Store() store.Store {
+	if a.store != nil {
+		return a.store
+	}
+
+	return a.Srv.Store
+}
+
+func (a *App) SetStore(s store.Store) {
+	a.store = s
+}
+
+// リクエストが終わった後も続ける非同期処理用に、リクエストのcontextとstoreを外したコピーを返す。
+// リクエストのcontextは切断やレスポンスの完了でキャンセルされるので、そのまま使うと書き込みが失われる
+func (a *App) Detached() *App {
+	detached := *a
+	detached.context = nil
+	detached.store = nil
+
+	return &detached
+}
+
 func (a *App) Shutdown() {
 	a.Srv.Shutdown()
 	a.Srv = nil
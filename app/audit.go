@@ -1,13 +1,102 @@
 package app
 
 import (
+	"github.com/clear-ness/qa-discussion/audit"
+	"github.com/clear-ness/qa-discussion/mlog"
 	"github.com/clear-ness/qa-discussion/model"
+	"github.com/clear-ness/qa-discussion/store"
 )
 
 func (a *App) GetAudits(userId string, limit int) (model.Audits, *model.AppError) {
-	return a.Srv.Store.Audit().Get(userId, 0, limit)
+	return a.Store().Audit().Get(userId, 0, limit)
 }
 
 func (a *App) GetAuditsPage(userId string, page int, perPage int) (model.Audits, *model.AppError) {
-	return a.Srv.Store.Audit().Get(userId, page*perPage, perPage)
+	return a.Store().Audit().Get(userId, page*perPage, perPage)
+}
+
+func (a *App) SearchAudits(options *model.SearchAuditsOptions) (model.Audits, *model.AppError) {
+	return a.Store().Audit().Search(options)
+}
+
+// errがある場合は失敗として記録する
+func (a *App) LogAuditRec(rec *audit.Record, err *model.AppError) {
+	if rec == nil {
+		return
+	}
+
+	if err != nil {
+		rec.AddMeta("err", err.Id)
+		rec.AddMeta("code", err.StatusCode)
+		rec.Fail()
+	}
+
+	if a.Srv.Audit != nil {
+		a.Srv.Audit.Log(rec)
+	}
+}
+
+// Recordの書き出し先として、Auditsテーブルに保存する
+type dbAuditTarget struct {
+	store store.Store
+}
+
+func (t *dbAuditTarget) Log(rec *audit.Record) error {
+	a := &model.Audit{
+		CreateAt:  rec.CreateAt,
+		UserId:    rec.UserID,
+		Action:    rec.Event,
+		ExtraInfo: rec.MetaToJson(),
+		IpAddress: rec.IPAddress,
+		SessionId: rec.SessionID,
+		Status:    rec.Status,
+		ApiPath:   rec.APIPath,
+		Client:    rec.Client,
+	}
+
+	if err := t.store.Audit().Save(a); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (t *dbAuditTarget) Shutdown() error {
+	return nil
+}
+
+// 設定に従ってaudit recordの書き出し先を用意する。
+// 書き出し先の作成に失敗した場合はログに残し、残りの書き出し先だけで動かす。
+func (s *Server) configureAudit() {
+	settings := s.Config().AuditSettings
+
+	s.Audit = audit.NewAudit(*settings.QueueSize)
+	s.Audit.OnQueueFull = func(rec *audit.Record) {
+		mlog.Error("Audit queue is full, dropping record", mlog.String("event", rec.Event), mlog.String("user_id", rec.UserID))
+	}
+	s.Audit.OnError = func(err error) {
+		mlog.Error("Failed to write audit record", mlog.Err(err))
+	}
+
+	if *settings.EnableDatabase {
+		s.Audit.AddTarget(&dbAuditTarget{store: s.Store})
+	}
+
+	if *settings.EnableFile {
+		target, err := audit.NewFileTarget(*settings.FileName, *settings.FileMaxSizeMB, *settings.FileMaxBackups)
+		if err != nil {
+			mlog.Error("Failed to open audit file", mlog.String("file", *settings.FileName), mlog.Err(err))
+		} else {
+			s.Audit.AddTarget(target)
+		}
+	}
+
+	if *settings.EnableSyslog {
+		target, err := audit.NewSyslogTarget(*settings.SyslogNetwork, *settings.SyslogAddress, *settings.SyslogTag)
+		if err != nil {
+			mlog.Error("Failed to connect to syslog for audit", mlog.String("address", *settings.SyslogAddress), mlog.Err(err))
+		} else {
+			s.Audit.AddTarget(target)
+		}
+	}
 }
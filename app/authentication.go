@@ -21,6 +21,10 @@ func (a *App) IsPasswordValid(password string) *model.AppError {
 }
 
 func (a *App) authenticateUser(user *model.User, password string) (*model.User, *model.AppError) {
+	if user.IsSSOUser() {
+		return user, model.NewAppError("authenticateUser", "api.user.login.use_auth_service.app_error", map[string]interface{}{"AuthService": user.AuthService}, "user_id="+user.Id, http.StatusBadRequest)
+	}
+
 	if err := a.CheckPasswordAndAllCriteria(user, password); err != nil {
 		err.StatusCode = http.StatusUnauthorized
 		return user, err
@@ -35,7 +39,7 @@ func (a *App) CheckPasswordAndAllCriteria(user *model.User, password string) *mo
 	}
 
 	if err := a.checkUserPassword(user, password); err != nil {
-		if passErr := a.Srv.Store.User().UpdateFailedPasswordAttempts(user.Id, user.FailedAttempts+1); passErr != nil {
+		if passErr := a.Store().User().UpdateFailedPasswordAttempts(user.Id, user.FailedAttempts+1); passErr != nil {
 			return passErr
 		}
 
@@ -44,7 +48,7 @@ func (a *App) CheckPasswordAndAllCriteria(user *model.User, password string) *mo
 		return err
 	}
 
-	if passErr := a.Srv.Store.User().UpdateFailedPasswordAttempts(user.Id, 0); passErr != nil {
+	if passErr := a.Store().User().UpdateFailedPasswordAttempts(user.Id, 0); passErr != nil {
 		return passErr
 	}
 
@@ -62,6 +66,10 @@ func (a *App) CheckUserPreflightAuthenticationCriteria(user *model.User) *model.
 		return err
 	}
 
+	if err := checkUserNotBot(user); err != nil {
+		return err
+	}
+
 	if err := checkUserLoginAttempts(user, *a.Config().ServiceSettings.MaximumLoginAttempts); err != nil {
 		return err
 	}
@@ -93,6 +101,14 @@ func checkUserNotDisabled(user *model.User) *model.AppError {
 	return nil
 }
 
+func checkUserNotBot(user *model.User) *model.AppError {
+	if user.IsBot() {
+		return model.NewAppError("Login", "api.user.login.bot_login_forbidden.app_error", nil, "user_id="+user.Id, http.StatusUnauthorized)
+	}
+
+	return nil
+}
+
 func (a *App) checkUserPassword(user *model.User, password string) *model.AppError {
 	if !model.ComparePassword(user.Password, password) {
 		return model.NewAppError("checkUserPassword", "api.user.check_user_password.invalid.app_error", nil, "user_id="+user.Id, http.StatusUnauthorized)
@@ -107,7 +123,7 @@ func (a *App) DoubleCheckPassword(user *model.User, password string) *model.AppE
 	}
 
 	if err := a.checkUserPassword(user, password); err != nil {
-		if passErr := a.Srv.Store.User().UpdateFailedPasswordAttempts(user.Id, user.FailedAttempts+1); passErr != nil {
+		if passErr := a.Store().User().UpdateFailedPasswordAttempts(user.Id, user.FailedAttempts+1); passErr != nil {
 			return passErr
 		}
 
@@ -116,7 +132,7 @@ func (a *App) DoubleCheckPassword(user *model.User, password string) *model.AppE
 		return err
 	}
 
-	if passErr := a.Srv.Store.User().UpdateFailedPasswordAttempts(user.Id, 0); passErr != nil {
+	if passErr := a.Store().User().UpdateFailedPasswordAttempts(user.Id, 0); passErr != nil {
 		return passErr
 	}
 
@@ -0,0 +1,109 @@
+package app
+
+import (
+	"mime/multipart"
+	"net/http"
+
+	"github.com/clear-ness/qa-discussion/mlog"
+	"github.com/clear-ness/qa-discussion/model"
+)
+
+func (a *App) CreateBot(bot *model.Bot) (*model.Bot, *model.AppError) {
+	if !model.IsValidUsername(bot.Username) {
+		return nil, model.NewAppError("CreateBot", "app.bot.create_bot.username.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	// ユーザー名やメールアドレスの重複はUserの保存時に検出される
+	user, err := a.Store().User().Save(model.UserFromBot(bot))
+	if err != nil {
+		return nil, err
+	}
+
+	bot.UserId = user.Id
+	rbot, err := a.Store().Bot().Save(bot)
+	if err != nil {
+		// 投稿者として使われる前なので、作りかけのユーザーは削除済みにしておく
+		if deleteErr := a.Store().User().Delete(user.Id, model.GetMillis(), bot.OwnerId); deleteErr != nil {
+			mlog.Error("Failed to delete user for bot", mlog.String("user_id", user.Id), mlog.Err(deleteErr))
+		}
+		return nil, err
+	}
+
+	rbot.Username = user.Username
+	return rbot, nil
+}
+
+func (a *App) GetBot(userId string) (*model.Bot, *model.AppError) {
+	bot, err := a.Store().Bot().Get(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := a.Store().User().Get(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	bot.Username = user.Username
+	return bot, nil
+}
+
+func (a *App) GetBotsForTeamPage(teamId string, page, perPage int) ([]*model.Bot, *model.AppError) {
+	bots, err := a.Store().Bot().GetByTeam(teamId, page*perPage, perPage)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(bots) == 0 {
+		return bots, nil
+	}
+
+	userIds := make([]string, 0, len(bots))
+	for _, bot := range bots {
+		userIds = append(userIds, bot.UserId)
+	}
+
+	users, err := a.Store().User().GetByIds(userIds)
+	if err != nil {
+		return nil, err
+	}
+
+	usernames := make(map[string]string, len(users))
+	for _, user := range users {
+		usernames[user.Id] = user.Username
+	}
+
+	for _, bot := range bots {
+		bot.Username = usernames[bot.UserId]
+	}
+
+	return bots, nil
+}
+
+func (a *App) PatchBot(bot *model.Bot, patch *model.BotPatch) (*model.Bot, *model.AppError) {
+	bot.Patch(patch)
+
+	return a.Store().Bot().Update(bot)
+}
+
+// botのユーザーを削除済みにし、そのbotで投稿するincoming webhookも使えなくする。
+// 既に作られた投稿はbotの名前のまま残る。
+func (a *App) DeleteBot(bot *model.Bot, deleteById string) *model.AppError {
+	curTime := model.GetMillis()
+
+	if err := a.Store().IncomingWebhook().DeleteByBot(bot.UserId, curTime); err != nil {
+		return err
+	}
+
+	bot.DeleteAt = curTime
+	if _, err := a.Store().Bot().Update(bot); err != nil {
+		return err
+	}
+
+	return a.Store().User().Delete(bot.UserId, curTime, deleteById)
+}
+
+// アイコンは通常のユーザーのプロフィール画像と同じ場所に保存する
+func (a *App) SetBotIconImage(botUserId string, imageData *multipart.FileHeader) (string, *model.AppError) {
+	return a.SetProfileImage(botUserId, imageData)
+}
@@ -0,0 +1,71 @@
+package app
+
+import (
+	"net/http"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/clear-ness/qa-discussion/mlog"
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/clear-ness/qa-discussion/services/cache"
+)
+
+// 自サーバーのL1キャッシュ(user session)と、webConnが持つセッション情報を全て削除する
+func (a *App) ClearLocalCaches() {
+	if err := a.Srv.sessionCache.Purge(); err != nil {
+		mlog.Error("Failed to purge the session cache", mlog.Err(err))
+	}
+
+	a.Srv.InvalidateAllWebConnSessionCaches()
+}
+
+// 自サーバーとクラスタ全体のL1キャッシュを削除する。includeRedisならredisのキャッシュも全て削除する。
+// redisには書き出し前の閲覧数も溜まっているので、先にDBへ書き出しておく。
+func (a *App) InvalidateAllCaches(includeRedis bool) *model.AppError {
+	if includeRedis {
+		if _, err := a.Store().Post().FlushBufferedViews(POST_VIEWS_FLUSH_BATCH_SIZE); err != nil {
+			return err
+		}
+
+		if _, err := cache.NewRedisBackend(&a.Config().CacheSettings).WithContext(a.Context()).FlushAll(); err != nil {
+			return model.NewAppError("InvalidateAllCaches", "app.cache.flush_redis.app_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+	}
+
+	a.ClearLocalCaches()
+
+	if a.Srv.Cluster != nil {
+		a.Srv.Cluster.SendClusterMessage(&model.ClusterMessage{
+			OmitCluster: a.Srv.clusterId,
+			Event:       model.CLUSTER_EVENT_INVALIDATE_ALL_CACHES,
+		})
+	}
+
+	return nil
+}
+
+func (s *Server) RedisClient() *redis.Client {
+	s.redisClientLock.RLock()
+	defer s.redisClientLock.RUnlock()
+
+	return s.redisClient
+}
+
+// 設定に合わせてredis clientを作り直し、前のclientを閉じる。cfgがnilなら閉じるだけ
+func (s *Server) configureRedisClient(cfg *model.Config) {
+	var client *redis.Client
+	if cfg != nil {
+		client = cache.NewRedisClient(&cfg.CacheSettings)
+	}
+
+	s.redisClientLock.Lock()
+	old := s.redisClient
+	s.redisClient = client
+	s.redisClientLock.Unlock()
+
+	if old != nil {
+		if err := old.Close(); err != nil {
+			mlog.Warn("Failed to close the redis client", mlog.Err(err))
+		}
+	}
+}
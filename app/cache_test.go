@@ -0,0 +1,36 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/clear-ness/qa-discussion/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisClient(t *testing.T) {
+	th := Setup(t)
+	defer th.TearDown()
+
+	// リクエストごとに作らず、サーバーで共有する
+	client := th.Server.RedisClient()
+	require.NotNil(t, client)
+	assert.Same(t, client, th.Server.RedisClient())
+
+	// キャッシュ以外の設定の変更では作り直さない
+	th.UpdateConfig(func(cfg *model.Config) {
+		*cfg.SqlSettings.ReadYourWritesSeconds = 10
+	})
+	assert.Same(t, client, th.Server.RedisClient())
+
+	th.UpdateConfig(func(cfg *model.Config) {
+		*cfg.CacheSettings.CacheEndpoint = "localhost:6380"
+	})
+	updated := th.Server.RedisClient()
+	require.NotNil(t, updated)
+	assert.NotSame(t, client, updated)
+	assert.Equal(t, "localhost:6380", updated.Options().Addr)
+	// 前のclientは閉じている
+	assert.Error(t, client.Ping(client.Context()).Err())
+}
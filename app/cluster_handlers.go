@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"github.com/clear-ness/qa-discussion/clusters"
+	"github.com/clear-ness/qa-discussion/mlog"
 	"github.com/clear-ness/qa-discussion/model"
 )
 
@@ -12,6 +13,10 @@ func (a *App) registerAllClusterMessageHandlers() {
 	a.Cluster.RegisterClusterMessageHandler(model.CLUSTER_EVENT_WEBSOCKET, clusters.NewClusterMessageHandler(a.clusterPublishHandler))
 
 	a.Cluster.RegisterClusterMessageHandler(model.CLUSTER_EVENT_CLEAR_SESSION_CACHE_FOR_USER, clusters.NewClusterMessageHandler(a.clusterClearSessionCacheForUserHandler))
+
+	a.Cluster.RegisterClusterMessageHandler(model.CLUSTER_EVENT_CONFIG_CHANGED, clusters.NewClusterMessageHandler(a.clusterConfigChangedHandler))
+
+	a.Cluster.RegisterClusterMessageHandler(model.CLUSTER_EVENT_INVALIDATE_ALL_CACHES, clusters.NewClusterMessageHandler(a.clusterInvalidateAllCachesHandler))
 }
 
 func (a *App) clusterPublishHandler(msg *model.ClusterMessage) {
@@ -26,3 +31,13 @@ func (a *App) clusterPublishHandler(msg *model.ClusterMessage) {
 func (a *App) clusterClearSessionCacheForUserHandler(msg *model.ClusterMessage) {
 	a.ClearLocalSessionCacheForUser(msg.Data)
 }
+
+func (a *App) clusterConfigChangedHandler(msg *model.ClusterMessage) {
+	if err := a.Srv.ReloadConfig(); err != nil {
+		mlog.Error("Failed to reload config from cluster message", mlog.Err(err))
+	}
+}
+
+func (a *App) clusterInvalidateAllCachesHandler(msg *model.ClusterMessage) {
+	a.ClearLocalCaches()
+}
@@ -32,7 +32,7 @@ func (a *App) CreateCollectionWithUser(collection *model.Collection, userId stri
 }
 
 func (a *App) GetNumberOfCollectionsOnTeam(teamId string) (int, *model.AppError) {
-	list, err := a.Srv.Store.Collection().GetTeamCollections(teamId)
+	list, err := a.Store().Collection().GetTeamCollections(teamId)
 	if err != nil {
 		return 0, err
 	}
@@ -43,7 +43,7 @@ func (a *App) GetNumberOfCollectionsOnTeam(teamId string) (int, *model.AppError)
 func (a *App) CreateCollection(collection *model.Collection, addPost bool) (*model.Collection, *model.AppError) {
 	// TODO:collection.Titleを事前に(regex)整形しておく
 
-	col, err := a.Srv.Store.Collection().Save(collection, *a.Config().TeamSettings.MaxCollectionsPerTeam)
+	col, err := a.Store().Collection().Save(collection, *a.Config().TeamSettings.MaxCollectionsPerTeam)
 	if err != nil {
 		return nil, err
 	}
@@ -52,15 +52,15 @@ func (a *App) CreateCollection(collection *model.Collection, addPost bool) (*mod
 }
 
 func (a *App) GetCollection(collectionId string) (*model.Collection, *model.AppError) {
-	return a.Srv.Store.Collection().Get(collectionId)
+	return a.Store().Collection().Get(collectionId)
 }
 
 func (a *App) GetCollectionPost(collectionId string, postId string) (*model.CollectionPost, *model.AppError) {
-	return a.Srv.Store.Collection().GetPost(collectionId, postId)
+	return a.Store().Collection().GetPost(collectionId, postId)
 }
 
 func (a *App) AddCollectionPost(postId string, collection *model.Collection) (*model.CollectionPost, *model.AppError) {
-	if colPost, err := a.Srv.Store.Collection().GetPost(collection.Id, postId); err != nil {
+	if colPost, err := a.Store().Collection().GetPost(collection.Id, postId); err != nil {
 		if err.Id != store.MISSING_COLLECTION_POST_ERROR {
 			return nil, err
 		}
@@ -87,6 +87,13 @@ func (a *App) AddCollectionPost(postId string, collection *model.Collection) (*m
 		return nil, err
 	}
 
+	a.triggerWebhookEvent(collection.TeamId, model.WEBHOOK_EVENT_COLLECTION_UPDATED, a.Session.UserId, &model.WebhookCollectionData{
+		CollectionId: collection.Id,
+		Title:        collection.Title,
+		Action:       model.WEBHOOK_COLLECTION_ACTION_POST_ADDED,
+		PostId:       post.Id,
+	})
+
 	return colPost, nil
 }
 
@@ -96,7 +103,7 @@ func (a *App) AddPostToCollection(post *model.Post, collection *model.Collection
 		PostId:       post.Id,
 	}
 
-	newColPost, err := a.Srv.Store.Collection().SavePost(newColPost)
+	newColPost, err := a.Store().Collection().SavePost(newColPost)
 	if err != nil {
 		return nil, model.NewAppError("AddPostToCollection", "api.collection.add_post.failed.app_error", nil, "", http.StatusInternalServerError)
 	}
@@ -105,15 +112,25 @@ func (a *App) AddPostToCollection(post *model.Post, collection *model.Collection
 }
 
 func (a *App) GetCollectionPostsPage(collectionId string, page, perPage int) (*model.CollectionPosts, *model.AppError) {
-	return a.Srv.Store.Collection().GetPosts(collectionId, page*perPage, perPage)
+	return a.Store().Collection().GetPosts(collectionId, page*perPage, perPage)
 }
 
-func (a *App) GetCollectionsForTeam(teamId string, offset int, limit int, title string) (*model.CollectionList, *model.AppError) {
-	return a.Srv.Store.Collection().GetCollectionsForTeam(teamId, offset, limit, title)
+func (a *App) GetCollectionsForTeam(teamId string, offset int, limit int, title string, cursor *model.Cursor) (*model.CollectionList, model.CursorPage, *model.AppError) {
+	cols, err := a.Store().Collection().GetCollectionsForTeam(teamId, offset, limit, title, cursor)
+	if err != nil {
+		return nil, model.CursorPage{}, err
+	}
+
+	list := *cols
+	cursorPage := model.NewCursorPage(len(list), limit, cursor, offset > 0, func(i int) *model.Cursor {
+		return model.NewCursor(model.CURSOR_TYPE_COLLECTIONS, "", list[i].CreateAt, list[i].Id)
+	})
+
+	return cols, cursorPage, nil
 }
 
 func (a *App) RemovePostFromCollection(collectionId string, postId string) *model.AppError {
-	if _, err := a.Srv.Store.Collection().GetPost(collectionId, postId); err != nil {
+	if _, err := a.Store().Collection().GetPost(collectionId, postId); err != nil {
 		return err
 	}
 
@@ -135,10 +152,17 @@ func (a *App) RemovePostFromCollection(collectionId string, postId string) *mode
 		return model.NewAppError("RemoveCollectionPost", "api.collection.removecollection_post.different_team_id.app_error", nil, "", http.StatusBadRequest)
 	}
 
-	if err := a.Srv.Store.Collection().RemovePost(collection.Id, postId); err != nil {
+	if err := a.Store().Collection().RemovePost(collection.Id, postId); err != nil {
 		return err
 	}
 
+	a.triggerWebhookEvent(collection.TeamId, model.WEBHOOK_EVENT_COLLECTION_UPDATED, a.Session.UserId, &model.WebhookCollectionData{
+		CollectionId: collection.Id,
+		Title:        collection.Title,
+		Action:       model.WEBHOOK_COLLECTION_ACTION_POST_REMOVED,
+		PostId:       postId,
+	})
+
 	return nil
 }
 
@@ -149,9 +173,15 @@ func (a *App) DeleteCollection(collection *model.Collection) *model.AppError {
 	}
 
 	deleteAt := model.GetMillis()
-	if err := a.Srv.Store.Collection().Delete(collection.Id, deleteAt); err != nil {
+	if err := a.Store().Collection().Delete(collection.Id, deleteAt); err != nil {
 		return model.NewAppError("DeleteCollection", "app.collection.delete.app_error", nil, err.Error(), http.StatusInternalServerError)
 	}
 
+	a.triggerWebhookEvent(collection.TeamId, model.WEBHOOK_EVENT_COLLECTION_UPDATED, a.Session.UserId, &model.WebhookCollectionData{
+		CollectionId: collection.Id,
+		Title:        collection.Title,
+		Action:       model.WEBHOOK_COLLECTION_ACTION_DELETED,
+	})
+
 	return nil
 }
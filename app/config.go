@@ -1,6 +1,11 @@
 package app
 
 import (
+	"net/http"
+	"reflect"
+
+	"github.com/clear-ness/qa-discussion/config"
+	"github.com/clear-ness/qa-discussion/mlog"
 	"github.com/clear-ness/qa-discussion/model"
 )
 
@@ -12,6 +17,107 @@ func (s *Server) Config() *model.Config {
 	return s.configStore.Get()
 }
 
+// restrictedの項目も含めてそのまま書き換える。管理画面からの保存にはSaveConfigを使う
+func (s *Server) UpdateConfig(f func(*model.Config)) {
+	cfg := s.Config().Clone()
+	f(cfg)
+
+	if _, err := s.configStore.Set(cfg); err != nil {
+		mlog.Error("Failed to update config", mlog.Err(err))
+	}
+}
+
+func (s *Server) AddConfigListener(listener func(*model.Config, *model.Config)) string {
+	return s.configStore.AddListener(listener)
+}
+
+func (s *Server) RemoveConfigListener(id string) {
+	s.configStore.RemoveListener(id)
+}
+
+// 再起動せずに反映できる設定を、変更のあったサブシステムにだけ反映する。
+// メール・ファイルのbackendとキャッシュ層のredis clientは使う度に設定から作るので、ここでは扱わない。
+// read-your-writesで使うredis clientはサーバーで共有しているので作り直す。
+func (s *Server) onConfigChanged(oldCfg, newCfg *model.Config) {
+	if !reflect.DeepEqual(oldCfg.RateLimitSettings, newCfg.RateLimitSettings) ||
+		!reflect.DeepEqual(oldCfg.ServiceSettings.TrustedProxyIPHeader, newCfg.ServiceSettings.TrustedProxyIPHeader) {
+		if err := s.configureRateLimiter(newCfg); err != nil {
+			mlog.Error("Failed to reconfigure rate limiter", mlog.Err(err))
+		}
+	}
+
+	if s.searchLayer != nil && !reflect.DeepEqual(oldCfg.SearchSettings, newCfg.SearchSettings) {
+		if err := s.searchLayer.UpdateConfig(newCfg); err != nil {
+			mlog.Error("Failed to reconfigure search backend", mlog.Err(err))
+		}
+	}
+
+	if !reflect.DeepEqual(oldCfg.CacheSettings, newCfg.CacheSettings) {
+		s.configureRedisClient(newCfg)
+	}
+
+	if s.EmailBatching != nil && !reflect.DeepEqual(oldCfg.EmailBatchJobSettings, newCfg.EmailBatchJobSettings) {
+		s.EmailBatching.rescheduleJobs()
+	}
+
+	mlog.Info("Config changed", mlog.String("source", s.configStore.String()))
+}
+
+// 保存先から読み直す。DBに保存している場合は他サーバーの変更もここで取り込む
+func (s *Server) ReloadConfig() error {
+	return s.configStore.Load()
+}
+
+func (a *App) GetEnvironmentConfig() map[string]interface{} {
+	return a.Srv.configStore.GetEnvironmentOverrides()
+}
+
+// restricted の項目を除いた設定
+func (a *App) GetSanitizedConfig() *model.Config {
+	return config.RemoveRestricted(a.Config())
+}
+
+// restricted の項目は今の値を引き継いで保存し、他のサーバーにも読み直させる
+func (a *App) SaveConfig(newCfg *model.Config, sendConfigChangeClusterMessage bool) (*model.Config, *model.AppError) {
+	newCfg = config.KeepRestricted(newCfg, a.Config())
+	newCfg.SetDefaults()
+
+	if appErr := newCfg.IsValid(); appErr != nil {
+		return nil, appErr
+	}
+
+	if _, err := a.Srv.configStore.Set(newCfg); err != nil {
+		return nil, model.NewAppError("SaveConfig", "app.config.save.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	if sendConfigChangeClusterMessage {
+		a.sendConfigChangedClusterMessage()
+	}
+
+	return a.GetSanitizedConfig(), nil
+}
+
+func (a *App) ReloadConfig() *model.AppError {
+	if err := a.Srv.ReloadConfig(); err != nil {
+		return model.NewAppError("ReloadConfig", "app.config.reload.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	a.sendConfigChangedClusterMessage()
+
+	return nil
+}
+
+func (a *App) sendConfigChangedClusterMessage() {
+	if a.Srv.Cluster == nil {
+		return
+	}
+
+	a.Srv.Cluster.SendClusterMessage(&model.ClusterMessage{
+		OmitCluster: a.Srv.clusterId,
+		Event:       model.CLUSTER_EVENT_CONFIG_CHANGED,
+	})
+}
+
 func (a *App) GetSiteURL() string {
 	return *a.Config().ServiceSettings.SiteURL
 }
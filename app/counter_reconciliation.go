@@ -0,0 +1,277 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/clear-ness/qa-discussion/mlog"
+	"github.com/clear-ness/qa-discussion/model"
+)
+
+const (
+	RECONCILE_DEFAULT_BATCH_SIZE = 1000
+	RECONCILE_MAX_BATCH_SIZE     = 10000
+	// レポートはJobs.Data(text型)に収める必要がある
+	RECONCILE_MAX_REPORTED_DRIFTS = 1000
+)
+
+type ReconcileReport struct {
+	DryRun bool `json:"dry_run"`
+	// テーブルごとに照合した行数
+	Checked    map[string]int        `json:"checked"`
+	DriftCount int                   `json:"drift_count"`
+	Drifts     []*model.CounterDrift `json:"drifts"`
+	Fixed      int                   `json:"fixed"`
+	// 照合してから修正するまでの間に値が更新されていたもの。次回の実行で再度照合される
+	Skipped int `json:"skipped"`
+}
+
+func (r *ReconcileReport) ToJson() string {
+	b, _ := json.Marshal(r)
+	return string(b)
+}
+
+func (r *ReconcileReport) addDrift(drift *model.CounterDrift) {
+	r.DriftCount++
+	if len(r.Drifts) < RECONCILE_MAX_REPORTED_DRIFTS {
+		r.Drifts = append(r.Drifts, drift)
+	}
+}
+
+type counterFixer func(drift *model.CounterDrift) (bool, *model.AppError)
+
+func (r *ReconcileReport) handleDrifts(drifts []*model.CounterDrift, fix counterFixer) *model.AppError {
+	for _, drift := range drifts {
+		r.addDrift(drift)
+		if r.DryRun {
+			continue
+		}
+
+		fixed, err := fix(drift)
+		if err != nil {
+			return err
+		}
+
+		if fixed {
+			r.Fixed++
+		} else {
+			r.Skipped++
+		}
+	}
+
+	return nil
+}
+
+func (a *App) CreateReconcileCountersJob(userId string, dryRun bool) (*model.Job, *model.AppError) {
+	data := map[string]string{model.JOB_DATA_DRY_RUN: strconv.FormatBool(dryRun)}
+	job, err := a.createTeamJob(model.JOB_TYPE_RECONCILE_COUNTERS, "", userId, data)
+	if err != nil {
+		return nil, err
+	}
+
+	background := a.Detached()
+	a.Srv.Go(func() {
+		background.runReconcileCountersJob(job, dryRun)
+	})
+
+	return job, nil
+}
+
+func (a *App) runReconcileCountersJob(job *model.Job, dryRun bool) {
+	if err := a.setJobInProgress(job); err != nil {
+		a.setJobError(job, err)
+		return
+	}
+
+	report, err := a.ReconcileCounters(dryRun, RECONCILE_DEFAULT_BATCH_SIZE)
+	if report != nil {
+		job.Data[model.JOB_DATA_RECONCILE_REPORT] = report.ToJson()
+	}
+
+	if err != nil {
+		a.setJobError(job, err)
+		return
+	}
+
+	a.setJobSuccess(job)
+}
+
+// 非正規化したカウンタを元のテーブル(Votes、子のPosts、UserPointHistoryなど)から計算し直し、ずれを報告する。
+// dryRunでなければずれを修正する。Stack Exchangeからインポートしたデータは投票や履歴の行を持たないので、
+// 投票数とポイントは照合しない。
+func (a *App) ReconcileCounters(dryRun bool, batchSize int) (*ReconcileReport, *model.AppError) {
+	if batchSize <= 0 || batchSize > RECONCILE_MAX_BATCH_SIZE {
+		return nil, model.NewAppError("ReconcileCounters", "app.reconcile.batch_size.app_error", nil, "batch_size="+strconv.Itoa(batchSize), http.StatusBadRequest)
+	}
+
+	report := &ReconcileReport{
+		DryRun:  dryRun,
+		Checked: map[string]int{},
+		Drifts:  []*model.CounterDrift{},
+	}
+
+	steps := []struct {
+		table string
+		run   func(*ReconcileReport, int) *model.AppError
+	}{
+		{model.COUNTER_TABLE_POSTS, a.reconcilePostCounters},
+		{model.COUNTER_TABLE_TAGS, a.reconcileTagCounters},
+		{model.COUNTER_TABLE_USERS, a.reconcileUserPoints},
+		{model.COUNTER_TABLE_TEAM_MEMBERS, a.reconcileTeamMemberPoints},
+	}
+
+	for _, step := range steps {
+		if err := step.run(report, batchSize); err != nil {
+			return report, err
+		}
+
+		mlog.Info("Reconciled counters", mlog.String("table", step.table), mlog.Int("checked", report.Checked[step.table]), mlog.Int("drifts", report.DriftCount))
+	}
+
+	return report, nil
+}
+
+func (a *App) reconcilePostCounters(report *ReconcileReport, batchSize int) *model.AppError {
+	var cursor *model.CounterCursor
+	for {
+		batch, err := a.Store().Post().GetCounterDrifts(cursor, batchSize, STACK_EXCHANGE_PROP_ID)
+		if err != nil {
+			return err
+		}
+
+		report.Checked[model.COUNTER_TABLE_POSTS] += batch.Checked
+		if err := report.handleDrifts(batch.Drifts, a.Store().Post().FixCounterDrift); err != nil {
+			return err
+		}
+
+		if batch.Next == nil {
+			return nil
+		}
+		cursor = batch.Next
+	}
+}
+
+func (a *App) reconcileUserPoints(report *ReconcileReport, batchSize int) *model.AppError {
+	var cursor *model.CounterCursor
+	for {
+		batch, err := a.Store().User().GetPointsDrifts(cursor, batchSize, STACK_EXCHANGE_PROP_ID)
+		if err != nil {
+			return err
+		}
+
+		report.Checked[model.COUNTER_TABLE_USERS] += batch.Checked
+		if err := report.handleDrifts(batch.Drifts, a.Store().User().FixPointsDrift); err != nil {
+			return err
+		}
+
+		if batch.Next == nil {
+			return nil
+		}
+		cursor = batch.Next
+	}
+}
+
+func (a *App) reconcileTeamMemberPoints(report *ReconcileReport, batchSize int) *model.AppError {
+	var cursor *model.CounterCursor
+	for {
+		batch, err := a.Store().Team().GetMemberPointsDrifts(cursor, batchSize, STACK_EXCHANGE_PROP_ID)
+		if err != nil {
+			return err
+		}
+
+		report.Checked[model.COUNTER_TABLE_TEAM_MEMBERS] += batch.Checked
+		if err := report.handleDrifts(batch.Drifts, a.Store().Team().FixMemberPointsDrift); err != nil {
+			return err
+		}
+
+		if batch.Next == nil {
+			return nil
+		}
+		cursor = batch.Next
+	}
+}
+
+// タグはPosts.Tagsにスペース区切りで入っているので、SQLのLIKEで数えると
+// タグ数×質問数になってしまう。チーム単位でタグを読み込んでから質問を1回だけ走査して数える。
+func (a *App) reconcileTagCounters(report *ReconcileReport, batchSize int) *model.AppError {
+	var cursor *model.CounterCursor
+	var teamTags model.Tags
+	for {
+		tags, err := a.Store().Tag().GetPostTagsAfter(cursor, batchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, tag := range tags {
+			if len(teamTags) > 0 && teamTags[0].TeamId != tag.TeamId {
+				if err := a.reconcileTeamTagCounters(report, teamTags, batchSize); err != nil {
+					return err
+				}
+				teamTags = nil
+			}
+
+			teamTags = append(teamTags, tag)
+		}
+
+		if len(tags) < batchSize {
+			break
+		}
+
+		last := tags[len(tags)-1]
+		cursor = &model.CounterCursor{TeamId: last.TeamId, Key: last.Content}
+	}
+
+	if len(teamTags) == 0 {
+		return nil
+	}
+
+	return a.reconcileTeamTagCounters(report, teamTags, batchSize)
+}
+
+// 質問を数える前にタグを読み込んでおくので、その間に加算された分は修正時の条件で弾かれる
+func (a *App) reconcileTeamTagCounters(report *ReconcileReport, tags model.Tags, batchSize int) *model.AppError {
+	teamId := tags[0].TeamId
+
+	counts := make(map[string]int, len(tags))
+	afterId := ""
+	for {
+		posts, err := a.Store().Post().GetPostsForExport(teamId, model.POST_TYPE_QUESTION, afterId, batchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, post := range posts {
+			for _, content := range strings.Fields(post.Tags) {
+				counts[strings.ToLower(content)]++
+			}
+		}
+
+		if len(posts) < batchSize {
+			break
+		}
+		afterId = posts[len(posts)-1].Id
+	}
+
+	report.Checked[model.COUNTER_TABLE_TAGS] += len(tags)
+
+	var drifts []*model.CounterDrift
+	for _, tag := range tags {
+		expected := counts[strings.ToLower(tag.Content)]
+		if tag.PostCount == expected {
+			continue
+		}
+
+		drifts = append(drifts, &model.CounterDrift{
+			Table:    model.COUNTER_TABLE_TAGS,
+			TeamId:   teamId,
+			Key:      tag.Content,
+			Column:   model.COUNTER_COLUMN_POST_COUNT,
+			Actual:   int64(tag.PostCount),
+			Expected: int64(expected),
+		})
+	}
+
+	return report.handleDrifts(drifts, a.Store().Tag().FixCounterDrift)
+}
@@ -178,6 +178,28 @@ func SendInboxMessagesDigestEmail(email, siteURL string, messageCount int64, con
 // 宛先毎にTOKEN_TYPE_TEAM_INVITATIONでTokenをDBに保存し、メアド宛にsignup_user_completeを付与して送信する。
 // つまりサインアップさせると同時にチーム参加API(addUserToTeamFromInvite)を叩かせる？
 // TODO: メール送信(injection注意)
+func (a *App) SendTeamExportCompletedEmail(email string, team *model.Team, link string, expireHours int) *model.AppError {
+	htmlBody := "<p>The export of team " + team.Name + " has completed. You can download it from this link: <a href=\"" + link + "\">download</a></p>" +
+		"<p>The link will expire in " + strconv.Itoa(expireHours) + " hours.</p>"
+	textBody := "The export of team " + team.Name + " has completed. You can download it from this link: " + link + "\n" +
+		"The link will expire in " + strconv.Itoa(expireHours) + " hours."
+
+	mail := &mail.MailData{
+		Sender:    *a.Config().EmailSettings.SupportEmail,
+		Recipient: email,
+		Subject:   "QA Discussion",
+		HtmlBody:  htmlBody,
+		TextBody:  textBody,
+		CharSet:   "UTF-8",
+	}
+
+	if err := sendMail(mail, a.Config()); err != nil {
+		return model.NewAppError("SendTeamExportCompletedEmail", "api.team.send_export_completed_email.failed.error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	return nil
+}
+
 func (a *App) SendInviteEmails(team *model.Team, senderName string, senderUserId string, invites []string, siteURL string) {
 	for _, invite := range invites {
 		if len(invite) > 0 {
@@ -206,7 +228,7 @@ func (a *App) SendInviteEmails(team *model.Team, senderName string, senderUserId
 			//props["name"] = team.Name
 			//data := model.MapToJson(props)
 
-			if err := a.Srv.Store.Token().Save(token); err != nil {
+			if err := a.Store().Token().Save(token); err != nil {
 				mlog.Error("Failed to send invite email successfully ", mlog.Err(err))
 				continue
 			}
@@ -74,6 +74,8 @@ func (job *EmailBatchingJob) emailBatchJob(inboxInterval string) {
 		return
 	}
 
+	defer job.server.recordJobRun("email_batching_"+inboxInterval, time.Now(), nil)
+
 	lastDoneUserId := strings.Repeat("0", 26)
 
 	for {
@@ -92,7 +94,8 @@ func (job *EmailBatchingJob) emailBatchJob(inboxInterval string) {
 		mlog.Info("email batch job last userId: ", mlog.String("userId", lastDoneUserId))
 
 		for _, user := range users {
-			if user.DeleteAt != 0 || user.Email == "" || !user.EmailVerified {
+			// botのメールアドレスは実在しないので送らない
+			if user.DeleteAt != 0 || user.Email == "" || !user.EmailVerified || user.IsBot() {
 				continue
 			}
 
@@ -112,7 +115,7 @@ func (job *EmailBatchingJob) emailBatchJob(inboxInterval string) {
 
 			var messages []*model.InboxMessage
 			// TODO: teamを考慮
-			if messages, err = job.server.Store.InboxMessage().GetInboxMessages(minDate, user.Id, ">", 0, 10, ""); err != nil {
+			if messages, err = job.server.Store.InboxMessage().GetInboxMessages(minDate, user.Id, ">", 0, 10, "", nil); err != nil {
 				continue
 			}
 			if len(messages) <= 0 {
@@ -154,6 +157,20 @@ func (job *EmailBatchingJob) startJobs() {
 	}
 }
 
+// EmailBatchJobSettingsの変更を反映する。
+// 外部から間隔を指定して1回だけ実行する場合は、次の実行時に今の設定が使われる
+func (job *EmailBatchingJob) rescheduleJobs() {
+	if !job.useCron() {
+		return
+	}
+
+	gocron.Clear()
+
+	if err := job.scheduleJobs(); err != nil {
+		mlog.Info("Email batching is disabled", mlog.Err(err))
+	}
+}
+
 func (job *EmailBatchingJob) StopJobs() {
 	if job.useCron() {
 		gocron.Clear()
@@ -0,0 +1,671 @@
+package app
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/clear-ness/qa-discussion/mlog"
+	"github.com/clear-ness/qa-discussion/model"
+)
+
+const (
+	EXPORT_BATCH_SIZE        = 200
+	EXPORT_LINK_EXPIRE_HOURS = 24
+	EXPORT_FILE_DIR          = "exports"
+)
+
+// 添付ファイルはJSONLを書き終えてからzipに追加する
+type exportAttachment struct {
+	zipPath  string
+	filePath string
+}
+
+type teamExporter struct {
+	app     *App
+	team    *model.Team
+	encoder *json.Encoder
+
+	// bestIdsは質問id → 採用された回答id。回答ごとに質問を読み直さずに済むよう、質問の書き出し時に控える
+	usernames   map[string]string
+	postIds     map[string]bool
+	bestIds     map[string]string
+	attachments []exportAttachment
+}
+
+// エクスポートを非同期で開始する。完了すると依頼者にダウンロードリンクがメールで届く
+func (a *App) CreateTeamExportJob(teamId string, userId string) (*model.Job, *model.AppError) {
+	// ダウンロードリンクはS3の署名付きURLで送るため、ファイルストアが無ければ始めない
+	if !a.FileBackend().IsConfigured() {
+		return nil, model.NewAppError("CreateTeamExportJob", "app.export.file_storage.not_configured.app_error", nil, "", http.StatusNotImplemented)
+	}
+
+	team, err := a.GetTeam(teamId)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := a.createTeamJob(model.JOB_TYPE_EXPORT_TEAM, team.Id, userId, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	background := a.Detached()
+	a.Srv.Go(func() {
+		background.runTeamExportJob(job, team)
+	})
+
+	return job, nil
+}
+
+func (a *App) runTeamExportJob(job *model.Job, team *model.Team) {
+	if err := a.setJobInProgress(job); err != nil {
+		a.setJobError(job, err)
+		return
+	}
+
+	path, err := a.exportTeamToFile(job, team)
+	if err != nil {
+		a.setJobError(job, err)
+		return
+	}
+
+	job.Data[model.JOB_DATA_EXPORT_PATH] = path
+	a.setJobSuccess(job)
+
+	if err := a.sendTeamExportLink(job, team, path); err != nil {
+		mlog.Error("Failed to send team export email", mlog.String("job_id", job.Id), mlog.Err(err))
+	}
+}
+
+func (a *App) sendTeamExportLink(job *model.Job, team *model.Team, path string) *model.AppError {
+	user, err := a.GetUser(job.UserId)
+	if err != nil {
+		return err
+	}
+
+	link, err := a.GetPresignedFileURL(path, EXPORT_LINK_EXPIRE_HOURS*time.Hour)
+	if err != nil {
+		return err
+	}
+
+	return a.SendTeamExportCompletedEmail(user.Email, team, link, EXPORT_LINK_EXPIRE_HOURS)
+}
+
+// 一時ファイルにzipを書き出してからファイルストアへアップロードし、そのパスを返す
+func (a *App) exportTeamToFile(job *model.Job, team *model.Team) (string, *model.AppError) {
+	file, tmpErr := ioutil.TempFile("", "team_export")
+	if tmpErr != nil {
+		return "", model.NewAppError("exportTeamToFile", "app.export.temp_file.app_error", nil, tmpErr.Error(), http.StatusInternalServerError)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	// アップロード分を残しておくため、100%にはしない
+	err := a.writeTeamExport(file, team, func(done int, total int) {
+		a.setJobProgress(job, int64(done*90/total))
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", model.NewAppError("exportTeamToFile", "app.export.temp_file.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	path := EXPORT_FILE_DIR + "/" + team.Id + "/" + job.Id + ".zip"
+	if err := a.WriteFile(file, path); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
+
+// チームの内容をインポートと同じ形式のzipとしてwに書き出す
+func (a *App) writeTeamExport(w io.Writer, team *model.Team, progress func(done int, total int)) *model.AppError {
+	zipWriter := zip.NewWriter(w)
+
+	dataWriter, zipErr := zipWriter.Create(IMPORT_DATA_FILE_NAME)
+	if zipErr != nil {
+		return model.NewAppError("writeTeamExport", "app.export.zip.app_error", nil, zipErr.Error(), http.StatusInternalServerError)
+	}
+
+	exporter := &teamExporter{
+		app:       a,
+		team:      team,
+		encoder:   json.NewEncoder(dataWriter),
+		usernames: make(map[string]string),
+		postIds:   make(map[string]bool),
+		bestIds:   make(map[string]string),
+	}
+
+	steps := []func() *model.AppError{
+		exporter.exportVersionAndTeam,
+		exporter.exportUsers,
+		exporter.exportGroups,
+		exporter.exportTags,
+		exporter.exportQuestions,
+		exporter.exportAnswers,
+		exporter.exportComments,
+		exporter.exportRevisions,
+		exporter.exportVotes,
+		exporter.exportFavorites,
+		exporter.exportCollections,
+		func() *model.AppError { return exporter.writeAttachments(zipWriter) },
+	}
+
+	for i, step := range steps {
+		if err := step(); err != nil {
+			return err
+		}
+
+		progress(i+1, len(steps))
+	}
+
+	if err := zipWriter.Close(); err != nil {
+		return model.NewAppError("writeTeamExport", "app.export.zip.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	return nil
+}
+
+func (e *teamExporter) writeLine(line *LineImportData) *model.AppError {
+	if err := e.encoder.Encode(line); err != nil {
+		return model.NewAppError("teamExporter.writeLine", "app.export.write_line.app_error", nil, "type="+line.Type+", "+err.Error(), http.StatusInternalServerError)
+	}
+
+	return nil
+}
+
+func (e *teamExporter) exportVersionAndTeam() *model.AppError {
+	version := IMPORT_DATA_FILE_VERSION
+	if err := e.writeLine(&LineImportData{Type: IMPORT_LINE_TYPE_VERSION, Version: &version}); err != nil {
+		return err
+	}
+
+	return e.writeLine(&LineImportData{
+		Type: IMPORT_LINE_TYPE_TEAM,
+		Team: &TeamImportData{
+			Name:           model.NewString(e.team.Name),
+			Type:           model.NewString(e.team.Type),
+			Description:    model.NewString(e.team.Description),
+			Email:          model.NewString(e.team.Email),
+			AllowedDomains: model.NewString(e.team.AllowedDomains),
+		},
+	})
+}
+
+func (e *teamExporter) exportUsers() *model.AppError {
+	lastUserId := strings.Repeat("0", 26)
+
+	for {
+		users, err := e.app.Srv.Store.User().GetTeamUsersForExport(e.team.Id, lastUserId, EXPORT_BATCH_SIZE)
+		if err != nil {
+			return err
+		}
+
+		if len(users) == 0 {
+			return nil
+		}
+
+		userIds := make([]string, 0, len(users))
+		for _, user := range users {
+			userIds = append(userIds, user.Id)
+		}
+
+		members, err := e.app.Srv.Store.Team().GetMembersByIds(e.team.Id, userIds)
+		if err != nil {
+			return err
+		}
+
+		memberTypes := make(map[string]string, len(members))
+		for _, member := range members {
+			memberTypes[member.UserId] = member.Type
+		}
+
+		for _, user := range users {
+			data := &UserImportData{
+				Username:      model.NewString(user.Username),
+				Email:         model.NewString(user.Email),
+				EmailVerified: model.NewBool(user.EmailVerified),
+				CreateAt:      model.NewInt64(user.CreateAt),
+				DeleteAt:      model.NewInt64(user.DeleteAt),
+			}
+
+			if len(user.AuthService) > 0 {
+				data.AuthService = model.NewString(user.AuthService)
+				data.AuthData = user.AuthData
+			}
+
+			if memberType, ok := memberTypes[user.Id]; ok {
+				data.TeamMemberType = model.NewString(memberType)
+			}
+
+			if err := e.writeLine(&LineImportData{Type: IMPORT_LINE_TYPE_USER, User: data}); err != nil {
+				return err
+			}
+
+			e.usernames[user.Id] = user.Username
+		}
+
+		lastUserId = users[len(users)-1].Id
+	}
+}
+
+func (e *teamExporter) exportGroups() *model.AppError {
+	groups, err := e.app.Srv.Store.UserGroup().GetTeamGroups(e.team.Id)
+	if err != nil {
+		return err
+	}
+
+	for _, group := range *groups {
+		if group.DeleteAt > 0 {
+			continue
+		}
+
+		owner, ok := e.usernames[group.UserId]
+		if !ok {
+			mlog.Warn("Skipping group with unknown owner on export", mlog.String("group_id", group.Id))
+			continue
+		}
+
+		members := []GroupMemberImportData{}
+		for offset := 0; ; offset += EXPORT_BATCH_SIZE {
+			page, err := e.app.Srv.Store.UserGroup().GetMembers(group.Id, "", offset, EXPORT_BATCH_SIZE, nil)
+			if err != nil {
+				return err
+			}
+
+			for _, member := range *page {
+				if username, ok := e.usernames[member.UserId]; ok {
+					members = append(members, GroupMemberImportData{
+						User: model.NewString(username),
+						Type: model.NewString(member.Type),
+					})
+				}
+			}
+
+			if len(*page) < EXPORT_BATCH_SIZE {
+				break
+			}
+		}
+
+		if err := e.writeLine(&LineImportData{
+			Type: IMPORT_LINE_TYPE_GROUP,
+			Group: &GroupImportData{
+				Name:        model.NewString(group.Name),
+				Type:        model.NewString(group.Type),
+				Description: model.NewString(group.Description),
+				User:        model.NewString(owner),
+				CreateAt:    model.NewInt64(group.CreateAt),
+				Members:     &members,
+			},
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e *teamExporter) exportTags() *model.AppError {
+	for page := 0; ; page++ {
+		tags, err := e.app.Srv.Store.Tag().GetTags(&model.GetTagsOptions{
+			TeamId:   e.team.Id,
+			SortType: model.POST_SORT_TYPE_NAME,
+			Page:     page,
+			PerPage:  EXPORT_BATCH_SIZE,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, tag := range tags {
+			if err := e.writeLine(&LineImportData{
+				Type: IMPORT_LINE_TYPE_TAG,
+				Tag: &TagImportData{
+					Content:  model.NewString(tag.Content),
+					CreateAt: model.NewInt64(tag.CreateAt),
+				},
+			}); err != nil {
+				return err
+			}
+		}
+
+		if len(tags) < EXPORT_BATCH_SIZE {
+			return nil
+		}
+	}
+}
+
+// 投稿の種類ごとにId順で読み出し、1件ずつ書き出す
+func (e *teamExporter) exportPosts(postType string, write func(post *model.Post, username string, attachments *[]AttachmentImportData) *model.AppError) *model.AppError {
+	lastPostId := strings.Repeat("0", 26)
+
+	for {
+		posts, err := e.app.Srv.Store.Post().GetPostsForExport(e.team.Id, postType, lastPostId, EXPORT_BATCH_SIZE)
+		if err != nil {
+			return err
+		}
+
+		if len(posts) == 0 {
+			return nil
+		}
+
+		attachments := map[string][]AttachmentImportData{}
+		if postType != model.POST_TYPE_COMMENT {
+			postIds := make([]string, 0, len(posts))
+			for _, post := range posts {
+				postIds = append(postIds, post.Id)
+			}
+
+			infos, err := e.app.Srv.Store.FileInfo().GetForPosts(postIds)
+			if err != nil {
+				return err
+			}
+
+			for _, info := range infos {
+				zipPath := IMPORT_ATTACHMENTS_DIR + info.Id + "/" + info.Name
+				e.attachments = append(e.attachments, exportAttachment{zipPath: zipPath, filePath: info.Path})
+				attachments[info.PostId] = append(attachments[info.PostId], AttachmentImportData{
+					Path: model.NewString(zipPath),
+					Name: model.NewString(info.Name),
+				})
+			}
+		}
+
+		for _, post := range posts {
+			username, ok := e.usernames[post.UserId]
+			if !ok {
+				mlog.Warn("Skipping post with unknown author on export", mlog.String("post_id", post.Id))
+				continue
+			}
+
+			// 親が書き出されていない投稿は取り込めないため除外する
+			if postType != model.POST_TYPE_QUESTION && !e.postIds[post.ParentId] {
+				continue
+			}
+
+			var postAttachments *[]AttachmentImportData
+			if files, ok := attachments[post.Id]; ok {
+				postAttachments = &files
+			}
+
+			if err := write(post, username, postAttachments); err != nil {
+				return err
+			}
+
+			e.postIds[post.Id] = true
+		}
+
+		lastPostId = posts[len(posts)-1].Id
+	}
+}
+
+func postPropsForExport(post *model.Post) *model.StringInterface {
+	if len(post.Props) == 0 {
+		return nil
+	}
+
+	return &post.Props
+}
+
+func optionalMillis(millis int64) *int64 {
+	if millis == 0 {
+		return nil
+	}
+
+	return model.NewInt64(millis)
+}
+
+func (e *teamExporter) exportQuestions() *model.AppError {
+	return e.exportPosts(model.POST_TYPE_QUESTION, func(post *model.Post, username string, attachments *[]AttachmentImportData) *model.AppError {
+		e.bestIds[post.Id] = post.BestId
+
+		return e.writeLine(&LineImportData{
+			Type: IMPORT_LINE_TYPE_QUESTION,
+			Question: &QuestionImportData{
+				Id:          model.NewString(post.Id),
+				User:        model.NewString(username),
+				Title:       model.NewString(post.Title),
+				Content:     model.NewString(post.Content),
+				Tags:        model.NewString(post.Tags),
+				Props:       postPropsForExport(post),
+				CreateAt:    model.NewInt64(post.CreateAt),
+				EditAt:      optionalMillis(post.EditAt),
+				LockedAt:    optionalMillis(post.LockedAt),
+				ProtectedAt: optionalMillis(post.ProtectedAt),
+				Attachments: attachments,
+			},
+		})
+	})
+}
+
+func (e *teamExporter) exportAnswers() *model.AppError {
+	return e.exportPosts(model.POST_TYPE_ANSWER, func(post *model.Post, username string, attachments *[]AttachmentImportData) *model.AppError {
+		return e.writeLine(&LineImportData{
+			Type: IMPORT_LINE_TYPE_ANSWER,
+			Answer: &AnswerImportData{
+				Id:          model.NewString(post.Id),
+				Question:    model.NewString(post.ParentId),
+				User:        model.NewString(username),
+				Content:     model.NewString(post.Content),
+				IsBest:      model.NewBool(e.bestIds[post.ParentId] == post.Id),
+				Props:       postPropsForExport(post),
+				CreateAt:    model.NewInt64(post.CreateAt),
+				EditAt:      optionalMillis(post.EditAt),
+				Attachments: attachments,
+			},
+		})
+	})
+}
+
+func (e *teamExporter) exportComments() *model.AppError {
+	return e.exportPosts(model.POST_TYPE_COMMENT, func(post *model.Post, username string, attachments *[]AttachmentImportData) *model.AppError {
+		return e.writeLine(&LineImportData{
+			Type: IMPORT_LINE_TYPE_COMMENT,
+			Comment: &CommentImportData{
+				Id:       model.NewString(post.Id),
+				Parent:   model.NewString(post.ParentId),
+				User:     model.NewString(username),
+				Content:  model.NewString(post.Content),
+				CreateAt: model.NewInt64(post.CreateAt),
+				EditAt:   optionalMillis(post.EditAt),
+			},
+		})
+	})
+}
+
+func (e *teamExporter) exportRevisions() *model.AppError {
+	lastPostId := strings.Repeat("0", 26)
+
+	for {
+		revisions, err := e.app.Srv.Store.Post().GetRevisionsForExport(e.team.Id, lastPostId, EXPORT_BATCH_SIZE)
+		if err != nil {
+			return err
+		}
+
+		if len(revisions) == 0 {
+			return nil
+		}
+
+		for _, revision := range revisions {
+			username, ok := e.usernames[revision.UserId]
+			if !ok || !e.postIds[revision.OriginalId] {
+				continue
+			}
+
+			data := &RevisionImportData{
+				Post:      model.NewString(revision.OriginalId),
+				User:      model.NewString(username),
+				Content:   model.NewString(revision.Content),
+				CreateAt:  model.NewInt64(revision.CreateAt),
+				EditAt:    optionalMillis(revision.EditAt),
+				RevisedAt: model.NewInt64(revision.DeleteAt),
+			}
+
+			if revision.Type == model.POST_TYPE_QUESTION {
+				data.Title = model.NewString(revision.Title)
+				data.Tags = model.NewString(revision.Tags)
+			}
+
+			if err := e.writeLine(&LineImportData{Type: IMPORT_LINE_TYPE_REVISION, Revision: data}); err != nil {
+				return err
+			}
+		}
+
+		lastPostId = revisions[len(revisions)-1].Id
+	}
+}
+
+func (e *teamExporter) exportVotes() *model.AppError {
+	for offset := 0; ; offset += EXPORT_BATCH_SIZE {
+		votes, err := e.app.Srv.Store.Vote().GetVotesForExport(e.team.Id, offset, EXPORT_BATCH_SIZE)
+		if err != nil {
+			return err
+		}
+
+		for _, vote := range votes {
+			username, ok := e.usernames[vote.UserId]
+			if !ok || !e.postIds[vote.PostId] {
+				continue
+			}
+
+			if err := e.writeLine(&LineImportData{
+				Type: IMPORT_LINE_TYPE_VOTE,
+				Vote: &VoteImportData{
+					Post:     model.NewString(vote.PostId),
+					User:     model.NewString(username),
+					Type:     model.NewString(vote.Type),
+					CreateAt: model.NewInt64(vote.CreateAt),
+				},
+			}); err != nil {
+				return err
+			}
+		}
+
+		if len(votes) < EXPORT_BATCH_SIZE {
+			return nil
+		}
+	}
+}
+
+func (e *teamExporter) exportFavorites() *model.AppError {
+	for offset := 0; ; offset += EXPORT_BATCH_SIZE {
+		favorites, err := e.app.Srv.Store.UserFavoritePost().GetForExport(e.team.Id, offset, EXPORT_BATCH_SIZE)
+		if err != nil {
+			return err
+		}
+
+		for _, favorite := range favorites {
+			username, ok := e.usernames[favorite.UserId]
+			if !ok || !e.postIds[favorite.PostId] {
+				continue
+			}
+
+			if err := e.writeLine(&LineImportData{
+				Type: IMPORT_LINE_TYPE_FAVORITE,
+				Favorite: &FavoriteImportData{
+					Post:     model.NewString(favorite.PostId),
+					User:     model.NewString(username),
+					CreateAt: model.NewInt64(favorite.CreateAt),
+				},
+			}); err != nil {
+				return err
+			}
+		}
+
+		if len(favorites) < EXPORT_BATCH_SIZE {
+			return nil
+		}
+	}
+}
+
+func (e *teamExporter) exportCollections() *model.AppError {
+	collections, err := e.app.Srv.Store.Collection().GetTeamCollections(e.team.Id)
+	if err != nil {
+		if err.StatusCode == http.StatusNotFound {
+			return nil
+		}
+		return err
+	}
+
+	for _, collection := range *collections {
+		if collection.DeleteAt > 0 {
+			continue
+		}
+
+		owner, ok := e.usernames[collection.UserId]
+		if !ok {
+			mlog.Warn("Skipping collection with unknown owner on export", mlog.String("collection_id", collection.Id))
+			continue
+		}
+
+		postIds := []string{}
+		for offset := 0; ; offset += EXPORT_BATCH_SIZE {
+			colPosts, err := e.app.Srv.Store.Collection().GetPosts(collection.Id, offset, EXPORT_BATCH_SIZE)
+			if err != nil {
+				return err
+			}
+
+			for _, colPost := range *colPosts {
+				if e.postIds[colPost.PostId] {
+					postIds = append(postIds, colPost.PostId)
+				}
+			}
+
+			if len(*colPosts) < EXPORT_BATCH_SIZE {
+				break
+			}
+		}
+
+		if err := e.writeLine(&LineImportData{
+			Type: IMPORT_LINE_TYPE_COLLECTION,
+			Collection: &CollectionImportData{
+				Title:       model.NewString(collection.Title),
+				Description: model.NewString(collection.Description),
+				User:        model.NewString(owner),
+				CreateAt:    model.NewInt64(collection.CreateAt),
+				Posts:       &postIds,
+			},
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e *teamExporter) writeAttachments(zipWriter *zip.Writer) *model.AppError {
+	for _, attachment := range e.attachments {
+		if err := e.writeAttachment(zipWriter, attachment); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e *teamExporter) writeAttachment(zipWriter *zip.Writer, attachment exportAttachment) *model.AppError {
+	reader, err := e.app.FileReader(attachment.filePath)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	writer, zipErr := zipWriter.Create(attachment.zipPath)
+	if zipErr != nil {
+		return model.NewAppError("teamExporter.writeAttachment", "app.export.zip.app_error", nil, zipErr.Error(), http.StatusInternalServerError)
+	}
+
+	if _, copyErr := io.Copy(writer, reader); copyErr != nil {
+		return model.NewAppError("teamExporter.writeAttachment", "app.export.write_attachment.app_error", nil, "path="+attachment.filePath+", "+copyErr.Error(), http.StatusInternalServerError)
+	}
+
+	return nil
+}
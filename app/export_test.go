@@ -0,0 +1,242 @@
+package app
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/clear-ness/qa-discussion/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTeamExportRoundTrip(t *testing.T) {
+	th := Setup(t)
+	defer th.TearDown()
+
+	owner := th.CreateUser(t)
+	member := th.CreateUser(t)
+	team := th.CreateTeam(t, owner)
+	_, err := th.Store.Team().SaveMember(&model.TeamMember{
+		TeamId: team.Id,
+		UserId: member.Id,
+		Type:   model.TEAM_MEMBER_TYPE_NORMAL,
+	}, -1)
+	require.Nil(t, err)
+
+	question, err := th.Store.Post().SaveQuestion(&model.Post{
+		Type:    model.POST_TYPE_QUESTION,
+		UserId:  owner.Id,
+		TeamId:  team.Id,
+		Title:   "How to export a team",
+		Content: "Is there a way to move a team?",
+		Tags:    "export import",
+	})
+	require.Nil(t, err)
+
+	best := th.saveAnswer(t, question, member.Id, "Use the team export")
+	other := th.saveAnswer(t, question, owner.Id, "Copy the database")
+	require.Nil(t, th.Store.Post().SelectBestAnswer(question.Id, best.Id))
+
+	_, err = th.Store.Post().SaveComment(&model.Post{
+		Type:     model.POST_TYPE_COMMENT,
+		UserId:   member.Id,
+		TeamId:   team.Id,
+		ParentId: question.Id,
+		RootId:   question.Id,
+		Content:  "Which version?",
+	})
+	require.Nil(t, err)
+
+	_, err = th.Store.Post().UpVotePost(question.Id, member.Id)
+	require.Nil(t, err)
+	require.Nil(t, th.Store.UserFavoritePost().Save(question.Id, member.Id, team.Id))
+
+	// 他のチームの投稿は含まない
+	otherTeam := th.CreateTeam(t, owner)
+	_, err = th.Store.Post().SaveQuestion(&model.Post{
+		Type:    model.POST_TYPE_QUESTION,
+		UserId:  owner.Id,
+		TeamId:  otherTeam.Id,
+		Title:   "Another team",
+		Content: "Not exported",
+	})
+	require.Nil(t, err)
+
+	dir, tempErr := ioutil.TempDir("", "export")
+	require.NoError(t, tempErr)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "export.zip")
+	file, tempErr := os.Create(path)
+	require.NoError(t, tempErr)
+
+	progress := 0
+	err = th.App.writeTeamExport(file, team, func(done int, total int) {
+		assert.True(t, done <= total)
+		progress = done
+	})
+	require.NoError(t, file.Close())
+	require.Nil(t, err)
+	assert.NotZero(t, progress)
+
+	lines := readExportLines(t, path)
+
+	t.Run("counts", func(t *testing.T) {
+		counts := map[string]int{}
+		for _, line := range lines {
+			counts[line.Type]++
+		}
+
+		assert.Equal(t, map[string]int{
+			IMPORT_LINE_TYPE_VERSION:  1,
+			IMPORT_LINE_TYPE_TEAM:     1,
+			IMPORT_LINE_TYPE_USER:     2,
+			IMPORT_LINE_TYPE_TAG:      2,
+			IMPORT_LINE_TYPE_QUESTION: 1,
+			IMPORT_LINE_TYPE_ANSWER:   2,
+			IMPORT_LINE_TYPE_COMMENT:  1,
+			IMPORT_LINE_TYPE_VOTE:     1,
+			IMPORT_LINE_TYPE_FAVORITE: 1,
+		}, counts)
+	})
+
+	t.Run("contents", func(t *testing.T) {
+		assert.Equal(t, IMPORT_DATA_FILE_VERSION, *lines[0].Version)
+		assert.Equal(t, team.Name, *lines[1].Team.Name)
+
+		memberTypes := map[string]string{}
+		isBest := map[string]bool{}
+		for _, line := range lines {
+			switch line.Type {
+			case IMPORT_LINE_TYPE_USER:
+				memberTypes[*line.User.Username] = *line.User.TeamMemberType
+			case IMPORT_LINE_TYPE_QUESTION:
+				assert.Equal(t, question.Id, *line.Question.Id)
+				assert.Equal(t, owner.Username, *line.Question.User)
+				assert.Equal(t, question.Title, *line.Question.Title)
+				assert.Equal(t, question.Content, *line.Question.Content)
+				assert.Equal(t, question.Tags, *line.Question.Tags)
+			case IMPORT_LINE_TYPE_ANSWER:
+				assert.Equal(t, question.Id, *line.Answer.Question)
+				isBest[*line.Answer.Id] = *line.Answer.IsBest
+			case IMPORT_LINE_TYPE_COMMENT:
+				assert.Equal(t, question.Id, *line.Comment.Parent)
+				assert.Equal(t, member.Username, *line.Comment.User)
+			case IMPORT_LINE_TYPE_VOTE:
+				assert.Equal(t, model.VOTE_TYPE_UP_VOTE, *line.Vote.Type)
+				assert.Equal(t, member.Username, *line.Vote.User)
+			}
+		}
+
+		assert.Equal(t, map[string]string{
+			owner.Username:  model.TEAM_MEMBER_TYPE_ADMIN,
+			member.Username: model.TEAM_MEMBER_TYPE_NORMAL,
+		}, memberTypes)
+		assert.Equal(t, map[string]bool{best.Id: true, other.Id: false}, isBest)
+	})
+
+	t.Run("dry-run import", func(t *testing.T) {
+		target := th.CreateTeam(t, th.CreateUser(t))
+		th.AddTeamMember(t, target, owner)
+		th.AddTeamMember(t, target, member)
+
+		report, err := th.App.importTeamFromZip(target, path, true, false)
+		require.Nil(t, err)
+		assert.True(t, report.DryRun)
+		assert.Equal(t, len(lines), report.LineCount)
+		assert.Equal(t, 0, report.ErrorCount, report.Errors)
+		// 書き出し元のチームの管理者は既存のメンバーなので、管理者にはしない
+		assert.Len(t, report.Warnings, 1)
+
+		// 実際には書き込まない
+		assert.Equal(t, int64(0), th.countPosts(t, model.POST_TYPE_QUESTION, owner.Id, target.Id))
+	})
+
+	t.Run("import", func(t *testing.T) {
+		target := th.CreateTeam(t, th.CreateUser(t))
+		th.AddTeamMember(t, target, owner)
+		th.AddTeamMember(t, target, member)
+
+		report, err := th.App.importTeamFromZip(target, path, false, false)
+		require.Nil(t, err)
+		assert.Equal(t, 0, report.ErrorCount, report.Errors)
+		assert.Len(t, report.Warnings, 1)
+
+		posts, _, err := th.Store.Post().GetPosts(&model.GetPostsOptions{
+			PostType: model.POST_TYPE_QUESTION,
+			TeamId:   target.Id,
+			SortType: model.POST_SORT_TYPE_CREATION,
+			PerPage:  10,
+		}, false)
+		require.Nil(t, err)
+		require.Len(t, posts, 1)
+
+		imported, err := th.Store.Post().GetSingle(posts[0].Id, false)
+		require.Nil(t, err)
+		assert.Equal(t, question.Title, imported.Title)
+		assert.Equal(t, question.Content, imported.Content)
+		assert.Equal(t, question.Tags, imported.Tags)
+		assert.Equal(t, owner.Id, imported.UserId)
+		assert.Equal(t, 1, imported.UpVotes)
+
+		importedBest, err := th.Store.Post().GetSingle(imported.BestId, false)
+		require.Nil(t, err)
+		assert.Equal(t, best.Content, importedBest.Content)
+		assert.Equal(t, member.Id, importedBest.UserId)
+
+		assert.Equal(t, int64(2), th.countPosts(t, model.POST_TYPE_ANSWER, member.Id, target.Id)+th.countPosts(t, model.POST_TYPE_ANSWER, owner.Id, target.Id))
+		assert.Equal(t, int64(1), th.countPosts(t, model.POST_TYPE_COMMENT, member.Id, target.Id))
+
+		favorite, err := th.Store.UserFavoritePost().GetByPostIdForUser(member.Id, imported.Id)
+		require.Nil(t, err)
+		require.NotNil(t, favorite)
+	})
+}
+
+func (th *TestHelper) saveAnswer(tb testing.TB, question *model.Post, userId string, content string) *model.Post {
+	answer, err := th.Store.Post().SaveAnswer(&model.Post{
+		Type:     model.POST_TYPE_ANSWER,
+		UserId:   userId,
+		TeamId:   question.TeamId,
+		ParentId: question.Id,
+		RootId:   question.Id,
+		Content:  content,
+	})
+	require.Nil(tb, err)
+
+	return answer
+}
+
+func readExportLines(tb testing.TB, path string) []LineImportData {
+	zipReader, err := zip.OpenReader(path)
+	require.NoError(tb, err)
+	defer zipReader.Close()
+
+	var lines []LineImportData
+	for _, file := range zipReader.File {
+		if file.Name != IMPORT_DATA_FILE_NAME {
+			continue
+		}
+
+		reader, err := file.Open()
+		require.NoError(tb, err)
+		defer reader.Close()
+
+		scanner := bufio.NewScanner(reader)
+		for scanner.Scan() {
+			var line LineImportData
+			require.NoError(tb, json.Unmarshal(scanner.Bytes(), &line))
+			lines = append(lines, line)
+		}
+		require.NoError(tb, scanner.Err())
+	}
+	require.NotEmpty(tb, lines)
+
+	return lines
+}
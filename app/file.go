@@ -42,7 +42,7 @@ const (
 )
 
 func (a *App) FileBackend() *filesstore.S3FileBackend {
-	return filesstore.NewFileBackend(&a.Config().FileSettings)
+	return filesstore.NewFileBackend(&a.Config().FileSettings).WithContext(a.Context())
 }
 
 func (a *App) WriteFile(fr io.Reader, path string) *model.AppError {
@@ -60,6 +60,16 @@ func (a *App) RemoveFile(path string) *model.AppError {
 	return backend.RemoveFile(path)
 }
 
+func (a *App) FileReader(path string) (io.ReadCloser, *model.AppError) {
+	backend := a.FileBackend()
+	return backend.Reader(path)
+}
+
+func (a *App) GetPresignedFileURL(path string, expire time.Duration) (string, *model.AppError) {
+	backend := a.FileBackend()
+	return backend.PresignedURL(path, expire)
+}
+
 func getImageOrientation(input io.Reader) (int, error) {
 	exifData, err := exif.Decode(input)
 	if err != nil {
@@ -194,7 +204,7 @@ func (t *uploadFileTask) init(a *App) {
 	t.teeInput = io.TeeReader(t.limitedInput, t.buf)
 
 	t.writeFile = a.WriteFile
-	t.saveToDatabase = a.Srv.Store.FileInfo().Save
+	t.saveToDatabase = a.Store().FileInfo().Save
 }
 
 func (a *App) UploadFileX(name string, input io.Reader,
@@ -433,7 +443,7 @@ func (t *uploadFileTask) postprocessImage() {
 }
 
 func (a *App) GetFileInfo(fileId string) (*model.FileInfo, *model.AppError) {
-	info, err := a.Srv.Store.FileInfo().Get(fileId)
+	info, err := a.Store().FileInfo().Get(fileId)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,152 @@
+package app
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/clear-ness/qa-discussion/services/cache"
+	"github.com/clear-ness/qa-discussion/services/filesstore"
+	"github.com/clear-ness/qa-discussion/services/mail"
+	"github.com/clear-ness/qa-discussion/services/search"
+)
+
+// 1つの依存先が応答しなくてもreadinessの応答自体は遅らせない
+const HEALTH_CHECK_TIMEOUT = 3 * time.Second
+
+type healthCheck struct {
+	name string
+	// nilの場合は設定で無効になっている
+	check func(ctx context.Context) error
+}
+
+func (s *Server) healthChecks() []healthCheck {
+	cfg := s.Config()
+
+	checks := []healthCheck{}
+
+	// DBを使わないstore(memstore)の場合は接続が無い
+	conns := s.Store.GetAllConns()
+	if len(conns) > 0 {
+		// GetAllConnsはreplica達の後ろにmasterを入れて返す
+		master := conns[len(conns)-1]
+		checks = append(checks, healthCheck{name: model.HEALTH_CHECK_MYSQL_MASTER, check: master.Db.PingContext})
+		for i, replica := range conns[:len(conns)-1] {
+			checks = append(checks, healthCheck{
+				name:  fmt.Sprintf("%s_%d", model.HEALTH_CHECK_MYSQL_REPLICA, i),
+				check: replica.Db.PingContext,
+			})
+		}
+	}
+
+	checks = append(checks,
+		healthCheck{name: model.HEALTH_CHECK_REDIS_CACHE, check: func(ctx context.Context) error {
+			return cache.NewRedisBackend(&cfg.CacheSettings).WithContext(ctx).Ping()
+		}},
+		healthCheck{name: model.HEALTH_CHECK_REDIS_CLUSTER, check: s.Cluster.Ping},
+		healthCheck{name: model.HEALTH_CHECK_ELASTICSEARCH, check: func(ctx context.Context) error {
+			esBackend, err := search.NewESBackend(&cfg.SearchSettings, nil)
+			if err != nil {
+				return *err
+			}
+			return esBackend.WithContext(ctx).Ping()
+		}},
+	)
+
+	fileCheck := healthCheck{name: model.HEALTH_CHECK_FILE_BACKEND}
+	if *cfg.FileSettings.AmazonS3Bucket != "" {
+		fileCheck.check = func(ctx context.Context) error {
+			return filesstore.NewFileBackend(&cfg.FileSettings).WithContext(ctx).TestConnection()
+		}
+	}
+
+	mailCheck := healthCheck{name: model.HEALTH_CHECK_MAIL_BACKEND}
+	if *cfg.EmailSettings.AmazonSESAccessKeyId != "" {
+		mailCheck.check = mail.NewSesMailBackend(&cfg.EmailSettings).TestConnection
+	}
+
+	return append(checks, fileCheck, mailCheck)
+}
+
+// 全ての依存先を並列に確認する。無効なものを除いて全てokならreadyとする
+func (s *Server) CheckReadiness() *model.HealthStatus {
+	checks := s.healthChecks()
+	results := make([]*model.DependencyStatus, len(checks))
+
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		if c.check == nil {
+			results[i] = &model.DependencyStatus{Name: c.name, Status: model.HEALTH_STATUS_DISABLED}
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, c healthCheck) {
+			defer wg.Done()
+			results[i] = runHealthCheck(c)
+		}(i, c)
+	}
+	wg.Wait()
+
+	status := &model.HealthStatus{
+		Status:       model.HEALTH_STATUS_OK,
+		Dependencies: results,
+	}
+	for _, result := range results {
+		if result.Status == model.HEALTH_STATUS_FAIL {
+			status.Status = model.HEALTH_STATUS_FAIL
+			break
+		}
+	}
+
+	return status
+}
+
+func runHealthCheck(c healthCheck) *model.DependencyStatus {
+	ctx, cancel := context.WithTimeout(context.Background(), HEALTH_CHECK_TIMEOUT)
+	defer cancel()
+
+	result := &model.DependencyStatus{Name: c.name, Status: model.HEALTH_STATUS_OK}
+
+	start := time.Now()
+	err := c.check(ctx)
+	result.LatencyMs = time.Since(start).Milliseconds()
+
+	// タイムアウトを無視するclientもあるので、ctxの期限切れもエラーとする
+	if err == nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		result.Status = model.HEALTH_STATUS_FAIL
+		result.Error = err.Error()
+	}
+
+	return result
+}
+
+// 設定の中身は返さず、サーバー間で設定が揃っているかの比較に使う
+func (s *Server) ConfigHash() string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(s.Config().ToJson())))
+}
+
+func (a *App) CheckReadiness() *model.HealthStatus {
+	return a.Srv.CheckReadiness()
+}
+
+func (a *App) GetSystemStatus() *model.SystemStatus {
+	now := model.GetMillis()
+
+	return &model.SystemStatus{
+		Version:       model.CurrentVersion,
+		ClusterId:     a.Srv.clusterId,
+		ConfigHash:    a.Srv.ConfigHash(),
+		Goroutines:    runtime.NumGoroutine(),
+		StartAt:       a.Srv.startAt,
+		UptimeSeconds: (now - a.Srv.startAt) / 1000,
+		Health:        a.CheckReadiness(),
+	}
+}
@@ -0,0 +1,110 @@
+package app
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/clear-ness/qa-discussion/store/memstore"
+
+	"github.com/stretchr/testify/require"
+)
+
+type TestHelper struct {
+	App    *App
+	Server *Server
+	Store  *memstore.MemStore
+
+	tempDir string
+}
+
+// DB・Redis・ESなしで動かすため、memstoreを層で包まずに使い、定期処理も動かさない
+func Setup(tb testing.TB) *TestHelper {
+	tempDir, err := ioutil.TempDir("", "apptest")
+	require.NoError(tb, err)
+
+	memStore := memstore.New()
+
+	s, err := NewServer(
+		Config(filepath.Join(tempDir, "config.json"), false),
+		StoreOverride(memStore),
+		SkipStoreLayers(),
+		SkipBackgroundJobs(),
+	)
+	if err != nil {
+		os.RemoveAll(tempDir)
+		require.NoError(tb, err)
+	}
+
+	return &TestHelper{
+		App:     s.FakeApp(),
+		Server:  s,
+		Store:   memStore,
+		tempDir: tempDir,
+	}
+}
+
+func (th *TestHelper) TearDown() {
+	th.Server.Shutdown()
+	os.RemoveAll(th.tempDir)
+}
+
+func (th *TestHelper) UpdateConfig(f func(*model.Config)) {
+	th.Server.UpdateConfig(f)
+}
+
+func (th *TestHelper) CreateUser(tb testing.TB) *model.User {
+	id := model.NewId()
+
+	user, err := th.Store.User().Save(&model.User{
+		Type:          model.USER_TYPE_NORMAL,
+		Username:      "un" + id,
+		Email:         "success+" + id + "@simulator.amazonses.com",
+		EmailVerified: true,
+	})
+	require.Nil(tb, err)
+
+	return user
+}
+
+func (th *TestHelper) CreateTeam(tb testing.TB, owner *model.User) *model.Team {
+	team, err := th.Store.Team().Save(&model.Team{
+		Type:  model.TEAM_TYPE_PRIVATE,
+		Name:  "team-" + model.NewId(),
+		Email: owner.Email,
+	})
+	require.Nil(tb, err)
+
+	_, err = th.Store.Team().SaveMember(&model.TeamMember{
+		TeamId: team.Id,
+		UserId: owner.Id,
+		Type:   model.TEAM_MEMBER_TYPE_ADMIN,
+	}, *th.Server.Config().TeamSettings.MaxUsersPerTeam)
+	require.Nil(tb, err)
+
+	return team
+}
+
+func (th *TestHelper) AddTeamMember(tb testing.TB, team *model.Team, user *model.User) {
+	_, err := th.Store.Team().SaveMember(&model.TeamMember{
+		TeamId: team.Id,
+		UserId: user.Id,
+		Type:   model.TEAM_MEMBER_TYPE_NORMAL,
+	}, *th.Server.Config().TeamSettings.MaxUsersPerTeam)
+	require.Nil(tb, err)
+}
+
+func (th *TestHelper) CreateWebhook(tb testing.TB, team *model.Team, user *model.User, url string, events ...string) *model.Webhook {
+	hook, err := th.Store.Webhook().Save(&model.Webhook{
+		UserId:      user.Id,
+		TeamId:      team.Id,
+		Events:      events,
+		URLs:        []string{url},
+		ContentType: "application/json",
+	})
+	require.Nil(tb, err)
+
+	return hook
+}
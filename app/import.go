@@ -3,132 +3,223 @@ package app
 import (
 	"archive/zip"
 	"bufio"
-	"bytes"
+	"encoding/json"
 	"io"
-	"mime/multipart"
+	"io/ioutil"
 	"net/http"
+	"os"
+	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/clear-ness/qa-discussion/model"
 )
 
-func (a *App) SlackImport(fileData multipart.File, fileSize int64, teamID string) (*model.AppError, *bytes.Buffer) {
-	log := bytes.NewBufferString(utils.T("api.slackimport.slack_import.log"))
+const (
+	// 1行(添付を除く1投稿)の最大サイズ
+	maxScanTokenSize = 16 * 1024 * 1024
 
-	zipreader, err := zip.NewReader(fileData, fileSize)
-	if err != nil || zipreader.File == nil {
-		log.WriteString(utils.T("api.slackimport.slack_import.zip.app_error"))
-		return model.NewAppError("SlackImport", "api.slackimport.slack_import.zip.app_error", nil, err.Error(), http.StatusBadRequest), log
+	BULK_IMPORT_WORKERS = 4
+)
+
+// アップロードされたzipを一時ファイルに保存し、インポートを非同期で開始する。
+// dryRunの場合は検証のみ行い、結果はjobのレポートで確認できる。
+// allowAuthDataがfalseの場合、外部認証の情報を含むユーザーの行はエラーになる。
+func (a *App) CreateTeamImportJob(teamId string, userId string, file io.Reader, dryRun bool, allowAuthData bool) (*model.Job, *model.AppError) {
+	team, err := a.GetTeam(teamId)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpFile, tmpErr := ioutil.TempFile("", "team_import")
+	if tmpErr != nil {
+		return nil, model.NewAppError("CreateTeamImportJob", "app.import.temp_file.app_error", nil, tmpErr.Error(), http.StatusInternalServerError)
+	}
+
+	_, copyErr := io.Copy(tmpFile, file)
+	tmpFile.Close()
+	if copyErr != nil {
+		os.Remove(tmpFile.Name())
+		return nil, model.NewAppError("CreateTeamImportJob", "app.import.temp_file.app_error", nil, copyErr.Error(), http.StatusInternalServerError)
+	}
+
+	data := map[string]string{model.JOB_DATA_DRY_RUN: strconv.FormatBool(dryRun)}
+	job, err := a.createTeamJob(model.JOB_TYPE_IMPORT_TEAM, team.Id, userId, data)
+	if err != nil {
+		os.Remove(tmpFile.Name())
+		return nil, err
 	}
 
-	file := zipreader.File[0]
-	if file.UncompressedSize64 > slackImportMaxFileSize {
-		log.WriteString(utils.T("api.slackimport.slack_import.zip.file_too_large", map[string]interface{}{"Filename": file.Name}))
+	background := a.Detached()
+	a.Srv.Go(func() {
+		defer os.Remove(tmpFile.Name())
+		background.runTeamImportJob(job, team, tmpFile.Name(), dryRun, allowAuthData)
+	})
+
+	return job, nil
+}
+
+func (a *App) runTeamImportJob(job *model.Job, team *model.Team, path string, dryRun bool, allowAuthData bool) {
+	if err := a.setJobInProgress(job); err != nil {
+		a.setJobError(job, err)
 		return
 	}
 
-	reader, err := file.Open()
+	report, err := a.importTeamFromZip(team, path, dryRun, allowAuthData)
+	if report != nil {
+		job.Data[model.JOB_DATA_IMPORT_REPORT] = report.ToJson()
+	}
 
-	a.BulkImport(reader, false, 3)
+	if err != nil {
+		a.setJobError(job, err)
+		return
+	}
+
+	// 行単位のエラーはレポートに残し、job自体は成功とする
+	a.setJobSuccess(job)
+}
+
+func (a *App) importTeamFromZip(team *model.Team, path string, dryRun bool, allowAuthData bool) (*ImportReport, *model.AppError) {
+	zipReader, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, model.NewAppError("importTeamFromZip", "app.import.zip.app_error", nil, err.Error(), http.StatusBadRequest)
+	}
+	defer zipReader.Close()
+
+	var dataFile *zip.File
+	attachments := make(map[string]*zip.File)
+	for _, file := range zipReader.File {
+		if file.Name == IMPORT_DATA_FILE_NAME {
+			dataFile = file
+		} else if strings.HasPrefix(file.Name, IMPORT_ATTACHMENTS_DIR) {
+			attachments[file.Name] = file
+		}
+	}
+
+	if dataFile == nil {
+		return nil, model.NewAppError("importTeamFromZip", "app.import.zip.no_data_file.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	reader, err := dataFile.Open()
+	if err != nil {
+		return nil, model.NewAppError("importTeamFromZip", "app.import.zip.app_error", nil, err.Error(), http.StatusBadRequest)
+	}
+	defer reader.Close()
+
+	return a.BulkImport(reader, team, attachments, dryRun, allowAuthData, BULK_IMPORT_WORKERS)
 }
 
 // データアップロード
-func (a *App) BulkImport(fileReader io.Reader, dryRun bool, workers int) (*model.AppError, int) {
+// 行単位のエラーはレポートに記録して処理を続け、ファイル全体が読めない場合のみエラーを返す。
+func (a *App) BulkImport(fileReader io.Reader, team *model.Team, attachments map[string]*zip.File, dryRun bool, allowAuthData bool, workers int) (*ImportReport, *model.AppError) {
 	scanner := bufio.NewScanner(fileReader)
 	buf := make([]byte, 0, 64*1024)
 	scanner.Buffer(buf, maxScanTokenSize)
 
-	lineNumber := 0
+	report := &ImportReport{DryRun: dryRun, Errors: []ImportLineError{}, Warnings: []ImportLineError{}}
+	importer := newTeamImporter(a, team, attachments, report, allowAuthData)
 
-	//a.Srv().Store.LockToMaster()
-	//defer a.Srv().Store.UnlockFromMaster()
+	lineNumber := 0
 
 	errorsChan := make(chan LineImportWorkerError, (2*workers)+1) // size chosen to ensure it never gets filled up completely.
 	var wg sync.WaitGroup
 	var linesChan chan LineImportWorkerData
 	lastLineType := ""
 
+	// workerのエラーはレポートへ集約する
+	var collectorWg sync.WaitGroup
+	collectorWg.Add(1)
+	go func() {
+		defer collectorWg.Done()
+		for err := range errorsChan {
+			report.addError(err)
+		}
+	}()
+
+	finish := func() {
+		// No more lines. Clear out the worker queue before continuing.
+		if linesChan != nil {
+			close(linesChan)
+		}
+		wg.Wait()
+
+		close(errorsChan)
+		collectorWg.Wait()
+
+		report.LineCount = lineNumber
+	}
+
 	for scanner.Scan() {
-		decoder := json.NewDecoder(strings.NewReader(scanner.Text()))
 		lineNumber++
 
 		var line LineImportData
+		decoder := json.NewDecoder(strings.NewReader(scanner.Text()))
 		if err := decoder.Decode(&line); err != nil {
-			return model.NewAppError("BulkImport", "app.import.bulk_import.json_decode.error", nil, err.Error(), http.StatusBadRequest), lineNumber
+			appErr := model.NewAppError("BulkImport", "app.import.bulk_import.json_decode.error", nil, err.Error(), http.StatusBadRequest)
+			if lineNumber == 1 {
+				finish()
+				return report, appErr
+			}
+
+			errorsChan <- LineImportWorkerError{appErr, lineNumber}
+			continue
 		}
 
 		if lineNumber == 1 {
-			importDataFileVersion, appErr := processImportDataFileVersionLine(line)
-			if appErr != nil {
-				return appErr, lineNumber
+			if appErr := processImportDataFileVersionLine(line); appErr != nil {
+				finish()
+				return report, appErr
 			}
 
-			if importDataFileVersion != 1 {
-				return model.NewAppError("BulkImport", "app.import.bulk_import.unsupported_version.error", nil, "", http.StatusBadRequest), lineNumber
-			}
 			lastLineType = line.Type
 			continue
 		}
 
 		if line.Type != lastLineType {
-			// ここでif文+コメント書くのは勿体無い..
-			// Only clear the worker queue if is not the first data entry
-			if lineNumber != 2 {
-				// Changing type. Clear out the worker queue before continuing.
-				// これによりworker側のforループが止まり、wg.Done()が呼ばれる。
-				// 前回のTypeの処理をやめて、
+			// Changing type. Clear out the worker queue before continuing.
+			// これによりworker側のforループが止まり、wg.Done()が呼ばれる。
+			// 前のTypeの行が全て処理されてから次のTypeに進むため、参照先が先に作られる。
+			if linesChan != nil {
 				close(linesChan)
 				wg.Wait()
+			}
 
-				// Check no errors occurred while waiting for the queue to empty.
-				if len(errorsChan) != 0 {
-					err := <-errorsChan
-					if stopOnError(err) {
-						return err.Error, err.LineNumber
-					}
-				}
+			if stopOnError(report) {
+				finish()
+				return report, model.NewAppError("BulkImport", "app.import.bulk_import.too_many_errors.error", nil, "line="+strconv.Itoa(lineNumber), http.StatusBadRequest)
 			}
-			// Set up the workers and channel for this type.
-			lastLineType = line.Type
+
 			// 別のTypeを処理するループを開始しておく。
+			lastLineType = line.Type
 			linesChan = make(chan LineImportWorkerData, workers)
 			for i := 0; i < workers; i++ {
 				wg.Add(1)
-				go a.bulkImportWorker(dryRun, &wg, linesChan, errorsChan)
+				go importer.bulkImportWorker(&wg, linesChan, errorsChan)
 			}
 		}
 
-		select { // 1行の処理をループ側に任せるため。
-		// なお、1メッセージはランダムな1つだけのgoルーチンによって処理される。
-		// 複数のループが同一のメッセージを同時処理することは無い。
-		case linesChan <- LineImportWorkerData{line, lineNumber}:
-		// これはどうなる？
-		case err := <-errorsChan:
-			if stopOnError(err) {
-				close(linesChan)
-				wg.Wait()
-				return err.Error, err.LineNumber
-			}
+		// 1メッセージはいずれか1つのworkerだけが処理する。
+		linesChan <- LineImportWorkerData{line, lineNumber}
+
+		if stopOnError(report) {
+			finish()
+			return report, model.NewAppError("BulkImport", "app.import.bulk_import.too_many_errors.error", nil, "line="+strconv.Itoa(lineNumber), http.StatusBadRequest)
 		}
 	}
 
-	// No more lines. Clear out the worker queue before continuing.
-	if linesChan != nil {
-		close(linesChan)
-	}
-	wg.Wait()
+	finish()
 
-	// Check no errors occurred while waiting for the queue to empty.
-	if len(errorsChan) != 0 {
-		err := <-errorsChan
-		if stopOnError(err) {
-			return err.Error, err.LineNumber
-		}
+	if err := scanner.Err(); err != nil {
+		return report, model.NewAppError("BulkImport", "app.import.bulk_import.file_scan.error", nil, err.Error(), http.StatusInternalServerError)
 	}
 
-	if err := scanner.Err(); err != nil {
-		return model.NewAppError("BulkImport", "app.import.bulk_import.file_scan.error", nil, err.Error(), http.StatusInternalServerError), 0
+	if lineNumber == 0 {
+		return report, model.NewAppError("BulkImport", "app.import.bulk_import.empty_file.error", nil, "", http.StatusBadRequest)
 	}
 
-	return nil, 0
+	return report, nil
+}
+
+func stopOnError(report *ImportReport) bool {
+	return report.errorCount() >= IMPORT_MAX_LINE_ERRORS
 }
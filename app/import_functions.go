@@ -1 +1,742 @@
 package app
+
+import (
+	"archive/zip"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/clear-ness/qa-discussion/store"
+)
+
+// インポート済みの投稿。元データのidから引けるようにしておく
+type importedPost struct {
+	id       string
+	rootId   string
+	parentId string
+	postType string
+	userId   string
+}
+
+// 1回のインポートの間、username・元の投稿idとインポート先idの対応を保持する
+type teamImporter struct {
+	app         *App
+	team        *model.Team
+	dryRun      bool
+	attachments map[string]*zip.File
+	report      *ImportReport
+	// 外部認証の情報を書き込めるのはシステム管理者がインポートする場合のみ
+	allowAuthData bool
+
+	mutex sync.RWMutex
+	users map[string]string
+	posts map[string]*importedPost
+}
+
+func newTeamImporter(a *App, team *model.Team, attachments map[string]*zip.File, report *ImportReport, allowAuthData bool) *teamImporter {
+	return &teamImporter{
+		app:           a,
+		team:          team,
+		dryRun:        report.DryRun,
+		attachments:   attachments,
+		report:        report,
+		allowAuthData: allowAuthData,
+		users:         make(map[string]string),
+		posts:         make(map[string]*importedPost),
+	}
+}
+
+func processImportDataFileVersionLine(line LineImportData) *model.AppError {
+	if line.Type != IMPORT_LINE_TYPE_VERSION || line.Version == nil {
+		return model.NewAppError("BulkImport", "app.import.bulk_import.version_line.error", nil, "", http.StatusBadRequest)
+	}
+
+	if *line.Version != IMPORT_DATA_FILE_VERSION {
+		return model.NewAppError("BulkImport", "app.import.bulk_import.unsupported_version.error", nil, "", http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+func (ti *teamImporter) bulkImportWorker(wg *sync.WaitGroup, lines <-chan LineImportWorkerData, errors chan<- LineImportWorkerError) {
+	defer wg.Done()
+
+	for line := range lines {
+		if err := ti.importLine(line); err != nil {
+			errors <- LineImportWorkerError{err, line.LineNumber}
+		}
+	}
+}
+
+func (ti *teamImporter) importLine(line LineImportWorkerData) *model.AppError {
+	switch {
+	case line.Type == IMPORT_LINE_TYPE_TEAM && line.Team != nil:
+		return ti.importTeam(line.Team)
+	case line.Type == IMPORT_LINE_TYPE_USER && line.User != nil:
+		return ti.importUser(line.User, line.LineNumber)
+	case line.Type == IMPORT_LINE_TYPE_GROUP && line.Group != nil:
+		return ti.importGroup(line.Group)
+	case line.Type == IMPORT_LINE_TYPE_TAG && line.Tag != nil:
+		return ti.importTag(line.Tag)
+	case line.Type == IMPORT_LINE_TYPE_QUESTION && line.Question != nil:
+		return ti.importQuestion(line.Question)
+	case line.Type == IMPORT_LINE_TYPE_ANSWER && line.Answer != nil:
+		return ti.importAnswer(line.Answer)
+	case line.Type == IMPORT_LINE_TYPE_COMMENT && line.Comment != nil:
+		return ti.importComment(line.Comment)
+	case line.Type == IMPORT_LINE_TYPE_REVISION && line.Revision != nil:
+		return ti.importRevision(line.Revision)
+	case line.Type == IMPORT_LINE_TYPE_VOTE && line.Vote != nil:
+		return ti.importVote(line.Vote)
+	case line.Type == IMPORT_LINE_TYPE_FAVORITE && line.Favorite != nil:
+		return ti.importFavorite(line.Favorite)
+	case line.Type == IMPORT_LINE_TYPE_COLLECTION && line.Collection != nil:
+		return ti.importCollection(line.Collection)
+	default:
+		return model.NewAppError("BulkImport", "app.import.import_line.unknown_line_type.error", map[string]interface{}{"Type": line.Type}, "type="+line.Type, http.StatusBadRequest)
+	}
+}
+
+func (ti *teamImporter) setUser(username string, userId string) {
+	ti.mutex.Lock()
+	defer ti.mutex.Unlock()
+
+	ti.users[model.NormalizeUsername(username)] = userId
+}
+
+// ファイル内で定義されていないユーザーは、インポート先のチームのメンバーの場合のみ使う。
+// チーム外のユーザーをusernameで指定して投稿させることはできない
+func (ti *teamImporter) resolveUser(username string) (string, *model.AppError) {
+	username = model.NormalizeUsername(username)
+
+	ti.mutex.RLock()
+	userId, ok := ti.users[username]
+	ti.mutex.RUnlock()
+	if ok {
+		return userId, nil
+	}
+
+	user, err := ti.app.Srv.Store.User().GetByUsername(username)
+	if err != nil {
+		return "", model.NewAppError("BulkImport", "app.import.resolve_user.missing.error", nil, "username="+username, http.StatusBadRequest)
+	}
+
+	member, err := ti.teamMember(user.Id)
+	if err != nil {
+		return "", err
+	}
+	if member == nil {
+		return "", model.NewAppError("BulkImport", "app.import.resolve_user.missing.error", nil, "username="+username, http.StatusBadRequest)
+	}
+
+	ti.setUser(username, user.Id)
+	return user.Id, nil
+}
+
+// 退出済みのメンバーはメンバーとして扱わない
+func (ti *teamImporter) teamMember(userId string) (*model.TeamMember, *model.AppError) {
+	member, err := ti.app.Srv.Store.Team().GetMember(ti.team.Id, userId)
+	if err != nil {
+		if err.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if member.DeleteAt != 0 {
+		return nil, nil
+	}
+
+	return member, nil
+}
+
+func (ti *teamImporter) setPost(sourceId string, post *importedPost) {
+	ti.mutex.Lock()
+	defer ti.mutex.Unlock()
+
+	ti.posts[sourceId] = post
+}
+
+func (ti *teamImporter) resolvePost(sourceId string) (*importedPost, *model.AppError) {
+	ti.mutex.RLock()
+	defer ti.mutex.RUnlock()
+
+	post, ok := ti.posts[sourceId]
+	if !ok {
+		return nil, model.NewAppError("BulkImport", "app.import.resolve_post.missing.error", nil, "post="+sourceId, http.StatusBadRequest)
+	}
+
+	return post, nil
+}
+
+// チームはインポート先のものを使うため、内容の検証のみ行う
+func (ti *teamImporter) importTeam(data *TeamImportData) *model.AppError {
+	return validateTeamImportData(data)
+}
+
+func (ti *teamImporter) importUser(data *UserImportData, lineNumber int) *model.AppError {
+	if err := validateUserImportData(data); err != nil {
+		return err
+	}
+
+	// 他のアカウントのSSOのidを指定して乗っ取れないようにする
+	if (data.AuthService != nil || data.AuthData != nil) && !ti.allowAuthData {
+		return model.NewAppError("BulkImport", "app.import.import_user.auth_not_allowed.error", nil, "username="+*data.Username, http.StatusForbidden)
+	}
+
+	user, member, err := ti.findExistingUser(data)
+	if err != nil {
+		return err
+	}
+
+	// 既存のメンバーはファイルの内容だけで管理者にせず、今の種別のまま使う
+	isAdmin := data.TeamMemberType != nil && *data.TeamMemberType == model.TEAM_MEMBER_TYPE_ADMIN
+	if member != nil && isAdmin && member.Type != model.TEAM_MEMBER_TYPE_ADMIN {
+		ti.report.addWarning(LineImportWorkerError{
+			model.NewAppError("BulkImport", "app.import.import_user.existing_user_not_elevated.warning", nil, "username="+user.Username, http.StatusBadRequest),
+			lineNumber,
+		})
+	}
+
+	if ti.dryRun {
+		userId := model.NewId()
+		if user != nil {
+			userId = user.Id
+		}
+		ti.setUser(*data.Username, userId)
+		return nil
+	}
+
+	if user != nil {
+		ti.setUser(*data.Username, user.Id)
+		return nil
+	}
+
+	user = &model.User{
+		Type:     model.USER_TYPE_NORMAL,
+		Username: *data.Username,
+		Email:    *data.Email,
+	}
+	if data.EmailVerified != nil {
+		user.EmailVerified = *data.EmailVerified
+	}
+	if data.AuthService != nil {
+		user.AuthService = *data.AuthService
+		user.AuthData = data.AuthData
+	}
+	if data.DeleteAt != nil {
+		user.DeleteAt = *data.DeleteAt
+	}
+
+	if user, err = ti.app.Srv.Store.User().Save(user); err != nil {
+		return err
+	}
+
+	ti.setUser(*data.Username, user.Id)
+
+	if data.TeamMemberType != nil {
+		if err := ti.app.JoinUserToTeam(ti.team, user, isAdmin); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// 投稿の無いタグも作成日時とともに残す。投稿数は質問を取り込む時に数える
+func (ti *teamImporter) importTag(data *TagImportData) *model.AppError {
+	if err := validateTagImportData(data); err != nil {
+		return err
+	}
+
+	if ti.dryRun {
+		return nil
+	}
+
+	createAt := model.GetMillis()
+	if data.CreateAt != nil {
+		createAt = *data.CreateAt
+	}
+
+	for _, content := range strings.Fields(model.ParseTags(*data.Content)) {
+		if err := ti.app.Srv.Store.Tag().ImportTag(&model.Tag{Content: content, TeamId: ti.team.Id, CreateAt: createAt}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// メールアドレスか外部認証のidが一致するユーザーは作成せずにそのまま使う。
+// チームのメンバーでないユーザーをインポートでチームに加えることはできない
+func (ti *teamImporter) findExistingUser(data *UserImportData) (*model.User, *model.TeamMember, *model.AppError) {
+	user, err := ti.app.Srv.Store.User().GetByEmail(*data.Email)
+	if err != nil {
+		if err.Id != store.MISSING_ACCOUNT_ERROR {
+			return nil, nil, err
+		}
+		user = nil
+	}
+
+	if user == nil && data.AuthService != nil && data.AuthData != nil {
+		if found, err := ti.app.Srv.Store.User().GetByAuth(data.AuthData, *data.AuthService); err == nil {
+			user = found
+		}
+	}
+
+	if user == nil {
+		if _, err := ti.app.Srv.Store.User().GetByUsername(*data.Username); err == nil {
+			return nil, nil, model.NewAppError("BulkImport", "app.import.import_user.username_exists.error", nil, "username="+*data.Username, http.StatusBadRequest)
+		}
+		return nil, nil, nil
+	}
+
+	member, err := ti.teamMember(user.Id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if member == nil {
+		return nil, nil, model.NewAppError("BulkImport", "app.import.import_user.not_team_member.error", nil, "username="+*data.Username, http.StatusBadRequest)
+	}
+
+	return user, member, nil
+}
+
+func (ti *teamImporter) importGroup(data *GroupImportData) *model.AppError {
+	if err := validateGroupImportData(data); err != nil {
+		return err
+	}
+
+	userId, err := ti.resolveUser(*data.User)
+	if err != nil {
+		return err
+	}
+
+	memberIds := make(map[string]string)
+	if data.Members != nil {
+		for _, member := range *data.Members {
+			memberId, err := ti.resolveUser(*member.User)
+			if err != nil {
+				return err
+			}
+			memberIds[memberId] = *member.Type
+		}
+	}
+
+	if ti.dryRun {
+		return nil
+	}
+
+	group := &model.UserGroup{
+		Type:   *data.Type,
+		TeamId: ti.team.Id,
+		Name:   *data.Name,
+		UserId: userId,
+	}
+	if data.Description != nil {
+		group.Description = *data.Description
+	}
+
+	group, err = ti.app.Srv.Store.UserGroup().Save(group, *ti.app.Config().TeamSettings.MaxGroupsPerTeam)
+	if err != nil {
+		return err
+	}
+
+	if len(memberIds) == 0 {
+		return nil
+	}
+
+	members := make([]*model.GroupMember, 0, len(memberIds))
+	for memberId, memberType := range memberIds {
+		members = append(members, &model.GroupMember{GroupId: group.Id, UserId: memberId, Type: memberType})
+	}
+
+	_, err = ti.app.Srv.Store.UserGroup().SaveMultipleMembers(members)
+	return err
+}
+
+func (ti *teamImporter) importQuestion(data *QuestionImportData) *model.AppError {
+	if err := validateQuestionImportData(data); err != nil {
+		return err
+	}
+
+	if err := ti.validateAttachments(data.Attachments); err != nil {
+		return err
+	}
+
+	userId, err := ti.resolveUser(*data.User)
+	if err != nil {
+		return err
+	}
+
+	if ti.dryRun {
+		ti.setPost(*data.Id, &importedPost{id: model.NewId(), postType: model.POST_TYPE_QUESTION, userId: userId})
+		return nil
+	}
+
+	post := &model.Post{
+		Type:    model.POST_TYPE_QUESTION,
+		UserId:  userId,
+		TeamId:  ti.team.Id,
+		Title:   *data.Title,
+		Content: *data.Content,
+	}
+	if data.Tags != nil {
+		post.Tags = model.ParseTags(*data.Tags)
+	}
+	if data.Props != nil {
+		post.Props = *data.Props
+	}
+	if data.CreateAt != nil {
+		post.CreateAt = *data.CreateAt
+	}
+	if data.EditAt != nil {
+		post.EditAt = *data.EditAt
+	}
+	if data.LockedAt != nil {
+		post.LockedAt = *data.LockedAt
+	}
+	if data.ProtectedAt != nil {
+		post.ProtectedAt = *data.ProtectedAt
+	}
+
+	post, err = ti.app.Srv.Store.Post().ImportPost(post)
+	if err != nil {
+		return err
+	}
+
+	ti.setPost(*data.Id, &importedPost{id: post.Id, postType: post.Type, userId: post.UserId})
+
+	return ti.importAttachments(post, data.Attachments)
+}
+
+func (ti *teamImporter) importAnswer(data *AnswerImportData) *model.AppError {
+	if err := validateAnswerImportData(data); err != nil {
+		return err
+	}
+
+	if err := ti.validateAttachments(data.Attachments); err != nil {
+		return err
+	}
+
+	userId, err := ti.resolveUser(*data.User)
+	if err != nil {
+		return err
+	}
+
+	question, err := ti.resolvePost(*data.Question)
+	if err != nil {
+		return err
+	}
+
+	if question.postType != model.POST_TYPE_QUESTION {
+		return model.NewAppError("BulkImport", "app.import.import_answer.question_type.error", nil, "question="+*data.Question, http.StatusBadRequest)
+	}
+
+	if ti.dryRun {
+		ti.setPost(*data.Id, &importedPost{id: model.NewId(), rootId: question.id, parentId: question.id, postType: model.POST_TYPE_ANSWER, userId: userId})
+		return nil
+	}
+
+	post := &model.Post{
+		Type:     model.POST_TYPE_ANSWER,
+		RootId:   question.id,
+		ParentId: question.id,
+		UserId:   userId,
+		TeamId:   ti.team.Id,
+		Content:  *data.Content,
+	}
+	if data.Props != nil {
+		post.Props = *data.Props
+	}
+	if data.CreateAt != nil {
+		post.CreateAt = *data.CreateAt
+	}
+	if data.EditAt != nil {
+		post.EditAt = *data.EditAt
+	}
+
+	post, err = ti.app.Srv.Store.Post().ImportPost(post)
+	if err != nil {
+		return err
+	}
+
+	ti.setPost(*data.Id, &importedPost{id: post.Id, rootId: post.RootId, parentId: post.ParentId, postType: post.Type, userId: post.UserId})
+
+	if data.IsBest != nil && *data.IsBest {
+		if err := ti.app.Srv.Store.Post().SelectBestAnswer(question.id, post.Id); err != nil {
+			return err
+		}
+	}
+
+	return ti.importAttachments(post, data.Attachments)
+}
+
+func (ti *teamImporter) importComment(data *CommentImportData) *model.AppError {
+	if err := validateCommentImportData(data); err != nil {
+		return err
+	}
+
+	userId, err := ti.resolveUser(*data.User)
+	if err != nil {
+		return err
+	}
+
+	parent, err := ti.resolvePost(*data.Parent)
+	if err != nil {
+		return err
+	}
+
+	if !model.IsQuestionOrAnswer(parent.postType) {
+		return model.NewAppError("BulkImport", "app.import.import_comment.parent_type.error", nil, "parent="+*data.Parent, http.StatusBadRequest)
+	}
+
+	rootId := parent.rootId
+	if parent.postType == model.POST_TYPE_QUESTION {
+		rootId = parent.id
+	}
+
+	if ti.dryRun {
+		ti.setPost(*data.Id, &importedPost{id: model.NewId(), rootId: rootId, parentId: parent.id, postType: model.POST_TYPE_COMMENT, userId: userId})
+		return nil
+	}
+
+	post := &model.Post{
+		Type:     model.POST_TYPE_COMMENT,
+		RootId:   rootId,
+		ParentId: parent.id,
+		UserId:   userId,
+		TeamId:   ti.team.Id,
+		Content:  *data.Content,
+	}
+	if data.CreateAt != nil {
+		post.CreateAt = *data.CreateAt
+	}
+	if data.EditAt != nil {
+		post.EditAt = *data.EditAt
+	}
+
+	post, err = ti.app.Srv.Store.Post().ImportPost(post)
+	if err != nil {
+		return err
+	}
+
+	ti.setPost(*data.Id, &importedPost{id: post.Id, rootId: post.RootId, parentId: post.ParentId, postType: post.Type, userId: post.UserId})
+	return nil
+}
+
+// 編集履歴は元の投稿のコピーとして、置き換えられた時刻に削除された状態で保存する
+func (ti *teamImporter) importRevision(data *RevisionImportData) *model.AppError {
+	if err := validateRevisionImportData(data); err != nil {
+		return err
+	}
+
+	userId, err := ti.resolveUser(*data.User)
+	if err != nil {
+		return err
+	}
+
+	original, err := ti.resolvePost(*data.Post)
+	if err != nil {
+		return err
+	}
+
+	if original.postType == model.POST_TYPE_QUESTION && data.Title == nil {
+		return model.NewAppError("BulkImport", "app.import.validate_revision_import_data.title_missing.error", nil, "post="+*data.Post, http.StatusBadRequest)
+	}
+
+	if ti.dryRun {
+		return nil
+	}
+
+	post := &model.Post{
+		Type:       original.postType,
+		RootId:     original.rootId,
+		ParentId:   original.parentId,
+		OriginalId: original.id,
+		UserId:     userId,
+		TeamId:     ti.team.Id,
+		Content:    *data.Content,
+		UpdateAt:   *data.RevisedAt,
+		DeleteAt:   *data.RevisedAt,
+	}
+	if original.postType == model.POST_TYPE_QUESTION {
+		post.Title = *data.Title
+		if data.Tags != nil {
+			post.Tags = model.ParseTags(*data.Tags)
+		}
+	}
+	if data.CreateAt != nil {
+		post.CreateAt = *data.CreateAt
+	}
+	if data.EditAt != nil {
+		post.EditAt = *data.EditAt
+	}
+
+	_, err = ti.app.Srv.Store.Post().ImportPost(post)
+	return err
+}
+
+func (ti *teamImporter) importVote(data *VoteImportData) *model.AppError {
+	if err := validateVoteImportData(data); err != nil {
+		return err
+	}
+
+	userId, err := ti.resolveUser(*data.User)
+	if err != nil {
+		return err
+	}
+
+	post, err := ti.resolvePost(*data.Post)
+	if err != nil {
+		return err
+	}
+
+	if ti.dryRun {
+		return nil
+	}
+
+	switch *data.Type {
+	case model.VOTE_TYPE_UP_VOTE:
+		_, err = ti.app.Srv.Store.Post().UpVotePost(post.id, userId)
+	case model.VOTE_TYPE_DOWN_VOTE:
+		_, err = ti.app.Srv.Store.Post().DownVotePost(post.id, userId)
+	case model.VOTE_TYPE_FLAG:
+		_, err = ti.app.Srv.Store.Post().FlagPost(post.id, userId)
+	}
+
+	return err
+}
+
+func (ti *teamImporter) importFavorite(data *FavoriteImportData) *model.AppError {
+	if err := validateFavoriteImportData(data); err != nil {
+		return err
+	}
+
+	userId, err := ti.resolveUser(*data.User)
+	if err != nil {
+		return err
+	}
+
+	post, err := ti.resolvePost(*data.Post)
+	if err != nil {
+		return err
+	}
+
+	if ti.dryRun {
+		return nil
+	}
+
+	return ti.app.Srv.Store.UserFavoritePost().Save(post.id, userId, ti.team.Id)
+}
+
+func (ti *teamImporter) importCollection(data *CollectionImportData) *model.AppError {
+	if err := validateCollectionImportData(data); err != nil {
+		return err
+	}
+
+	userId, err := ti.resolveUser(*data.User)
+	if err != nil {
+		return err
+	}
+
+	postIds := []string{}
+	if data.Posts != nil {
+		for _, sourceId := range *data.Posts {
+			post, err := ti.resolvePost(sourceId)
+			if err != nil {
+				return err
+			}
+			postIds = append(postIds, post.id)
+		}
+	}
+
+	if ti.dryRun {
+		return nil
+	}
+
+	collection := &model.Collection{
+		TeamId: ti.team.Id,
+		Title:  *data.Title,
+		UserId: userId,
+	}
+	if data.Description != nil {
+		collection.Description = *data.Description
+	}
+
+	collection, err = ti.app.Srv.Store.Collection().Save(collection, *ti.app.Config().TeamSettings.MaxCollectionsPerTeam)
+	if err != nil {
+		return err
+	}
+
+	if len(postIds) == 0 {
+		return nil
+	}
+
+	colPosts := make([]*model.CollectionPost, 0, len(postIds))
+	for _, postId := range postIds {
+		colPosts = append(colPosts, &model.CollectionPost{CollectionId: collection.Id, PostId: postId})
+	}
+
+	_, err = ti.app.Srv.Store.Collection().SaveMultiplePosts(colPosts)
+	return err
+}
+
+func (ti *teamImporter) validateAttachments(attachments *[]AttachmentImportData) *model.AppError {
+	if attachments == nil {
+		return nil
+	}
+
+	for _, attachment := range *attachments {
+		if err := validateAttachmentImportData(&attachment); err != nil {
+			return err
+		}
+
+		if _, ok := ti.attachments[*attachment.Path]; !ok {
+			return model.NewAppError("BulkImport", "app.import.validate_attachment_import_data.file_missing.error", nil, "path="+*attachment.Path, http.StatusBadRequest)
+		}
+	}
+
+	return nil
+}
+
+func (ti *teamImporter) importAttachments(post *model.Post, attachments *[]AttachmentImportData) *model.AppError {
+	if attachments == nil {
+		return nil
+	}
+
+	for _, attachment := range *attachments {
+		if err := ti.importAttachment(post, &attachment); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (ti *teamImporter) importAttachment(post *model.Post, attachment *AttachmentImportData) *model.AppError {
+	file := ti.attachments[*attachment.Path]
+
+	name := file.Name[strings.LastIndex(file.Name, "/")+1:]
+	if attachment.Name != nil {
+		name = *attachment.Name
+	}
+
+	reader, err := file.Open()
+	if err != nil {
+		return model.NewAppError("BulkImport", "app.import.import_attachment.open.error", nil, "path="+*attachment.Path+", "+err.Error(), http.StatusBadRequest)
+	}
+	defer reader.Close()
+
+	info, appErr := ti.app.UploadFileX(name, reader,
+		UploadFileSetUserId(post.UserId),
+		UploadFileSetTimestamp(time.Unix(0, post.CreateAt*int64(time.Millisecond))),
+		UploadFileSetContentLength(int64(file.UncompressedSize64)),
+	)
+	if appErr != nil {
+		return appErr
+	}
+
+	return ti.app.Srv.Store.FileInfo().AttachToPost(info.Id, post.Id, post.UserId)
+}
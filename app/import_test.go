@@ -0,0 +1,164 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/clear-ness/qa-discussion/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (th *TestHelper) bulkImport(tb testing.TB, team *model.Team, dryRun bool, lines ...string) *ImportReport {
+	return th.bulkImportWithAuth(tb, team, dryRun, false, lines...)
+}
+
+func (th *TestHelper) bulkImportWithAuth(tb testing.TB, team *model.Team, dryRun bool, allowAuthData bool, lines ...string) *ImportReport {
+	data := `{"type": "version", "version": 1}` + "\n" + strings.Join(lines, "\n")
+
+	report, err := th.App.BulkImport(strings.NewReader(data), team, nil, dryRun, allowAuthData, 2)
+	require.Nil(tb, err)
+
+	return report
+}
+
+func TestImportUser(t *testing.T) {
+	th := Setup(t)
+	defer th.TearDown()
+
+	existing := th.CreateUser(t)
+	team := th.CreateTeam(t, th.CreateUser(t))
+	th.AddTeamMember(t, team, existing)
+
+	username := "import" + strings.ToLower(model.NewRandomString(10))
+	lines := []string{
+		`{"type": "user", "user": {"username": "` + existing.Username + `", "email": "` + existing.Email + `", "team_member_type": "admin"}}`,
+		`{"type": "user", "user": {"username": "` + username + `", "email": "` + username + `@example.com", "team_member_type": "admin"}}`,
+	}
+
+	// dry-runでも実際の取り込みと同じ内容を報告する
+	for _, dryRun := range []bool{true, false} {
+		report := th.bulkImport(t, team, dryRun, lines...)
+		assert.Equal(t, 0, report.ErrorCount, report.Errors)
+		require.Len(t, report.Warnings, 1, dryRun)
+		assert.Equal(t, 2, report.Warnings[0].LineNumber)
+		assert.Equal(t, "app.import.import_user.existing_user_not_elevated.warning", report.Warnings[0].Id)
+	}
+
+	// 既存のメンバーは通常のメンバーのまま
+	member, err := th.Store.Team().GetMember(team.Id, existing.Id)
+	require.Nil(t, err)
+	assert.Equal(t, model.TEAM_MEMBER_TYPE_NORMAL, member.Type)
+
+	created, err := th.Store.User().GetByUsername(username)
+	require.Nil(t, err)
+	member, err = th.Store.Team().GetMember(team.Id, created.Id)
+	require.Nil(t, err)
+	assert.Equal(t, model.TEAM_MEMBER_TYPE_ADMIN, member.Type)
+}
+
+func TestImportUserOutsideTeam(t *testing.T) {
+	th := Setup(t)
+	defer th.TearDown()
+
+	owner := th.CreateUser(t)
+	outsider := th.CreateUser(t)
+	team := th.CreateTeam(t, owner)
+
+	t.Run("existing user by email", func(t *testing.T) {
+		report := th.bulkImport(t, team, false,
+			`{"type": "user", "user": {"username": "`+outsider.Username+`", "email": "`+outsider.Email+`", "team_member_type": "normal"}}`,
+		)
+		require.Equal(t, 1, report.ErrorCount)
+		assert.Equal(t, "app.import.import_user.not_team_member.error", report.Errors[0].Id)
+
+		// チーム外のユーザーは参加させない
+		_, err := th.Store.Team().GetMember(team.Id, outsider.Id)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("question by username", func(t *testing.T) {
+		report := th.bulkImport(t, team, false,
+			`{"type": "question", "question": {"id": "q1", "user": "`+outsider.Username+`", "title": "imported question", "content": "imported question content", "tags": "golang"}}`,
+			`{"type": "question", "question": {"id": "q2", "user": "`+owner.Username+`", "title": "imported question", "content": "imported question content", "tags": "golang"}}`,
+		)
+		require.Equal(t, 1, report.ErrorCount)
+		assert.Equal(t, 2, report.Errors[0].LineNumber)
+		assert.Equal(t, "app.import.resolve_user.missing.error", report.Errors[0].Id)
+
+		assert.Equal(t, int64(0), th.countPosts(t, model.POST_TYPE_QUESTION, outsider.Id, team.Id))
+		assert.Equal(t, int64(1), th.countPosts(t, model.POST_TYPE_QUESTION, owner.Id, team.Id))
+	})
+}
+
+func TestImportUserAuthData(t *testing.T) {
+	th := Setup(t)
+	defer th.TearDown()
+
+	team := th.CreateTeam(t, th.CreateUser(t))
+
+	username := "import" + strings.ToLower(model.NewRandomString(10))
+	line := `{"type": "user", "user": {"username": "` + username + `", "email": "` + username + `@example.com", "auth_service": "openid", "auth_data": "` + username + `", "team_member_type": "normal"}}`
+
+	// チームの管理者は外部認証の情報を書き込めない
+	report := th.bulkImport(t, team, false, line)
+	require.Equal(t, 1, report.ErrorCount)
+	assert.Equal(t, "app.import.import_user.auth_not_allowed.error", report.Errors[0].Id)
+	_, err := th.Store.User().GetByUsername(username)
+	assert.NotNil(t, err)
+
+	report = th.bulkImportWithAuth(t, team, false, true, line)
+	assert.Equal(t, 0, report.ErrorCount, report.Errors)
+
+	created, err := th.Store.User().GetByUsername(username)
+	require.Nil(t, err)
+	assert.Equal(t, "openid", created.AuthService)
+	require.NotNil(t, created.AuthData)
+	assert.Equal(t, username, *created.AuthData)
+}
+
+func TestImportTag(t *testing.T) {
+	th := Setup(t)
+	defer th.TearDown()
+
+	user := th.CreateUser(t)
+	team := th.CreateTeam(t, user)
+
+	lines := []string{
+		`{"type": "tag", "tag": {"content": "unused", "create_at": 1600000000000}}`,
+		`{"type": "tag", "tag": {"content": "golang"}}`,
+		// バイト数は足りるが文字数が足りない
+		`{"type": "tag", "tag": {"content": "日本"}}`,
+		`{"type": "question", "question": {"id": "q1", "user": "` + user.Username + `", "title": "imported question", "content": "imported question content", "tags": "golang"}}`,
+	}
+
+	report := th.bulkImport(t, team, true, lines...)
+	assert.Equal(t, 1, report.ErrorCount)
+	assert.Empty(t, th.teamTags(t, team))
+
+	report = th.bulkImport(t, team, false, lines...)
+	require.Equal(t, 1, report.ErrorCount)
+	assert.Equal(t, 4, report.Errors[0].LineNumber)
+	assert.Equal(t, "app.import.validate_tag_import_data.content.error", report.Errors[0].Id)
+
+	tags := th.teamTags(t, team)
+	require.Len(t, tags, 2)
+	// 投稿の無いタグも作成日時とともに残る
+	assert.Equal(t, 0, tags["unused"].PostCount)
+	assert.Equal(t, int64(1600000000000), tags["unused"].CreateAt)
+	// 投稿数は質問の取り込みで数える
+	assert.Equal(t, 1, tags["golang"].PostCount)
+}
+
+func (th *TestHelper) teamTags(tb testing.TB, team *model.Team) map[string]model.Tag {
+	tags, err := th.Store.Tag().GetTags(&model.GetTagsOptions{TeamId: team.Id, SortType: model.POST_SORT_TYPE_NAME, PerPage: 100})
+	require.Nil(tb, err)
+
+	byContent := map[string]model.Tag{}
+	for _, tag := range tags {
+		byContent[tag.Content] = tag
+	}
+
+	return byContent
+}
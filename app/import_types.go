@@ -1,74 +1,172 @@
 package app
 
 import (
+	"encoding/json"
+	"sync"
+
 	"github.com/clear-ness/qa-discussion/model"
 )
 
+const (
+	IMPORT_DATA_FILE_VERSION = 1
+
+	IMPORT_LINE_TYPE_VERSION    = "version"
+	IMPORT_LINE_TYPE_TEAM       = "team"
+	IMPORT_LINE_TYPE_USER       = "user"
+	IMPORT_LINE_TYPE_GROUP      = "group"
+	IMPORT_LINE_TYPE_TAG        = "tag"
+	IMPORT_LINE_TYPE_QUESTION   = "question"
+	IMPORT_LINE_TYPE_ANSWER     = "answer"
+	IMPORT_LINE_TYPE_COMMENT    = "comment"
+	IMPORT_LINE_TYPE_REVISION   = "revision"
+	IMPORT_LINE_TYPE_VOTE       = "vote"
+	IMPORT_LINE_TYPE_FAVORITE   = "favorite"
+	IMPORT_LINE_TYPE_COLLECTION = "collection"
+
+	// zip内のJSONLファイル名と、添付ファイルを置くディレクトリ
+	IMPORT_DATA_FILE_NAME  = "import.jsonl"
+	IMPORT_ATTACHMENTS_DIR = "data/"
+
+	// これ以上エラーが出た場合はデータ自体が壊れているとみなして中断する
+	IMPORT_MAX_LINE_ERRORS = 100
+	// レポートはJobs.Data(text型)に収める必要がある
+	IMPORT_ERROR_DETAIL_MAX_LENGTH = 256
+)
+
+// 1行が1つのデータに対応する。
+// ユーザーはusernameで、投稿は元のインスタンスでのidで参照する。
 type LineImportData struct {
 	// Typeでどのモデルかを判断する
-	Type string          `json:"type"`
-	Team *TeamImportData `json:"team,omitempty"`
-	User *UserImportData `json:"user,omitempty"`
-	Post *PostImportData `json:"post,omitempty"`
+	Type       string                `json:"type"`
+	Version    *int                  `json:"version,omitempty"`
+	Team       *TeamImportData       `json:"team,omitempty"`
+	User       *UserImportData       `json:"user,omitempty"`
+	Group      *GroupImportData      `json:"group,omitempty"`
+	Tag        *TagImportData        `json:"tag,omitempty"`
+	Question   *QuestionImportData   `json:"question,omitempty"`
+	Answer     *AnswerImportData     `json:"answer,omitempty"`
+	Comment    *CommentImportData    `json:"comment,omitempty"`
+	Revision   *RevisionImportData   `json:"revision,omitempty"`
+	Vote       *VoteImportData       `json:"vote,omitempty"`
+	Favorite   *FavoriteImportData   `json:"favorite,omitempty"`
+	Collection *CollectionImportData `json:"collection,omitempty"`
 }
 
 type TeamImportData struct {
-	Name            *string `json:"name"`
-	DisplayName     *string `json:"display_name"`
-	Type            *string `json:"type"`
-	Description     *string `json:"description,omitempty"`
-	AllowOpenInvite *bool   `json:"allow_open_invite,omitempty"`
-	Scheme          *string `json:"scheme,omitempty"`
+	Name           *string `json:"name"`
+	Type           *string `json:"type"`
+	Description    *string `json:"description,omitempty"`
+	Email          *string `json:"email,omitempty"`
+	AllowedDomains *string `json:"allowed_domains,omitempty"`
 }
 
 type UserImportData struct {
-	ProfileImage       *string `json:"profile_image,omitempty"`
-	Username           *string `json:"username"`
-	Email              *string `json:"email"`
-	AuthService        *string `json:"auth_service"`
-	AuthData           *string `json:"auth_data,omitempty"`
-	Password           *string `json:"password,omitempty"`
-	Nickname           *string `json:"nickname"`
-	FirstName          *string `json:"first_name"`
-	LastName           *string `json:"last_name"`
-	Position           *string `json:"position"`
-	Roles              *string `json:"roles"`
-	Locale             *string `json:"locale"`
-	UseMarkdownPreview *string `json:"feature_enabled_markdown_preview,omitempty"`
-	UseFormatting      *string `json:"formatting,omitempty"`
-	ShowUnreadSection  *string `json:"show_unread_section,omitempty"`
-	DeleteAt           *int64  `json:"delete_at,omitempty"`
-
-	Teams *[]UserTeamImportData `json:"teams,omitempty"`
-
-	Theme              *string `json:"theme,omitempty"`
-	UseMilitaryTime    *string `json:"military_time,omitempty"`
-	CollapsePreviews   *string `json:"link_previews,omitempty"`
-	MessageDisplay     *string `json:"message_display,omitempty"`
-	ChannelDisplayMode *string `json:"channel_display_mode,omitempty"`
-	TutorialStep       *string `json:"tutorial_step,omitempty"`
-	EmailInterval      *string `json:"email_interval,omitempty"`
-}
-
-type UserTeamImportData struct {
-	Name *string `json:"name"`
-	Type *string `json:"type"`
+	Username      *string `json:"username"`
+	Email         *string `json:"email"`
+	EmailVerified *bool   `json:"email_verified,omitempty"`
+	AuthService   *string `json:"auth_service,omitempty"`
+	AuthData      *string `json:"auth_data,omitempty"`
+	CreateAt      *int64  `json:"create_at,omitempty"`
+	DeleteAt      *int64  `json:"delete_at,omitempty"`
+
+	// チームから退会済みの場合は空
+	TeamMemberType *string `json:"team_member_type,omitempty"`
+}
+
+type GroupImportData struct {
+	Name        *string                  `json:"name"`
+	Type        *string                  `json:"type"`
+	Description *string                  `json:"description,omitempty"`
+	User        *string                  `json:"user"`
+	CreateAt    *int64                   `json:"create_at,omitempty"`
+	Members     *[]GroupMemberImportData `json:"members,omitempty"`
 }
 
-type PostImportData struct {
-	Team *string `json:"team"`
+type GroupMemberImportData struct {
 	User *string `json:"user"`
+	Type *string `json:"type"`
+}
 
-	Content  *string                `json:"content"`
-	Props    *model.StringInterface `json:"props"`
-	CreateAt *int64                 `json:"create_at"`
+type TagImportData struct {
+	Content  *string `json:"content"`
+	CreateAt *int64  `json:"create_at,omitempty"`
+}
+
+type QuestionImportData struct {
+	Id          *string                 `json:"id"`
+	User        *string                 `json:"user"`
+	Title       *string                 `json:"title"`
+	Content     *string                 `json:"content"`
+	Tags        *string                 `json:"tags,omitempty"`
+	Props       *model.StringInterface  `json:"props,omitempty"`
+	CreateAt    *int64                  `json:"create_at,omitempty"`
+	EditAt      *int64                  `json:"edit_at,omitempty"`
+	LockedAt    *int64                  `json:"locked_at,omitempty"`
+	ProtectedAt *int64                  `json:"protected_at,omitempty"`
+	Attachments *[]AttachmentImportData `json:"attachments,omitempty"`
+}
 
-	FlaggedBy   *[]string               `json:"flagged_by,omitempty"`
-	Reactions   *[]ReactionImportData   `json:"reactions,omitempty"`
-	Replies     *[]ReplyImportData      `json:"replies,omitempty"`
+type AnswerImportData struct {
+	Id          *string                 `json:"id"`
+	Question    *string                 `json:"question"`
+	User        *string                 `json:"user"`
+	Content     *string                 `json:"content"`
+	IsBest      *bool                   `json:"is_best,omitempty"`
+	Props       *model.StringInterface  `json:"props,omitempty"`
+	CreateAt    *int64                  `json:"create_at,omitempty"`
+	EditAt      *int64                  `json:"edit_at,omitempty"`
 	Attachments *[]AttachmentImportData `json:"attachments,omitempty"`
 }
 
+type CommentImportData struct {
+	Id       *string `json:"id"`
+	Parent   *string `json:"parent"`
+	User     *string `json:"user"`
+	Content  *string `json:"content"`
+	CreateAt *int64  `json:"create_at,omitempty"`
+	EditAt   *int64  `json:"edit_at,omitempty"`
+}
+
+// 編集前の投稿内容
+type RevisionImportData struct {
+	Post     *string `json:"post"`
+	User     *string `json:"user"`
+	Title    *string `json:"title,omitempty"`
+	Content  *string `json:"content"`
+	Tags     *string `json:"tags,omitempty"`
+	CreateAt *int64  `json:"create_at,omitempty"`
+	EditAt   *int64  `json:"edit_at,omitempty"`
+	// この内容が編集によって置き換えられた時刻
+	RevisedAt *int64 `json:"revised_at"`
+}
+
+type VoteImportData struct {
+	Post     *string `json:"post"`
+	User     *string `json:"user"`
+	Type     *string `json:"type"`
+	CreateAt *int64  `json:"create_at,omitempty"`
+}
+
+type FavoriteImportData struct {
+	Post     *string `json:"post"`
+	User     *string `json:"user"`
+	CreateAt *int64  `json:"create_at,omitempty"`
+}
+
+type CollectionImportData struct {
+	Title       *string   `json:"title"`
+	Description *string   `json:"description,omitempty"`
+	User        *string   `json:"user"`
+	CreateAt    *int64    `json:"create_at,omitempty"`
+	Posts       *[]string `json:"posts,omitempty"`
+}
+
+type AttachmentImportData struct {
+	// zip内のパス
+	Path *string `json:"path"`
+	Name *string `json:"name,omitempty"`
+}
+
 // 受信データを複数goルーチンで並列処理するため
 type LineImportWorkerData struct {
 	LineImportData
@@ -80,3 +178,64 @@ type LineImportWorkerError struct {
 	Error      *model.AppError
 	LineNumber int
 }
+
+// 行ごとのエラーをまとめて返し、dry-runの結果確認にも使う。
+// Warningsは取り込んだが、ファイルの内容どおりにはしなかった行
+type ImportReport struct {
+	DryRun     bool              `json:"dry_run"`
+	LineCount  int               `json:"line_count"`
+	ErrorCount int               `json:"error_count"`
+	Errors     []ImportLineError `json:"errors"`
+	Warnings   []ImportLineError `json:"warnings"`
+
+	mutex sync.Mutex
+}
+
+type ImportLineError struct {
+	LineNumber int    `json:"line_number"`
+	Id         string `json:"id"`
+	Detail     string `json:"detail,omitempty"`
+}
+
+func (r *ImportReport) addError(err LineImportWorkerError) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	detail := err.Error.DetailedError
+	if len(detail) > IMPORT_ERROR_DETAIL_MAX_LENGTH {
+		detail = detail[:IMPORT_ERROR_DETAIL_MAX_LENGTH]
+	}
+
+	r.ErrorCount++
+	r.Errors = append(r.Errors, ImportLineError{
+		LineNumber: err.LineNumber,
+		Id:         err.Error.Id,
+		Detail:     detail,
+	})
+}
+
+func (r *ImportReport) addWarning(warning LineImportWorkerError) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.Warnings = append(r.Warnings, ImportLineError{
+		LineNumber: warning.LineNumber,
+		Id:         warning.Error.Id,
+		Detail:     warning.Error.DetailedError,
+	})
+}
+
+func (r *ImportReport) errorCount() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.ErrorCount
+}
+
+func (r *ImportReport) ToJson() string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	b, _ := json.Marshal(r)
+	return string(b)
+}
@@ -1 +1,253 @@
 package app
+
+import (
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/clear-ness/qa-discussion/model"
+)
+
+// 各行のデータがモデルの制約を満たすかを、DBに触れる前に検証する
+
+func validateTeamImportData(data *TeamImportData) *model.AppError {
+	if data.Name == nil || !model.IsValidTeamName(*data.Name) {
+		return model.NewAppError("BulkImport", "app.import.validate_team_import_data.name.error", nil, "", http.StatusBadRequest)
+	}
+
+	if data.Type == nil || (*data.Type != model.TEAM_TYPE_PUBLIC && *data.Type != model.TEAM_TYPE_PRIVATE) {
+		return model.NewAppError("BulkImport", "app.import.validate_team_import_data.type.error", nil, "", http.StatusBadRequest)
+	}
+
+	if data.Description != nil && utf8.RuneCountInString(*data.Description) > model.TEAM_DESCRIPTION_MAX_LENGTH {
+		return model.NewAppError("BulkImport", "app.import.validate_team_import_data.description_length.error", nil, "", http.StatusBadRequest)
+	}
+
+	if data.AllowedDomains != nil && len(*data.AllowedDomains) > model.TEAM_ALLOWED_DOMAINS_MAX_LENGTH {
+		return model.NewAppError("BulkImport", "app.import.validate_team_import_data.allowed_domains_length.error", nil, "", http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+func validateUserImportData(data *UserImportData) *model.AppError {
+	if data.Username == nil || !model.IsValidUsername(model.NormalizeUsername(*data.Username)) {
+		return model.NewAppError("BulkImport", "app.import.validate_user_import_data.username.error", nil, "", http.StatusBadRequest)
+	}
+
+	if data.Email == nil || len(*data.Email) > model.USER_EMAIL_MAX_LENGTH || !model.IsValidEmail(*data.Email) {
+		return model.NewAppError("BulkImport", "app.import.validate_user_import_data.email.error", nil, "username="+*data.Username, http.StatusBadRequest)
+	}
+
+	if data.AuthService != nil && (data.AuthData == nil || len(*data.AuthData) == 0 || len(*data.AuthData) > model.USER_AUTH_DATA_MAX_LENGTH) {
+		return model.NewAppError("BulkImport", "app.import.validate_user_import_data.auth_data.error", nil, "username="+*data.Username, http.StatusBadRequest)
+	}
+
+	if data.TeamMemberType != nil && *data.TeamMemberType != model.TEAM_MEMBER_TYPE_NORMAL && *data.TeamMemberType != model.TEAM_MEMBER_TYPE_ADMIN {
+		return model.NewAppError("BulkImport", "app.import.validate_user_import_data.team_member_type.error", nil, "username="+*data.Username, http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+func validateGroupImportData(data *GroupImportData) *model.AppError {
+	if data.Name == nil || !model.IsValidUserGroupIdentifier(*data.Name) {
+		return model.NewAppError("BulkImport", "app.import.validate_group_import_data.name.error", nil, "", http.StatusBadRequest)
+	}
+
+	if data.Type == nil || (*data.Type != model.GROUP_TYPE_PUBLIC && *data.Type != model.GROUP_TYPE_PRIVATE) {
+		return model.NewAppError("BulkImport", "app.import.validate_group_import_data.type.error", nil, "name="+*data.Name, http.StatusBadRequest)
+	}
+
+	if data.Description != nil && len(*data.Description) > model.GROUP_DESCRIPTION_MAX_LENGTH {
+		return model.NewAppError("BulkImport", "app.import.validate_group_import_data.description_length.error", nil, "name="+*data.Name, http.StatusBadRequest)
+	}
+
+	if data.User == nil {
+		return model.NewAppError("BulkImport", "app.import.validate_group_import_data.user_missing.error", nil, "name="+*data.Name, http.StatusBadRequest)
+	}
+
+	if data.Members != nil {
+		for _, member := range *data.Members {
+			if member.User == nil {
+				return model.NewAppError("BulkImport", "app.import.validate_group_import_data.member_user_missing.error", nil, "name="+*data.Name, http.StatusBadRequest)
+			}
+
+			if member.Type == nil || (*member.Type != model.GROUP_MEMBER_TYPE_NORMAL && *member.Type != model.GROUP_MEMBER_TYPE_ADMIN) {
+				return model.NewAppError("BulkImport", "app.import.validate_group_import_data.member_type.error", nil, "name="+*data.Name, http.StatusBadRequest)
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateTagImportData(data *TagImportData) *model.AppError {
+	if data.Content == nil || len(model.ParseTags(*data.Content)) == 0 {
+		return model.NewAppError("BulkImport", "app.import.validate_tag_import_data.content.error", nil, "", http.StatusBadRequest)
+	}
+
+	// ParseTagsはバイト数で見るため、保存時と同じく文字数でも確かめる
+	for _, content := range strings.Fields(model.ParseTags(*data.Content)) {
+		if !isValidImportRuneCount(content, model.TAG_MIN_RUNES, model.TAG_MAX_RUNES) {
+			return model.NewAppError("BulkImport", "app.import.validate_tag_import_data.content.error", nil, "content="+content, http.StatusBadRequest)
+		}
+	}
+
+	return nil
+}
+
+func validateQuestionImportData(data *QuestionImportData) *model.AppError {
+	if data.Id == nil || len(*data.Id) == 0 {
+		return model.NewAppError("BulkImport", "app.import.validate_question_import_data.id_missing.error", nil, "", http.StatusBadRequest)
+	}
+
+	if data.User == nil {
+		return model.NewAppError("BulkImport", "app.import.validate_question_import_data.user_missing.error", nil, "id="+*data.Id, http.StatusBadRequest)
+	}
+
+	if data.Title == nil || !isValidImportRuneCount(*data.Title, model.POST_TITLE_MIN_RUNES, model.POST_TITLE_MAX_RUNES) {
+		return model.NewAppError("BulkImport", "app.import.validate_question_import_data.title.error", nil, "id="+*data.Id, http.StatusBadRequest)
+	}
+
+	if data.Content == nil || !isValidImportRuneCount(*data.Content, model.POST_CONTENT_MIN_RUNES, model.POST_CONTENT_MAX_RUNES) {
+		return model.NewAppError("BulkImport", "app.import.validate_question_import_data.content.error", nil, "id="+*data.Id, http.StatusBadRequest)
+	}
+
+	if data.Props != nil && utf8.RuneCountInString(model.StringInterfaceToJson(*data.Props)) > model.POST_PROPS_MAX_RUNES {
+		return model.NewAppError("BulkImport", "app.import.validate_question_import_data.props_too_large.error", nil, "id="+*data.Id, http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+func validateAnswerImportData(data *AnswerImportData) *model.AppError {
+	if data.Id == nil || len(*data.Id) == 0 {
+		return model.NewAppError("BulkImport", "app.import.validate_answer_import_data.id_missing.error", nil, "", http.StatusBadRequest)
+	}
+
+	if data.Question == nil {
+		return model.NewAppError("BulkImport", "app.import.validate_answer_import_data.question_missing.error", nil, "id="+*data.Id, http.StatusBadRequest)
+	}
+
+	if data.User == nil {
+		return model.NewAppError("BulkImport", "app.import.validate_answer_import_data.user_missing.error", nil, "id="+*data.Id, http.StatusBadRequest)
+	}
+
+	if data.Content == nil || !isValidImportRuneCount(*data.Content, model.POST_CONTENT_MIN_RUNES, model.POST_CONTENT_MAX_RUNES) {
+		return model.NewAppError("BulkImport", "app.import.validate_answer_import_data.content.error", nil, "id="+*data.Id, http.StatusBadRequest)
+	}
+
+	if data.Props != nil && utf8.RuneCountInString(model.StringInterfaceToJson(*data.Props)) > model.POST_PROPS_MAX_RUNES {
+		return model.NewAppError("BulkImport", "app.import.validate_answer_import_data.props_too_large.error", nil, "id="+*data.Id, http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+func validateCommentImportData(data *CommentImportData) *model.AppError {
+	if data.Id == nil || len(*data.Id) == 0 {
+		return model.NewAppError("BulkImport", "app.import.validate_comment_import_data.id_missing.error", nil, "", http.StatusBadRequest)
+	}
+
+	if data.Parent == nil {
+		return model.NewAppError("BulkImport", "app.import.validate_comment_import_data.parent_missing.error", nil, "id="+*data.Id, http.StatusBadRequest)
+	}
+
+	if data.User == nil {
+		return model.NewAppError("BulkImport", "app.import.validate_comment_import_data.user_missing.error", nil, "id="+*data.Id, http.StatusBadRequest)
+	}
+
+	if data.Content == nil || !isValidImportRuneCount(*data.Content, model.POST_CONTENT_MIN_RUNES, model.POST_CONTENT_MAX_RUNES) {
+		return model.NewAppError("BulkImport", "app.import.validate_comment_import_data.content.error", nil, "id="+*data.Id, http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+func validateRevisionImportData(data *RevisionImportData) *model.AppError {
+	if data.Post == nil {
+		return model.NewAppError("BulkImport", "app.import.validate_revision_import_data.post_missing.error", nil, "", http.StatusBadRequest)
+	}
+
+	if data.User == nil {
+		return model.NewAppError("BulkImport", "app.import.validate_revision_import_data.user_missing.error", nil, "post="+*data.Post, http.StatusBadRequest)
+	}
+
+	if data.Title != nil && !isValidImportRuneCount(*data.Title, model.POST_TITLE_MIN_RUNES, model.POST_TITLE_MAX_RUNES) {
+		return model.NewAppError("BulkImport", "app.import.validate_revision_import_data.title.error", nil, "post="+*data.Post, http.StatusBadRequest)
+	}
+
+	if data.Content == nil || !isValidImportRuneCount(*data.Content, model.POST_CONTENT_MIN_RUNES, model.POST_CONTENT_MAX_RUNES) {
+		return model.NewAppError("BulkImport", "app.import.validate_revision_import_data.content.error", nil, "post="+*data.Post, http.StatusBadRequest)
+	}
+
+	if data.RevisedAt == nil || *data.RevisedAt <= 0 {
+		return model.NewAppError("BulkImport", "app.import.validate_revision_import_data.revised_at.error", nil, "post="+*data.Post, http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+func validateVoteImportData(data *VoteImportData) *model.AppError {
+	if data.Post == nil {
+		return model.NewAppError("BulkImport", "app.import.validate_vote_import_data.post_missing.error", nil, "", http.StatusBadRequest)
+	}
+
+	if data.User == nil {
+		return model.NewAppError("BulkImport", "app.import.validate_vote_import_data.user_missing.error", nil, "post="+*data.Post, http.StatusBadRequest)
+	}
+
+	if data.Type == nil {
+		return model.NewAppError("BulkImport", "app.import.validate_vote_import_data.type.error", nil, "post="+*data.Post, http.StatusBadRequest)
+	}
+
+	switch *data.Type {
+	case model.VOTE_TYPE_UP_VOTE, model.VOTE_TYPE_DOWN_VOTE, model.VOTE_TYPE_FLAG:
+	default:
+		return model.NewAppError("BulkImport", "app.import.validate_vote_import_data.type.error", nil, "post="+*data.Post+", type="+*data.Type, http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+func validateFavoriteImportData(data *FavoriteImportData) *model.AppError {
+	if data.Post == nil {
+		return model.NewAppError("BulkImport", "app.import.validate_favorite_import_data.post_missing.error", nil, "", http.StatusBadRequest)
+	}
+
+	if data.User == nil {
+		return model.NewAppError("BulkImport", "app.import.validate_favorite_import_data.user_missing.error", nil, "post="+*data.Post, http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+func validateCollectionImportData(data *CollectionImportData) *model.AppError {
+	if data.Title == nil || !isValidImportRuneCount(*data.Title, model.COLLECTION_TITLE_MIN_RUNES, model.COLLECTION_TITLE_MAX_RUNES) {
+		return model.NewAppError("BulkImport", "app.import.validate_collection_import_data.title.error", nil, "", http.StatusBadRequest)
+	}
+
+	if data.Description != nil && len(*data.Description) > model.COLLECTION_DESCRIPTION_MAX_LENGTH {
+		return model.NewAppError("BulkImport", "app.import.validate_collection_import_data.description_length.error", nil, "", http.StatusBadRequest)
+	}
+
+	if data.User == nil {
+		return model.NewAppError("BulkImport", "app.import.validate_collection_import_data.user_missing.error", nil, "", http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+func validateAttachmentImportData(data *AttachmentImportData) *model.AppError {
+	if data.Path == nil || len(*data.Path) == 0 {
+		return model.NewAppError("BulkImport", "app.import.validate_attachment_import_data.path_missing.error", nil, "", http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+func isValidImportRuneCount(s string, min int, max int) bool {
+	count := utf8.RuneCountInString(s)
+	return count >= min && count <= max
+}
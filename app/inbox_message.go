@@ -5,13 +5,22 @@ import (
 )
 
 func (a *App) GetSingleInboxMessage(messageId string) (*model.InboxMessage, *model.AppError) {
-	return a.Srv.Store.InboxMessage().GetSingle(messageId)
+	return a.Store().InboxMessage().GetSingle(messageId)
 }
 
-func (a *App) GetInboxMessagesForUserToDate(toDate int64, userId string, page, perPage int, teamId string) ([]*model.InboxMessage, *model.AppError) {
-	return a.Srv.Store.InboxMessage().GetInboxMessages(toDate, userId, "<=", page, perPage, teamId)
+func (a *App) GetInboxMessagesForUserToDate(toDate int64, userId string, page, perPage int, teamId string, cursor *model.Cursor) ([]*model.InboxMessage, model.CursorPage, *model.AppError) {
+	messages, err := a.Store().InboxMessage().GetInboxMessages(toDate, userId, "<=", page, perPage, teamId, cursor)
+	if err != nil {
+		return nil, model.CursorPage{}, err
+	}
+
+	cursorPage := model.NewCursorPage(len(messages), perPage, cursor, page > 0, func(i int) *model.Cursor {
+		return model.NewCursor(model.CURSOR_TYPE_INBOX_MESSAGES, "", messages[i].CreateAt, messages[i].Id)
+	})
+
+	return messages, cursorPage, nil
 }
 
 func (a *App) GetInboxMessagesUnreadCountForUser(userId string, teamId string) (int64, *model.AppError) {
-	return a.Srv.Store.InboxMessage().GetInboxMessagesUnreadCount(userId, 0, teamId)
+	return a.Store().InboxMessage().GetInboxMessagesUnreadCount(userId, 0, teamId)
 }
@@ -0,0 +1,125 @@
+package app
+
+import (
+	"net/http"
+
+	"github.com/clear-ness/qa-discussion/model"
+)
+
+func (a *App) CreateIncomingWebhook(hook *model.IncomingWebhook) (*model.IncomingWebhook, *model.AppError) {
+	if err := a.checkIncomingWebhookTargets(hook); err != nil {
+		return nil, err
+	}
+
+	return a.Store().IncomingWebhook().Save(hook)
+}
+
+func (a *App) GetIncomingWebhook(hookId string) (*model.IncomingWebhook, *model.AppError) {
+	return a.Store().IncomingWebhook().Get(hookId)
+}
+
+func (a *App) GetIncomingWebhooksForTeamPage(teamId string, page, perPage int) ([]*model.IncomingWebhook, *model.AppError) {
+	return a.Store().IncomingWebhook().GetByTeam(teamId, page*perPage, perPage)
+}
+
+func (a *App) UpdateIncomingWebhook(oldHook, updatedHook *model.IncomingWebhook) (*model.IncomingWebhook, *model.AppError) {
+	updatedHook.Id = oldHook.Id
+	updatedHook.Token = oldHook.Token
+	updatedHook.TeamId = oldHook.TeamId
+	updatedHook.CreatorId = oldHook.CreatorId
+	updatedHook.CreateAt = oldHook.CreateAt
+	updatedHook.DeleteAt = oldHook.DeleteAt
+
+	if err := a.checkIncomingWebhookTargets(updatedHook); err != nil {
+		return nil, err
+	}
+
+	return a.Store().IncomingWebhook().Update(updatedHook)
+}
+
+func (a *App) DeleteIncomingWebhook(hookId string) *model.AppError {
+	return a.Store().IncomingWebhook().Delete(hookId, model.GetMillis())
+}
+
+func (a *App) RegenIncomingWebhookToken(hook *model.IncomingWebhook) (*model.IncomingWebhook, *model.AppError) {
+	hook.Token = model.NewId()
+	return a.Store().IncomingWebhook().Update(hook)
+}
+
+// 投稿者のbotと通知先のgroupが、webhookと同じteamのものであることを確認する
+func (a *App) checkIncomingWebhookTargets(hook *model.IncomingWebhook) *model.AppError {
+	bot, err := a.Store().Bot().Get(hook.BotUserId)
+	if err != nil {
+		return model.NewAppError("checkIncomingWebhookTargets", "app.incoming_webhook.bot.app_error", nil, err.Error(), http.StatusBadRequest)
+	}
+	if bot.TeamId != hook.TeamId {
+		return model.NewAppError("checkIncomingWebhookTargets", "app.incoming_webhook.bot_team_mismatch.app_error", nil, "bot_user_id="+hook.BotUserId, http.StatusBadRequest)
+	}
+
+	if hook.GroupId != "" {
+		group, err := a.GetGroup(hook.GroupId)
+		if err != nil {
+			return model.NewAppError("checkIncomingWebhookTargets", "app.incoming_webhook.group.app_error", nil, err.Error(), http.StatusBadRequest)
+		}
+		if group.TeamId != hook.TeamId {
+			return model.NewAppError("checkIncomingWebhookTargets", "app.incoming_webhook.group_team_mismatch.app_error", nil, "group_id="+hook.GroupId, http.StatusBadRequest)
+		}
+	}
+
+	return nil
+}
+
+// tokenに対応するwebhookのbotとして、リクエストの内容で投稿を作る
+func (a *App) ExecuteIncomingWebhook(token string, req *model.IncomingWebhookRequest) (*model.Post, *model.AppError) {
+	hook, err := a.Store().IncomingWebhook().GetByToken(token)
+	if err != nil {
+		if err.StatusCode == http.StatusNotFound {
+			return nil, model.NewAppError("ExecuteIncomingWebhook", "app.incoming_webhook.invalid_token.app_error", nil, "", http.StatusUnauthorized)
+		}
+		return nil, err
+	}
+
+	if err := req.IsValid(); err != nil {
+		return nil, err
+	}
+
+	// 削除済みのbotはGetで見つからない
+	if _, err := a.Store().Bot().Get(hook.BotUserId); err != nil {
+		return nil, model.NewAppError("ExecuteIncomingWebhook", "app.incoming_webhook.bot.app_error", nil, err.Error(), http.StatusBadRequest)
+	}
+
+	post := &model.Post{
+		UserId:   hook.BotUserId,
+		TeamId:   hook.TeamId,
+		Title:    req.Title,
+		Content:  req.Content,
+		Tags:     req.Tags,
+		ParentId: req.ParentId,
+	}
+
+	if req.Type == model.POST_TYPE_QUESTION {
+		var group *model.UserGroup
+		if hook.GroupId != "" {
+			if group, err = a.GetGroup(hook.GroupId); err != nil {
+				return nil, err
+			}
+		}
+
+		return a.CreateQuestion(post, group)
+	}
+
+	// 他のteamの投稿に回答・コメントできないようにする
+	parent, err := a.Store().Post().GetSingle(req.ParentId, false)
+	if err != nil {
+		return nil, err
+	}
+	if parent.TeamId != hook.TeamId {
+		return nil, model.NewAppError("ExecuteIncomingWebhook", "app.incoming_webhook.parent_team_mismatch.app_error", nil, "parent_id="+req.ParentId, http.StatusBadRequest)
+	}
+
+	if req.Type == model.POST_TYPE_ANSWER {
+		return a.CreateAnswer(post)
+	}
+
+	return a.CreateComment(post)
+}
@@ -0,0 +1,86 @@
+package app
+
+import (
+	"net/http"
+
+	"github.com/clear-ness/qa-discussion/mlog"
+	"github.com/clear-ness/qa-discussion/model"
+)
+
+func (a *App) GetJob(jobId string) (*model.Job, *model.AppError) {
+	return a.Store().Job().Get(jobId)
+}
+
+// 同じチームで同じ種類のjobは同時に1つしか実行しない
+func (a *App) createTeamJob(jobType string, teamId string, userId string, data map[string]string) (*model.Job, *model.AppError) {
+	count, err := a.Store().Job().GetActiveCountByTeamAndType(teamId, jobType)
+	if err != nil {
+		return nil, err
+	}
+
+	if count > 0 {
+		return nil, model.NewAppError("createTeamJob", "app.job.create.already_running.app_error", nil, "team_id="+teamId+", type="+jobType, http.StatusConflict)
+	}
+
+	job := &model.Job{
+		Type:   jobType,
+		TeamId: teamId,
+		UserId: userId,
+		Data:   data,
+	}
+
+	return a.Store().Job().Save(job)
+}
+
+func (a *App) setJobInProgress(job *model.Job) *model.AppError {
+	job.Status = model.JOB_STATUS_IN_PROGRESS
+	job.StartAt = model.GetMillis()
+	job.Progress = 0
+
+	_, err := a.Store().Job().Update(job)
+	return err
+}
+
+func (a *App) setJobProgress(job *model.Job, progress int64) {
+	job.Progress = progress
+
+	if _, err := a.Store().Job().Update(job); err != nil {
+		mlog.Error("Failed to update job progress", mlog.String("job_id", job.Id), mlog.Err(err))
+	}
+}
+
+func (a *App) setJobSuccess(job *model.Job) {
+	job.Status = model.JOB_STATUS_SUCCESS
+	job.Progress = 100
+
+	if _, err := a.Store().Job().Update(job); err != nil {
+		mlog.Error("Failed to set job success", mlog.String("job_id", job.Id), mlog.Err(err))
+	}
+
+	a.recordJobRun(job)
+}
+
+func (a *App) setJobError(job *model.Job, jobErr *model.AppError) {
+	mlog.Error("Job failed", mlog.String("job_id", job.Id), mlog.String("type", job.Type), mlog.Err(jobErr))
+
+	job.Status = model.JOB_STATUS_ERROR
+	job.Data[model.JOB_DATA_ERROR] = jobErr.Error()
+
+	if _, err := a.Store().Job().Update(job); err != nil {
+		mlog.Error("Failed to set job error", mlog.String("job_id", job.Id), mlog.Err(err))
+	}
+
+	a.recordJobRun(job)
+}
+
+func (a *App) recordJobRun(job *model.Job) {
+	if a.Metrics() == nil {
+		return
+	}
+
+	a.Metrics().IncrementJobRun(job.Type, job.Status)
+	if job.StartAt > 0 {
+		elapsed := float64(model.GetMillis()-job.StartAt) / 1000
+		a.Metrics().ObserveJobDuration(job.Type, elapsed)
+	}
+}
@@ -12,7 +12,7 @@ import (
 
 func (a *App) GetUserForLogin(loginId string) (*model.User, *model.AppError) {
 
-	if user, err := a.Srv.Store.User().GetForLogin(loginId); err == nil {
+	if user, err := a.Store().User().GetForLogin(loginId); err == nil {
 		return user, nil
 	}
 
@@ -51,6 +51,7 @@ func (a *App) DoLogin(w http.ResponseWriter, r *http.Request, user *model.User)
 	session.AddProp(model.SESSION_PROP_PLATFORM, plat)
 	session.AddProp(model.SESSION_PROP_OS, os)
 	session.AddProp(model.SESSION_PROP_BROWSER, fmt.Sprintf("%v/%v", bname, bversion))
+	session.AddProp(model.SESSION_PROP_IP, a.IpAddress)
 
 	var err *model.AppError
 	if session, err = a.CreateSession(session); err != nil {
@@ -0,0 +1,88 @@
+package app
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/clear-ness/qa-discussion/mlog"
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/clear-ness/qa-discussion/services/metrics"
+)
+
+func (a *App) Metrics() metrics.MetricsInterface {
+	return a.Srv.Metrics
+}
+
+// 計測が無効な場合はs.Metricsをnilのままにする
+func (s *Server) initMetrics() {
+	if !*s.Config().MetricsSettings.Enable {
+		return
+	}
+
+	s.Metrics = metrics.NewMetrics()
+}
+
+// Jobテーブルを介さない定期処理の実行結果を記録する
+func (s *Server) recordJobRun(jobType string, start time.Time, err error) {
+	if s.Metrics == nil {
+		return
+	}
+
+	status := model.JOB_STATUS_SUCCESS
+	if err != nil {
+		status = model.JOB_STATUS_ERROR
+	}
+
+	s.Metrics.IncrementJobRun(jobType, status)
+	s.Metrics.ObserveJobDuration(jobType, float64(time.Since(start))/float64(time.Second))
+}
+
+// APIとは別のアドレスで/metricsを公開する
+func (s *Server) StartMetricsServer() error {
+	if s.Metrics == nil {
+		return nil
+	}
+
+	router := mux.NewRouter()
+	router.Handle("/metrics", s.Metrics.Handler())
+
+	listener, err := net.Listen("tcp", *s.Config().MetricsSettings.ListenAddress)
+	if err != nil {
+		return err
+	}
+
+	s.metricsServer = &http.Server{
+		Handler:      router,
+		ReadTimeout:  30 * time.Second,
+		WriteTimeout: 30 * time.Second,
+	}
+
+	server := s.metricsServer
+	go func() {
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			mlog.Error("Error starting metrics server", mlog.Err(err))
+		}
+	}()
+
+	mlog.Info("Metrics server is listening", mlog.String("address", listener.Addr().String()))
+
+	return nil
+}
+
+func (s *Server) StopMetricsServer() {
+	if s.metricsServer == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), TIME_TO_WAIT_FOR_CONNECTIONS_TO_CLOSE_ON_SERVER_SHUTDOWN)
+	defer cancel()
+
+	if err := s.metricsServer.Shutdown(ctx); err != nil {
+		mlog.Warn("Unable to shutdown metrics server", mlog.Err(err))
+	}
+	s.metricsServer = nil
+}
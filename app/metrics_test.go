@@ -0,0 +1,54 @@
+package app
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/clear-ness/qa-discussion/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsDisabled(t *testing.T) {
+	th := Setup(t)
+	defer th.TearDown()
+
+	require.False(t, *th.Server.Config().MetricsSettings.Enable)
+	require.Nil(t, th.Server.Metrics)
+	assert.Nil(t, th.App.Metrics())
+
+	// 計測が無効でも記録の呼び出しで落ちない
+	th.Server.recordJobRun("webhooks_history_retention", time.Now(), errors.New("failed"))
+	th.App.recordJobRun(&model.Job{Type: model.JOB_TYPE_EXPORT_TEAM, Status: model.JOB_STATUS_SUCCESS, StartAt: model.GetMillis()})
+
+	require.NoError(t, th.Server.StartMetricsServer())
+	assert.Nil(t, th.Server.metricsServer)
+	th.Server.StopMetricsServer()
+}
+
+func TestMetricsEnabled(t *testing.T) {
+	th := Setup(t)
+	defer th.TearDown()
+
+	th.UpdateConfig(func(cfg *model.Config) {
+		*cfg.MetricsSettings.Enable = true
+	})
+	th.Server.initMetrics()
+	require.NotNil(t, th.App.Metrics())
+
+	th.Server.recordJobRun("webhooks_history_retention", time.Now(), errors.New("failed"))
+	th.App.recordJobRun(&model.Job{Type: model.JOB_TYPE_EXPORT_TEAM, Status: model.JOB_STATUS_SUCCESS, StartAt: model.GetMillis()})
+
+	recorder := httptest.NewRecorder()
+	th.App.Metrics().Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	body := recorder.Body.String()
+	assert.Contains(t, body, `qa_discussion_jobs_runs_total{status="error",type="webhooks_history_retention"} 1`)
+	assert.Contains(t, body, `qa_discussion_jobs_runs_total{status="success",type="`+model.JOB_TYPE_EXPORT_TEAM+`"} 1`)
+	assert.Contains(t, body, `qa_discussion_jobs_duration_seconds_count{type="webhooks_history_retention"} 1`)
+}
@@ -1,40 +1,98 @@
 package app
 
 import (
+	"net/http"
+
+	"github.com/clear-ness/qa-discussion/db"
+	"github.com/clear-ness/qa-discussion/mlog"
 	"github.com/clear-ness/qa-discussion/model"
 )
 
-func (a *App) TagsMigration() {
+// Goで書くデータの移行。適用したものはSystemsテーブルに名前を記録し、以降の起動では実行しない。
+// 複数台が同時に起動すると同じ移行が重ねて実行されうるので、何度実行しても同じ結果になるように書く
+type dataMigration struct {
+	Name string
+	Run  func(a *App) *model.AppError
+}
+
+// 追加する時は末尾に足し、適用済みの名前は変えない
+var dataMigrations = []dataMigration{
+	{Name: "SystemTags", Run: (*App).TagsMigration},
+}
+
+func (a *App) TagsMigration() *model.AppError {
 	options := &model.GetTagsOptions{TeamId: "", Type: model.TAG_TYPE_SYSTEM}
 
 	var count int64
 	var err *model.AppError
-	if count, err = a.Srv.Store.Tag().GetTagsCount(options); err != nil {
-		return
+	if count, err = a.Store().Tag().GetTagsCount(options); err != nil {
+		return err
 	}
 
 	curTime := model.GetMillis()
 
 	if count <= 0 {
 		systemTags := []string{model.SYSTEM_TAG_FIRST_POSTS, model.SYSTEM_TAG_LATE_ANSWERS}
-		if err := a.Srv.Store.Tag().CreateTags(systemTags, curTime, "", model.TAG_TYPE_SYSTEM); err != nil {
-			return
+		if err := a.Store().Tag().CreateTags(systemTags, curTime, "", model.TAG_TYPE_SYSTEM); err != nil {
+			return err
 		}
 	}
 
 	options = &model.GetTagsOptions{TeamId: "", Type: model.TAG_TYPE_REVIEW}
-	if count, err = a.Srv.Store.Tag().GetTagsCount(options); err != nil {
-		return
+	if count, err = a.Store().Tag().GetTagsCount(options); err != nil {
+		return err
 	}
 
 	if count <= 0 {
 		reviewTags := []string{model.REVIEW_TAG_ABUSE, model.REVIEW_TAG_SPAM, model.REVIEW_TAG_DUPLICATE, model.REVIEW_TAG_INVALID_CONTENT, model.REVIEW_TAG_LOW_QUALITY}
-		if err := a.Srv.Store.Tag().CreateTags(reviewTags, curTime, "", model.TAG_TYPE_REVIEW); err != nil {
-			return
+		if err := a.Store().Tag().CreateTags(reviewTags, curTime, "", model.TAG_TYPE_REVIEW); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// 未適用のデータ移行を順に実行する。失敗したらそこで止め、次の起動でやり直す
+func (a *App) RunDataMigrations() *model.AppError {
+	for _, migration := range dataMigrations {
+		name := model.SYSTEM_DATA_MIGRATION_PREFIX + migration.Name
+
+		if _, err := a.Store().System().GetByName(name); err == nil {
+			continue
+		} else if err.StatusCode != http.StatusNotFound {
+			return err
 		}
+
+		if err := migration.Run(a); err != nil {
+			return err
+		}
+
+		if err := a.Store().System().SaveOrUpdate(&model.System{Name: name, Value: "true"}); err != nil {
+			return err
+		}
+
+		mlog.Info("Applied data migration", mlog.String("migration", migration.Name))
 	}
+
+	return nil
 }
 
 func (a *App) InitMigrations() {
-	a.TagsMigration()
+	if err := a.RunDataMigrations(); err != nil {
+		mlog.Error("Failed to run data migrations", mlog.Err(err))
+	}
+}
+
+// 未適用のスキーマのマイグレーションを適用する。
+// 複数台が同時に起動しても、DBのadvisory lockで1台ずつ適用される
+func migrateSchema(settings *model.SqlSettings) error {
+	migrator, err := db.NewMigrator(settings)
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+
+	_, err = migrator.Up()
+	return err
 }
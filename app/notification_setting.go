@@ -7,7 +7,7 @@ import (
 )
 
 func (a *App) GetNotificationSettingForUser(userId string) (*model.NotificationSetting, *model.AppError) {
-	res, err := a.Srv.Store.NotificationSetting().Get(userId)
+	res, err := a.Store().NotificationSetting().Get(userId)
 	if err != nil {
 		return nil, err
 	}
@@ -16,7 +16,7 @@ func (a *App) GetNotificationSettingForUser(userId string) (*model.NotificationS
 }
 
 func (a *App) UpdateNotificationSettingForUser(userId string, inboxInterval string) *model.AppError {
-	if err := a.Srv.Store.NotificationSetting().Save(userId, inboxInterval); err != nil {
+	if err := a.Store().NotificationSetting().Save(userId, inboxInterval); err != nil {
 		err.StatusCode = http.StatusBadRequest
 		return err
 	}
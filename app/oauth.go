@@ -12,11 +12,11 @@ import (
 func (a *App) CreateOAuthApp(app *model.OAuthApp) (*model.OAuthApp, *model.AppError) {
 	app.ClientSecret = model.NewId()
 
-	return a.Srv.Store.OAuth().SaveApp(app)
+	return a.Store().OAuth().SaveApp(app)
 }
 
 func (a *App) GetOAuthApp(appId string) (*model.OAuthApp, *model.AppError) {
-	return a.Srv.Store.OAuth().GetApp(appId)
+	return a.Store().OAuth().GetApp(appId)
 }
 
 func (a *App) UpdateOauthApp(oldApp, updatedApp *model.OAuthApp) (*model.OAuthApp, *model.AppError) {
@@ -25,15 +25,15 @@ func (a *App) UpdateOauthApp(oldApp, updatedApp *model.OAuthApp) (*model.OAuthAp
 	updatedApp.CreateAt = oldApp.CreateAt
 	updatedApp.ClientSecret = oldApp.ClientSecret
 
-	return a.Srv.Store.OAuth().UpdateApp(updatedApp)
+	return a.Store().OAuth().UpdateApp(updatedApp)
 }
 
 func (a *App) GetOAuthAppsByUserId(userId string, page, perPage int) ([]*model.OAuthApp, *model.AppError) {
-	return a.Srv.Store.OAuth().GetAppByUserId(userId, page*perPage, perPage)
+	return a.Store().OAuth().GetAppByUserId(userId, page*perPage, perPage)
 }
 
 func (a *App) DeleteOAuthApp(appId string) *model.AppError {
-	if err := a.Srv.Store.OAuth().DeleteApp(appId); err != nil {
+	if err := a.Store().OAuth().DeleteApp(appId); err != nil {
 		return err
 	}
 
@@ -42,7 +42,7 @@ func (a *App) DeleteOAuthApp(appId string) *model.AppError {
 
 func (a *App) RegenerateOAuthAppSecret(app *model.OAuthApp) (*model.OAuthApp, *model.AppError) {
 	app.ClientSecret = model.NewId()
-	if _, err := a.Srv.Store.OAuth().UpdateApp(app); err != nil {
+	if _, err := a.Store().OAuth().UpdateApp(app); err != nil {
 		return nil, err
 	}
 
@@ -50,7 +50,7 @@ func (a *App) RegenerateOAuthAppSecret(app *model.OAuthApp) (*model.OAuthApp, *m
 }
 
 func (a *App) GetAuthorizedAppsForUser(userId string, page, perPage int) ([]*model.OAuthApp, *model.AppError) {
-	apps, err := a.Srv.Store.OAuth().GetAuthorizedApps(userId, page*perPage, perPage)
+	apps, err := a.Store().OAuth().GetAuthorizedApps(userId, page*perPage, perPage)
 	if err != nil {
 		return nil, err
 	}
@@ -66,15 +66,15 @@ func (a *App) GetAuthorizedAppsForUser(userId string, page, perPage int) ([]*mod
 func (a *App) RevokeAccessToken(token string) *model.AppError {
 	schan := make(chan *model.AppError, 1)
 	go func() {
-		schan <- a.Srv.Store.Session().Remove(token)
+		schan <- a.Store().Session().Remove(token)
 		close(schan)
 	}()
 
-	if _, err := a.Srv.Store.OAuth().GetAccessData(token); err != nil {
+	if _, err := a.Store().OAuth().GetAccessData(token); err != nil {
 		return model.NewAppError("RevokeAccessToken", "api.oauth.revoke_access_token.get.app_error", nil, "", http.StatusBadRequest)
 	}
 
-	if err := a.Srv.Store.OAuth().RemoveAccessData(token); err != nil {
+	if err := a.Store().OAuth().RemoveAccessData(token); err != nil {
 		return model.NewAppError("RevokeAccessToken", "api.oauth.revoke_access_token.del_token.app_error", nil, "", http.StatusInternalServerError)
 	}
 
@@ -90,7 +90,7 @@ func (a *App) AllowOAuthAppAccessToUser(userId string, authRequest *model.Author
 		authRequest.Scope = model.DEFAULT_SCOPE
 	}
 
-	oauthApp, err := a.Srv.Store.OAuth().GetApp(authRequest.ClientId)
+	oauthApp, err := a.Store().OAuth().GetApp(authRequest.ClientId)
 	if err != nil {
 		return "", err
 	}
@@ -120,7 +120,7 @@ func (a *App) AllowOAuthAppAccessToUser(userId string, authRequest *model.Author
 		Scope:    authRequest.Scope,
 	}
 
-	if err = a.Srv.Store.OAuth().SaveAuthorizedApp(authorizedApp); err != nil {
+	if err = a.Store().OAuth().SaveAuthorizedApp(authorizedApp); err != nil {
 		return authRequest.RedirectUri + "?error=server_error&state=" + authRequest.State, nil
 	}
 
@@ -131,7 +131,7 @@ func (a *App) GetOAuthCodeRedirect(userId string, authRequest *model.AuthorizeRe
 	authData := &model.AuthData{UserId: userId, ClientId: authRequest.ClientId, CreateAt: model.GetMillis(), RedirectUri: authRequest.RedirectUri, State: authRequest.State, Scope: authRequest.Scope}
 	authData.Code = model.NewId() + model.NewId()
 
-	if _, err := a.Srv.Store.OAuth().SaveAuthData(authData); err != nil {
+	if _, err := a.Store().OAuth().SaveAuthData(authData); err != nil {
 		return authRequest.RedirectUri + "?error=server_error&state=" + authRequest.State, nil
 	}
 
@@ -172,7 +172,7 @@ func (a *App) GetOAuthAccessTokenForImplicitFlow(userId string, authRequest *mod
 
 	accessData := &model.AccessData{ClientId: authRequest.ClientId, UserId: user.Id, Token: session.Token, RefreshToken: "", RedirectUri: authRequest.RedirectUri, ExpiresAt: session.ExpiresAt, Scope: authRequest.Scope}
 
-	if _, err := a.Srv.Store.OAuth().SaveAccessData(accessData); err != nil {
+	if _, err := a.Store().OAuth().SaveAccessData(accessData); err != nil {
 		return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.internal_saving.app_error", nil, "", http.StatusInternalServerError)
 	}
 
@@ -188,7 +188,7 @@ func (a *App) newSession(appName string, user *model.User) (*model.Session, *mod
 	session.AddProp(model.SESSION_PROP_OS, "OAuth2")
 	session.AddProp(model.SESSION_PROP_BROWSER, "OAuth2")
 
-	session, err := a.Srv.Store.Session().Save(session)
+	session, err := a.Store().Session().Save(session)
 	if err != nil {
 		return nil, model.NewAppError("newSession", "api.oauth.get_access_token.internal_session.app_error", nil, "", http.StatusInternalServerError)
 	}
@@ -198,7 +198,7 @@ func (a *App) newSession(appName string, user *model.User) (*model.Session, *mod
 
 func (a *App) GetOAuthAccessTokenForCodeFlow(clientId, grantType, redirectUri, code, secret, refreshToken string) (*model.AccessResponse, *model.AppError) {
 	// clientId はOAuthApps.Idカラムに相当する。
-	oauthApp, err := a.Srv.Store.OAuth().GetApp(clientId)
+	oauthApp, err := a.Store().OAuth().GetApp(clientId)
 	if err != nil {
 		return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.credentials.app_error", nil, "", http.StatusNotFound)
 	}
@@ -213,13 +213,13 @@ func (a *App) GetOAuthAccessTokenForCodeFlow(clientId, grantType, redirectUri, c
 
 	if grantType == model.ACCESS_TOKEN_GRANT_TYPE {
 		var authData *model.AuthData
-		authData, err = a.Srv.Store.OAuth().GetAuthData(code)
+		authData, err = a.Store().OAuth().GetAuthData(code)
 		if err != nil {
 			return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.expired_code.app_error", nil, "", http.StatusBadRequest)
 		}
 
 		if authData.IsExpired() {
-			a.Srv.Store.OAuth().RemoveAuthData(authData.Code)
+			a.Store().OAuth().RemoveAuthData(authData.Code)
 			return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.expired_code.app_error", nil, "", http.StatusForbidden)
 		}
 
@@ -227,12 +227,12 @@ func (a *App) GetOAuthAccessTokenForCodeFlow(clientId, grantType, redirectUri, c
 			return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.redirect_uri.app_error", nil, "", http.StatusBadRequest)
 		}
 
-		user, err = a.Srv.Store.User().Get(authData.UserId)
+		user, err = a.Store().User().Get(authData.UserId)
 		if err != nil {
 			return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.internal_user.app_error", nil, "", http.StatusNotFound)
 		}
 
-		accessData, err = a.Srv.Store.OAuth().GetPreviousAccessData(user.Id, clientId)
+		accessData, err = a.Store().OAuth().GetPreviousAccessData(user.Id, clientId)
 		if err != nil {
 			return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.internal.app_error", nil, "", http.StatusBadRequest)
 		}
@@ -266,7 +266,7 @@ func (a *App) GetOAuthAccessTokenForCodeFlow(clientId, grantType, redirectUri, c
 			// session.TokenをaccessData.Tokenとして使う。
 			accessData = &model.AccessData{ClientId: clientId, UserId: user.Id, Token: session.Token, RefreshToken: model.NewId(), RedirectUri: redirectUri, ExpiresAt: session.ExpiresAt, Scope: authData.Scope}
 
-			if _, err = a.Srv.Store.OAuth().SaveAccessData(accessData); err != nil {
+			if _, err = a.Store().OAuth().SaveAccessData(accessData); err != nil {
 				return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.internal_saving.app_error", nil, "", http.StatusInternalServerError)
 			}
 
@@ -278,15 +278,15 @@ func (a *App) GetOAuthAccessTokenForCodeFlow(clientId, grantType, redirectUri, c
 			}
 		}
 
-		a.Srv.Store.OAuth().RemoveAuthData(authData.Code)
+		a.Store().OAuth().RemoveAuthData(authData.Code)
 	} else {
 		// when grantType is refresh_token
-		accessData, err = a.Srv.Store.OAuth().GetAccessDataByRefreshToken(refreshToken)
+		accessData, err = a.Store().OAuth().GetAccessDataByRefreshToken(refreshToken)
 		if err != nil {
 			return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.refresh_token.app_error", nil, "", http.StatusNotFound)
 		}
 
-		user, err := a.Srv.Store.User().Get(accessData.UserId)
+		user, err := a.Store().User().Get(accessData.UserId)
 		if err != nil {
 			return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.internal_user.app_error", nil, "", http.StatusNotFound)
 		}
@@ -304,7 +304,7 @@ func (a *App) GetOAuthAccessTokenForCodeFlow(clientId, grantType, redirectUri, c
 
 func (a *App) newSessionUpdateToken(appName string, accessData *model.AccessData, user *model.User) (*model.AccessResponse, *model.AppError) {
 	// remove the previous session
-	a.Srv.Store.Session().Remove(accessData.Token)
+	a.Store().Session().Remove(accessData.Token)
 
 	session, err := a.newSession(appName, user)
 	if err != nil {
@@ -314,7 +314,7 @@ func (a *App) newSessionUpdateToken(appName string, accessData *model.AccessData
 	accessData.RefreshToken = model.NewId()
 	accessData.ExpiresAt = session.ExpiresAt
 
-	if _, err := a.Srv.Store.OAuth().UpdateAccessData(accessData); err != nil {
+	if _, err := a.Store().OAuth().UpdateAccessData(accessData); err != nil {
 		return nil, model.NewAppError("newSessionUpdateToken", "web.get_access_token.internal_saving.app_error", nil, "", http.StatusInternalServerError)
 	}
 
@@ -332,7 +332,7 @@ func (a *App) DeauthorizeOAuthAppForUser(userId, appId string) *model.AppError {
 	// Revoke app sessions
 	// accessDataは(clientId,userId)ペアがユニークキー。
 	// ここでは0個または1個取得出来る。
-	accessData, err := a.Srv.Store.OAuth().GetAccessDataByUserForApp(userId, appId)
+	accessData, err := a.Store().OAuth().GetAccessDataByUserForApp(userId, appId)
 	if err != nil {
 		return err
 	}
@@ -345,12 +345,12 @@ func (a *App) DeauthorizeOAuthAppForUser(userId, appId string) *model.AppError {
 		}
 
 		// トークンの一致するaccessDataを削除
-		if err := a.Srv.Store.OAuth().RemoveAccessData(ad.Token); err != nil {
+		if err := a.Store().OAuth().RemoveAccessData(ad.Token); err != nil {
 			return err
 		}
 	}
 
-	if err := a.Srv.Store.OAuth().DeleteAuthorizedApp(userId, appId); err != nil {
+	if err := a.Store().OAuth().DeleteAuthorizedApp(userId, appId); err != nil {
 		return err
 	}
 
@@ -0,0 +1,214 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/clear-ness/qa-discussion/mlog"
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/clear-ness/qa-discussion/services/openid"
+	"github.com/clear-ness/qa-discussion/store"
+)
+
+const (
+	TOKEN_TYPE_OPENID = "openid"
+
+	OPENID_STATE_EXPIRY_TIME = 1000 * 60 * 10 // 10 minutes
+	OPENID_NONCE_SIZE        = 32
+
+	OPENID_COMPLETE_PATH = "/login/openid/complete"
+)
+
+type openIdStateData struct {
+	Nonce      string
+	RedirectTo string
+}
+
+// 設定が変わった場合はdiscoveryやJWKSのキャッシュごと作り直す
+func (a *App) getOpenIdProvider() (*openid.Provider, *model.AppError) {
+	settings := &a.Config().OpenIdSettings
+	if !*settings.Enable {
+		return nil, model.NewAppError("getOpenIdProvider", "api.openid.disabled.app_error", nil, "", http.StatusNotImplemented)
+	}
+
+	a.Srv.openIdProviderLock.Lock()
+	defer a.Srv.openIdProviderLock.Unlock()
+
+	if a.Srv.openIdProvider == nil || !a.Srv.openIdProvider.Matches(settings) {
+		// IdPはシステム管理者が設定するものなので、内部ネットワークへの通信も許可する
+		a.Srv.openIdProvider = openid.NewProvider(settings, a.HttpService.MakeClient(true))
+	}
+
+	return a.Srv.openIdProvider, nil
+}
+
+func (a *App) getOpenIdRedirectUri() string {
+	return strings.TrimSuffix(a.GetSiteURL(), "/") + OPENID_COMPLETE_PATH
+}
+
+// stateとしてtokenを保存し、IdPの認可エンドポイントへのURLを返す
+func (a *App) GetOpenIdLoginURL(redirectTo string) (string, string, *model.AppError) {
+	provider, err := a.getOpenIdProvider()
+	if err != nil {
+		return "", "", err
+	}
+
+	stateData := openIdStateData{
+		Nonce:      model.NewRandomString(OPENID_NONCE_SIZE),
+		RedirectTo: redirectTo,
+	}
+	jsonData, jsonErr := json.Marshal(stateData)
+	if jsonErr != nil {
+		return "", "", model.NewAppError("GetOpenIdLoginURL", "api.openid.create_state.app_error", nil, jsonErr.Error(), http.StatusInternalServerError)
+	}
+
+	token := model.NewToken(TOKEN_TYPE_OPENID, string(jsonData))
+	if err := a.Store().Token().Save(token); err != nil {
+		return "", "", err
+	}
+
+	authUrl, err := provider.AuthCodeURL(a.getOpenIdRedirectUri(), *a.Config().OpenIdSettings.Scope, token.Token, stateData.Nonce)
+	if err != nil {
+		return "", "", err
+	}
+
+	return authUrl, token.Token, nil
+}
+
+func (a *App) getOpenIdState(state string) (*openIdStateData, *model.AppError) {
+	token, err := a.Store().Token().GetByToken(state)
+	if err != nil {
+		return nil, model.NewAppError("getOpenIdState", "api.openid.complete.invalid_state.app_error", nil, err.Error(), http.StatusBadRequest)
+	}
+
+	// stateは一度しか使えない
+	if err := a.DeleteToken(token); err != nil {
+		mlog.Error("Failed to delete token", mlog.Err(err))
+	}
+
+	if token.Type != TOKEN_TYPE_OPENID {
+		return nil, model.NewAppError("getOpenIdState", "api.openid.complete.invalid_state.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if model.GetMillis()-token.CreateAt >= OPENID_STATE_EXPIRY_TIME {
+		return nil, model.NewAppError("getOpenIdState", "api.openid.complete.state_expired.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	stateData := &openIdStateData{}
+	if err := json.Unmarshal([]byte(token.Extra), stateData); err != nil {
+		return nil, model.NewAppError("getOpenIdState", "api.openid.complete.state_parse.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	return stateData, nil
+}
+
+// IdPから戻ってきたcodeを検証し、ログイン対象のユーザーとログイン後の遷移先を返す
+func (a *App) CompleteOpenIdLogin(code, state string) (*model.User, string, *model.AppError) {
+	provider, err := a.getOpenIdProvider()
+	if err != nil {
+		return nil, "", err
+	}
+
+	stateData, err := a.getOpenIdState(state)
+	if err != nil {
+		return nil, "", err
+	}
+
+	tokenResponse, err := provider.Exchange(code, a.getOpenIdRedirectUri())
+	if err != nil {
+		return nil, "", err
+	}
+
+	claims, err := provider.VerifyIdToken(tokenResponse.IdToken, stateData.Nonce)
+	if err != nil {
+		return nil, "", err
+	}
+
+	user, err := a.getOrCreateOpenIdUser(claims)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := checkUserNotDisabled(user); err != nil {
+		return nil, "", err
+	}
+
+	return user, stateData.RedirectTo, nil
+}
+
+func (a *App) getOrCreateOpenIdUser(claims openid.Claims) (*model.User, *model.AppError) {
+	settings := &a.Config().OpenIdSettings
+
+	authData := claims.GetString(*settings.IdClaim)
+	if len(authData) == 0 || len(authData) > model.USER_AUTH_DATA_MAX_LENGTH {
+		return nil, model.NewAppError("getOrCreateOpenIdUser", "api.openid.complete.invalid_id_claim.app_error", nil, "", http.StatusUnauthorized)
+	}
+
+	user, err := a.Store().User().GetByAuth(&authData, model.USER_AUTH_SERVICE_OPENID)
+	if err == nil {
+		return user, nil
+	}
+	if err.Id != store.MISSING_AUTH_ACCOUNT_ERROR {
+		return nil, err
+	}
+
+	email := model.NormalizeEmail(claims.GetString(*settings.EmailClaim))
+	if !model.IsValidEmail(email) {
+		return nil, model.NewAppError("getOrCreateOpenIdUser", "api.openid.complete.invalid_email.app_error", nil, "", http.StatusUnauthorized)
+	}
+
+	// IdP側で未確認のメールアドレスは信用しない
+	if _, ok := claims["email_verified"]; ok && !claims.GetBool("email_verified") {
+		return nil, model.NewAppError("getOrCreateOpenIdUser", "api.openid.complete.email_not_verified.app_error", nil, "", http.StatusUnauthorized)
+	}
+
+	// 既存のパスワードユーザーを乗っ取られないよう、自動で紐付けはしない
+	if _, err := a.Store().User().GetByEmail(email); err == nil {
+		return nil, model.NewAppError("getOrCreateOpenIdUser", "api.openid.complete.email_taken.app_error", nil, "", http.StatusConflict)
+	}
+
+	username := model.NormalizeUsername(claims.GetString(*settings.UsernameClaim))
+	if !model.IsValidUsername(username) {
+		// PreSaveでランダムなusernameが採番される
+		username = ""
+	}
+
+	newUser := &model.User{
+		Username:      username,
+		Email:         email,
+		EmailVerified: true,
+		AuthService:   model.USER_AUTH_SERVICE_OPENID,
+		AuthData:      &authData,
+	}
+
+	ruser, err := a.CreateNormalUser(newUser)
+	if err != nil {
+		return nil, err
+	}
+
+	if *settings.AutoJoinTeams {
+		a.joinUserToAllowedDomainTeams(ruser)
+	}
+
+	return ruser, nil
+}
+
+// AllowedDomainsがメールアドレスに一致するチームへ参加させる
+func (a *App) joinUserToAllowedDomainTeams(user *model.User) {
+	teams, err := a.Store().Team().GetAllWithAllowedDomains()
+	if err != nil {
+		mlog.Error("Failed to get teams for auto join", mlog.String("user_id", user.Id), mlog.Err(err))
+		return
+	}
+
+	for _, team := range teams {
+		if !a.isTeamEmailAllowed(user, team) {
+			continue
+		}
+
+		if err := a.JoinUserToTeam(team, user, false); err != nil {
+			mlog.Error("Failed to auto join user to team", mlog.String("user_id", user.Id), mlog.String("team_id", team.Id), mlog.Err(err))
+		}
+	}
+}
@@ -3,11 +3,32 @@ package app
 import (
 	"github.com/pkg/errors"
 
+	"github.com/clear-ness/qa-discussion/config"
 	"github.com/clear-ness/qa-discussion/store"
 )
 
 type Option func(s *Server) error
 
+// dsnはファイルのパスまたは mysql:// で始まるDBの接続文字列
+func Config(dsn string, watch bool) Option {
+	return func(s *Server) error {
+		configStore, err := config.NewStore(dsn, watch)
+		if err != nil {
+			return errors.Wrap(err, "failed to apply Config option")
+		}
+
+		s.configStore = configStore
+		return nil
+	}
+}
+
+func ConfigStore(configStore config.Store) Option {
+	return func(s *Server) error {
+		s.configStore = configStore
+		return nil
+	}
+}
+
 func StoreOverride(override interface{}) Option {
 	return func(s *Server) error {
 		switch o := override.(type) {
@@ -41,6 +62,25 @@ func ServerConnector(s *Server) AppOption {
 	}
 }
 
+// 管理コマンド用。webhookの配信や閲覧数の書き出し、クラスタのsubscribe、メトリクスの待ち受けを始めず、
+// 起動中のサーバーとポートや定期処理が重ならないようにする
+func SkipBackgroundJobs() Option {
+	return func(s *Server) error {
+		s.skipBackgroundJobs = true
+		return nil
+	}
+}
+
+// テスト用。キャッシュ層や検索層で包まずにstoreをそのまま使い、RedisやESなしで動かす
+func SkipStoreLayers() Option {
+	return func(s *Server) error {
+		s.newStore = func() store.Store {
+			return s.sqlStore
+		}
+		return nil
+	}
+}
+
 func InitEmailBatching(interval *string) Option {
 	return func(s *Server) error {
 		if s.EmailBatching == nil {
@@ -11,15 +11,15 @@ import (
 )
 
 func (a *App) GetSinglePost(postId string, includeDeleted bool) (*model.Post, *model.AppError) {
-	return a.Srv.Store.Post().GetSingle(postId, includeDeleted)
+	return a.Store().Post().GetSingle(postId, includeDeleted)
 }
 
 func (a *App) GetSinglePostByType(postId string, postType string) (*model.Post, *model.AppError) {
-	return a.Srv.Store.Post().GetSingleByType(postId, postType)
+	return a.Store().Post().GetSingleByType(postId, postType)
 }
 
 func (a *App) GetPostCount(userId string, postType string, teamId string) (int64, *model.AppError) {
-	return a.Srv.Store.Post().GetPostCount(postType, userId, teamId, 0, 0)
+	return a.Store().Post().GetPostCount(postType, userId, teamId, 0, 0)
 }
 
 func (a *App) CreateQuestion(post *model.Post, group *model.UserGroup) (*model.Post, *model.AppError) {
@@ -59,7 +59,7 @@ func (a *App) CreateQuestion(post *model.Post, group *model.UserGroup) (*model.P
 		DeleteAt:    0,
 	}
 
-	rpost, err := a.Srv.Store.Post().SaveQuestion(post)
+	rpost, err := a.Store().Post().SaveQuestion(post)
 	if err != nil {
 		mlog.Error("Couldn't save the question", mlog.Err(err))
 		return nil, err
@@ -75,7 +75,7 @@ func (a *App) CreateQuestion(post *model.Post, group *model.UserGroup) (*model.P
 		curTime := model.GetMillis()
 		var messages []*model.InboxMessage
 
-		members, err := a.GetGroupMembersPage(group.Id, "", 0, model.GROUP_MEMBER_SEARCH_DEFAULT_LIMIT)
+		members, _, err := a.GetGroupMembersPage(group.Id, "", 0, model.GROUP_MEMBER_SEARCH_DEFAULT_LIMIT, nil)
 		for _, member := range *members {
 			message := &model.InboxMessage{
 				Content:    content,
@@ -93,7 +93,7 @@ func (a *App) CreateQuestion(post *model.Post, group *model.UserGroup) (*model.P
 			messages = append(messages, message)
 		}
 
-		_, err = a.Srv.Store.InboxMessage().SaveMultipleInboxMessages(messages)
+		_, err = a.Store().InboxMessage().SaveMultipleInboxMessages(messages)
 		if err != nil {
 			return nil, model.NewAppError("saveInboxMessagesForComment", "api.post.save_inbox_messages_for_comment.save_multiple_inbox_messages.app_error", nil, err.Error(), http.StatusInternalServerError)
 		}
@@ -118,16 +118,19 @@ func (a *App) PublishInboxMessages(messages []*model.InboxMessage) {
 }
 
 func (a *App) tryWebhook(post *model.Post, user *model.User) {
-	a.Srv.Go(func() {
-		team, err := a.Srv.Store.Team().Get(post.TeamId)
-		if err != nil || team.DeleteAt > 0 {
-			return
-		}
+	var event string
+	switch post.Type {
+	case model.POST_TYPE_QUESTION:
+		event = model.WEBHOOK_EVENT_QUESTION_CREATED
+	case model.POST_TYPE_ANSWER:
+		event = model.WEBHOOK_EVENT_ANSWER_CREATED
+	case model.POST_TYPE_COMMENT:
+		event = model.WEBHOOK_EVENT_COMMENT_CREATED
+	default:
+		return
+	}
 
-		if err := a.handleWebhookEvents(post, team, user); err != nil {
-			mlog.Error(err.Error())
-		}
-	})
+	a.triggerPostWebhookEvent(event, post, user.Id)
 }
 
 func (a *App) CreateAnswer(post *model.Post) (*model.Post, *model.AppError) {
@@ -135,7 +138,7 @@ func (a *App) CreateAnswer(post *model.Post) (*model.Post, *model.AppError) {
 		return nil, model.NewAppError("CreateAnswer", "api.post.create_answer.parent.app_error", nil, "", http.StatusBadRequest)
 	}
 
-	parentQuestion, err := a.Srv.Store.Post().GetSingleByType(post.ParentId, model.POST_TYPE_QUESTION)
+	parentQuestion, err := a.Store().Post().GetSingleByType(post.ParentId, model.POST_TYPE_QUESTION)
 	if err != nil {
 		mlog.Error("Couldn't save the answer", mlog.Err(err))
 		return nil, err
@@ -176,7 +179,7 @@ func (a *App) CreateAnswer(post *model.Post) (*model.Post, *model.AppError) {
 		DeleteAt:    0,
 	}
 
-	_, err = a.Srv.Store.Post().SaveAnswer(post)
+	_, err = a.Store().Post().SaveAnswer(post)
 	if err != nil {
 		mlog.Error("Couldn't save the answer", mlog.Err(err))
 		return nil, err
@@ -202,7 +205,7 @@ func (a *App) CreateAnswer(post *model.Post) (*model.Post, *model.AppError) {
 		CreateAt:   curTime,
 	}
 
-	_, err = a.Srv.Store.InboxMessage().SaveInboxMessage(message)
+	_, err = a.Store().InboxMessage().SaveInboxMessage(message)
 	if err != nil {
 		return nil, model.NewAppError("CreateAnswer", "api.post.create_answer.save_inbox_message.app_error", nil, err.Error(), http.StatusInternalServerError)
 	}
@@ -217,7 +220,7 @@ func (a *App) CreateAnswer(post *model.Post) (*model.Post, *model.AppError) {
 }
 
 func (a *App) CreateComment(post *model.Post) (*model.Post, *model.AppError) {
-	parent, err := a.Srv.Store.Post().GetSingle(post.ParentId, false)
+	parent, err := a.Store().Post().GetSingle(post.ParentId, false)
 	if err != nil {
 		mlog.Error("Couldn't save the comment", mlog.Err(err))
 		return nil, err
@@ -263,13 +266,13 @@ func (a *App) CreateComment(post *model.Post) (*model.Post, *model.AppError) {
 		DeleteAt:    0,
 	}
 
-	rpost, err := a.Srv.Store.Post().SaveComment(post)
+	rpost, err := a.Store().Post().SaveComment(post)
 	if err != nil {
 		mlog.Error("Couldn't save the comment", mlog.Err(err))
 		return nil, err
 	}
 
-	rpost, err = a.Srv.Store.Post().GetSingle(rpost.Id, false)
+	rpost, err = a.Store().Post().GetSingle(rpost.Id, false)
 	if err != nil {
 		mlog.Error("Couldn't get post for inbox messages", mlog.Err(err))
 		return rpost, nil
@@ -290,7 +293,7 @@ func (a *App) CreateComment(post *model.Post) (*model.Post, *model.AppError) {
 
 // users can comment reply to participants of a comment thread or the author of the post
 func (a *App) saveInboxMessagesForComment(post *model.Post, forceInformAuthor bool) *model.AppError {
-	parent, err := a.Srv.Store.Post().GetSingle(post.ParentId, false)
+	parent, err := a.Store().Post().GetSingle(post.ParentId, false)
 	if err != nil {
 		return model.NewAppError("saveInboxMessagesForComment", "api.post.save_inbox_messages_for_comment.get_single.app_error", nil, err.Error(), http.StatusInternalServerError)
 	}
@@ -298,7 +301,7 @@ func (a *App) saveInboxMessagesForComment(post *model.Post, forceInformAuthor bo
 		return model.NewAppError("saveInboxMessagesForComment", "api.post.save_inbox_messages_for_comment.get_single.app_error", nil, "", http.StatusInternalServerError)
 	}
 
-	root, err := a.Srv.Store.Post().GetSingle(post.RootId, false)
+	root, err := a.Store().Post().GetSingle(post.RootId, false)
 	if err != nil {
 		return model.NewAppError("saveInboxMessagesForComment", "api.post.save_inbox_messages_for_comment.get_single.app_error", nil, err.Error(), http.StatusInternalServerError)
 	}
@@ -333,7 +336,7 @@ func (a *App) saveInboxMessagesForComment(post *model.Post, forceInformAuthor bo
 		}
 		message.Type = model.INBOX_MESSAGE_TYPE_COMMENT
 		message.UserId = parent.UserId
-		_, err := a.Srv.Store.InboxMessage().SaveInboxMessage(message)
+		_, err := a.Store().InboxMessage().SaveInboxMessage(message)
 		if err != nil {
 			return model.NewAppError("saveInboxMessagesForComment", "api.post.save_inbox_messages_for_comment.save_inbox_message.app_error", nil, err.Error(), http.StatusInternalServerError)
 		}
@@ -343,7 +346,7 @@ func (a *App) saveInboxMessagesForComment(post *model.Post, forceInformAuthor bo
 		return nil
 	}
 
-	commentsForPost, err := a.Srv.Store.Post().GetCommentsForPost(parent.Id, model.POST_COMMENT_LIMIT)
+	commentsForPost, err := a.Store().Post().GetCommentsForPost(parent.Id, model.POST_COMMENT_LIMIT)
 	if err != nil {
 		return model.NewAppError("saveInboxMessagesForComment", "api.post.save_inbox_messages_for_comment.get_comments.app_error", nil, err.Error(), http.StatusInternalServerError)
 	}
@@ -421,7 +424,7 @@ func (a *App) saveInboxMessagesForComment(post *model.Post, forceInformAuthor bo
 		messages = append(messages, message)
 	}
 
-	_, err = a.Srv.Store.InboxMessage().SaveMultipleInboxMessages(messages)
+	_, err = a.Store().InboxMessage().SaveMultipleInboxMessages(messages)
 	if err != nil {
 		return model.NewAppError("saveInboxMessagesForComment", "api.post.save_inbox_messages_for_comment.save_multiple_inbox_messages.app_error", nil, err.Error(), http.StatusInternalServerError)
 	}
@@ -465,10 +468,23 @@ func (a *App) GetPostWithMetadata(postId string) (*model.Post, *model.AppError)
 	return posts[0], nil
 }
 
-func (a *App) GetPosts(options *model.GetPostsOptions, getComments bool, getParent bool, checkVoted bool, limitContent bool) (model.Posts, int64, *model.AppError) {
-	posts, totalCount, err := a.Srv.Store.Post().GetPosts(options, true)
+func (a *App) GetPosts(options *model.GetPostsOptions, getComments bool, getParent bool, checkVoted bool, limitContent bool) (model.Posts, int64, model.CursorPage, *model.AppError) {
+	posts, totalCount, err := a.Store().Post().GetPosts(options, true)
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, model.CursorPage{}, err
+	}
+
+	var cursorPage model.CursorPage
+	if options.SortType == model.POST_SORT_TYPE_RELEVANCE {
+		offset := options.Page * options.PerPage
+		if options.Cursor != nil {
+			offset = options.Cursor.Offset
+		}
+		cursorPage = model.NewOffsetCursorPage(len(posts), options.PerPage, offset, model.CURSOR_TYPE_POSTS, options.SortType)
+	} else {
+		cursorPage = model.NewCursorPage(len(posts), options.PerPage, options.Cursor, options.Page > 0, func(i int) *model.Cursor {
+			return model.NewCursor(model.CURSOR_TYPE_POSTS, options.SortType, posts[i].SortKey(options.SortType), posts[i].Id)
+		})
 	}
 
 	option := model.SetPostMetadataOptions{
@@ -479,13 +495,13 @@ func (a *App) GetPosts(options *model.GetPostsOptions, getComments bool, getPare
 	}
 	posts, err = a.SetPostMetadata(posts, option)
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, model.CursorPage{}, err
 	}
 
 	if checkVoted {
 		posts, err = a.CheckVoted(posts)
 		if err != nil {
-			return nil, 0, err
+			return nil, 0, model.CursorPage{}, err
 		}
 	}
 
@@ -493,7 +509,7 @@ func (a *App) GetPosts(options *model.GetPostsOptions, getComments bool, getPare
 		posts.LimitContentLength()
 	}
 
-	return posts, totalCount, err
+	return posts, totalCount, cursorPage, nil
 }
 
 func (a *App) CheckVoted(posts model.Posts) (model.Posts, *model.AppError) {
@@ -577,7 +593,7 @@ func (a *App) GetCommentsAndCommentUserForPosts(postIds []string) (map[string][]
 	userIdsMaps := map[string]bool{}
 
 	for _, postId := range postIds {
-		commentsForPost, err := a.Srv.Store.Post().GetCommentsForPost(postId, model.POST_COMMENT_LIMIT)
+		commentsForPost, err := a.Store().Post().GetCommentsForPost(postId, model.POST_COMMENT_LIMIT)
 		if err != nil {
 			return nil, err
 		}
@@ -644,7 +660,7 @@ func (a *App) SearchPosts(terms string, sortType string, getParent bool, limitCo
 		return nil, int64(0), err
 	}
 
-	posts, totalCount, err := a.Srv.Store.Post().SearchPosts(finalParamsList, sortType, page, perPage, teamId)
+	posts, totalCount, err := a.Store().Post().SearchPosts(finalParamsList, sortType, page, perPage, teamId)
 	if err != nil {
 		return nil, int64(0), err
 	}
@@ -668,7 +684,7 @@ func (a *App) SearchPosts(terms string, sortType string, getParent bool, limitCo
 }
 
 func (a *App) UpdatePost(post *model.Post) (*model.Post, *model.AppError) {
-	oldPost, err := a.Srv.Store.Post().GetSingle(post.Id, false)
+	oldPost, err := a.Store().Post().GetSingle(post.Id, false)
 	if err != nil {
 		return nil, err
 	}
@@ -718,13 +734,17 @@ func (a *App) UpdatePost(post *model.Post) (*model.Post, *model.AppError) {
 		newPost.EditAt = model.GetMillis()
 	}
 
-	rpost, err := a.Srv.Store.Post().Update(newPost, oldPost)
+	rpost, err := a.Store().Post().Update(newPost, oldPost)
 	if err != nil {
 		return nil, err
 	}
 
+	if edited {
+		a.triggerPostWebhookEvent(model.WEBHOOK_EVENT_POST_EDITED, rpost, a.Session.UserId)
+	}
+
 	if edited && rpost.Type == model.POST_TYPE_COMMENT {
-		rpost, err = a.Srv.Store.Post().GetSingle(rpost.Id, false)
+		rpost, err = a.Store().Post().GetSingle(rpost.Id, false)
 		if err != nil {
 			mlog.Error("Couldn't get post for inbox messages", mlog.Err(err))
 			return rpost, nil
@@ -744,7 +764,7 @@ func (a *App) DeletePost(post *model.Post, deleteByID string) (*model.Post, *mod
 	switch post.Type {
 	case model.POST_TYPE_QUESTION:
 		// with one more child posts, then prevent deleting the post
-		count, err := a.Srv.Store.Post().GetChildPostsCount(post.Id)
+		count, err := a.Store().Post().GetChildPostsCount(post.Id)
 		if err != nil {
 			return nil, err
 		}
@@ -752,11 +772,11 @@ func (a *App) DeletePost(post *model.Post, deleteByID string) (*model.Post, *mod
 			return nil, model.NewAppError("DeletePost", "api.post.delete_question.child.app_error", nil, "", http.StatusInternalServerError)
 		}
 
-		if err := a.Srv.Store.Post().DeleteQuestion(post.Id, model.GetMillis(), deleteByID); err != nil {
+		if err := a.Store().Post().DeleteQuestion(post.Id, model.GetMillis(), deleteByID); err != nil {
 			return nil, err
 		}
 	case model.POST_TYPE_ANSWER:
-		count, err := a.Srv.Store.Post().GetChildPostsCount(post.Id)
+		count, err := a.Store().Post().GetChildPostsCount(post.Id)
 		if err != nil {
 			return nil, err
 		}
@@ -764,7 +784,7 @@ func (a *App) DeletePost(post *model.Post, deleteByID string) (*model.Post, *mod
 			return nil, model.NewAppError("DeletePost", "api.post.delete_answer.child.app_error", nil, "", http.StatusInternalServerError)
 		}
 
-		parent, err := a.Srv.Store.Post().GetSingleByType(post.ParentId, model.POST_TYPE_QUESTION)
+		parent, err := a.Store().Post().GetSingleByType(post.ParentId, model.POST_TYPE_QUESTION)
 		if err != nil {
 			return nil, err
 		}
@@ -772,55 +792,106 @@ func (a *App) DeletePost(post *model.Post, deleteByID string) (*model.Post, *mod
 			return nil, model.NewAppError("DeletePost", "api.post.delete_answer.parent.app_error", nil, "", http.StatusInternalServerError)
 		}
 
-		if err := a.Srv.Store.Post().DeleteAnswer(post.Id, model.GetMillis(), deleteByID); err != nil {
+		if err := a.Store().Post().DeleteAnswer(post.Id, model.GetMillis(), deleteByID); err != nil {
 			return nil, err
 		}
 	case model.POST_TYPE_COMMENT:
-		if err := a.Srv.Store.Post().DeleteComment(post.Id, model.GetMillis(), deleteByID); err != nil {
+		if err := a.Store().Post().DeleteComment(post.Id, model.GetMillis(), deleteByID); err != nil {
 			return nil, err
 		}
 	default:
 		return nil, model.NewAppError("DeletePost", "api.post.delete.type.app_error", nil, "", http.StatusInternalServerError)
 	}
 
+	background := a.Detached()
 	a.Srv.Go(func() {
-		a.DeletePostFiles(post)
+		background.DeletePostFiles(post)
 	})
 
+	a.triggerPostWebhookEvent(model.WEBHOOK_EVENT_POST_DELETED, post, deleteByID)
+
 	return post, nil
 }
 
 func (a *App) DeletePostForcely(post *model.Post, deleteByID string) (*model.Post, *model.AppError) {
 	switch post.Type {
 	case model.POST_TYPE_QUESTION:
-		if err := a.Srv.Store.Post().DeleteQuestion(post.Id, model.GetMillis(), deleteByID); err != nil {
+		if err := a.Store().Post().DeleteQuestion(post.Id, model.GetMillis(), deleteByID); err != nil {
 			return nil, err
 		}
 	case model.POST_TYPE_ANSWER:
-		if err := a.Srv.Store.Post().DeleteAnswer(post.Id, model.GetMillis(), deleteByID); err != nil {
+		if err := a.Store().Post().DeleteAnswer(post.Id, model.GetMillis(), deleteByID); err != nil {
 			return nil, err
 		}
 	case model.POST_TYPE_COMMENT:
-		if err := a.Srv.Store.Post().DeleteComment(post.Id, model.GetMillis(), deleteByID); err != nil {
+		if err := a.Store().Post().DeleteComment(post.Id, model.GetMillis(), deleteByID); err != nil {
 			return nil, err
 		}
 	default:
 		return nil, model.NewAppError("DeletePost", "api.post.delete.type.app_error", nil, "", http.StatusInternalServerError)
 	}
 
+	background := a.Detached()
 	a.Srv.Go(func() {
-		a.DeletePostFiles(post)
+		background.DeletePostFiles(post)
 	})
 
+	a.triggerPostWebhookEvent(model.WEBHOOK_EVENT_POST_DELETED, post, deleteByID)
+
 	return post, nil
 }
 
+// 削除した投稿を元に戻す。削除時に消した添付ファイルは戻らない
+func (a *App) RestorePost(postId string) (*model.Post, *model.AppError) {
+	if err := a.Store().Post().RestorePost(postId, model.GetMillis()); err != nil {
+		return nil, err
+	}
+
+	return a.Store().Post().GetSingle(postId, false)
+}
+
 func (a *App) SelectBestAnswer(postId, bestId string) *model.AppError {
-	return a.Srv.Store.Post().SelectBestAnswer(postId, bestId)
+	if err := a.Store().Post().SelectBestAnswer(postId, bestId); err != nil {
+		return err
+	}
+
+	if answer, err := a.Store().Post().GetSingle(bestId, false); err == nil {
+		a.triggerWebhookEvent(answer.TeamId, model.WEBHOOK_EVENT_BEST_ANSWER_SELECTED, a.Session.UserId, &model.WebhookBestAnswerData{
+			QuestionId: postId,
+			AnswerId:   bestId,
+			AuthorId:   answer.UserId,
+		})
+	}
+
+	return nil
+}
+
+// 投票で点数が上がった結果、閾値に届いた場合に通知する。
+// 取り消し等で閾値を上下しても、届いた時にだけ送る。
+func (a *App) checkVoteThreshold(post *model.Post, userId string) {
+	if post.TeamId == "" {
+		return
+	}
+
+	updated, err := a.Store().Post().GetSingle(post.Id, false)
+	if err != nil {
+		return
+	}
+
+	for _, threshold := range model.WEBHOOK_VOTE_THRESHOLDS {
+		if post.Points < threshold && updated.Points >= threshold {
+			a.triggerWebhookEvent(updated.TeamId, model.WEBHOOK_EVENT_VOTE_THRESHOLD_CROSSED, userId, &model.WebhookVoteThresholdData{
+				PostId:    updated.Id,
+				PostType:  updated.Type,
+				Points:    updated.Points,
+				Threshold: threshold,
+			})
+		}
+	}
 }
 
 func (a *App) UpVotePost(postId string, userId string) *model.AppError {
-	post, err := a.Srv.Store.Post().GetSingle(postId, false)
+	post, err := a.Store().Post().GetSingle(postId, false)
 	if err != nil {
 		mlog.Error("Couldn't upvote the post", mlog.Err(err))
 		return err
@@ -847,16 +918,18 @@ func (a *App) UpVotePost(postId string, userId string) *model.AppError {
 		return model.NewAppError("UpVotePost", "api.post.upvote.user_suspending.app_error", nil, "", http.StatusBadRequest)
 	}
 
-	_, err = a.Srv.Store.Post().UpVotePost(postId, userId)
+	_, err = a.Store().Post().UpVotePost(postId, userId)
 	if err != nil {
 		return err
 	}
 
+	a.checkVoteThreshold(post, userId)
+
 	return nil
 }
 
 func (a *App) CancelUpVotePost(postId string, userId string) *model.AppError {
-	post, err := a.Srv.Store.Post().GetSingle(postId, false)
+	post, err := a.Store().Post().GetSingle(postId, false)
 	if err != nil {
 		mlog.Error("Couldn't cancel upvote the post", mlog.Err(err))
 		return err
@@ -883,7 +956,7 @@ func (a *App) CancelUpVotePost(postId string, userId string) *model.AppError {
 		return model.NewAppError("CancelUpVotePost", "api.post.cancel_upvote.user_suspending.app_error", nil, "", http.StatusBadRequest)
 	}
 
-	_, err = a.Srv.Store.Post().CancelUpVotePost(postId, userId)
+	_, err = a.Store().Post().CancelUpVotePost(postId, userId)
 	if err != nil {
 		return err
 	}
@@ -892,7 +965,7 @@ func (a *App) CancelUpVotePost(postId string, userId string) *model.AppError {
 }
 
 func (a *App) DownVotePost(postId string, userId string) *model.AppError {
-	post, err := a.Srv.Store.Post().GetSingle(postId, false)
+	post, err := a.Store().Post().GetSingle(postId, false)
 	if err != nil {
 		mlog.Error("Couldn't downvote the post", mlog.Err(err))
 		return err
@@ -919,7 +992,7 @@ func (a *App) DownVotePost(postId string, userId string) *model.AppError {
 		return model.NewAppError("DownVotePost", "api.post.downvote.user_suspending.app_error", nil, "", http.StatusBadRequest)
 	}
 
-	_, err = a.Srv.Store.Post().DownVotePost(postId, userId)
+	_, err = a.Store().Post().DownVotePost(postId, userId)
 	if err != nil {
 		return err
 	}
@@ -928,7 +1001,7 @@ func (a *App) DownVotePost(postId string, userId string) *model.AppError {
 }
 
 func (a *App) CancelDownVotePost(postId string, userId string) *model.AppError {
-	post, err := a.Srv.Store.Post().GetSingle(postId, false)
+	post, err := a.Store().Post().GetSingle(postId, false)
 	if err != nil {
 		mlog.Error("Couldn't cancel cancel downvote the post", mlog.Err(err))
 		return err
@@ -955,16 +1028,18 @@ func (a *App) CancelDownVotePost(postId string, userId string) *model.AppError {
 		return model.NewAppError("CancelDownVotePost", "api.post.cancel_downvote.user_suspending.app_error", nil, "", http.StatusBadRequest)
 	}
 
-	_, err = a.Srv.Store.Post().CancelDownVotePost(postId, userId)
+	_, err = a.Store().Post().CancelDownVotePost(postId, userId)
 	if err != nil {
 		return err
 	}
 
+	a.checkVoteThreshold(post, userId)
+
 	return nil
 }
 
 func (a *App) FlagPost(postId string, userId string) *model.AppError {
-	post, err := a.Srv.Store.Post().GetSingle(postId, false)
+	post, err := a.Store().Post().GetSingle(postId, false)
 	if err != nil {
 		mlog.Error("Couldn't flag the post", mlog.Err(err))
 		return err
@@ -974,7 +1049,7 @@ func (a *App) FlagPost(postId string, userId string) *model.AppError {
 		return model.NewAppError("FlagPost", "api.post.flag.get.app_error", nil, "", http.StatusInternalServerError)
 	}
 
-	_, err = a.Srv.Store.Post().FlagPost(postId, userId)
+	_, err = a.Store().Post().FlagPost(postId, userId)
 	if err != nil {
 		return err
 	}
@@ -983,7 +1058,7 @@ func (a *App) FlagPost(postId string, userId string) *model.AppError {
 }
 
 func (a *App) CancelFlagPost(postId string, userId string) *model.AppError {
-	post, err := a.Srv.Store.Post().GetSingle(postId, false)
+	post, err := a.Store().Post().GetSingle(postId, false)
 	if err != nil {
 		mlog.Error("Couldn't cancel flag the post", mlog.Err(err))
 		return err
@@ -992,7 +1067,7 @@ func (a *App) CancelFlagPost(postId string, userId string) *model.AppError {
 		return model.NewAppError("CancelFlagPost", "api.post.cancel_flag.get.app_error", nil, "", http.StatusInternalServerError)
 	}
 
-	_, err = a.Srv.Store.Post().CancelFlagPost(postId, userId)
+	_, err = a.Store().Post().CancelFlagPost(postId, userId)
 	if err != nil {
 		return err
 	}
@@ -1001,7 +1076,7 @@ func (a *App) CancelFlagPost(postId string, userId string) *model.AppError {
 }
 
 func (a *App) LockPost(postId string, userId string) *model.AppError {
-	post, err := a.Srv.Store.Post().GetSingle(postId, false)
+	post, err := a.Store().Post().GetSingle(postId, false)
 	if err != nil {
 		mlog.Error("Couldn't lock the post", mlog.Err(err))
 		return err
@@ -1020,11 +1095,17 @@ func (a *App) LockPost(postId string, userId string) *model.AppError {
 		return model.NewAppError("LockPost", "api.post.lock.team.app_error", nil, "", http.StatusBadRequest)
 	}
 
-	return a.Srv.Store.Post().LockPost(postId, model.GetMillis(), userId)
+	if err := a.Store().Post().LockPost(postId, model.GetMillis(), userId); err != nil {
+		return err
+	}
+
+	a.triggerPostWebhookEvent(model.WEBHOOK_EVENT_POST_LOCKED, post, userId)
+
+	return nil
 }
 
 func (a *App) CancelLockPost(postId string, userId string) *model.AppError {
-	post, err := a.Srv.Store.Post().GetSingle(postId, false)
+	post, err := a.Store().Post().GetSingle(postId, false)
 	if err != nil {
 		mlog.Error("Couldn't cancel lock the post", mlog.Err(err))
 		return err
@@ -1047,11 +1128,17 @@ func (a *App) CancelLockPost(postId string, userId string) *model.AppError {
 		return model.NewAppError("CancelLockPost", "api.post.cancel_lock.team.app_error", nil, "", http.StatusBadRequest)
 	}
 
-	return a.Srv.Store.Post().CancelLockPost(postId, userId)
+	if err := a.Store().Post().CancelLockPost(postId, userId); err != nil {
+		return err
+	}
+
+	a.triggerPostWebhookEvent(model.WEBHOOK_EVENT_POST_UNLOCKED, post, userId)
+
+	return nil
 }
 
 func (a *App) ProtectPost(postId string, userId string) *model.AppError {
-	post, err := a.Srv.Store.Post().GetSingle(postId, false)
+	post, err := a.Store().Post().GetSingle(postId, false)
 	if err != nil {
 		mlog.Error("Couldn't protect the post", mlog.Err(err))
 		return err
@@ -1070,11 +1157,17 @@ func (a *App) ProtectPost(postId string, userId string) *model.AppError {
 		return model.NewAppError("ProtectPost", "api.post.protect.team.app_error", nil, "", http.StatusBadRequest)
 	}
 
-	return a.Srv.Store.Post().ProtectPost(postId, model.GetMillis(), userId)
+	if err := a.Store().Post().ProtectPost(postId, model.GetMillis(), userId); err != nil {
+		return err
+	}
+
+	a.triggerPostWebhookEvent(model.WEBHOOK_EVENT_POST_PROTECTED, post, userId)
+
+	return nil
 }
 
 func (a *App) CancelProtectPost(postId string, userId string) *model.AppError {
-	post, err := a.Srv.Store.Post().GetSingle(postId, false)
+	post, err := a.Store().Post().GetSingle(postId, false)
 	if err != nil {
 		mlog.Error("Couldn't cancel protect the post", mlog.Err(err))
 		return err
@@ -1093,32 +1186,42 @@ func (a *App) CancelProtectPost(postId string, userId string) *model.AppError {
 		return model.NewAppError("CancelProtectPost", "api.post.cancel_protect.team.app_error", nil, "", http.StatusBadRequest)
 	}
 
-	return a.Srv.Store.Post().CancelProtectPost(postId, userId)
+	if err := a.Store().Post().CancelProtectPost(postId, userId); err != nil {
+		return err
+	}
+
+	a.triggerPostWebhookEvent(model.WEBHOOK_EVENT_POST_UNPROTECTED, post, userId)
+
+	return nil
 }
 
 func (a *App) DeletePostFiles(post *model.Post) {
-	if _, err := a.Srv.Store.FileInfo().DeleteForPost(post.Id); err != nil {
+	if _, err := a.Store().FileInfo().DeleteForPost(post.Id); err != nil {
 		mlog.Warn("Encountered error when deleting files for post", mlog.String("post_id", post.Id), mlog.Err(err))
 	}
 }
 
 func (a *App) ViewPost(post *model.Post, userId string, ipAddress string) *model.AppError {
-	return a.Srv.Store.Post().ViewPost(post.Id, post.TeamId, userId, ipAddress, 1)
+	if isBotUserAgent(a.UserAgent) {
+		return nil
+	}
+
+	return a.Store().Post().ViewPost(post.Id, post.TeamId, userId, ipAddress, 1)
 }
 
 func (a *App) RelatedPosts(post *model.Post) ([]*model.RelatedPostSearchResult, *model.AppError) {
 	term := post.Title + " " + post.Tags
 
-	return a.Srv.Store.Post().RelatedSearch(term, 10)
+	return a.Store().Post().RelatedSearch(term, 10)
 }
 
 func (a *App) HotPosts(interval string, teamId string) (model.Posts, *model.AppError) {
-	postIds, err := a.Srv.Store.Post().HotSearch(interval, teamId, searchlayer.HOT_POST_SEARCH_MAX_COUNT*2)
+	postIds, err := a.Store().Post().HotSearch(interval, teamId, searchlayer.HOT_POST_SEARCH_MAX_COUNT*2)
 	if err != nil {
 		return nil, model.NewAppError("HotPosts", "api.post.hot_posts.hot_search.app_error", nil, "", http.StatusInternalServerError)
 	}
 
-	posts, err := a.Srv.Store.Post().GetPostsByIds(postIds)
+	posts, err := a.Store().Post().GetPostsByIds(postIds)
 	if err != nil {
 		return nil, err
 	}
@@ -1136,9 +1239,9 @@ func (a *App) HotPosts(interval string, teamId string) (model.Posts, *model.AppE
 }
 
 func (a *App) GetCurrentRevisionForPost(postId string, teamId string) (int64, *model.AppError) {
-	return a.Srv.Store.Post().GetCurrentRevisionForPost(postId, teamId)
+	return a.Store().Post().GetCurrentRevisionForPost(postId, teamId)
 }
 
 func (a *App) GetRevisionPost(postId string, teamId string, offset int) (*model.Post, *model.AppError) {
-	return a.Srv.Store.Post().GetRevisionPost(postId, teamId, offset)
+	return a.Store().Post().GetRevisionPost(postId, teamId, offset)
 }
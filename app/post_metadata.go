@@ -59,7 +59,7 @@ func (a *App) SetPostMetadata(posts model.Posts, options model.SetPostMetadataOp
 			parentIds = append(parentIds, key)
 		}
 
-		parents, err := a.Srv.Store.Post().GetPostsByIds(parentIds)
+		parents, err := a.Store().Post().GetPostsByIds(parentIds)
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,82 @@
+package app
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/clear-ness/qa-discussion/mlog"
+	"github.com/clear-ness/qa-discussion/model"
+)
+
+const (
+	POST_VIEWS_FLUSH_BATCH_SIZE = 500
+)
+
+// Redisに溜めた閲覧数を定期的にPosts.ViewsとPostViewsHistoryに書き出す。
+// 書き出しはロックを取れた1台のサーバーだけが行う。
+type PostViewsFlushWorker struct {
+	server  *Server
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+func NewPostViewsFlushWorker(s *Server) *PostViewsFlushWorker {
+	return &PostViewsFlushWorker{
+		server:  s,
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+}
+
+func (w *PostViewsFlushWorker) Start() {
+	go w.run()
+}
+
+func (w *PostViewsFlushWorker) Stop() {
+	close(w.stop)
+	<-w.stopped
+}
+
+func (w *PostViewsFlushWorker) run() {
+	defer close(w.stopped)
+
+	interval := time.Duration(*w.server.Config().PostViewsSettings.FlushIntervalSeconds) * time.Second
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			w.flush()
+		}
+	}
+}
+
+func (w *PostViewsFlushWorker) flush() {
+	start := time.Now()
+
+	count, err := w.server.Store.Post().FlushBufferedViews(POST_VIEWS_FLUSH_BATCH_SIZE)
+	if err != nil {
+		mlog.Error("Failed to flush post views", mlog.Err(err))
+		w.server.recordJobRun("post_views_flush", start, err)
+		return
+	}
+
+	if count > 0 {
+		mlog.Debug("Flushed post views", mlog.Int("posts", count))
+	}
+	w.server.recordJobRun("post_views_flush", start, nil)
+}
+
+// 新しい日付から1日ごとのユニーク閲覧数を返す。Redisに残っている日数まで遡れる
+func (a *App) GetPostDailyUniqueViews(postId string, days int) (model.Analytics, *model.AppError) {
+	maxDays := *a.Config().PostViewsSettings.RetentionDays
+	if days <= 0 || days > maxDays {
+		return nil, model.NewAppError("GetPostDailyUniqueViews", "app.post.get_daily_unique_views.days.app_error", map[string]interface{}{"Max": maxDays}, "days="+strconv.Itoa(days), http.StatusBadRequest)
+	}
+
+	return a.Store().Post().GetDailyUniqueViews(postId, days)
+}
@@ -117,3 +117,41 @@ func setRateLimitHeaders(w http.ResponseWriter, context throttled.RateLimitResul
 		w.Header().Add("Retry-After", strconv.Itoa(vi))
 	}
 }
+
+func (s *Server) GetRateLimiter() *RateLimiter {
+	s.rateLimiterLock.RLock()
+	defer s.rateLimiterLock.RUnlock()
+
+	return s.rateLimiter
+}
+
+// 設定に合わせてRateLimiterを作り直す。無効な場合はnilにする
+func (s *Server) configureRateLimiter(cfg *model.Config) error {
+	var rateLimiter *RateLimiter
+	if *cfg.RateLimitSettings.Enable {
+		mlog.Info("RateLimiter is enabled")
+
+		var err error
+		rateLimiter, err = NewRateLimiter(&cfg.RateLimitSettings, cfg.ServiceSettings.TrustedProxyIPHeader)
+		if err != nil {
+			return err
+		}
+	}
+
+	s.rateLimiterLock.Lock()
+	s.rateLimiter = rateLimiter
+	s.rateLimiterLock.Unlock()
+
+	return nil
+}
+
+func (s *Server) rateLimitHandler(wrappedHandler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if rateLimiter := s.GetRateLimiter(); rateLimiter != nil {
+			rateLimiter.RateLimitHandler(wrappedHandler).ServeHTTP(w, r)
+			return
+		}
+
+		wrappedHandler.ServeHTTP(w, r)
+	})
+}
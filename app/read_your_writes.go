@@ -0,0 +1,44 @@
+package app
+
+import (
+	"github.com/clear-ness/qa-discussion/mlog"
+	"github.com/clear-ness/qa-discussion/services/cache"
+	"github.com/clear-ness/qa-discussion/store"
+)
+
+const (
+	// 書き込んだユーザーをReadYourWritesSecondsの間だけ残す
+	READ_YOUR_WRITES_KEY_PREFIX = "read_your_writes:"
+)
+
+func (a *App) readYourWritesEnabled() bool {
+	settings := a.Config().SqlSettings
+	return len(settings.DataSourceReplicas) > 0 && *settings.ReadYourWritesSeconds > 0
+}
+
+// 書き込むリクエストならユーザーを記録する。記録が残っている間は、
+// レプリカの遅延で自分の書き込みが見えなくならないよう、そのユーザーの読み込みをmasterから行う。
+// 記録はredisに置くので、次のリクエストが別のサーバーに来ても効く。
+func (a *App) SetupReadYourWrites(write bool) {
+	userId := a.Session.UserId
+	if userId == "" || !a.readYourWritesEnabled() {
+		return
+	}
+
+	redis := cache.NewRedisBackendWithClient(a.Srv.RedisClient()).WithContext(a.Context())
+	key := READ_YOUR_WRITES_KEY_PREFIX + userId
+
+	if write {
+		// 記録に失敗しても、このリクエスト自体はmasterから読む
+		if err := redis.Set(key, 1, *a.Config().SqlSettings.ReadYourWritesSeconds); err != nil {
+			mlog.Warn("Failed to record a write for read-your-writes", mlog.String("user_id", userId), mlog.Err(err))
+		}
+	} else if _, err := redis.Get(key); err != nil {
+		// キーが無ければerrが返る
+		return
+	}
+
+	ctx := store.WithMasterReads(a.Context())
+	a.SetContext(ctx)
+	a.SetStore(a.Store().WithContext(ctx))
+}
@@ -0,0 +1,432 @@
+package app
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strings"
+
+	"github.com/clear-ness/qa-discussion/mlog"
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/clear-ness/qa-discussion/store"
+)
+
+const (
+	SAMPLE_DATA_EMAIL_DOMAIN = "sample.invalid"
+	// 負荷試験でログインできるように、全員に同じパスワードを設定する
+	SAMPLE_DATA_PASSWORD = "SampleData-1234"
+	// 生成したデータに実行ごとの接頭辞を残し、後から見分けられるようにする
+	SAMPLE_DATA_PROP = "sample_data"
+
+	sampleDataDayMillis = int64(24 * 60 * 60 * 1000)
+)
+
+var sampleDataFirstNames = []string{
+	"alice", "bob", "carol", "dave", "erin", "frank", "grace", "heidi", "ivan", "judy",
+	"kenji", "laura", "mallory", "naoko", "oscar", "peggy", "quentin", "rupert", "sybil", "taro",
+	"ursula", "victor", "wendy", "xavier", "yuki", "zoe",
+}
+
+var sampleDataTags = []string{
+	"golang", "python", "javascript", "typescript", "java", "rust", "ruby", "php", "kotlin", "swift",
+	"mysql", "postgresql", "redis", "elasticsearch", "mongodb", "sqlite", "kafka", "rabbitmq",
+	"docker", "kubernetes", "terraform", "ansible", "nginx", "linux", "bash", "git",
+	"react", "vue", "angular", "webpack", "css", "html", "http", "websocket", "grpc", "graphql",
+	"aws", "gcp", "azure", "oauth", "jwt", "testing", "performance", "concurrency", "regex", "json",
+}
+
+var sampleDataActions = []string{
+	"parse a large file", "handle timeouts", "retry failed requests", "paginate results", "cache responses",
+	"run migrations", "stream data", "limit concurrency", "log structured errors", "mock dependencies",
+	"upload files", "validate input", "configure connection pools", "deploy without downtime", "profile memory usage",
+}
+
+var sampleDataProblems = []string{
+	"a deadlock", "an out of memory error", "a connection refused error", "an unexpected nil value",
+	"a race condition", "a slow query", "a permission denied error", "an encoding problem",
+}
+
+var sampleDataSentences = []string{
+	"I have tried the approach described in the official documentation, but it does not seem to apply to my setup.",
+	"The problem only happens under load, so it is hard to reproduce locally.",
+	"Here is a minimal example that shows the behaviour I am seeing.",
+	"Restarting the service fixes it for a while, but it comes back after a few hours.",
+	"I would prefer a solution that does not require adding another dependency.",
+	"The same code works fine on my laptop but fails in the staging environment.",
+	"I checked the logs and there is nothing suspicious before the failure.",
+	"Any pointers to where I should start looking would be appreciated.",
+	"You need to close the resource explicitly, otherwise it is only released when the process exits.",
+	"This is a known limitation and the usual workaround is to split the work into smaller batches.",
+	"Setting an explicit timeout on the client solved exactly this for us.",
+	"Make sure the configuration is actually loaded, a typo in the key is silently ignored.",
+	"The default value changed in the latest release, which would explain the difference.",
+	"I would measure first before optimizing, the bottleneck is often somewhere else.",
+}
+
+var sampleDataComments = []string{
+	"Which version are you using?",
+	"Can you share the full error message?",
+	"This worked for me, thanks!",
+	"Have you tried running it with the debug flag enabled?",
+	"Note that this changed in the latest release.",
+	"Could you add the configuration you are using?",
+	"Same problem here, following.",
+	"Good point, I have updated the answer.",
+}
+
+type SampleDataOptions struct {
+	Seed  int64
+	Teams int
+	Users int
+	// 公開サイトと各チームに振り分ける質問の総数
+	Questions int
+	// 1投稿あたりの最大数
+	Answers  int
+	Comments int
+	Votes    int
+	// 使うタグの種類数
+	Tags int
+	// 作成日時を過去何日に散らすか
+	Days int
+}
+
+type SampleDataReport struct {
+	Prefix    string `json:"prefix"`
+	Password  string `json:"password"`
+	Teams     int    `json:"teams"`
+	Users     int    `json:"users"`
+	Questions int    `json:"questions"`
+	Answers   int    `json:"answers"`
+	Comments  int    `json:"comments"`
+	Votes     int    `json:"votes"`
+	Errors    int    `json:"errors"`
+}
+
+type sampleDataGenerator struct {
+	app    *App
+	store  store.Store
+	opts   *SampleDataOptions
+	rand   *rand.Rand
+	prefix string
+	now    int64
+	report *SampleDataReport
+
+	users []*model.User
+	// 空文字は公開サイト
+	members map[string][]*model.User
+	teamIds []string
+	tags    []string
+}
+
+// 負荷試験用に、チーム・ユーザー・質問・回答・コメント・投票・タグを生成する。
+// 実際の利用に近づけるため、投稿するユーザーやタグは一部に偏らせる。
+func (a *App) GenerateSampleData(opts *SampleDataOptions) (*SampleDataReport, *model.AppError) {
+	if opts.Teams < 0 || opts.Users < 2 || opts.Questions < 0 || opts.Answers < 0 || opts.Comments < 0 || opts.Votes < 0 || opts.Tags < 1 || opts.Days < 1 {
+		return nil, model.NewAppError("GenerateSampleData", "app.sample_data.options.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	tags := sampleDataTags
+	if opts.Tags < len(tags) {
+		tags = tags[:opts.Tags]
+	}
+
+	g := &sampleDataGenerator{
+		app: a,
+		// 書き込んだ直後の投稿を親として読むので、replicaの遅延を避ける
+		store:   a.Srv.Store.WithContext(store.WithMasterReads(a.Context())),
+		opts:    opts,
+		rand:    rand.New(rand.NewSource(opts.Seed)),
+		prefix:  model.NewId()[:6],
+		now:     model.GetMillis(),
+		members: make(map[string][]*model.User),
+		tags:    tags,
+	}
+	g.report = &SampleDataReport{Prefix: g.prefix, Password: SAMPLE_DATA_PASSWORD}
+
+	if err := g.createUsers(); err != nil {
+		return g.report, err
+	}
+
+	if err := g.createTeams(); err != nil {
+		return g.report, err
+	}
+
+	g.createPosts()
+
+	return g.report, nil
+}
+
+func (g *sampleDataGenerator) logError(kind string, err *model.AppError) {
+	g.report.Errors++
+	mlog.Warn("Failed to generate sample data", mlog.String("kind", kind), mlog.Err(err))
+}
+
+func (g *sampleDataGenerator) createUsers() *model.AppError {
+	// bcryptは遅いので一度だけハッシュ化して全員に使う
+	hashed := model.HashPassword(SAMPLE_DATA_PASSWORD)
+
+	for i := 0; i < g.opts.Users; i++ {
+		username := fmt.Sprintf("%s.%s.%d", sampleDataFirstNames[i%len(sampleDataFirstNames)], g.prefix, i)
+		user := &model.User{
+			Type:          model.USER_TYPE_NORMAL,
+			Username:      username,
+			Email:         username + "@" + SAMPLE_DATA_EMAIL_DOMAIN,
+			EmailVerified: true,
+			Props:         model.StringMap{SAMPLE_DATA_PROP: g.prefix},
+		}
+
+		user, err := g.store.User().Save(user)
+		if err != nil {
+			return err
+		}
+
+		if err := g.store.User().UpdatePassword(user.Id, hashed); err != nil {
+			return err
+		}
+
+		g.users = append(g.users, user)
+		g.report.Users++
+	}
+
+	g.teamIds = append(g.teamIds, "")
+	g.members[""] = g.users
+
+	return nil
+}
+
+// 各ユーザーは半分の確率で各チームに参加する。最初のメンバーをチーム管理者にする
+func (g *sampleDataGenerator) createTeams() *model.AppError {
+	maxUsers := *g.app.Config().TeamSettings.MaxUsersPerTeam
+
+	for i := 0; i < g.opts.Teams; i++ {
+		var members []*model.User
+		for _, index := range g.rand.Perm(len(g.users)) {
+			if len(members) >= maxUsers {
+				break
+			}
+			if len(members) < 2 || g.rand.Intn(2) == 0 {
+				members = append(members, g.users[index])
+			}
+		}
+
+		teamType := model.TEAM_TYPE_PRIVATE
+		if g.rand.Intn(2) == 0 {
+			teamType = model.TEAM_TYPE_PUBLIC
+		}
+
+		team, err := g.store.Team().Save(&model.Team{
+			Type:        teamType,
+			Name:        fmt.Sprintf("sample-%s-%d", g.prefix, i),
+			Description: "Sample team for load testing",
+			Email:       members[0].Email,
+		})
+		if err != nil {
+			return err
+		}
+
+		for j, user := range members {
+			memberType := model.TEAM_MEMBER_TYPE_NORMAL
+			if j == 0 {
+				memberType = model.TEAM_MEMBER_TYPE_ADMIN
+			}
+
+			member := &model.TeamMember{TeamId: team.Id, UserId: user.Id, Type: memberType}
+			if _, err := g.store.Team().SaveMember(member, maxUsers); err != nil {
+				return err
+			}
+		}
+
+		g.teamIds = append(g.teamIds, team.Id)
+		g.members[team.Id] = members
+		g.report.Teams++
+	}
+
+	return nil
+}
+
+// 少数のよく使われる要素に偏るように選ぶ
+func (g *sampleDataGenerator) skewedIndex(n int) int {
+	if n <= 1 {
+		return 0
+	}
+
+	return int(rand.NewZipf(g.rand, 1.1, 1, uint64(n-1)).Uint64())
+}
+
+func (g *sampleDataGenerator) pickUser(teamId string) *model.User {
+	members := g.members[teamId]
+	return members[g.skewedIndex(len(members))]
+}
+
+// 親の投稿日時から現在までの間で、親に近い日時ほど選ばれやすくする
+func (g *sampleDataGenerator) timeAfter(createAt int64) int64 {
+	span := g.now - createAt
+	if span <= 0 {
+		return g.now
+	}
+
+	r := g.rand.Float64()
+	return createAt + int64(r*r*float64(span))
+}
+
+func (g *sampleDataGenerator) sentences(min int, max int) string {
+	count := min + g.rand.Intn(max-min+1)
+	sentences := make([]string, count)
+	for i := range sentences {
+		sentences[i] = sampleDataSentences[g.rand.Intn(len(sampleDataSentences))]
+	}
+
+	return strings.Join(sentences, " ")
+}
+
+func (g *sampleDataGenerator) questionTags() []string {
+	count := 1 + g.rand.Intn(3)
+	tags := []string{}
+	seen := map[string]bool{}
+	for i := 0; i < count; i++ {
+		tag := g.tags[g.skewedIndex(len(g.tags))]
+		if !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+
+	return tags
+}
+
+func (g *sampleDataGenerator) questionTitle(tags []string) string {
+	action := sampleDataActions[g.rand.Intn(len(sampleDataActions))]
+	problem := sampleDataProblems[g.rand.Intn(len(sampleDataProblems))]
+
+	switch g.rand.Intn(4) {
+	case 0:
+		return fmt.Sprintf("How do I %s in %s?", action, tags[0])
+	case 1:
+		return fmt.Sprintf("Why does %s cause %s when I %s?", tags[0], problem, action)
+	case 2:
+		return fmt.Sprintf("Best way to %s with %s", action, tags[0])
+	default:
+		return fmt.Sprintf("Getting %s with %s", problem, tags[0])
+	}
+}
+
+func (g *sampleDataGenerator) createPosts() {
+	for i := 0; i < g.opts.Questions; i++ {
+		teamId := g.teamIds[g.rand.Intn(len(g.teamIds))]
+		if err := g.createQuestion(teamId); err != nil {
+			g.logError("question", err)
+		}
+	}
+}
+
+func (g *sampleDataGenerator) createQuestion(teamId string) *model.AppError {
+	tags := g.questionTags()
+
+	question, err := g.store.Post().SaveQuestion(&model.Post{
+		Type:     model.POST_TYPE_QUESTION,
+		UserId:   g.pickUser(teamId).Id,
+		TeamId:   teamId,
+		Title:    g.questionTitle(tags),
+		Content:  g.sentences(2, 5),
+		Tags:     strings.Join(tags, " "),
+		Props:    model.StringInterface{SAMPLE_DATA_PROP: g.prefix},
+		CreateAt: g.now - g.rand.Int63n(int64(g.opts.Days)*sampleDataDayMillis),
+	})
+	if err != nil {
+		return err
+	}
+	g.report.Questions++
+
+	g.createComments(question, question.Id, teamId)
+	g.createVotes(question, teamId)
+
+	var answers []*model.Post
+	for i := g.rand.Intn(g.opts.Answers + 1); i > 0; i-- {
+		answer, err := g.store.Post().SaveAnswer(&model.Post{
+			Type:     model.POST_TYPE_ANSWER,
+			RootId:   question.Id,
+			ParentId: question.Id,
+			UserId:   g.pickUser(teamId).Id,
+			TeamId:   teamId,
+			Content:  g.sentences(1, 4),
+			Props:    model.StringInterface{SAMPLE_DATA_PROP: g.prefix},
+			CreateAt: g.timeAfter(question.CreateAt),
+		})
+		if err != nil {
+			g.logError("answer", err)
+			continue
+		}
+		g.report.Answers++
+
+		g.createComments(answer, question.Id, teamId)
+		g.createVotes(answer, teamId)
+
+		answers = append(answers, answer)
+	}
+
+	if len(answers) > 0 && g.rand.Intn(2) == 0 {
+		best := answers[g.rand.Intn(len(answers))]
+		if err := g.store.Post().SelectBestAnswer(question.Id, best.Id); err != nil {
+			g.logError("best_answer", err)
+		}
+	}
+
+	return nil
+}
+
+func (g *sampleDataGenerator) createComments(parent *model.Post, rootId string, teamId string) {
+	count := g.rand.Intn(g.opts.Comments + 1)
+	if count > model.POST_COMMENT_LIMIT {
+		count = model.POST_COMMENT_LIMIT
+	}
+
+	for i := 0; i < count; i++ {
+		_, err := g.store.Post().SaveComment(&model.Post{
+			Type:     model.POST_TYPE_COMMENT,
+			RootId:   rootId,
+			ParentId: parent.Id,
+			UserId:   g.pickUser(teamId).Id,
+			TeamId:   teamId,
+			Content:  sampleDataComments[g.rand.Intn(len(sampleDataComments))],
+			Props:    model.StringInterface{SAMPLE_DATA_PROP: g.prefix},
+			CreateAt: g.timeAfter(parent.CreateAt),
+		})
+		if err != nil {
+			g.logError("comment", err)
+			continue
+		}
+		g.report.Comments++
+	}
+}
+
+// 票数の多い投稿は一部に偏らせ、反対票は少なめにする。投稿者自身は投票しない
+func (g *sampleDataGenerator) createVotes(post *model.Post, teamId string) {
+	r := g.rand.Float64()
+	count := int(r * r * float64(g.opts.Votes+1))
+
+	members := g.members[teamId]
+	for _, index := range g.rand.Perm(len(members)) {
+		if count <= 0 {
+			break
+		}
+
+		voter := members[index]
+		if voter.Id == post.UserId {
+			continue
+		}
+
+		var err *model.AppError
+		if g.rand.Intn(100) < 85 {
+			_, err = g.store.Post().UpVotePost(post.Id, voter.Id)
+		} else {
+			_, err = g.store.Post().DownVotePost(post.Id, voter.Id)
+		}
+		if err != nil {
+			g.logError("vote", err)
+			continue
+		}
+
+		g.report.Votes++
+		count--
+	}
+}
@@ -0,0 +1,124 @@
+package app
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/clear-ness/qa-discussion/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateSampleData(t *testing.T) {
+	th := Setup(t)
+	defer th.TearDown()
+
+	t.Run("invalid options", func(t *testing.T) {
+		for _, opts := range []*SampleDataOptions{
+			{Users: 1, Tags: 1, Days: 1},
+			{Users: 2, Tags: 0, Days: 1},
+			{Users: 2, Tags: 1, Days: 0},
+			{Users: 2, Tags: 1, Days: 1, Questions: -1},
+			{Users: 2, Tags: 1, Days: 1, Teams: -1},
+		} {
+			_, err := th.App.GenerateSampleData(opts)
+			require.NotNil(t, err)
+			assert.Equal(t, "app.sample_data.options.app_error", err.Id)
+		}
+	})
+
+	t.Run("generate", func(t *testing.T) {
+		opts := &SampleDataOptions{
+			Seed:      1,
+			Teams:     2,
+			Users:     5,
+			Questions: 20,
+			Answers:   3,
+			Comments:  2,
+			Votes:     3,
+			Tags:      4,
+			Days:      30,
+		}
+
+		report, err := th.App.GenerateSampleData(opts)
+		require.Nil(t, err)
+		assert.Equal(t, 0, report.Errors)
+		assert.Equal(t, 2, report.Teams)
+		assert.Equal(t, 5, report.Users)
+		assert.Equal(t, 20, report.Questions)
+		assert.Equal(t, SAMPLE_DATA_PASSWORD, report.Password)
+
+		for i := 0; i < opts.Users; i++ {
+			username := fmt.Sprintf("%s.%s.%d", sampleDataFirstNames[i], report.Prefix, i)
+			user, err := th.Store.User().GetByEmail(username + "@" + SAMPLE_DATA_EMAIL_DOMAIN)
+			require.Nil(t, err)
+			assert.Equal(t, report.Prefix, user.Props[SAMPLE_DATA_PROP])
+			assert.True(t, model.ComparePassword(user.Password, SAMPLE_DATA_PASSWORD))
+		}
+
+		questions, answers, comments := int64(0), int64(0), int64(0)
+		for _, user := range th.sampleDataUsers(t, report) {
+			for _, teamId := range th.teamIdsOf(t, user) {
+				questions += th.countPosts(t, model.POST_TYPE_QUESTION, user.Id, teamId)
+				answers += th.countPosts(t, model.POST_TYPE_ANSWER, user.Id, teamId)
+				comments += th.countPosts(t, model.POST_TYPE_COMMENT, user.Id, teamId)
+			}
+		}
+		assert.Equal(t, int64(report.Questions), questions)
+		assert.Equal(t, int64(report.Answers), answers)
+		assert.Equal(t, int64(report.Comments), comments)
+		assert.NotZero(t, report.Answers)
+		assert.NotZero(t, report.Votes)
+		assert.True(t, report.Answers <= opts.Questions*opts.Answers)
+	})
+
+	t.Run("runs do not collide", func(t *testing.T) {
+		opts := &SampleDataOptions{Seed: 1, Users: 2, Tags: 1, Days: 1}
+
+		first, err := th.App.GenerateSampleData(opts)
+		require.Nil(t, err)
+
+		second, err := th.App.GenerateSampleData(opts)
+		require.Nil(t, err)
+		assert.NotEqual(t, first.Prefix, second.Prefix)
+	})
+}
+
+func (th *TestHelper) sampleDataUsers(tb testing.TB, report *SampleDataReport) []*model.User {
+	var users []*model.User
+	for i := 0; i < report.Users; i++ {
+		username := fmt.Sprintf("%s.%s.%d", sampleDataFirstNames[i%len(sampleDataFirstNames)], report.Prefix, i)
+		user, err := th.Store.User().GetByUsername(username)
+		require.Nil(tb, err)
+		users = append(users, user)
+	}
+
+	return users
+}
+
+func (th *TestHelper) countPosts(tb testing.TB, postType string, userId string, teamId string) int64 {
+	_, count, err := th.Store.Post().GetPosts(&model.GetPostsOptions{
+		PostType: postType,
+		UserId:   userId,
+		TeamId:   teamId,
+		SortType: model.POST_SORT_TYPE_CREATION,
+		PerPage:  1,
+	}, true)
+	require.Nil(tb, err)
+
+	return count
+}
+
+// 公開サイトは空文字
+func (th *TestHelper) teamIdsOf(tb testing.TB, user *model.User) []string {
+	members, err := th.Store.Team().GetTeamsForUser(user.Id)
+	require.Nil(tb, err)
+
+	teamIds := []string{""}
+	for _, member := range members {
+		teamIds = append(teamIds, member.TeamId)
+	}
+
+	return teamIds
+}
@@ -0,0 +1,22 @@
+package app
+
+import (
+	"net/http"
+
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/clear-ness/qa-discussion/services/search"
+)
+
+func (a *App) GetSearchIndexStatuses() ([]*search.IndexStatus, *model.AppError) {
+	esBackend, err := search.NewESBackend(&a.Config().SearchSettings, nil)
+	if err != nil {
+		return nil, model.NewAppError("GetSearchIndexStatuses", "app.search.backend.app_error", nil, (*err).Error(), http.StatusInternalServerError)
+	}
+
+	statuses, indexErr := esBackend.WithContext(a.Context()).IndexStatuses(search.AllIndexNames)
+	if indexErr != nil {
+		return nil, model.NewAppError("GetSearchIndexStatuses", "app.search.index_statuses.app_error", nil, indexErr.Error(), http.StatusInternalServerError)
+	}
+
+	return statuses, nil
+}
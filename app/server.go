@@ -7,23 +7,30 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/go-redis/redis/v8"
 	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
 	"github.com/rs/cors"
 
+	"github.com/clear-ness/qa-discussion/audit"
 	"github.com/clear-ness/qa-discussion/clusters"
 	"github.com/clear-ness/qa-discussion/config"
 	"github.com/clear-ness/qa-discussion/mlog"
 	"github.com/clear-ness/qa-discussion/model"
 	"github.com/clear-ness/qa-discussion/services/httpservice"
 	"github.com/clear-ness/qa-discussion/services/l1cache"
+	"github.com/clear-ness/qa-discussion/services/metrics"
+	"github.com/clear-ness/qa-discussion/services/openid"
+	"github.com/clear-ness/qa-discussion/services/tracing"
 	"github.com/clear-ness/qa-discussion/store"
 	"github.com/clear-ness/qa-discussion/store/cachelayer"
 	"github.com/clear-ness/qa-discussion/store/searchlayer"
 	"github.com/clear-ness/qa-discussion/store/sqlstore"
+	"github.com/clear-ness/qa-discussion/store/timerlayer"
 	"github.com/clear-ness/qa-discussion/utils"
 )
 
@@ -35,9 +42,12 @@ type Server struct {
 	Router          *mux.Router
 	WebSocketRouter *WebSocketRouter
 
-	Server      *http.Server
-	ListenAddr  *net.TCPAddr
-	RateLimiter *RateLimiter
+	Server     *http.Server
+	ListenAddr *net.TCPAddr
+
+	// 設定の変更で作り直すので、GetRateLimiterで取り出す
+	rateLimiter     *RateLimiter
+	rateLimiterLock sync.RWMutex
 
 	didFinishListen chan struct{}
 
@@ -47,16 +57,37 @@ type Server struct {
 	newSqlStore func() store.Store
 	newStore    func() store.Store
 
+	skipBackgroundJobs bool
+
+	// ESの設定変更を反映するために保持する
+	searchLayer *searchlayer.SearchStore
+
 	sessionCache l1cache.Cache
 
+	// リクエストごとに使うので接続を使い回す。設定の変更で作り直すので、RedisClientで取り出す
+	redisClient     *redis.Client
+	redisClientLock sync.RWMutex
+
 	CacheProvider l1cache.Provider
 
-	configStore config.Store
+	configStore      config.Store
+	configListenerId string
 
 	Log *mlog.Logger
 
 	EmailBatching *EmailBatchingJob
 
+	WebhookDelivery *WebhookDeliveryWorker
+
+	PostViewsFlush *PostViewsFlushWorker
+
+	Audit *audit.Audit
+
+	Metrics       metrics.MetricsInterface
+	metricsServer *http.Server
+
+	Tracer *tracing.Tracer
+
 	HTTPService httpservice.HTTPService
 
 	hubs     []*Hub
@@ -64,6 +95,11 @@ type Server struct {
 
 	Cluster   clusters.ClusterInterface
 	clusterId string
+
+	startAt int64
+
+	openIdProvider     *openid.Provider
+	openIdProviderLock sync.Mutex
 }
 
 func NewServer(options ...Option) (*Server, error) {
@@ -74,15 +110,7 @@ func NewServer(options ...Option) (*Server, error) {
 		RootRouter:          rootRouter,
 		hashSeed:            maphash.MakeSeed(),
 		clusterId:           model.NewId(),
-	}
-
-	if s.configStore == nil {
-		configStore, err := config.NewFileStore("config.json")
-		if err != nil {
-			return nil, err
-		}
-
-		s.configStore = configStore
+		startAt:             model.GetMillis(),
 	}
 
 	for _, option := range options {
@@ -93,6 +121,16 @@ func NewServer(options ...Option) (*Server, error) {
 		}
 	}
 
+	if s.configStore == nil {
+		configStore, err := config.NewFileStore("config.json", true)
+		if err != nil {
+			return nil, err
+		}
+
+		s.configStore = configStore
+	}
+	mlog.Info("Loaded config", mlog.String("source", s.configStore.String()))
+
 	//if s.Log == nil {
 	//    s.Log = mlog.NewLogger()
 	//}
@@ -103,15 +141,29 @@ func NewServer(options ...Option) (*Server, error) {
 	//    return nil, errors.Wrapf(err, "unable to load translation files")
 	//}
 
-	s.CacheProvider = l1cache.NewProvider()
+	// キャッシュ層やstore層が計測値を記録するので、それらより先に用意する
+	s.initMetrics()
+
+	if err := s.initTracing(); err != nil {
+		return nil, errors.Wrap(err, "failed to initialize tracing")
+	}
+
+	s.CacheProvider = l1cache.NewProvider(s.Metrics)
 
 	s.sessionCache = s.CacheProvider.NewCache(&l1cache.CacheOptions{
 		Size: model.SESSION_CACHE_SIZE,
+		Name: "Session",
 	})
 
 	if s.newSqlStore == nil {
+		if *s.Config().SqlSettings.AutoMigrate {
+			if err := migrateSchema(&s.Config().SqlSettings); err != nil {
+				return nil, errors.Wrap(err, "failed to migrate database")
+			}
+		}
+
 		s.newSqlStore = func() store.Store {
-			return sqlstore.NewSqlSupplier(s.Config().SqlSettings)
+			return sqlstore.NewSqlSupplier(s.Config().SqlSettings, s.Metrics)
 		}
 	}
 	s.sqlStore = s.newSqlStore()
@@ -129,21 +181,32 @@ func NewServer(options ...Option) (*Server, error) {
 				// We use redis's pub/sub to clear L1 caches on other servers when one web server does a removal for consistency,
 				cachelayer.NewCacheLayer(
 					s.sqlStore,
-					s.Config(),
+					s.Metrics,
+					s,
 				),
+				s.Metrics,
 				s.Config(),
 			)
 
 			newLayer.SetupIndexes()
+			s.searchLayer = newLayer
+
+			if s.Metrics != nil {
+				return timerlayer.New(newLayer, s.Metrics)
+			}
 
 			return newLayer
 		}
 	}
 	s.Store = s.newStore()
 
+	s.configureAudit()
+
+	s.configureRedisClient(s.Config())
+
 	s.HTTPService = httpservice.MakeHTTPService(s)
 
-	s.Cluster = clusters.MakeCluster(s)
+	s.Cluster = clusters.MakeCluster(s, s.Metrics)
 
 	subpath, err := utils.GetSubpathFromConfig(s.Config())
 	if err != nil {
@@ -169,10 +232,27 @@ func NewServer(options ...Option) (*Server, error) {
 
 	s.FakeApp().InitMigrations()
 
+	s.configListenerId = s.AddConfigListener(s.onConfigChanged)
+
 	s.FakeApp().registerAllClusterMessageHandlers()
+
+	if s.skipBackgroundJobs {
+		return s, nil
+	}
+
+	s.WebhookDelivery = NewWebhookDeliveryWorker(s)
+	s.WebhookDelivery.Start()
+
+	s.PostViewsFlush = NewPostViewsFlushWorker(s)
+	s.PostViewsFlush.Start()
+
 	// redis pub/subにsubscribeしておく
 	s.Cluster.Start(s.clusterId)
 
+	if err := s.StartMetricsServer(); err != nil {
+		return nil, errors.Wrap(err, "failed to start metrics server")
+	}
+
 	return s, nil
 }
 
@@ -197,7 +277,7 @@ func (s *Server) Start() error {
 			AllowedOrigins:   strings.Fields(allowedOrigins),
 			AllowedMethods:   corsAllowedMethods,
 			AllowedHeaders:   []string{"*"},
-			ExposedHeaders:   strings.Fields(exposedCorsHeaders),
+			ExposedHeaders:   append(strings.Fields(exposedCorsHeaders), model.HEADER_NEXT_CURSOR, model.HEADER_PREV_CURSOR),
 			MaxAge:           daySeconds,
 			AllowCredentials: allowCredentials,
 			Debug:            debug,
@@ -211,17 +291,11 @@ func (s *Server) Start() error {
 		handler = corsWrapper.Handler(handler)
 	}
 
-	if *s.Config().RateLimitSettings.Enable {
-		mlog.Info("RateLimiter is enabled")
-
-		rateLimiter, err := NewRateLimiter(&s.Config().RateLimitSettings, s.Config().ServiceSettings.TrustedProxyIPHeader)
-		if err != nil {
-			return err
-		}
-
-		s.RateLimiter = rateLimiter
-		handler = rateLimiter.RateLimitHandler(handler)
+	if err := s.configureRateLimiter(s.Config()); err != nil {
+		return err
 	}
+	// 設定の変更で有効・無効が切り替わるので、無効な場合も挟んでおく
+	handler = s.rateLimitHandler(handler)
 
 	s.Server = &http.Server{
 		Handler:      handler,
@@ -293,18 +367,54 @@ func (s *Server) Shutdown() error {
 
 	s.HubStop()
 
+	s.RemoveConfigListener(s.configListenerId)
+
 	s.StopHTTPServer()
 
+	s.StopMetricsServer()
+
 	if s.EmailBatching != nil {
 		s.EmailBatching.StopJobs()
 	}
 
+	if s.WebhookDelivery != nil {
+		s.WebhookDelivery.Stop()
+	}
+
+	if s.PostViewsFlush != nil {
+		s.PostViewsFlush.Stop()
+	}
+
 	s.WaitForGoroutines()
 
+	if s.Cluster != nil {
+		s.Cluster.Stop()
+	}
+
+	// DBへの書き出しがあるので、storeを閉じる前に残りのrecordを書き出す
+	if s.Audit != nil {
+		if err := s.Audit.Shutdown(); err != nil {
+			mlog.Warn("Unable to shutdown audit", mlog.Err(err))
+		}
+	}
+
 	if s.Store != nil {
 		s.Store.Close()
 	}
 
+	s.configureRedisClient(nil)
+
+	if err := s.configStore.Close(); err != nil {
+		mlog.Warn("Unable to close config store", mlog.Err(err))
+	}
+
+	// 残っているspanを書き出す
+	if s.Tracer != nil {
+		if err := s.Tracer.Shutdown(); err != nil {
+			mlog.Warn("Unable to shutdown tracer", mlog.Err(err))
+		}
+	}
+
 	mlog.Info("Server stopped")
 	return nil
 }
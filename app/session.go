@@ -3,13 +3,14 @@ package app
 import (
 	"net/http"
 
+	"github.com/clear-ness/qa-discussion/mlog"
 	"github.com/clear-ness/qa-discussion/model"
 )
 
 func (a *App) CreateSession(session *model.Session) (*model.Session, *model.AppError) {
 	session.Token = ""
 
-	session, err := a.Srv.Store.Session().Save(session)
+	session, err := a.Store().Session().Save(session)
 	if err != nil {
 		return nil, err
 	}
@@ -23,7 +24,7 @@ func (a *App) GetSession(token string) (*model.Session, *model.AppError) {
 
 	// TODO: まずセッションL1キャッシュから取得を試みる
 
-	if session, err = a.Srv.Store.Session().Get(token); err == nil {
+	if session, err = a.Store().Session().Get(token); err == nil {
 		if session != nil {
 			if session.Token != token {
 				return nil, model.NewAppError("GetSession", "api.context.invalid_token.error", map[string]interface{}{"Token": token, "Error": ""}, "", http.StatusUnauthorized)
@@ -41,7 +42,7 @@ func (a *App) GetSession(token string) (*model.Session, *model.AppError) {
 }
 
 func (a *App) RevokeSessionById(sessionId string) *model.AppError {
-	session, err := a.Srv.Store.Session().Get(sessionId)
+	session, err := a.Store().Session().Get(sessionId)
 	if err != nil {
 		err.StatusCode = http.StatusBadRequest
 		return err
@@ -56,7 +57,7 @@ func (a *App) RevokeSession(session *model.Session) *model.AppError {
 			return err
 		}
 	} else {
-		if err := a.Srv.Store.Session().Remove(session.Id); err != nil {
+		if err := a.Store().Session().Remove(session.Id); err != nil {
 			return err
 		}
 	}
@@ -66,25 +67,118 @@ func (a *App) RevokeSession(session *model.Session) *model.AppError {
 	return nil
 }
 
-func (a *App) RevokeAllSessions(userId string) *model.AppError {
-	sessions, err := a.Srv.Store.Session().GetSessions(userId)
+// 期限切れのセッションは削除されるまで残っているので、一覧には含めない
+func (a *App) GetSessions(userId string) ([]*model.Session, *model.AppError) {
+	sessions, err := a.Store().Session().GetSessions(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	active := make([]*model.Session, 0, len(sessions))
+	for _, session := range sessions {
+		if !session.IsExpired() {
+			active = append(active, session)
+		}
+	}
+
+	return active, nil
+}
+
+// 指定ユーザーのセッションの1つを無効化する(他の端末からのログアウト)
+func (a *App) RevokeSessionForUser(userId, sessionId string) *model.AppError {
+	session, err := a.Store().Session().Get(sessionId)
+	if err != nil {
+		err.StatusCode = http.StatusBadRequest
+		return err
+	}
+
+	if session.UserId != userId {
+		return model.NewAppError("RevokeSessionForUser", "api.user.revoke_session.user_id_mismatch.app_error", nil, "userId="+userId+", sessionId="+sessionId, http.StatusBadRequest)
+	}
+
+	return a.RevokeSession(session)
+}
+
+// 現在のセッション以外の全てのセッションを無効化する
+// 1つ失敗しても残りのセッションの無効化を続け、最初のエラーを返す。
+// 無効にしたセッションがキャッシュに残らないよう、失敗した場合もキャッシュは消す
+func (a *App) RevokeSessionsExcept(userId, currentSessionId string) *model.AppError {
+	sessions, err := a.Store().Session().GetSessions(userId)
 	if err != nil {
 		return err
 	}
 
+	var firstErr *model.AppError
 	for _, session := range sessions {
+		if session.Id == currentSessionId {
+			continue
+		}
+
+		var err *model.AppError
 		if session.IsOAuth {
-			a.RevokeAccessToken(session.Token)
+			err = a.RevokeAccessToken(session.Token)
 		} else {
-			if err := a.Srv.Store.Session().Remove(session.Id); err != nil {
-				return err
+			err = a.Store().Session().Remove(session.Id)
+		}
+
+		if err != nil {
+			mlog.Warn("Failed to revoke a session", mlog.String("user_id", userId), mlog.String("session_id", session.Id), mlog.Err(err))
+			if firstErr == nil {
+				firstErr = err
 			}
 		}
 	}
 
 	a.ClearSessionCacheForUser(userId)
 
-	return nil
+	return firstErr
+}
+
+func (a *App) RevokeAllSessions(userId string) *model.AppError {
+	return a.RevokeSessionsExcept(userId, "")
+}
+
+// リクエスト毎にDBを更新しないよう、SESSION_ACTIVITY_TIMEOUT以上経過した場合のみ更新する。
+func (a *App) UpdateLastActivityAtIfNeeded(session model.Session) {
+	now := model.GetMillis()
+	if now-session.LastActivityAt < model.SESSION_ACTIVITY_TIMEOUT {
+		return
+	}
+
+	if err := a.Store().Session().UpdateLastActivityAt(session.Id, now); err != nil {
+		mlog.Error("Failed to update LastActivityAt", mlog.String("user_id", session.UserId), mlog.String("session_id", session.Id), mlog.Err(err))
+	}
+}
+
+// アクティビティがある限りセッションの有効期限を延長する。
+// 延長幅がSESSION_ACTIVITY_TIMEOUTに満たない場合はDBを更新しない。
+func (a *App) ExtendSessionExpiryIfNeeded(session *model.Session) bool {
+	if !*a.Config().ServiceSettings.ExtendSessionLengthWithActivity {
+		return false
+	}
+
+	if session == nil || session.IsExpired() || session.ExpiresAt <= 0 {
+		return false
+	}
+
+	sessionLength := *a.Config().ServiceSettings.SessionLengthWebInDays
+	if session.IsOAuth {
+		sessionLength = *a.Config().ServiceSettings.SessionLengthOAuthInDays
+	}
+
+	newExpiresAt := model.GetMillis() + (1000 * 60 * 60 * 24 * int64(sessionLength))
+	if newExpiresAt-session.ExpiresAt < model.SESSION_ACTIVITY_TIMEOUT {
+		return false
+	}
+
+	if err := a.Store().Session().UpdateExpiresAt(session.Id, newExpiresAt); err != nil {
+		mlog.Error("Failed to update ExpiresAt", mlog.String("user_id", session.UserId), mlog.String("session_id", session.Id), mlog.Err(err))
+		return false
+	}
+
+	session.ExpiresAt = newExpiresAt
+
+	return true
 }
 
 // 自サーバーのL1キャッシュ(user session)を削除
@@ -0,0 +1,101 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/clear-ness/qa-discussion/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (th *TestHelper) createSession(tb testing.TB, session *model.Session) *model.Session {
+	session, err := th.Store.Session().Save(session)
+	require.Nil(tb, err)
+
+	return session
+}
+
+func sessionIds(sessions []*model.Session) []string {
+	ids := []string{}
+	for _, session := range sessions {
+		ids = append(ids, session.Id)
+	}
+
+	return ids
+}
+
+func TestGetSessions(t *testing.T) {
+	th := Setup(t)
+	defer th.TearDown()
+
+	user := th.CreateUser(t)
+	active := th.createSession(t, &model.Session{UserId: user.Id, ExpiresAt: model.GetMillis() + 60*1000})
+	noExpiry := th.createSession(t, &model.Session{UserId: user.Id})
+	th.createSession(t, &model.Session{UserId: user.Id, ExpiresAt: model.GetMillis() - 1000})
+
+	sessions, err := th.App.GetSessions(user.Id)
+	require.Nil(t, err)
+	assert.ElementsMatch(t, []string{active.Id, noExpiry.Id}, sessionIds(sessions))
+}
+
+func TestRevokeSessions(t *testing.T) {
+	th := Setup(t)
+	defer th.TearDown()
+
+	t.Run("revoke all but the current session", func(t *testing.T) {
+		user := th.CreateUser(t)
+		current := th.createSession(t, &model.Session{UserId: user.Id})
+		th.createSession(t, &model.Session{UserId: user.Id})
+
+		oauth := th.createSession(t, &model.Session{UserId: user.Id, IsOAuth: true})
+		_, err := th.Store.OAuth().SaveAccessData(&model.AccessData{
+			ClientId:    model.NewId(),
+			UserId:      user.Id,
+			Token:       oauth.Token,
+			RedirectUri: "https://example.com/callback",
+		})
+		require.Nil(t, err)
+
+		require.Nil(t, th.App.RevokeSessionsExcept(user.Id, current.Id))
+
+		sessions, err := th.App.GetSessions(user.Id)
+		require.Nil(t, err)
+		assert.Equal(t, []string{current.Id}, sessionIds(sessions))
+
+		_, err = th.Store.OAuth().GetAccessData(oauth.Token)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("failure to revoke an access token is returned", func(t *testing.T) {
+		user := th.CreateUser(t)
+		current := th.createSession(t, &model.Session{UserId: user.Id})
+		other := th.createSession(t, &model.Session{UserId: user.Id, CreateAt: model.GetMillis() - 1000})
+		// アクセストークンが見つからないOAuthのセッション。新しい順に無効にするので先に失敗する
+		th.createSession(t, &model.Session{UserId: user.Id, IsOAuth: true, CreateAt: model.GetMillis() + 1000})
+		require.NoError(t, th.Server.sessionCache.Set(other.Token, other))
+
+		err := th.App.RevokeSessionsExcept(user.Id, current.Id)
+		require.NotNil(t, err)
+		assert.Equal(t, "api.oauth.revoke_access_token.get.app_error", err.Id)
+
+		// 失敗の後も残りのセッションを無効にし、キャッシュも消す
+		sessions, err := th.App.GetSessions(user.Id)
+		require.Nil(t, err)
+		assert.Contains(t, sessionIds(sessions), current.Id)
+		assert.NotContains(t, sessionIds(sessions), other.Id)
+		keys, cacheErr := th.Server.sessionCache.Keys()
+		require.NoError(t, cacheErr)
+		assert.NotContains(t, keys, other.Token)
+
+		th.createSession(t, &model.Session{UserId: user.Id, IsOAuth: true, CreateAt: model.GetMillis() + 1000})
+
+		err = th.App.RevokeAllSessions(user.Id)
+		require.NotNil(t, err)
+		assert.Equal(t, "api.oauth.revoke_access_token.get.app_error", err.Id)
+
+		sessions, err = th.App.GetSessions(user.Id)
+		require.Nil(t, err)
+		assert.NotContains(t, sessionIds(sessions), current.Id)
+	})
+}
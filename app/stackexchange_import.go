@@ -0,0 +1,723 @@
+package app
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/clear-ness/qa-discussion/mlog"
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/clear-ness/qa-discussion/store"
+)
+
+const (
+	// 取り込んだデータの元のidを保持するProps。再実行時に同じデータを取り込まないために使う
+	STACK_EXCHANGE_PROP_ID = "stackexchange_id"
+
+	STACK_EXCHANGE_EMAIL_DOMAIN   = "stackexchange.invalid"
+	STACK_EXCHANGE_DATE_FORMAT    = "2006-01-02T15:04:05.999"
+	STACK_EXCHANGE_POINTS_BATCH   = 100
+	STACK_EXCHANGE_DELETED_USER   = "deleted-user"
+	stackExchangeDeletedUserIndex = 0
+
+	seQuestionPostType = 1
+	seAnswerPostType   = 2
+
+	seVoteTypeUpMod    = 2
+	seVoteTypeDownMod  = 3
+	seVoteTypeFavorite = 5
+)
+
+// PostHistoryTypeIdのうち、本文・タイトル・タグの内容を持つもの
+var seHistoryFields = map[int]string{
+	1: "title", 2: "body", 3: "tags",
+	4: "title", 5: "body", 6: "tags",
+	7: "title", 8: "body", 9: "tags",
+}
+
+var seInvalidUsernameChars = regexp.MustCompile(`[^a-z0-9\.\-_]+`)
+var seValidSiteName = regexp.MustCompile(`^[a-z0-9\.\-]+$`)
+
+type StackExchangeImportReport struct {
+	Users     int `json:"users"`
+	Questions int `json:"questions"`
+	Answers   int `json:"answers"`
+	Comments  int `json:"comments"`
+	Revisions int `json:"revisions"`
+	Votes     int `json:"votes"`
+	Favorites int `json:"favorites"`
+	Skipped   int `json:"skipped"`
+	Errors    int `json:"errors"`
+}
+
+type seUserRow struct {
+	Id          int    `xml:"Id,attr"`
+	DisplayName string `xml:"DisplayName,attr"`
+}
+
+type sePostRow struct {
+	Id               int    `xml:"Id,attr"`
+	PostTypeId       int    `xml:"PostTypeId,attr"`
+	ParentId         int    `xml:"ParentId,attr"`
+	AcceptedAnswerId int    `xml:"AcceptedAnswerId,attr"`
+	OwnerUserId      *int   `xml:"OwnerUserId,attr"`
+	CreationDate     string `xml:"CreationDate,attr"`
+	LastEditDate     string `xml:"LastEditDate,attr"`
+	ClosedDate       string `xml:"ClosedDate,attr"`
+	Title            string `xml:"Title,attr"`
+	Body             string `xml:"Body,attr"`
+	Tags             string `xml:"Tags,attr"`
+}
+
+type seCommentRow struct {
+	Id           int    `xml:"Id,attr"`
+	PostId       int    `xml:"PostId,attr"`
+	UserId       *int   `xml:"UserId,attr"`
+	CreationDate string `xml:"CreationDate,attr"`
+	Text         string `xml:"Text,attr"`
+}
+
+type seHistoryRow struct {
+	PostHistoryTypeId int    `xml:"PostHistoryTypeId,attr"`
+	PostId            int    `xml:"PostId,attr"`
+	RevisionGUID      string `xml:"RevisionGUID,attr"`
+	CreationDate      string `xml:"CreationDate,attr"`
+	Text              string `xml:"Text,attr"`
+}
+
+type seVoteRow struct {
+	PostId     int  `xml:"PostId,attr"`
+	VoteTypeId int  `xml:"VoteTypeId,attr"`
+	UserId     *int `xml:"UserId,attr"`
+}
+
+// 編集履歴は項目ごとの行に分かれているため、各項目の最新の行の位置だけを覚えておき、
+// 次の編集が来た時点でその位置から読み直して編集前の内容を組み立てる。
+type seRevisionState struct {
+	guid   string
+	fields map[string]int64
+}
+
+type seVoteCount struct {
+	up   int
+	down int
+}
+
+type stackExchangeImporter struct {
+	app    *App
+	dir    string
+	site   string
+	teamId string
+	team   *model.Team
+	report *StackExchangeImportReport
+
+	// 元データのid → インポート先のid
+	users    map[int]string
+	posts    map[int]*importedPost
+	imported map[string]string
+	// 質問id → 採用された回答id
+	accepted map[int]int
+}
+
+// 展開済みのStack Exchangeデータダンプ(Users.xml, Posts.xmlなど)をdirから読み込む。
+// teamIdが空の場合は公開サイトへ取り込む。
+func (a *App) StackExchangeImport(dir string, site string, teamId string) (*StackExchangeImportReport, *model.AppError) {
+	site = strings.ToLower(site)
+	if !seValidSiteName.MatchString(site) {
+		return nil, model.NewAppError("StackExchangeImport", "app.stackexchange_import.site.app_error", nil, "site="+site, http.StatusBadRequest)
+	}
+
+	var team *model.Team
+	if len(teamId) > 0 {
+		var err *model.AppError
+		if team, err = a.GetTeam(teamId); err != nil {
+			return nil, err
+		}
+	}
+
+	importer := &stackExchangeImporter{
+		app:      a,
+		dir:      dir,
+		site:     site,
+		teamId:   teamId,
+		team:     team,
+		report:   &StackExchangeImportReport{},
+		users:    make(map[int]string),
+		posts:    make(map[int]*importedPost),
+		imported: make(map[string]string),
+		accepted: make(map[int]int),
+	}
+
+	if err := importer.loadImported(); err != nil {
+		return nil, err
+	}
+
+	// タグはTags.xmlではなく質問のタグから作成し、投稿数を正しく保つ
+	steps := []struct {
+		file     string
+		required bool
+		run      func(path string) *model.AppError
+	}{
+		{"Users.xml", true, importer.importUsers},
+		{"Posts.xml", true, importer.importPosts},
+		{"Comments.xml", false, importer.importComments},
+		{"PostHistory.xml", false, importer.importHistory},
+		{"Votes.xml", false, importer.importVotes},
+	}
+
+	for _, step := range steps {
+		path := filepath.Join(dir, step.file)
+		if _, err := os.Stat(path); err != nil {
+			if step.required {
+				return importer.report, model.NewAppError("StackExchangeImport", "app.stackexchange_import.missing_file.app_error", nil, "file="+step.file, http.StatusBadRequest)
+			}
+			mlog.Info("Skipping missing Stack Exchange dump file", mlog.String("file", step.file))
+			continue
+		}
+
+		mlog.Info("Importing Stack Exchange dump file", mlog.String("file", step.file))
+		if err := step.run(path); err != nil {
+			return importer.report, err
+		}
+	}
+
+	if err := importer.recomputePoints(); err != nil {
+		return importer.report, err
+	}
+
+	return importer.report, nil
+}
+
+func (si *stackExchangeImporter) sourceId(kind string, id interface{}) string {
+	return fmt.Sprintf("%s/%s/%v", si.site, kind, id)
+}
+
+func (si *stackExchangeImporter) loadImported() *model.AppError {
+	posts, err := si.app.Srv.Store.Post().GetImportedPosts(si.teamId, STACK_EXCHANGE_PROP_ID, si.site+"/")
+	if err != nil {
+		return err
+	}
+
+	for _, post := range posts {
+		sourceId, ok := post.Props[STACK_EXCHANGE_PROP_ID].(string)
+		if !ok {
+			continue
+		}
+
+		si.imported[sourceId] = post.Id
+
+		var id int
+		if _, scanErr := fmt.Sscanf(sourceId, si.site+"/post/%d", &id); scanErr == nil && len(post.OriginalId) == 0 {
+			si.posts[id] = &importedPost{id: post.Id, rootId: post.RootId, parentId: post.ParentId, postType: post.Type, userId: post.UserId}
+		}
+	}
+
+	return nil
+}
+
+func (si *stackExchangeImporter) logError(kind string, id interface{}, err *model.AppError) {
+	si.report.Errors++
+	mlog.Warn("Failed to import Stack Exchange row", mlog.String("source_id", si.sourceId(kind, id)), mlog.Err(err))
+}
+
+// <row .../>要素を1つずつ読み込み、ファイル全体をメモリに載せない
+func forEachStackExchangeRow(path string, fn func(decoder *xml.Decoder, start *xml.StartElement, offset int64) *model.AppError) *model.AppError {
+	file, err := os.Open(path)
+	if err != nil {
+		return model.NewAppError("forEachStackExchangeRow", "app.stackexchange_import.open_file.app_error", nil, err.Error(), http.StatusBadRequest)
+	}
+	defer file.Close()
+
+	decoder := xml.NewDecoder(file)
+	for {
+		offset := decoder.InputOffset()
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return model.NewAppError("forEachStackExchangeRow", "app.stackexchange_import.parse_file.app_error", nil, "file="+filepath.Base(path)+", "+err.Error(), http.StatusBadRequest)
+		}
+
+		if start, ok := token.(xml.StartElement); ok && start.Name.Local == "row" {
+			if err := fn(decoder, &start, offset); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func decodeStackExchangeRow(decoder *xml.Decoder, start *xml.StartElement, row interface{}) *model.AppError {
+	if err := decoder.DecodeElement(row, start); err != nil {
+		return model.NewAppError("decodeStackExchangeRow", "app.stackexchange_import.parse_row.app_error", nil, err.Error(), http.StatusBadRequest)
+	}
+	return nil
+}
+
+func parseStackExchangeDate(value string) int64 {
+	if len(value) == 0 {
+		return 0
+	}
+
+	t, err := time.Parse(STACK_EXCHANGE_DATE_FORMAT, value)
+	if err != nil {
+		return 0
+	}
+
+	return t.UnixNano() / int64(time.Millisecond)
+}
+
+// "<python><django>" と "|python|django|" の両方の形式がある。
+// タグに使えない"-"と"."は"_"に置き換え、それでも使えないタグ(c#など)は取り込まない。
+func parseStackExchangeTags(value string) string {
+	return model.ParseTags(strings.NewReplacer("<", " ", ">", " ", "|", " ", "-", "_", ".", "_").Replace(value))
+}
+
+func (si *stackExchangeImporter) importUsers(path string) *model.AppError {
+	return forEachStackExchangeRow(path, func(decoder *xml.Decoder, start *xml.StartElement, offset int64) *model.AppError {
+		var row seUserRow
+		if err := decodeStackExchangeRow(decoder, start, &row); err != nil {
+			return err
+		}
+
+		if _, err := si.ensureUser(row.Id, row.DisplayName); err != nil {
+			si.logError("user", row.Id, err)
+		}
+
+		return nil
+	})
+}
+
+// メールアドレスをサイトと元のidから決めることで、再実行時は既存のユーザーを使う
+func (si *stackExchangeImporter) ensureUser(id int, displayName string) (string, *model.AppError) {
+	if userId, ok := si.users[id]; ok {
+		return userId, nil
+	}
+
+	email := fmt.Sprintf("%s-%d@%s", si.site, id, STACK_EXCHANGE_EMAIL_DOMAIN)
+
+	user, err := si.app.Srv.Store.User().GetByEmail(email)
+	if err != nil && err.Id != store.MISSING_ACCOUNT_ERROR {
+		return "", err
+	}
+
+	if user == nil {
+		user = &model.User{
+			Type:          model.USER_TYPE_NORMAL,
+			Username:      si.username(id, displayName),
+			Email:         email,
+			EmailVerified: true,
+			Props:         model.StringMap{STACK_EXCHANGE_PROP_ID: si.sourceId("user", id)},
+		}
+
+		if user, err = si.app.Srv.Store.User().Save(user); err != nil {
+			return "", err
+		}
+
+		si.report.Users++
+	}
+
+	if si.team != nil {
+		if err := si.app.JoinUserToTeam(si.team, user, false); err != nil {
+			return "", err
+		}
+	}
+
+	si.users[id] = user.Id
+	return user.Id, nil
+}
+
+// 表示名は重複し得るため、元のidを付けて一意にする
+func (si *stackExchangeImporter) username(id int, displayName string) string {
+	suffix := "-" + strconv.Itoa(id)
+
+	name := seInvalidUsernameChars.ReplaceAllString(strings.ToLower(displayName), "")
+	if len(name) == 0 {
+		name = "user"
+	}
+
+	if len(name)+len(suffix) > model.USER_NAME_MAX_LENGTH {
+		name = name[:model.USER_NAME_MAX_LENGTH-len(suffix)]
+	}
+
+	username := name + suffix
+	if _, err := si.app.Srv.Store.User().GetByUsername(username); err == nil {
+		// 別サイトのインポートなどで既に使われている場合
+		username = si.site + suffix
+	}
+
+	return username
+}
+
+// 退会済みユーザーの投稿はまとめて1人のユーザーに紐付ける
+func (si *stackExchangeImporter) resolveUser(id *int) (string, *model.AppError) {
+	if id != nil {
+		if userId, ok := si.users[*id]; ok {
+			return userId, nil
+		}
+	}
+
+	return si.ensureUser(stackExchangeDeletedUserIndex, STACK_EXCHANGE_DELETED_USER)
+}
+
+func (si *stackExchangeImporter) importPosts(path string) *model.AppError {
+	return forEachStackExchangeRow(path, func(decoder *xml.Decoder, start *xml.StartElement, offset int64) *model.AppError {
+		var row sePostRow
+		if err := decodeStackExchangeRow(decoder, start, &row); err != nil {
+			return err
+		}
+
+		if row.PostTypeId != seQuestionPostType && row.PostTypeId != seAnswerPostType {
+			return nil
+		}
+
+		if row.AcceptedAnswerId > 0 {
+			si.accepted[row.Id] = row.AcceptedAnswerId
+		}
+
+		sourceId := si.sourceId("post", row.Id)
+		if _, ok := si.imported[sourceId]; ok {
+			si.report.Skipped++
+			return nil
+		}
+
+		if err := si.importPost(&row, sourceId); err != nil {
+			si.logError("post", row.Id, err)
+		}
+
+		return nil
+	})
+}
+
+func (si *stackExchangeImporter) importPost(row *sePostRow, sourceId string) *model.AppError {
+	userId, err := si.resolveUser(row.OwnerUserId)
+	if err != nil {
+		return err
+	}
+
+	post := &model.Post{
+		UserId:   userId,
+		TeamId:   si.teamId,
+		Content:  row.Body,
+		Props:    model.StringInterface{STACK_EXCHANGE_PROP_ID: sourceId},
+		CreateAt: parseStackExchangeDate(row.CreationDate),
+		EditAt:   parseStackExchangeDate(row.LastEditDate),
+	}
+
+	var question *importedPost
+	if row.PostTypeId == seQuestionPostType {
+		post.Type = model.POST_TYPE_QUESTION
+		post.Title = row.Title
+		post.Tags = parseStackExchangeTags(row.Tags)
+		// 締め切られた質問には回答できないようにする
+		post.LockedAt = parseStackExchangeDate(row.ClosedDate)
+	} else {
+		parent, ok := si.posts[row.ParentId]
+		if !ok || parent.postType != model.POST_TYPE_QUESTION {
+			return model.NewAppError("importPost", "app.stackexchange_import.missing_parent.app_error", nil, "parent="+strconv.Itoa(row.ParentId), http.StatusBadRequest)
+		}
+
+		question = parent
+		post.Type = model.POST_TYPE_ANSWER
+		post.RootId = parent.id
+		post.ParentId = parent.id
+	}
+
+	post, err = si.app.Srv.Store.Post().ImportPost(post)
+	if err != nil {
+		return err
+	}
+
+	si.posts[row.Id] = &importedPost{id: post.Id, rootId: post.RootId, parentId: post.ParentId, postType: post.Type, userId: post.UserId}
+	si.imported[sourceId] = post.Id
+
+	if question == nil {
+		si.report.Questions++
+		return nil
+	}
+
+	si.report.Answers++
+
+	if si.accepted[row.ParentId] == row.Id {
+		return si.app.Srv.Store.Post().SelectBestAnswer(question.id, post.Id)
+	}
+
+	return nil
+}
+
+func (si *stackExchangeImporter) importComments(path string) *model.AppError {
+	return forEachStackExchangeRow(path, func(decoder *xml.Decoder, start *xml.StartElement, offset int64) *model.AppError {
+		var row seCommentRow
+		if err := decodeStackExchangeRow(decoder, start, &row); err != nil {
+			return err
+		}
+
+		sourceId := si.sourceId("comment", row.Id)
+		if _, ok := si.imported[sourceId]; ok {
+			si.report.Skipped++
+			return nil
+		}
+
+		if err := si.importComment(&row, sourceId); err != nil {
+			si.logError("comment", row.Id, err)
+		}
+
+		return nil
+	})
+}
+
+func (si *stackExchangeImporter) importComment(row *seCommentRow, sourceId string) *model.AppError {
+	parent, ok := si.posts[row.PostId]
+	if !ok {
+		return model.NewAppError("importComment", "app.stackexchange_import.missing_parent.app_error", nil, "parent="+strconv.Itoa(row.PostId), http.StatusBadRequest)
+	}
+
+	userId, err := si.resolveUser(row.UserId)
+	if err != nil {
+		return err
+	}
+
+	rootId := parent.rootId
+	if parent.postType == model.POST_TYPE_QUESTION {
+		rootId = parent.id
+	}
+
+	post := &model.Post{
+		Type:     model.POST_TYPE_COMMENT,
+		RootId:   rootId,
+		ParentId: parent.id,
+		UserId:   userId,
+		TeamId:   si.teamId,
+		Content:  row.Text,
+		Props:    model.StringInterface{STACK_EXCHANGE_PROP_ID: sourceId},
+		CreateAt: parseStackExchangeDate(row.CreationDate),
+	}
+
+	if _, err := si.app.Srv.Store.Post().ImportPost(post); err != nil {
+		return err
+	}
+
+	si.report.Comments++
+	return nil
+}
+
+func (si *stackExchangeImporter) importHistory(path string) *model.AppError {
+	file, err := os.Open(path)
+	if err != nil {
+		return model.NewAppError("importHistory", "app.stackexchange_import.open_file.app_error", nil, err.Error(), http.StatusBadRequest)
+	}
+	defer file.Close()
+
+	states := make(map[int]*seRevisionState)
+
+	return forEachStackExchangeRow(path, func(decoder *xml.Decoder, start *xml.StartElement, offset int64) *model.AppError {
+		var row seHistoryRow
+		if err := decodeStackExchangeRow(decoder, start, &row); err != nil {
+			return err
+		}
+
+		field, ok := seHistoryFields[row.PostHistoryTypeId]
+		if !ok {
+			return nil
+		}
+
+		if _, ok := si.posts[row.PostId]; !ok {
+			return nil
+		}
+
+		state, ok := states[row.PostId]
+		if !ok {
+			state = &seRevisionState{fields: make(map[string]int64)}
+			states[row.PostId] = state
+		}
+
+		// 新しい編集の最初の行が来た時点で、編集前の内容を履歴として保存する
+		if len(state.guid) > 0 && state.guid != row.RevisionGUID {
+			if err := si.importRevision(file, row.PostId, state, &row); err != nil {
+				si.logError("revision", row.RevisionGUID, err)
+			}
+		}
+
+		state.guid = row.RevisionGUID
+		state.fields[field] = offset
+
+		return nil
+	})
+}
+
+func (si *stackExchangeImporter) importRevision(file *os.File, postId int, state *seRevisionState, next *seHistoryRow) *model.AppError {
+	sourceId := si.sourceId("revision", next.RevisionGUID)
+	if _, ok := si.imported[sourceId]; ok {
+		si.report.Skipped++
+		return nil
+	}
+
+	original := si.posts[postId]
+
+	body, err := readStackExchangeHistoryRow(file, state.fields["body"])
+	if err != nil {
+		return err
+	}
+
+	revisedAt := parseStackExchangeDate(next.CreationDate)
+	post := &model.Post{
+		Type:       original.postType,
+		RootId:     original.rootId,
+		ParentId:   original.parentId,
+		OriginalId: original.id,
+		UserId:     original.userId,
+		TeamId:     si.teamId,
+		Content:    body.Text,
+		Props:      model.StringInterface{STACK_EXCHANGE_PROP_ID: sourceId},
+		CreateAt:   parseStackExchangeDate(body.CreationDate),
+		UpdateAt:   revisedAt,
+		DeleteAt:   revisedAt,
+	}
+
+	if original.postType == model.POST_TYPE_QUESTION {
+		title, err := readStackExchangeHistoryRow(file, state.fields["title"])
+		if err != nil {
+			return err
+		}
+		post.Title = title.Text
+
+		if _, ok := state.fields["tags"]; ok {
+			tags, err := readStackExchangeHistoryRow(file, state.fields["tags"])
+			if err != nil {
+				return err
+			}
+			post.Tags = parseStackExchangeTags(tags.Text)
+		}
+	}
+
+	if _, err := si.app.Srv.Store.Post().ImportPost(post); err != nil {
+		return err
+	}
+
+	si.imported[sourceId] = post.Id
+	si.report.Revisions++
+	return nil
+}
+
+func readStackExchangeHistoryRow(file *os.File, offset int64) (*seHistoryRow, *model.AppError) {
+	if offset <= 0 {
+		return nil, model.NewAppError("readStackExchangeHistoryRow", "app.stackexchange_import.missing_history.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	var row seHistoryRow
+	decoder := xml.NewDecoder(io.NewSectionReader(file, offset, 1<<62))
+	if err := decoder.Decode(&row); err != nil {
+		return nil, model.NewAppError("readStackExchangeHistoryRow", "app.stackexchange_import.parse_row.app_error", nil, err.Error(), http.StatusBadRequest)
+	}
+
+	return &row, nil
+}
+
+// 公開されているダンプでは賛成・反対票の投票者は分からないため、投稿ごとの票数として取り込む。
+// お気に入りは投票者が分かるのでユーザーごとに保存する。
+func (si *stackExchangeImporter) importVotes(path string) *model.AppError {
+	counts := make(map[int]*seVoteCount)
+
+	err := forEachStackExchangeRow(path, func(decoder *xml.Decoder, start *xml.StartElement, offset int64) *model.AppError {
+		var row seVoteRow
+		if err := decodeStackExchangeRow(decoder, start, &row); err != nil {
+			return err
+		}
+
+		post, ok := si.posts[row.PostId]
+		if !ok {
+			return nil
+		}
+
+		count, ok := counts[row.PostId]
+		if !ok {
+			count = &seVoteCount{}
+			counts[row.PostId] = count
+		}
+
+		switch row.VoteTypeId {
+		case seVoteTypeUpMod:
+			count.up++
+		case seVoteTypeDownMod:
+			count.down++
+		case seVoteTypeFavorite:
+			if err := si.importFavorite(post, row.UserId); err != nil {
+				si.logError("favorite", row.PostId, err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for postId, count := range counts {
+		if count.up == 0 && count.down == 0 {
+			continue
+		}
+
+		if err := si.app.Srv.Store.Post().SetVoteCounts(si.posts[postId].id, count.up, count.down); err != nil {
+			si.logError("post", postId, err)
+			continue
+		}
+
+		si.report.Votes += count.up + count.down
+	}
+
+	return nil
+}
+
+func (si *stackExchangeImporter) importFavorite(post *importedPost, seUserId *int) *model.AppError {
+	if seUserId == nil {
+		return nil
+	}
+
+	userId, ok := si.users[*seUserId]
+	if !ok {
+		return nil
+	}
+
+	// 見つからない場合はエラーではなくnilが返る
+	favorite, err := si.app.Srv.Store.UserFavoritePost().GetByPostIdForUser(userId, post.id)
+	if err != nil {
+		return err
+	}
+	if favorite != nil {
+		return nil
+	}
+
+	if err := si.app.Srv.Store.UserFavoritePost().Save(post.id, userId, si.teamId); err != nil {
+		return err
+	}
+
+	si.report.Favorites++
+	return nil
+}
+
+func (si *stackExchangeImporter) recomputePoints() *model.AppError {
+	userIds := make([]string, 0, len(si.users))
+	for _, userId := range si.users {
+		userIds = append(userIds, userId)
+	}
+
+	for start := 0; start < len(userIds); start += STACK_EXCHANGE_POINTS_BATCH {
+		end := start + STACK_EXCHANGE_POINTS_BATCH
+		if end > len(userIds) {
+			end = len(userIds)
+		}
+
+		if err := si.app.Srv.Store.User().RecomputePoints(si.teamId, userIds[start:end]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
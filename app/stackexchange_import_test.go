@@ -0,0 +1,226 @@
+package app
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/clear-ness/qa-discussion/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const stackExchangeTestDir = "testdata/stackexchange"
+
+func TestParseStackExchangeTags(t *testing.T) {
+	assert.Equal(t, "golang unit_testing", parseStackExchangeTags("<golang><unit-testing><c#>"))
+	assert.Equal(t, "python python_3_x", parseStackExchangeTags("|python|python-3.x|"))
+	assert.Equal(t, "golang", parseStackExchangeTags("<golang><golang>"))
+	assert.Equal(t, "", parseStackExchangeTags(""))
+}
+
+func TestParseStackExchangeDate(t *testing.T) {
+	assert.Equal(t, int64(1577934245123), parseStackExchangeDate("2020-01-02T03:04:05.123"))
+	assert.Equal(t, int64(1577934245000), parseStackExchangeDate("2020-01-02T03:04:05"))
+	assert.Equal(t, int64(0), parseStackExchangeDate(""))
+	assert.Equal(t, int64(0), parseStackExchangeDate("yesterday"))
+}
+
+func TestStackExchangeImport(t *testing.T) {
+	th := Setup(t)
+	defer th.TearDown()
+
+	t.Run("invalid site", func(t *testing.T) {
+		_, err := th.App.StackExchangeImport(stackExchangeTestDir, "Bad Site!", "")
+		require.NotNil(t, err)
+		assert.Equal(t, "app.stackexchange_import.site.app_error", err.Id)
+	})
+
+	t.Run("missing required file", func(t *testing.T) {
+		dir, tempErr := ioutil.TempDir("", "stackexchange")
+		require.NoError(t, tempErr)
+		defer os.RemoveAll(dir)
+
+		_, err := th.App.StackExchangeImport(dir, "missing", "")
+		require.NotNil(t, err)
+		assert.Equal(t, "app.stackexchange_import.missing_file.app_error", err.Id)
+	})
+
+	site := "fixture"
+
+	report, err := th.App.StackExchangeImport(stackExchangeTestDir, site, "")
+	require.Nil(t, err)
+	assert.Equal(t, &StackExchangeImportReport{
+		// 3人 + 退会済みユーザー
+		Users:     4,
+		Questions: 2,
+		// 親のない回答(13)は取り込まない
+		Answers:   2,
+		Comments:  1,
+		Revisions: 1,
+		// 存在しない投稿への投票と採用の票は数えない
+		Votes:     6,
+		Favorites: 1,
+		Skipped:   0,
+		// 回答13とコメント101
+		Errors: 2,
+	}, report)
+
+	t.Run("users", func(t *testing.T) {
+		alice := th.stackExchangeUser(t, site, 1)
+		assert.Equal(t, "alicesmith-1", alice.Username)
+		assert.Equal(t, site+"/user/1", alice.Props[STACK_EXCHANGE_PROP_ID])
+		assert.True(t, alice.EmailVerified)
+
+		// 使える文字が残らない表示名
+		assert.Equal(t, "user-3", th.stackExchangeUser(t, site, 3).Username)
+
+		deleted := th.stackExchangeUser(t, site, stackExchangeDeletedUserIndex)
+		assert.Equal(t, STACK_EXCHANGE_DELETED_USER+"-0", deleted.Username)
+	})
+
+	posts := th.stackExchangePosts(t, site)
+	require.Len(t, posts, 6)
+	for _, missing := range []string{"post/13", "post/14", "comment/101"} {
+		assert.NotContains(t, posts, missing)
+	}
+
+	t.Run("posts", func(t *testing.T) {
+		question := posts["post/10"]
+		assert.Equal(t, model.POST_TYPE_QUESTION, question.Type)
+		assert.Equal(t, "Table driven tests in Go", question.Title)
+		assert.Equal(t, "<p>How do I write table driven tests?</p>", question.Content)
+		assert.Equal(t, "golang unit_testing", question.Tags)
+		assert.Equal(t, int64(1577934245123), question.CreateAt)
+		assert.Equal(t, th.stackExchangeUser(t, site, 1).Id, question.UserId)
+		assert.Equal(t, posts["post/11"].Id, question.BestId)
+		assert.Equal(t, 3, question.UpVotes)
+		assert.Equal(t, 1, question.DownVotes)
+		assert.Zero(t, question.LockedAt)
+
+		answer := posts["post/11"]
+		assert.Equal(t, model.POST_TYPE_ANSWER, answer.Type)
+		assert.Equal(t, question.Id, answer.ParentId)
+		assert.Equal(t, question.Id, answer.RootId)
+		assert.Equal(t, 2, answer.UpVotes)
+
+		assert.Equal(t, th.stackExchangeUser(t, site, stackExchangeDeletedUserIndex).Id, posts["post/12"].UserId)
+
+		closed := posts["post/20"]
+		assert.Equal(t, "python python_3_x", closed.Tags)
+		assert.NotZero(t, closed.LockedAt)
+
+		comment := posts["comment/100"]
+		assert.Equal(t, model.POST_TYPE_COMMENT, comment.Type)
+		assert.Equal(t, question.Id, comment.ParentId)
+		assert.Equal(t, question.Id, comment.RootId)
+
+		// 編集前の内容は削除済みの履歴として残る
+		revision := posts["revision/aaaaaaaa-0000-0000-0000-000000000002"]
+		assert.Equal(t, question.Id, revision.OriginalId)
+		assert.Equal(t, "Table driven tests", revision.Title)
+		assert.Equal(t, "<p>How do I write tests?</p>", revision.Content)
+		assert.Equal(t, "golang", revision.Tags)
+		assert.NotZero(t, revision.DeleteAt)
+
+		favorite, err := th.Store.UserFavoritePost().GetByPostIdForUser(th.stackExchangeUser(t, site, 2).Id, question.Id)
+		require.Nil(t, err)
+		assert.Equal(t, question.Id, favorite.PostId)
+	})
+
+	t.Run("tags", func(t *testing.T) {
+		// Tags.xmlの件数ではなく、取り込んだ質問の数になる
+		for content, count := range map[string]int{"golang": 1, "unit_testing": 1, "python": 1, "python_3_x": 1} {
+			tags, err := th.Store.Tag().GetTags(&model.GetTagsOptions{Content: content, PerPage: 10})
+			require.Nil(t, err)
+			require.Len(t, tags, 1, content)
+			assert.Equal(t, count, tags[0].PostCount, content)
+		}
+	})
+
+	t.Run("points", func(t *testing.T) {
+		expected := map[int]int{
+			// 質問 + 回答の採用 + 賛成3票 - 反対1票
+			1: model.USER_POINT_FOR_CREATE_QUESTION + model.USER_POINT_FOR_SELECT_ANSWER + 3*model.USER_POINT_FOR_VOTED + model.USER_POINT_FOR_DOWN_VOTED,
+			// 質問 + 回答 + 賛成2票 + 採用された回答
+			2:                             model.USER_POINT_FOR_CREATE_QUESTION + model.USER_POINT_FOR_CREATE_ANSWER + 2*model.USER_POINT_FOR_VOTED + model.USER_POINT_FOR_SELECTED_ANSWER,
+			3:                             0,
+			stackExchangeDeletedUserIndex: model.USER_POINT_FOR_CREATE_ANSWER,
+		}
+
+		for id, points := range expected {
+			assert.Equal(t, points, th.stackExchangeUser(t, site, id).Points, id)
+		}
+	})
+
+	t.Run("re-run is idempotent", func(t *testing.T) {
+		report, err := th.App.StackExchangeImport(stackExchangeTestDir, site, "")
+		require.Nil(t, err)
+		assert.Equal(t, &StackExchangeImportReport{
+			// 投票数は加算ではなく設定し直す
+			Votes: 6,
+			// 投稿4件、コメント1件、編集履歴1件
+			Skipped: 6,
+			Errors:  2,
+		}, report)
+
+		assert.Len(t, th.stackExchangePosts(t, site), 6)
+
+		question := th.stackExchangePosts(t, site)["post/10"]
+		assert.Equal(t, 3, question.UpVotes)
+		assert.Equal(t, 1, question.DownVotes)
+
+		assert.Equal(t, model.USER_POINT_FOR_CREATE_ANSWER, th.stackExchangeUser(t, site, stackExchangeDeletedUserIndex).Points)
+		assert.Equal(t, 1, th.findTag(t, "golang").PostCount)
+	})
+
+	t.Run("import into a team", func(t *testing.T) {
+		owner := th.CreateUser(t)
+		team := th.CreateTeam(t, owner)
+
+		report, err := th.App.StackExchangeImport(stackExchangeTestDir, site, team.Id)
+		require.Nil(t, err)
+		// ユーザーは公開サイトへの取り込みで作成済み
+		assert.Equal(t, 0, report.Users)
+		assert.Equal(t, 2, report.Questions)
+		assert.Equal(t, 0, report.Skipped)
+
+		member, err := th.Store.Team().GetMember(team.Id, th.stackExchangeUser(t, site, 1).Id)
+		require.Nil(t, err)
+		// 公開サイトと同じ内容を取り込んだので、チームのポイントも同じになる
+		assert.Equal(t, th.stackExchangeUser(t, site, 1).Points, member.Points)
+	})
+}
+
+func (th *TestHelper) stackExchangeUser(tb testing.TB, site string, id int) *model.User {
+	user, err := th.Store.User().GetByEmail(fmt.Sprintf("%s-%d@%s", site, id, STACK_EXCHANGE_EMAIL_DOMAIN))
+	require.Nil(tb, err)
+
+	return user
+}
+
+// 元データのid("post/10"など) → 取り込んだ投稿
+func (th *TestHelper) stackExchangePosts(tb testing.TB, site string) map[string]*model.Post {
+	imported, err := th.Store.Post().GetImportedPosts("", STACK_EXCHANGE_PROP_ID, site+"/")
+	require.Nil(tb, err)
+
+	posts := map[string]*model.Post{}
+	for _, post := range imported {
+		full, err := th.Store.Post().GetSingle(post.Id, true)
+		require.Nil(tb, err)
+
+		posts[post.Props[STACK_EXCHANGE_PROP_ID].(string)[len(site)+1:]] = full
+	}
+
+	return posts
+}
+
+func (th *TestHelper) findTag(tb testing.TB, content string) *model.Tag {
+	tags, err := th.Store.Tag().GetTags(&model.GetTagsOptions{Content: content, PerPage: 10})
+	require.Nil(tb, err)
+	require.Len(tb, tags, 1)
+
+	return &tags[0]
+}
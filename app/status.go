@@ -7,7 +7,7 @@ import (
 )
 
 func (a *App) GetUserStatusesByIds(userIds []string) ([]*model.Status, *model.AppError) {
-	statuses, err := a.Srv.Store.Status().GetByIds(userIds)
+	statuses, err := a.Store().Status().GetByIds(userIds)
 	if err != nil {
 		return nil, model.NewAppError("GetUserStatusesByIds", "app.status.get.app_error", nil, err.Error(), http.StatusInternalServerError)
 	}
@@ -16,7 +16,7 @@ func (a *App) GetUserStatusesByIds(userIds []string) ([]*model.Status, *model.Ap
 }
 
 func (a *App) GetStatus(userId string) (*model.Status, *model.AppError) {
-	status, err := a.Srv.Store.Status().Get(userId)
+	status, err := a.Store().Status().Get(userId)
 	if err != nil {
 		return nil, model.NewAppError("GetStatus", "app.status.get.app_error", nil, err.Error(), http.StatusInternalServerError)
 	}
@@ -48,7 +48,7 @@ func (a *App) SetStatusOnline(userId string, manual bool) {
 	}
 
 	if status.Status != oldStatus || status.Manual != oldManual || status.LastActivityAt-oldTime > model.STATUS_MIN_UPDATE_TIME {
-		a.Srv.Store.Status().UpdateLastActivityAt(status.UserId, status.LastActivityAt)
+		a.Store().Status().UpdateLastActivityAt(status.UserId, status.LastActivityAt)
 	}
 }
 
@@ -60,7 +60,7 @@ func (a *App) SetStatusOffline(userId string, manual bool) {
 
 	status = &model.Status{UserId: userId, Status: model.STATUS_OFFLINE, Manual: manual, LastActivityAt: model.GetMillis(), ActiveTeam: ""}
 
-	a.Srv.Store.Status().SaveOrUpdate(status)
+	a.Store().Status().SaveOrUpdate(status)
 }
 
 func (a *App) SetStatusAwayIfNeeded(userId string, manual bool) {
@@ -87,7 +87,7 @@ func (a *App) SetStatusAwayIfNeeded(userId string, manual bool) {
 	status.Manual = manual
 	status.ActiveTeam = ""
 
-	a.Srv.Store.Status().SaveOrUpdate(status)
+	a.Store().Status().SaveOrUpdate(status)
 }
 
 func (a *App) IsUserAway(lastActivityAt int64) bool {
@@ -18,7 +18,7 @@ import (
 func (a *App) CreateTeam(team *model.Team) (*model.Team, *model.AppError) {
 	team.InviteId = ""
 	// team_store内でPreSave()によりinvite idがセットされる
-	rteam, err := a.Srv.Store.Team().Save(team)
+	rteam, err := a.Store().Team().Save(team)
 	if err != nil {
 		return nil, err
 	}
@@ -99,11 +99,11 @@ func (a *App) joinUserToTeam(team *model.Team, user *model.User, isAdmin bool) (
 		DeleteAt: 0,
 	}
 
-	rtm, err := a.Srv.Store.Team().GetMember(team.Id, user.Id)
+	rtm, err := a.Store().Team().GetMember(team.Id, user.Id)
 	if err != nil {
 		var tmr *model.TeamMember
 		// 新規作成を試みる
-		tmr, err = a.Srv.Store.Team().SaveMember(tm, *a.Config().TeamSettings.MaxUsersPerTeam)
+		tmr, err = a.Store().Team().SaveMember(tm, *a.Config().TeamSettings.MaxUsersPerTeam)
 		if err != nil {
 			return nil, false, err
 		}
@@ -117,7 +117,7 @@ func (a *App) joinUserToTeam(team *model.Team, user *model.User, isAdmin bool) (
 
 	// これ以降は該当team memberが既に削除済み状態で存在する場合
 
-	membersCount, err := a.Srv.Store.Team().GetActiveMemberCount(tm.TeamId)
+	membersCount, err := a.Store().Team().GetActiveMemberCount(tm.TeamId)
 	if err != nil {
 		return nil, false, err
 	}
@@ -129,7 +129,7 @@ func (a *App) joinUserToTeam(team *model.Team, user *model.User, isAdmin bool) (
 	// 以前の削除前の状態を考慮し、未削除状態に更新する
 	tm.Points = rtm.Points
 
-	member, err := a.Srv.Store.Team().UpdateMember(tm)
+	member, err := a.Store().Team().UpdateMember(tm)
 	if err != nil {
 		return nil, false, err
 	}
@@ -155,13 +155,18 @@ func (a *App) JoinUserToTeam(team *model.Team, user *model.User, isAdmin bool) *
 	if tm != nil {
 		// L1キャッシュ(user session)にはteam membersも含まれるため
 		a.ClearSessionCacheForUser(user.Id)
+
+		a.triggerWebhookEvent(team.Id, model.WEBHOOK_EVENT_MEMBER_JOINED, user.Id, &model.WebhookMemberData{
+			MemberId:   user.Id,
+			MemberName: user.Username,
+		})
 	}
 
 	return nil
 }
 
 func (a *App) GetTeamsForUser(userId string) ([]*model.Team, *model.AppError) {
-	teams, err := a.Srv.Store.Team().GetTeamsByUserId(userId)
+	teams, err := a.Store().Team().GetTeamsByUserId(userId)
 	for _, team := range teams {
 		team.TeamImageLink = team.GetTeamImageLink(&a.Config().FileSettings)
 	}
@@ -188,7 +193,7 @@ func (a *App) SanitizeTeams(session model.Session, teams []*model.Team) []*model
 }
 
 func (a *App) GetTeam(teamId string) (*model.Team, *model.AppError) {
-	team, err := a.Srv.Store.Team().Get(teamId)
+	team, err := a.Store().Team().Get(teamId)
 	if err != nil {
 		return nil, err
 	}
@@ -215,7 +220,7 @@ func (a *App) UpdateTeam(team *model.Team) (*model.Team, *model.AppError) {
 }
 
 func (a *App) updateTeamUnsanitized(team *model.Team) (*model.Team, *model.AppError) {
-	return a.Srv.Store.Team().Update(team)
+	return a.Store().Team().Update(team)
 }
 
 func (a *App) PermanentDeleteTeamId(teamId string) *model.AppError {
@@ -229,15 +234,15 @@ func (a *App) PermanentDeleteTeamId(teamId string) *model.AppError {
 
 func (a *App) PermanentDeleteTeam(team *model.Team) *model.AppError {
 	team.DeleteAt = model.GetMillis()
-	if _, err := a.Srv.Store.Team().Update(team); err != nil {
+	if _, err := a.Store().Team().Update(team); err != nil {
 		return err
 	}
 
-	if err := a.Srv.Store.Team().RemoveAllMembersByTeam(team.Id); err != nil {
+	if err := a.Store().Team().RemoveAllMembersByTeam(team.Id); err != nil {
 		return err
 	}
 
-	if err := a.Srv.Store.Team().PermanentDelete(team.Id); err != nil {
+	if err := a.Store().Team().PermanentDelete(team.Id); err != nil {
 		return err
 	}
 
@@ -251,7 +256,7 @@ func (a *App) SoftDeleteTeam(teamId string) *model.AppError {
 	}
 
 	team.DeleteAt = model.GetMillis()
-	if team, err = a.Srv.Store.Team().Update(team); err != nil {
+	if team, err = a.Store().Team().Update(team); err != nil {
 		return err
 	}
 
@@ -266,7 +271,7 @@ func (a *App) RegenerateTeamInviteId(teamId string) (*model.Team, *model.AppErro
 
 	team.InviteId = model.NewId()
 
-	updatedTeam, err := a.Srv.Store.Team().Update(team)
+	updatedTeam, err := a.Store().Team().Update(team)
 	if err != nil {
 		return nil, err
 	}
@@ -277,14 +282,14 @@ func (a *App) RegenerateTeamInviteId(teamId string) (*model.Team, *model.AppErro
 func (a *App) prepareInviteNewUsersToTeam(teamId, senderId string) (*model.User, *model.Team, *model.AppError) {
 	tchan := make(chan store.StoreResult, 1)
 	go func() {
-		team, err := a.Srv.Store.Team().Get(teamId)
+		team, err := a.Store().Team().Get(teamId)
 		tchan <- store.StoreResult{Data: team, Err: err}
 		close(tchan)
 	}()
 
 	uchan := make(chan store.StoreResult, 1)
 	go func() {
-		user, err := a.Srv.Store.User().Get(senderId)
+		user, err := a.Store().User().Get(senderId)
 		uchan <- store.StoreResult{Data: user, Err: err}
 		close(uchan)
 	}()
@@ -362,7 +367,7 @@ func (a *App) AddTeamMemberByInviteId(inviteId, userId string) (*model.TeamMembe
 }
 
 func (a *App) AddUserToTeamByToken(userId string, tokenId string) (*model.Team, *model.AppError) {
-	token, err := a.Srv.Store.Token().GetByToken(tokenId)
+	token, err := a.Store().Token().GetByToken(tokenId)
 	if err != nil {
 		return nil, model.NewAppError("AddUserToTeamByToken", "api.user.create_user.signup_link_invalid.app_error", nil, err.Error(), http.StatusBadRequest)
 	}
@@ -380,14 +385,14 @@ func (a *App) AddUserToTeamByToken(userId string, tokenId string) (*model.Team,
 
 	tchan := make(chan store.StoreResult, 1)
 	go func() {
-		team, err := a.Srv.Store.Team().Get(tokenData["teamId"])
+		team, err := a.Store().Team().Get(tokenData["teamId"])
 		tchan <- store.StoreResult{Data: team, Err: err}
 		close(tchan)
 	}()
 
 	uchan := make(chan store.StoreResult, 1)
 	go func() {
-		user, err := a.Srv.Store.User().Get(userId)
+		user, err := a.Store().User().Get(userId)
 		uchan <- store.StoreResult{Data: user, Err: err}
 		close(uchan)
 	}()
@@ -418,14 +423,14 @@ func (a *App) AddUserToTeamByToken(userId string, tokenId string) (*model.Team,
 func (a *App) AddUserToTeamByInviteId(inviteId string, userId string) (*model.Team, *model.AppError) {
 	tchan := make(chan store.StoreResult, 1)
 	go func() {
-		team, err := a.Srv.Store.Team().GetByInviteId(inviteId)
+		team, err := a.Store().Team().GetByInviteId(inviteId)
 		tchan <- store.StoreResult{Data: team, Err: err}
 		close(tchan)
 	}()
 
 	uchan := make(chan store.StoreResult, 1)
 	go func() {
-		user, err := a.Srv.Store.User().Get(userId)
+		user, err := a.Store().User().Get(userId)
 		uchan <- store.StoreResult{Data: user, Err: err}
 		close(uchan)
 	}()
@@ -450,32 +455,63 @@ func (a *App) AddUserToTeamByInviteId(inviteId string, userId string) (*model.Te
 }
 
 func (a *App) GetTeamMember(teamId, userId string) (*model.TeamMember, *model.AppError) {
-	return a.Srv.Store.Team().GetMember(teamId, userId)
+	return a.Store().Team().GetMember(teamId, userId)
 }
 
-func (a *App) GetTeamMembers(teamId string, offset int, limit int, teamMembersGetOptions *model.TeamMembersGetOptions) ([]*model.TeamMember, *model.AppError) {
-	return a.Srv.Store.Team().GetMembers(teamId, offset, limit, teamMembersGetOptions)
+func (a *App) GetTeamMembers(teamId string, offset int, limit int, teamMembersGetOptions *model.TeamMembersGetOptions) ([]*model.TeamMember, model.CursorPage, *model.AppError) {
+	members, err := a.Store().Team().GetMembers(teamId, offset, limit, teamMembersGetOptions)
+	if err != nil {
+		return nil, model.CursorPage{}, err
+	}
+
+	var cursor *model.Cursor
+	sort := ""
+	if teamMembersGetOptions != nil {
+		cursor = teamMembersGetOptions.Cursor
+		sort = teamMembersGetOptions.CursorSort()
+	}
+
+	usernames := map[string]string{}
+	if sort == model.TEAM_MEMBER_SORT_TYPE_USERNAME && len(members) > 0 {
+		// ユーザー名はTeamMemberに無いので、カーソルに使う先頭と末尾のユーザーだけ読み込む
+		for _, member := range []*model.TeamMember{members[0], members[len(members)-1]} {
+			user, err := a.Store().User().Get(member.UserId)
+			if err != nil {
+				return nil, model.CursorPage{}, err
+			}
+			usernames[member.UserId] = user.Username
+		}
+	}
+
+	cursorPage := model.NewCursorPage(len(members), limit, cursor, offset > 0, func(i int) *model.Cursor {
+		if sort == model.TEAM_MEMBER_SORT_TYPE_USERNAME {
+			return model.NewCursor(model.CURSOR_TYPE_TEAM_MEMBERS, sort, 0, usernames[members[i].UserId], members[i].UserId)
+		}
+		return model.NewCursor(model.CURSOR_TYPE_TEAM_MEMBERS, sort, 0, members[i].UserId)
+	})
+
+	return members, cursorPage, nil
 }
 
 func (a *App) GetTeamMembersByIds(teamId string, userIds []string) ([]*model.TeamMember, *model.AppError) {
-	return a.Srv.Store.Team().GetMembersByIds(teamId, userIds)
+	return a.Store().Team().GetMembersByIds(teamId, userIds)
 }
 
 func (a *App) GetTeamMembersForUser(userId string) ([]*model.TeamMember, *model.AppError) {
-	return a.Srv.Store.Team().GetTeamsForUser(userId)
+	return a.Store().Team().GetTeamsForUser(userId)
 }
 
 func (a *App) RemoveUserFromTeam(teamId string, userId string, requestorId string) *model.AppError {
 	tchan := make(chan store.StoreResult, 1)
 	go func() {
-		team, err := a.Srv.Store.Team().Get(teamId)
+		team, err := a.Store().Team().Get(teamId)
 		tchan <- store.StoreResult{Data: team, Err: err}
 		close(tchan)
 	}()
 
 	uchan := make(chan store.StoreResult, 1)
 	go func() {
-		user, err := a.Srv.Store.User().Get(userId)
+		user, err := a.Store().User().Get(userId)
 		uchan <- store.StoreResult{Data: user, Err: err}
 		close(uchan)
 	}()
@@ -512,7 +548,7 @@ func (a *App) LeaveTeam(team *model.Team, user *model.User, requestorId string)
 
 	// そのteamに関連する、現在所属しているgroupから離脱させる(物理削除)
 	var groupList *model.UserGroupList
-	if groupList, err = a.Srv.Store.UserGroup().GetGroups(team.Id, user.Id, true); err != nil {
+	if groupList, err = a.Store().UserGroup().GetGroups(team.Id, user.Id, true); err != nil {
 		if err.Id == store.MISSING_GROUPS_ERROR {
 			groupList = &model.UserGroupList{}
 		} else {
@@ -521,7 +557,7 @@ func (a *App) LeaveTeam(team *model.Team, user *model.User, requestorId string)
 	}
 
 	for _, group := range *groupList {
-		if err = a.Srv.Store.UserGroup().RemoveMember(group.Id, user.Id); err != nil {
+		if err = a.Store().UserGroup().RemoveMember(group.Id, user.Id); err != nil {
 			return err
 		}
 	}
@@ -539,13 +575,19 @@ func (a *App) RemoveTeamMemberFromTeam(teamMember *model.TeamMember, requestorId
 	teamMember.Type = ""
 	teamMember.DeleteAt = model.GetMillis()
 
-	if _, err := a.Srv.Store.Team().UpdateMember(teamMember); err != nil {
+	if _, err := a.Store().Team().UpdateMember(teamMember); err != nil {
 		return err
 	}
 
 	// L1キャッシュ(user session)にはteam membersも含まれるため
 	a.ClearSessionCacheForUser(teamMember.UserId)
 
+	data := &model.WebhookMemberData{MemberId: teamMember.UserId}
+	if user, err := a.Store().User().Get(teamMember.UserId); err == nil {
+		data.MemberName = user.Username
+	}
+	a.triggerWebhookEvent(teamMember.TeamId, model.WEBHOOK_EVENT_MEMBER_LEFT, requestorId, data)
+
 	return nil
 }
 
@@ -603,7 +645,7 @@ func (a *App) SetTeamIconFromFile(team *model.Team, file io.Reader) *model.AppEr
 		return model.NewAppError("SetTeamIcon", "api.team.set_team_icon.write_file.app_error", nil, "", http.StatusInternalServerError)
 	}
 
-	if err := a.Srv.Store.Team().UpdateLastTeamIconUpdate(team.Id, curTime); err != nil {
+	if err := a.Store().Team().UpdateLastTeamIconUpdate(team.Id, curTime); err != nil {
 		return model.NewAppError("SetTeamIcon", "api.team.team_icon.update.app_error", nil, err.Error(), http.StatusBadRequest)
 	}
 
@@ -616,7 +658,7 @@ func (a *App) RemoveTeamIcon(teamId string) *model.AppError {
 		return model.NewAppError("RemoveTeamIcon", "api.team.remove_team_icon.get_team.app_error", nil, err.Error(), http.StatusBadRequest)
 	}
 
-	if err := a.Srv.Store.Team().UpdateLastTeamIconUpdate(teamId, 0); err != nil {
+	if err := a.Store().Team().UpdateLastTeamIconUpdate(teamId, 0); err != nil {
 		return model.NewAppError("RemoveTeamIcon", "api.team.team_icon.update.app_error", nil, err.Error(), http.StatusBadRequest)
 	}
 
@@ -624,7 +666,7 @@ func (a *App) RemoveTeamIcon(teamId string) *model.AppError {
 }
 
 func (a *App) AutocompletePublicTeams(name string) ([]*model.Team, *model.AppError) {
-	return a.Srv.Store.Team().AutocompletePublic(name)
+	return a.Store().Team().AutocompletePublic(name)
 }
 
 func (a *App) UpdateTeamMemberType(teamId string, userId string, newType string) (*model.TeamMember, *model.AppError) {
@@ -643,13 +685,13 @@ func (a *App) UpdateTeamMemberType(teamId string, userId string, newType string)
 		Type:                model.TEAM_MEMBER_TYPE_ADMIN,
 	}
 	// team adminの数が1人以下ならadmin → normalに変更不能にする
-	members, err := a.GetTeamMembers(teamId, 0, 10, teamMembersGetOptions)
+	members, _, err := a.GetTeamMembers(teamId, 0, 10, teamMembersGetOptions)
 	if member.Type == model.TEAM_MEMBER_TYPE_ADMIN && newType != model.TEAM_MEMBER_TYPE_ADMIN && len(members) <= 1 {
 		return nil, model.NewAppError("UpdateTeamMemberType", "api.team.update_team_member_type.missing_admin.app_error", nil, "", http.StatusBadRequest)
 	}
 
 	member.Type = newType
-	member, err = a.Srv.Store.Team().UpdateMember(member)
+	member, err = a.Store().Team().UpdateMember(member)
 	if err != nil {
 		return nil, err
 	}
@@ -665,7 +707,7 @@ func (a *App) GetAnalytics(analyticKey string, teamId string) (model.Analytics,
 		var rows model.Analytics = make([]*model.Analytic, 1)
 		rows[0] = &model.Analytic{Name: "answered_rate", Value: 0}
 
-		rate, err := a.Srv.Store.Post().GetAnsweredRate(teamId)
+		rate, err := a.Store().Post().GetAnsweredRate(teamId)
 		if err != nil {
 			return nil, err
 		}
@@ -673,13 +715,13 @@ func (a *App) GetAnalytics(analyticKey string, teamId string) (model.Analytics,
 
 		return rows, nil
 	} else if analyticKey == "post_counts_day" {
-		return a.Srv.Store.Post().AnalyticsPostCounts(teamId)
+		return a.Store().Post().AnalyticsPostCounts(teamId)
 	} else if analyticKey == "active_author_counts_day" {
-		return a.Srv.Store.Post().AnalyticsActiveAuthorCounts(teamId)
+		return a.Store().Post().AnalyticsActiveAuthorCounts(teamId)
 	} else if analyticKey == "post_views_counts_day" {
-		return a.Srv.Store.PostViewsHistory().AnalyticsPostViewsHistoryCounts(teamId)
+		return a.Store().PostViewsHistory().AnalyticsPostViewsHistoryCounts(teamId)
 	} else if analyticKey == "up_vote_counts_day" {
-		return a.Srv.Store.Vote().AnalyticsVoteCounts(teamId, model.VOTE_TYPE_UP_VOTE)
+		return a.Store().Vote().AnalyticsVoteCounts(teamId, model.VOTE_TYPE_UP_VOTE)
 	}
 
 	return nil, nil
@@ -0,0 +1,36 @@
+package app
+
+import (
+	"context"
+
+	"github.com/clear-ness/qa-discussion/mlog"
+	"github.com/clear-ness/qa-discussion/services/tracing"
+	"github.com/clear-ness/qa-discussion/store"
+	"github.com/clear-ness/qa-discussion/store/tracinglayer"
+)
+
+// トレースが無効な場合はs.Tracerをnilのままにする
+func (s *Server) initTracing() error {
+	if !*s.Config().TracingSettings.Enable {
+		return nil
+	}
+
+	tracer, err := tracing.New(&s.Config().TracingSettings)
+	if err != nil {
+		return err
+	}
+	s.Tracer = tracer
+
+	mlog.Info("Tracing is enabled", mlog.String("exporter", *s.Config().TracingSettings.Exporter))
+
+	return nil
+}
+
+// storeメソッド、redis、ESの呼び出しがctxのspanの子になるstoreを返す
+func (s *Server) TracedStore(ctx context.Context) store.Store {
+	return tracinglayer.New(s.Store.WithContext(ctx), ctx)
+}
+
+func (a *App) TraceId() string {
+	return tracing.TraceIdFromContext(a.Context())
+}
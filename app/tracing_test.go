@@ -0,0 +1,57 @@
+package app
+
+import (
+	"testing"
+
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/api/trace"
+
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/clear-ness/qa-discussion/services/tracing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracingDisabled(t *testing.T) {
+	th := Setup(t)
+	defer th.TearDown()
+
+	require.False(t, *th.Server.Config().TracingSettings.Enable)
+	require.NoError(t, th.Server.initTracing())
+	assert.Nil(t, th.Server.Tracer)
+
+	// 無効な場合はspanを記録せず、trace idも空になる
+	span, ctx := tracing.StartSpan(th.App.Context(), "disabled")
+	defer span.End()
+	assert.False(t, span.IsRecording())
+
+	th.App.SetContext(ctx)
+	assert.Equal(t, "", th.App.TraceId())
+
+	// spanの無いcontextでもstoreはそのまま使える
+	user := th.CreateUser(t)
+	got, err := th.Server.TracedStore(ctx).User().Get(user.Id)
+	require.Nil(t, err)
+	assert.Equal(t, user.Id, got.Id)
+}
+
+func TestTracingEnabled(t *testing.T) {
+	th := Setup(t)
+	defer th.TearDown()
+	defer global.SetTracerProvider(trace.NoopTracerProvider())
+
+	th.UpdateConfig(func(cfg *model.Config) {
+		*cfg.TracingSettings.Enable = true
+		*cfg.TracingSettings.Exporter = model.TRACING_EXPORTER_STDOUT
+		*cfg.TracingSettings.SampleRate = 1
+	})
+	require.NoError(t, th.Server.initTracing())
+	require.NotNil(t, th.Server.Tracer)
+
+	span, ctx := tracing.StartSpan(th.App.Context(), "enabled")
+	defer span.End()
+
+	th.App.SetContext(ctx)
+	assert.Equal(t, span.SpanContext().TraceID.String(), th.App.TraceId())
+}
@@ -67,7 +67,7 @@ func (a *App) CreateVerifyEmailToken(userId string, newEmail string) (*model.Tok
 
 	token := model.NewToken(TOKEN_TYPE_VERIFY_EMAIL, string(jsonData))
 
-	if err := a.Srv.Store.Token().Save(token); err != nil {
+	if err := a.Store().Token().Save(token); err != nil {
 		return nil, err
 	}
 
@@ -75,7 +75,7 @@ func (a *App) CreateVerifyEmailToken(userId string, newEmail string) (*model.Tok
 }
 
 func (a *App) GetVerifyEmailToken(token string) (*model.Token, *model.AppError) {
-	rtoken, err := a.Srv.Store.Token().GetByToken(token)
+	rtoken, err := a.Store().Token().GetByToken(token)
 	if err != nil {
 		return nil, model.NewAppError("GetVerifyEmailToken", "api.user.verify_email.bad_link.app_error", nil, err.Error(), http.StatusBadRequest)
 	}
@@ -114,8 +114,9 @@ func (a *App) VerifyEmailFromToken(userSuppliedTokenString string) *model.AppErr
 	}
 
 	if user.Email != tokenData.Email {
+		background := a.Detached()
 		a.Srv.Go(func() {
-			if err := a.SendEmailChangeEmail(user.Email, tokenData.Email, a.GetSiteURL()); err != nil {
+			if err := background.SendEmailChangeEmail(user.Email, tokenData.Email, background.GetSiteURL()); err != nil {
 				mlog.Error("Failed to send email change email", mlog.Err(err))
 			}
 		})
@@ -129,13 +130,13 @@ func (a *App) VerifyEmailFromToken(userSuppliedTokenString string) *model.AppErr
 }
 
 func (a *App) GetUser(userId string) (*model.User, *model.AppError) {
-	user, err := a.Srv.Store.User().Get(userId)
+	user, err := a.Store().User().Get(userId)
 	user.ProfileImageLink = user.GetProfileImageLink(&a.Config().FileSettings)
 	return user, err
 }
 
 func (a *App) GetUserByEmail(email string) (*model.User, *model.AppError) {
-	user, err := a.Srv.Store.User().GetByEmail(email)
+	user, err := a.Store().User().GetByEmail(email)
 	if err != nil {
 		if err.Id == store.MISSING_ACCOUNT_ERROR {
 			err.StatusCode = http.StatusNotFound
@@ -151,7 +152,7 @@ func (a *App) GetUserByEmail(email string) (*model.User, *model.AppError) {
 }
 
 func (a *App) GetUsers(userIds []string) ([]*model.User, *model.AppError) {
-	users, err := a.Srv.Store.User().GetByIds(userIds)
+	users, err := a.Store().User().GetByIds(userIds)
 	for _, user := range users {
 		user.ProfileImageLink = user.GetProfileImageLink(&a.Config().FileSettings)
 		// TODO: ここでsanitizeする様に
@@ -160,7 +161,7 @@ func (a *App) GetUsers(userIds []string) ([]*model.User, *model.AppError) {
 }
 
 func (a *App) GetUsersByDates(options *model.GetUsersOptions) ([]*model.User, *model.AppError) {
-	users, err := a.Srv.Store.User().GetUsersByDates(options)
+	users, err := a.Store().User().GetUsersByDates(options)
 	for _, user := range users {
 		user.ProfileImageLink = user.GetProfileImageLink(&a.Config().FileSettings)
 
@@ -173,7 +174,7 @@ func (a *App) GetUsersByDates(options *model.GetUsersOptions) ([]*model.User, *m
 }
 
 func (a *App) VerifyUserEmail(userId, email string) *model.AppError {
-	_, err := a.Srv.Store.User().VerifyEmail(userId, email)
+	_, err := a.Store().User().VerifyEmail(userId, email)
 	if err != nil {
 		return err
 	}
@@ -184,7 +185,7 @@ func (a *App) VerifyUserEmail(userId, email string) *model.AppError {
 }
 
 func (a *App) GetPasswordRecoveryToken(token string) (*model.Token, *model.AppError) {
-	rtoken, err := a.Srv.Store.Token().GetByToken(token)
+	rtoken, err := a.Store().Token().GetByToken(token)
 	if err != nil {
 		return nil, model.NewAppError("GetPasswordRecoveryToken", "api.user.reset_password.invalid_link.app_error", nil, err.Error(), http.StatusBadRequest)
 	}
@@ -197,14 +198,14 @@ func (a *App) GetPasswordRecoveryToken(token string) (*model.Token, *model.AppEr
 }
 
 func (a *App) DeleteToken(token *model.Token) *model.AppError {
-	return a.Srv.Store.Token().Delete(token.Token)
+	return a.Store().Token().Delete(token.Token)
 }
 
 func (a *App) CreateNormalUser(user *model.User) (*model.User, *model.AppError) {
 	user.Type = model.USER_TYPE_NORMAL
 
 	if *a.Config().ServiceSettings.EnableAdminUser {
-		count, err := a.Srv.Store.User().Count(&model.UserCountOptions{IncludeDeleted: true})
+		count, err := a.Store().User().Count(&model.UserCountOptions{IncludeDeleted: true})
 		if err != nil {
 			return nil, err
 		}
@@ -222,11 +223,13 @@ func (a *App) CreateNormalUser(user *model.User) (*model.User, *model.AppError)
 }
 
 func (a *App) createUser(user *model.User) (*model.User, *model.AppError) {
-	if err := a.IsPasswordValid(user.Password); err != nil {
-		return nil, err
+	if !user.IsSSOUser() {
+		if err := a.IsPasswordValid(user.Password); err != nil {
+			return nil, err
+		}
 	}
 
-	ruser, err := a.Srv.Store.User().Save(user)
+	ruser, err := a.Store().User().Save(user)
 	if err != nil {
 		mlog.Error("Couldn't save the user", mlog.Err(err))
 		return nil, err
@@ -252,22 +255,24 @@ func (a *App) UpdateUser(user *model.User) (*model.User, *model.AppError) {
 		}
 	}
 
-	userUpdate, err := a.Srv.Store.User().Update(user, false)
+	userUpdate, err := a.Store().User().Update(user, false)
 	if err != nil {
 		return nil, err
 	}
 
 	if userUpdate.New.Email != userUpdate.Old.Email || newEmail != "" {
+		background := a.Detached()
 		a.Srv.Go(func() {
-			if err := a.SendEmailVerification(userUpdate.New, newEmail); err != nil {
+			if err := background.SendEmailVerification(userUpdate.New, newEmail); err != nil {
 				mlog.Error("Failed to send email verification", mlog.Err(err))
 			}
 		})
 	}
 
 	if userUpdate.New.Username != userUpdate.Old.Username {
+		background := a.Detached()
 		a.Srv.Go(func() {
-			if err := a.SendChangeUsernameEmail(userUpdate.Old.Username, userUpdate.New.Username, userUpdate.New.Email, a.GetSiteURL()); err != nil {
+			if err := background.SendChangeUsernameEmail(userUpdate.Old.Username, userUpdate.New.Username, userUpdate.New.Email, background.GetSiteURL()); err != nil {
 				mlog.Error("Failed to send change username email", mlog.Err(err))
 			}
 		})
@@ -294,7 +299,7 @@ func (a *App) UpdateUserType(userId string, newType string) (*model.User, *model
 
 	user.Type = newType
 
-	userUpdate, err := a.Srv.Store.User().Update(user, true)
+	userUpdate, err := a.Store().User().Update(user, true)
 	if err != nil {
 		return nil, err
 	}
@@ -340,8 +345,9 @@ func (a *App) UpdatePasswordSendEmail(user *model.User, newPassword, method stri
 		return err
 	}
 
+	background := a.Detached()
 	a.Srv.Go(func() {
-		if err := a.SendPasswordChangeCompletedEmail(user.Email, method, a.GetSiteURL()); err != nil {
+		if err := background.SendPasswordChangeCompletedEmail(user.Email, method, background.GetSiteURL()); err != nil {
 			mlog.Error("Failed to send password change completed email", mlog.Err(err))
 		}
 	})
@@ -356,7 +362,7 @@ func (a *App) UpdatePassword(user *model.User, newPassword string) *model.AppErr
 
 	hashedPassword := model.HashPassword(newPassword)
 
-	if err := a.Srv.Store.User().UpdatePassword(user.Id, hashedPassword); err != nil {
+	if err := a.Store().User().UpdatePassword(user.Id, hashedPassword); err != nil {
 		return model.NewAppError("UpdatePassword", "api.user.update_password.failed.app_error", nil, err.Error(), http.StatusInternalServerError)
 	}
 
@@ -410,6 +416,10 @@ func (a *App) SendPasswordReset(email string, siteURL string) *model.AppError {
 		return nil
 	}
 
+	if user.IsSSOUser() {
+		return model.NewAppError("SendPasswordReset", "api.user.send_password_reset.sso.app_error", nil, "userId="+user.Id, http.StatusBadRequest)
+	}
+
 	token, err := a.CreatePasswordRecoveryToken(user.Id, user.Email)
 	if err != nil {
 		return err
@@ -434,7 +444,7 @@ func (a *App) CreatePasswordRecoveryToken(userId, email string) (*model.Token, *
 
 	token := model.NewToken(TOKEN_TYPE_PASSWORD_RECOVERY, string(jsonData))
 
-	if err := a.Srv.Store.Token().Save(token); err != nil {
+	if err := a.Store().Token().Save(token); err != nil {
 		return nil, err
 	}
 
@@ -442,11 +452,11 @@ func (a *App) CreatePasswordRecoveryToken(userId, email string) (*model.Token, *
 }
 
 func (a *App) UpdateLastInboxMessageViewedForUser(message *model.InboxMessage, userId string) *model.AppError {
-	return a.Srv.Store.User().UpdateLastInboxMessageViewed(message, userId)
+	return a.Store().User().UpdateLastInboxMessageViewed(message, userId)
 }
 
 func (a *App) SuspendUser(userId string, suspendSpan string, moderatorId string) *model.AppError {
-	return a.Srv.Store.User().SuspendUser(userId, suspendSpan, moderatorId)
+	return a.Store().User().SuspendUser(userId, suspendSpan, moderatorId)
 }
 
 func (a *App) DeleteUser(userId string, sessionUserId string) *model.AppError {
@@ -481,11 +491,16 @@ func (a *App) DeleteUser(userId string, sessionUserId string) *model.AppError {
 	// (キャッシュも消える)
 
 	// normal users can self delete
-	if err := a.Srv.Store.User().Delete(userId, model.GetMillis(), sessionUserId); err != nil {
+	return a.DeactivateUser(user.Id, sessionUserId)
+}
+
+// 権限を確認せずに退会させる。管理コマンドからはdeleteByIdが空になる
+func (a *App) DeactivateUser(userId string, deleteById string) *model.AppError {
+	if err := a.Store().User().Delete(userId, model.GetMillis(), deleteById); err != nil {
 		return err
 	}
 
-	if err := a.RevokeAllSessions(user.Id); err != nil {
+	if err := a.RevokeAllSessions(userId); err != nil {
 		return err
 	}
 
@@ -543,7 +558,7 @@ func (a *App) SetProfileImageFromFile(userId string, file io.Reader) (string, *m
 	}
 
 	// not save FileInfos
-	if err := a.Srv.Store.User().UpdateLastPictureUpdate(userId, curTime); err != nil {
+	if err := a.Store().User().UpdateLastPictureUpdate(userId, curTime); err != nil {
 		return "", model.NewAppError("SetProfileImage", "api.user.upload_profile_user.upload_profile.app_error", nil, "", http.StatusInternalServerError)
 	}
 
@@ -562,7 +577,7 @@ func (a *App) CreateUserWithToken(user *model.User, token *model.Token) (*model.
 
 	tokenData := model.MapFromJson(strings.NewReader(token.Extra))
 
-	team, err := a.Srv.Store.Team().Get(tokenData["teamId"])
+	team, err := a.Store().Team().Get(tokenData["teamId"])
 	if err != nil {
 		return nil, err
 	}
@@ -597,7 +612,7 @@ func (a *App) CreateUserWithToken(user *model.User, token *model.Token) (*model.
 }
 
 func (a *App) CreateUserWithInviteId(user *model.User, inviteId string) (*model.User, *model.AppError) {
-	team, err := a.Srv.Store.Team().GetByInviteId(inviteId)
+	team, err := a.Store().Team().GetByInviteId(inviteId)
 	if err != nil {
 		return nil, err
 	}
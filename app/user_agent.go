@@ -113,3 +113,12 @@ func getBrowserName(ua *uasurfer.UserAgent, userAgentString string) string {
 
 	return browserNames[uasurfer.BrowserUnknown]
 }
+
+// クローラーの閲覧は閲覧数に数えない
+func isBotUserAgent(userAgentString string) bool {
+	if userAgentString == "" {
+		return false
+	}
+
+	return uasurfer.Parse(userAgentString).IsBot()
+}
@@ -8,12 +8,17 @@ import (
 	"github.com/clear-ness/qa-discussion/model"
 )
 
-func (a *App) GetUserFavoritePostsForUser(toDate int64, userId string, page, perPage int, limitContent bool, teamId string) ([]*model.UserFavoritePostWithPost, int64, *model.AppError) {
-	favoritePosts, totalCount, err := a.Srv.Store.UserFavoritePost().GetUserFavoritePostsBeforeTime(toDate, userId, page, perPage, true, teamId)
+func (a *App) GetUserFavoritePostsForUser(toDate int64, userId string, page, perPage int, limitContent bool, teamId string, cursor *model.Cursor) ([]*model.UserFavoritePostWithPost, int64, model.CursorPage, *model.AppError) {
+	favoritePosts, totalCount, err := a.Store().UserFavoritePost().GetUserFavoritePostsBeforeTime(toDate, userId, page, perPage, true, teamId, cursor)
 	if err != nil {
-		return nil, int64(0), err
+		return nil, int64(0), model.CursorPage{}, err
 	}
 
+	// 投稿が削除されたお気に入りは返さないので、取得したお気に入りからカーソルを作る
+	cursorPage := model.NewCursorPage(len(favoritePosts), perPage, cursor, page > 0, func(i int) *model.Cursor {
+		return model.NewCursor(model.CURSOR_TYPE_FAVORITE_POSTS, "", favoritePosts[i].CreateAt, favoritePosts[i].PostId)
+	})
+
 	postIdsMaps := map[string]bool{}
 	for _, favoritePost := range favoritePosts {
 		postIdsMaps[favoritePost.PostId] = true
@@ -24,9 +29,9 @@ func (a *App) GetUserFavoritePostsForUser(toDate int64, userId string, page, per
 		postIds = append(postIds, key)
 	}
 
-	posts, err := a.Srv.Store.Post().GetPostsByIds(postIds)
+	posts, err := a.Store().Post().GetPostsByIds(postIds)
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, model.CursorPage{}, err
 	}
 
 	option := model.SetPostMetadataOptions{
@@ -37,7 +42,7 @@ func (a *App) GetUserFavoritePostsForUser(toDate int64, userId string, page, per
 	}
 	posts, err = a.SetPostMetadata(posts, option)
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, model.CursorPage{}, err
 	}
 
 	if limitContent {
@@ -60,23 +65,23 @@ func (a *App) GetUserFavoritePostsForUser(toDate int64, userId string, page, per
 		}
 	}
 
-	sort.Slice(uPosts, func(i, j int) bool {
+	sort.SliceStable(uPosts, func(i, j int) bool {
 		return uPosts[i].CreateAt > uPosts[j].CreateAt
 	})
 
-	return uPosts, totalCount, err
+	return uPosts, totalCount, cursorPage, nil
 }
 
 func (a *App) GetUserFavoritePostForUser(userId string, postId string) (*model.UserFavoritePost, *model.AppError) {
-	return a.Srv.Store.UserFavoritePost().GetByPostIdForUser(userId, postId)
+	return a.Store().UserFavoritePost().GetByPostIdForUser(userId, postId)
 }
 
 func (a *App) GetUserFavoritePostsCountByPostId(postId string) (int64, *model.AppError) {
-	return a.Srv.Store.UserFavoritePost().GetCountByPostId(postId)
+	return a.Store().UserFavoritePost().GetCountByPostId(postId)
 }
 
 func (a *App) CreateUserFavoritePost(postId string, userId string) *model.AppError {
-	post, err := a.Srv.Store.Post().GetSingle(postId, false)
+	post, err := a.Store().Post().GetSingle(postId, false)
 	if err != nil {
 		mlog.Error("Couldn't create the favorite post", mlog.Err(err))
 		return err
@@ -90,11 +95,11 @@ func (a *App) CreateUserFavoritePost(postId string, userId string) *model.AppErr
 		return model.NewAppError("CreateUserFavoritePost", "api.user_favorite_post.create.get.app_error", nil, "", http.StatusInternalServerError)
 	}
 
-	return a.Srv.Store.UserFavoritePost().Save(postId, userId, post.TeamId)
+	return a.Store().UserFavoritePost().Save(postId, userId, post.TeamId)
 }
 
 func (a *App) DeleteUserFavoritePost(postId string, userId string) *model.AppError {
-	post, err := a.Srv.Store.Post().GetSingle(postId, false)
+	post, err := a.Store().Post().GetSingle(postId, false)
 	if err != nil {
 		mlog.Error("Couldn't delete the favorite post", mlog.Err(err))
 		return err
@@ -108,5 +113,5 @@ func (a *App) DeleteUserFavoritePost(postId string, userId string) *model.AppErr
 		return model.NewAppError("DeleteUserFavoritePost", "api.user_favorite_post.delete.get.app_error", nil, "", http.StatusInternalServerError)
 	}
 
-	return a.Srv.Store.UserFavoritePost().Delete(postId, userId)
+	return a.Store().UserFavoritePost().Delete(postId, userId)
 }
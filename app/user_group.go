@@ -34,7 +34,7 @@ func (a *App) CreateGroupWithUser(group *model.UserGroup, userId string) (*model
 }
 
 func (a *App) GetNumberOfGroupsOnTeam(teamId string) (int, *model.AppError) {
-	list, err := a.Srv.Store.UserGroup().GetTeamGroups(teamId)
+	list, err := a.Store().UserGroup().GetTeamGroups(teamId)
 	if err != nil {
 		if err.Id == store.MISSING_GROUPS_ERROR {
 			return 0, nil
@@ -49,13 +49,13 @@ func (a *App) GetNumberOfGroupsOnTeam(teamId string) (int, *model.AppError) {
 func (a *App) CreateGroup(group *model.UserGroup, addMember bool) (*model.UserGroup, *model.AppError) {
 	group.Name = strings.TrimSpace(group.Name)
 
-	sg, err := a.Srv.Store.UserGroup().Save(group, *a.Config().TeamSettings.MaxGroupsPerTeam)
+	sg, err := a.Store().UserGroup().Save(group, *a.Config().TeamSettings.MaxGroupsPerTeam)
 	if err != nil {
 		return nil, err
 	}
 
 	if addMember {
-		user, err := a.Srv.Store.User().Get(group.UserId)
+		user, err := a.Store().User().Get(group.UserId)
 		if err != nil {
 			return nil, err
 		}
@@ -66,11 +66,11 @@ func (a *App) CreateGroup(group *model.UserGroup, addMember bool) (*model.UserGr
 			UserId:  user.Id,
 			Type:    model.GROUP_MEMBER_TYPE_ADMIN,
 		}
-		if _, err := a.Srv.Store.UserGroup().SaveMember(gm); err != nil {
+		if _, err := a.Store().UserGroup().SaveMember(gm); err != nil {
 			return nil, err
 		}
 
-		if err := a.Srv.Store.GroupMemberHistory().LogJoinEvent(group.UserId, sg.Id, model.GetMillis()); err != nil {
+		if err := a.Store().GroupMemberHistory().LogJoinEvent(group.UserId, sg.Id, model.GetMillis()); err != nil {
 			mlog.Error("Failed to update GroupMemberHistory table", mlog.Err(err))
 			return nil, model.NewAppError("CreateGroup", "app.group_member_history.log_join_event.internal_error", nil, err.Error(), http.StatusInternalServerError)
 		}
@@ -80,24 +80,24 @@ func (a *App) CreateGroup(group *model.UserGroup, addMember bool) (*model.UserGr
 }
 
 func (a *App) GetGroupsForTeam(teamId string, groupType string, offset int, limit int) (*model.UserGroupList, *model.AppError) {
-	return a.Srv.Store.UserGroup().GetGroupsForTeam(teamId, groupType, offset, limit)
+	return a.Store().UserGroup().GetGroupsForTeam(teamId, groupType, offset, limit)
 }
 
 func (a *App) AutocompleteGroups(teamId string, term string, groupType string) (*model.UserGroupList, *model.AppError) {
 	term = strings.TrimSpace(term)
-	return a.Srv.Store.UserGroup().AutocompleteInTeam(teamId, term, groupType, false)
+	return a.Store().UserGroup().AutocompleteInTeam(teamId, term, groupType, false)
 }
 
 func (a *App) GetGroupsForUser(teamId string, userId string, includeDeleted bool) (*model.UserGroupList, *model.AppError) {
-	return a.Srv.Store.UserGroup().GetGroups(teamId, userId, includeDeleted)
+	return a.Store().UserGroup().GetGroups(teamId, userId, includeDeleted)
 }
 
 func (a *App) GetGroup(groupId string) (*model.UserGroup, *model.AppError) {
-	return a.Srv.Store.UserGroup().Get(groupId)
+	return a.Store().UserGroup().Get(groupId)
 }
 
 func (a *App) UpdateGroup(group *model.UserGroup) (*model.UserGroup, *model.AppError) {
-	rgroup, err := a.Srv.Store.UserGroup().Update(group)
+	rgroup, err := a.Store().UserGroup().Update(group)
 	if err != nil {
 		return nil, err
 	}
@@ -112,23 +112,33 @@ func (a *App) DeleteGroup(group *model.UserGroup) *model.AppError {
 	}
 
 	deleteAt := model.GetMillis()
-	if err := a.Srv.Store.UserGroup().Delete(group.Id, deleteAt); err != nil {
+	if err := a.Store().UserGroup().Delete(group.Id, deleteAt); err != nil {
 		return model.NewAppError("DeleteGroup", "app.group.delete.app_error", nil, err.Error(), http.StatusInternalServerError)
 	}
 
 	return nil
 }
 
-func (a *App) GetGroupMembersPage(groupId string, memberType string, page, perPage int) (*model.GroupMembers, *model.AppError) {
-	return a.Srv.Store.UserGroup().GetMembers(groupId, memberType, page*perPage, perPage)
+func (a *App) GetGroupMembersPage(groupId string, memberType string, page, perPage int, cursor *model.Cursor) (*model.GroupMembers, model.CursorPage, *model.AppError) {
+	members, err := a.Store().UserGroup().GetMembers(groupId, memberType, page*perPage, perPage, cursor)
+	if err != nil {
+		return nil, model.CursorPage{}, err
+	}
+
+	list := *members
+	cursorPage := model.NewCursorPage(len(list), perPage, cursor, page > 0, func(i int) *model.Cursor {
+		return model.NewCursor(model.CURSOR_TYPE_GROUP_MEMBERS, "", 0, list[i].UserId)
+	})
+
+	return members, cursorPage, nil
 }
 
 func (a *App) GetGroupMember(groupId string, userId string) (*model.GroupMember, *model.AppError) {
-	return a.Srv.Store.UserGroup().GetMember(groupId, userId)
+	return a.Store().UserGroup().GetMember(groupId, userId)
 }
 
 func (a *App) AddGroupMember(userId string, group *model.UserGroup) (*model.GroupMember, *model.AppError) {
-	if member, err := a.Srv.Store.UserGroup().GetMember(group.Id, userId); err != nil {
+	if member, err := a.Store().UserGroup().GetMember(group.Id, userId); err != nil {
 		if err.Id != store.MISSING_GROUP_MEMBER_ERROR {
 			return nil, err
 		}
@@ -151,7 +161,7 @@ func (a *App) AddGroupMember(userId string, group *model.UserGroup) (*model.Grou
 }
 
 func (a *App) addUserToGroup(user *model.User, group *model.UserGroup, teamMember *model.TeamMember) (*model.GroupMember, *model.AppError) {
-	groupMember, err := a.Srv.Store.UserGroup().GetMember(group.Id, user.Id)
+	groupMember, err := a.Store().UserGroup().GetMember(group.Id, user.Id)
 	if err != nil {
 		if err.Id != store.MISSING_GROUP_MEMBER_ERROR {
 			return nil, err
@@ -168,13 +178,13 @@ func (a *App) addUserToGroup(user *model.User, group *model.UserGroup, teamMembe
 		Type:    model.GROUP_MEMBER_TYPE_NORMAL,
 	}
 
-	newMember, err = a.Srv.Store.UserGroup().SaveMember(newMember)
+	newMember, err = a.Store().UserGroup().SaveMember(newMember)
 	if err != nil {
 		mlog.Error("Failed to add member", mlog.String("user_id", user.Id), mlog.String("group_id", group.Id), mlog.Err(err))
 		return nil, model.NewAppError("AddUserToGroup", "api.group.add_user.to.group.failed.app_error", nil, "", http.StatusInternalServerError)
 	}
 
-	if nErr := a.Srv.Store.GroupMemberHistory().LogJoinEvent(user.Id, group.Id, model.GetMillis()); nErr != nil {
+	if nErr := a.Store().GroupMemberHistory().LogJoinEvent(user.Id, group.Id, model.GetMillis()); nErr != nil {
 		mlog.Error("Failed to update GroupMemberHistory table", mlog.Err(nErr))
 		return nil, model.NewAppError("AddUserToGroup", "app.group_member_history.log_join_event.internal_error", nil, nErr.Error(), http.StatusInternalServerError)
 	}
@@ -183,7 +193,7 @@ func (a *App) addUserToGroup(user *model.User, group *model.UserGroup, teamMembe
 }
 
 func (a *App) AddUserToGroup(user *model.User, group *model.UserGroup) (*model.GroupMember, *model.AppError) {
-	teamMember, err := a.Srv.Store.Team().GetMember(group.TeamId, user.Id)
+	teamMember, err := a.Store().Team().GetMember(group.TeamId, user.Id)
 	// ユーザーをグループに追加する場合、関連するteamにそのユーザーが所蔵していることが前提。
 	if err != nil {
 		return nil, err
@@ -212,13 +222,13 @@ func (a *App) UpdateGroupMemberType(groupId string, userId string, newType strin
 	}
 
 	// group adminの数が1人以下ならadmin → normalに変更不能にする
-	members, err := a.GetGroupMembersPage(groupId, model.GROUP_MEMBER_TYPE_ADMIN, 0, model.GROUP_MEMBER_SEARCH_DEFAULT_LIMIT)
+	members, _, err := a.GetGroupMembersPage(groupId, model.GROUP_MEMBER_TYPE_ADMIN, 0, model.GROUP_MEMBER_SEARCH_DEFAULT_LIMIT, nil)
 	if member.Type == model.GROUP_MEMBER_TYPE_ADMIN && newType != model.GROUP_MEMBER_TYPE_ADMIN && len(*members) <= 1 {
 		return nil, model.NewAppError("UpdateGroupMemberType", "api.group.update_group_member_type.missing_admin.app_error", nil, "", http.StatusBadRequest)
 	}
 
 	member.Type = newType
-	member, err = a.Srv.Store.UserGroup().UpdateMember(member)
+	member, err = a.Store().UserGroup().UpdateMember(member)
 	if err != nil {
 		return nil, err
 	}
@@ -239,11 +249,11 @@ func (a *App) removeUserFromGroup(userIdToRemove string, removerUserId string, g
 		return model.NewAppError("removeUserFromGroup", "api.group.remove_user_from_group.admin.app_error", nil, "", http.StatusBadRequest)
 	}
 
-	if err := a.Srv.Store.UserGroup().RemoveMember(group.Id, userIdToRemove); err != nil {
+	if err := a.Store().UserGroup().RemoveMember(group.Id, userIdToRemove); err != nil {
 		return err
 	}
 
-	if err := a.Srv.Store.GroupMemberHistory().LogLeaveEvent(userIdToRemove, group.Id, model.GetMillis()); err != nil {
+	if err := a.Store().GroupMemberHistory().LogLeaveEvent(userIdToRemove, group.Id, model.GetMillis()); err != nil {
 		return model.NewAppError("removeUserFromGroup", "app.group_member_history.log_leave_event.internal_error", nil, err.Error(), http.StatusInternalServerError)
 	}
 
@@ -8,12 +8,17 @@ import (
 	"github.com/clear-ness/qa-discussion/model"
 )
 
-func (a *App) GetVotesForUser(toDate int64, userId string, page, perPage int, excludeFlag bool, limitContent bool, teamId string) ([]*model.VoteWithPost, int64, *model.AppError) {
-	votes, totalCount, err := a.Srv.Store.Vote().GetVotesBeforeTime(toDate, userId, page, perPage, excludeFlag, true, teamId)
+func (a *App) GetVotesForUser(toDate int64, userId string, page, perPage int, excludeFlag bool, limitContent bool, teamId string, cursor *model.Cursor) ([]*model.VoteWithPost, int64, model.CursorPage, *model.AppError) {
+	votes, totalCount, err := a.Store().Vote().GetVotesBeforeTime(toDate, userId, page, perPage, excludeFlag, true, teamId, cursor)
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, model.CursorPage{}, err
 	}
 
+	// 投稿が削除された投票は返さないので、取得した投票からカーソルを作る
+	cursorPage := model.NewCursorPage(len(votes), perPage, cursor, page > 0, func(i int) *model.Cursor {
+		return model.NewCursor(model.CURSOR_TYPE_VOTES, "", votes[i].CreateAt, votes[i].PostId, votes[i].Type)
+	})
+
 	postIdsMaps := map[string]bool{}
 	for _, vote := range votes {
 		postIdsMaps[vote.PostId] = true
@@ -24,9 +29,9 @@ func (a *App) GetVotesForUser(toDate int64, userId string, page, perPage int, ex
 		postIds = append(postIds, key)
 	}
 
-	posts, err := a.Srv.Store.Post().GetPostsByIds(postIds)
+	posts, err := a.Store().Post().GetPostsByIds(postIds)
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, model.CursorPage{}, err
 	}
 
 	option := model.SetPostMetadataOptions{
@@ -37,7 +42,7 @@ func (a *App) GetVotesForUser(toDate int64, userId string, page, perPage int, ex
 	}
 	posts, err = a.SetPostMetadata(posts, option)
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, model.CursorPage{}, err
 	}
 
 	if limitContent {
@@ -60,34 +65,34 @@ func (a *App) GetVotesForUser(toDate int64, userId string, page, perPage int, ex
 		}
 	}
 
-	sort.Slice(votesWithPost, func(i, j int) bool {
+	sort.SliceStable(votesWithPost, func(i, j int) bool {
 		return votesWithPost[i].CreateAt > votesWithPost[j].CreateAt
 	})
 
-	return votesWithPost, totalCount, nil
+	return votesWithPost, totalCount, cursorPage, nil
 }
 
 func (a *App) GetVote(userId string, postId string, voteType string) (*model.Vote, *model.AppError) {
 	// TODO: teamIdの指定は不要？
 	// インデックスの影響で必要そう..
-	return a.Srv.Store.Vote().GetByPostIdForUser(userId, postId, voteType)
+	return a.Store().Vote().GetByPostIdForUser(userId, postId, voteType)
 }
 
 func (a *App) GetVoteTypesForPost(userId string, postId string) ([]string, *model.AppError) {
 	// TODO: teamIdの指定は不要？
 	// インデックスの影響で必要そう..
-	return a.Srv.Store.Vote().GetVoteTypesForPost(userId, postId)
+	return a.Store().Vote().GetVoteTypesForPost(userId, postId)
 }
 
 func (a *App) CreateReviewVote(postId string, userId string, tagContents string) (*model.Vote, *model.AppError) {
-	post, err := a.Srv.Store.Post().GetSingle(postId, false)
+	post, err := a.Store().Post().GetSingle(postId, false)
 	if err != nil {
 		return nil, err
 	}
 
 	currentRevision, err := a.GetCurrentRevisionForPost(post.Id, "")
 
-	count, err := a.Srv.Store.Vote().GetRejectedReviewsCount(postId, currentRevision)
+	count, err := a.Store().Vote().GetRejectedReviewsCount(postId, currentRevision)
 	if err != nil {
 		return nil, err
 	}
@@ -106,7 +111,7 @@ func (a *App) CreateReviewVote(postId string, userId string, tagContents string)
 		}
 	}
 
-	vote, err := a.Srv.Store.Vote().CreateReviewVote(post, userId, tagContents, currentRevision)
+	vote, err := a.Store().Vote().CreateReviewVote(post, userId, tagContents, currentRevision)
 	if err != nil {
 		return nil, err
 	}
@@ -115,12 +120,24 @@ func (a *App) CreateReviewVote(postId string, userId string, tagContents string)
 }
 
 func (a *App) RejectReviewsForPost(postId string, rejectedBy string) *model.AppError {
-	rev, err := a.Srv.Store.Post().GetCurrentRevisionForPost(postId, "")
+	rev, err := a.Store().Post().GetCurrentRevisionForPost(postId, "")
 	if err != nil {
 		return err
 	}
 
-	return a.Srv.Store.Vote().RejectReviewsForPost(postId, rejectedBy, rev)
+	if err := a.Store().Vote().RejectReviewsForPost(postId, rejectedBy, rev); err != nil {
+		return err
+	}
+
+	if post, err := a.Store().Post().GetSingle(postId, false); err == nil {
+		a.triggerWebhookEvent(post.TeamId, model.WEBHOOK_EVENT_REVIEW_REJECTED, rejectedBy, &model.WebhookReviewData{
+			PostId:   post.Id,
+			PostType: post.Type,
+			Revision: rev,
+		})
+	}
+
+	return nil
 }
 
 func (a *App) CompleteReviewsForPost(post *model.Post, completedBy string) *model.AppError {
@@ -128,14 +145,24 @@ func (a *App) CompleteReviewsForPost(post *model.Post, completedBy string) *mode
 		return err
 	}
 
-	rev, err := a.Srv.Store.Post().GetCurrentRevisionForPost(post.Id, "")
+	rev, err := a.Store().Post().GetCurrentRevisionForPost(post.Id, "")
 	if err != nil {
 		return err
 	}
 
-	return a.Srv.Store.Vote().CompleteReviewsForPost(post.Id, completedBy, rev)
+	if err := a.Store().Vote().CompleteReviewsForPost(post.Id, completedBy, rev); err != nil {
+		return err
+	}
+
+	a.triggerWebhookEvent(post.TeamId, model.WEBHOOK_EVENT_REVIEW_COMPLETED, completedBy, &model.WebhookReviewData{
+		PostId:   post.Id,
+		PostType: post.Type,
+		Revision: rev,
+	})
+
+	return nil
 }
 
 func (a *App) GetReviews(options *model.SearchReviewsOptions, getCount bool) ([]*model.Vote, int64, *model.AppError) {
-	return a.Srv.Store.Vote().GetReviews(options, getCount)
+	return a.Store().Vote().GetReviews(options, getCount)
 }
@@ -35,8 +35,9 @@ type WebConn struct {
 
 func (a *App) NewWebConn(ws *websocket.Conn, session model.Session) *WebConn {
 	if session.UserId != "" {
+		background := a.Detached()
 		a.Srv.Go(func() {
-			a.SetStatusOnline(session.UserId, false)
+			background.SetStatusOnline(session.UserId, false)
 		})
 	}
 
@@ -28,6 +28,7 @@ type Hub struct {
 	stop            chan struct{}
 	didStop         chan struct{}
 	invalidateUser  chan string
+	invalidateAll   chan struct{}
 	directMsg       chan *webConnDirectMessage
 	explicitStop    bool
 }
@@ -42,6 +43,7 @@ func (a *App) NewWebHub() *Hub {
 		stop:           make(chan struct{}),
 		didStop:        make(chan struct{}),
 		invalidateUser: make(chan string),
+		invalidateAll:  make(chan struct{}),
 		directMsg:      make(chan *webConnDirectMessage),
 	}
 }
@@ -74,6 +76,20 @@ func (a *App) HubStart() {
 	// Assigning to the hubs slice without any mutex is fine because it is only assigned once
 	// during the start of the program and always read from after that.
 	a.Srv.hubs = hubs
+
+	if a.Metrics() != nil {
+		a.Metrics().RegisterWebSocketConnectionsFunc(a.Srv.webSocketConnectionCounts)
+	}
+}
+
+// hubごとのwebSocket接続数を返す
+func (s *Server) webSocketConnectionCounts() []int64 {
+	counts := make([]int64, len(s.hubs))
+	for i, hub := range s.hubs {
+		counts[i] = atomic.LoadInt64(&hub.connectionCount)
+	}
+
+	return counts
 }
 
 func (s *Server) HubStop() {
@@ -102,6 +118,20 @@ func (h *Hub) InvalidateUser(userId string) {
 	}
 }
 
+// (現サーバーにwebSocket接続中の)全てのwebConnが持つユーザーセッション情報を初期化する
+func (s *Server) InvalidateAllWebConnSessionCaches() {
+	for _, hub := range s.hubs {
+		hub.InvalidateAll()
+	}
+}
+
+func (h *Hub) InvalidateAll() {
+	select {
+	case h.invalidateAll <- struct{}{}:
+	case <-h.stop:
+	}
+}
+
 // sends the given message to the given connection.
 func (h *Hub) SendMessage(conn *WebConn, msg model.WebSocketMessage) {
 	select {
@@ -198,6 +228,10 @@ func (h *Hub) Start() {
 				for _, webConn := range connIndex.ForUser(userId) {
 					webConn.InvalidateCache()
 				}
+			case <-h.invalidateAll:
+				for webConn := range connIndex.All() {
+					webConn.InvalidateCache()
+				}
 			case directMsg := <-h.directMsg:
 				if !connIndex.Has(directMsg.conn) {
 					continue
@@ -1,144 +1,173 @@
 package app
 
 import (
-	"io"
-	"io/ioutil"
 	"net/http"
-	"strings"
 
 	"github.com/clear-ness/qa-discussion/mlog"
 	"github.com/clear-ness/qa-discussion/model"
 	"github.com/clear-ness/qa-discussion/utils"
 )
 
-const (
-	MaxResponseSize = 1024 * 1024
-)
+// イベントを購読しているteamのwebhookそれぞれに配信を積む。
+// 呼び出し元の処理を待たせないよう非同期に行う。
+func (a *App) triggerWebhookEvent(teamId string, event string, userId string, data interface{}) {
+	if teamId == "" {
+		return
+	}
 
-func (a *App) handleWebhookEvents(post *model.Post, team *model.Team, user *model.User) *model.AppError {
-	hooks, err := a.Srv.Store.Webhook().GetByTeam(team.Id, "", -1, -1)
-	if err != nil {
-		return err
+	eventType := model.GetWebhookEventType(event)
+	if eventType == nil {
+		mlog.Error("Unknown webhook event", mlog.String("event", event))
+		return
 	}
 
-	if len(hooks) == 0 {
-		return nil
+	timestamp := model.GetMillis()
+
+	background := a.Detached()
+	a.Srv.Go(func() {
+		if err := background.handleWebhookEvent(teamId, eventType, userId, timestamp, data); err != nil {
+			mlog.Error("Failed to handle webhook event", mlog.String("event", event), mlog.Err(err))
+		}
+	})
+}
+
+func (a *App) handleWebhookEvent(teamId string, eventType *model.WebhookEventType, userId string, timestamp int64, data interface{}) *model.AppError {
+	hooks, err := a.Store().Webhook().GetByTeam(teamId, "", -1, -1)
+	if err != nil {
+		return err
 	}
 
 	relevantHooks := []*model.Webhook{}
 	for _, hook := range hooks {
-		trigger := false
-		switch post.Type {
-		case model.POST_TYPE_QUESTION:
-			trigger = hook.QuestionEvents
-		case model.POST_TYPE_ANSWER:
-			trigger = hook.AnswerEvents
-		case model.POST_TYPE_COMMENT:
-			trigger = hook.CommentEvents
-		default:
-			trigger = false
+		if hook.IsSubscribed(eventType.Event) && !hook.IsDisabled() {
+			relevantHooks = append(relevantHooks, hook)
 		}
+	}
 
-		if trigger {
-			relevantHooks = append(relevantHooks, hook)
+	if len(relevantHooks) == 0 {
+		return nil
+	}
+
+	team, err := a.Store().Team().Get(teamId)
+	if err != nil {
+		return err
+	}
+	if team.DeleteAt > 0 {
+		return nil
+	}
+
+	payload := &model.WebhookPayload{
+		EventId:   model.NewId(),
+		Event:     eventType.Event,
+		Version:   eventType.Version,
+		TeamId:    team.Id,
+		TeamName:  team.Name,
+		Timestamp: timestamp,
+		UserId:    userId,
+		Data:      data,
+	}
+
+	if userId != "" {
+		if user, err := a.Store().User().Get(userId); err == nil {
+			payload.UserName = user.Username
 		}
 	}
 
 	for _, hook := range relevantHooks {
-		payload := &model.WebhookPayload{
-			Token:     hook.Token,
-			TeamId:    hook.TeamId,
-			TeamName:  team.Name,
-			Timestamp: post.CreateAt,
-			UserId:    post.UserId,
-			UserName:  user.Username,
-			PostId:    post.Id,
-			Content:   post.Content,
-			Title:     post.Title,
-			PostType:  post.Type,
+		if _, err := a.EnqueueWebhook(payload, hook); err != nil {
+			mlog.Error("Failed to enqueue webhook", mlog.String("webhook_id", hook.Id), mlog.Err(err))
 		}
+	}
 
-		a.Srv.Go(func(hook *model.Webhook) func() {
-			return func() {
-				a.TriggerWebhook(payload, hook, post)
-			}
-		}(hook))
+	if a.Srv.WebhookDelivery != nil {
+		a.Srv.WebhookDelivery.Wake()
 	}
 
 	return nil
 }
 
-func (a *App) TriggerWebhook(payload *model.WebhookPayload, hook *model.Webhook, post *model.Post) {
-	var body io.Reader
-	var contentType string
-	if hook.ContentType == "application/json" {
-		body = strings.NewReader(payload.ToJSON())
-		contentType = "application/json"
-	} else {
-		body = strings.NewReader(payload.ToFormValues())
-		contentType = "application/x-www-form-urlencoded"
+func (a *App) triggerPostWebhookEvent(event string, post *model.Post, userId string) {
+	a.triggerWebhookEvent(post.TeamId, event, userId, &model.WebhookPostData{
+		PostId:   post.Id,
+		PostType: post.Type,
+		ParentId: post.ParentId,
+		RootId:   post.RootId,
+		Title:    post.Title,
+		Content:  post.Content,
+		AuthorId: post.UserId,
+	})
+}
+
+// 送信はWebhookDeliveryWorkerが非同期に行うので、ここではURLごとにキューへ積むだけ
+func (a *App) EnqueueWebhook(payload *model.WebhookPayload, hook *model.Webhook) ([]*model.WebhookDelivery, *model.AppError) {
+	body, contentType, err := a.renderWebhookBody(payload, hook)
+	if err != nil {
+		return nil, err
 	}
 
-	for i := range hook.URLs {
-		url := hook.URLs[i]
+	deliveries := []*model.WebhookDelivery{}
+	for _, url := range hook.URLs {
+		delivery := &model.WebhookDelivery{
+			WebhookId:   hook.Id,
+			TeamId:      hook.TeamId,
+			PostId:      payload.GetPostId(),
+			URL:         url,
+			ContentType: contentType,
+			RequestBody: body,
+		}
+
+		delivery, err := a.Store().WebhookDelivery().Save(delivery)
+		if err != nil {
+			return nil, err
+		}
 
-		a.Srv.Go(func() {
-			_, err := a.doWebhookRequest(url, body, contentType, hook, payload)
-			if err != nil {
-				return
-			}
-		})
+		deliveries = append(deliveries, delivery)
 	}
+
+	return deliveries, nil
 }
 
-func (a *App) doWebhookRequest(url string, body io.Reader, contentType string, hook *model.Webhook, payload *model.WebhookPayload) (*model.WebhookResponse, error) {
-	req, err := http.NewRequest("POST", url, body)
-	if err != nil {
-		return nil, err
+// 購読しているかどうかに関わらず ping イベントを送り、受信側の設定や署名の検証を確かめられるようにする
+func (a *App) SendTestWebhookEvent(hook *model.Webhook, userId string) ([]*model.WebhookDelivery, *model.AppError) {
+	if hook.IsDisabled() {
+		return nil, model.NewAppError("SendTestWebhookEvent", "app.webhook.send_test_event.disabled.app_error", nil, "id="+hook.Id, http.StatusBadRequest)
 	}
 
-	req.Header.Set("Content-Type", contentType)
-	req.Header.Set("Accept", "application/json")
-
-	resp, err := a.HttpService.MakeClient(false).Do(req)
+	team, err := a.Store().Team().Get(hook.TeamId)
 	if err != nil {
 		return nil, err
 	}
 
-	defer resp.Body.Close()
+	eventType := model.GetWebhookEventType(model.WEBHOOK_EVENT_PING)
+	payload := &model.WebhookPayload{
+		EventId:   model.NewId(),
+		Event:     eventType.Event,
+		Version:   eventType.Version,
+		TeamId:    team.Id,
+		TeamName:  team.Name,
+		Timestamp: model.GetMillis(),
+		UserId:    userId,
+		Data:      &model.WebhookPingData{WebhookId: hook.Id},
+	}
 
-	bodyBytes, err := ioutil.ReadAll(req.Body)
-	if err != nil {
+	if user, err := a.Store().User().Get(userId); err == nil {
+		payload.UserName = user.Username
 	}
-	reqBody := string(bodyBytes)
 
-	bodyBytes, err = ioutil.ReadAll(resp.Body)
+	deliveries, err := a.EnqueueWebhook(payload, hook)
 	if err != nil {
+		return nil, err
 	}
-	resBody := string(bodyBytes)
 
-	history := &model.WebhooksHistory{
-		Id:             model.NewId(),
-		WebhookId:      hook.Id,
-		PostId:         payload.PostId,
-		TeamId:         payload.TeamId,
-		WebhookName:    hook.Name,
-		URL:            url,
-		ContentType:    contentType,
-		RequestBody:    reqBody,
-		ResponseBody:   resBody,
-		ResponseStatus: resp.StatusCode,
-		CreateAt:       model.GetMillis(),
-	}
-	if err := a.Srv.Store.WebhooksHistory().LogWebhookEvent(history); err != nil {
-		mlog.Error("Failed to log webhook history", mlog.Err(err))
+	if a.Srv.WebhookDelivery != nil {
+		a.Srv.WebhookDelivery.Wake()
 	}
 
-	return model.WebhookResponseFromJson(io.LimitReader(resp.Body, MaxResponseSize))
+	return deliveries, nil
 }
 
 func (a *App) CreateWebhook(hook *model.Webhook) (*model.Webhook, *model.AppError) {
-	if allHooks, err := a.Srv.Store.Webhook().GetByTeam(hook.TeamId, "", -1, -1); err != nil {
+	if allHooks, err := a.Store().Webhook().GetByTeam(hook.TeamId, "", -1, -1); err != nil {
 		return nil, err
 	} else {
 		for _, existingHook := range allHooks {
@@ -149,7 +178,7 @@ func (a *App) CreateWebhook(hook *model.Webhook) (*model.Webhook, *model.AppErro
 		}
 	}
 
-	webhook, err := a.Srv.Store.Webhook().Save(hook)
+	webhook, err := a.Store().Webhook().Save(hook)
 	if err != nil {
 		return nil, err
 	}
@@ -158,15 +187,15 @@ func (a *App) CreateWebhook(hook *model.Webhook) (*model.Webhook, *model.AppErro
 }
 
 func (a *App) GetWebhooksForTeamPage(teamId string, userId string, page, perPage int) ([]*model.Webhook, *model.AppError) {
-	return a.Srv.Store.Webhook().GetByTeam(teamId, userId, page*perPage, perPage)
+	return a.Store().Webhook().GetByTeam(teamId, userId, page*perPage, perPage)
 }
 
 func (a *App) GetWebhook(hookId string) (*model.Webhook, *model.AppError) {
-	return a.Srv.Store.Webhook().Get(hookId)
+	return a.Store().Webhook().Get(hookId)
 }
 
 func (a *App) UpdateWebhook(oldHook, updatedHook *model.Webhook) (*model.Webhook, *model.AppError) {
-	allHooks, err := a.Srv.Store.Webhook().GetByTeam(oldHook.TeamId, "", -1, -1)
+	allHooks, err := a.Store().Webhook().GetByTeam(oldHook.TeamId, "", -1, -1)
 	if err != nil {
 		return nil, err
 	}
@@ -185,15 +214,25 @@ func (a *App) UpdateWebhook(oldHook, updatedHook *model.Webhook) (*model.Webhook
 	updatedHook.CreateAt = oldHook.CreateAt
 	updatedHook.UpdateAt = model.GetMillis()
 	updatedHook.DeleteAt = oldHook.DeleteAt
+	if updatedHook.PayloadFormat == "" {
+		updatedHook.PayloadFormat = oldHook.PayloadFormat
+	}
+	// 自動で無効化されたwebhookは、設定を見直して更新すれば再び有効になる
+	updatedHook.FailureCount = 0
+	updatedHook.DisabledAt = 0
+
+	if err := updatedHook.IsValid(); err != nil {
+		return nil, err
+	}
 
-	return a.Srv.Store.Webhook().Update(updatedHook)
+	return a.Store().Webhook().Update(updatedHook)
 }
 
 func (a *App) DeleteWebhook(hookId string) *model.AppError {
-	return a.Srv.Store.Webhook().Delete(hookId, model.GetMillis())
+	return a.Store().Webhook().Delete(hookId, model.GetMillis())
 }
 
 func (a *App) RegenWebhookToken(hook *model.Webhook) (*model.Webhook, *model.AppError) {
 	hook.Token = model.NewId()
-	return a.Srv.Store.Webhook().Update(hook)
+	return a.Store().Webhook().Update(hook)
 }
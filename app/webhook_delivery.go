@@ -0,0 +1,308 @@
+package app
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/clear-ness/qa-discussion/mlog"
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/clear-ness/qa-discussion/services/cache"
+	"github.com/clear-ness/qa-discussion/services/tracing"
+)
+
+const (
+	MaxResponseSize = 1024 * 1024
+
+	WEBHOOK_DELIVERY_BATCH_SIZE = 50
+	// 1つの遅いURLが他のdeliveryを詰まらせないよう、並列に送る
+	WEBHOOK_DELIVERY_CONCURRENCY = 8
+	WEBHOOK_HISTORY_ERROR_MAX    = 1024
+
+	WEBHOOK_HISTORY_RETENTION_INTERVAL = time.Hour
+	WEBHOOK_HISTORY_DELETE_BATCH_SIZE  = 1000
+	WEBHOOK_HISTORY_RETENTION_LOCK_KEY = "webhooks_history_retention_lock"
+)
+
+// WebhookDeliveries テーブルをポーリングして送信時刻を迎えたwebhookを送る。
+// 新しいdeliveryが積まれた時はWakeで待ち時間を飛ばせる。
+type WebhookDeliveryWorker struct {
+	server  *Server
+	wake    chan struct{}
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+func NewWebhookDeliveryWorker(s *Server) *WebhookDeliveryWorker {
+	return &WebhookDeliveryWorker{
+		server:  s,
+		wake:    make(chan struct{}, 1),
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+}
+
+func (w *WebhookDeliveryWorker) Start() {
+	go w.run()
+}
+
+func (w *WebhookDeliveryWorker) Stop() {
+	close(w.stop)
+	<-w.stopped
+}
+
+func (w *WebhookDeliveryWorker) Wake() {
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (w *WebhookDeliveryWorker) run() {
+	defer close(w.stopped)
+
+	interval := time.Duration(*w.server.Config().WebhookSettings.PollIntervalSeconds) * time.Second
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	retentionTicker := time.NewTicker(WEBHOOK_HISTORY_RETENTION_INTERVAL)
+	defer retentionTicker.Stop()
+
+	for {
+		w.processDue()
+
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+		case <-w.wake:
+		case <-retentionTicker.C:
+			w.purgeHistory()
+		}
+	}
+}
+
+// 全サーバーのworkerが毎時実行するので、ロックを取れたサーバーだけが削除する。
+// 他のサーバーのtickerは時刻がずれているため、ロックは削除後も間隔の間だけ残しておく。
+func (w *WebhookDeliveryWorker) purgeHistory() {
+	locked, err := cache.NewRedisBackend(&w.server.Config().CacheSettings).SetNX(WEBHOOK_HISTORY_RETENTION_LOCK_KEY, model.GetMillis(), int(WEBHOOK_HISTORY_RETENTION_INTERVAL/time.Second))
+	if err != nil {
+		mlog.Error("Failed to lock webhooks history retention", mlog.Err(err))
+		return
+	}
+	if !locked {
+		return
+	}
+
+	start := time.Now()
+	_, appErr := w.server.FakeApp().PurgeWebhooksHistory()
+	if appErr != nil {
+		mlog.Error("Failed to purge webhooks history", mlog.Err(appErr))
+		w.server.recordJobRun("webhooks_history_retention", start, appErr)
+	} else {
+		w.server.recordJobRun("webhooks_history_retention", start, nil)
+	}
+}
+
+func (w *WebhookDeliveryWorker) processDue() {
+	a := w.server.FakeApp()
+
+	for {
+		deliveries, err := w.server.Store.WebhookDelivery().GetDue(model.GetMillis(), WEBHOOK_DELIVERY_BATCH_SIZE)
+		if err != nil {
+			mlog.Error("Failed to get webhook deliveries", mlog.Err(err))
+			return
+		}
+
+		if len(deliveries) == 0 {
+			return
+		}
+
+		settings := w.server.Config().WebhookSettings
+		// 送信中に落ちた場合でも、タイムアウトより十分長く経てば再送の対象になる
+		lockUntil := model.GetMillis() + int64(*settings.RequestTimeoutSeconds)*2*1000
+
+		start := time.Now()
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, WEBHOOK_DELIVERY_CONCURRENCY)
+		claimed := 0
+
+		for _, delivery := range deliveries {
+			ok, err := w.server.Store.WebhookDelivery().Claim(delivery, lockUntil)
+			if err != nil {
+				mlog.Error("Failed to claim webhook delivery", mlog.String("delivery_id", delivery.Id), mlog.Err(err))
+				continue
+			}
+			if !ok {
+				// 他のサーバーが先に確保した
+				continue
+			}
+			claimed++
+
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(delivery *model.WebhookDelivery) {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+
+				a.deliverWebhook(delivery)
+			}(delivery)
+		}
+
+		wg.Wait()
+		w.server.recordJobRun("webhook_delivery", start, nil)
+
+		if claimed == 0 || len(deliveries) < WEBHOOK_DELIVERY_BATCH_SIZE {
+			return
+		}
+
+		select {
+		case <-w.stop:
+			return
+		default:
+		}
+	}
+}
+
+func (a *App) deliverWebhook(delivery *model.WebhookDelivery) {
+	hook, err := a.Store().Webhook().Get(delivery.WebhookId)
+	if err != nil {
+		if err.StatusCode != http.StatusNotFound {
+			// 一時的なエラーなら確保が切れた後に再び取得される
+			mlog.Error("Failed to get webhook for delivery", mlog.String("delivery_id", delivery.Id), mlog.Err(err))
+			return
+		}
+
+		a.finishWebhookDelivery(delivery, model.WEBHOOK_DELIVERY_STATUS_FAILED)
+		return
+	}
+
+	// 再送を待つ間に送信先から外されたURLには送らない
+	if hook.IsDisabled() || !hook.HasURL(delivery.URL) {
+		a.finishWebhookDelivery(delivery, model.WEBHOOK_DELIVERY_STATUS_FAILED)
+		return
+	}
+
+	settings := a.Config().WebhookSettings
+	delivery.Attempts++
+
+	history := &model.WebhooksHistory{
+		Id:          model.NewId(),
+		WebhookId:   hook.Id,
+		PostId:      delivery.PostId,
+		TeamId:      delivery.TeamId,
+		WebhookName: hook.Name,
+		URL:         delivery.URL,
+		ContentType: delivery.ContentType,
+		RequestBody: delivery.RequestBody,
+		DeliveryId:  delivery.Id,
+		Attempt:     delivery.Attempts,
+	}
+
+	sendErr := a.sendWebhookRequest(delivery, hook, time.Duration(*settings.RequestTimeoutSeconds)*time.Second, history)
+	history.CreateAt = model.GetMillis()
+	if sendErr != nil {
+		history.Error = sendErr.Error()
+		if len(history.Error) > WEBHOOK_HISTORY_ERROR_MAX {
+			history.Error = history.Error[:WEBHOOK_HISTORY_ERROR_MAX]
+		}
+	}
+	if err := a.Store().WebhooksHistory().LogWebhookEvent(history); err != nil {
+		mlog.Error("Failed to log webhook history", mlog.Err(err))
+	}
+
+	if sendErr == nil && history.ResponseStatus >= 200 && history.ResponseStatus < 300 {
+		if err := a.Store().Webhook().ResetFailureCount(hook.Id); err != nil {
+			mlog.Error("Failed to reset webhook failure count", mlog.String("webhook_id", hook.Id), mlog.Err(err))
+		}
+
+		a.finishWebhookDelivery(delivery, model.WEBHOOK_DELIVERY_STATUS_SUCCESS)
+		return
+	}
+
+	failures, err := a.Store().Webhook().IncrementFailureCount(hook.Id)
+	if err != nil {
+		mlog.Error("Failed to increment webhook failure count", mlog.String("webhook_id", hook.Id), mlog.Err(err))
+	} else if failures >= *settings.MaxConsecutiveFailures {
+		mlog.Warn("Disabling webhook after repeated failures", mlog.String("webhook_id", hook.Id), mlog.Int("failures", failures))
+		if err := a.Store().Webhook().Disable(hook.Id, model.GetMillis()); err != nil {
+			mlog.Error("Failed to disable webhook", mlog.String("webhook_id", hook.Id), mlog.Err(err))
+		}
+	}
+
+	if delivery.Attempts >= *settings.MaxAttempts {
+		a.finishWebhookDelivery(delivery, model.WEBHOOK_DELIVERY_STATUS_FAILED)
+		return
+	}
+
+	delivery.NextAttemptAt = model.GetMillis() + webhookRetryInterval(delivery.Attempts, settings).Milliseconds()
+	if _, err := a.Store().WebhookDelivery().Update(delivery); err != nil {
+		mlog.Error("Failed to schedule webhook retry", mlog.String("delivery_id", delivery.Id), mlog.Err(err))
+	}
+}
+
+func (a *App) finishWebhookDelivery(delivery *model.WebhookDelivery, status string) {
+	delivery.Status = status
+	if _, err := a.Store().WebhookDelivery().Update(delivery); err != nil {
+		mlog.Error("Failed to update webhook delivery", mlog.String("delivery_id", delivery.Id), mlog.Err(err))
+	}
+}
+
+// attempt回目の失敗後、次の送信までの待ち時間。1回目の失敗で初期値、以降倍々で上限まで伸びる。
+func webhookRetryInterval(attempt int, settings model.WebhookSettings) time.Duration {
+	interval := time.Duration(*settings.InitialRetryIntervalSeconds) * time.Second
+	max := time.Duration(*settings.MaxRetryIntervalSeconds) * time.Second
+
+	for i := 1; i < attempt && interval < max; i++ {
+		interval *= 2
+	}
+
+	if interval > max {
+		interval = max
+	}
+
+	return interval
+}
+
+// 送信した内容と受け取った結果をhistoryに記録する
+func (a *App) sendWebhookRequest(delivery *model.WebhookDelivery, hook *model.Webhook, timeout time.Duration, history *model.WebhooksHistory) error {
+	req, err := http.NewRequestWithContext(a.Context(), "POST", delivery.URL, strings.NewReader(delivery.RequestBody))
+	if err != nil {
+		return err
+	}
+
+	// 署名はtimestampとbodyの両方に対して行うので、古いリクエストの使い回しを受信側で検出できる
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", delivery.ContentType)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set(model.HEADER_WEBHOOK_DELIVERY, delivery.Id)
+	req.Header.Set(model.HEADER_WEBHOOK_TIMESTAMP, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(model.HEADER_WEBHOOK_SIGNATURE, model.WebhookSignature(hook.Token, timestamp, delivery.RequestBody))
+	history.RequestHeaders = model.HeaderToStringMap(req.Header)
+
+	client := a.HttpService.MakeClient(false)
+	client.Timeout = timeout
+	client.Transport = tracing.NewTransport("webhook", client.Transport)
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		history.Latency = time.Since(start).Milliseconds()
+		return err
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := ioutil.ReadAll(io.LimitReader(resp.Body, MaxResponseSize))
+	history.Latency = time.Since(start).Milliseconds()
+	history.ResponseStatus = resp.StatusCode
+	history.ResponseHeaders = model.HeaderToStringMap(resp.Header)
+	history.ResponseBody = string(bodyBytes)
+
+	return err
+}
@@ -0,0 +1,225 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/clear-ness/qa-discussion/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookRetryInterval(t *testing.T) {
+	settings := model.WebhookSettings{
+		InitialRetryIntervalSeconds: model.NewInt(10),
+		MaxRetryIntervalSeconds:     model.NewInt(60),
+	}
+
+	for attempt, expected := range map[int]time.Duration{
+		1:  10 * time.Second,
+		2:  20 * time.Second,
+		3:  40 * time.Second,
+		4:  60 * time.Second,
+		5:  60 * time.Second,
+		50: 60 * time.Second,
+	} {
+		assert.Equal(t, expected, webhookRetryInterval(attempt, settings), attempt)
+	}
+
+	// 初期値が上限を超えていても上限で止める
+	settings.InitialRetryIntervalSeconds = model.NewInt(120)
+	assert.Equal(t, 60*time.Second, webhookRetryInterval(1, settings))
+}
+
+type testWebhookReceiver struct {
+	*httptest.Server
+
+	mutex    sync.Mutex
+	status   int
+	requests []*http.Request
+}
+
+func newTestWebhookReceiver() *testWebhookReceiver {
+	receiver := &testWebhookReceiver{status: http.StatusOK}
+	receiver.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receiver.mutex.Lock()
+		defer receiver.mutex.Unlock()
+
+		receiver.requests = append(receiver.requests, r)
+		w.WriteHeader(receiver.status)
+	}))
+
+	return receiver
+}
+
+func (r *testWebhookReceiver) setStatus(status int) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.status = status
+}
+
+func (r *testWebhookReceiver) received() []*http.Request {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return append([]*http.Request{}, r.requests...)
+}
+
+func (th *TestHelper) enqueueWebhookDelivery(tb testing.TB, hook *model.Webhook) *model.WebhookDelivery {
+	delivery, err := th.Store.WebhookDelivery().Save(&model.WebhookDelivery{
+		WebhookId:   hook.Id,
+		TeamId:      hook.TeamId,
+		URL:         hook.URLs[0],
+		ContentType: "application/json",
+		RequestBody: `{"event":"ping"}`,
+	})
+	require.Nil(tb, err)
+
+	return delivery
+}
+
+func (th *TestHelper) isDue(tb testing.TB, delivery *model.WebhookDelivery, now int64) bool {
+	deliveries, err := th.Store.WebhookDelivery().GetDue(now, 100)
+	require.Nil(tb, err)
+
+	for _, due := range deliveries {
+		if due.Id == delivery.Id {
+			return true
+		}
+	}
+
+	return false
+}
+
+func TestDeliverWebhook(t *testing.T) {
+	th := Setup(t)
+	defer th.TearDown()
+
+	th.UpdateConfig(func(cfg *model.Config) {
+		*cfg.ServiceSettings.AllowedUntrustedInternalConnections = "127.0.0.1 127.0.0.0/8"
+		*cfg.WebhookSettings.MaxAttempts = 2
+		*cfg.WebhookSettings.MaxConsecutiveFailures = 3
+		*cfg.WebhookSettings.InitialRetryIntervalSeconds = 60
+		*cfg.WebhookSettings.MaxRetryIntervalSeconds = 600
+	})
+
+	receiver := newTestWebhookReceiver()
+	defer receiver.Close()
+
+	user := th.CreateUser(t)
+	team := th.CreateTeam(t, user)
+	hook := th.CreateWebhook(t, team, user, receiver.URL+"/hook", model.WEBHOOK_EVENT_POST_DELETED)
+
+	worker := NewWebhookDeliveryWorker(th.Server)
+
+	t.Run("2xx finishes the delivery", func(t *testing.T) {
+		delivery := th.enqueueWebhookDelivery(t, hook)
+
+		worker.processDue()
+
+		requests := receiver.received()
+		require.Len(t, requests, 1)
+		request := requests[0]
+		assert.Equal(t, "/hook", request.URL.Path)
+		assert.Equal(t, delivery.Id, request.Header.Get(model.HEADER_WEBHOOK_DELIVERY))
+
+		timestamp, err := strconv.ParseInt(request.Header.Get(model.HEADER_WEBHOOK_TIMESTAMP), 10, 64)
+		require.NoError(t, err)
+		assert.Equal(t, model.WebhookSignature(hook.Token, timestamp, delivery.RequestBody), request.Header.Get(model.HEADER_WEBHOOK_SIGNATURE))
+
+		assert.False(t, th.isDue(t, delivery, model.GetMillis()+24*60*60*1000))
+
+		histories, appErr := th.Store.WebhooksHistory().Search(&model.SearchWebhooksHistoryOptions{TeamId: team.Id, WebhookId: hook.Id, PerPage: 10})
+		require.Nil(t, appErr)
+		require.Len(t, histories, 1)
+		assert.Equal(t, delivery.Id, histories[0].DeliveryId)
+		assert.Equal(t, http.StatusOK, histories[0].ResponseStatus)
+		assert.Equal(t, 1, histories[0].Attempt)
+	})
+
+	t.Run("5xx is retried until MaxAttempts", func(t *testing.T) {
+		receiver.setStatus(http.StatusInternalServerError)
+		delivery := th.enqueueWebhookDelivery(t, hook)
+
+		before := model.GetMillis()
+		worker.processDue()
+		assert.Len(t, receiver.received(), 2)
+
+		// 1回目の失敗後は初期値の60秒後に再送する
+		assert.False(t, th.isDue(t, delivery, model.GetMillis()))
+		deliveries, appErr := th.Store.WebhookDelivery().GetDue(model.GetMillis()+61*1000, 100)
+		require.Nil(t, appErr)
+		require.Len(t, deliveries, 1)
+		retry := deliveries[0]
+		assert.Equal(t, 1, retry.Attempts)
+		assert.True(t, retry.NextAttemptAt >= before+60*1000)
+
+		th.App.deliverWebhook(retry)
+		assert.Len(t, receiver.received(), 3)
+		assert.Equal(t, 2, retry.Attempts)
+		assert.Equal(t, model.WEBHOOK_DELIVERY_STATUS_FAILED, retry.Status)
+		assert.False(t, th.isDue(t, delivery, model.GetMillis()+24*60*60*1000))
+
+		updated, appErr := th.Store.Webhook().Get(hook.Id)
+		require.Nil(t, appErr)
+		assert.Equal(t, 2, updated.FailureCount)
+		assert.False(t, updated.IsDisabled())
+	})
+
+	t.Run("consecutive failures disable the webhook", func(t *testing.T) {
+		delivery := th.enqueueWebhookDelivery(t, hook)
+		th.App.deliverWebhook(delivery)
+		assert.Len(t, receiver.received(), 4)
+
+		updated, appErr := th.Store.Webhook().Get(hook.Id)
+		require.Nil(t, appErr)
+		assert.Equal(t, 3, updated.FailureCount)
+		assert.True(t, updated.IsDisabled())
+
+		// 無効化された後は送らずに失敗として終える
+		delivery = th.enqueueWebhookDelivery(t, hook)
+		th.App.deliverWebhook(delivery)
+		assert.Len(t, receiver.received(), 4)
+		assert.Equal(t, model.WEBHOOK_DELIVERY_STATUS_FAILED, delivery.Status)
+	})
+
+	t.Run("success resets the failure count", func(t *testing.T) {
+		receiver.setStatus(http.StatusInternalServerError)
+		other := th.CreateWebhook(t, team, user, receiver.URL+"/other", model.WEBHOOK_EVENT_POST_DELETED)
+		th.App.deliverWebhook(th.enqueueWebhookDelivery(t, other))
+
+		updated, appErr := th.Store.Webhook().Get(other.Id)
+		require.Nil(t, appErr)
+		assert.Equal(t, 1, updated.FailureCount)
+
+		receiver.setStatus(http.StatusNoContent)
+		delivery := th.enqueueWebhookDelivery(t, other)
+		th.App.deliverWebhook(delivery)
+		assert.Equal(t, model.WEBHOOK_DELIVERY_STATUS_SUCCESS, delivery.Status)
+
+		updated, appErr = th.Store.Webhook().Get(other.Id)
+		require.Nil(t, appErr)
+		assert.Equal(t, 0, updated.FailureCount)
+	})
+
+	t.Run("removed URL is not retried", func(t *testing.T) {
+		receiver.setStatus(http.StatusOK)
+		other := th.CreateWebhook(t, team, user, receiver.URL+"/removed", model.WEBHOOK_EVENT_POST_DELETED)
+		delivery := th.enqueueWebhookDelivery(t, other)
+		count := len(receiver.received())
+
+		other.URLs = []string{receiver.URL + "/new"}
+		_, appErr := th.Store.Webhook().Update(other)
+		require.Nil(t, appErr)
+
+		th.App.deliverWebhook(delivery)
+		assert.Len(t, receiver.received(), count)
+		assert.Equal(t, model.WEBHOOK_DELIVERY_STATUS_FAILED, delivery.Status)
+	})
+}
@@ -0,0 +1,221 @@
+package app
+
+import (
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/clear-ness/qa-discussion/model"
+)
+
+// hookの形式に合わせてpayloadの本文とContent-Typeを作る
+func (a *App) renderWebhookBody(payload *model.WebhookPayload, hook *model.Webhook) (string, string, *model.AppError) {
+	switch hook.PayloadFormat {
+	case "", model.WEBHOOK_PAYLOAD_FORMAT_NATIVE:
+		if hook.ContentType == "application/json" {
+			return payload.ToJSON(), "application/json", nil
+		}
+		return payload.ToFormValues(), "application/x-www-form-urlencoded", nil
+	}
+
+	return renderWebhookMessage(a.buildWebhookMessage(payload), hook)
+}
+
+func renderWebhookMessage(message *model.WebhookMessage, hook *model.Webhook) (string, string, *model.AppError) {
+	switch hook.PayloadFormat {
+	case model.WEBHOOK_PAYLOAD_FORMAT_SLACK:
+		return message.ToSlackJSON(), "application/json", nil
+	case model.WEBHOOK_PAYLOAD_FORMAT_TEAMS:
+		return message.ToTeamsMessageCardJSON(), "application/json", nil
+	case model.WEBHOOK_PAYLOAD_FORMAT_TEAMS_ADAPTIVE_CARD:
+		return message.ToTeamsAdaptiveCardJSON(), "application/json", nil
+	case model.WEBHOOK_PAYLOAD_FORMAT_TEMPLATE:
+		body, err := message.RenderTemplate(hook.PayloadTemplate)
+		if err != nil {
+			return "", "", model.NewAppError("renderWebhookMessage", "app.webhook.render_template.app_error", nil, "id="+hook.Id+", "+err.Error(), http.StatusBadRequest)
+		}
+
+		contentType := hook.ContentType
+		if contentType == "" {
+			contentType = "application/json"
+		}
+		return body, contentType, nil
+	}
+
+	return "", "", model.NewAppError("renderWebhookMessage", "app.webhook.render.payload_format.app_error", nil, "id="+hook.Id+", format="+hook.PayloadFormat, http.StatusBadRequest)
+}
+
+// payloadから、投稿のタイトル・作者・タグ・リンクなど人が読むための情報を集める。
+// 取得に失敗した項目は空のまま送る。
+func (a *App) buildWebhookMessage(payload *model.WebhookPayload) *model.WebhookMessage {
+	message := &model.WebhookMessage{
+		Event:     payload.Event,
+		Summary:   payload.Event,
+		TeamName:  payload.TeamName,
+		Timestamp: payload.Timestamp,
+		Payload:   payload,
+	}
+
+	if eventType := model.GetWebhookEventType(payload.Event); eventType != nil {
+		message.Summary = eventType.Description
+	}
+
+	siteURL := strings.TrimSuffix(a.GetSiteURL(), "/")
+	message.Link = siteURL
+
+	switch data := payload.Data.(type) {
+	case *model.WebhookMemberData:
+		message.Title = data.MemberName
+	case *model.WebhookCollectionData:
+		message.Title = data.Title
+	}
+
+	if postId := payload.GetPostId(); postId != "" {
+		a.fillWebhookMessageWithPost(message, postId, siteURL)
+	} else if payload.UserName != "" {
+		message.AuthorName = payload.UserName
+	}
+
+	return message
+}
+
+func (a *App) fillWebhookMessageWithPost(message *model.WebhookMessage, postId string, siteURL string) {
+	// 削除イベントでも内容を送れるよう、削除済みの投稿も取得する
+	post, err := a.Store().Post().GetSingle(postId, true)
+	if err != nil {
+		return
+	}
+
+	root := post
+	if post.RootId != "" {
+		if r, err := a.Store().Post().GetSingle(post.RootId, true); err == nil {
+			root = r
+		}
+	}
+
+	// collectionのイベントではcollectionのタイトルを優先する
+	if message.Title == "" {
+		message.Title = root.Title
+	}
+	message.Text = truncateRunes(post.Content, model.WEBHOOK_MESSAGE_TEXT_MAX_RUNES)
+	message.Tags = strings.Fields(root.Tags)
+	message.Link = model.GetLink(siteURL, root.Id)
+
+	if user, err := a.Store().User().Get(post.UserId); err == nil {
+		message.AuthorName = user.Username
+		if user.IsBot() {
+			if bot, err := a.Store().Bot().Get(user.Id); err == nil {
+				message.AuthorName = bot.DisplayName
+			}
+		}
+	}
+}
+
+func truncateRunes(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+
+	return string([]rune(s)[:max]) + "…"
+}
+
+// 保存前のhookの設定で、サンプルのイベントがどのような本文になるかを返す
+func (a *App) PreviewWebhookPayload(hook *model.Webhook, event string) (*model.WebhookPreview, *model.AppError) {
+	if hook.PayloadFormat == "" {
+		hook.PayloadFormat = model.WEBHOOK_PAYLOAD_FORMAT_NATIVE
+	}
+	if !model.IsValidWebhookPayloadFormat(hook.PayloadFormat) {
+		return nil, model.NewAppError("PreviewWebhookPayload", "model.hook.is_valid.payload_format.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	eventType := model.GetWebhookEventType(event)
+	if eventType == nil {
+		return nil, model.NewAppError("PreviewWebhookPayload", "app.webhook.preview.event.app_error", nil, "event="+event, http.StatusBadRequest)
+	}
+
+	payload := sampleWebhookPayload(eventType)
+
+	var body, contentType string
+	var err *model.AppError
+	if hook.PayloadFormat == model.WEBHOOK_PAYLOAD_FORMAT_NATIVE {
+		body, contentType, err = a.renderWebhookBody(payload, hook)
+	} else {
+		body, contentType, err = renderWebhookMessage(sampleWebhookMessage(payload, strings.TrimSuffix(a.GetSiteURL(), "/")), hook)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.WebhookPreview{ContentType: contentType, Body: body}, nil
+}
+
+// プレビュー用の架空のイベント。DBには存在しないidを使う
+func sampleWebhookPayload(eventType *model.WebhookEventType) *model.WebhookPayload {
+	postData := &model.WebhookPostData{
+		PostId:   model.NewId(),
+		PostType: model.POST_TYPE_QUESTION,
+		Title:    "How do I configure webhooks?",
+		Content:  "I want to receive a notification whenever a question is posted in our team.",
+		AuthorId: model.NewId(),
+	}
+
+	var data interface{} = postData
+	switch eventType.Event {
+	case model.WEBHOOK_EVENT_BEST_ANSWER_SELECTED:
+		data = &model.WebhookBestAnswerData{QuestionId: postData.PostId, AnswerId: model.NewId(), AuthorId: postData.AuthorId}
+	case model.WEBHOOK_EVENT_VOTE_THRESHOLD_CROSSED:
+		data = &model.WebhookVoteThresholdData{PostId: postData.PostId, PostType: postData.PostType, Points: 10, Threshold: 10}
+	case model.WEBHOOK_EVENT_REVIEW_COMPLETED, model.WEBHOOK_EVENT_REVIEW_REJECTED:
+		data = &model.WebhookReviewData{PostId: postData.PostId, PostType: postData.PostType, Revision: model.GetMillis()}
+	case model.WEBHOOK_EVENT_MEMBER_JOINED, model.WEBHOOK_EVENT_MEMBER_LEFT:
+		data = &model.WebhookMemberData{MemberId: model.NewId(), MemberName: "sample-user"}
+	case model.WEBHOOK_EVENT_COLLECTION_UPDATED:
+		data = &model.WebhookCollectionData{CollectionId: model.NewId(), Title: "Getting started", Action: model.WEBHOOK_COLLECTION_ACTION_POST_ADDED, PostId: postData.PostId}
+	case model.WEBHOOK_EVENT_PING:
+		data = &model.WebhookPingData{WebhookId: model.NewId()}
+	}
+
+	return &model.WebhookPayload{
+		EventId:   model.NewId(),
+		Event:     eventType.Event,
+		Version:   eventType.Version,
+		TeamId:    model.NewId(),
+		TeamName:  "sample-team",
+		Timestamp: model.GetMillis(),
+		UserId:    postData.AuthorId,
+		UserName:  "sample-user",
+		Data:      data,
+	}
+}
+
+func sampleWebhookMessage(payload *model.WebhookPayload, siteURL string) *model.WebhookMessage {
+	message := &model.WebhookMessage{
+		Event:      payload.Event,
+		Summary:    model.GetWebhookEventType(payload.Event).Description,
+		AuthorName: payload.UserName,
+		TeamName:   payload.TeamName,
+		Timestamp:  payload.Timestamp,
+		Link:       siteURL,
+		Payload:    payload,
+	}
+
+	switch data := payload.Data.(type) {
+	case *model.WebhookMemberData:
+		message.Title = data.MemberName
+		return message
+	case *model.WebhookPingData:
+		return message
+	case *model.WebhookCollectionData:
+		message.Title = data.Title
+	default:
+		message.Title = "How do I configure webhooks?"
+	}
+
+	message.Text = "I want to receive a notification whenever a question is posted in our team."
+	message.Tags = []string{"webhooks", "integrations"}
+	if postId := payload.GetPostId(); postId != "" {
+		message.Link = model.GetLink(siteURL, postId)
+	}
+
+	return message
+}
@@ -0,0 +1,84 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/clear-ness/qa-discussion/store"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// リクエスト単位のstoreを真似る。contextがキャンセルされた後の書き込みは失敗する
+type contextBoundStore struct {
+	store.Store
+	ctx context.Context
+}
+
+func (s *contextBoundStore) WebhookDelivery() store.WebhookDeliveryStore {
+	return &contextBoundWebhookDeliveryStore{s.Store.WebhookDelivery(), s.ctx}
+}
+
+type contextBoundWebhookDeliveryStore struct {
+	store.WebhookDeliveryStore
+	ctx context.Context
+}
+
+func (s *contextBoundWebhookDeliveryStore) Save(delivery *model.WebhookDelivery) (*model.WebhookDelivery, *model.AppError) {
+	if err := s.ctx.Err(); err != nil {
+		return nil, model.NewAppError("contextBoundWebhookDeliveryStore.Save", "store.sql_webhook_delivery.save.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	return s.WebhookDeliveryStore.Save(delivery)
+}
+
+func TestTriggerWebhookEventAfterRequestCanceled(t *testing.T) {
+	th := Setup(t)
+	defer th.TearDown()
+
+	user := th.CreateUser(t)
+	team := th.CreateTeam(t, user)
+	hook := th.CreateWebhook(t, team, user, "https://example.com/hook", model.WEBHOOK_EVENT_POST_DELETED)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	a := th.Server.FakeApp()
+	a.SetContext(ctx)
+	a.SetStore(&contextBoundStore{th.Store, ctx})
+
+	// 非同期処理が動き出す前にクライアントが切断した状態
+	cancel()
+	a.triggerPostWebhookEvent(model.WEBHOOK_EVENT_POST_DELETED, &model.Post{Id: model.NewId(), TeamId: team.Id, Type: model.POST_TYPE_QUESTION}, user.Id)
+
+	th.Server.WaitForGoroutines()
+
+	deliveries, err := th.Store.WebhookDelivery().GetDue(model.GetMillis()+1000, 10)
+	require.Nil(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, hook.Id, deliveries[0].WebhookId)
+}
+
+func TestDetached(t *testing.T) {
+	th := Setup(t)
+	defer th.TearDown()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	a := th.Server.FakeApp()
+	a.Session = model.Session{UserId: model.NewId()}
+	a.SetContext(ctx)
+	a.SetStore(&contextBoundStore{th.Store, ctx})
+
+	detached := a.Detached()
+	assert.Equal(t, context.Background(), detached.Context())
+	assert.Equal(t, th.Server.Store, detached.Store())
+	assert.Equal(t, a.Session.UserId, detached.Session.UserId)
+
+	// 元のAppはリクエストのcontextとstoreを使い続ける
+	assert.Equal(t, ctx, a.Context())
+	assert.NotEqual(t, th.Server.Store, a.Store())
+}
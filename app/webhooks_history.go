@@ -1,9 +1,98 @@
 package app
 
 import (
+	"net/http"
+	"time"
+
+	"github.com/clear-ness/qa-discussion/mlog"
 	"github.com/clear-ness/qa-discussion/model"
 )
 
-func (a *App) GetWebhooksHistoriesPage(teamId string, page, perPage int) ([]*model.WebhooksHistory, *model.AppError) {
-	return a.Srv.Store.WebhooksHistory().GetWebhooksHistoriesPage(teamId, page*perPage, perPage)
+func (a *App) GetWebhooksHistory(historyId string) (*model.WebhooksHistory, *model.AppError) {
+	return a.Store().WebhooksHistory().Get(historyId)
+}
+
+func (a *App) SearchWebhooksHistory(options *model.SearchWebhooksHistoryOptions) ([]*model.WebhooksHistory, model.CursorPage, *model.AppError) {
+	histories, err := a.Store().WebhooksHistory().Search(options)
+	if err != nil {
+		return nil, model.CursorPage{}, err
+	}
+
+	cursorPage := model.NewCursorPage(len(histories), options.PerPage, options.Cursor, options.Page > 0, func(i int) *model.Cursor {
+		return model.NewCursor(model.CURSOR_TYPE_WEBHOOKS_HISTORY, "", histories[i].CreateAt, histories[i].Id)
+	})
+
+	return histories, cursorPage, nil
+}
+
+// 過去の送信と同じbodyを新しいdeliveryとして積み直す。
+// 署名とtimestampは送信時に付け直されるので、受信側のリプレイ対策には引っかからない。
+func (a *App) RedeliverWebhook(history *model.WebhooksHistory, hook *model.Webhook) (*model.WebhookDelivery, *model.AppError) {
+	if hook.IsDisabled() {
+		return nil, model.NewAppError("RedeliverWebhook", "app.webhook.redeliver.disabled.app_error", nil, "id="+hook.Id, http.StatusBadRequest)
+	}
+
+	if !hook.HasURL(history.URL) {
+		return nil, model.NewAppError("RedeliverWebhook", "app.webhook.redeliver.url_removed.app_error", nil, "id="+hook.Id, http.StatusBadRequest)
+	}
+
+	delivery := &model.WebhookDelivery{
+		WebhookId:   hook.Id,
+		TeamId:      history.TeamId,
+		PostId:      history.PostId,
+		URL:         history.URL,
+		ContentType: history.ContentType,
+		RequestBody: history.RequestBody,
+	}
+
+	delivery, err := a.Store().WebhookDelivery().Save(delivery)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.Srv.WebhookDelivery != nil {
+		a.Srv.WebhookDelivery.Wake()
+	}
+
+	return delivery, nil
+}
+
+// 保存期間を過ぎた送信履歴と、送信が終わったdeliveryを削除する
+func (a *App) PurgeWebhooksHistory() (int64, *model.AppError) {
+	days := *a.Config().WebhookSettings.HistoryRetentionDays
+	if days <= 0 {
+		return 0, nil
+	}
+
+	before := model.GetMillis() - int64(days)*int64(24*time.Hour/time.Millisecond)
+
+	total := int64(0)
+	for {
+		deleted, err := a.Store().WebhooksHistory().PermanentDeleteBefore(before, WEBHOOK_HISTORY_DELETE_BATCH_SIZE)
+		if err != nil {
+			return total, err
+		}
+
+		total += deleted
+		if deleted < WEBHOOK_HISTORY_DELETE_BATCH_SIZE {
+			break
+		}
+	}
+
+	for {
+		deleted, err := a.Store().WebhookDelivery().PermanentDeleteFinishedBefore(before, WEBHOOK_HISTORY_DELETE_BATCH_SIZE)
+		if err != nil {
+			return total, err
+		}
+
+		if deleted < WEBHOOK_HISTORY_DELETE_BATCH_SIZE {
+			break
+		}
+	}
+
+	if total > 0 {
+		mlog.Info("Purged webhooks history", mlog.Int64("count", total))
+	}
+
+	return total, nil
 }
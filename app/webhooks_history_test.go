@@ -0,0 +1,99 @@
+package app
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/clear-ness/qa-discussion/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPurgeWebhooksHistory(t *testing.T) {
+	th := Setup(t)
+	defer th.TearDown()
+
+	user := th.CreateUser(t)
+	team := th.CreateTeam(t, user)
+	hook := th.CreateWebhook(t, team, user, "https://example.com/hook", model.WEBHOOK_EVENT_POST_DELETED)
+
+	day := int64(24 * 60 * 60 * 1000)
+	now := model.GetMillis()
+	logHistory := func(createAt int64) *model.WebhooksHistory {
+		history := &model.WebhooksHistory{
+			Id:             model.NewId(),
+			WebhookId:      hook.Id,
+			TeamId:         team.Id,
+			ResponseStatus: http.StatusOK,
+			CreateAt:       createAt,
+		}
+		require.NoError(t, th.Store.WebhooksHistory().LogWebhookEvent(history))
+		return history
+	}
+
+	expired := logHistory(now - 31*day)
+	kept := logHistory(now - 29*day)
+
+	t.Run("disabled retention keeps everything", func(t *testing.T) {
+		th.UpdateConfig(func(cfg *model.Config) {
+			*cfg.WebhookSettings.HistoryRetentionDays = 0
+		})
+
+		deleted, err := th.App.PurgeWebhooksHistory()
+		require.Nil(t, err)
+		assert.Equal(t, int64(0), deleted)
+
+		_, err = th.Store.WebhooksHistory().Get(expired.Id)
+		require.Nil(t, err)
+	})
+
+	t.Run("deletes history older than the retention", func(t *testing.T) {
+		th.UpdateConfig(func(cfg *model.Config) {
+			*cfg.WebhookSettings.HistoryRetentionDays = 30
+		})
+
+		deleted, err := th.App.PurgeWebhooksHistory()
+		require.Nil(t, err)
+		assert.Equal(t, int64(1), deleted)
+
+		_, err = th.Store.WebhooksHistory().Get(expired.Id)
+		require.NotNil(t, err)
+		_, err = th.Store.WebhooksHistory().Get(kept.Id)
+		require.Nil(t, err)
+	})
+}
+
+func TestRedeliverWebhook(t *testing.T) {
+	th := Setup(t)
+	defer th.TearDown()
+
+	user := th.CreateUser(t)
+	team := th.CreateTeam(t, user)
+	hook := th.CreateWebhook(t, team, user, "https://example.com/hook", model.WEBHOOK_EVENT_POST_DELETED)
+
+	history := &model.WebhooksHistory{
+		Id:          model.NewId(),
+		WebhookId:   hook.Id,
+		TeamId:      team.Id,
+		URL:         "https://example.com/hook",
+		ContentType: "application/json",
+		RequestBody: `{"event":"ping"}`,
+	}
+	require.NoError(t, th.Store.WebhooksHistory().LogWebhookEvent(history))
+
+	delivery, err := th.App.RedeliverWebhook(history, hook)
+	require.Nil(t, err)
+	assert.Equal(t, history.URL, delivery.URL)
+	assert.Equal(t, history.RequestBody, delivery.RequestBody)
+
+	// 送信先から外したURLには再送しない
+	hook.URLs = []string{"https://example.com/new"}
+	hook, err = th.Store.Webhook().Update(hook)
+	require.Nil(t, err)
+
+	_, err = th.App.RedeliverWebhook(history, hook)
+	require.NotNil(t, err)
+	assert.Equal(t, "app.webhook.redeliver.url_removed.app_error", err.Id)
+	assert.Equal(t, http.StatusBadRequest, err.StatusCode)
+}
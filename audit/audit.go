@@ -1 +1,118 @@
 package audit
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/clear-ness/qa-discussion/model"
+)
+
+// Recordの書き出し先
+type Target interface {
+	Log(rec *Record) error
+	Shutdown() error
+}
+
+// Recordをキューに積み、別のgoroutineで全てのTargetに書き出す。
+// リクエストの処理がTargetの遅延に引きずられないよう、キューが溢れた場合は破棄する。
+type Audit struct {
+	mux     sync.RWMutex
+	targets []Target
+	queue   chan *Record
+	done    chan struct{}
+	closed  bool
+
+	// キューが溢れてRecordを破棄したときに呼ばれる
+	OnQueueFull func(rec *Record)
+	// Targetへの書き出しに失敗したときに呼ばれる
+	OnError func(err error)
+}
+
+func NewAudit(queueSize int) *Audit {
+	if queueSize <= 0 {
+		queueSize = 1
+	}
+
+	a := &Audit{
+		queue: make(chan *Record, queueSize),
+		done:  make(chan struct{}),
+	}
+
+	go a.run()
+
+	return a
+}
+
+func (a *Audit) AddTarget(t Target) {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+
+	a.targets = append(a.targets, t)
+}
+
+func (a *Audit) HasTargets() bool {
+	a.mux.RLock()
+	defer a.mux.RUnlock()
+
+	return len(a.targets) > 0
+}
+
+func (a *Audit) Log(rec *Record) {
+	if rec.CreateAt == 0 {
+		rec.CreateAt = model.GetMillis()
+	}
+
+	a.mux.RLock()
+	defer a.mux.RUnlock()
+
+	if a.closed || len(a.targets) == 0 {
+		return
+	}
+
+	select {
+	case a.queue <- rec:
+	default:
+		if a.OnQueueFull != nil {
+			a.OnQueueFull(rec)
+		}
+	}
+}
+
+func (a *Audit) run() {
+	defer close(a.done)
+
+	for rec := range a.queue {
+		a.mux.RLock()
+		targets := a.targets
+		a.mux.RUnlock()
+
+		for _, t := range targets {
+			if err := t.Log(rec); err != nil && a.OnError != nil {
+				a.OnError(err)
+			}
+		}
+	}
+}
+
+// キューに残っているRecordを書き出してから、全てのTargetを閉じる
+func (a *Audit) Shutdown() error {
+	a.mux.Lock()
+	if a.closed {
+		a.mux.Unlock()
+		return errors.New("audit already shut down")
+	}
+	a.closed = true
+	close(a.queue)
+	a.mux.Unlock()
+
+	<-a.done
+
+	var firstErr error
+	for _, t := range a.targets {
+		if err := t.Shutdown(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
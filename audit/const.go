@@ -9,6 +9,8 @@ const (
 	KeyClient    = "client"
 	KeyIPAddress = "ip_address"
 	KeyClusterID = "cluster_id"
+	KeyCreateAt  = "create_at"
+	KeyMeta      = "meta"
 
 	Success = "success"
 	Attempt = "attempt"
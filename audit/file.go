@@ -0,0 +1,128 @@
+package audit
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const (
+	megabyte = 1024 * 1024
+)
+
+// Recordを1行1JSONでファイルに追記する。
+// ファイルがMaxSizeMBを超えると name.1, name.2 ... とずらして新しいファイルに切り替え、
+// MaxBackupsより古いものは削除する。
+type FileTarget struct {
+	mux        sync.Mutex
+	filename   string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+func NewFileTarget(filename string, maxSizeMB int, maxBackups int) (*FileTarget, error) {
+	if maxSizeMB <= 0 {
+		return nil, fmt.Errorf("invalid max size for audit file: %d", maxSizeMB)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return nil, err
+	}
+
+	t := &FileTarget{
+		filename:   filename,
+		maxSize:    int64(maxSizeMB) * megabyte,
+		maxBackups: maxBackups,
+	}
+
+	if err := t.open(); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (t *FileTarget) open() error {
+	file, err := os.OpenFile(t.filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	t.file = file
+	t.size = info.Size()
+	return nil
+}
+
+func (t *FileTarget) Log(rec *Record) error {
+	line := []byte(rec.ToJson() + "\n")
+
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	if t.file == nil {
+		return fmt.Errorf("audit file %s is closed", t.filename)
+	}
+
+	if t.size > 0 && t.size+int64(len(line)) > t.maxSize {
+		if err := t.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := t.file.Write(line)
+	t.size += int64(n)
+	return err
+}
+
+func (t *FileTarget) rotate() error {
+	if err := t.file.Close(); err != nil {
+		return err
+	}
+	t.file = nil
+
+	if t.maxBackups <= 0 {
+		if err := os.Remove(t.filename); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return t.open()
+	}
+
+	os.Remove(t.backupName(t.maxBackups))
+	for i := t.maxBackups - 1; i >= 1; i-- {
+		if err := os.Rename(t.backupName(i), t.backupName(i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	if err := os.Rename(t.filename, t.backupName(1)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return t.open()
+}
+
+func (t *FileTarget) backupName(n int) string {
+	return fmt.Sprintf("%s.%d", t.filename, n)
+}
+
+func (t *FileTarget) Shutdown() error {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	if t.file == nil {
+		return nil
+	}
+
+	err := t.file.Close()
+	t.file = nil
+	return err
+}
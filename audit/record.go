@@ -1,10 +1,15 @@
 package audit
 
+import (
+	"encoding/json"
+)
+
 type Meta map[string]interface{}
 
 type FuncMetaTypeConv func(val interface{}) (newVal interface{}, converted bool)
 
 type Record struct {
+	CreateAt  int64
 	APIPath   string
 	Event     string
 	Status    string
@@ -43,3 +48,40 @@ func (rec *Record) AddMeta(name string, val interface{}) {
 func (rec *Record) AddMetaTypeConverter(f FuncMetaTypeConv) {
 	rec.metaConv = append(rec.metaConv, f)
 }
+
+// ファイルやsyslogに書き出す1行分のJSON
+func (rec *Record) ToJson() string {
+	fields := map[string]interface{}{
+		KeyCreateAt:  rec.CreateAt,
+		KeyAPIPath:   rec.APIPath,
+		KeyEvent:     rec.Event,
+		KeyStatus:    rec.Status,
+		KeyUserID:    rec.UserID,
+		KeySessionID: rec.SessionID,
+		KeyClient:    rec.Client,
+		KeyIPAddress: rec.IPAddress,
+	}
+	if len(rec.Meta) > 0 {
+		fields[KeyMeta] = rec.Meta
+	}
+
+	b, err := json.Marshal(fields)
+	if err != nil {
+		// metaに書き出せない値が含まれていた場合もRecord自体は残す
+		delete(fields, KeyMeta)
+		b, _ = json.Marshal(fields)
+	}
+	return string(b)
+}
+
+func (rec *Record) MetaToJson() string {
+	if len(rec.Meta) == 0 {
+		return ""
+	}
+
+	b, err := json.Marshal(rec.Meta)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
@@ -0,0 +1,35 @@
+//go:build !windows
+// +build !windows
+
+package audit
+
+import (
+	"log/syslog"
+)
+
+// RecordをJSONにしてsyslogに送る
+type SyslogTarget struct {
+	writer *syslog.Writer
+}
+
+// networkとaddrが空の場合はローカルのsyslogに接続する
+func NewSyslogTarget(network string, addr string, tag string) (*SyslogTarget, error) {
+	writer, err := syslog.Dial(network, addr, syslog.LOG_INFO|syslog.LOG_AUTH, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SyslogTarget{writer: writer}, nil
+}
+
+func (t *SyslogTarget) Log(rec *Record) error {
+	if rec.Status == Fail {
+		return t.writer.Warning(rec.ToJson())
+	}
+
+	return t.writer.Info(rec.ToJson())
+}
+
+func (t *SyslogTarget) Shutdown() error {
+	return t.writer.Close()
+}
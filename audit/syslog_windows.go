@@ -0,0 +1,20 @@
+package audit
+
+import (
+	"errors"
+)
+
+type SyslogTarget struct {
+}
+
+func NewSyslogTarget(network string, addr string, tag string) (*SyslogTarget, error) {
+	return nil, errors.New("syslog is not supported on windows")
+}
+
+func (t *SyslogTarget) Log(rec *Record) error {
+	return nil
+}
+
+func (t *SyslogTarget) Shutdown() error {
+	return nil
+}
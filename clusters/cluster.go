@@ -3,9 +3,12 @@ package clusters
 import (
 	"context"
 	"strings"
+	"sync"
 
+	"github.com/clear-ness/qa-discussion/mlog"
 	"github.com/clear-ness/qa-discussion/model"
 	"github.com/clear-ness/qa-discussion/services/configservice"
+	"github.com/clear-ness/qa-discussion/services/metrics"
 
 	"github.com/go-redis/redis/v8"
 )
@@ -14,17 +17,24 @@ type ClusterInterface interface {
 	Start(clusterId string)
 	RegisterClusterMessageHandler(event string, cmh ClusterMessageHandler)
 	SendClusterMessage(cm *model.ClusterMessage)
+	Ping(ctx context.Context) error
+	Stop()
 }
 
 type ClusterImpl struct {
 	configService configservice.ConfigService
 	handlers      map[string]ClusterMessageHandler
+	metrics       metrics.MetricsInterface
+
+	// 送信中のメッセージ。管理コマンドがすぐ終了しても送り切れるように、Stopで待つ
+	publishing sync.WaitGroup
 }
 
-func MakeCluster(configService configservice.ConfigService) ClusterInterface {
+func MakeCluster(configService configservice.ConfigService, metrics metrics.MetricsInterface) ClusterInterface {
 	return &ClusterImpl{
-		configService,
-		make(map[string]ClusterMessageHandler),
+		configService: configService,
+		handlers:      make(map[string]ClusterMessageHandler),
+		metrics:       metrics,
 	}
 }
 
@@ -34,7 +44,7 @@ func (h *ClusterImpl) RegisterClusterMessageHandler(event string, cmh ClusterMes
 }
 
 func GetAllClusterChannels() []string {
-	allChannels := []string{model.CLUSTER_EVENT_WEBSOCKET, model.CLUSTER_EVENT_CLEAR_SESSION_CACHE_FOR_USER}
+	allChannels := []string{model.CLUSTER_EVENT_WEBSOCKET, model.CLUSTER_EVENT_CLEAR_SESSION_CACHE_FOR_USER, model.CLUSTER_EVENT_CONFIG_CHANGED, model.CLUSTER_EVENT_INVALIDATE_ALL_CACHES}
 	return allChannels
 }
 
@@ -50,6 +60,13 @@ func (h *ClusterImpl) ClusterClient() *redis.Client {
 	return client
 }
 
+func (h *ClusterImpl) Ping(ctx context.Context) error {
+	client := h.ClusterClient()
+	defer client.Close()
+
+	return client.Ping(ctx).Err()
+}
+
 func (h *ClusterImpl) ServeClusterMessage(cm *model.ClusterMessage) {
 	// 各eventに応じたstructを選ぶ
 	handler, ok := h.handlers[cm.Event]
@@ -85,23 +102,40 @@ func (h *ClusterImpl) subscribe(ctx context.Context, clusterId string) {
 			continue
 		}
 
+		if h.metrics != nil {
+			h.metrics.IncrementClusterMessageReceived(cm.Event)
+		}
+
 		h.ServeClusterMessage(cm)
 	}
 }
 
 func (h *ClusterImpl) SendClusterMessage(cm *model.ClusterMessage) {
 	var ctx = context.Background()
+	h.publishing.Add(1)
 	go func() {
+		defer h.publishing.Done()
 		h.publish(ctx, cm)
 	}()
 }
 
+func (h *ClusterImpl) Stop() {
+	h.publishing.Wait()
+}
+
 func (h *ClusterImpl) publish(ctx context.Context, cm *model.ClusterMessage) {
 	client := h.ClusterClient()
+	defer client.Close()
 
 	// ClusterMessage.Eventをそのままredis pub/subのチャンネル名にする
+	// 届かなかった場合も呼び出し元の処理は終わっているので、記録だけする
 	err := client.Publish(ctx, cm.Event, cm.ToJson()).Err()
 	if err != nil {
-		panic(err)
+		mlog.Error("Failed to publish cluster message", mlog.String("event", cm.Event), mlog.Err(err))
+		return
+	}
+
+	if h.metrics != nil {
+		h.metrics.IncrementClusterMessageSent(cm.Event)
 	}
 }
@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var CacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "manage caches",
+}
+
+// L1キャッシュは各サーバーのメモリにあるので、クラスタ経由で全サーバーに削除させる
+var CachePurgeCmd = &cobra.Command{
+	Use:     "purge",
+	Short:   "purge the redis cache and the in-memory caches of all servers",
+	Example: "  cache purge\n  cache purge --skip-redis",
+	Args:    cobra.NoArgs,
+	RunE:    cachePurgeCmdF,
+}
+
+func init() {
+	CachePurgeCmd.Flags().Bool("skip-redis", false, "only purge the in-memory caches.")
+
+	CacheCmd.AddCommand(CachePurgeCmd)
+	RootCmd.AddCommand(CacheCmd)
+}
+
+func cachePurgeCmdF(command *cobra.Command, args []string) error {
+	skipRedis, _ := command.Flags().GetBool("skip-redis")
+
+	server, err := initServer()
+	if err != nil {
+		return err
+	}
+	defer server.Shutdown()
+
+	a := server.FakeApp()
+
+	if appErr := a.InvalidateAllCaches(!skipRedis); appErr != nil {
+		return errors.Wrap(appErr, "failed to purge caches")
+	}
+
+	fmt.Println("Purged caches")
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/clear-ness/qa-discussion/config"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var ConfigCmd = &cobra.Command{
+	Use:   "config",
+	Short: "read and update the configuration",
+}
+
+var ConfigGetCmd = &cobra.Command{
+	Use:     "get [key]",
+	Short:   "show a setting or a whole section",
+	Example: "  config get RateLimitSettings\n  config get RateLimitSettings.PerSec",
+	Args:    cobra.ExactArgs(1),
+	RunE:    configGetCmdF,
+}
+
+// ファイルを監視しているサーバーはすぐに読み直す。DBに保存している場合は、
+// 設定の再読み込みAPIを呼ぶか再起動するまで他のサーバーには反映されない
+var ConfigSetCmd = &cobra.Command{
+	Use:     "set [key] [value]",
+	Short:   "update a setting",
+	Example: "  config set RateLimitSettings.PerSec 20\n  config set ServiceSettings.AllowCorsFrom \"https://a.example.com https://b.example.com\"",
+	Args:    cobra.ExactArgs(2),
+	RunE:    configSetCmdF,
+}
+
+var ConfigValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "validate the configuration",
+	Args:  cobra.NoArgs,
+	RunE:  configValidateCmdF,
+}
+
+func init() {
+	ConfigCmd.AddCommand(
+		ConfigGetCmd,
+		ConfigSetCmd,
+		ConfigValidateCmd,
+	)
+	RootCmd.AddCommand(ConfigCmd)
+}
+
+func configGetCmdF(command *cobra.Command, args []string) error {
+	configStore, err := config.NewStore(viper.GetString("config"), false)
+	if err != nil {
+		return errors.Wrap(err, "failed to load config")
+	}
+	defer configStore.Close()
+
+	value, err := config.GetValue(configStore.Get(), args[0])
+	if err != nil {
+		return err
+	}
+
+	printJson(value)
+
+	return nil
+}
+
+func configSetCmdF(command *cobra.Command, args []string) error {
+	key, value := args[0], args[1]
+
+	configStore, err := config.NewStore(viper.GetString("config"), false)
+	if err != nil {
+		return errors.Wrap(err, "failed to load config")
+	}
+	defer configStore.Close()
+
+	// 環境変数で上書きされている項目は保存しても反映されない
+	parts := strings.Split(key, ".")
+	if section, ok := configStore.GetEnvironmentOverrides()[parts[0]].(map[string]interface{}); ok && len(parts) == 2 {
+		if _, ok := section[parts[1]]; ok {
+			fmt.Fprintf(os.Stderr, "%s is overridden by an environment variable, the saved value will not take effect.\n", key)
+		}
+	}
+
+	newCfg := configStore.Get().Clone()
+	if err := config.SetValue(newCfg, key, value); err != nil {
+		return err
+	}
+
+	if _, err := configStore.Set(newCfg); err != nil {
+		return errors.Wrap(err, "failed to save config")
+	}
+
+	fmt.Println("Saved", key, "to", configStore.String())
+
+	return nil
+}
+
+func configValidateCmdF(command *cobra.Command, args []string) error {
+	// 読み込み時に検証されるので、読み込めれば正しい
+	configStore, err := config.NewStore(viper.GetString("config"), false)
+	if err != nil {
+		return errors.Wrap(err, "invalid config")
+	}
+	defer configStore.Close()
+
+	fmt.Println("The configuration in", configStore.String(), "is valid.")
+
+	return nil
+}
@@ -0,0 +1,188 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/clear-ness/qa-discussion/config"
+	"github.com/clear-ness/qa-discussion/db"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var DbCmd = &cobra.Command{
+	Use:   "db",
+	Short: "manage the database",
+}
+
+// バイナリに埋め込んだスキーマのマイグレーションを、設定のSqlSettingsのDBに適用する
+var MigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "apply or roll back the schema migrations embedded in the binary",
+}
+
+var MigrateUpCmd = &cobra.Command{
+	Use:   "up",
+	Short: "apply all pending migrations",
+	Args:  cobra.NoArgs,
+	RunE:  migrateUpCmdF,
+}
+
+var MigrateDownCmd = &cobra.Command{
+	Use:   "down",
+	Short: "roll back the most recently applied migration",
+	Args:  cobra.NoArgs,
+	RunE:  migrateDownCmdF,
+}
+
+var MigrateRedoCmd = &cobra.Command{
+	Use:   "redo",
+	Short: "roll back and re-apply the most recently applied migration",
+	Args:  cobra.NoArgs,
+	RunE:  migrateRedoCmdF,
+}
+
+// 全てのテーブルとデータを削除するので、--confirmを付けた場合のみ実行する
+var MigrateResetCmd = &cobra.Command{
+	Use:     "reset",
+	Short:   "roll back all applied migrations",
+	Long:    "Roll back all applied migrations. This drops every table and all data, so --confirm is required.",
+	Example: "  db migrate reset --confirm",
+	Args:    cobra.NoArgs,
+	RunE:    migrateResetCmdF,
+}
+
+var MigrateStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "show which migrations are applied",
+	Args:  cobra.NoArgs,
+	RunE:  migrateStatusCmdF,
+}
+
+func init() {
+	MigrateResetCmd.Flags().Bool("confirm", false, "confirm that all tables and data will be dropped.")
+
+	MigrateCmd.AddCommand(
+		MigrateUpCmd,
+		MigrateDownCmd,
+		MigrateRedoCmd,
+		MigrateResetCmd,
+		MigrateStatusCmd,
+	)
+
+	DbCmd.AddCommand(MigrateCmd)
+	RootCmd.AddCommand(DbCmd)
+}
+
+// サーバーを起動するとスキーマを前提にした初期化が走るので、設定だけを読んで接続する
+func newMigrator() (*db.Migrator, error) {
+	configStore, err := config.NewStore(viper.GetString("config"), false)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load config")
+	}
+	defer configStore.Close()
+
+	return db.NewMigrator(&configStore.Get().SqlSettings)
+}
+
+func migrateUpCmdF(command *cobra.Command, args []string) error {
+	migrator, err := newMigrator()
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+
+	applied, err := migrator.Up()
+	for _, migration := range applied {
+		fmt.Println("Applied", migration.Name)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(applied) == 0 {
+		fmt.Println("No pending migrations")
+	}
+
+	return nil
+}
+
+func migrateDownCmdF(command *cobra.Command, args []string) error {
+	migrator, err := newMigrator()
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+
+	migration, err := migrator.Down()
+	if err != nil {
+		return err
+	}
+
+	if migration == nil {
+		fmt.Println("No applied migrations")
+		return nil
+	}
+
+	fmt.Println("Rolled back", migration.Name)
+	return nil
+}
+
+func migrateRedoCmdF(command *cobra.Command, args []string) error {
+	migrator, err := newMigrator()
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+
+	migration, err := migrator.Redo()
+	if err != nil {
+		return err
+	}
+
+	if migration == nil {
+		fmt.Println("No applied migrations")
+		return nil
+	}
+
+	fmt.Println("Redone", migration.Name)
+	return nil
+}
+
+func migrateResetCmdF(command *cobra.Command, args []string) error {
+	if confirm, _ := command.Flags().GetBool("confirm"); !confirm {
+		return errors.New("reset drops all tables and data; re-run with --confirm to proceed")
+	}
+
+	migrator, err := newMigrator()
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+
+	rolledBack, err := migrator.Reset()
+	for _, migration := range rolledBack {
+		fmt.Println("Rolled back", migration.Name)
+	}
+
+	return err
+}
+
+func migrateStatusCmdF(command *cobra.Command, args []string) error {
+	migrator, err := newMigrator()
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+
+	statuses, err := migrator.Status()
+	if err != nil {
+		return err
+	}
+
+	for _, status := range statuses {
+		fmt.Println(status.String())
+	}
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrateResetRequiresConfirm(t *testing.T) {
+	command := &cobra.Command{}
+	command.Flags().AddFlagSet(MigrateResetCmd.Flags())
+
+	// 設定を読む前に止まるので、存在しない設定ファイルでもDBには接続しない
+	original := viper.GetString("config")
+	viper.Set("config", "/nonexistent/config.json")
+	defer viper.Set("config", original)
+
+	err := migrateResetCmdF(command, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--confirm")
+
+	require.NoError(t, command.Flags().Set("confirm", "true"))
+	defer command.Flags().Set("confirm", "false")
+
+	// --confirmを付けると先に進み、設定の読み込みで失敗する
+	err = migrateResetCmdF(command, nil)
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "--confirm")
+}
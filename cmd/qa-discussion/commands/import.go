@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var ImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "import data from other systems",
+}
+
+// 大きなデータダンプはHTTPでアップロードせず、サーバー上で展開したディレクトリから直接読み込む
+var StackExchangeImportCmd = &cobra.Command{
+	Use:     "stackexchange [dump directory]",
+	Short:   "import an extracted Stack Exchange data dump",
+	Example: "  import stackexchange ./superuser.com --site superuser --team 8ewqz4ymbbfn9m3b5urq1dfk7a",
+	Args:    cobra.ExactArgs(1),
+	RunE:    stackExchangeImportCmdF,
+}
+
+func init() {
+	StackExchangeImportCmd.Flags().String("site", "", "name of the source site, used to detect already imported data. defaults to the directory name.")
+	StackExchangeImportCmd.Flags().String("team", "", "id of the team to import into. imports into the public site if omitted.")
+
+	ImportCmd.AddCommand(StackExchangeImportCmd)
+	RootCmd.AddCommand(ImportCmd)
+}
+
+func stackExchangeImportCmdF(command *cobra.Command, args []string) error {
+	dir := args[0]
+
+	site, _ := command.Flags().GetString("site")
+	if len(site) == 0 {
+		site = filepath.Base(filepath.Clean(dir))
+	}
+
+	teamId, _ := command.Flags().GetString("team")
+
+	server, err := initServer()
+	if err != nil {
+		return err
+	}
+	defer server.Shutdown()
+
+	a := server.FakeApp()
+
+	report, appErr := a.StackExchangeImport(dir, site, teamId)
+	if report != nil {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(report)
+	}
+
+	if appErr != nil {
+		return errors.Wrap(appErr, "stack exchange import failed")
+	}
+
+	return nil
+}
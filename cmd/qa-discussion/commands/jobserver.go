@@ -9,6 +9,7 @@ import (
 	"github.com/clear-ness/qa-discussion/model"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 // if you want to run jobs on jobservers, use this command
@@ -48,6 +49,7 @@ func jobserverCmdF(command *cobra.Command, args []string) error {
 
 func initContext(interval *string) (*app.App, error) {
 	var options []app.Option
+	options = append(options, app.Config(viper.GetString("config"), false))
 	options = append(options, app.InitEmailBatching(interval))
 
 	server, err := app.NewServer(options...)
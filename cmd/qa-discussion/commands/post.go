@@ -0,0 +1,136 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/clear-ness/qa-discussion/app"
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var PostCmd = &cobra.Command{
+	Use:   "post",
+	Short: "moderate posts",
+}
+
+var PostLockCmd = &cobra.Command{
+	Use:     "lock [post ids]",
+	Short:   "lock posts so that they can not be answered, commented or voted on",
+	Example: "  post lock 8ewqz4ymbbfn9m3b5urq1dfk7a\n  post lock 8ewqz4ymbbfn9m3b5urq1dfk7a --cancel",
+	Args:    cobra.MinimumNArgs(1),
+	RunE:    postLockCmdF,
+}
+
+var PostProtectCmd = &cobra.Command{
+	Use:     "protect [post ids]",
+	Short:   "protect questions from answers by users with few points",
+	Example: "  post protect 8ewqz4ymbbfn9m3b5urq1dfk7a\n  post protect 8ewqz4ymbbfn9m3b5urq1dfk7a --cancel",
+	Args:    cobra.MinimumNArgs(1),
+	RunE:    postProtectCmdF,
+}
+
+var PostDeleteCmd = &cobra.Command{
+	Use:     "delete [post ids]",
+	Short:   "delete posts",
+	Example: "  post delete 8ewqz4ymbbfn9m3b5urq1dfk7a --force",
+	Args:    cobra.MinimumNArgs(1),
+	RunE:    postDeleteCmdF,
+}
+
+var PostRestoreCmd = &cobra.Command{
+	Use:     "restore [post ids]",
+	Short:   "restore deleted posts",
+	Example: "  post restore 8ewqz4ymbbfn9m3b5urq1dfk7a",
+	Args:    cobra.MinimumNArgs(1),
+	RunE:    postRestoreCmdF,
+}
+
+func init() {
+	PostLockCmd.Flags().Bool("cancel", false, "unlock the posts.")
+	PostProtectCmd.Flags().Bool("cancel", false, "unprotect the posts.")
+	PostDeleteCmd.Flags().Bool("force", false, "delete even if the post has answers or comments.")
+
+	PostCmd.AddCommand(
+		PostLockCmd,
+		PostProtectCmd,
+		PostDeleteCmd,
+		PostRestoreCmd,
+	)
+	RootCmd.AddCommand(PostCmd)
+}
+
+// 管理コマンドからの操作は、操作したユーザーを空にして記録する
+func forEachPost(args []string, verb string, fn func(a *app.App, postId string) *model.AppError) error {
+	server, err := initServer()
+	if err != nil {
+		return err
+	}
+	defer server.Shutdown()
+
+	a := server.FakeApp()
+
+	for _, postId := range args {
+		if appErr := fn(a, postId); appErr != nil {
+			return errors.Wrapf(appErr, "failed to %s post %s", verb, postId)
+		}
+
+		fmt.Println("Succeeded to", verb, postId)
+	}
+
+	return nil
+}
+
+func postLockCmdF(command *cobra.Command, args []string) error {
+	cancel, _ := command.Flags().GetBool("cancel")
+
+	if cancel {
+		return forEachPost(args, "unlock", func(a *app.App, postId string) *model.AppError {
+			return a.CancelLockPost(postId, "")
+		})
+	}
+
+	return forEachPost(args, "lock", func(a *app.App, postId string) *model.AppError {
+		return a.LockPost(postId, "")
+	})
+}
+
+func postProtectCmdF(command *cobra.Command, args []string) error {
+	cancel, _ := command.Flags().GetBool("cancel")
+
+	if cancel {
+		return forEachPost(args, "unprotect", func(a *app.App, postId string) *model.AppError {
+			return a.CancelProtectPost(postId, "")
+		})
+	}
+
+	return forEachPost(args, "protect", func(a *app.App, postId string) *model.AppError {
+		return a.ProtectPost(postId, "")
+	})
+}
+
+func postDeleteCmdF(command *cobra.Command, args []string) error {
+	force, _ := command.Flags().GetBool("force")
+
+	return forEachPost(args, "delete", func(a *app.App, postId string) *model.AppError {
+		post, err := a.GetSinglePost(postId, false)
+		if err != nil {
+			return err
+		}
+
+		if force {
+			_, err = a.DeletePostForcely(post, "")
+		} else {
+			_, err = a.DeletePost(post, "")
+		}
+
+		return err
+	})
+}
+
+func postRestoreCmdF(command *cobra.Command, args []string) error {
+	return forEachPost(args, "restore", func(a *app.App, postId string) *model.AppError {
+		_, err := a.RestorePost(postId)
+		return err
+	})
+}
@@ -0,0 +1,58 @@
+package commands
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/clear-ness/qa-discussion/app"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var ReconcileCmd = &cobra.Command{
+	Use:   "reconcile",
+	Short: "repair denormalized data",
+}
+
+// 部分的な失敗でずれたカウンタを元のテーブルから計算し直す
+var ReconcileCountersCmd = &cobra.Command{
+	Use:     "counters",
+	Short:   "recompute vote, answer, view, tag and point counters from their source tables",
+	Example: "  reconcile counters --dry-run",
+	Args:    cobra.NoArgs,
+	RunE:    reconcileCountersCmdF,
+}
+
+func init() {
+	ReconcileCountersCmd.Flags().Bool("dry-run", false, "only report the differences without fixing them.")
+	ReconcileCountersCmd.Flags().Int("batch-size", app.RECONCILE_DEFAULT_BATCH_SIZE, "number of rows to check per query.")
+
+	ReconcileCmd.AddCommand(ReconcileCountersCmd)
+	RootCmd.AddCommand(ReconcileCmd)
+}
+
+func reconcileCountersCmdF(command *cobra.Command, args []string) error {
+	dryRun, _ := command.Flags().GetBool("dry-run")
+	batchSize, _ := command.Flags().GetInt("batch-size")
+
+	server, err := initServer()
+	if err != nil {
+		return err
+	}
+	defer server.Shutdown()
+
+	a := server.FakeApp()
+
+	report, appErr := a.ReconcileCounters(dryRun, batchSize)
+	if report != nil {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(report)
+	}
+
+	if appErr != nil {
+		return errors.Wrap(appErr, "counter reconciliation failed")
+	}
+
+	return nil
+}
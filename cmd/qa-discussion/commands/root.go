@@ -18,7 +18,7 @@ var RootCmd = &cobra.Command{
 }
 
 func init() {
-	RootCmd.PersistentFlags().StringP("config", "c", "config.json", "config file name.")
+	RootCmd.PersistentFlags().StringP("config", "c", "config.json", "config file name, or mysql:// dsn to load config from the database.")
 
 	viper.SetEnvPrefix("qa-discussion")
 	viper.BindEnv("config")
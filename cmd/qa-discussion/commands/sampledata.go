@@ -0,0 +1,68 @@
+package commands
+
+import (
+	"time"
+
+	"github.com/clear-ness/qa-discussion/app"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// 負荷試験用のデータを生成する。生成したユーザーは全員同じパスワードでログインできる
+var SampleDataCmd = &cobra.Command{
+	Use:     "sampledata",
+	Short:   "generate fake teams, users, questions, answers, votes and tags for load testing",
+	Example: "  sampledata --users 500 --questions 5000 --seed 1",
+	Args:    cobra.NoArgs,
+	RunE:    sampleDataCmdF,
+}
+
+func init() {
+	SampleDataCmd.Flags().Int64("seed", 0, "seed for the random generator. defaults to the current time.")
+	SampleDataCmd.Flags().Int("teams", 2, "number of teams.")
+	SampleDataCmd.Flags().Int("users", 50, "number of users.")
+	SampleDataCmd.Flags().Int("questions", 200, "number of questions, spread over the public site and the teams.")
+	SampleDataCmd.Flags().Int("answers", 5, "maximum number of answers per question.")
+	SampleDataCmd.Flags().Int("comments", 3, "maximum number of comments per question or answer.")
+	SampleDataCmd.Flags().Int("votes", 10, "maximum number of votes per question or answer.")
+	SampleDataCmd.Flags().Int("tags", 30, "number of distinct tags to use.")
+	SampleDataCmd.Flags().Int("days", 90, "spread the creation dates over this many past days.")
+
+	RootCmd.AddCommand(SampleDataCmd)
+}
+
+func sampleDataCmdF(command *cobra.Command, args []string) error {
+	opts := &app.SampleDataOptions{}
+	opts.Seed, _ = command.Flags().GetInt64("seed")
+	opts.Teams, _ = command.Flags().GetInt("teams")
+	opts.Users, _ = command.Flags().GetInt("users")
+	opts.Questions, _ = command.Flags().GetInt("questions")
+	opts.Answers, _ = command.Flags().GetInt("answers")
+	opts.Comments, _ = command.Flags().GetInt("comments")
+	opts.Votes, _ = command.Flags().GetInt("votes")
+	opts.Tags, _ = command.Flags().GetInt("tags")
+	opts.Days, _ = command.Flags().GetInt("days")
+
+	if !command.Flags().Changed("seed") {
+		opts.Seed = time.Now().UnixNano()
+	}
+
+	server, err := initServer()
+	if err != nil {
+		return err
+	}
+	defer server.Shutdown()
+
+	a := server.FakeApp()
+
+	report, appErr := a.GenerateSampleData(opts)
+	if report != nil {
+		printJson(report)
+	}
+
+	if appErr != nil {
+		return errors.Wrap(appErr, "sample data generation failed")
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package commands
+
+import (
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var SearchCmd = &cobra.Command{
+	Use:   "search",
+	Short: "inspect the search indexes",
+}
+
+var SearchStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "show the health, document count and size of each search index",
+	Args:  cobra.NoArgs,
+	RunE:  searchStatusCmdF,
+}
+
+func init() {
+	SearchCmd.AddCommand(SearchStatusCmd)
+	RootCmd.AddCommand(SearchCmd)
+}
+
+func searchStatusCmdF(command *cobra.Command, args []string) error {
+	server, err := initServer()
+	if err != nil {
+		return err
+	}
+	defer server.Shutdown()
+
+	a := server.FakeApp()
+
+	statuses, appErr := a.GetSearchIndexStatuses()
+	if appErr != nil {
+		return errors.Wrap(appErr, "failed to get search index status")
+	}
+
+	printJson(statuses)
+
+	return nil
+}
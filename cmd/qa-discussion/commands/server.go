@@ -9,6 +9,7 @@ import (
 	"github.com/clear-ness/qa-discussion/app"
 	"github.com/clear-ness/qa-discussion/web"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 var serverCmd = &cobra.Command{
@@ -29,7 +30,7 @@ func serverCmdF(command *cobra.Command, args []string) error {
 func runServer() error {
 	interruptChan := make(chan os.Signal, 1)
 
-	server, err := app.NewServer()
+	server, err := app.NewServer(app.Config(viper.GetString("config"), true))
 	if err != nil {
 		return err
 	}
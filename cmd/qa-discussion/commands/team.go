@@ -0,0 +1,214 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var TeamCmd = &cobra.Command{
+	Use:   "team",
+	Short: "manage teams",
+}
+
+var TeamCreateCmd = &cobra.Command{
+	Use:     "create [name]",
+	Short:   "create a team owned by a user",
+	Example: "  team create myteam --owner user@example.com --type public",
+	Args:    cobra.ExactArgs(1),
+	RunE:    teamCreateCmdF,
+}
+
+var TeamArchiveCmd = &cobra.Command{
+	Use:     "archive [team ids]",
+	Short:   "archive teams",
+	Example: "  team archive 8ewqz4ymbbfn9m3b5urq1dfk7a",
+	Args:    cobra.MinimumNArgs(1),
+	RunE:    teamArchiveCmdF,
+}
+
+var TeamAddCmd = &cobra.Command{
+	Use:     "add [team id] [users]",
+	Short:   "add users to a team",
+	Example: "  team add 8ewqz4ymbbfn9m3b5urq1dfk7a user@example.com user2",
+	Args:    cobra.MinimumNArgs(2),
+	RunE:    teamAddCmdF,
+}
+
+var TeamRemoveCmd = &cobra.Command{
+	Use:     "remove [team id] [users]",
+	Short:   "remove users from a team",
+	Example: "  team remove 8ewqz4ymbbfn9m3b5urq1dfk7a user@example.com user2",
+	Args:    cobra.MinimumNArgs(2),
+	RunE:    teamRemoveCmdF,
+}
+
+var TeamMemberTypeCmd = &cobra.Command{
+	Use:     "member-type [team id] [user] [normal|admin]",
+	Short:   "change the type of a team member",
+	Example: "  team member-type 8ewqz4ymbbfn9m3b5urq1dfk7a user@example.com admin",
+	Args:    cobra.ExactArgs(3),
+	RunE:    teamMemberTypeCmdF,
+}
+
+func init() {
+	TeamCreateCmd.Flags().String("owner", "", "user who becomes the team admin. the team email is taken from this user.")
+	TeamCreateCmd.Flags().String("type", model.TEAM_TYPE_PRIVATE, "public or private.")
+	TeamCreateCmd.Flags().String("description", "", "description of the team.")
+	TeamCreateCmd.MarkFlagRequired("owner")
+
+	TeamAddCmd.Flags().Bool("admin", false, "add the users as team admins.")
+
+	TeamCmd.AddCommand(
+		TeamCreateCmd,
+		TeamArchiveCmd,
+		TeamAddCmd,
+		TeamRemoveCmd,
+		TeamMemberTypeCmd,
+	)
+	RootCmd.AddCommand(TeamCmd)
+}
+
+func teamCreateCmdF(command *cobra.Command, args []string) error {
+	owner, _ := command.Flags().GetString("owner")
+	teamType, _ := command.Flags().GetString("type")
+	description, _ := command.Flags().GetString("description")
+
+	if teamType != model.TEAM_TYPE_PUBLIC && teamType != model.TEAM_TYPE_PRIVATE {
+		return errors.Errorf("invalid team type %s", teamType)
+	}
+
+	server, err := initServer()
+	if err != nil {
+		return err
+	}
+	defer server.Shutdown()
+
+	a := server.FakeApp()
+
+	user, err := getUserFromArg(a, owner)
+	if err != nil {
+		return err
+	}
+
+	team, appErr := a.CreateTeamWithUser(&model.Team{
+		Name:        args[0],
+		Type:        teamType,
+		Description: description,
+	}, user.Id)
+	if appErr != nil {
+		return errors.Wrap(appErr, "failed to create the team")
+	}
+
+	printJson(team)
+
+	return nil
+}
+
+func teamArchiveCmdF(command *cobra.Command, args []string) error {
+	server, err := initServer()
+	if err != nil {
+		return err
+	}
+	defer server.Shutdown()
+
+	a := server.FakeApp()
+
+	for _, teamId := range args {
+		if appErr := a.SoftDeleteTeam(teamId); appErr != nil {
+			return errors.Wrapf(appErr, "failed to archive team %s", teamId)
+		}
+
+		fmt.Println("Archived", teamId)
+	}
+
+	return nil
+}
+
+func teamAddCmdF(command *cobra.Command, args []string) error {
+	isAdmin, _ := command.Flags().GetBool("admin")
+
+	server, err := initServer()
+	if err != nil {
+		return err
+	}
+	defer server.Shutdown()
+
+	a := server.FakeApp()
+
+	team, appErr := a.GetTeam(args[0])
+	if appErr != nil {
+		return errors.Wrap(appErr, "failed to get the team")
+	}
+
+	for _, arg := range args[1:] {
+		user, err := getUserFromArg(a, arg)
+		if err != nil {
+			return err
+		}
+
+		if appErr := a.JoinUserToTeam(team, user, isAdmin); appErr != nil {
+			return errors.Wrapf(appErr, "failed to add user %s", arg)
+		}
+
+		fmt.Println("Added", user.Username)
+	}
+
+	return nil
+}
+
+func teamRemoveCmdF(command *cobra.Command, args []string) error {
+	server, err := initServer()
+	if err != nil {
+		return err
+	}
+	defer server.Shutdown()
+
+	a := server.FakeApp()
+
+	for _, arg := range args[1:] {
+		user, err := getUserFromArg(a, arg)
+		if err != nil {
+			return err
+		}
+
+		if appErr := a.RemoveUserFromTeam(args[0], user.Id, ""); appErr != nil {
+			return errors.Wrapf(appErr, "failed to remove user %s", arg)
+		}
+
+		fmt.Println("Removed", user.Username)
+	}
+
+	return nil
+}
+
+func teamMemberTypeCmdF(command *cobra.Command, args []string) error {
+	memberType := args[2]
+	if memberType != model.TEAM_MEMBER_TYPE_NORMAL && memberType != model.TEAM_MEMBER_TYPE_ADMIN {
+		return errors.Errorf("invalid member type %s", memberType)
+	}
+
+	server, err := initServer()
+	if err != nil {
+		return err
+	}
+	defer server.Shutdown()
+
+	a := server.FakeApp()
+
+	user, err := getUserFromArg(a, args[1])
+	if err != nil {
+		return err
+	}
+
+	member, appErr := a.UpdateTeamMemberType(args[0], user.Id, memberType)
+	if appErr != nil {
+		return errors.Wrap(appErr, "failed to change the member type")
+	}
+
+	printJson(member)
+
+	return nil
+}
@@ -0,0 +1,373 @@
+package commands
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/clear-ness/qa-discussion/app"
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+var UserCmd = &cobra.Command{
+	Use:   "user",
+	Short: "manage users",
+}
+
+var UserCreateCmd = &cobra.Command{
+	Use:     "create",
+	Short:   "create a user",
+	Example: "  user create --email user@example.com --username user1 --password Password-1234",
+	Args:    cobra.NoArgs,
+	RunE:    userCreateCmdF,
+}
+
+var UserSearchCmd = &cobra.Command{
+	Use:     "search [email or username prefix]",
+	Short:   "search users by email or username prefix",
+	Example: "  user search user@example.com\n  user search user",
+	Args:    cobra.ExactArgs(1),
+	RunE:    userSearchCmdF,
+}
+
+var UserDeactivateCmd = &cobra.Command{
+	Use:     "deactivate [users]",
+	Short:   "deactivate users and revoke their sessions",
+	Example: "  user deactivate user@example.com user2",
+	Args:    cobra.MinimumNArgs(1),
+	RunE:    userDeactivateCmdF,
+}
+
+var UserTypeCmd = &cobra.Command{
+	Use:     "type [user] [normal|moderator|admin]",
+	Short:   "change the type of a user",
+	Example: "  user type user@example.com moderator",
+	Args:    cobra.ExactArgs(2),
+	RunE:    userTypeCmdF,
+}
+
+// 引数のパスワードはプロセス一覧やシェルの履歴から見えるので、ファイルか標準入力から読む
+var UserResetPasswordCmd = &cobra.Command{
+	Use:     "reset-password [user]",
+	Short:   "set a new password for a user",
+	Long:    "Set a new password for a user. The password is read from --password-file, or from stdin (prompted without echo on a terminal).",
+	Example: "  user reset-password user@example.com\n  user reset-password user@example.com --password-file ./password.txt",
+	Args:    cobra.ExactArgs(1),
+	RunE:    userResetPasswordCmdF,
+}
+
+var UserSuspendCmd = &cobra.Command{
+	Use:     "suspend [users]",
+	Short:   "suspend users from posting",
+	Example: "  user suspend user@example.com --span month",
+	Args:    cobra.MinimumNArgs(1),
+	RunE:    userSuspendCmdF,
+}
+
+var suspendSpans = []string{
+	model.SUSPEND_SPAN_TYPE_WEEK,
+	model.SUSPEND_SPAN_TYPE_MONTH,
+	model.SUSPEND_SPAN_TYPE_QUARTER,
+	model.SUSPEND_SPAN_TYPE_HALF_YEAR,
+	model.SUSPEND_SPAN_TYPE_YEAR,
+}
+
+func init() {
+	UserCreateCmd.Flags().String("email", "", "email of the user.")
+	UserCreateCmd.Flags().String("username", "", "username of the user.")
+	UserCreateCmd.Flags().String("password", "", "password of the user.")
+	UserCreateCmd.Flags().String("type", model.USER_TYPE_NORMAL, "normal, moderator or admin.")
+	UserCreateCmd.MarkFlagRequired("email")
+	UserCreateCmd.MarkFlagRequired("username")
+	UserCreateCmd.MarkFlagRequired("password")
+
+	UserSearchCmd.Flags().Int("limit", 20, "maximum number of users to show.")
+
+	UserResetPasswordCmd.Flags().String("password-file", "", "file containing the new password.")
+
+	UserSuspendCmd.Flags().String("span", model.SUSPEND_SPAN_TYPE_WEEK, strings.Join(suspendSpans, ", ")+".")
+
+	UserCmd.AddCommand(
+		UserCreateCmd,
+		UserSearchCmd,
+		UserDeactivateCmd,
+		UserTypeCmd,
+		UserResetPasswordCmd,
+		UserSuspendCmd,
+	)
+	RootCmd.AddCommand(UserCmd)
+}
+
+// id, メールアドレス, ユーザー名のいずれかで指定できる
+func getUserFromArg(a *app.App, arg string) (*model.User, error) {
+	if model.IsValidId(arg) {
+		if user, err := a.Srv.Store.User().Get(arg); err == nil {
+			return user, nil
+		}
+	}
+
+	if strings.Contains(arg, "@") {
+		if user, err := a.Srv.Store.User().GetByEmail(arg); err == nil {
+			return user, nil
+		}
+	}
+
+	if user, err := a.Srv.Store.User().GetByUsername(arg); err == nil {
+		return user, nil
+	}
+
+	return nil, errors.Errorf("user %s not found", arg)
+}
+
+func isValidUserType(userType string) bool {
+	return userType == model.USER_TYPE_NORMAL || userType == model.USER_TYPE_MODERATOR || userType == model.USER_TYPE_ADMIN
+}
+
+func isValidSuspendSpan(span string) bool {
+	for _, suspendSpan := range suspendSpans {
+		if span == suspendSpan {
+			return true
+		}
+	}
+
+	return false
+}
+
+func printUser(user *model.User) {
+	user.Password = ""
+	user.AuthData = nil
+	printJson(user)
+}
+
+func userCreateCmdF(command *cobra.Command, args []string) error {
+	email, _ := command.Flags().GetString("email")
+	username, _ := command.Flags().GetString("username")
+	password, _ := command.Flags().GetString("password")
+	userType, _ := command.Flags().GetString("type")
+
+	if !isValidUserType(userType) {
+		return errors.Errorf("invalid user type %s", userType)
+	}
+
+	server, err := initServer()
+	if err != nil {
+		return err
+	}
+	defer server.Shutdown()
+
+	a := server.FakeApp()
+
+	// 管理者が作成するので、メールアドレスの確認は済んだものとする
+	user, appErr := a.CreateNormalUser(&model.User{
+		Email:         email,
+		Username:      username,
+		Password:      password,
+		EmailVerified: true,
+	})
+	if appErr != nil {
+		return errors.Wrap(appErr, "failed to create the user")
+	}
+
+	if userType != model.USER_TYPE_NORMAL {
+		if user, appErr = a.UpdateUserType(user.Id, userType); appErr != nil {
+			return errors.Wrap(appErr, "failed to change the user type")
+		}
+	}
+
+	printUser(user)
+
+	return nil
+}
+
+func userSearchCmdF(command *cobra.Command, args []string) error {
+	limit, _ := command.Flags().GetInt("limit")
+
+	server, err := initServer()
+	if err != nil {
+		return err
+	}
+	defer server.Shutdown()
+
+	a := server.FakeApp()
+
+	if strings.Contains(args[0], "@") {
+		user, err := getUserFromArg(a, args[0])
+		if err != nil {
+			return err
+		}
+
+		printUser(user)
+		return nil
+	}
+
+	users, appErr := a.Srv.Store.User().GetUsersByDates(&model.GetUsersOptions{
+		Username: model.NormalizeUsername(args[0]),
+		PerPage:  limit,
+	})
+	if appErr != nil {
+		return errors.Wrap(appErr, "failed to search users")
+	}
+
+	for _, user := range users {
+		printUser(user)
+	}
+
+	return nil
+}
+
+func userDeactivateCmdF(command *cobra.Command, args []string) error {
+	server, err := initServer()
+	if err != nil {
+		return err
+	}
+	defer server.Shutdown()
+
+	a := server.FakeApp()
+
+	for _, arg := range args {
+		user, err := getUserFromArg(a, arg)
+		if err != nil {
+			return err
+		}
+
+		if appErr := a.DeactivateUser(user.Id, ""); appErr != nil {
+			return errors.Wrapf(appErr, "failed to deactivate user %s", arg)
+		}
+
+		fmt.Println("Deactivated", user.Username)
+	}
+
+	return nil
+}
+
+func userTypeCmdF(command *cobra.Command, args []string) error {
+	userType := args[1]
+	if !isValidUserType(userType) {
+		return errors.Errorf("invalid user type %s", userType)
+	}
+
+	server, err := initServer()
+	if err != nil {
+		return err
+	}
+	defer server.Shutdown()
+
+	a := server.FakeApp()
+
+	user, err := getUserFromArg(a, args[0])
+	if err != nil {
+		return err
+	}
+
+	user, appErr := a.UpdateUserType(user.Id, userType)
+	if appErr != nil {
+		return errors.Wrap(appErr, "failed to change the user type")
+	}
+
+	printUser(user)
+
+	return nil
+}
+
+func userResetPasswordCmdF(command *cobra.Command, args []string) error {
+	server, err := initServer()
+	if err != nil {
+		return err
+	}
+	defer server.Shutdown()
+
+	a := server.FakeApp()
+
+	user, err := getUserFromArg(a, args[0])
+	if err != nil {
+		return err
+	}
+
+	passwordFile, _ := command.Flags().GetString("password-file")
+	password, err := readNewPassword(passwordFile, command.InOrStdin())
+	if err != nil {
+		return err
+	}
+
+	if appErr := a.UpdatePassword(user, password); appErr != nil {
+		return errors.Wrap(appErr, "failed to reset the password")
+	}
+
+	// 古いパスワードでログインしたままのセッションも無効にする
+	if appErr := a.RevokeAllSessions(user.Id); appErr != nil {
+		return errors.Wrap(appErr, "failed to revoke sessions")
+	}
+
+	fmt.Println("Password reset for", user.Username)
+
+	return nil
+}
+
+// 端末なら入力を表示せずに聞き、それ以外は1行目を読む
+func readNewPassword(passwordFile string, in io.Reader) (string, error) {
+	if passwordFile != "" {
+		file, err := os.Open(passwordFile)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to open the password file")
+		}
+		defer file.Close()
+		in = file
+	} else if file, ok := in.(*os.File); ok && terminal.IsTerminal(int(file.Fd())) {
+		fmt.Fprint(os.Stderr, "New password: ")
+		password, err := terminal.ReadPassword(int(file.Fd()))
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to read the password")
+		}
+		in = bytes.NewReader(password)
+	}
+
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", errors.Wrap(err, "failed to read the password")
+	}
+
+	password := strings.TrimRight(line, "\r\n")
+	if password == "" {
+		return "", errors.New("password is empty")
+	}
+
+	return password, nil
+}
+
+func userSuspendCmdF(command *cobra.Command, args []string) error {
+	span, _ := command.Flags().GetString("span")
+
+	if !isValidSuspendSpan(span) {
+		return errors.Errorf("invalid span %s", span)
+	}
+
+	server, err := initServer()
+	if err != nil {
+		return err
+	}
+	defer server.Shutdown()
+
+	a := server.FakeApp()
+
+	for _, arg := range args {
+		user, err := getUserFromArg(a, arg)
+		if err != nil {
+			return err
+		}
+
+		if appErr := a.SuspendUser(user.Id, span, ""); appErr != nil {
+			return errors.Wrapf(appErr, "failed to suspend user %s", arg)
+		}
+
+		fmt.Println("Suspended", user.Username)
+	}
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/clear-ness/qa-discussion/app"
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/clear-ness/qa-discussion/store/memstore"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupTestApp(t *testing.T) (*app.App, func()) {
+	tempDir, err := ioutil.TempDir("", "commandstest")
+	require.NoError(t, err)
+
+	server, err := app.NewServer(
+		app.Config(filepath.Join(tempDir, "config.json"), false),
+		app.StoreOverride(memstore.New()),
+		app.SkipStoreLayers(),
+		app.SkipBackgroundJobs(),
+	)
+	if err != nil {
+		os.RemoveAll(tempDir)
+		require.NoError(t, err)
+	}
+
+	return server.FakeApp(), func() {
+		server.Shutdown()
+		os.RemoveAll(tempDir)
+	}
+}
+
+func TestGetUserFromArg(t *testing.T) {
+	a, tearDown := setupTestApp(t)
+	defer tearDown()
+
+	user, err := a.Srv.Store.User().Save(&model.User{
+		Type:     model.USER_TYPE_NORMAL,
+		Username: "un" + model.NewId(),
+		Email:    "success+" + model.NewId() + "@simulator.amazonses.com",
+	})
+	require.Nil(t, err)
+
+	// 他のユーザーのusernameがidと同じ文字列でも、idでの一致を優先する
+	_, err = a.Srv.Store.User().Save(&model.User{
+		Type:     model.USER_TYPE_NORMAL,
+		Username: user.Id,
+		Email:    "success+" + model.NewId() + "@simulator.amazonses.com",
+	})
+	require.Nil(t, err)
+
+	for _, arg := range []string{user.Id, user.Email, user.Username} {
+		found, err := getUserFromArg(a, arg)
+		require.NoError(t, err, arg)
+		assert.Equal(t, user.Id, found.Id, arg)
+	}
+
+	for _, arg := range []string{model.NewId(), "missing@example.com", "missing"} {
+		_, err := getUserFromArg(a, arg)
+		assert.Error(t, err, arg)
+	}
+}
+
+func TestIsValidUserType(t *testing.T) {
+	for _, userType := range []string{model.USER_TYPE_NORMAL, model.USER_TYPE_MODERATOR, model.USER_TYPE_ADMIN} {
+		assert.True(t, isValidUserType(userType), userType)
+	}
+
+	for _, userType := range []string{"", "root", "Admin"} {
+		assert.False(t, isValidUserType(userType), userType)
+	}
+}
+
+func TestIsValidSuspendSpan(t *testing.T) {
+	for _, span := range suspendSpans {
+		assert.True(t, isValidSuspendSpan(span), span)
+	}
+
+	for _, span := range []string{"", "day", "forever", "Week"} {
+		assert.False(t, isValidSuspendSpan(span), span)
+	}
+}
+
+func TestReadNewPassword(t *testing.T) {
+	t.Run("stdin", func(t *testing.T) {
+		password, err := readNewPassword("", strings.NewReader("Password-1234\nignored\n"))
+		require.NoError(t, err)
+		assert.Equal(t, "Password-1234", password)
+	})
+
+	t.Run("password file", func(t *testing.T) {
+		file, err := ioutil.TempFile("", "password")
+		require.NoError(t, err)
+		defer os.Remove(file.Name())
+
+		_, err = file.WriteString("Password-5678\r\n")
+		require.NoError(t, err)
+		require.NoError(t, file.Close())
+
+		// ファイルを指定した場合は標準入力を読まない
+		password, err := readNewPassword(file.Name(), strings.NewReader("Password-1234\n"))
+		require.NoError(t, err)
+		assert.Equal(t, "Password-5678", password)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := readNewPassword(filepath.Join(os.TempDir(), model.NewId()), strings.NewReader("Password-1234\n"))
+		assert.Error(t, err)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		_, err := readNewPassword("", strings.NewReader("\n"))
+		assert.Error(t, err)
+
+		_, err = readNewPassword("", strings.NewReader(""))
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,23 @@
+package commands
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/clear-ness/qa-discussion/app"
+	"github.com/spf13/viper"
+)
+
+// 管理コマンドはHTTPやメトリクスを待ち受けず、定期処理も動かさずにAppの処理だけを使う
+func initServer() (*app.Server, error) {
+	return app.NewServer(
+		app.Config(viper.GetString("config"), false),
+		app.SkipBackgroundJobs(),
+	)
+}
+
+func printJson(v interface{}) {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(v)
+}
@@ -2,14 +2,21 @@ package config
 
 import (
 	"io"
+	"os"
 	"sync"
 
 	"github.com/clear-ness/qa-discussion/model"
 )
 
 type commonStore struct {
+	emitter
+
 	configLock sync.RWMutex
-	config     *model.Config
+	// 環境変数での上書きを適用済み
+	config *model.Config
+	// 保存先に書き込む、上書き前の設定
+	configWithoutOverrides *model.Config
+	environmentOverrides   map[string]interface{}
 }
 
 func (cs *commonStore) load(f io.ReadCloser, needsSave bool, validate func(*model.Config) error, persist func(*model.Config) error) error {
@@ -20,6 +27,12 @@ func (cs *commonStore) load(f io.ReadCloser, needsSave bool, validate func(*mode
 
 	loadedCfg.SetDefaults()
 
+	cfgWithoutOverrides := loadedCfg.Clone()
+	overrides, err := applyEnvironmentMap(loadedCfg, os.LookupEnv)
+	if err != nil {
+		return err
+	}
+
 	if validate != nil {
 		if err = validate(loadedCfg); err != nil {
 			return err
@@ -31,17 +44,67 @@ func (cs *commonStore) load(f io.ReadCloser, needsSave bool, validate func(*mode
 	defer unlockOnce.Do(cs.configLock.Unlock)
 
 	if needsSave && persist != nil {
-		if err = persist(loadedCfg); err != nil {
+		if err = persist(cfgWithoutOverrides); err != nil {
 			return err
 		}
 	}
 
+	oldCfg := cs.config
 	cs.config = loadedCfg
+	cs.configWithoutOverrides = cfgWithoutOverrides
+	cs.environmentOverrides = overrides
 	unlockOnce.Do(cs.configLock.Unlock)
 
+	// 初回の読み込みと、内容が変わっていない再読み込みでは通知しない
+	if oldCfg != nil && oldCfg.ToJson() != loadedCfg.ToJson() {
+		cs.invokeConfigListeners(oldCfg, loadedCfg)
+	}
+
 	return nil
 }
 
+func (cs *commonStore) set(newCfg *model.Config, validate func(*model.Config) error, persist func(*model.Config) error) (*model.Config, error) {
+	newCfg = newCfg.Clone()
+	newCfg.SetDefaults()
+
+	cs.configLock.Lock()
+	var unlockOnce sync.Once
+	defer unlockOnce.Do(cs.configLock.Unlock)
+
+	// 環境変数で上書きされている項目は、上書き前の値のまま保存する
+	restoreEnvironmentOverrides(newCfg, cs.configWithoutOverrides, cs.environmentOverrides)
+
+	appliedCfg := newCfg.Clone()
+	overrides, err := applyEnvironmentMap(appliedCfg, os.LookupEnv)
+	if err != nil {
+		return nil, err
+	}
+
+	if validate != nil {
+		if err = validate(appliedCfg); err != nil {
+			return nil, err
+		}
+	}
+
+	if persist != nil {
+		if err = persist(newCfg); err != nil {
+			return nil, err
+		}
+	}
+
+	oldCfg := cs.config
+	cs.config = appliedCfg
+	cs.configWithoutOverrides = newCfg
+	cs.environmentOverrides = overrides
+	unlockOnce.Do(cs.configLock.Unlock)
+
+	if oldCfg.ToJson() != appliedCfg.ToJson() {
+		cs.invokeConfigListeners(oldCfg, appliedCfg)
+	}
+
+	return appliedCfg, nil
+}
+
 func (cs *commonStore) Get() *model.Config {
 	cs.configLock.RLock()
 	defer cs.configLock.RUnlock()
@@ -49,6 +112,13 @@ func (cs *commonStore) Get() *model.Config {
 	return cs.config
 }
 
+func (cs *commonStore) GetEnvironmentOverrides() map[string]interface{} {
+	cs.configLock.RLock()
+	defer cs.configLock.RUnlock()
+
+	return cs.environmentOverrides
+}
+
 func (cs *commonStore) validate(cfg *model.Config) error {
 	if err := cfg.IsValid(); err != nil {
 		return err
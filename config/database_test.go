@@ -0,0 +1,280 @@
+package config
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/clear-ness/qa-discussion/model"
+)
+
+type configurationRow struct {
+	Id     string
+	Value  string
+	Active bool
+}
+
+// DatabaseStoreが発行するクエリだけを解釈する、メモリ上のConfigurationsテーブル
+type stubConfigDriver struct {
+	mutex sync.Mutex
+	rows  []configurationRow
+}
+
+var stubConfigDB = &stubConfigDriver{}
+
+func init() {
+	sql.Register("stub_config", stubConfigDB)
+}
+
+func (d *stubConfigDriver) reset() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.rows = nil
+}
+
+func (d *stubConfigDriver) snapshot() []configurationRow {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return append([]configurationRow(nil), d.rows...)
+}
+
+func (d *stubConfigDriver) Open(name string) (driver.Conn, error) {
+	return &stubConfigConn{driver: d}, nil
+}
+
+type stubConfigConn struct {
+	driver *stubConfigDriver
+	// トランザクション開始時の状態。Rollbackで戻す
+	saved []configurationRow
+}
+
+func (c *stubConfigConn) Prepare(query string) (driver.Stmt, error) {
+	return &stubConfigStmt{conn: c, query: strings.Join(strings.Fields(query), " ")}, nil
+}
+
+func (c *stubConfigConn) Close() error {
+	return nil
+}
+
+func (c *stubConfigConn) Begin() (driver.Tx, error) {
+	c.saved = c.driver.snapshot()
+	return c, nil
+}
+
+func (c *stubConfigConn) Commit() error {
+	c.saved = nil
+	return nil
+}
+
+func (c *stubConfigConn) Rollback() error {
+	if c.saved != nil {
+		c.driver.mutex.Lock()
+		c.driver.rows = c.saved
+		c.driver.mutex.Unlock()
+		c.saved = nil
+	}
+	return nil
+}
+
+type stubConfigStmt struct {
+	conn  *stubConfigConn
+	query string
+}
+
+func (s *stubConfigStmt) Close() error {
+	return nil
+}
+
+func (s *stubConfigStmt) NumInput() int {
+	return -1
+}
+
+func (s *stubConfigStmt) Exec(args []driver.Value) (driver.Result, error) {
+	d := s.conn.driver
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	switch {
+	case strings.HasPrefix(s.query, "CREATE TABLE IF NOT EXISTS Configurations"):
+	case s.query == "UPDATE Configurations SET Active = NULL WHERE Active":
+		for i := range d.rows {
+			d.rows[i].Active = false
+		}
+	case strings.HasPrefix(s.query, "INSERT INTO Configurations"):
+		for _, row := range d.rows {
+			if row.Active {
+				return nil, fmt.Errorf("duplicate active configuration")
+			}
+		}
+		d.rows = append(d.rows, configurationRow{Id: args[0].(string), Value: args[1].(string), Active: true})
+	default:
+		return nil, fmt.Errorf("unexpected exec: %s", s.query)
+	}
+
+	return driver.RowsAffected(1), nil
+}
+
+func (s *stubConfigStmt) Query(args []driver.Value) (driver.Rows, error) {
+	d := s.conn.driver
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if !strings.HasPrefix(s.query, "SELECT Value FROM Configurations WHERE Active") {
+		return nil, fmt.Errorf("unexpected query: %s", s.query)
+	}
+
+	rows := &stubConfigRows{}
+	for _, row := range d.rows {
+		if row.Active {
+			rows.values = append(rows.values, row.Value)
+		}
+	}
+
+	return rows, nil
+}
+
+type stubConfigRows struct {
+	values []string
+}
+
+func (r *stubConfigRows) Columns() []string {
+	return []string{"Value"}
+}
+
+func (r *stubConfigRows) Close() error {
+	return nil
+}
+
+func (r *stubConfigRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	dest[0] = []byte(r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+func setupStubDatabaseStore(t *testing.T) *DatabaseStore {
+	stubConfigDB.reset()
+
+	db, err := sql.Open("stub_config", "")
+	require.NoError(t, err)
+	// トランザクションの状態を接続ごとに持つので、1本に絞る
+	db.SetMaxOpenConns(1)
+
+	ds := &DatabaseStore{db: db}
+	require.NoError(t, ds.initializeConfigurationsTable())
+	require.NoError(t, ds.Load())
+
+	return ds
+}
+
+func activeConfig(t *testing.T) *model.Config {
+	for _, row := range stubConfigDB.snapshot() {
+		if row.Active {
+			cfg, err := unmarshalConfig(ioutil.NopCloser(strings.NewReader(row.Value)))
+			require.NoError(t, err)
+			return cfg
+		}
+	}
+
+	require.FailNow(t, "no active configuration")
+	return nil
+}
+
+func TestDatabaseStoreLoad(t *testing.T) {
+	t.Run("saves defaults on first load", func(t *testing.T) {
+		ds := setupStubDatabaseStore(t)
+		defer ds.Close()
+
+		rows := stubConfigDB.snapshot()
+		require.Len(t, rows, 1)
+		assert.True(t, rows[0].Active)
+
+		defaultCfg := &model.Config{}
+		defaultCfg.SetDefaults()
+		assert.Equal(t, *defaultCfg.ServiceSettings.SiteURL, *ds.Get().ServiceSettings.SiteURL)
+		assert.Equal(t, *defaultCfg.ServiceSettings.SiteURL, *activeConfig(t).ServiceSettings.SiteURL)
+	})
+
+	t.Run("reloads the active row", func(t *testing.T) {
+		ds := setupStubDatabaseStore(t)
+		defer ds.Close()
+
+		cfg := ds.Get().Clone()
+		*cfg.ServiceSettings.SiteURL = "https://other.example.com"
+		b, err := marshalConfig(cfg)
+		require.NoError(t, err)
+
+		// 他のサーバーが保存した設定を読み直す
+		stubConfigDB.mutex.Lock()
+		for i := range stubConfigDB.rows {
+			stubConfigDB.rows[i].Active = false
+		}
+		stubConfigDB.rows = append(stubConfigDB.rows, configurationRow{Id: model.NewId(), Value: string(b), Active: true})
+		stubConfigDB.mutex.Unlock()
+
+		var notified *model.Config
+		ds.AddListener(func(oldCfg, newCfg *model.Config) {
+			notified = newCfg
+		})
+
+		require.NoError(t, ds.Load())
+		assert.Equal(t, "https://other.example.com", *ds.Get().ServiceSettings.SiteURL)
+		require.NotNil(t, notified)
+		assert.Equal(t, "https://other.example.com", *notified.ServiceSettings.SiteURL)
+		assert.Len(t, stubConfigDB.snapshot(), 2)
+	})
+}
+
+func TestDatabaseStoreSet(t *testing.T) {
+	t.Run("adds a new active row", func(t *testing.T) {
+		ds := setupStubDatabaseStore(t)
+		defer ds.Close()
+
+		cfg := ds.Get().Clone()
+		*cfg.ServiceSettings.SiteURL = "https://qa.example.com"
+
+		saved, err := ds.Set(cfg)
+		require.NoError(t, err)
+		assert.Equal(t, "https://qa.example.com", *saved.ServiceSettings.SiteURL)
+		assert.Equal(t, "https://qa.example.com", *ds.Get().ServiceSettings.SiteURL)
+
+		// 以前の行は履歴として残る
+		rows := stubConfigDB.snapshot()
+		require.Len(t, rows, 2)
+		assert.False(t, rows[0].Active)
+		assert.True(t, rows[1].Active)
+		assert.Equal(t, "https://qa.example.com", *activeConfig(t).ServiceSettings.SiteURL)
+	})
+
+	t.Run("unchanged config does not add a row", func(t *testing.T) {
+		ds := setupStubDatabaseStore(t)
+		defer ds.Close()
+
+		_, err := ds.Set(ds.Get().Clone())
+		require.NoError(t, err)
+		assert.Len(t, stubConfigDB.snapshot(), 1)
+	})
+
+	t.Run("invalid config is not saved", func(t *testing.T) {
+		ds := setupStubDatabaseStore(t)
+		defer ds.Close()
+
+		cfg := ds.Get().Clone()
+		*cfg.RateLimitSettings.PerSec = 0
+
+		_, err := ds.Set(cfg)
+		require.Error(t, err)
+		assert.Len(t, stubConfigDB.snapshot(), 1)
+		assert.NotEqual(t, 0, *ds.Get().RateLimitSettings.PerSec)
+	})
+}
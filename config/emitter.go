@@ -0,0 +1,30 @@
+package config
+
+import (
+	"sync"
+
+	"github.com/clear-ness/qa-discussion/model"
+)
+
+type emitter struct {
+	listeners sync.Map
+}
+
+func (e *emitter) AddListener(listener Listener) string {
+	id := model.NewId()
+	e.listeners.Store(id, listener)
+
+	return id
+}
+
+func (e *emitter) RemoveListener(id string) {
+	e.listeners.Delete(id)
+}
+
+func (e *emitter) invokeConfigListeners(oldCfg, newCfg *model.Config) {
+	e.listeners.Range(func(key, value interface{}) bool {
+		listener := value.(Listener)
+		listener(oldCfg, newCfg)
+		return true
+	})
+}
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/clear-ness/qa-discussion/model"
+)
+
+// QA_RATELIMITSETTINGS_PERSEC のように QA_<セクション>_<項目> で上書きする
+const ENVIRONMENT_PREFIX = "QA_"
+
+func environmentKey(section, field string) string {
+	return ENVIRONMENT_PREFIX + strings.ToUpper(section) + "_" + strings.ToUpper(field)
+}
+
+// 環境変数があれば設定を上書きし、上書きした項目を返す
+func applyEnvironmentMap(cfg *model.Config, lookupEnv func(string) (string, bool)) (map[string]interface{}, error) {
+	overrides := map[string]interface{}{}
+
+	cfgValue := reflect.ValueOf(cfg).Elem()
+	cfgType := cfgValue.Type()
+	for i := 0; i < cfgType.NumField(); i++ {
+		section := cfgType.Field(i).Name
+		sectionValue := cfgValue.Field(i)
+		sectionType := sectionValue.Type()
+
+		sectionOverrides := map[string]interface{}{}
+		for j := 0; j < sectionType.NumField(); j++ {
+			field := sectionType.Field(j).Name
+
+			value, ok := lookupEnv(environmentKey(section, field))
+			if !ok {
+				continue
+			}
+
+			if err := setFieldFromString(sectionValue.Field(j), value); err != nil {
+				return nil, errors.Wrapf(err, "invalid value for %s", environmentKey(section, field))
+			}
+			sectionOverrides[field] = true
+		}
+
+		if len(sectionOverrides) > 0 {
+			overrides[section] = sectionOverrides
+		}
+	}
+
+	return overrides, nil
+}
+
+func setFieldFromString(field reflect.Value, value string) error {
+	switch field.Interface().(type) {
+	case *string:
+		field.Set(reflect.ValueOf(model.NewString(value)))
+	case *bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(model.NewBool(b)))
+	case *int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(model.NewInt(n)))
+	case *int64:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(model.NewInt64(n)))
+	case *float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(model.NewFloat64(f)))
+	case []string:
+		// 空白区切りで複数指定する
+		field.Set(reflect.ValueOf(strings.Fields(value)))
+	default:
+		return errors.Errorf("unsupported type %s", field.Type())
+	}
+
+	return nil
+}
+
+// 上書きされていた項目をoriginalの値に戻す
+func restoreEnvironmentOverrides(cfg *model.Config, original *model.Config, overrides map[string]interface{}) {
+	if original == nil {
+		return
+	}
+
+	cfgValue := reflect.ValueOf(cfg).Elem()
+	originalValue := reflect.ValueOf(original).Elem()
+	for section, fields := range overrides {
+		for field := range fields.(map[string]interface{}) {
+			value := originalValue.FieldByName(section).FieldByName(field)
+			cfgValue.FieldByName(section).FieldByName(field).Set(value)
+		}
+	}
+}
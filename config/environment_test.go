@@ -0,0 +1,151 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/clear-ness/qa-discussion/model"
+)
+
+func lookupEnvFrom(env map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	}
+}
+
+func TestApplyEnvironmentMap(t *testing.T) {
+	testCases := []struct {
+		Name   string
+		Env    map[string]string
+		Check  func(t *testing.T, cfg *model.Config)
+		Errors bool
+	}{
+		{
+			Name: "string",
+			Env:  map[string]string{"QA_SERVICESETTINGS_LISTENADDRESS": ":9000"},
+			Check: func(t *testing.T, cfg *model.Config) {
+				assert.Equal(t, ":9000", *cfg.ServiceSettings.ListenAddress)
+			},
+		},
+		{
+			Name: "bool",
+			Env:  map[string]string{"QA_SERVICESETTINGS_ENABLEDEVELOPER": "true"},
+			Check: func(t *testing.T, cfg *model.Config) {
+				assert.True(t, *cfg.ServiceSettings.EnableDeveloper)
+			},
+		},
+		{
+			Name: "int",
+			Env:  map[string]string{"QA_SERVICESETTINGS_MAXIMUMLOGINATTEMPTS": "7"},
+			Check: func(t *testing.T, cfg *model.Config) {
+				assert.Equal(t, 7, *cfg.ServiceSettings.MaximumLoginAttempts)
+			},
+		},
+		{
+			Name: "int64",
+			Env:  map[string]string{"QA_SERVICESETTINGS_USERSTATUSAWAYTIMEOUT": "600"},
+			Check: func(t *testing.T, cfg *model.Config) {
+				assert.Equal(t, int64(600), *cfg.ServiceSettings.UserStatusAwayTimeout)
+			},
+		},
+		{
+			Name: "float64",
+			Env:  map[string]string{"QA_TRACINGSETTINGS_SAMPLERATE": "0.25"},
+			Check: func(t *testing.T, cfg *model.Config) {
+				assert.Equal(t, 0.25, *cfg.TracingSettings.SampleRate)
+			},
+		},
+		{
+			Name: "string slice",
+			Env:  map[string]string{"QA_SQLSETTINGS_DATASOURCEREPLICAS": " replica1  replica2 "},
+			Check: func(t *testing.T, cfg *model.Config) {
+				assert.Equal(t, []string{"replica1", "replica2"}, cfg.SqlSettings.DataSourceReplicas)
+			},
+		},
+		{
+			Name: "empty string slice",
+			Env:  map[string]string{"QA_SQLSETTINGS_DATASOURCEREPLICAS": ""},
+			Check: func(t *testing.T, cfg *model.Config) {
+				assert.Empty(t, cfg.SqlSettings.DataSourceReplicas)
+			},
+		},
+		{
+			Name:   "invalid bool",
+			Env:    map[string]string{"QA_SERVICESETTINGS_ENABLEDEVELOPER": "yes please"},
+			Errors: true,
+		},
+		{
+			Name:   "invalid int",
+			Env:    map[string]string{"QA_SERVICESETTINGS_MAXIMUMLOGINATTEMPTS": "seven"},
+			Errors: true,
+		},
+		{
+			Name:   "invalid float64",
+			Env:    map[string]string{"QA_TRACINGSETTINGS_SAMPLERATE": "half"},
+			Errors: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			cfg := &model.Config{}
+			cfg.SetDefaults()
+
+			overrides, err := applyEnvironmentMap(cfg, lookupEnvFrom(testCase.Env))
+			if testCase.Errors {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			testCase.Check(t, cfg)
+			assert.Len(t, overrides, 1)
+		})
+	}
+
+	t.Run("overrides are reported by section and field", func(t *testing.T) {
+		cfg := &model.Config{}
+		cfg.SetDefaults()
+
+		overrides, err := applyEnvironmentMap(cfg, lookupEnvFrom(map[string]string{
+			"QA_SERVICESETTINGS_LISTENADDRESS":    ":9000",
+			"QA_SERVICESETTINGS_ENABLEDEVELOPER":  "true",
+			"QA_RATELIMITSETTINGS_PERSEC":         "20",
+			"QA_SERVICESETTINGS_UNKNOWNFIELDNAME": "ignored",
+		}))
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{
+			"ServiceSettings":   map[string]interface{}{"ListenAddress": true, "EnableDeveloper": true},
+			"RateLimitSettings": map[string]interface{}{"PerSec": true},
+		}, overrides)
+	})
+
+	t.Run("no environment", func(t *testing.T) {
+		cfg := &model.Config{}
+		cfg.SetDefaults()
+		expected := cfg.Clone()
+
+		overrides, err := applyEnvironmentMap(cfg, lookupEnvFrom(nil))
+		require.NoError(t, err)
+		assert.Empty(t, overrides)
+		assert.Equal(t, expected, cfg)
+	})
+}
+
+func TestRestoreEnvironmentOverrides(t *testing.T) {
+	original := &model.Config{}
+	original.SetDefaults()
+	*original.ServiceSettings.ListenAddress = ":8065"
+
+	cfg := original.Clone()
+	overrides, err := applyEnvironmentMap(cfg, lookupEnvFrom(map[string]string{"QA_SERVICESETTINGS_LISTENADDRESS": ":9000"}))
+	require.NoError(t, err)
+	*cfg.ServiceSettings.EnableDeveloper = true
+
+	// 上書きされた項目だけが元に戻り、他の変更は残る
+	restoreEnvironmentOverrides(cfg, original, overrides)
+	assert.Equal(t, ":8065", *cfg.ServiceSettings.ListenAddress)
+	assert.True(t, *cfg.ServiceSettings.EnableDeveloper)
+}
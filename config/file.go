@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/clear-ness/qa-discussion/mlog"
 	"github.com/clear-ness/qa-discussion/model"
 	"github.com/clear-ness/qa-discussion/utils/fileutils"
 	"github.com/pkg/errors"
@@ -44,36 +45,83 @@ func resolveConfigFilePath(path string) (string, error) {
 type FileStore struct {
 	commonStore
 
-	path string
+	path    string
+	watch   bool
+	watcher *watcher
 }
 
-func NewFileStore(path string) (fs *FileStore, err error) {
+// watchがtrueの場合、ファイルが書き換えられたら読み直す
+func NewFileStore(path string, watch bool) (fs *FileStore, err error) {
 	resolvedPath, err := resolveConfigFilePath(path)
 	if err != nil {
 		return nil, err
 	}
 
 	fs = &FileStore{
-		path: resolvedPath,
+		path:  resolvedPath,
+		watch: watch,
 	}
 	if err = fs.Load(); err != nil {
 		return nil, err
 	}
 
+	if fs.watch {
+		if err = fs.startWatcher(); err != nil {
+			mlog.Error("Failed to start config watcher", mlog.String("path", fs.path), mlog.Err(err))
+		}
+	}
+
 	return fs, nil
 }
 
+func (fs *FileStore) startWatcher() error {
+	watcher, err := newWatcher(fs.path, func() {
+		// 書きかけのファイルや不正な設定の場合は、今の設定のまま動かし続ける
+		if err := fs.Load(); err != nil {
+			mlog.Error("Failed to reload config on change", mlog.String("path", fs.path), mlog.Err(err))
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	fs.watcher = watcher
+
+	return nil
+}
+
+func (fs *FileStore) Set(newCfg *model.Config) (*model.Config, error) {
+	return fs.commonStore.set(newCfg, fs.commonStore.validate, fs.persist)
+}
+
+func (fs *FileStore) String() string {
+	return "file://" + fs.path
+}
+
+func (fs *FileStore) Close() error {
+	if fs.watcher != nil {
+		return fs.watcher.Close()
+	}
+
+	return nil
+}
+
 func (fs *FileStore) persist(cfg *model.Config) error {
 	b, err := marshalConfig(cfg)
 	if err != nil {
 		return err
 	}
 
-	err = ioutil.WriteFile(fs.path, b, 0600)
-	if err != nil {
+	// 監視中に書きかけのファイルを読まないよう、一時ファイルに書いてから置き換える
+	tmpPath := fs.path + ".tmp"
+	if err = ioutil.WriteFile(tmpPath, b, 0600); err != nil {
 		return err
 	}
 
+	if err = os.Rename(tmpPath, fs.path); err != nil {
+		return errors.Wrap(err, "failed to replace config file")
+	}
+
 	return nil
 }
 
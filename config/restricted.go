@@ -0,0 +1,53 @@
+package config
+
+import (
+	"reflect"
+
+	"github.com/clear-ness/qa-discussion/model"
+)
+
+// セクションまたは項目に restricted:"true" が付いたものは管理APIから読み書きさせない
+func isRestricted(field reflect.StructField) bool {
+	return field.Tag.Get("restricted") == "true"
+}
+
+// restricted の項目を空にしたコピーを返す
+func RemoveRestricted(cfg *model.Config) *model.Config {
+	ret := cfg.Clone()
+
+	walkRestricted(reflect.ValueOf(ret).Elem(), func(index []int, field reflect.Value) {
+		field.Set(reflect.Zero(field.Type()))
+	})
+
+	return ret
+}
+
+// restricted の項目をcurrentの値で置き換えたコピーを返す
+func KeepRestricted(cfg *model.Config, current *model.Config) *model.Config {
+	ret := cfg.Clone()
+	currentValue := reflect.ValueOf(current.Clone()).Elem()
+
+	walkRestricted(reflect.ValueOf(ret).Elem(), func(index []int, field reflect.Value) {
+		field.Set(currentValue.FieldByIndex(index))
+	})
+
+	return ret
+}
+
+func walkRestricted(cfgValue reflect.Value, fn func(index []int, field reflect.Value)) {
+	cfgType := cfgValue.Type()
+	for i := 0; i < cfgType.NumField(); i++ {
+		sectionValue := cfgValue.Field(i)
+		if isRestricted(cfgType.Field(i)) {
+			fn([]int{i}, sectionValue)
+			continue
+		}
+
+		sectionType := sectionValue.Type()
+		for j := 0; j < sectionType.NumField(); j++ {
+			if isRestricted(sectionType.Field(j)) {
+				fn([]int{i, j}, sectionValue.Field(j))
+			}
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/clear-ness/qa-discussion/model"
+)
+
+func newRestrictedTestConfig() *model.Config {
+	cfg := &model.Config{}
+	cfg.SetDefaults()
+	*cfg.SqlSettings.DataSource = "user:secret@tcp(db:3306)/qa"
+	cfg.SqlSettings.DataSourceReplicas = []string{"user:secret@tcp(replica:3306)/qa"}
+	*cfg.FileSettings.AmazonS3SecretAccessKey = "s3-secret"
+	*cfg.ServiceSettings.WebserverMode = "gzip"
+	*cfg.ServiceSettings.SiteURL = "https://qa.example.com"
+	return cfg
+}
+
+func TestRemoveRestricted(t *testing.T) {
+	cfg := newRestrictedTestConfig()
+
+	removed := RemoveRestricted(cfg)
+
+	// セクションごと、または項目ごとに空になる
+	assert.Equal(t, model.SqlSettings{}, removed.SqlSettings)
+	assert.Nil(t, removed.FileSettings.AmazonS3SecretAccessKey)
+	assert.Nil(t, removed.ServiceSettings.WebserverMode)
+	assert.Nil(t, removed.RateLimitSettings.PerSec)
+
+	// restrictedでない項目は残る
+	assert.Equal(t, "https://qa.example.com", *removed.ServiceSettings.SiteURL)
+	assert.Equal(t, *cfg.FileSettings.MaxFileSize, *removed.FileSettings.MaxFileSize)
+
+	// 元の設定は変更しない
+	assert.Equal(t, "user:secret@tcp(db:3306)/qa", *cfg.SqlSettings.DataSource)
+	assert.Equal(t, "s3-secret", *cfg.FileSettings.AmazonS3SecretAccessKey)
+}
+
+func TestKeepRestricted(t *testing.T) {
+	current := newRestrictedTestConfig()
+
+	t.Run("round trip keeps restricted values", func(t *testing.T) {
+		// 管理APIで取得した設定を編集して送り返す
+		edited := RemoveRestricted(current)
+		*edited.ServiceSettings.SiteURL = "https://new.example.com"
+
+		saved := KeepRestricted(edited, current)
+		assert.Equal(t, current.SqlSettings, saved.SqlSettings)
+		assert.Equal(t, "s3-secret", *saved.FileSettings.AmazonS3SecretAccessKey)
+		assert.Equal(t, "gzip", *saved.ServiceSettings.WebserverMode)
+		assert.Equal(t, "https://new.example.com", *saved.ServiceSettings.SiteURL)
+	})
+
+	t.Run("restricted values cannot be changed", func(t *testing.T) {
+		edited := current.Clone()
+		*edited.SqlSettings.DataSource = "attacker:pw@tcp(evil:3306)/qa"
+		*edited.FileSettings.AmazonS3SecretAccessKey = "changed"
+
+		saved := KeepRestricted(edited, current)
+		assert.Equal(t, "user:secret@tcp(db:3306)/qa", *saved.SqlSettings.DataSource)
+		assert.Equal(t, "s3-secret", *saved.FileSettings.AmazonS3SecretAccessKey)
+	})
+
+	t.Run("does not share values with current", func(t *testing.T) {
+		saved := KeepRestricted(RemoveRestricted(current), current)
+		require.NotNil(t, saved.SqlSettings.DataSource)
+
+		*saved.SqlSettings.DataSource = "changed"
+		saved.SqlSettings.DataSourceReplicas[0] = "changed"
+		assert.Equal(t, "user:secret@tcp(db:3306)/qa", *current.SqlSettings.DataSource)
+		assert.Equal(t, "user:secret@tcp(replica:3306)/qa", current.SqlSettings.DataSourceReplicas[0])
+	})
+}
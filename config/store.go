@@ -4,6 +4,36 @@ import (
 	"github.com/clear-ness/qa-discussion/model"
 )
 
+// 設定が変わった時に呼ばれる。旧設定と新設定を受け取る
+type Listener func(oldConfig *model.Config, newConfig *model.Config)
+
 type Store interface {
+	// 環境変数での上書きを適用済みの設定を返す
 	Get() *model.Config
+
+	// 環境変数で上書きされている項目を {"Section": {"Field": true}} の形で返す
+	GetEnvironmentOverrides() map[string]interface{}
+
+	// 設定を保存して反映する。環境変数で上書きされている項目は保存しない
+	Set(*model.Config) (*model.Config, error)
+
+	// 保存先から読み直す
+	Load() error
+
+	AddListener(listener Listener) string
+	RemoveListener(id string)
+
+	// ログ出力用の保存先
+	String() string
+
+	Close() error
+}
+
+// dsnがDBの接続文字列ならDBに、それ以外はファイルに設定を保存する
+func NewStore(dsn string, watch bool) (Store, error) {
+	if IsDatabaseDSN(dsn) {
+		return NewDatabaseStore(dsn)
+	}
+
+	return NewFileStore(dsn, watch)
 }
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/clear-ness/qa-discussion/model"
+)
+
+// "RateLimitSettings.PerSec" のようなキーに対応する項目を返す。セクション名だけならセクション全体を返す
+func lookupValue(cfg *model.Config, key string) (reflect.Value, error) {
+	parts := strings.Split(key, ".")
+	if len(parts) > 2 {
+		return reflect.Value{}, errors.Errorf("invalid key %s", key)
+	}
+
+	value := reflect.ValueOf(cfg).Elem()
+	for _, part := range parts {
+		value = value.FieldByName(part)
+		if !value.IsValid() {
+			return reflect.Value{}, errors.Errorf("unknown key %s", key)
+		}
+	}
+
+	return value, nil
+}
+
+func GetValue(cfg *model.Config, key string) (interface{}, error) {
+	value, err := lookupValue(cfg, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return value.Interface(), nil
+}
+
+// 環境変数と同じ書式の文字列を、項目の型に変換して設定する
+func SetValue(cfg *model.Config, key string, value string) error {
+	if len(strings.Split(key, ".")) != 2 {
+		return errors.Errorf("invalid key %s, expected Section.Field", key)
+	}
+
+	field, err := lookupValue(cfg, key)
+	if err != nil {
+		return err
+	}
+
+	return setFieldFromString(field, value)
+}
@@ -0,0 +1,26 @@
+//go:generate go run migration_generator/main.go
+
+// スキーマのマイグレーションのSQLをバイナリに埋め込み、別途gooseを用意しなくても
+// サーバーのバイナリからマイグレーションできるようにする。
+// migrations、migrations_postgres のSQLを追加・変更したら、db ディレクトリで go generate を実行して
+// migrations_generated.go を更新する。
+package db
+
+import (
+	"github.com/clear-ness/qa-discussion/model"
+)
+
+type MigrationFile struct {
+	// 20200106075451_create_users.sql のように、先頭がバージョン
+	Name    string
+	Content string
+}
+
+// ドライバに対応するマイグレーションをファイル名の順に返す
+func Migrations(driverName string) []MigrationFile {
+	if driverName == model.DATABASE_DRIVER_POSTGRES {
+		return postgresMigrations
+	}
+
+	return mysqlMigrations
+}
@@ -0,0 +1,500 @@
+package db
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/clear-ness/qa-discussion/mlog"
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/pkg/errors"
+
+	// sql.Openで使うドライバを登録する
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+)
+
+const (
+	// これまでgooseで適用してきたDBをそのまま引き継げるよう、gooseと同じテーブルに記録する
+	MIGRATION_VERSION_TABLE = "goose_db_version"
+
+	// 複数台のサーバーが同時に起動してもマイグレーションは1台ずつ行う
+	MIGRATION_LOCK_NAME            = "qa_discussion_migrations"
+	MIGRATION_LOCK_KEY             = 7359082346102913
+	MIGRATION_LOCK_TIMEOUT_SECONDS = 300
+)
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      []string
+	Down    []string
+	// -- +goose NO TRANSACTION が指定されていればトランザクションの外で実行する
+	NoTransaction bool
+}
+
+type MigrationStatus struct {
+	Version   int64  `json:"version"`
+	Name      string `json:"name"`
+	Applied   bool   `json:"applied"`
+	AppliedAt string `json:"applied_at,omitempty"`
+}
+
+type Migrator struct {
+	db         *sql.DB
+	driverName string
+	migrations []*Migration
+}
+
+// QueryTimeoutで長いマイグレーションが打ち切られないよう、storeとは別にデータソースへ接続する
+func NewMigrator(settings *model.SqlSettings) (*Migrator, error) {
+	migrations, err := ParseMigrations(Migrations(*settings.DriverName))
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open(*settings.DriverName, *settings.DataSource)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open database")
+	}
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, errors.Wrap(err, "failed to ping database")
+	}
+
+	return &Migrator{
+		db:         db,
+		driverName: *settings.DriverName,
+		migrations: migrations,
+	}, nil
+}
+
+func (m *Migrator) Close() error {
+	return m.db.Close()
+}
+
+// 未適用のマイグレーションを全て古い順に適用し、適用したものを返す
+func (m *Migrator) Up() ([]*Migration, error) {
+	var applied []*Migration
+
+	err := m.withLock(func() error {
+		versions, err := m.appliedVersions()
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := versions[migration.Version]; ok {
+				continue
+			}
+
+			if err := m.apply(migration, true); err != nil {
+				return err
+			}
+			applied = append(applied, migration)
+		}
+
+		return nil
+	})
+
+	return applied, err
+}
+
+// 最後に適用したマイグレーションを1つ戻し、戻したものを返す。適用済みが無ければnil
+func (m *Migrator) Down() (*Migration, error) {
+	var rolledBack *Migration
+
+	err := m.withLock(func() error {
+		migration, err := m.latestApplied()
+		if err != nil || migration == nil {
+			return err
+		}
+
+		if err := m.apply(migration, false); err != nil {
+			return err
+		}
+		rolledBack = migration
+
+		return nil
+	})
+
+	return rolledBack, err
+}
+
+// 最後に適用したマイグレーションを戻してから適用し直す
+func (m *Migrator) Redo() (*Migration, error) {
+	var redone *Migration
+
+	err := m.withLock(func() error {
+		migration, err := m.latestApplied()
+		if err != nil || migration == nil {
+			return err
+		}
+
+		if err := m.apply(migration, false); err != nil {
+			return err
+		}
+		if err := m.apply(migration, true); err != nil {
+			return err
+		}
+		redone = migration
+
+		return nil
+	})
+
+	return redone, err
+}
+
+// 適用済みのマイグレーションを全て新しい順に戻し、戻したものを返す
+func (m *Migrator) Reset() ([]*Migration, error) {
+	var rolledBack []*Migration
+
+	err := m.withLock(func() error {
+		for {
+			migration, err := m.latestApplied()
+			if err != nil || migration == nil {
+				return err
+			}
+
+			if err := m.apply(migration, false); err != nil {
+				return err
+			}
+			rolledBack = append(rolledBack, migration)
+		}
+	})
+
+	return rolledBack, err
+}
+
+func (m *Migrator) Status() ([]*MigrationStatus, error) {
+	if err := m.ensureVersionTable(); err != nil {
+		return nil, err
+	}
+
+	versions, err := m.appliedVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]*MigrationStatus, len(m.migrations))
+	for i, migration := range m.migrations {
+		appliedAt, applied := versions[migration.Version]
+		statuses[i] = &MigrationStatus{
+			Version:   migration.Version,
+			Name:      migration.Name,
+			Applied:   applied,
+			AppliedAt: appliedAt,
+		}
+	}
+
+	return statuses, nil
+}
+
+func (m *Migrator) apply(migration *Migration, up bool) error {
+	statements, direction := migration.Up, "up"
+	if !up {
+		statements, direction = migration.Down, "down"
+	}
+
+	run := func(exec func(query string, args ...interface{}) (sql.Result, error)) error {
+		for _, statement := range statements {
+			if _, err := exec(statement); err != nil {
+				return errors.Wrapf(err, "failed to migrate %s %s", direction, migration.Name)
+			}
+		}
+
+		return m.recordVersion(exec, migration.Version, up)
+	}
+
+	if migration.NoTransaction {
+		if err := run(m.db.Exec); err != nil {
+			return err
+		}
+	} else {
+		// mysqlのDDLは暗黙にコミットされるので、トランザクションが効くのはpostgresだけ
+		transaction, err := m.db.Begin()
+		if err != nil {
+			return errors.Wrap(err, "failed to begin transaction")
+		}
+
+		if err := run(transaction.Exec); err != nil {
+			transaction.Rollback()
+			return err
+		}
+
+		if err := transaction.Commit(); err != nil {
+			return errors.Wrapf(err, "failed to commit %s %s", direction, migration.Name)
+		}
+	}
+
+	mlog.Info("Migrated", mlog.String("direction", direction), mlog.String("migration", migration.Name))
+
+	return nil
+}
+
+// gooseと同じく、適用したら行を追加し、戻したら行を消す
+func (m *Migrator) recordVersion(exec func(query string, args ...interface{}) (sql.Result, error), version int64, up bool) error {
+	var err error
+	if up {
+		_, err = exec(m.rebind("INSERT INTO "+MIGRATION_VERSION_TABLE+" (version_id, is_applied) VALUES (?, ?)"), version, true)
+	} else {
+		_, err = exec(m.rebind("DELETE FROM "+MIGRATION_VERSION_TABLE+" WHERE version_id = ?"), version)
+	}
+
+	return errors.Wrapf(err, "failed to record version %d", version)
+}
+
+// 適用済みのバージョンと適用日時を返す。
+// 古いgooseは戻した時にis_applied = falseの行を追加するので、バージョンごとに最新の行で判断する
+func (m *Migrator) appliedVersions() (map[int64]string, error) {
+	rows, err := m.db.Query("SELECT version_id, is_applied, tstamp FROM " + MIGRATION_VERSION_TABLE + " ORDER BY id DESC")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query migration versions")
+	}
+	defer rows.Close()
+
+	seen := map[int64]bool{}
+	versions := map[int64]string{}
+	for rows.Next() {
+		var version int64
+		var applied bool
+		var tstamp sql.NullString
+		if err := rows.Scan(&version, &applied, &tstamp); err != nil {
+			return nil, errors.Wrap(err, "failed to scan migration version")
+		}
+
+		if seen[version] {
+			continue
+		}
+		seen[version] = true
+
+		// 0はgooseがテーブルを作った時に入れる行
+		if applied && version != 0 {
+			versions[version] = tstamp.String
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed while iterating over migration versions")
+	}
+
+	return versions, nil
+}
+
+func (m *Migrator) latestApplied() (*Migration, error) {
+	versions, err := m.appliedVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	var latest int64
+	for version := range versions {
+		if version > latest {
+			latest = version
+		}
+	}
+
+	if latest == 0 {
+		return nil, nil
+	}
+
+	for _, migration := range m.migrations {
+		if migration.Version == latest {
+			return migration, nil
+		}
+	}
+
+	return nil, errors.Errorf("migration %d is applied but not found in this binary", latest)
+}
+
+func (m *Migrator) ensureVersionTable() error {
+	if _, err := m.db.Exec("CREATE TABLE IF NOT EXISTS " + MIGRATION_VERSION_TABLE + " (id serial NOT NULL, version_id bigint NOT NULL, is_applied boolean NOT NULL, tstamp timestamp NULL default now(), PRIMARY KEY(id))"); err != nil {
+		return errors.Wrap(err, "failed to create migration version table")
+	}
+
+	var count int64
+	if err := m.db.QueryRow("SELECT COUNT(*) FROM " + MIGRATION_VERSION_TABLE).Scan(&count); err != nil {
+		return errors.Wrap(err, "failed to count migration versions")
+	}
+
+	if count == 0 {
+		if _, err := m.db.Exec(m.rebind("INSERT INTO "+MIGRATION_VERSION_TABLE+" (version_id, is_applied) VALUES (?, ?)"), 0, true); err != nil {
+			return errors.Wrap(err, "failed to initialize migration versions")
+		}
+	}
+
+	return nil
+}
+
+// DBのadvisory lockを取ってからfを実行する。
+// ロックは接続に紐づくので、取得から解放まで同じ接続を使う
+func (m *Migrator) withLock(f func() error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), MIGRATION_LOCK_TIMEOUT_SECONDS*time.Second)
+	defer cancel()
+
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get connection for migration lock")
+	}
+	defer conn.Close()
+
+	if m.driverName == model.DATABASE_DRIVER_POSTGRES {
+		if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", MIGRATION_LOCK_KEY); err != nil {
+			return errors.Wrap(err, "failed to acquire migration lock")
+		}
+		defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", MIGRATION_LOCK_KEY)
+	} else {
+		var locked sql.NullInt64
+		if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", MIGRATION_LOCK_NAME, MIGRATION_LOCK_TIMEOUT_SECONDS).Scan(&locked); err != nil {
+			return errors.Wrap(err, "failed to acquire migration lock")
+		}
+		if locked.Int64 != 1 {
+			return errors.New("timed out waiting for migration lock")
+		}
+		defer conn.ExecContext(context.Background(), "SELECT RELEASE_LOCK(?)", MIGRATION_LOCK_NAME)
+	}
+
+	if err := m.ensureVersionTable(); err != nil {
+		return err
+	}
+
+	return f()
+}
+
+func (m *Migrator) rebind(query string) string {
+	if m.driverName != model.DATABASE_DRIVER_POSTGRES {
+		return query
+	}
+
+	var builder strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			builder.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		builder.WriteRune(r)
+	}
+
+	return builder.String()
+}
+
+// gooseの書式のSQLファイルをバージョンの順に解析する
+func ParseMigrations(files []MigrationFile) ([]*Migration, error) {
+	migrations := make([]*Migration, 0, len(files))
+	versions := map[int64]string{}
+
+	for _, file := range files {
+		migration, err := parseMigration(file)
+		if err != nil {
+			return nil, err
+		}
+
+		if other, ok := versions[migration.Version]; ok {
+			return nil, errors.Errorf("duplicate migration version %d: %s, %s", migration.Version, other, file.Name)
+		}
+		versions[migration.Version] = file.Name
+
+		migrations = append(migrations, migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// -- +goose Up と -- +goose Down で区切られた部分を、行末の;で文に分ける。
+// -- +goose StatementBegin から StatementEnd までは;を含んでも1つの文として扱う
+func parseMigration(file MigrationFile) (*Migration, error) {
+	separator := strings.Index(file.Name, "_")
+	if separator <= 0 {
+		return nil, errors.Errorf("migration file name must start with a version: %s", file.Name)
+	}
+
+	version, err := strconv.ParseInt(file.Name[:separator], 10, 64)
+	if err != nil {
+		return nil, errors.Wrapf(err, "migration file name must start with a version: %s", file.Name)
+	}
+
+	migration := &Migration{Version: version, Name: file.Name}
+
+	var statements *[]string
+	var buf strings.Builder
+	inStatement := false
+
+	flush := func() {
+		if statement := strings.TrimSpace(buf.String()); statement != "" {
+			*statements = append(*statements, statement)
+		}
+		buf.Reset()
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(file.Content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "-- +goose ") {
+			switch strings.TrimSpace(strings.TrimPrefix(trimmed, "-- +goose ")) {
+			case "Up":
+				statements = &migration.Up
+			case "Down":
+				statements = &migration.Down
+			case "StatementBegin":
+				inStatement = true
+			case "StatementEnd":
+				if statements != nil {
+					flush()
+				}
+				inStatement = false
+			case "NO TRANSACTION":
+				migration.NoTransaction = true
+			}
+			continue
+		}
+
+		if statements == nil || (!inStatement && (trimmed == "" || strings.HasPrefix(trimmed, "--"))) {
+			continue
+		}
+
+		buf.WriteString(line)
+		buf.WriteString("\n")
+
+		if !inStatement && strings.HasSuffix(trimmed, ";") {
+			flush()
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrapf(err, "failed to read %s", file.Name)
+	}
+
+	if inStatement || strings.TrimSpace(buf.String()) != "" {
+		return nil, errors.Errorf("unterminated statement in %s", file.Name)
+	}
+
+	if migration.Up == nil {
+		return nil, errors.Errorf("no -- +goose Up section in %s", file.Name)
+	}
+
+	return migration, nil
+}
+
+func (s *MigrationStatus) String() string {
+	state := "Pending"
+	if s.Applied {
+		state = "Applied at " + s.AppliedAt
+	}
+
+	return fmt.Sprintf("%-40s %s", s.Name, state)
+}
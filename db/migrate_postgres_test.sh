@@ -0,0 +1,3 @@
+#!/bin/bash
+
+goose -dir ./db/migrations_postgres postgres "postgres://postgres:@localhost:5432/qa_discussion_test?sslmode=disable" $1
@@ -0,0 +1,89 @@
+package db
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMigration(t *testing.T) {
+	migration, err := parseMigration(MigrationFile{
+		Name: "20201030100000_add_function.sql",
+		Content: `-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE Foo (
+  Id varchar(26) NOT NULL
+);
+ALTER TABLE Foo ADD COLUMN Bar int;
+
+-- +goose StatementBegin
+CREATE FUNCTION foo() RETURNS int AS $$
+BEGIN
+  RETURN 1;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP FUNCTION foo;
+DROP TABLE Foo;
+`,
+	})
+	require.Nil(t, err)
+
+	assert.Equal(t, int64(20201030100000), migration.Version)
+	require.Len(t, migration.Up, 3)
+	assert.Equal(t, "CREATE TABLE Foo (\n  Id varchar(26) NOT NULL\n);", migration.Up[0])
+	assert.Equal(t, "ALTER TABLE Foo ADD COLUMN Bar int;", migration.Up[1])
+	assert.Contains(t, migration.Up[2], "RETURN 1;\nEND;\n$$ LANGUAGE plpgsql;")
+	assert.Equal(t, []string{"DROP FUNCTION foo;", "DROP TABLE Foo;"}, migration.Down)
+	assert.False(t, migration.NoTransaction)
+
+	_, err = parseMigration(MigrationFile{Name: "create_foo.sql", Content: "-- +goose Up\nSELECT 1;\n"})
+	assert.NotNil(t, err)
+
+	_, err = parseMigration(MigrationFile{Name: "20201030100000_foo.sql", Content: "-- +goose Up\nSELECT 1\n"})
+	assert.NotNil(t, err)
+}
+
+// go generateのし忘れを検出する
+func TestEmbeddedMigrationsAreUpToDate(t *testing.T) {
+	for driverName, dir := range map[string]string{
+		model.DATABASE_DRIVER_MYSQL:    "migrations",
+		model.DATABASE_DRIVER_POSTGRES: "migrations_postgres",
+	} {
+		paths, err := filepath.Glob(filepath.Join(dir, "*.sql"))
+		require.Nil(t, err)
+
+		embedded := Migrations(driverName)
+		require.Len(t, embedded, len(paths), "run go generate in the db directory")
+
+		for i, path := range paths {
+			content, err := ioutil.ReadFile(path)
+			require.Nil(t, err)
+
+			assert.Equal(t, filepath.Base(path), embedded[i].Name)
+			assert.Equal(t, string(content), embedded[i].Content, "run go generate in the db directory")
+		}
+
+		migrations, err := ParseMigrations(embedded)
+		require.Nil(t, err)
+		require.Len(t, migrations, len(paths))
+		for _, migration := range migrations {
+			assert.NotEmpty(t, migration.Up, migration.Name)
+			assert.NotEmpty(t, migration.Down, migration.Name)
+		}
+	}
+}
+
+func TestRebind(t *testing.T) {
+	m := &Migrator{driverName: model.DATABASE_DRIVER_POSTGRES}
+	assert.Equal(t, "INSERT INTO t (a, b) VALUES ($1, $2)", m.rebind("INSERT INTO t (a, b) VALUES (?, ?)"))
+
+	m = &Migrator{driverName: model.DATABASE_DRIVER_MYSQL}
+	assert.Equal(t, "INSERT INTO t (a, b) VALUES (?, ?)", m.rebind("INSERT INTO t (a, b) VALUES (?, ?)"))
+}
@@ -0,0 +1,79 @@
+// db/migrations と db/migrations_postgres のSQLを埋め込んだ db/migrations_generated.go を生成する。
+// db ディレクトリで go generate を実行すると更新される。
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	mysqlDir    = flag.String("mysql", "migrations", "directory containing mysql migrations")
+	postgresDir = flag.String("postgres", "migrations_postgres", "directory containing postgres migrations")
+	outputFile  = flag.String("out", "migrations_generated.go", "output file")
+)
+
+func main() {
+	flag.Parse()
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by \"go run migration_generator/main.go\"; DO NOT EDIT.\n\n")
+	buf.WriteString("package db\n\n")
+
+	for _, source := range []struct {
+		variable string
+		dir      string
+	}{
+		{"mysqlMigrations", *mysqlDir},
+		{"postgresMigrations", *postgresDir},
+	} {
+		if err := writeMigrations(&buf, source.variable, source.dir); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(*outputFile, formatted, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func writeMigrations(buf *bytes.Buffer, variable string, dir string) error {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	names := []string{}
+	for _, file := range files {
+		if !file.IsDir() && strings.HasSuffix(file.Name(), ".sql") {
+			names = append(names, file.Name())
+		}
+	}
+	sort.Strings(names)
+
+	fmt.Fprintf(buf, "var %s = []MigrationFile{\n", variable)
+	for _, name := range names {
+		content, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(buf, "{Name: %s, Content: %s},\n", strconv.Quote(name), strconv.Quote(string(content)))
+	}
+	buf.WriteString("}\n\n")
+
+	return nil
+}
@@ -0,0 +1,11 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+ALTER TABLE `Users` ADD COLUMN `AuthService` varchar(32) NOT NULL DEFAULT '' AFTER `FailedAttempts`;
+ALTER TABLE `Users` ADD COLUMN `AuthData` varchar(128) DEFAULT NULL AFTER `AuthService`;
+ALTER TABLE `Users` ADD UNIQUE KEY `AuthData` (`AuthData`);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+ALTER TABLE `Users` DROP INDEX `AuthData`;
+ALTER TABLE `Users` DROP COLUMN `AuthData`;
+ALTER TABLE `Users` DROP COLUMN `AuthService`;
@@ -0,0 +1,20 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE `Jobs` (
+  `Id` varchar(26) NOT NULL,
+  `Type` varchar(32) DEFAULT NULL,
+  `TeamId` varchar(26) DEFAULT NULL,
+  `UserId` varchar(26) DEFAULT NULL,
+  `Status` varchar(32) DEFAULT NULL,
+  `Progress` bigint(20) DEFAULT NULL,
+  `CreateAt` bigint(20) DEFAULT NULL,
+  `StartAt` bigint(20) DEFAULT NULL,
+  `LastActivityAt` bigint(20) DEFAULT NULL,
+  `Data` text,
+  PRIMARY KEY (`Id`),
+  KEY `idx_jobs_team_id_type_status` (`TeamId`, `Type`, `Status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS `Jobs`;
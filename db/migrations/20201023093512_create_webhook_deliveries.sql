@@ -0,0 +1,36 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE `WebhookDeliveries` (
+  `Id` varchar(26) NOT NULL,
+  `WebhookId` varchar(26) NOT NULL,
+  `TeamId` varchar(26) DEFAULT NULL,
+  `PostId` varchar(26) DEFAULT NULL,
+  `URL` text,
+  `ContentType` varchar(128) DEFAULT NULL,
+  `RequestBody` text,
+  `Status` varchar(32) DEFAULT NULL,
+  `Attempts` int(11) NOT NULL DEFAULT 0,
+  `NextAttemptAt` bigint(20) DEFAULT NULL,
+  `CreateAt` bigint(20) DEFAULT NULL,
+  `UpdateAt` bigint(20) DEFAULT NULL,
+  PRIMARY KEY (`Id`),
+  KEY `idx_webhook_deliveries_status_next_attempt_at` (`Status`, `NextAttemptAt`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+ALTER TABLE `Webhooks` ADD COLUMN `FailureCount` int(11) NOT NULL DEFAULT 0 AFTER `DeleteAt`;
+ALTER TABLE `Webhooks` ADD COLUMN `DisabledAt` bigint(20) NOT NULL DEFAULT 0 AFTER `FailureCount`;
+
+ALTER TABLE `WebhooksHistory` ADD COLUMN `DeliveryId` varchar(26) DEFAULT NULL AFTER `ResponseStatus`;
+ALTER TABLE `WebhooksHistory` ADD COLUMN `Attempt` int(11) NOT NULL DEFAULT 0 AFTER `DeliveryId`;
+ALTER TABLE `WebhooksHistory` ADD COLUMN `Latency` bigint(20) NOT NULL DEFAULT 0 AFTER `Attempt`;
+ALTER TABLE `WebhooksHistory` ADD COLUMN `Error` text AFTER `Latency`;
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+ALTER TABLE `WebhooksHistory` DROP COLUMN `Error`;
+ALTER TABLE `WebhooksHistory` DROP COLUMN `Latency`;
+ALTER TABLE `WebhooksHistory` DROP COLUMN `Attempt`;
+ALTER TABLE `WebhooksHistory` DROP COLUMN `DeliveryId`;
+ALTER TABLE `Webhooks` DROP COLUMN `DisabledAt`;
+ALTER TABLE `Webhooks` DROP COLUMN `FailureCount`;
+DROP TABLE IF EXISTS `WebhookDeliveries`;
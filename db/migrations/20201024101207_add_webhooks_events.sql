@@ -0,0 +1,22 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+ALTER TABLE `Webhooks` ADD COLUMN `Events` text AFTER `TeamId`;
+UPDATE `Webhooks` SET `Events` = CONCAT('[', CONCAT_WS(',',
+  IF(`QuestionEvents`, '"question_created"', NULL),
+  IF(`AnswerEvents`, '"answer_created"', NULL),
+  IF(`CommentEvents`, '"comment_created"', NULL)
+), ']');
+ALTER TABLE `Webhooks` DROP COLUMN `QuestionEvents`;
+ALTER TABLE `Webhooks` DROP COLUMN `AnswerEvents`;
+ALTER TABLE `Webhooks` DROP COLUMN `CommentEvents`;
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+ALTER TABLE `Webhooks` ADD COLUMN `QuestionEvents` tinyint(1) DEFAULT NULL AFTER `TeamId`;
+ALTER TABLE `Webhooks` ADD COLUMN `AnswerEvents` tinyint(1) DEFAULT NULL AFTER `QuestionEvents`;
+ALTER TABLE `Webhooks` ADD COLUMN `CommentEvents` tinyint(1) DEFAULT NULL AFTER `AnswerEvents`;
+UPDATE `Webhooks` SET
+  `QuestionEvents` = `Events` LIKE '%"question_created"%',
+  `AnswerEvents` = `Events` LIKE '%"answer_created"%',
+  `CommentEvents` = `Events` LIKE '%"comment_created"%';
+ALTER TABLE `Webhooks` DROP COLUMN `Events`;
@@ -0,0 +1,15 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+ALTER TABLE `WebhooksHistory` ADD COLUMN `RequestHeaders` text AFTER `ResponseStatus`;
+ALTER TABLE `WebhooksHistory` ADD COLUMN `ResponseHeaders` text AFTER `RequestHeaders`;
+ALTER TABLE `WebhooksHistory` ADD KEY `idx_webhooks_history_team_id_create_at` (`TeamId`, `CreateAt`);
+ALTER TABLE `WebhooksHistory` ADD KEY `idx_webhooks_history_webhook_id` (`WebhookId`);
+ALTER TABLE `WebhooksHistory` ADD KEY `idx_webhooks_history_create_at` (`CreateAt`);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+ALTER TABLE `WebhooksHistory` DROP INDEX `idx_webhooks_history_create_at`;
+ALTER TABLE `WebhooksHistory` DROP INDEX `idx_webhooks_history_webhook_id`;
+ALTER TABLE `WebhooksHistory` DROP INDEX `idx_webhooks_history_team_id_create_at`;
+ALTER TABLE `WebhooksHistory` DROP COLUMN `ResponseHeaders`;
+ALTER TABLE `WebhooksHistory` DROP COLUMN `RequestHeaders`;
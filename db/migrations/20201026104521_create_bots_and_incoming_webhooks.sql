@@ -0,0 +1,37 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE `Bots` (
+  `UserId` varchar(26) NOT NULL,
+  `TeamId` varchar(26) NOT NULL,
+  `DisplayName` varchar(256) DEFAULT NULL,
+  `Description` text,
+  `OwnerId` varchar(26) DEFAULT NULL,
+  `CreateAt` bigint(20) DEFAULT NULL,
+  `UpdateAt` bigint(20) DEFAULT NULL,
+  `DeleteAt` bigint(20) NOT NULL DEFAULT 0,
+  PRIMARY KEY (`UserId`),
+  KEY `idx_bots_team_id_delete_at` (`TeamId`, `DeleteAt`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE `IncomingWebhooks` (
+  `Id` varchar(26) NOT NULL,
+  `Token` varchar(26) NOT NULL,
+  `TeamId` varchar(26) NOT NULL,
+  `GroupId` varchar(26) DEFAULT NULL,
+  `BotUserId` varchar(26) NOT NULL,
+  `CreatorId` varchar(26) DEFAULT NULL,
+  `Name` varchar(64) DEFAULT NULL,
+  `Description` varchar(255) DEFAULT NULL,
+  `CreateAt` bigint(20) DEFAULT NULL,
+  `UpdateAt` bigint(20) DEFAULT NULL,
+  `DeleteAt` bigint(20) NOT NULL DEFAULT 0,
+  PRIMARY KEY (`Id`),
+  UNIQUE KEY `idx_incoming_webhooks_token` (`Token`),
+  KEY `idx_incoming_webhooks_team_id_delete_at` (`TeamId`, `DeleteAt`),
+  KEY `idx_incoming_webhooks_bot_user_id` (`BotUserId`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS `IncomingWebhooks`;
+DROP TABLE IF EXISTS `Bots`;
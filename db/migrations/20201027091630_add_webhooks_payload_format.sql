@@ -0,0 +1,9 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+ALTER TABLE `Webhooks` ADD COLUMN `PayloadFormat` varchar(32) NOT NULL DEFAULT 'native' AFTER `ContentType`;
+ALTER TABLE `Webhooks` ADD COLUMN `PayloadTemplate` text AFTER `PayloadFormat`;
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+ALTER TABLE `Webhooks` DROP COLUMN `PayloadTemplate`;
+ALTER TABLE `Webhooks` DROP COLUMN `PayloadFormat`;
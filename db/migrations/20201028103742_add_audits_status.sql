@@ -0,0 +1,17 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+ALTER TABLE `Audits`
+  ADD COLUMN `Status` varchar(32) DEFAULT NULL,
+  ADD COLUMN `ApiPath` varchar(255) DEFAULT NULL,
+  ADD COLUMN `Client` text,
+  ADD KEY `idx_audits_create_at` (`CreateAt`),
+  ADD KEY `idx_audits_ip_address` (`IpAddress`);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+ALTER TABLE `Audits`
+  DROP KEY `idx_audits_ip_address`,
+  DROP KEY `idx_audits_create_at`,
+  DROP COLUMN `Client`,
+  DROP COLUMN `ApiPath`,
+  DROP COLUMN `Status`;
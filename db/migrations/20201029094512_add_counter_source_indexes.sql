@@ -0,0 +1,11 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+ALTER TABLE `Votes` ADD KEY `idx_votes_post_id_type` (`PostId`, `Type`);
+ALTER TABLE `UserPointHistory` ADD KEY `idx_user_point_history_user_id_team_id` (`UserId`, `TeamId`);
+ALTER TABLE `PostViewsHistory` ADD KEY `idx_post_views_history_post_id` (`PostId`);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+ALTER TABLE `PostViewsHistory` DROP INDEX `idx_post_views_history_post_id`;
+ALTER TABLE `UserPointHistory` DROP INDEX `idx_user_point_history_user_id_team_id`;
+ALTER TABLE `Votes` DROP INDEX `idx_votes_post_id_type`;
@@ -0,0 +1,87 @@
+// Code generated by "go run migration_generator/main.go"; DO NOT EDIT.
+
+package db
+
+var mysqlMigrations = []MigrationFile{
+	{Name: "20200106075451_create_users.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE `Users` (\n  `Id` varchar(26) NOT NULL,\n  `Type` varchar(26) DEFAULT NULL,\n  `CreateAt` bigint(20) DEFAULT NULL,\n  `UpdateAt` bigint(20) DEFAULT NULL,\n  `DeleteAt` bigint(20) DEFAULT NULL,\n  `SuspendTime` bigint(20) DEFAULT NULL,\n  `Username` varchar(64) DEFAULT NULL,\n  `Password` varchar(128) DEFAULT NULL,\n  `Props` text,\n  `Email` varchar(128) DEFAULT NULL,\n  `EmailVerified` tinyint(1) DEFAULT NULL,\n  `Points` int(11) DEFAULT NULL,\n  `LastInboxMessageViewed` bigint(20) DEFAULT NULL,\n  `LastPictureUpdate` bigint(20) DEFAULT NULL,\n  `FailedAttempts` int(11) DEFAULT NULL,\n  PRIMARY KEY (`Id`),\n  UNIQUE KEY `Email` (`Email`),\n  KEY `idx_users_email` (`Email`),\n  KEY `idx_users_update_at` (`UpdateAt`),\n  KEY `idx_users_create_at` (`CreateAt`),\n  KEY `idx_users_delete_at` (`DeleteAt`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS `Users`;\n"},
+	{Name: "20200107040845_create_sessions.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE `Sessions` (\n  `Id` varchar(26) NOT NULL,\n  `Token` varchar(26) DEFAULT NULL,\n  `CreateAt` bigint(20) DEFAULT NULL,\n  `ExpiresAt` bigint(20) DEFAULT NULL,\n  `UserId` varchar(26) DEFAULT NULL,\n  `Props` text,\n  `IsOAuth` tinyint(1) DEFAULT NULL,\n  PRIMARY KEY (`Id`),\n  KEY `idx_sessions_user_id` (`UserId`),\n  KEY `idx_sessions_token` (`Token`),\n  KEY `idx_sessions_expires_at` (`ExpiresAt`),\n  KEY `idx_sessions_create_at` (`CreateAt`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS `Sessions`;\n"},
+	{Name: "20200107041130_create_tokens.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE `Tokens` (\n  `Token` varchar(64) NOT NULL,\n  `CreateAt` bigint(20) DEFAULT NULL,\n  `Type` varchar(64) DEFAULT NULL,\n  `Extra` varchar(128) DEFAULT NULL,\n  PRIMARY KEY (`Token`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS `Tokens`;\n"},
+	{Name: "20200107220016_create_posts.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\n-- TODO: indexを全て再考慮\nCREATE TABLE `Posts` (\n  `Id` varchar(26) NOT NULL,\n  `Type` varchar(26) DEFAULT NULL,\n  `ParentId` varchar(26) DEFAULT NULL,\n  `RootId` varchar(26) DEFAULT NULL,\n  `OriginalId` varchar(26) DEFAULT NULL,\n  `BestId` varchar(26) DEFAULT NULL,\n  `UserId` varchar(26) DEFAULT NULL,\n  `TeamId` varchar(26) DEFAULT NULL,\n  `Title` text,\n  `Content` text,\n  `Tags` text,\n  `Props` text,\n  `UpVotes` int(11) DEFAULT NULL,\n  `DownVotes` int(11) DEFAULT NULL,\n  `Points` int(11) DEFAULT NULL,\n  `AnswerCount` int(11) DEFAULT NULL,\n  `FlagCount` int(11) DEFAULT NULL,\n  `Views` int(11) DEFAULT NULL,\n  `ProtectedAt` bigint(20) DEFAULT NULL,\n  `LockedAt` bigint(20) DEFAULT NULL,\n  `CreateAt` bigint(20) DEFAULT NULL,\n  `UpdateAt` bigint(20) DEFAULT NULL,\n  `EditAt` bigint(20) DEFAULT NULL,\n  `DeleteAt` bigint(20) DEFAULT NULL,\n  PRIMARY KEY (`Id`),\n  KEY `idx_posts_type_delete_at_create_at` (`Type`,`DeleteAt`,`CreateAt`),\n  KEY `idx_posts_type_delete_at_update_at` (`Type`,`DeleteAt`,`UpdateAt`),\n  KEY `idx_posts_type_delete_at_points` (`Type`,`DeleteAt`,`Points`),\n  KEY `idx_posts_type_delete_at_answer_count` (`Type`,`DeleteAt`,`AnswerCount`),\n  KEY `idx_posts_type_up_votes_delete_at_create_at` (`Type`,`UpVotes`,`DeleteAt`,`CreateAt`),\n  KEY `idx_posts_parent_id_type_delete_at_create_at` (`ParentId`,`Type`,`DeleteAt`,`CreateAt`),\n  KEY `idx_posts_parent_id_type_delete_at_update_at` (`ParentId`,`Type`,`DeleteAt`,`UpdateAt`),\n  KEY `idx_posts_parent_id_type_delete_at_points` (`ParentId`,`Type`,`DeleteAt`,`Points`),\n  KEY `idx_posts_root_id_type_delete_at_create_at` (`RootId`,`Type`,`DeleteAt`,`CreateAt`),\n  KEY `idx_posts_user_id_type_delete_at_create_at` (`UserId`,`Type`,`DeleteAt`,`CreateAt`),\n  FULLTEXT KEY `idx_posts_title_txt` (`Title`),\n  FULLTEXT KEY `idx_posts_content_txt` (`Content`),\n  FULLTEXT KEY `idx_posts_tags_txt` (`Tags`),\n  FULLTEXT KEY `idx_posts_title_tags_txt` (`Title`,`Tags`),\n  FULLTEXT KEY `idx_posts_title_tags_content_txt` (`Title`,`Tags`,`Content`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS `Posts`;\n"},
+	{Name: "20200108064409_create_tags.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE `Tags` (\n  `Content` varchar(64) NOT NULL,\n  `TeamId` varchar(26) NOT NULL,\n  `Type` varchar(26) NOT NULL,\n  `PostCount` int(11) DEFAULT NULL,\n  `CreateAt` bigint(20) DEFAULT NULL,\n  `UpdateAt` bigint(20) DEFAULT NULL,\n  PRIMARY KEY (`Content`,`TeamId`,`Type`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS `Tags`;\n"},
+	{Name: "20200109030004_create_systems.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE `Systems` (\n  `Name` varchar(64) NOT NULL,\n  `Value` text,\n  PRIMARY KEY (`Name`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS `Systems`;\n"},
+	{Name: "20200209095220_create_votes.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\n-- TODO: チームに紐づくテーブルを見るにはそのteamのメンバーである事が必要。\n-- すでに脱退済みなら見れない、他も同様。\n-- 一度脱退し、再度joinすると以前と同じ様に見れる仕様。\nCREATE TABLE `Votes` (\n  `PostId` varchar(26) NOT NULL,\n  `UserId` varchar(26) NOT NULL,\n  `Type` varchar(26) NOT NULL,\n  `Tags` text,\n  `TeamId` varchar(26) DEFAULT NULL,\n  `FirstPostRev` int(11) DEFAULT NULL,\n  `LastPostRev` int(11) DEFAULT NULL,\n  `CreateAt` bigint(20) DEFAULT NULL,\n  `InvalidateAt` bigint(20) DEFAULT NULL,\n  `CompletedAt` bigint(20) DEFAULT NULL,\n  `CompletedBy` varchar(26) DEFAULT NULL,\n  `RejectedAt` bigint(20) DEFAULT NULL,\n  `RejectedBy` varchar(26) DEFAULT NULL,\n  PRIMARY KEY (`UserId`,`Type`,`PostId`),\n  KEY `idx_votes_user_id_type_create_at` (`UserId`,`Type`,`CreateAt`),\n  FULLTEXT KEY `idx_votes_tags_txt` (`Tags`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS `Votes`;\n"},
+	{Name: "20200209105331_create_user_point_history.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE `UserPointHistory` (\n  `Id` varchar(26) NOT NULL,\n  `UserId` varchar(26) NOT NULL,\n  `TeamId` varchar(26) DEFAULT NULL,\n  `Type` varchar(26) DEFAULT NULL,\n  `PostId` varchar(26) DEFAULT NULL,\n  `PostType` varchar(26) DEFAULT NULL,\n  `Tags` text,\n  `Points` int(11) DEFAULT NULL,\n  `CreateAt` bigint(20) DEFAULT NULL,\n  PRIMARY KEY (`Id`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS `UserPointHistory`;\n"},
+	{Name: "20200222211637_create_inbox_messages.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE `InboxMessages` (\n  `Id` varchar(26) NOT NULL,\n  `Type` varchar(26) DEFAULT NULL,\n  `Content` text,\n  `UserId` varchar(26) DEFAULT NULL,\n  `SenderId` varchar(26) DEFAULT NULL,\n  `QuestionId` varchar(26) NOT NULL,\n  `Title` text,\n  `AnswerId` varchar(26) NOT NULL,\n  `CommentId` varchar(26) NOT NULL,\n  `TeamId` varchar(26) DEFAULT NULL,\n  `CreateAt` bigint(20) DEFAULT NULL,\n  PRIMARY KEY (`Id`),\n  KEY `idx_inbox_messages_user_id_create_at` (`UserId`,`CreateAt`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS `InboxMessages`;\n"},
+	{Name: "20200225202508_create_user_favorite_posts.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE `UserFavoritePosts` (\n  `PostId` varchar(26) NOT NULL,\n  `UserId` varchar(26) NOT NULL,\n  `TeamId` varchar(26) DEFAULT NULL,\n  `CreateAt` bigint(20) DEFAULT NULL,\n  PRIMARY KEY (`PostId`,`UserId`),\n  KEY `idx_user_favorite_posts_user_id_create_at` (`UserId`,`CreateAt`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS `UserFavoritePosts`;\n"},
+	{Name: "20200305235455_create_file_info.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE `FileInfo` (\n  `Id` varchar(26) NOT NULL,\n  `UserId` varchar(26) DEFAULT NULL,\n  `PostId` varchar(26) DEFAULT NULL,\n  `CreateAt` bigint(20) DEFAULT NULL,\n  `DeleteAt` bigint(20) DEFAULT NULL,\n  `Path` text,\n  `ThumbnailPath` text,\n  `Name` text,\n  `Extension` varchar(64) DEFAULT NULL,\n  `Size` bigint(20) DEFAULT NULL,\n  `MimeType` text,\n  `Width` int(11) DEFAULT NULL,\n  `Height` int(11) DEFAULT NULL,\n  PRIMARY KEY (`Id`),\n  KEY `idx_file_info_post_id_delete_at_create_at` (`PostId`, `DeleteAt`, `CreateAt`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS `FileInfo`;\n"},
+	{Name: "20200313035307_create_notification_settings.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE `NotificationSettings` (\n  `Id` varchar(26) NOT NULL,\n  `UserId` varchar(26) NOT NULL,\n  `InboxInterval` varchar(26) DEFAULT NULL,\n  `CreateAt` bigint(20) DEFAULT NULL,\n  `UpdateAt` bigint(20) DEFAULT NULL,\n  PRIMARY KEY (`Id`),\n  KEY `idx_notification_settings_user_id_inbox_interval` (`UserId`, `InboxInterval`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS `NotificationSettings`;\n"},
+	{Name: "20200804105638_create_teams.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE `Teams` (\n  `Id` varchar(26) NOT NULL,\n  `Type` varchar(26) DEFAULT NULL,\n  `Name` varchar(64) DEFAULT NULL,\n  `Description` varchar(255) DEFAULT NULL,\n  `Email` varchar(128) DEFAULT NULL,\n  `AllowedDomains` text,\n  `InviteId` varchar(32) DEFAULT NULL,\n  `LastPictureUpdate` bigint(20) DEFAULT NULL,\n  `CreateAt` bigint(20) DEFAULT NULL,\n  `UpdateAt` bigint(20) DEFAULT NULL,\n  `DeleteAt` bigint(20) DEFAULT NULL,\n  PRIMARY KEY (`Id`),\n  UNIQUE KEY `Name` (`Name`),\n  KEY `idx_teams_name` (`Name`),\n  KEY `idx_teams_invite_id` (`InviteId`),\n  KEY `idx_teams_update_at` (`UpdateAt`),\n  KEY `idx_teams_create_at` (`CreateAt`),\n  KEY `idx_teams_delete_at` (`DeleteAt`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS `Teams`;\n"},
+	{Name: "20200804130532_create_team_members.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE `TeamMembers` (\n  `TeamId` varchar(26) NOT NULL,\n  `UserId` varchar(26) NOT NULL,\n  `Type` varchar(26) DEFAULT NULL,\n  `Points` int(11) DEFAULT NULL,\n  `DeleteAt` bigint(20) DEFAULT NULL,\n  PRIMARY KEY (`TeamId`,`UserId`),\n  KEY `idx_teammembers_team_id` (`TeamId`),\n  KEY `idx_teammembers_user_id` (`UserId`),\n  KEY `idx_teammembers_delete_at` (`DeleteAt`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS `TeamMembers`;\n"},
+	{Name: "20200804131158_create_team_member_history.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE `TeamMemberHistory` (\n  `TeamId` varchar(26) NOT NULL,\n  `UserId` varchar(26) NOT NULL,\n  `JoinTime` bigint(20) NOT NULL,\n  `LeaveTime` bigint(20) DEFAULT NULL,\n  PRIMARY KEY (`TeamId`,`UserId`,`JoinTime`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS `TeamMemberHistory`;\n"},
+	{Name: "20200806102102_create_user_groups.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE `UserGroups` (\n  `Id` varchar(26) NOT NULL,\n  `Type` varchar(26) DEFAULT NULL,\n  `CreateAt` bigint(20) DEFAULT NULL,\n  `UpdateAt` bigint(20) DEFAULT NULL,\n  `DeleteAt` bigint(20) DEFAULT NULL,\n  `TeamId` varchar(26) DEFAULT NULL,\n  `Name` varchar(64) DEFAULT NULL,\n  `Description` varchar(255) DEFAULT NULL,\n  `UserId` varchar(26) DEFAULT NULL,\n  PRIMARY KEY (`Id`),\n  UNIQUE KEY `Name` (`Name`,`TeamId`),\n  KEY `idx_user_groups_team_id` (`TeamId`),\n  KEY `idx_user_groups_update_at` (`UpdateAt`),\n  KEY `idx_user_groups_create_at` (`CreateAt`),\n  KEY `idx_user_groups_delete_at` (`DeleteAt`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS `UserGroups`;\n"},
+	{Name: "20200806102238_create_group_members.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE `GroupMembers` (\n  `GroupId` varchar(26) NOT NULL,\n  `UserId` varchar(26) NOT NULL,\n  `Type` varchar(26) DEFAULT NULL,\n  PRIMARY KEY (`GroupId`,`UserId`),\n  KEY `idx_groupmembers_group_id` (`GroupId`),\n  KEY `idx_groupmembers_user_id` (`UserId`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS `GroupMembers`;\n"},
+	{Name: "20200806113738_create_group_member_history.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE `GroupMemberHistory` (\n  `GroupId` varchar(26) NOT NULL,\n  `UserId` varchar(26) NOT NULL,\n  `JoinTime` bigint(20) NOT NULL,\n  `LeaveTime` bigint(20) DEFAULT NULL,\n  PRIMARY KEY (`GroupId`,`UserId`,`JoinTime`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS `GroupMemberHistory`;\n"},
+	{Name: "20200809053237_create_collections.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE `Collections` (\n  `Id` varchar(26) NOT NULL,\n  `CreateAt` bigint(20) DEFAULT NULL,\n  `UpdateAt` bigint(20) DEFAULT NULL,\n  `DeleteAt` bigint(20) DEFAULT NULL,\n  `TeamId` varchar(26) DEFAULT NULL,\n  `Title` text,\n  `Description` varchar(255) DEFAULT NULL,\n  `UserId` varchar(26) DEFAULT NULL,\n  PRIMARY KEY (`Id`),\n  KEY `idx_collections_team_id` (`TeamId`),\n  KEY `idx_collections_create_at` (`CreateAt`),\n  KEY `idx_collections_update_at` (`UpdateAt`),\n  KEY `idx_collections_delete_at` (`DeleteAt`),\n  FULLTEXT KEY `idx_collections_title_txt` (`Title`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS `Collections`;\n"},
+	{Name: "20200809053321_create_collection_posts.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE `CollectionPosts` (\n  `CollectionId` varchar(26) NOT NULL,\n  `PostId` varchar(26) NOT NULL,\n  PRIMARY KEY (`CollectionId`,`PostId`),\n  KEY `idx_collectionposts_collection_id` (`CollectionId`),\n  KEY `idx_collectionposts_post_id` (`PostId`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS `CollectionPosts`;\n"},
+	{Name: "20200810113250_create_audits.sql", Content: "-- +goose Up\n-- TODO: TeamIdを用意、\n-- teamに所属している限りはteam関連のログが本人も見れるが、\n-- 脱退すれば本人は見れなくなり、team adminなら見れる。\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE `Audits` (\n  `Id` varchar(26) NOT NULL,\n  `CreateAt` bigint(20) DEFAULT NULL,\n  `UserId` varchar(26) DEFAULT NULL,\n  `Action` text,\n  `ExtraInfo` text,\n  `IpAddress` varchar(64) DEFAULT NULL,\n  `SessionId` varchar(26) DEFAULT NULL,\n  PRIMARY KEY (`Id`),\n  KEY `idx_audits_user_id` (`UserId`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS `Audits`;\n"},
+	{Name: "20200819204843_create_post_views_history.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE `PostViewsHistory` (\n  `Id` varchar(26) NOT NULL,\n  `PostId` varchar(26) NOT NULL,\n  `TeamId` varchar(26) DEFAULT NULL,\n  `UserId` varchar(26) DEFAULT NULL,\n  `IpAddress` varchar(64) DEFAULT NULL,\n  `ViewsCount` int(11) DEFAULT NULL,\n  `CreateAt` bigint(20) DEFAULT NULL,\n  PRIMARY KEY (`Id`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS `PostViewsHistory`;\n"},
+	{Name: "20200826043049_create_webhooks.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE `Webhooks` (\n  `Id` varchar(26) NOT NULL,\n  `Token` varchar(26) DEFAULT NULL,\n  `UserId` varchar(26) DEFAULT NULL,\n  `TeamId` varchar(26) DEFAULT NULL,\n  `QuestionEvents` tinyint(1) DEFAULT NULL,\n  `AnswerEvents` tinyint(1) DEFAULT NULL,\n  `CommentEvents` tinyint(1) DEFAULT NULL,\n  `URLs` text,\n  `Name` varchar(64) DEFAULT NULL,\n  `Description` varchar(255) DEFAULT NULL,\n  `ContentType` varchar(128) DEFAULT NULL,\n  `CreateAt` bigint(20) DEFAULT NULL,\n  `UpdateAt` bigint(20) DEFAULT NULL,\n  `DeleteAt` bigint(20) DEFAULT NULL,\n  PRIMARY KEY (`Id`),\n  KEY `idx_webhook_team_id` (`TeamId`),\n  KEY `idx_webhook_create_at` (`CreateAt`),\n  KEY `idx_webhook_update_at` (`UpdateAt`),\n  KEY `idx_webhook_delete_at` (`DeleteAt`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS `Webhooks`;\n"},
+	{Name: "20200826043508_create_webhooks_history.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE `WebhooksHistory` (\n  `Id` varchar(26) NOT NULL,\n  `WebhookId` varchar(26) NOT NULL,\n  `PostId` varchar(26) DEFAULT NULL,\n  `TeamId` varchar(26) DEFAULT NULL,\n  `WebhookName` varchar(64) DEFAULT NULL,\n  `URL` text,\n  `ContentType` varchar(128) DEFAULT NULL,\n  `RequestBody` text,\n  `ResponseBody` text,\n  `ResponseStatus` int(11) DEFAULT NULL,\n  `CreateAt` bigint(20) DEFAULT NULL,\n  PRIMARY KEY (`Id`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS `WebhooksHistory`;\n"},
+	{Name: "20200827123548_create_oauth_access_data.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE `OAuthAccessData` (\n  `ClientId` varchar(26) DEFAULT NULL,\n  `UserId` varchar(26) DEFAULT NULL,\n  `Token` varchar(26) NOT NULL,\n  `RefreshToken` varchar(26) DEFAULT NULL,\n  `RedirectUri` text,\n  `ExpiresAt` bigint(20) DEFAULT NULL,\n  `Scope` varchar(128) DEFAULT NULL,\n  PRIMARY KEY (`Token`),\n  UNIQUE KEY `ClientId` (`ClientId`,`UserId`),\n  KEY `idx_oauthaccessdata_client_id` (`ClientId`),\n  KEY `idx_oauthaccessdata_user_id` (`UserId`),\n  KEY `idx_oauthaccessdata_refresh_token` (`RefreshToken`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS `OAuthAccessData`;\n"},
+	{Name: "20200827123619_create_oauth_apps.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE `OAuthApps` (\n  `Id` varchar(26) NOT NULL,\n  `UserId` varchar(26) DEFAULT NULL,\n  `CreateAt` bigint(20) DEFAULT NULL,\n  `UpdateAt` bigint(20) DEFAULT NULL,\n  `ClientSecret` varchar(128) DEFAULT NULL,\n  `Name` varchar(64) DEFAULT NULL,\n  `Description` text,\n  `IconURL` text,\n  `URLs` text,\n  `Homepage` text,\n  PRIMARY KEY (`Id`),\n  KEY `idx_oauthapps_user_id` (`UserId`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS `OAuthApps`;\n"},
+	{Name: "20200827124332_create_oauth_auth_data.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE `OAuthAuthData` (\n  `ClientId` varchar(26) DEFAULT NULL,\n  `UserId` varchar(26) DEFAULT NULL,\n  `Code` varchar(128) NOT NULL,\n  `ExpiresIn` int(11) DEFAULT NULL,\n  `CreateAt` bigint(20) DEFAULT NULL,\n  `RedirectUri` text,\n  `State` text,\n  `Scope` varchar(128) DEFAULT NULL,\n  PRIMARY KEY (`Code`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS `OAuthAuthData`;\n"},
+	{Name: "20200827183650_create_oauth_authorized_apps.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE `OAuthAuthorizedApps` (\n  `UserId` varchar(26) NOT NULL,\n  `ClientId` varchar(26) NOT NULL,\n  `Scope` varchar(128) DEFAULT NULL,\n  PRIMARY KEY (`UserId`,`ClientId`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS `OAuthAuthorizedApps`;\n"},
+	{Name: "20200909190327_create_status.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE `Status` (\n  `UserId` varchar(26) NOT NULL,\n  `Status` varchar(32) DEFAULT NULL,\n  `Manual` tinyint(1) DEFAULT NULL,\n  `LastActivityAt` bigint(20) DEFAULT NULL,\n  PRIMARY KEY (`UserId`),\n  KEY `idx_status_user_id` (`UserId`),\n  KEY `idx_status_status` (`Status`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS `Status`;\n"},
+	{Name: "20201020093012_add_sessions_last_activity_at.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nALTER TABLE `Sessions` ADD COLUMN `LastActivityAt` bigint(20) DEFAULT NULL AFTER `ExpiresAt`;\nUPDATE `Sessions` SET `LastActivityAt` = `CreateAt`;\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nALTER TABLE `Sessions` DROP COLUMN `LastActivityAt`;\n"},
+	{Name: "20201021101544_add_users_auth.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nALTER TABLE `Users` ADD COLUMN `AuthService` varchar(32) NOT NULL DEFAULT '' AFTER `FailedAttempts`;\nALTER TABLE `Users` ADD COLUMN `AuthData` varchar(128) DEFAULT NULL AFTER `AuthService`;\nALTER TABLE `Users` ADD UNIQUE KEY `AuthData` (`AuthData`);\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nALTER TABLE `Users` DROP INDEX `AuthData`;\nALTER TABLE `Users` DROP COLUMN `AuthData`;\nALTER TABLE `Users` DROP COLUMN `AuthService`;\n"},
+	{Name: "20201022140311_create_jobs.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE `Jobs` (\n  `Id` varchar(26) NOT NULL,\n  `Type` varchar(32) DEFAULT NULL,\n  `TeamId` varchar(26) DEFAULT NULL,\n  `UserId` varchar(26) DEFAULT NULL,\n  `Status` varchar(32) DEFAULT NULL,\n  `Progress` bigint(20) DEFAULT NULL,\n  `CreateAt` bigint(20) DEFAULT NULL,\n  `StartAt` bigint(20) DEFAULT NULL,\n  `LastActivityAt` bigint(20) DEFAULT NULL,\n  `Data` text,\n  PRIMARY KEY (`Id`),\n  KEY `idx_jobs_team_id_type_status` (`TeamId`, `Type`, `Status`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS `Jobs`;\n"},
+	{Name: "20201023093512_create_webhook_deliveries.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE `WebhookDeliveries` (\n  `Id` varchar(26) NOT NULL,\n  `WebhookId` varchar(26) NOT NULL,\n  `TeamId` varchar(26) DEFAULT NULL,\n  `PostId` varchar(26) DEFAULT NULL,\n  `URL` text,\n  `ContentType` varchar(128) DEFAULT NULL,\n  `RequestBody` text,\n  `Status` varchar(32) DEFAULT NULL,\n  `Attempts` int(11) NOT NULL DEFAULT 0,\n  `NextAttemptAt` bigint(20) DEFAULT NULL,\n  `CreateAt` bigint(20) DEFAULT NULL,\n  `UpdateAt` bigint(20) DEFAULT NULL,\n  PRIMARY KEY (`Id`),\n  KEY `idx_webhook_deliveries_status_next_attempt_at` (`Status`, `NextAttemptAt`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;\n\nALTER TABLE `Webhooks` ADD COLUMN `FailureCount` int(11) NOT NULL DEFAULT 0 AFTER `DeleteAt`;\nALTER TABLE `Webhooks` ADD COLUMN `DisabledAt` bigint(20) NOT NULL DEFAULT 0 AFTER `FailureCount`;\n\nALTER TABLE `WebhooksHistory` ADD COLUMN `DeliveryId` varchar(26) DEFAULT NULL AFTER `ResponseStatus`;\nALTER TABLE `WebhooksHistory` ADD COLUMN `Attempt` int(11) NOT NULL DEFAULT 0 AFTER `DeliveryId`;\nALTER TABLE `WebhooksHistory` ADD COLUMN `Latency` bigint(20) NOT NULL DEFAULT 0 AFTER `Attempt`;\nALTER TABLE `WebhooksHistory` ADD COLUMN `Error` text AFTER `Latency`;\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nALTER TABLE `WebhooksHistory` DROP COLUMN `Error`;\nALTER TABLE `WebhooksHistory` DROP COLUMN `Latency`;\nALTER TABLE `WebhooksHistory` DROP COLUMN `Attempt`;\nALTER TABLE `WebhooksHistory` DROP COLUMN `DeliveryId`;\nALTER TABLE `Webhooks` DROP COLUMN `DisabledAt`;\nALTER TABLE `Webhooks` DROP COLUMN `FailureCount`;\nDROP TABLE IF EXISTS `WebhookDeliveries`;\n"},
+	{Name: "20201024101207_add_webhooks_events.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nALTER TABLE `Webhooks` ADD COLUMN `Events` text AFTER `TeamId`;\nUPDATE `Webhooks` SET `Events` = CONCAT('[', CONCAT_WS(',',\n  IF(`QuestionEvents`, '\"question_created\"', NULL),\n  IF(`AnswerEvents`, '\"answer_created\"', NULL),\n  IF(`CommentEvents`, '\"comment_created\"', NULL)\n), ']');\nALTER TABLE `Webhooks` DROP COLUMN `QuestionEvents`;\nALTER TABLE `Webhooks` DROP COLUMN `AnswerEvents`;\nALTER TABLE `Webhooks` DROP COLUMN `CommentEvents`;\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nALTER TABLE `Webhooks` ADD COLUMN `QuestionEvents` tinyint(1) DEFAULT NULL AFTER `TeamId`;\nALTER TABLE `Webhooks` ADD COLUMN `AnswerEvents` tinyint(1) DEFAULT NULL AFTER `QuestionEvents`;\nALTER TABLE `Webhooks` ADD COLUMN `CommentEvents` tinyint(1) DEFAULT NULL AFTER `AnswerEvents`;\nUPDATE `Webhooks` SET\n  `QuestionEvents` = `Events` LIKE '%\"question_created\"%',\n  `AnswerEvents` = `Events` LIKE '%\"answer_created\"%',\n  `CommentEvents` = `Events` LIKE '%\"comment_created\"%';\nALTER TABLE `Webhooks` DROP COLUMN `Events`;\n"},
+	{Name: "20201025093844_add_webhooks_history_headers.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nALTER TABLE `WebhooksHistory` ADD COLUMN `RequestHeaders` text AFTER `ResponseStatus`;\nALTER TABLE `WebhooksHistory` ADD COLUMN `ResponseHeaders` text AFTER `RequestHeaders`;\nALTER TABLE `WebhooksHistory` ADD KEY `idx_webhooks_history_team_id_create_at` (`TeamId`, `CreateAt`);\nALTER TABLE `WebhooksHistory` ADD KEY `idx_webhooks_history_webhook_id` (`WebhookId`);\nALTER TABLE `WebhooksHistory` ADD KEY `idx_webhooks_history_create_at` (`CreateAt`);\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nALTER TABLE `WebhooksHistory` DROP INDEX `idx_webhooks_history_create_at`;\nALTER TABLE `WebhooksHistory` DROP INDEX `idx_webhooks_history_webhook_id`;\nALTER TABLE `WebhooksHistory` DROP INDEX `idx_webhooks_history_team_id_create_at`;\nALTER TABLE `WebhooksHistory` DROP COLUMN `ResponseHeaders`;\nALTER TABLE `WebhooksHistory` DROP COLUMN `RequestHeaders`;\n"},
+	{Name: "20201026104521_create_bots_and_incoming_webhooks.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE `Bots` (\n  `UserId` varchar(26) NOT NULL,\n  `TeamId` varchar(26) NOT NULL,\n  `DisplayName` varchar(256) DEFAULT NULL,\n  `Description` text,\n  `OwnerId` varchar(26) DEFAULT NULL,\n  `CreateAt` bigint(20) DEFAULT NULL,\n  `UpdateAt` bigint(20) DEFAULT NULL,\n  `DeleteAt` bigint(20) NOT NULL DEFAULT 0,\n  PRIMARY KEY (`UserId`),\n  KEY `idx_bots_team_id_delete_at` (`TeamId`, `DeleteAt`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;\n\nCREATE TABLE `IncomingWebhooks` (\n  `Id` varchar(26) NOT NULL,\n  `Token` varchar(26) NOT NULL,\n  `TeamId` varchar(26) NOT NULL,\n  `GroupId` varchar(26) DEFAULT NULL,\n  `BotUserId` varchar(26) NOT NULL,\n  `CreatorId` varchar(26) DEFAULT NULL,\n  `Name` varchar(64) DEFAULT NULL,\n  `Description` varchar(255) DEFAULT NULL,\n  `CreateAt` bigint(20) DEFAULT NULL,\n  `UpdateAt` bigint(20) DEFAULT NULL,\n  `DeleteAt` bigint(20) NOT NULL DEFAULT 0,\n  PRIMARY KEY (`Id`),\n  UNIQUE KEY `idx_incoming_webhooks_token` (`Token`),\n  KEY `idx_incoming_webhooks_team_id_delete_at` (`TeamId`, `DeleteAt`),\n  KEY `idx_incoming_webhooks_bot_user_id` (`BotUserId`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS `IncomingWebhooks`;\nDROP TABLE IF EXISTS `Bots`;\n"},
+	{Name: "20201027091630_add_webhooks_payload_format.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nALTER TABLE `Webhooks` ADD COLUMN `PayloadFormat` varchar(32) NOT NULL DEFAULT 'native' AFTER `ContentType`;\nALTER TABLE `Webhooks` ADD COLUMN `PayloadTemplate` text AFTER `PayloadFormat`;\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nALTER TABLE `Webhooks` DROP COLUMN `PayloadTemplate`;\nALTER TABLE `Webhooks` DROP COLUMN `PayloadFormat`;\n"},
+	{Name: "20201028103742_add_audits_status.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nALTER TABLE `Audits`\n  ADD COLUMN `Status` varchar(32) DEFAULT NULL,\n  ADD COLUMN `ApiPath` varchar(255) DEFAULT NULL,\n  ADD COLUMN `Client` text,\n  ADD KEY `idx_audits_create_at` (`CreateAt`),\n  ADD KEY `idx_audits_ip_address` (`IpAddress`);\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nALTER TABLE `Audits`\n  DROP KEY `idx_audits_ip_address`,\n  DROP KEY `idx_audits_create_at`,\n  DROP COLUMN `Client`,\n  DROP COLUMN `ApiPath`,\n  DROP COLUMN `Status`;\n"},
+	{Name: "20201029094512_add_counter_source_indexes.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nALTER TABLE `Votes` ADD KEY `idx_votes_post_id_type` (`PostId`, `Type`);\nALTER TABLE `UserPointHistory` ADD KEY `idx_user_point_history_user_id_team_id` (`UserId`, `TeamId`);\nALTER TABLE `PostViewsHistory` ADD KEY `idx_post_views_history_post_id` (`PostId`);\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nALTER TABLE `PostViewsHistory` DROP INDEX `idx_post_views_history_post_id`;\nALTER TABLE `UserPointHistory` DROP INDEX `idx_user_point_history_user_id_team_id`;\nALTER TABLE `Votes` DROP INDEX `idx_votes_post_id_type`;\n"},
+}
+
+var postgresMigrations = []MigrationFile{
+	{Name: "20200106075451_create_users.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE Users (\n  Id varchar(26) NOT NULL,\n  Type varchar(26) DEFAULT NULL,\n  CreateAt bigint DEFAULT NULL,\n  UpdateAt bigint DEFAULT NULL,\n  DeleteAt bigint DEFAULT NULL,\n  SuspendTime bigint DEFAULT NULL,\n  Username varchar(64) DEFAULT NULL,\n  Password varchar(128) DEFAULT NULL,\n  Props text,\n  Email varchar(128) DEFAULT NULL,\n  EmailVerified boolean DEFAULT NULL,\n  Points integer DEFAULT NULL,\n  LastInboxMessageViewed bigint DEFAULT NULL,\n  LastPictureUpdate bigint DEFAULT NULL,\n  FailedAttempts integer DEFAULT NULL,\n  PRIMARY KEY (Id),\n  UNIQUE (Email)\n);\n\nCREATE INDEX idx_users_email ON Users (Email);\nCREATE INDEX idx_users_update_at ON Users (UpdateAt);\nCREATE INDEX idx_users_create_at ON Users (CreateAt);\nCREATE INDEX idx_users_delete_at ON Users (DeleteAt);\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS Users;\n"},
+	{Name: "20200107040845_create_sessions.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE Sessions (\n  Id varchar(26) NOT NULL,\n  Token varchar(26) DEFAULT NULL,\n  CreateAt bigint DEFAULT NULL,\n  ExpiresAt bigint DEFAULT NULL,\n  UserId varchar(26) DEFAULT NULL,\n  Props text,\n  IsOAuth boolean DEFAULT NULL,\n  PRIMARY KEY (Id)\n);\n\nCREATE INDEX idx_sessions_user_id ON Sessions (UserId);\nCREATE INDEX idx_sessions_token ON Sessions (Token);\nCREATE INDEX idx_sessions_expires_at ON Sessions (ExpiresAt);\nCREATE INDEX idx_sessions_create_at ON Sessions (CreateAt);\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS Sessions;\n"},
+	{Name: "20200107041130_create_tokens.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE Tokens (\n  Token varchar(64) NOT NULL,\n  CreateAt bigint DEFAULT NULL,\n  Type varchar(64) DEFAULT NULL,\n  Extra varchar(128) DEFAULT NULL,\n  PRIMARY KEY (Token)\n);\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS Tokens;\n"},
+	{Name: "20200107220016_create_posts.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\n-- TODO: indexを全て再考慮\nCREATE TABLE Posts (\n  Id varchar(26) NOT NULL,\n  Type varchar(26) DEFAULT NULL,\n  ParentId varchar(26) DEFAULT NULL,\n  RootId varchar(26) DEFAULT NULL,\n  OriginalId varchar(26) DEFAULT NULL,\n  BestId varchar(26) DEFAULT NULL,\n  UserId varchar(26) DEFAULT NULL,\n  TeamId varchar(26) DEFAULT NULL,\n  Title text,\n  Content text,\n  Tags text,\n  Props text,\n  UpVotes integer DEFAULT NULL,\n  DownVotes integer DEFAULT NULL,\n  Points integer DEFAULT NULL,\n  AnswerCount integer DEFAULT NULL,\n  FlagCount integer DEFAULT NULL,\n  Views integer DEFAULT NULL,\n  ProtectedAt bigint DEFAULT NULL,\n  LockedAt bigint DEFAULT NULL,\n  CreateAt bigint DEFAULT NULL,\n  UpdateAt bigint DEFAULT NULL,\n  EditAt bigint DEFAULT NULL,\n  DeleteAt bigint DEFAULT NULL,\n  PRIMARY KEY (Id)\n);\n\nCREATE INDEX idx_posts_type_delete_at_create_at ON Posts (Type, DeleteAt, CreateAt);\nCREATE INDEX idx_posts_type_delete_at_update_at ON Posts (Type, DeleteAt, UpdateAt);\nCREATE INDEX idx_posts_type_delete_at_points ON Posts (Type, DeleteAt, Points);\nCREATE INDEX idx_posts_type_delete_at_answer_count ON Posts (Type, DeleteAt, AnswerCount);\nCREATE INDEX idx_posts_type_up_votes_delete_at_create_at ON Posts (Type, UpVotes, DeleteAt, CreateAt);\nCREATE INDEX idx_posts_parent_id_type_delete_at_create_at ON Posts (ParentId, Type, DeleteAt, CreateAt);\nCREATE INDEX idx_posts_parent_id_type_delete_at_update_at ON Posts (ParentId, Type, DeleteAt, UpdateAt);\nCREATE INDEX idx_posts_parent_id_type_delete_at_points ON Posts (ParentId, Type, DeleteAt, Points);\nCREATE INDEX idx_posts_root_id_type_delete_at_create_at ON Posts (RootId, Type, DeleteAt, CreateAt);\nCREATE INDEX idx_posts_user_id_type_delete_at_create_at ON Posts (UserId, Type, DeleteAt, CreateAt);\nCREATE INDEX idx_posts_title_txt ON Posts USING GIN (to_tsvector('simple', COALESCE(Title, '')));\nCREATE INDEX idx_posts_content_txt ON Posts USING GIN (to_tsvector('simple', COALESCE(Content, '')));\nCREATE INDEX idx_posts_tags_txt ON Posts USING GIN (to_tsvector('simple', COALESCE(Tags, '')));\nCREATE INDEX idx_posts_title_tags_txt ON Posts USING GIN (to_tsvector('simple', COALESCE(Title, '') || ' ' || COALESCE(Tags, '')));\nCREATE INDEX idx_posts_title_tags_content_txt ON Posts USING GIN (to_tsvector('simple', COALESCE(Title, '') || ' ' || COALESCE(Tags, '') || ' ' || COALESCE(Content, '')));\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS Posts;\n"},
+	{Name: "20200108064409_create_tags.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE Tags (\n  Content varchar(64) NOT NULL,\n  TeamId varchar(26) NOT NULL,\n  Type varchar(26) NOT NULL,\n  PostCount integer DEFAULT NULL,\n  CreateAt bigint DEFAULT NULL,\n  UpdateAt bigint DEFAULT NULL,\n  PRIMARY KEY (Content, TeamId, Type)\n);\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS Tags;\n"},
+	{Name: "20200109030004_create_systems.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE Systems (\n  Name varchar(64) NOT NULL,\n  Value text,\n  PRIMARY KEY (Name)\n);\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS Systems;\n"},
+	{Name: "20200209095220_create_votes.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\n-- TODO: チームに紐づくテーブルを見るにはそのteamのメンバーである事が必要。\n-- すでに脱退済みなら見れない、他も同様。\n-- 一度脱退し、再度joinすると以前と同じ様に見れる仕様。\nCREATE TABLE Votes (\n  PostId varchar(26) NOT NULL,\n  UserId varchar(26) NOT NULL,\n  Type varchar(26) NOT NULL,\n  Tags text,\n  TeamId varchar(26) DEFAULT NULL,\n  FirstPostRev integer DEFAULT NULL,\n  LastPostRev integer DEFAULT NULL,\n  CreateAt bigint DEFAULT NULL,\n  InvalidateAt bigint DEFAULT NULL,\n  CompletedAt bigint DEFAULT NULL,\n  CompletedBy varchar(26) DEFAULT NULL,\n  RejectedAt bigint DEFAULT NULL,\n  RejectedBy varchar(26) DEFAULT NULL,\n  PRIMARY KEY (UserId, Type, PostId)\n);\n\nCREATE INDEX idx_votes_user_id_type_create_at ON Votes (UserId, Type, CreateAt);\nCREATE INDEX idx_votes_tags_txt ON Votes USING GIN (to_tsvector('simple', COALESCE(Tags, '')));\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS Votes;\n"},
+	{Name: "20200209105331_create_user_point_history.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE UserPointHistory (\n  Id varchar(26) NOT NULL,\n  UserId varchar(26) NOT NULL,\n  TeamId varchar(26) DEFAULT NULL,\n  Type varchar(26) DEFAULT NULL,\n  PostId varchar(26) DEFAULT NULL,\n  PostType varchar(26) DEFAULT NULL,\n  Tags text,\n  Points integer DEFAULT NULL,\n  CreateAt bigint DEFAULT NULL,\n  PRIMARY KEY (Id)\n);\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS UserPointHistory;\n"},
+	{Name: "20200222211637_create_inbox_messages.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE InboxMessages (\n  Id varchar(26) NOT NULL,\n  Type varchar(26) DEFAULT NULL,\n  Content text,\n  UserId varchar(26) DEFAULT NULL,\n  SenderId varchar(26) DEFAULT NULL,\n  QuestionId varchar(26) NOT NULL,\n  Title text,\n  AnswerId varchar(26) NOT NULL,\n  CommentId varchar(26) NOT NULL,\n  TeamId varchar(26) DEFAULT NULL,\n  CreateAt bigint DEFAULT NULL,\n  PRIMARY KEY (Id)\n);\n\nCREATE INDEX idx_inbox_messages_user_id_create_at ON InboxMessages (UserId, CreateAt);\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS InboxMessages;\n"},
+	{Name: "20200225202508_create_user_favorite_posts.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE UserFavoritePosts (\n  PostId varchar(26) NOT NULL,\n  UserId varchar(26) NOT NULL,\n  TeamId varchar(26) DEFAULT NULL,\n  CreateAt bigint DEFAULT NULL,\n  PRIMARY KEY (PostId, UserId)\n);\n\nCREATE INDEX idx_user_favorite_posts_user_id_create_at ON UserFavoritePosts (UserId, CreateAt);\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS UserFavoritePosts;\n"},
+	{Name: "20200305235455_create_file_info.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE FileInfo (\n  Id varchar(26) NOT NULL,\n  UserId varchar(26) DEFAULT NULL,\n  PostId varchar(26) DEFAULT NULL,\n  CreateAt bigint DEFAULT NULL,\n  DeleteAt bigint DEFAULT NULL,\n  Path text,\n  ThumbnailPath text,\n  Name text,\n  Extension varchar(64) DEFAULT NULL,\n  Size bigint DEFAULT NULL,\n  MimeType text,\n  Width integer DEFAULT NULL,\n  Height integer DEFAULT NULL,\n  PRIMARY KEY (Id)\n);\n\nCREATE INDEX idx_file_info_post_id_delete_at_create_at ON FileInfo (PostId, DeleteAt, CreateAt);\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS FileInfo;\n"},
+	{Name: "20200313035307_create_notification_settings.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE NotificationSettings (\n  Id varchar(26) NOT NULL,\n  UserId varchar(26) NOT NULL,\n  InboxInterval varchar(26) DEFAULT NULL,\n  CreateAt bigint DEFAULT NULL,\n  UpdateAt bigint DEFAULT NULL,\n  PRIMARY KEY (Id)\n);\n\nCREATE INDEX idx_notification_settings_user_id_inbox_interval ON NotificationSettings (UserId, InboxInterval);\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS NotificationSettings;\n"},
+	{Name: "20200804105638_create_teams.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE Teams (\n  Id varchar(26) NOT NULL,\n  Type varchar(26) DEFAULT NULL,\n  Name varchar(64) DEFAULT NULL,\n  Description varchar(255) DEFAULT NULL,\n  Email varchar(128) DEFAULT NULL,\n  AllowedDomains text,\n  InviteId varchar(32) DEFAULT NULL,\n  LastPictureUpdate bigint DEFAULT NULL,\n  CreateAt bigint DEFAULT NULL,\n  UpdateAt bigint DEFAULT NULL,\n  DeleteAt bigint DEFAULT NULL,\n  PRIMARY KEY (Id),\n  UNIQUE (Name)\n);\n\nCREATE INDEX idx_teams_name ON Teams (Name);\nCREATE INDEX idx_teams_invite_id ON Teams (InviteId);\nCREATE INDEX idx_teams_update_at ON Teams (UpdateAt);\nCREATE INDEX idx_teams_create_at ON Teams (CreateAt);\nCREATE INDEX idx_teams_delete_at ON Teams (DeleteAt);\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS Teams;\n"},
+	{Name: "20200804130532_create_team_members.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE TeamMembers (\n  TeamId varchar(26) NOT NULL,\n  UserId varchar(26) NOT NULL,\n  Type varchar(26) DEFAULT NULL,\n  Points integer DEFAULT NULL,\n  DeleteAt bigint DEFAULT NULL,\n  PRIMARY KEY (TeamId, UserId)\n);\n\nCREATE INDEX idx_teammembers_team_id ON TeamMembers (TeamId);\nCREATE INDEX idx_teammembers_user_id ON TeamMembers (UserId);\nCREATE INDEX idx_teammembers_delete_at ON TeamMembers (DeleteAt);\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS TeamMembers;\n"},
+	{Name: "20200804131158_create_team_member_history.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE TeamMemberHistory (\n  TeamId varchar(26) NOT NULL,\n  UserId varchar(26) NOT NULL,\n  JoinTime bigint NOT NULL,\n  LeaveTime bigint DEFAULT NULL,\n  PRIMARY KEY (TeamId, UserId, JoinTime)\n);\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS TeamMemberHistory;\n"},
+	{Name: "20200806102102_create_user_groups.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE UserGroups (\n  Id varchar(26) NOT NULL,\n  Type varchar(26) DEFAULT NULL,\n  CreateAt bigint DEFAULT NULL,\n  UpdateAt bigint DEFAULT NULL,\n  DeleteAt bigint DEFAULT NULL,\n  TeamId varchar(26) DEFAULT NULL,\n  Name varchar(64) DEFAULT NULL,\n  Description varchar(255) DEFAULT NULL,\n  UserId varchar(26) DEFAULT NULL,\n  PRIMARY KEY (Id),\n  UNIQUE (Name, TeamId)\n);\n\nCREATE INDEX idx_user_groups_team_id ON UserGroups (TeamId);\nCREATE INDEX idx_user_groups_update_at ON UserGroups (UpdateAt);\nCREATE INDEX idx_user_groups_create_at ON UserGroups (CreateAt);\nCREATE INDEX idx_user_groups_delete_at ON UserGroups (DeleteAt);\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS UserGroups;\n"},
+	{Name: "20200806102238_create_group_members.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE GroupMembers (\n  GroupId varchar(26) NOT NULL,\n  UserId varchar(26) NOT NULL,\n  Type varchar(26) DEFAULT NULL,\n  PRIMARY KEY (GroupId, UserId)\n);\n\nCREATE INDEX idx_groupmembers_group_id ON GroupMembers (GroupId);\nCREATE INDEX idx_groupmembers_user_id ON GroupMembers (UserId);\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS GroupMembers;\n"},
+	{Name: "20200806113738_create_group_member_history.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE GroupMemberHistory (\n  GroupId varchar(26) NOT NULL,\n  UserId varchar(26) NOT NULL,\n  JoinTime bigint NOT NULL,\n  LeaveTime bigint DEFAULT NULL,\n  PRIMARY KEY (GroupId, UserId, JoinTime)\n);\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS GroupMemberHistory;\n"},
+	{Name: "20200809053237_create_collections.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE Collections (\n  Id varchar(26) NOT NULL,\n  CreateAt bigint DEFAULT NULL,\n  UpdateAt bigint DEFAULT NULL,\n  DeleteAt bigint DEFAULT NULL,\n  TeamId varchar(26) DEFAULT NULL,\n  Title text,\n  Description varchar(255) DEFAULT NULL,\n  UserId varchar(26) DEFAULT NULL,\n  PRIMARY KEY (Id)\n);\n\nCREATE INDEX idx_collections_team_id ON Collections (TeamId);\nCREATE INDEX idx_collections_create_at ON Collections (CreateAt);\nCREATE INDEX idx_collections_update_at ON Collections (UpdateAt);\nCREATE INDEX idx_collections_delete_at ON Collections (DeleteAt);\nCREATE INDEX idx_collections_title_txt ON Collections USING GIN (to_tsvector('simple', COALESCE(Title, '')));\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS Collections;\n"},
+	{Name: "20200809053321_create_collection_posts.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE CollectionPosts (\n  CollectionId varchar(26) NOT NULL,\n  PostId varchar(26) NOT NULL,\n  PRIMARY KEY (CollectionId, PostId)\n);\n\nCREATE INDEX idx_collectionposts_collection_id ON CollectionPosts (CollectionId);\nCREATE INDEX idx_collectionposts_post_id ON CollectionPosts (PostId);\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS CollectionPosts;\n"},
+	{Name: "20200810113250_create_audits.sql", Content: "-- +goose Up\n-- TODO: TeamIdを用意、\n-- teamに所属している限りはteam関連のログが本人も見れるが、\n-- 脱退すれば本人は見れなくなり、team adminなら見れる。\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE Audits (\n  Id varchar(26) NOT NULL,\n  CreateAt bigint DEFAULT NULL,\n  UserId varchar(26) DEFAULT NULL,\n  Action text,\n  ExtraInfo text,\n  IpAddress varchar(64) DEFAULT NULL,\n  SessionId varchar(26) DEFAULT NULL,\n  PRIMARY KEY (Id)\n);\n\nCREATE INDEX idx_audits_user_id ON Audits (UserId);\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS Audits;\n"},
+	{Name: "20200819204843_create_post_views_history.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE PostViewsHistory (\n  Id varchar(26) NOT NULL,\n  PostId varchar(26) NOT NULL,\n  TeamId varchar(26) DEFAULT NULL,\n  UserId varchar(26) DEFAULT NULL,\n  IpAddress varchar(64) DEFAULT NULL,\n  ViewsCount integer DEFAULT NULL,\n  CreateAt bigint DEFAULT NULL,\n  PRIMARY KEY (Id)\n);\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS PostViewsHistory;\n"},
+	{Name: "20200826043049_create_webhooks.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE Webhooks (\n  Id varchar(26) NOT NULL,\n  Token varchar(26) DEFAULT NULL,\n  UserId varchar(26) DEFAULT NULL,\n  TeamId varchar(26) DEFAULT NULL,\n  QuestionEvents boolean DEFAULT NULL,\n  AnswerEvents boolean DEFAULT NULL,\n  CommentEvents boolean DEFAULT NULL,\n  URLs text,\n  Name varchar(64) DEFAULT NULL,\n  Description varchar(255) DEFAULT NULL,\n  ContentType varchar(128) DEFAULT NULL,\n  CreateAt bigint DEFAULT NULL,\n  UpdateAt bigint DEFAULT NULL,\n  DeleteAt bigint DEFAULT NULL,\n  PRIMARY KEY (Id)\n);\n\nCREATE INDEX idx_webhook_team_id ON Webhooks (TeamId);\nCREATE INDEX idx_webhook_create_at ON Webhooks (CreateAt);\nCREATE INDEX idx_webhook_update_at ON Webhooks (UpdateAt);\nCREATE INDEX idx_webhook_delete_at ON Webhooks (DeleteAt);\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS Webhooks;\n"},
+	{Name: "20200826043508_create_webhooks_history.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE WebhooksHistory (\n  Id varchar(26) NOT NULL,\n  WebhookId varchar(26) NOT NULL,\n  PostId varchar(26) DEFAULT NULL,\n  TeamId varchar(26) DEFAULT NULL,\n  WebhookName varchar(64) DEFAULT NULL,\n  URL text,\n  ContentType varchar(128) DEFAULT NULL,\n  RequestBody text,\n  ResponseBody text,\n  ResponseStatus integer DEFAULT NULL,\n  CreateAt bigint DEFAULT NULL,\n  PRIMARY KEY (Id)\n);\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS WebhooksHistory;\n"},
+	{Name: "20200827123548_create_oauth_access_data.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE OAuthAccessData (\n  ClientId varchar(26) DEFAULT NULL,\n  UserId varchar(26) DEFAULT NULL,\n  Token varchar(26) NOT NULL,\n  RefreshToken varchar(26) DEFAULT NULL,\n  RedirectUri text,\n  ExpiresAt bigint DEFAULT NULL,\n  Scope varchar(128) DEFAULT NULL,\n  PRIMARY KEY (Token),\n  UNIQUE (ClientId, UserId)\n);\n\nCREATE INDEX idx_oauthaccessdata_client_id ON OAuthAccessData (ClientId);\nCREATE INDEX idx_oauthaccessdata_user_id ON OAuthAccessData (UserId);\nCREATE INDEX idx_oauthaccessdata_refresh_token ON OAuthAccessData (RefreshToken);\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS OAuthAccessData;\n"},
+	{Name: "20200827123619_create_oauth_apps.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE OAuthApps (\n  Id varchar(26) NOT NULL,\n  UserId varchar(26) DEFAULT NULL,\n  CreateAt bigint DEFAULT NULL,\n  UpdateAt bigint DEFAULT NULL,\n  ClientSecret varchar(128) DEFAULT NULL,\n  Name varchar(64) DEFAULT NULL,\n  Description text,\n  IconURL text,\n  URLs text,\n  Homepage text,\n  PRIMARY KEY (Id)\n);\n\nCREATE INDEX idx_oauthapps_user_id ON OAuthApps (UserId);\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS OAuthApps;\n"},
+	{Name: "20200827124332_create_oauth_auth_data.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE OAuthAuthData (\n  ClientId varchar(26) DEFAULT NULL,\n  UserId varchar(26) DEFAULT NULL,\n  Code varchar(128) NOT NULL,\n  ExpiresIn integer DEFAULT NULL,\n  CreateAt bigint DEFAULT NULL,\n  RedirectUri text,\n  State text,\n  Scope varchar(128) DEFAULT NULL,\n  PRIMARY KEY (Code)\n);\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS OAuthAuthData;\n"},
+	{Name: "20200827183650_create_oauth_authorized_apps.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE OAuthAuthorizedApps (\n  UserId varchar(26) NOT NULL,\n  ClientId varchar(26) NOT NULL,\n  Scope varchar(128) DEFAULT NULL,\n  PRIMARY KEY (UserId, ClientId)\n);\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS OAuthAuthorizedApps;\n"},
+	{Name: "20200909190327_create_status.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE Status (\n  UserId varchar(26) NOT NULL,\n  Status varchar(32) DEFAULT NULL,\n  Manual boolean DEFAULT NULL,\n  LastActivityAt bigint DEFAULT NULL,\n  PRIMARY KEY (UserId)\n);\n\nCREATE INDEX idx_status_user_id ON Status (UserId);\nCREATE INDEX idx_status_status ON Status (Status);\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS Status;\n"},
+	{Name: "20201020093012_add_sessions_last_activity_at.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nALTER TABLE Sessions ADD COLUMN LastActivityAt bigint DEFAULT NULL;\nUPDATE Sessions SET LastActivityAt = CreateAt;\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nALTER TABLE Sessions DROP COLUMN LastActivityAt;\n"},
+	{Name: "20201021101544_add_users_auth.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nALTER TABLE Users ADD COLUMN AuthService varchar(32) NOT NULL DEFAULT '';\nALTER TABLE Users ADD COLUMN AuthData varchar(128) DEFAULT NULL;\nALTER TABLE Users ADD CONSTRAINT idx_users_auth_data_unique UNIQUE (AuthData);\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nALTER TABLE Users DROP CONSTRAINT idx_users_auth_data_unique;\nALTER TABLE Users DROP COLUMN AuthData;\nALTER TABLE Users DROP COLUMN AuthService;\n"},
+	{Name: "20201022140311_create_jobs.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE Jobs (\n  Id varchar(26) NOT NULL,\n  Type varchar(32) DEFAULT NULL,\n  TeamId varchar(26) DEFAULT NULL,\n  UserId varchar(26) DEFAULT NULL,\n  Status varchar(32) DEFAULT NULL,\n  Progress bigint DEFAULT NULL,\n  CreateAt bigint DEFAULT NULL,\n  StartAt bigint DEFAULT NULL,\n  LastActivityAt bigint DEFAULT NULL,\n  Data text,\n  PRIMARY KEY (Id)\n);\n\nCREATE INDEX idx_jobs_team_id_type_status ON Jobs (TeamId, Type, Status);\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS Jobs;\n"},
+	{Name: "20201023093512_create_webhook_deliveries.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE WebhookDeliveries (\n  Id varchar(26) NOT NULL,\n  WebhookId varchar(26) NOT NULL,\n  TeamId varchar(26) DEFAULT NULL,\n  PostId varchar(26) DEFAULT NULL,\n  URL text,\n  ContentType varchar(128) DEFAULT NULL,\n  RequestBody text,\n  Status varchar(32) DEFAULT NULL,\n  Attempts integer NOT NULL DEFAULT 0,\n  NextAttemptAt bigint DEFAULT NULL,\n  CreateAt bigint DEFAULT NULL,\n  UpdateAt bigint DEFAULT NULL,\n  PRIMARY KEY (Id)\n);\n\nCREATE INDEX idx_webhook_deliveries_status_next_attempt_at ON WebhookDeliveries (Status, NextAttemptAt);\n\nALTER TABLE Webhooks ADD COLUMN FailureCount integer NOT NULL DEFAULT 0;\nALTER TABLE Webhooks ADD COLUMN DisabledAt bigint NOT NULL DEFAULT 0;\n\nALTER TABLE WebhooksHistory ADD COLUMN DeliveryId varchar(26) DEFAULT NULL;\nALTER TABLE WebhooksHistory ADD COLUMN Attempt integer NOT NULL DEFAULT 0;\nALTER TABLE WebhooksHistory ADD COLUMN Latency bigint NOT NULL DEFAULT 0;\nALTER TABLE WebhooksHistory ADD COLUMN Error text;\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nALTER TABLE WebhooksHistory DROP COLUMN Error;\nALTER TABLE WebhooksHistory DROP COLUMN Latency;\nALTER TABLE WebhooksHistory DROP COLUMN Attempt;\nALTER TABLE WebhooksHistory DROP COLUMN DeliveryId;\nALTER TABLE Webhooks DROP COLUMN DisabledAt;\nALTER TABLE Webhooks DROP COLUMN FailureCount;\nDROP TABLE IF EXISTS WebhookDeliveries;\n"},
+	{Name: "20201024101207_add_webhooks_events.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nALTER TABLE Webhooks ADD COLUMN Events text;\nUPDATE Webhooks SET Events = '[' || CONCAT_WS(',',\n  CASE WHEN QuestionEvents THEN '\"question_created\"' END,\n  CASE WHEN AnswerEvents THEN '\"answer_created\"' END,\n  CASE WHEN CommentEvents THEN '\"comment_created\"' END\n) || ']';\nALTER TABLE Webhooks DROP COLUMN QuestionEvents;\nALTER TABLE Webhooks DROP COLUMN AnswerEvents;\nALTER TABLE Webhooks DROP COLUMN CommentEvents;\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nALTER TABLE Webhooks ADD COLUMN QuestionEvents boolean DEFAULT NULL;\nALTER TABLE Webhooks ADD COLUMN AnswerEvents boolean DEFAULT NULL;\nALTER TABLE Webhooks ADD COLUMN CommentEvents boolean DEFAULT NULL;\nUPDATE Webhooks SET\n  QuestionEvents = Events LIKE '%\"question_created\"%',\n  AnswerEvents = Events LIKE '%\"answer_created\"%',\n  CommentEvents = Events LIKE '%\"comment_created\"%';\nALTER TABLE Webhooks DROP COLUMN Events;\n"},
+	{Name: "20201025093844_add_webhooks_history_headers.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nALTER TABLE WebhooksHistory ADD COLUMN RequestHeaders text;\nALTER TABLE WebhooksHistory ADD COLUMN ResponseHeaders text;\nCREATE INDEX idx_webhooks_history_team_id_create_at ON WebhooksHistory (TeamId, CreateAt);\nCREATE INDEX idx_webhooks_history_webhook_id ON WebhooksHistory (WebhookId);\nCREATE INDEX idx_webhooks_history_create_at ON WebhooksHistory (CreateAt);\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP INDEX IF EXISTS idx_webhooks_history_create_at;\nDROP INDEX IF EXISTS idx_webhooks_history_webhook_id;\nDROP INDEX IF EXISTS idx_webhooks_history_team_id_create_at;\nALTER TABLE WebhooksHistory DROP COLUMN ResponseHeaders;\nALTER TABLE WebhooksHistory DROP COLUMN RequestHeaders;\n"},
+	{Name: "20201026104521_create_bots_and_incoming_webhooks.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE TABLE Bots (\n  UserId varchar(26) NOT NULL,\n  TeamId varchar(26) NOT NULL,\n  DisplayName varchar(256) DEFAULT NULL,\n  Description text,\n  OwnerId varchar(26) DEFAULT NULL,\n  CreateAt bigint DEFAULT NULL,\n  UpdateAt bigint DEFAULT NULL,\n  DeleteAt bigint NOT NULL DEFAULT 0,\n  PRIMARY KEY (UserId)\n);\n\nCREATE INDEX idx_bots_team_id_delete_at ON Bots (TeamId, DeleteAt);\n\nCREATE TABLE IncomingWebhooks (\n  Id varchar(26) NOT NULL,\n  Token varchar(26) NOT NULL,\n  TeamId varchar(26) NOT NULL,\n  GroupId varchar(26) DEFAULT NULL,\n  BotUserId varchar(26) NOT NULL,\n  CreatorId varchar(26) DEFAULT NULL,\n  Name varchar(64) DEFAULT NULL,\n  Description varchar(255) DEFAULT NULL,\n  CreateAt bigint DEFAULT NULL,\n  UpdateAt bigint DEFAULT NULL,\n  DeleteAt bigint NOT NULL DEFAULT 0,\n  PRIMARY KEY (Id)\n);\n\nCREATE UNIQUE INDEX idx_incoming_webhooks_token ON IncomingWebhooks (Token);\nCREATE INDEX idx_incoming_webhooks_team_id_delete_at ON IncomingWebhooks (TeamId, DeleteAt);\nCREATE INDEX idx_incoming_webhooks_bot_user_id ON IncomingWebhooks (BotUserId);\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP TABLE IF EXISTS IncomingWebhooks;\nDROP TABLE IF EXISTS Bots;\n"},
+	{Name: "20201027091630_add_webhooks_payload_format.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nALTER TABLE Webhooks ADD COLUMN PayloadFormat varchar(32) NOT NULL DEFAULT 'native';\nALTER TABLE Webhooks ADD COLUMN PayloadTemplate text;\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nALTER TABLE Webhooks DROP COLUMN PayloadTemplate;\nALTER TABLE Webhooks DROP COLUMN PayloadFormat;\n"},
+	{Name: "20201028103742_add_audits_status.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nALTER TABLE Audits\n  ADD COLUMN Status varchar(32) DEFAULT NULL,\n  ADD COLUMN ApiPath varchar(255) DEFAULT NULL,\n  ADD COLUMN Client text;\nCREATE INDEX idx_audits_create_at ON Audits (CreateAt);\nCREATE INDEX idx_audits_ip_address ON Audits (IpAddress);\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP INDEX IF EXISTS idx_audits_ip_address;\nDROP INDEX IF EXISTS idx_audits_create_at;\nALTER TABLE Audits\n  DROP COLUMN Client,\n  DROP COLUMN ApiPath,\n  DROP COLUMN Status;\n"},
+	{Name: "20201029094512_add_counter_source_indexes.sql", Content: "-- +goose Up\n-- SQL in this section is executed when the migration is applied.\nCREATE INDEX idx_votes_post_id_type ON Votes (PostId, Type);\nCREATE INDEX idx_user_point_history_user_id_team_id ON UserPointHistory (UserId, TeamId);\nCREATE INDEX idx_post_views_history_post_id ON PostViewsHistory (PostId);\n\n-- +goose Down\n-- SQL in this section is executed when the migration is rolled back.\nDROP INDEX IF EXISTS idx_post_views_history_post_id;\nDROP INDEX IF EXISTS idx_user_point_history_user_id_team_id;\nDROP INDEX IF EXISTS idx_votes_post_id_type;\n"},
+}
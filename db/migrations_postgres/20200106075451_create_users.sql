@@ -0,0 +1,30 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE Users (
+  Id varchar(26) NOT NULL,
+  Type varchar(26) DEFAULT NULL,
+  CreateAt bigint DEFAULT NULL,
+  UpdateAt bigint DEFAULT NULL,
+  DeleteAt bigint DEFAULT NULL,
+  SuspendTime bigint DEFAULT NULL,
+  Username varchar(64) DEFAULT NULL,
+  Password varchar(128) DEFAULT NULL,
+  Props text,
+  Email varchar(128) DEFAULT NULL,
+  EmailVerified boolean DEFAULT NULL,
+  Points integer DEFAULT NULL,
+  LastInboxMessageViewed bigint DEFAULT NULL,
+  LastPictureUpdate bigint DEFAULT NULL,
+  FailedAttempts integer DEFAULT NULL,
+  PRIMARY KEY (Id),
+  UNIQUE (Email)
+);
+
+CREATE INDEX idx_users_email ON Users (Email);
+CREATE INDEX idx_users_update_at ON Users (UpdateAt);
+CREATE INDEX idx_users_create_at ON Users (CreateAt);
+CREATE INDEX idx_users_delete_at ON Users (DeleteAt);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS Users;
@@ -0,0 +1,21 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE Sessions (
+  Id varchar(26) NOT NULL,
+  Token varchar(26) DEFAULT NULL,
+  CreateAt bigint DEFAULT NULL,
+  ExpiresAt bigint DEFAULT NULL,
+  UserId varchar(26) DEFAULT NULL,
+  Props text,
+  IsOAuth boolean DEFAULT NULL,
+  PRIMARY KEY (Id)
+);
+
+CREATE INDEX idx_sessions_user_id ON Sessions (UserId);
+CREATE INDEX idx_sessions_token ON Sessions (Token);
+CREATE INDEX idx_sessions_expires_at ON Sessions (ExpiresAt);
+CREATE INDEX idx_sessions_create_at ON Sessions (CreateAt);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS Sessions;
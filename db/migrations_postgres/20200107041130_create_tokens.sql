@@ -0,0 +1,13 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE Tokens (
+  Token varchar(64) NOT NULL,
+  CreateAt bigint DEFAULT NULL,
+  Type varchar(64) DEFAULT NULL,
+  Extra varchar(128) DEFAULT NULL,
+  PRIMARY KEY (Token)
+);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS Tokens;
@@ -0,0 +1,50 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+-- TODO: indexを全て再考慮
+CREATE TABLE Posts (
+  Id varchar(26) NOT NULL,
+  Type varchar(26) DEFAULT NULL,
+  ParentId varchar(26) DEFAULT NULL,
+  RootId varchar(26) DEFAULT NULL,
+  OriginalId varchar(26) DEFAULT NULL,
+  BestId varchar(26) DEFAULT NULL,
+  UserId varchar(26) DEFAULT NULL,
+  TeamId varchar(26) DEFAULT NULL,
+  Title text,
+  Content text,
+  Tags text,
+  Props text,
+  UpVotes integer DEFAULT NULL,
+  DownVotes integer DEFAULT NULL,
+  Points integer DEFAULT NULL,
+  AnswerCount integer DEFAULT NULL,
+  FlagCount integer DEFAULT NULL,
+  Views integer DEFAULT NULL,
+  ProtectedAt bigint DEFAULT NULL,
+  LockedAt bigint DEFAULT NULL,
+  CreateAt bigint DEFAULT NULL,
+  UpdateAt bigint DEFAULT NULL,
+  EditAt bigint DEFAULT NULL,
+  DeleteAt bigint DEFAULT NULL,
+  PRIMARY KEY (Id)
+);
+
+CREATE INDEX idx_posts_type_delete_at_create_at ON Posts (Type, DeleteAt, CreateAt);
+CREATE INDEX idx_posts_type_delete_at_update_at ON Posts (Type, DeleteAt, UpdateAt);
+CREATE INDEX idx_posts_type_delete_at_points ON Posts (Type, DeleteAt, Points);
+CREATE INDEX idx_posts_type_delete_at_answer_count ON Posts (Type, DeleteAt, AnswerCount);
+CREATE INDEX idx_posts_type_up_votes_delete_at_create_at ON Posts (Type, UpVotes, DeleteAt, CreateAt);
+CREATE INDEX idx_posts_parent_id_type_delete_at_create_at ON Posts (ParentId, Type, DeleteAt, CreateAt);
+CREATE INDEX idx_posts_parent_id_type_delete_at_update_at ON Posts (ParentId, Type, DeleteAt, UpdateAt);
+CREATE INDEX idx_posts_parent_id_type_delete_at_points ON Posts (ParentId, Type, DeleteAt, Points);
+CREATE INDEX idx_posts_root_id_type_delete_at_create_at ON Posts (RootId, Type, DeleteAt, CreateAt);
+CREATE INDEX idx_posts_user_id_type_delete_at_create_at ON Posts (UserId, Type, DeleteAt, CreateAt);
+CREATE INDEX idx_posts_title_txt ON Posts USING GIN (to_tsvector('simple', COALESCE(Title, '')));
+CREATE INDEX idx_posts_content_txt ON Posts USING GIN (to_tsvector('simple', COALESCE(Content, '')));
+CREATE INDEX idx_posts_tags_txt ON Posts USING GIN (to_tsvector('simple', COALESCE(Tags, '')));
+CREATE INDEX idx_posts_title_tags_txt ON Posts USING GIN (to_tsvector('simple', COALESCE(Title, '') || ' ' || COALESCE(Tags, '')));
+CREATE INDEX idx_posts_title_tags_content_txt ON Posts USING GIN (to_tsvector('simple', COALESCE(Title, '') || ' ' || COALESCE(Tags, '') || ' ' || COALESCE(Content, '')));
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS Posts;
@@ -0,0 +1,15 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE Tags (
+  Content varchar(64) NOT NULL,
+  TeamId varchar(26) NOT NULL,
+  Type varchar(26) NOT NULL,
+  PostCount integer DEFAULT NULL,
+  CreateAt bigint DEFAULT NULL,
+  UpdateAt bigint DEFAULT NULL,
+  PRIMARY KEY (Content, TeamId, Type)
+);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS Tags;
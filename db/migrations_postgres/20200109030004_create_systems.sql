@@ -0,0 +1,11 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE Systems (
+  Name varchar(64) NOT NULL,
+  Value text,
+  PRIMARY KEY (Name)
+);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS Systems;
@@ -0,0 +1,28 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+-- TODO: チームに紐づくテーブルを見るにはそのteamのメンバーである事が必要。
+-- すでに脱退済みなら見れない、他も同様。
+-- 一度脱退し、再度joinすると以前と同じ様に見れる仕様。
+CREATE TABLE Votes (
+  PostId varchar(26) NOT NULL,
+  UserId varchar(26) NOT NULL,
+  Type varchar(26) NOT NULL,
+  Tags text,
+  TeamId varchar(26) DEFAULT NULL,
+  FirstPostRev integer DEFAULT NULL,
+  LastPostRev integer DEFAULT NULL,
+  CreateAt bigint DEFAULT NULL,
+  InvalidateAt bigint DEFAULT NULL,
+  CompletedAt bigint DEFAULT NULL,
+  CompletedBy varchar(26) DEFAULT NULL,
+  RejectedAt bigint DEFAULT NULL,
+  RejectedBy varchar(26) DEFAULT NULL,
+  PRIMARY KEY (UserId, Type, PostId)
+);
+
+CREATE INDEX idx_votes_user_id_type_create_at ON Votes (UserId, Type, CreateAt);
+CREATE INDEX idx_votes_tags_txt ON Votes USING GIN (to_tsvector('simple', COALESCE(Tags, '')));
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS Votes;
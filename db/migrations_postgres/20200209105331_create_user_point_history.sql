@@ -0,0 +1,18 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE UserPointHistory (
+  Id varchar(26) NOT NULL,
+  UserId varchar(26) NOT NULL,
+  TeamId varchar(26) DEFAULT NULL,
+  Type varchar(26) DEFAULT NULL,
+  PostId varchar(26) DEFAULT NULL,
+  PostType varchar(26) DEFAULT NULL,
+  Tags text,
+  Points integer DEFAULT NULL,
+  CreateAt bigint DEFAULT NULL,
+  PRIMARY KEY (Id)
+);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS UserPointHistory;
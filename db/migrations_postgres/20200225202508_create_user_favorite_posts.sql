@@ -0,0 +1,15 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE UserFavoritePosts (
+  PostId varchar(26) NOT NULL,
+  UserId varchar(26) NOT NULL,
+  TeamId varchar(26) DEFAULT NULL,
+  CreateAt bigint DEFAULT NULL,
+  PRIMARY KEY (PostId, UserId)
+);
+
+CREATE INDEX idx_user_favorite_posts_user_id_create_at ON UserFavoritePosts (UserId, CreateAt);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS UserFavoritePosts;
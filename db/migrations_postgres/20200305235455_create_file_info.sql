@@ -0,0 +1,24 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE FileInfo (
+  Id varchar(26) NOT NULL,
+  UserId varchar(26) DEFAULT NULL,
+  PostId varchar(26) DEFAULT NULL,
+  CreateAt bigint DEFAULT NULL,
+  DeleteAt bigint DEFAULT NULL,
+  Path text,
+  ThumbnailPath text,
+  Name text,
+  Extension varchar(64) DEFAULT NULL,
+  Size bigint DEFAULT NULL,
+  MimeType text,
+  Width integer DEFAULT NULL,
+  Height integer DEFAULT NULL,
+  PRIMARY KEY (Id)
+);
+
+CREATE INDEX idx_file_info_post_id_delete_at_create_at ON FileInfo (PostId, DeleteAt, CreateAt);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS FileInfo;
@@ -0,0 +1,16 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE NotificationSettings (
+  Id varchar(26) NOT NULL,
+  UserId varchar(26) NOT NULL,
+  InboxInterval varchar(26) DEFAULT NULL,
+  CreateAt bigint DEFAULT NULL,
+  UpdateAt bigint DEFAULT NULL,
+  PRIMARY KEY (Id)
+);
+
+CREATE INDEX idx_notification_settings_user_id_inbox_interval ON NotificationSettings (UserId, InboxInterval);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS NotificationSettings;
@@ -0,0 +1,27 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE Teams (
+  Id varchar(26) NOT NULL,
+  Type varchar(26) DEFAULT NULL,
+  Name varchar(64) DEFAULT NULL,
+  Description varchar(255) DEFAULT NULL,
+  Email varchar(128) DEFAULT NULL,
+  AllowedDomains text,
+  InviteId varchar(32) DEFAULT NULL,
+  LastPictureUpdate bigint DEFAULT NULL,
+  CreateAt bigint DEFAULT NULL,
+  UpdateAt bigint DEFAULT NULL,
+  DeleteAt bigint DEFAULT NULL,
+  PRIMARY KEY (Id),
+  UNIQUE (Name)
+);
+
+CREATE INDEX idx_teams_name ON Teams (Name);
+CREATE INDEX idx_teams_invite_id ON Teams (InviteId);
+CREATE INDEX idx_teams_update_at ON Teams (UpdateAt);
+CREATE INDEX idx_teams_create_at ON Teams (CreateAt);
+CREATE INDEX idx_teams_delete_at ON Teams (DeleteAt);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS Teams;
@@ -0,0 +1,18 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE TeamMembers (
+  TeamId varchar(26) NOT NULL,
+  UserId varchar(26) NOT NULL,
+  Type varchar(26) DEFAULT NULL,
+  Points integer DEFAULT NULL,
+  DeleteAt bigint DEFAULT NULL,
+  PRIMARY KEY (TeamId, UserId)
+);
+
+CREATE INDEX idx_teammembers_team_id ON TeamMembers (TeamId);
+CREATE INDEX idx_teammembers_user_id ON TeamMembers (UserId);
+CREATE INDEX idx_teammembers_delete_at ON TeamMembers (DeleteAt);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS TeamMembers;
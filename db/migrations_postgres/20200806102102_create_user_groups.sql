@@ -0,0 +1,24 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE UserGroups (
+  Id varchar(26) NOT NULL,
+  Type varchar(26) DEFAULT NULL,
+  CreateAt bigint DEFAULT NULL,
+  UpdateAt bigint DEFAULT NULL,
+  DeleteAt bigint DEFAULT NULL,
+  TeamId varchar(26) DEFAULT NULL,
+  Name varchar(64) DEFAULT NULL,
+  Description varchar(255) DEFAULT NULL,
+  UserId varchar(26) DEFAULT NULL,
+  PRIMARY KEY (Id),
+  UNIQUE (Name, TeamId)
+);
+
+CREATE INDEX idx_user_groups_team_id ON UserGroups (TeamId);
+CREATE INDEX idx_user_groups_update_at ON UserGroups (UpdateAt);
+CREATE INDEX idx_user_groups_create_at ON UserGroups (CreateAt);
+CREATE INDEX idx_user_groups_delete_at ON UserGroups (DeleteAt);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS UserGroups;
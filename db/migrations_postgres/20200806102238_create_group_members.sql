@@ -0,0 +1,15 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE GroupMembers (
+  GroupId varchar(26) NOT NULL,
+  UserId varchar(26) NOT NULL,
+  Type varchar(26) DEFAULT NULL,
+  PRIMARY KEY (GroupId, UserId)
+);
+
+CREATE INDEX idx_groupmembers_group_id ON GroupMembers (GroupId);
+CREATE INDEX idx_groupmembers_user_id ON GroupMembers (UserId);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS GroupMembers;
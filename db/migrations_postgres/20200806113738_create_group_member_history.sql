@@ -0,0 +1,13 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE GroupMemberHistory (
+  GroupId varchar(26) NOT NULL,
+  UserId varchar(26) NOT NULL,
+  JoinTime bigint NOT NULL,
+  LeaveTime bigint DEFAULT NULL,
+  PRIMARY KEY (GroupId, UserId, JoinTime)
+);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS GroupMemberHistory;
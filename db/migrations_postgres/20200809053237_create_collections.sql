@@ -0,0 +1,23 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE Collections (
+  Id varchar(26) NOT NULL,
+  CreateAt bigint DEFAULT NULL,
+  UpdateAt bigint DEFAULT NULL,
+  DeleteAt bigint DEFAULT NULL,
+  TeamId varchar(26) DEFAULT NULL,
+  Title text,
+  Description varchar(255) DEFAULT NULL,
+  UserId varchar(26) DEFAULT NULL,
+  PRIMARY KEY (Id)
+);
+
+CREATE INDEX idx_collections_team_id ON Collections (TeamId);
+CREATE INDEX idx_collections_create_at ON Collections (CreateAt);
+CREATE INDEX idx_collections_update_at ON Collections (UpdateAt);
+CREATE INDEX idx_collections_delete_at ON Collections (DeleteAt);
+CREATE INDEX idx_collections_title_txt ON Collections USING GIN (to_tsvector('simple', COALESCE(Title, '')));
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS Collections;
@@ -0,0 +1,14 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE CollectionPosts (
+  CollectionId varchar(26) NOT NULL,
+  PostId varchar(26) NOT NULL,
+  PRIMARY KEY (CollectionId, PostId)
+);
+
+CREATE INDEX idx_collectionposts_collection_id ON CollectionPosts (CollectionId);
+CREATE INDEX idx_collectionposts_post_id ON CollectionPosts (PostId);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS CollectionPosts;
@@ -0,0 +1,21 @@
+-- +goose Up
+-- TODO: TeamIdを用意、
+-- teamに所属している限りはteam関連のログが本人も見れるが、
+-- 脱退すれば本人は見れなくなり、team adminなら見れる。
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE Audits (
+  Id varchar(26) NOT NULL,
+  CreateAt bigint DEFAULT NULL,
+  UserId varchar(26) DEFAULT NULL,
+  Action text,
+  ExtraInfo text,
+  IpAddress varchar(64) DEFAULT NULL,
+  SessionId varchar(26) DEFAULT NULL,
+  PRIMARY KEY (Id)
+);
+
+CREATE INDEX idx_audits_user_id ON Audits (UserId);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS Audits;
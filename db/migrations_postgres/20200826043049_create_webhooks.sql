@@ -0,0 +1,28 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE Webhooks (
+  Id varchar(26) NOT NULL,
+  Token varchar(26) DEFAULT NULL,
+  UserId varchar(26) DEFAULT NULL,
+  TeamId varchar(26) DEFAULT NULL,
+  QuestionEvents boolean DEFAULT NULL,
+  AnswerEvents boolean DEFAULT NULL,
+  CommentEvents boolean DEFAULT NULL,
+  URLs text,
+  Name varchar(64) DEFAULT NULL,
+  Description varchar(255) DEFAULT NULL,
+  ContentType varchar(128) DEFAULT NULL,
+  CreateAt bigint DEFAULT NULL,
+  UpdateAt bigint DEFAULT NULL,
+  DeleteAt bigint DEFAULT NULL,
+  PRIMARY KEY (Id)
+);
+
+CREATE INDEX idx_webhook_team_id ON Webhooks (TeamId);
+CREATE INDEX idx_webhook_create_at ON Webhooks (CreateAt);
+CREATE INDEX idx_webhook_update_at ON Webhooks (UpdateAt);
+CREATE INDEX idx_webhook_delete_at ON Webhooks (DeleteAt);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS Webhooks;
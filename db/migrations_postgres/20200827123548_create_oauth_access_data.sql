@@ -0,0 +1,21 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE OAuthAccessData (
+  ClientId varchar(26) DEFAULT NULL,
+  UserId varchar(26) DEFAULT NULL,
+  Token varchar(26) NOT NULL,
+  RefreshToken varchar(26) DEFAULT NULL,
+  RedirectUri text,
+  ExpiresAt bigint DEFAULT NULL,
+  Scope varchar(128) DEFAULT NULL,
+  PRIMARY KEY (Token),
+  UNIQUE (ClientId, UserId)
+);
+
+CREATE INDEX idx_oauthaccessdata_client_id ON OAuthAccessData (ClientId);
+CREATE INDEX idx_oauthaccessdata_user_id ON OAuthAccessData (UserId);
+CREATE INDEX idx_oauthaccessdata_refresh_token ON OAuthAccessData (RefreshToken);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS OAuthAccessData;
@@ -0,0 +1,17 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE OAuthAuthData (
+  ClientId varchar(26) DEFAULT NULL,
+  UserId varchar(26) DEFAULT NULL,
+  Code varchar(128) NOT NULL,
+  ExpiresIn integer DEFAULT NULL,
+  CreateAt bigint DEFAULT NULL,
+  RedirectUri text,
+  State text,
+  Scope varchar(128) DEFAULT NULL,
+  PRIMARY KEY (Code)
+);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS OAuthAuthData;
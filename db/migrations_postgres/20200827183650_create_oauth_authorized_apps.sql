@@ -0,0 +1,12 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE OAuthAuthorizedApps (
+  UserId varchar(26) NOT NULL,
+  ClientId varchar(26) NOT NULL,
+  Scope varchar(128) DEFAULT NULL,
+  PRIMARY KEY (UserId, ClientId)
+);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS OAuthAuthorizedApps;
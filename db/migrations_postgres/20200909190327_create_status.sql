@@ -0,0 +1,16 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE Status (
+  UserId varchar(26) NOT NULL,
+  Status varchar(32) DEFAULT NULL,
+  Manual boolean DEFAULT NULL,
+  LastActivityAt bigint DEFAULT NULL,
+  PRIMARY KEY (UserId)
+);
+
+CREATE INDEX idx_status_user_id ON Status (UserId);
+CREATE INDEX idx_status_status ON Status (Status);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS Status;
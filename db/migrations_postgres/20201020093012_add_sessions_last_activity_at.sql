@@ -0,0 +1,8 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+ALTER TABLE Sessions ADD COLUMN LastActivityAt bigint DEFAULT NULL;
+UPDATE Sessions SET LastActivityAt = CreateAt;
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+ALTER TABLE Sessions DROP COLUMN LastActivityAt;
@@ -0,0 +1,11 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+ALTER TABLE Users ADD COLUMN AuthService varchar(32) NOT NULL DEFAULT '';
+ALTER TABLE Users ADD COLUMN AuthData varchar(128) DEFAULT NULL;
+ALTER TABLE Users ADD CONSTRAINT idx_users_auth_data_unique UNIQUE (AuthData);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+ALTER TABLE Users DROP CONSTRAINT idx_users_auth_data_unique;
+ALTER TABLE Users DROP COLUMN AuthData;
+ALTER TABLE Users DROP COLUMN AuthService;
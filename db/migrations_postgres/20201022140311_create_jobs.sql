@@ -0,0 +1,21 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE Jobs (
+  Id varchar(26) NOT NULL,
+  Type varchar(32) DEFAULT NULL,
+  TeamId varchar(26) DEFAULT NULL,
+  UserId varchar(26) DEFAULT NULL,
+  Status varchar(32) DEFAULT NULL,
+  Progress bigint DEFAULT NULL,
+  CreateAt bigint DEFAULT NULL,
+  StartAt bigint DEFAULT NULL,
+  LastActivityAt bigint DEFAULT NULL,
+  Data text,
+  PRIMARY KEY (Id)
+);
+
+CREATE INDEX idx_jobs_team_id_type_status ON Jobs (TeamId, Type, Status);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS Jobs;
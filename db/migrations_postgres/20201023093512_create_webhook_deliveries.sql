@@ -0,0 +1,37 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE WebhookDeliveries (
+  Id varchar(26) NOT NULL,
+  WebhookId varchar(26) NOT NULL,
+  TeamId varchar(26) DEFAULT NULL,
+  PostId varchar(26) DEFAULT NULL,
+  URL text,
+  ContentType varchar(128) DEFAULT NULL,
+  RequestBody text,
+  Status varchar(32) DEFAULT NULL,
+  Attempts integer NOT NULL DEFAULT 0,
+  NextAttemptAt bigint DEFAULT NULL,
+  CreateAt bigint DEFAULT NULL,
+  UpdateAt bigint DEFAULT NULL,
+  PRIMARY KEY (Id)
+);
+
+CREATE INDEX idx_webhook_deliveries_status_next_attempt_at ON WebhookDeliveries (Status, NextAttemptAt);
+
+ALTER TABLE Webhooks ADD COLUMN FailureCount integer NOT NULL DEFAULT 0;
+ALTER TABLE Webhooks ADD COLUMN DisabledAt bigint NOT NULL DEFAULT 0;
+
+ALTER TABLE WebhooksHistory ADD COLUMN DeliveryId varchar(26) DEFAULT NULL;
+ALTER TABLE WebhooksHistory ADD COLUMN Attempt integer NOT NULL DEFAULT 0;
+ALTER TABLE WebhooksHistory ADD COLUMN Latency bigint NOT NULL DEFAULT 0;
+ALTER TABLE WebhooksHistory ADD COLUMN Error text;
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+ALTER TABLE WebhooksHistory DROP COLUMN Error;
+ALTER TABLE WebhooksHistory DROP COLUMN Latency;
+ALTER TABLE WebhooksHistory DROP COLUMN Attempt;
+ALTER TABLE WebhooksHistory DROP COLUMN DeliveryId;
+ALTER TABLE Webhooks DROP COLUMN DisabledAt;
+ALTER TABLE Webhooks DROP COLUMN FailureCount;
+DROP TABLE IF EXISTS WebhookDeliveries;
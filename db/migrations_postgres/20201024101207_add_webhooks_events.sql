@@ -0,0 +1,22 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+ALTER TABLE Webhooks ADD COLUMN Events text;
+UPDATE Webhooks SET Events = '[' || CONCAT_WS(',',
+  CASE WHEN QuestionEvents THEN '"question_created"' END,
+  CASE WHEN AnswerEvents THEN '"answer_created"' END,
+  CASE WHEN CommentEvents THEN '"comment_created"' END
+) || ']';
+ALTER TABLE Webhooks DROP COLUMN QuestionEvents;
+ALTER TABLE Webhooks DROP COLUMN AnswerEvents;
+ALTER TABLE Webhooks DROP COLUMN CommentEvents;
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+ALTER TABLE Webhooks ADD COLUMN QuestionEvents boolean DEFAULT NULL;
+ALTER TABLE Webhooks ADD COLUMN AnswerEvents boolean DEFAULT NULL;
+ALTER TABLE Webhooks ADD COLUMN CommentEvents boolean DEFAULT NULL;
+UPDATE Webhooks SET
+  QuestionEvents = Events LIKE '%"question_created"%',
+  AnswerEvents = Events LIKE '%"answer_created"%',
+  CommentEvents = Events LIKE '%"comment_created"%';
+ALTER TABLE Webhooks DROP COLUMN Events;
@@ -0,0 +1,15 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+ALTER TABLE WebhooksHistory ADD COLUMN RequestHeaders text;
+ALTER TABLE WebhooksHistory ADD COLUMN ResponseHeaders text;
+CREATE INDEX idx_webhooks_history_team_id_create_at ON WebhooksHistory (TeamId, CreateAt);
+CREATE INDEX idx_webhooks_history_webhook_id ON WebhooksHistory (WebhookId);
+CREATE INDEX idx_webhooks_history_create_at ON WebhooksHistory (CreateAt);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP INDEX IF EXISTS idx_webhooks_history_create_at;
+DROP INDEX IF EXISTS idx_webhooks_history_webhook_id;
+DROP INDEX IF EXISTS idx_webhooks_history_team_id_create_at;
+ALTER TABLE WebhooksHistory DROP COLUMN ResponseHeaders;
+ALTER TABLE WebhooksHistory DROP COLUMN RequestHeaders;
@@ -0,0 +1,39 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE Bots (
+  UserId varchar(26) NOT NULL,
+  TeamId varchar(26) NOT NULL,
+  DisplayName varchar(256) DEFAULT NULL,
+  Description text,
+  OwnerId varchar(26) DEFAULT NULL,
+  CreateAt bigint DEFAULT NULL,
+  UpdateAt bigint DEFAULT NULL,
+  DeleteAt bigint NOT NULL DEFAULT 0,
+  PRIMARY KEY (UserId)
+);
+
+CREATE INDEX idx_bots_team_id_delete_at ON Bots (TeamId, DeleteAt);
+
+CREATE TABLE IncomingWebhooks (
+  Id varchar(26) NOT NULL,
+  Token varchar(26) NOT NULL,
+  TeamId varchar(26) NOT NULL,
+  GroupId varchar(26) DEFAULT NULL,
+  BotUserId varchar(26) NOT NULL,
+  CreatorId varchar(26) DEFAULT NULL,
+  Name varchar(64) DEFAULT NULL,
+  Description varchar(255) DEFAULT NULL,
+  CreateAt bigint DEFAULT NULL,
+  UpdateAt bigint DEFAULT NULL,
+  DeleteAt bigint NOT NULL DEFAULT 0,
+  PRIMARY KEY (Id)
+);
+
+CREATE UNIQUE INDEX idx_incoming_webhooks_token ON IncomingWebhooks (Token);
+CREATE INDEX idx_incoming_webhooks_team_id_delete_at ON IncomingWebhooks (TeamId, DeleteAt);
+CREATE INDEX idx_incoming_webhooks_bot_user_id ON IncomingWebhooks (BotUserId);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS IncomingWebhooks;
+DROP TABLE IF EXISTS Bots;
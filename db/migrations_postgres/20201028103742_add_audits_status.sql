@@ -0,0 +1,17 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+ALTER TABLE Audits
+  ADD COLUMN Status varchar(32) DEFAULT NULL,
+  ADD COLUMN ApiPath varchar(255) DEFAULT NULL,
+  ADD COLUMN Client text;
+CREATE INDEX idx_audits_create_at ON Audits (CreateAt);
+CREATE INDEX idx_audits_ip_address ON Audits (IpAddress);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP INDEX IF EXISTS idx_audits_ip_address;
+DROP INDEX IF EXISTS idx_audits_create_at;
+ALTER TABLE Audits
+  DROP COLUMN Client,
+  DROP COLUMN ApiPath,
+  DROP COLUMN Status;
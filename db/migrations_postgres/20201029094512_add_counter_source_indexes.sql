@@ -0,0 +1,11 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE INDEX idx_votes_post_id_type ON Votes (PostId, Type);
+CREATE INDEX idx_user_point_history_user_id_team_id ON UserPointHistory (UserId, TeamId);
+CREATE INDEX idx_post_views_history_post_id ON PostViewsHistory (PostId);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP INDEX IF EXISTS idx_post_views_history_post_id;
+DROP INDEX IF EXISTS idx_user_point_history_user_id_team_id;
+DROP INDEX IF EXISTS idx_votes_post_id_type;
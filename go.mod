@@ -17,7 +17,7 @@ require (
 	github.com/gorilla/mux v1.7.4
 	github.com/gorilla/websocket v1.4.2
 	github.com/jasonlvhit/gocron v0.0.0-20191228163020-98b59b546dee
-	github.com/lib/pq v1.7.0 // indirect
+	github.com/lib/pq v1.7.0
 	github.com/mattermost/mattermost-server/v5 v5.26.2
 	github.com/mattn/go-colorable v0.1.7 // indirect
 	github.com/mattn/go-runewidth v0.0.9 // indirect
@@ -29,6 +29,7 @@ require (
 	github.com/pkg/errors v0.9.1
 	github.com/pkg/profile v0.0.0-20170413231811-06b906832ed0 // indirect
 	github.com/pressly/goose v2.6.0+incompatible
+	github.com/prometheus/client_golang v1.7.1
 	github.com/rs/cors v1.7.0
 	github.com/rwcarlsen/goexif v0.0.0-20190401172101-9e8deecbddbd
 	github.com/spf13/cobra v1.0.0
@@ -39,6 +40,10 @@ require (
 	github.com/tinylib/msgp v1.1.2
 	github.com/vmihailenco/msgpack/v5 v5.0.0-beta.1
 	github.com/ziutek/mymysql v1.5.4 // indirect
+	go.opentelemetry.io/otel v0.13.0
+	go.opentelemetry.io/otel/exporters/otlp v0.13.0
+	go.opentelemetry.io/otel/exporters/stdout v0.13.0
+	go.opentelemetry.io/otel/sdk v0.13.0
 	go.starlark.net v0.0.0-20200901195727-6e684ef5eeee // indirect
 	go.uber.org/zap v1.15.0
 	golang.org/x/arch v0.0.0-20200826200359-b19915210f00 // indirect
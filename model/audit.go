@@ -13,6 +13,10 @@ type Audit struct {
 	ExtraInfo string `json:"extra_info"`
 	IpAddress string `json:"ip_address"`
 	SessionId string `json:"session_id"`
+	// success / attempt / fail
+	Status  string `json:"status"`
+	ApiPath string `json:"api_path"`
+	Client  string `json:"client"`
 }
 
 func (o *Audit) ToJson() string {
@@ -25,3 +29,14 @@ func AuditFromJson(data io.Reader) *Audit {
 	json.NewDecoder(data).Decode(&o)
 	return o
 }
+
+type SearchAuditsOptions struct {
+	UserId    string
+	Action    string
+	Status    string
+	IpAddress string
+	FromDate  int64
+	ToDate    int64
+	Page      int
+	PerPage   int
+}
@@ -0,0 +1,121 @@
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"unicode/utf8"
+)
+
+const (
+	BOT_DISPLAY_NAME_MAX_RUNES = 64
+	BOT_DESCRIPTION_MAX_RUNES  = 255
+	// botはメールを受け取らないので、ユーザー名から一意なアドレスを作る
+	BOT_EMAIL_DOMAIN = "@bot.localhost"
+)
+
+// Type が bot の User に付随する情報。botは作成したteamに属し、team adminが管理する。
+type Bot struct {
+	UserId      string `db:"UserId, primarykey" json:"user_id"`
+	TeamId      string `db:"TeamId" json:"team_id"`
+	Username    string `db:"-" json:"username"`
+	DisplayName string `db:"DisplayName" json:"display_name"`
+	Description string `db:"Description" json:"description"`
+	OwnerId     string `db:"OwnerId" json:"owner_id"`
+	CreateAt    int64  `db:"CreateAt" json:"create_at"`
+	UpdateAt    int64  `db:"UpdateAt" json:"update_at"`
+	DeleteAt    int64  `db:"DeleteAt" json:"delete_at"`
+}
+
+type BotPatch struct {
+	DisplayName *string `json:"display_name"`
+	Description *string `json:"description"`
+}
+
+func (o *Bot) ToJson() string {
+	b, _ := json.Marshal(o)
+	return string(b)
+}
+
+func BotFromJson(data io.Reader) *Bot {
+	var o *Bot
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
+func BotListToJson(list []*Bot) string {
+	b, _ := json.Marshal(list)
+	return string(b)
+}
+
+func BotPatchFromJson(data io.Reader) *BotPatch {
+	var o *BotPatch
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
+func (o *Bot) PreSave() {
+	o.DisplayName = SanitizeUnicode(o.DisplayName)
+	o.Description = SanitizeUnicode(o.Description)
+	o.CreateAt = GetMillis()
+	o.UpdateAt = o.CreateAt
+	o.DeleteAt = 0
+}
+
+func (o *Bot) PreUpdate() {
+	o.DisplayName = SanitizeUnicode(o.DisplayName)
+	o.Description = SanitizeUnicode(o.Description)
+	o.UpdateAt = GetMillis()
+}
+
+func (o *Bot) Patch(patch *BotPatch) {
+	if patch.DisplayName != nil {
+		o.DisplayName = *patch.DisplayName
+	}
+
+	if patch.Description != nil {
+		o.Description = *patch.Description
+	}
+}
+
+func (o *Bot) IsValid() *AppError {
+	if len(o.UserId) != 26 {
+		return NewAppError("Bot.IsValid", "model.bot.is_valid.user_id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if len(o.TeamId) != 26 {
+		return NewAppError("Bot.IsValid", "model.bot.is_valid.team_id.app_error", nil, "user_id="+o.UserId, http.StatusBadRequest)
+	}
+
+	if len(o.OwnerId) != 26 {
+		return NewAppError("Bot.IsValid", "model.bot.is_valid.owner_id.app_error", nil, "user_id="+o.UserId, http.StatusBadRequest)
+	}
+
+	if o.DisplayName == "" || utf8.RuneCountInString(o.DisplayName) > BOT_DISPLAY_NAME_MAX_RUNES {
+		return NewAppError("Bot.IsValid", "model.bot.is_valid.display_name.app_error", nil, "user_id="+o.UserId, http.StatusBadRequest)
+	}
+
+	if utf8.RuneCountInString(o.Description) > BOT_DESCRIPTION_MAX_RUNES {
+		return NewAppError("Bot.IsValid", "model.bot.is_valid.description.app_error", nil, "user_id="+o.UserId, http.StatusBadRequest)
+	}
+
+	if o.CreateAt == 0 {
+		return NewAppError("Bot.IsValid", "model.bot.is_valid.create_at.app_error", nil, "user_id="+o.UserId, http.StatusBadRequest)
+	}
+
+	if o.UpdateAt == 0 {
+		return NewAppError("Bot.IsValid", "model.bot.is_valid.update_at.app_error", nil, "user_id="+o.UserId, http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+// botの実体となるユーザー。パスワードを持たず、ログインには使えない
+func UserFromBot(b *Bot) *User {
+	return &User{
+		Type:          USER_TYPE_BOT,
+		Username:      b.Username,
+		Email:         NormalizeEmail(b.Username + BOT_EMAIL_DOMAIN),
+		EmailVerified: true,
+	}
+}
@@ -1,11 +1,14 @@
 package model
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"mime/multipart"
 	"net/http"
+	"net/url"
 	"regexp"
 	"strconv"
 	"strings"
@@ -13,6 +16,7 @@ import (
 
 const (
 	HEADER_REQUEST_ID         = "X-Request-ID"
+	HEADER_TRACE_ID           = "X-Trace-ID"
 	HEADER_VERSION_ID         = "X-Version-ID"
 	HEADER_REQUESTED_WITH     = "X-Requested-With"
 	HEADER_REQUESTED_WITH_XML = "XMLHttpRequest"
@@ -91,6 +95,31 @@ func closeBody(r *http.Response) {
 	}
 }
 
+// 一覧APIが返す次のページのカーソル。続きが無ければ空
+func (r *Response) NextCursor() string {
+	if r.Header == nil {
+		return ""
+	}
+	return r.Header.Get(HEADER_NEXT_CURSOR)
+}
+
+func (r *Response) PrevCursor() string {
+	if r.Header == nil {
+		return ""
+	}
+	return r.Header.Get(HEADER_PREV_CURSOR)
+}
+
+// cursorが空の場合は先頭のページを取得する
+func cursorQuery(cursor string, perPage int) string {
+	values := url.Values{}
+	values.Set("per_page", strconv.Itoa(perPage))
+	if cursor != "" {
+		values.Set("cursor", cursor)
+	}
+	return "?" + values.Encode()
+}
+
 func BuildResponse(r *http.Response) *Response {
 	return &Response{
 		StatusCode:    r.StatusCode,
@@ -261,16 +290,20 @@ func (c *Client) DoApiPut(url string, data string) (*http.Response, *AppError) {
 }
 
 func (c *Client) DoApiRequest(method, url, data string) (*http.Response, *AppError) {
-	return c.doApiRequestReader(method, url, strings.NewReader(data))
+	return c.doApiRequestReader(method, url, strings.NewReader(data), "")
 }
 
-func (c *Client) doApiRequestReader(method, url string, data io.Reader) (*http.Response, *AppError) {
+func (c *Client) doApiRequestReader(method, url string, data io.Reader, contentType string) (*http.Response, *AppError) {
 	rq, err := http.NewRequest(method, url, data)
 
 	if err != nil {
 		return nil, NewAppError(url, "model.client.connecting.app_error", nil, err.Error(), http.StatusBadRequest)
 	}
 
+	if len(contentType) > 0 {
+		rq.Header.Set("Content-Type", contentType)
+	}
+
 	if len(c.AuthToken) > 0 {
 		rq.Header.Set(HEADER_AUTH, c.AuthType+" "+c.AuthToken)
 	}
@@ -379,6 +412,20 @@ func (c *Client) GetQuestionsForUser(userId string) (*PostsWithCount, *Response)
 	return PostsWithCountFromJson(r.Body), BuildResponse(r)
 }
 
+func (c *Client) GetQuestionsForUserPage(userId string, sort string, cursor string, perPage int) (*PostsWithCount, *Response) {
+	query := cursorQuery(cursor, perPage)
+	if sort != "" {
+		query += "&sort=" + url.QueryEscape(sort)
+	}
+
+	r, err := c.DoApiGet(c.GetUserRoute(userId) + "/posts/questions" + query)
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+	return PostsWithCountFromJson(r.Body), BuildResponse(r)
+}
+
 func (c *Client) GetAnswersForUser(userId string) (*PostsWithCount, *Response) {
 	r, err := c.DoApiGet(c.GetUserRoute(userId) + "/posts/answers")
 	if err != nil {
@@ -459,6 +506,15 @@ func (c *Client) GetInboxMessagesForUser(userId string) (InboxMessages, *Respons
 	return InboxMessagesFromJson(r.Body), BuildResponse(r)
 }
 
+func (c *Client) GetInboxMessagesForUserPage(userId string, cursor string, perPage int) (InboxMessages, *Response) {
+	r, err := c.DoApiGet(c.GetUserRoute(userId) + c.GetInboxMessagesRoute() + cursorQuery(cursor, perPage))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+	return InboxMessagesFromJson(r.Body), BuildResponse(r)
+}
+
 func (c *Client) GetInboxMessagesUnreadCountForUser(userId string) (int, *Response) {
 	r, err := c.DoApiGet(c.GetUserRoute(userId) + c.GetInboxMessagesRoute() + "/unread_count")
 	if err != nil {
@@ -498,6 +554,15 @@ func (c *Client) GetUserFavoritePosts(userId string) (*UserFavoritePostsWithCoun
 	return UserFavoritePostsWithCountFromJson(r.Body), BuildResponse(r)
 }
 
+func (c *Client) GetUserFavoritePostsPage(userId string, cursor string, perPage int) (*UserFavoritePostsWithCount, *Response) {
+	r, err := c.DoApiGet(c.GetUserRoute(userId) + "/user_favorite_posts" + cursorQuery(cursor, perPage))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+	return UserFavoritePostsWithCountFromJson(r.Body), BuildResponse(r)
+}
+
 func (c *Client) GetUserVotes(userId string) (*VotesWithCount, *Response) {
 	r, err := c.DoApiGet(c.GetUserRoute(userId) + "/votes")
 	if err != nil {
@@ -507,6 +572,15 @@ func (c *Client) GetUserVotes(userId string) (*VotesWithCount, *Response) {
 	return VotesWithCountFromJson(r.Body), BuildResponse(r)
 }
 
+func (c *Client) GetUserVotesPage(userId string, cursor string, perPage int) (*VotesWithCount, *Response) {
+	r, err := c.DoApiGet(c.GetUserRoute(userId) + "/votes" + cursorQuery(cursor, perPage))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+	return VotesWithCountFromJson(r.Body), BuildResponse(r)
+}
+
 func (c *Client) GetNotificationSettingForUser(userId string) (*NotificationSetting, *Response) {
 	r, err := c.DoApiGet(c.GetUserRoute(userId) + "/notification_setting")
 	if err != nil {
@@ -541,6 +615,300 @@ func (c *Client) Logout() (bool, *Response) {
 	return CheckStatusOK(r), BuildResponse(r)
 }
 
+func (c *Client) GetSessions(userId string) ([]*Session, *Response) {
+	r, err := c.DoApiGet(c.GetUserRoute(userId) + "/sessions")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+	return SessionsFromJson(r.Body), BuildResponse(r)
+}
+
+func (c *Client) RevokeSession(userId, sessionId string) (bool, *Response) {
+	requestBody := map[string]string{"session_id": sessionId}
+	r, err := c.DoApiPost(c.GetUserRoute(userId)+"/sessions/revoke", MapToJson(requestBody))
+	if err != nil {
+		return false, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+	return CheckStatusOK(r), BuildResponse(r)
+}
+
+func (c *Client) RevokeAllSessionsExceptCurrent(userId string) (bool, *Response) {
+	r, err := c.DoApiPost(c.GetUserRoute(userId)+"/sessions/revoke/all", "")
+	if err != nil {
+		return false, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+	return CheckStatusOK(r), BuildResponse(r)
+}
+
+func (c *Client) GetTeamRoute(teamId string) string {
+	return fmt.Sprintf("/teams/%v", teamId)
+}
+
+func (c *Client) ExportTeam(teamId string) (*Job, *Response) {
+	r, err := c.DoApiPost(c.GetTeamRoute(teamId)+"/export", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+	return JobFromJson(r.Body), BuildResponse(r)
+}
+
+func (c *Client) ImportTeam(teamId string, data []byte, filename string, dryRun bool) (*Job, *Response) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	part, err := writer.CreateFormFile("file", filename)
+	if err != nil {
+		return nil, &Response{Error: NewAppError("ImportTeam", "model.client.import_team.file.app_error", nil, err.Error(), http.StatusBadRequest)}
+	}
+
+	if _, err = io.Copy(part, bytes.NewReader(data)); err != nil {
+		return nil, &Response{Error: NewAppError("ImportTeam", "model.client.import_team.file.app_error", nil, err.Error(), http.StatusBadRequest)}
+	}
+
+	if err = writer.WriteField("dry_run", strconv.FormatBool(dryRun)); err != nil {
+		return nil, &Response{Error: NewAppError("ImportTeam", "model.client.import_team.writer.app_error", nil, err.Error(), http.StatusBadRequest)}
+	}
+
+	if err = writer.Close(); err != nil {
+		return nil, &Response{Error: NewAppError("ImportTeam", "model.client.import_team.writer.app_error", nil, err.Error(), http.StatusBadRequest)}
+	}
+
+	r, appErr := c.doApiRequestReader(http.MethodPost, c.ApiUrl+c.GetTeamRoute(teamId)+"/import", body, writer.FormDataContentType())
+	if appErr != nil {
+		return nil, BuildErrorResponse(r, appErr)
+	}
+	defer closeBody(r)
+	return JobFromJson(r.Body), BuildResponse(r)
+}
+
+func (c *Client) GetTeamJob(teamId, jobId string) (*Job, *Response) {
+	r, err := c.DoApiGet(c.GetTeamRoute(teamId) + "/jobs/" + jobId)
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+	return JobFromJson(r.Body), BuildResponse(r)
+}
+
+func (c *Client) GetHooksRoute() string {
+	return "/hooks"
+}
+
+func (c *Client) GetHookRoute(hookId string) string {
+	return fmt.Sprintf(c.GetHooksRoute()+"/%v", hookId)
+}
+
+func (c *Client) CreateWebhook(hook *Webhook) (*Webhook, *Response) {
+	r, err := c.DoApiPost(c.GetHooksRoute(), hook.ToJson())
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+	return WebhookFromJson(r.Body), BuildResponse(r)
+}
+
+func (c *Client) GetWebhookEvents() ([]*WebhookEventType, *Response) {
+	r, err := c.DoApiGet(c.GetHooksRoute() + "/events")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+	var events []*WebhookEventType
+	json.NewDecoder(r.Body).Decode(&events)
+	return events, BuildResponse(r)
+}
+
+func (c *Client) PreviewWebhook(hook *Webhook, event string) (*WebhookPreview, *Response) {
+	r, err := c.DoApiPost(c.GetHooksRoute()+"/preview?event="+url.QueryEscape(event), hook.ToJson())
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+	return WebhookPreviewFromJson(r.Body), BuildResponse(r)
+}
+
+func (c *Client) TestWebhook(hookId string) ([]*WebhookDelivery, *Response) {
+	r, err := c.DoApiPost(c.GetHookRoute(hookId)+"/test", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+	var deliveries []*WebhookDelivery
+	json.NewDecoder(r.Body).Decode(&deliveries)
+	return deliveries, BuildResponse(r)
+}
+
+func (c *Client) GetHookHistoryRoute(historyId string) string {
+	return fmt.Sprintf(c.GetHooksRoute()+"/history/%v", historyId)
+}
+
+func (c *Client) GetWebhookHistory(historyId string) (*WebhooksHistory, *Response) {
+	r, err := c.DoApiGet(c.GetHookHistoryRoute(historyId))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+	return WebhooksHistoryFromJson(r.Body), BuildResponse(r)
+}
+
+func (c *Client) RedeliverWebhook(historyId string) (*WebhookDelivery, *Response) {
+	r, err := c.DoApiPost(c.GetHookHistoryRoute(historyId)+"/redeliver", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+	return WebhookDeliveryFromJson(r.Body), BuildResponse(r)
+}
+
+func (c *Client) GetIncomingHooksRoute() string {
+	return c.GetHooksRoute() + "/incoming"
+}
+
+func (c *Client) CreateIncomingWebhook(hook *IncomingWebhook) (*IncomingWebhook, *Response) {
+	r, err := c.DoApiPost(c.GetIncomingHooksRoute(), hook.ToJson())
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+	return IncomingWebhookFromJson(r.Body), BuildResponse(r)
+}
+
+func (c *Client) ExecuteIncomingWebhook(token string, req *IncomingWebhookRequest) (*Post, *Response) {
+	r, err := c.DoApiPost(c.GetIncomingHooksRoute()+"/execute/"+token, req.ToJson())
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+	return PostFromJson(r.Body), BuildResponse(r)
+}
+
+func (c *Client) GetBotsRoute() string {
+	return "/bots"
+}
+
+func (c *Client) GetBotRoute(botUserId string) string {
+	return fmt.Sprintf(c.GetBotsRoute()+"/%v", botUserId)
+}
+
+func (c *Client) CreateBot(bot *Bot) (*Bot, *Response) {
+	r, err := c.DoApiPost(c.GetBotsRoute(), bot.ToJson())
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+	return BotFromJson(r.Body), BuildResponse(r)
+}
+
+func (c *Client) GetBot(botUserId string) (*Bot, *Response) {
+	r, err := c.DoApiGet(c.GetBotRoute(botUserId))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+	return BotFromJson(r.Body), BuildResponse(r)
+}
+
+func (c *Client) GetAuditsRoute() string {
+	return "/audits"
+}
+
+func (c *Client) SearchAudits(options *SearchAuditsOptions) (Audits, *Response) {
+	values := url.Values{}
+	values.Set("page", strconv.Itoa(options.Page))
+	values.Set("per_page", strconv.Itoa(options.PerPage))
+	if options.UserId != "" {
+		values.Set("user_id", options.UserId)
+	}
+	if options.Action != "" {
+		values.Set("action", options.Action)
+	}
+	if options.Status != "" {
+		values.Set("status", options.Status)
+	}
+	if options.IpAddress != "" {
+		values.Set("ip_address", options.IpAddress)
+	}
+	if options.FromDate != 0 {
+		values.Set("from_date", strconv.FormatInt(options.FromDate, 10))
+	}
+	if options.ToDate != 0 {
+		values.Set("to_date", strconv.FormatInt(options.ToDate, 10))
+	}
+
+	r, err := c.DoApiGet(c.GetAuditsRoute() + "?" + values.Encode())
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+	return AuditsFromJson(r.Body), BuildResponse(r)
+}
+
+func (c *Client) GetSystemRoute() string {
+	return "/system"
+}
+
+func (c *Client) GetSystemStatus() (*SystemStatus, *Response) {
+	r, err := c.DoApiGet(c.GetSystemRoute() + "/status")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+	return SystemStatusFromJson(r.Body), BuildResponse(r)
+}
+
+func (c *Client) ReconcileCounters(dryRun bool) (*Job, *Response) {
+	r, err := c.DoApiPost(c.GetSystemRoute()+"/jobs/reconcile_counters?dry_run="+strconv.FormatBool(dryRun), "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+	return JobFromJson(r.Body), BuildResponse(r)
+}
+
+func (c *Client) GetSystemJob(jobId string) (*Job, *Response) {
+	r, err := c.DoApiGet(c.GetSystemRoute() + "/jobs/" + jobId)
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+	return JobFromJson(r.Body), BuildResponse(r)
+}
+
+func (c *Client) GetConfigRoute() string {
+	return "/config"
+}
+
+func (c *Client) GetConfig() (*Config, *Response) {
+	r, err := c.DoApiGet(c.GetConfigRoute())
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+	return ConfigFromJson(r.Body), BuildResponse(r)
+}
+
+func (c *Client) UpdateConfig(config *Config) (*Config, *Response) {
+	r, err := c.DoApiPut(c.GetConfigRoute(), config.ToJson())
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+	return ConfigFromJson(r.Body), BuildResponse(r)
+}
+
+func (c *Client) ReloadConfig() (bool, *Response) {
+	r, err := c.DoApiPost(c.GetConfigRoute()+"/reload", "")
+	if err != nil {
+		return false, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+	return CheckStatusOK(r), BuildResponse(r)
+}
+
 // CheckStatusOK is a convenience function for checking the standard OK response
 // from the web service.
 func CheckStatusOK(r *http.Response) bool {
@@ -558,6 +926,24 @@ func (c *Client) GetPostRoute(postId string) string {
 	return fmt.Sprintf(c.GetPostsRoute()+"/%v", postId)
 }
 
+func (c *Client) ViewPost(postId string) (bool, *Response) {
+	r, err := c.DoApiPost(c.GetPostRoute(postId)+"/view", "")
+	if err != nil {
+		return false, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+	return CheckStatusOK(r), BuildResponse(r)
+}
+
+func (c *Client) GetPostDailyViews(postId string, days int) (Analytics, *Response) {
+	r, err := c.DoApiGet(c.GetPostRoute(postId) + "/views/daily?days=" + strconv.Itoa(days))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+	return AnalyticsFromJson(r.Body), BuildResponse(r)
+}
+
 func (c *Client) DoApiGet(url string) (*http.Response, *AppError) {
 	return c.DoApiRequest(http.MethodGet, c.ApiUrl+url, "")
 }
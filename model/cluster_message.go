@@ -11,13 +11,19 @@ const (
 
 	// L1キャッシュの削除をクラスタ全体に周知する場合。
 	CLUSTER_EVENT_CLEAR_SESSION_CACHE_FOR_USER = "clear_user_session"
+
+	// 設定の保存先から読み直させる場合。
+	CLUSTER_EVENT_CONFIG_CHANGED = "config_changed"
+
+	// 全てのL1キャッシュの削除をクラスタ全体に周知する場合。
+	CLUSTER_EVENT_INVALIDATE_ALL_CACHES = "invalidate_all_caches"
 )
 
 type ClusterMessage struct {
 	// 自サーバーのインスタンスID
 	// を指定する事で自分以外のサーバー達だけに処理させる
 	OmitCluster string `json:"omit_cluster"`
-	// websocket, clear_user_session のいずれか
+	// websocket, clear_user_session, config_changed, invalidate_all_caches のいずれか
 	Event string `json:"event"`
 	// WebSocketEventのjson形式 または user_id
 	Data string `json:"data,omitempty"`
@@ -2,6 +2,7 @@ package model
 
 import (
 	"encoding/json"
+	"io"
 	"math"
 	"net"
 	"net/http"
@@ -11,6 +12,7 @@ import (
 
 const (
 	DATABASE_DRIVER_MYSQL            = "mysql"
+	DATABASE_DRIVER_POSTGRES         = "postgres"
 	SQL_SETTINGS_DEFAULT_DATA_SOURCE = "root:@tcp(localhost:3306)/qa_discussion?charset=utf8mb4,utf8\u0026readTimeout=30s\u0026writeTimeout=30s"
 
 	CONN_SECURITY_NONE = ""
@@ -35,6 +37,34 @@ const (
 	CACHE_SETTINGS_DEFAULT_ENDPOINT   = "http://localhost:6379"
 	CLUSTER_SETTINGS_DEFAULT_ENDPOINT = "127.0.0.1:6379"
 	SEARCH_SETTINGS_DEFAULT_ENDPOINT  = "http://localhost:9200"
+
+	OPENID_SETTINGS_DEFAULT_SCOPE = "openid profile email"
+
+	WEBHOOK_SETTINGS_DEFAULT_MAX_ATTEMPTS             = 8
+	WEBHOOK_SETTINGS_DEFAULT_INITIAL_RETRY_INTERVAL   = 30
+	WEBHOOK_SETTINGS_DEFAULT_MAX_RETRY_INTERVAL       = 3600
+	WEBHOOK_SETTINGS_DEFAULT_MAX_CONSECUTIVE_FAILURES = 20
+	WEBHOOK_SETTINGS_DEFAULT_REQUEST_TIMEOUT          = 10
+	WEBHOOK_SETTINGS_DEFAULT_POLL_INTERVAL            = 5
+	WEBHOOK_SETTINGS_DEFAULT_HISTORY_RETENTION_DAYS   = 30
+
+	AUDIT_SETTINGS_DEFAULT_QUEUE_SIZE       = 1000
+	AUDIT_SETTINGS_DEFAULT_FILE_NAME        = "./logs/audit.log"
+	AUDIT_SETTINGS_DEFAULT_FILE_MAX_SIZE_MB = 100
+	AUDIT_SETTINGS_DEFAULT_FILE_MAX_BACKUPS = 10
+	AUDIT_SETTINGS_DEFAULT_SYSLOG_TAG       = "qa-discussion"
+
+	METRICS_SETTINGS_DEFAULT_LISTEN_ADDRESS = ":8067"
+
+	POST_VIEWS_SETTINGS_DEFAULT_FLUSH_INTERVAL = 60
+	POST_VIEWS_SETTINGS_DEFAULT_RETENTION_DAYS = 30
+
+	TRACING_EXPORTER_OTLP   = "otlp"
+	TRACING_EXPORTER_STDOUT = "stdout"
+
+	TRACING_SETTINGS_DEFAULT_OTLP_ENDPOINT = "localhost:55680"
+	TRACING_SETTINGS_DEFAULT_SERVICE_NAME  = "qa-discussion"
+	TRACING_SETTINGS_DEFAULT_SAMPLE_RATE   = 1.0
 )
 
 type ServiceSettings struct {
@@ -55,6 +85,7 @@ type ServiceSettings struct {
 	WebserverMode                       *string `restricted:"true"`
 	SessionLengthWebInDays              *int
 	SessionLengthOAuthInDays            *int `restricted:"true"`
+	ExtendSessionLengthWithActivity     *bool
 	TrustedProxyIPHeader                []string
 	AllowedUntrustedInternalConnections *string `restricted:"true"`
 	UserStatusAwayTimeout               *int64
@@ -140,6 +171,10 @@ func (s *ServiceSettings) SetDefaults() {
 		s.SessionLengthOAuthInDays = NewInt(30)
 	}
 
+	if s.ExtendSessionLengthWithActivity == nil {
+		s.ExtendSessionLengthWithActivity = NewBool(true)
+	}
+
 	if s.TrustedProxyIPHeader == nil {
 		s.TrustedProxyIPHeader = []string{HEADER_FORWARDED, HEADER_REAL_IP}
 	}
@@ -263,7 +298,15 @@ type SqlSettings struct {
 	ConnMaxLifetimeMilliseconds *int
 	MaxOpenConns                *int
 	Trace                       *bool
-	QueryTimeout                *int
+	// 1文ごとのタイムアウトの秒数。接続時にDBのセッション変数として設定する
+	QueryTimeout *int
+	// 遅延がこの秒数を超えたレプリカは読み込みに使わない。0なら遅延を監視しない
+	MaxReplicaLagSeconds           *int
+	ReplicaLagCheckIntervalSeconds *int
+	// 書き込んだユーザーの読み込みを、この秒数の間masterから行う。0なら無効
+	ReadYourWritesSeconds *int
+	// trueならサーバーの起動時に未適用のスキーマのマイグレーションを適用する
+	AutoMigrate *bool
 }
 
 func (s *SqlSettings) SetDefaults() {
@@ -298,10 +341,26 @@ func (s *SqlSettings) SetDefaults() {
 	if s.QueryTimeout == nil {
 		s.QueryTimeout = NewInt(30)
 	}
+
+	if s.MaxReplicaLagSeconds == nil {
+		s.MaxReplicaLagSeconds = NewInt(10)
+	}
+
+	if s.ReplicaLagCheckIntervalSeconds == nil {
+		s.ReplicaLagCheckIntervalSeconds = NewInt(5)
+	}
+
+	if s.ReadYourWritesSeconds == nil {
+		s.ReadYourWritesSeconds = NewInt(5)
+	}
+
+	if s.AutoMigrate == nil {
+		s.AutoMigrate = NewBool(false)
+	}
 }
 
 func (ss *SqlSettings) isValid() *AppError {
-	if !(*ss.DriverName == DATABASE_DRIVER_MYSQL) {
+	if !(*ss.DriverName == DATABASE_DRIVER_MYSQL || *ss.DriverName == DATABASE_DRIVER_POSTGRES) {
 		return NewAppError("Config.IsValid", "model.config.is_valid.sql_driver.app_error", nil, "", http.StatusBadRequest)
 	}
 
@@ -325,6 +384,18 @@ func (ss *SqlSettings) isValid() *AppError {
 		return NewAppError("Config.IsValid", "model.config.is_valid.sql_max_conn.app_error", nil, "", http.StatusBadRequest)
 	}
 
+	if *ss.MaxReplicaLagSeconds < 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.sql_max_replica_lag.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if *ss.ReplicaLagCheckIntervalSeconds <= 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.sql_replica_lag_check_interval.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if *ss.ReadYourWritesSeconds < 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.sql_read_your_writes.app_error", nil, "", http.StatusBadRequest)
+	}
+
 	return nil
 }
 
@@ -507,7 +578,7 @@ func (rls *RateLimitSettings) isValid() *AppError {
 
 type EmailSettings struct {
 	AmazonSESAccessKeyId     *string
-	AmazonSESSecretAccessKey *string
+	AmazonSESSecretAccessKey *string `restricted:"true"`
 	AmazonSESRegion          *string
 	SupportEmail             *string
 }
@@ -581,9 +652,335 @@ func (s *ClusterSettings) isValid() *AppError {
 	return nil
 }
 
+type OpenIdSettings struct {
+	Enable        *bool
+	Issuer        *string
+	ClientId      *string
+	ClientSecret  *string `restricted:"true"`
+	Scope         *string
+	IdClaim       *string
+	UsernameClaim *string
+	EmailClaim    *string
+	AutoJoinTeams *bool
+}
+
+func (s *OpenIdSettings) SetDefaults() {
+	if s.Enable == nil {
+		s.Enable = NewBool(false)
+	}
+
+	if s.Issuer == nil {
+		s.Issuer = NewString("")
+	}
+
+	if s.ClientId == nil {
+		s.ClientId = NewString("")
+	}
+
+	if s.ClientSecret == nil {
+		s.ClientSecret = NewString("")
+	}
+
+	if s.Scope == nil {
+		s.Scope = NewString(OPENID_SETTINGS_DEFAULT_SCOPE)
+	}
+
+	if s.IdClaim == nil {
+		s.IdClaim = NewString("sub")
+	}
+
+	if s.UsernameClaim == nil {
+		s.UsernameClaim = NewString("preferred_username")
+	}
+
+	if s.EmailClaim == nil {
+		s.EmailClaim = NewString("email")
+	}
+
+	if s.AutoJoinTeams == nil {
+		s.AutoJoinTeams = NewBool(false)
+	}
+}
+
+func (s *OpenIdSettings) isValid() *AppError {
+	if !*s.Enable {
+		return nil
+	}
+
+	if len(*s.Issuer) == 0 || !IsValidHttpUrl(*s.Issuer) {
+		return NewAppError("Config.IsValid", "model.config.is_valid.openid_issuer.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if len(*s.ClientId) == 0 || len(*s.ClientSecret) == 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.openid_client.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if len(*s.IdClaim) == 0 || len(*s.EmailClaim) == 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.openid_claims.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+type WebhookSettings struct {
+	MaxAttempts *int
+	// 秒単位。失敗するたびに倍になり、MaxRetryIntervalSecondsで頭打ちになる
+	InitialRetryIntervalSeconds *int
+	MaxRetryIntervalSeconds     *int
+	// 連続でこの回数失敗したwebhookは自動で無効化される
+	MaxConsecutiveFailures *int
+	RequestTimeoutSeconds  *int
+	PollIntervalSeconds    *int
+	// これより古い送信履歴は定期的に削除される。0の場合は削除しない
+	HistoryRetentionDays *int
+}
+
+func (s *WebhookSettings) SetDefaults() {
+	if s.MaxAttempts == nil {
+		s.MaxAttempts = NewInt(WEBHOOK_SETTINGS_DEFAULT_MAX_ATTEMPTS)
+	}
+
+	if s.InitialRetryIntervalSeconds == nil {
+		s.InitialRetryIntervalSeconds = NewInt(WEBHOOK_SETTINGS_DEFAULT_INITIAL_RETRY_INTERVAL)
+	}
+
+	if s.MaxRetryIntervalSeconds == nil {
+		s.MaxRetryIntervalSeconds = NewInt(WEBHOOK_SETTINGS_DEFAULT_MAX_RETRY_INTERVAL)
+	}
+
+	if s.MaxConsecutiveFailures == nil {
+		s.MaxConsecutiveFailures = NewInt(WEBHOOK_SETTINGS_DEFAULT_MAX_CONSECUTIVE_FAILURES)
+	}
+
+	if s.RequestTimeoutSeconds == nil {
+		s.RequestTimeoutSeconds = NewInt(WEBHOOK_SETTINGS_DEFAULT_REQUEST_TIMEOUT)
+	}
+
+	if s.PollIntervalSeconds == nil {
+		s.PollIntervalSeconds = NewInt(WEBHOOK_SETTINGS_DEFAULT_POLL_INTERVAL)
+	}
+
+	if s.HistoryRetentionDays == nil {
+		s.HistoryRetentionDays = NewInt(WEBHOOK_SETTINGS_DEFAULT_HISTORY_RETENTION_DAYS)
+	}
+}
+
+func (s *WebhookSettings) isValid() *AppError {
+	if *s.MaxAttempts <= 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.webhook_max_attempts.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if *s.InitialRetryIntervalSeconds <= 0 || *s.MaxRetryIntervalSeconds < *s.InitialRetryIntervalSeconds {
+		return NewAppError("Config.IsValid", "model.config.is_valid.webhook_retry_interval.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if *s.MaxConsecutiveFailures <= 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.webhook_max_consecutive_failures.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if *s.RequestTimeoutSeconds <= 0 || *s.PollIntervalSeconds <= 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.webhook_timeout.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if *s.HistoryRetentionDays < 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.webhook_history_retention.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+type PostViewsSettings struct {
+	// 秒単位。Redisに溜めた閲覧数をこの間隔でPosts.Viewsに書き出す
+	FlushIntervalSeconds *int
+	// 日ごとのユニーク閲覧数(HyperLogLog)をRedisに残す日数
+	RetentionDays *int
+}
+
+func (s *PostViewsSettings) SetDefaults() {
+	if s.FlushIntervalSeconds == nil {
+		s.FlushIntervalSeconds = NewInt(POST_VIEWS_SETTINGS_DEFAULT_FLUSH_INTERVAL)
+	}
+
+	if s.RetentionDays == nil {
+		s.RetentionDays = NewInt(POST_VIEWS_SETTINGS_DEFAULT_RETENTION_DAYS)
+	}
+}
+
+func (s *PostViewsSettings) isValid() *AppError {
+	if *s.FlushIntervalSeconds <= 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.post_views_flush_interval.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if *s.RetentionDays <= 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.post_views_retention.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+type AuditSettings struct {
+	QueueSize *int
+	// Auditsテーブルに保存する
+	EnableDatabase *bool
+	// 1行1JSONでファイルに追記する
+	EnableFile *bool
+	FileName   *string
+	// MB単位。これを超えるとローテーションされる
+	FileMaxSizeMB  *int
+	FileMaxBackups *int
+	EnableSyslog   *bool
+	// 空の場合はローカルのsyslogに送る。リモートの場合は "udp" や "tcp" を指定する
+	SyslogNetwork *string
+	SyslogAddress *string
+	SyslogTag     *string
+}
+
+func (s *AuditSettings) SetDefaults() {
+	if s.QueueSize == nil {
+		s.QueueSize = NewInt(AUDIT_SETTINGS_DEFAULT_QUEUE_SIZE)
+	}
+
+	if s.EnableDatabase == nil {
+		s.EnableDatabase = NewBool(true)
+	}
+
+	if s.EnableFile == nil {
+		s.EnableFile = NewBool(false)
+	}
+
+	if s.FileName == nil {
+		s.FileName = NewString(AUDIT_SETTINGS_DEFAULT_FILE_NAME)
+	}
+
+	if s.FileMaxSizeMB == nil {
+		s.FileMaxSizeMB = NewInt(AUDIT_SETTINGS_DEFAULT_FILE_MAX_SIZE_MB)
+	}
+
+	if s.FileMaxBackups == nil {
+		s.FileMaxBackups = NewInt(AUDIT_SETTINGS_DEFAULT_FILE_MAX_BACKUPS)
+	}
+
+	if s.EnableSyslog == nil {
+		s.EnableSyslog = NewBool(false)
+	}
+
+	if s.SyslogNetwork == nil {
+		s.SyslogNetwork = NewString("")
+	}
+
+	if s.SyslogAddress == nil {
+		s.SyslogAddress = NewString("")
+	}
+
+	if s.SyslogTag == nil {
+		s.SyslogTag = NewString(AUDIT_SETTINGS_DEFAULT_SYSLOG_TAG)
+	}
+}
+
+func (s *AuditSettings) isValid() *AppError {
+	if *s.QueueSize <= 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.audit_queue_size.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if *s.EnableFile && (len(*s.FileName) == 0 || *s.FileMaxSizeMB <= 0 || *s.FileMaxBackups < 0) {
+		return NewAppError("Config.IsValid", "model.config.is_valid.audit_file.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if *s.EnableSyslog && len(*s.SyslogNetwork) != 0 && len(*s.SyslogAddress) == 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.audit_syslog.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+type MetricsSettings struct {
+	Enable *bool
+	// APIとは別のポートで /metrics を公開する
+	ListenAddress *string
+}
+
+func (s *MetricsSettings) SetDefaults() {
+	if s.Enable == nil {
+		s.Enable = NewBool(false)
+	}
+
+	if s.ListenAddress == nil {
+		s.ListenAddress = NewString(METRICS_SETTINGS_DEFAULT_LISTEN_ADDRESS)
+	}
+}
+
+func (s *MetricsSettings) isValid() *AppError {
+	if *s.Enable && len(*s.ListenAddress) == 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.metrics_listen_address.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+type TracingSettings struct {
+	Enable *bool
+	// otlp または stdout(ローカルでの確認用)
+	Exporter     *string
+	OTLPEndpoint *string
+	OTLPInsecure *bool
+	ServiceName  *string
+	// 0.0〜1.0 の割合でリクエストをサンプリングする
+	SampleRate *float64
+}
+
+func (s *TracingSettings) SetDefaults() {
+	if s.Enable == nil {
+		s.Enable = NewBool(false)
+	}
+
+	if s.Exporter == nil {
+		s.Exporter = NewString(TRACING_EXPORTER_OTLP)
+	}
+
+	if s.OTLPEndpoint == nil {
+		s.OTLPEndpoint = NewString(TRACING_SETTINGS_DEFAULT_OTLP_ENDPOINT)
+	}
+
+	if s.OTLPInsecure == nil {
+		s.OTLPInsecure = NewBool(true)
+	}
+
+	if s.ServiceName == nil {
+		s.ServiceName = NewString(TRACING_SETTINGS_DEFAULT_SERVICE_NAME)
+	}
+
+	if s.SampleRate == nil {
+		s.SampleRate = NewFloat64(TRACING_SETTINGS_DEFAULT_SAMPLE_RATE)
+	}
+}
+
+func (s *TracingSettings) isValid() *AppError {
+	if !*s.Enable {
+		return nil
+	}
+
+	switch *s.Exporter {
+	case TRACING_EXPORTER_OTLP:
+		if len(*s.OTLPEndpoint) == 0 {
+			return NewAppError("Config.IsValid", "model.config.is_valid.tracing_otlp_endpoint.app_error", nil, "", http.StatusBadRequest)
+		}
+	case TRACING_EXPORTER_STDOUT:
+	default:
+		return NewAppError("Config.IsValid", "model.config.is_valid.tracing_exporter.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if *s.SampleRate < 0 || *s.SampleRate > 1 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.tracing_sample_rate.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+// restricted:"true" のセクション・項目は管理APIから読み書きさせない。
+// SqlSettingsは接続文字列にパスワードを含むので、セクションごと扱わせない。
 type Config struct {
 	ServiceSettings       ServiceSettings
-	SqlSettings           SqlSettings
+	SqlSettings           SqlSettings `restricted:"true"`
 	CacheSettings         CacheSettings
 	SearchSettings        SearchSettings
 	TeamSettings          TeamSettings
@@ -593,6 +990,12 @@ type Config struct {
 	EmailBatchJobSettings EmailBatchJobSettings
 	EmailSettings         EmailSettings
 	ClusterSettings       ClusterSettings
+	OpenIdSettings        OpenIdSettings
+	WebhookSettings       WebhookSettings
+	PostViewsSettings     PostViewsSettings
+	AuditSettings         AuditSettings
+	MetricsSettings       MetricsSettings
+	TracingSettings       TracingSettings
 }
 
 func (o *Config) ToJson() string {
@@ -600,6 +1003,12 @@ func (o *Config) ToJson() string {
 	return string(b)
 }
 
+func ConfigFromJson(data io.Reader) *Config {
+	var o *Config
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
 func (o *Config) Clone() *Config {
 	var ret Config
 	if err := json.Unmarshal([]byte(o.ToJson()), &ret); err != nil {
@@ -620,6 +1029,12 @@ func (o *Config) SetDefaults() {
 	o.EmailBatchJobSettings.SetDefaults()
 	o.EmailSettings.SetDefaults()
 	o.ClusterSettings.SetDefaults()
+	o.OpenIdSettings.SetDefaults()
+	o.WebhookSettings.SetDefaults()
+	o.PostViewsSettings.SetDefaults()
+	o.AuditSettings.SetDefaults()
+	o.MetricsSettings.SetDefaults()
+	o.TracingSettings.SetDefaults()
 }
 
 func (o *Config) IsValid() *AppError {
@@ -667,5 +1082,29 @@ func (o *Config) IsValid() *AppError {
 		return err
 	}
 
+	if err := o.OpenIdSettings.isValid(); err != nil {
+		return err
+	}
+
+	if err := o.WebhookSettings.isValid(); err != nil {
+		return err
+	}
+
+	if err := o.PostViewsSettings.isValid(); err != nil {
+		return err
+	}
+
+	if err := o.AuditSettings.isValid(); err != nil {
+		return err
+	}
+
+	if err := o.MetricsSettings.isValid(); err != nil {
+		return err
+	}
+
+	if err := o.TracingSettings.isValid(); err != nil {
+		return err
+	}
+
 	return nil
 }
@@ -0,0 +1,49 @@
+package model
+
+import (
+	"encoding/json"
+)
+
+const (
+	COUNTER_TABLE_POSTS        = "Posts"
+	COUNTER_TABLE_TAGS         = "Tags"
+	COUNTER_TABLE_USERS        = "Users"
+	COUNTER_TABLE_TEAM_MEMBERS = "TeamMembers"
+
+	COUNTER_COLUMN_ANSWER_COUNT = "AnswerCount"
+	COUNTER_COLUMN_UP_VOTES     = "UpVotes"
+	COUNTER_COLUMN_DOWN_VOTES   = "DownVotes"
+	COUNTER_COLUMN_POINTS       = "Points"
+	COUNTER_COLUMN_FLAG_COUNT   = "FlagCount"
+	COUNTER_COLUMN_VIEWS        = "Views"
+	COUNTER_COLUMN_POST_COUNT   = "PostCount"
+)
+
+// 非正規化したカウンタの値と、元のテーブルから計算し直した値のずれ。
+// KeyはPostsとUsersではId、TeamMembersではUserId、TagsではContent。
+type CounterDrift struct {
+	Table    string `json:"table"`
+	TeamId   string `json:"team_id,omitempty"`
+	Key      string `json:"key"`
+	Column   string `json:"column"`
+	Actual   int64  `json:"actual"`
+	Expected int64  `json:"expected"`
+}
+
+// キーセットページングの位置。TagsとTeamMembersはTeamIdとの複合キーで進める
+type CounterCursor struct {
+	TeamId string `json:"team_id"`
+	Key    string `json:"key"`
+}
+
+type CounterDriftBatch struct {
+	Checked int
+	Drifts  []*CounterDrift
+	// 次のバッチの開始位置。nilなら最後まで照合した
+	Next *CounterCursor
+}
+
+func (d *CounterDrift) ToJson() string {
+	b, _ := json.Marshal(d)
+	return string(b)
+}
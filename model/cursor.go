@@ -0,0 +1,174 @@
+package model
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+)
+
+const (
+	CURSOR_TYPE_POSTS            = "posts"
+	CURSOR_TYPE_INBOX_MESSAGES   = "inbox_messages"
+	CURSOR_TYPE_VOTES            = "votes"
+	CURSOR_TYPE_FAVORITE_POSTS   = "favorite_posts"
+	CURSOR_TYPE_TEAM_MEMBERS     = "team_members"
+	CURSOR_TYPE_GROUP_MEMBERS    = "group_members"
+	CURSOR_TYPE_COLLECTIONS      = "collections"
+	CURSOR_TYPE_WEBHOOKS_HISTORY = "webhooks_history"
+
+	HEADER_NEXT_CURSOR = "X-Next-Cursor"
+	HEADER_PREV_CURSOR = "X-Prev-Cursor"
+)
+
+// 一覧の種類ごとの、数値の並びのキーに続くIdの数
+var cursorIdCounts = map[string]int{
+	CURSOR_TYPE_POSTS:            1,
+	CURSOR_TYPE_INBOX_MESSAGES:   1,
+	CURSOR_TYPE_VOTES:            2,
+	CURSOR_TYPE_FAVORITE_POSTS:   1,
+	CURSOR_TYPE_TEAM_MEMBERS:     1,
+	CURSOR_TYPE_GROUP_MEMBERS:    1,
+	CURSOR_TYPE_COLLECTIONS:      1,
+	CURSOR_TYPE_WEBHOOKS_HISTORY: 1,
+}
+
+// 並びのキーとIdで一覧の位置を表す。クライアントには中身を見せず、
+// EncodeしたものをそのままNext/Prevとして渡す。
+type Cursor struct {
+	Type string `json:"t"`
+	// 作成時と違う並び順では使えない
+	Sort string `json:"s,omitempty"`
+	// CreateAtやPointsなど、数値の並びのキー
+	Key int64 `json:"k,omitempty"`
+	// 並びのキーが同じ行を区別するId。文字列の並びのキーも含む
+	Ids []string `json:"i,omitempty"`
+	// 関連度順はキーで絞れないので、先頭からの位置で表す
+	Offset int `json:"o,omitempty"`
+	// trueならこの位置より前のページを表す
+	Before bool `json:"b,omitempty"`
+}
+
+type CursorPage struct {
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+}
+
+func NewCursor(cursorType string, sort string, key int64, ids ...string) *Cursor {
+	return &Cursor{
+		Type: cursorType,
+		Sort: sort,
+		Key:  key,
+		Ids:  ids,
+	}
+}
+
+func NewOffsetCursor(cursorType string, sort string, offset int) *Cursor {
+	return &Cursor{
+		Type:   cursorType,
+		Sort:   sort,
+		Offset: offset,
+	}
+}
+
+func (c *Cursor) Encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func (c *Cursor) IsOffset() bool {
+	return c.Type == CURSOR_TYPE_POSTS && c.Sort == POST_SORT_TYPE_RELEVANCE
+}
+
+func (c *Cursor) reverse() *Cursor {
+	reversed := *c
+	reversed.Before = true
+	return &reversed
+}
+
+// 一覧の種類と並び順が一致しないカーソルは、他の一覧から持ち込まれたものとして弾く
+func DecodeCursor(encoded string, cursorType string, sort string) (*Cursor, *AppError) {
+	invalid := func(details string) *AppError {
+		return NewAppError("DecodeCursor", "model.cursor.decode.app_error", nil, details, http.StatusBadRequest)
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, invalid(err.Error())
+	}
+
+	var cursor *Cursor
+	if err := json.Unmarshal(b, &cursor); err != nil || cursor == nil {
+		return nil, invalid("malformed cursor")
+	}
+
+	if cursor.Type != cursorType || cursor.Sort != sort {
+		return nil, invalid("type=" + cursor.Type + ", sort=" + cursor.Sort)
+	}
+
+	if cursor.IsOffset() {
+		if len(cursor.Ids) != 0 || cursor.Offset < 0 || cursor.Before {
+			return nil, invalid("invalid offset cursor")
+		}
+		return cursor, nil
+	}
+
+	idCount := cursorIdCounts[cursorType]
+	if cursorType == CURSOR_TYPE_TEAM_MEMBERS && sort == TEAM_MEMBER_SORT_TYPE_USERNAME {
+		// ユーザー名とUserIdで並べる
+		idCount = 2
+	}
+
+	if len(cursor.Ids) != idCount || cursor.Offset != 0 {
+		return nil, invalid("invalid keys")
+	}
+
+	return cursor, nil
+}
+
+// 取得したcount件の先頭と末尾の行から前後のページのカーソルを作る。
+// limit件取れた場合は続きがあるとみなすので、最後のページの次は空のページになることがある。
+// hasPrevはオフセットで2ページ目以降を取得した場合にtrueにする。
+func NewCursorPage(count int, limit int, cursor *Cursor, hasPrev bool, rowCursor func(i int) *Cursor) CursorPage {
+	page := CursorPage{}
+	if count == 0 {
+		return page
+	}
+
+	first := rowCursor(0).reverse()
+	last := rowCursor(count - 1)
+
+	if cursor != nil && cursor.Before {
+		// カーソルの位置から前に戻ってきたので、次のページは必ずある
+		page.NextCursor = last.Encode()
+		if count >= limit {
+			page.PrevCursor = first.Encode()
+		}
+		return page
+	}
+
+	if count >= limit {
+		page.NextCursor = last.Encode()
+	}
+	if cursor != nil || hasPrev {
+		page.PrevCursor = first.Encode()
+	}
+
+	return page
+}
+
+// 関連度順など、キーで絞れない並び順のページのカーソル
+func NewOffsetCursorPage(count int, limit int, offset int, cursorType string, sort string) CursorPage {
+	page := CursorPage{}
+	if count >= limit {
+		page.NextCursor = NewOffsetCursor(cursorType, sort, offset+count).Encode()
+	}
+	if offset > 0 {
+		prev := offset - limit
+		if prev < 0 {
+			prev = 0
+		}
+		page.PrevCursor = NewOffsetCursor(cursorType, sort, prev).Encode()
+	}
+
+	return page
+}
@@ -0,0 +1,69 @@
+package model
+
+import (
+	"encoding/json"
+	"io"
+)
+
+const (
+	HEALTH_STATUS_OK       = "ok"
+	HEALTH_STATUS_FAIL     = "fail"
+	HEALTH_STATUS_DISABLED = "disabled"
+
+	HEALTH_CHECK_MYSQL_MASTER  = "mysql_master"
+	HEALTH_CHECK_MYSQL_REPLICA = "mysql_replica"
+	HEALTH_CHECK_REDIS_CACHE   = "redis_cache"
+	HEALTH_CHECK_REDIS_CLUSTER = "redis_cluster"
+	HEALTH_CHECK_ELASTICSEARCH = "elasticsearch"
+	HEALTH_CHECK_FILE_BACKEND  = "file_backend"
+	HEALTH_CHECK_MAIL_BACKEND  = "mail_backend"
+)
+
+type DependencyStatus struct {
+	Name string `json:"name"`
+	// ok / fail / disabled
+	Status    string `json:"status"`
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+type HealthStatus struct {
+	Status       string              `json:"status"`
+	Dependencies []*DependencyStatus `json:"dependencies"`
+}
+
+func (o *HealthStatus) IsReady() bool {
+	return o.Status == HEALTH_STATUS_OK
+}
+
+func (o *HealthStatus) ToJson() string {
+	b, _ := json.Marshal(o)
+	return string(b)
+}
+
+func HealthStatusFromJson(data io.Reader) *HealthStatus {
+	var o *HealthStatus
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
+type SystemStatus struct {
+	Version       string        `json:"version"`
+	ClusterId     string        `json:"cluster_id"`
+	ConfigHash    string        `json:"config_hash"`
+	Goroutines    int           `json:"goroutines"`
+	StartAt       int64         `json:"start_at"`
+	UptimeSeconds int64         `json:"uptime_seconds"`
+	Health        *HealthStatus `json:"health"`
+}
+
+func (o *SystemStatus) ToJson() string {
+	b, _ := json.Marshal(o)
+	return string(b)
+}
+
+func SystemStatusFromJson(data io.Reader) *SystemStatus {
+	var o *SystemStatus
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
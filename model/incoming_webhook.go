@@ -0,0 +1,143 @@
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+const (
+	INCOMING_WEBHOOK_NAME_MAX_LENGTH        = 64
+	INCOMING_WEBHOOK_DESCRIPTION_MAX_LENGTH = 255
+)
+
+// 外部から投稿を作るためのwebhook。tokenを知っていればセッション無しで実行でき、
+// 投稿はBotUserIdのbotの名前で作られる。
+type IncomingWebhook struct {
+	Id     string `db:"Id, primarykey" json:"id"`
+	Token  string `db:"Token" json:"token"`
+	TeamId string `db:"TeamId" json:"team_id"`
+	// 指定された場合、作られた質問はこのgroupのメンバーに通知される
+	GroupId     string `db:"GroupId" json:"group_id"`
+	BotUserId   string `db:"BotUserId" json:"bot_user_id"`
+	CreatorId   string `db:"CreatorId" json:"creator_id"`
+	Name        string `db:"Name" json:"name"`
+	Description string `db:"Description" json:"description"`
+	CreateAt    int64  `db:"CreateAt" json:"create_at"`
+	UpdateAt    int64  `db:"UpdateAt" json:"update_at"`
+	DeleteAt    int64  `db:"DeleteAt" json:"delete_at"`
+}
+
+func (o *IncomingWebhook) ToJson() string {
+	b, _ := json.Marshal(o)
+	return string(b)
+}
+
+func IncomingWebhookFromJson(data io.Reader) *IncomingWebhook {
+	var o *IncomingWebhook
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
+func IncomingWebhookListToJson(list []*IncomingWebhook) string {
+	b, _ := json.Marshal(list)
+	return string(b)
+}
+
+func (o *IncomingWebhook) PreSave() {
+	if o.Id == "" {
+		o.Id = NewId()
+	}
+
+	o.Token = NewId()
+
+	o.CreateAt = GetMillis()
+	o.UpdateAt = o.CreateAt
+	o.DeleteAt = 0
+}
+
+func (o *IncomingWebhook) IsValid() *AppError {
+	if len(o.Id) != 26 {
+		return NewAppError("IncomingWebhook.IsValid", "model.incoming_hook.is_valid.id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if len(o.Token) != 26 {
+		return NewAppError("IncomingWebhook.IsValid", "model.incoming_hook.is_valid.token.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if len(o.TeamId) != 26 {
+		return NewAppError("IncomingWebhook.IsValid", "model.incoming_hook.is_valid.team_id.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if len(o.GroupId) != 0 && len(o.GroupId) != 26 {
+		return NewAppError("IncomingWebhook.IsValid", "model.incoming_hook.is_valid.group_id.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if len(o.BotUserId) != 26 {
+		return NewAppError("IncomingWebhook.IsValid", "model.incoming_hook.is_valid.bot_user_id.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if len(o.CreatorId) != 26 {
+		return NewAppError("IncomingWebhook.IsValid", "model.incoming_hook.is_valid.creator_id.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if len(o.Name) > INCOMING_WEBHOOK_NAME_MAX_LENGTH {
+		return NewAppError("IncomingWebhook.IsValid", "model.incoming_hook.is_valid.name.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if len(o.Description) > INCOMING_WEBHOOK_DESCRIPTION_MAX_LENGTH {
+		return NewAppError("IncomingWebhook.IsValid", "model.incoming_hook.is_valid.description.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if o.CreateAt == 0 {
+		return NewAppError("IncomingWebhook.IsValid", "model.incoming_hook.is_valid.create_at.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if o.UpdateAt == 0 {
+		return NewAppError("IncomingWebhook.IsValid", "model.incoming_hook.is_valid.update_at.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+// 外部から送られてくる投稿内容。
+// type が question の場合は title と tags を、answer と comment の場合は parent_id を指定する。
+type IncomingWebhookRequest struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Content  string `json:"content"`
+	Tags     string `json:"tags"`
+	ParentId string `json:"parent_id"`
+}
+
+func (o *IncomingWebhookRequest) ToJson() string {
+	b, _ := json.Marshal(o)
+	return string(b)
+}
+
+func IncomingWebhookRequestFromJson(data io.Reader) (*IncomingWebhookRequest, error) {
+	var o *IncomingWebhookRequest
+	err := json.NewDecoder(data).Decode(&o)
+	return o, err
+}
+
+func (o *IncomingWebhookRequest) IsValid() *AppError {
+	switch o.Type {
+	case POST_TYPE_QUESTION:
+		if len(o.Title) == 0 {
+			return NewAppError("IncomingWebhookRequest.IsValid", "model.incoming_hook_request.is_valid.title.app_error", nil, "", http.StatusBadRequest)
+		}
+	case POST_TYPE_ANSWER, POST_TYPE_COMMENT:
+		if len(o.ParentId) != 26 {
+			return NewAppError("IncomingWebhookRequest.IsValid", "model.incoming_hook_request.is_valid.parent_id.app_error", nil, "", http.StatusBadRequest)
+		}
+	default:
+		return NewAppError("IncomingWebhookRequest.IsValid", "model.incoming_hook_request.is_valid.type.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if len(o.Content) == 0 {
+		return NewAppError("IncomingWebhookRequest.IsValid", "model.incoming_hook_request.is_valid.content.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+const (
+	JOB_TYPE_EXPORT_TEAM = "export_team"
+	JOB_TYPE_IMPORT_TEAM = "import_team"
+	// チームに属さないjobなのでTeamIdは空
+	JOB_TYPE_RECONCILE_COUNTERS = "reconcile_counters"
+
+	JOB_STATUS_PENDING     = "pending"
+	JOB_STATUS_IN_PROGRESS = "in_progress"
+	JOB_STATUS_SUCCESS     = "success"
+	JOB_STATUS_ERROR       = "error"
+
+	JOB_DATA_EXPORT_PATH      = "export_path"
+	JOB_DATA_DRY_RUN          = "dry_run"
+	JOB_DATA_IMPORT_REPORT    = "import_report"
+	JOB_DATA_RECONCILE_REPORT = "reconcile_report"
+	JOB_DATA_ERROR            = "error"
+)
+
+// export/importのように時間のかかる処理を非同期で実行し、進捗を記録する
+type Job struct {
+	Id             string    `db:"Id, primarykey" json:"id"`
+	Type           string    `db:"Type" json:"type"`
+	TeamId         string    `db:"TeamId" json:"team_id"`
+	UserId         string    `db:"UserId" json:"user_id"`
+	Status         string    `db:"Status" json:"status"`
+	Progress       int64     `db:"Progress" json:"progress"`
+	CreateAt       int64     `db:"CreateAt" json:"create_at"`
+	StartAt        int64     `db:"StartAt" json:"start_at"`
+	LastActivityAt int64     `db:"LastActivityAt" json:"last_activity_at"`
+	Data           StringMap `db:"Data" json:"data"`
+}
+
+func (j *Job) ToJson() string {
+	b, _ := json.Marshal(j)
+	return string(b)
+}
+
+func JobFromJson(data io.Reader) *Job {
+	var job *Job
+	json.NewDecoder(data).Decode(&job)
+	return job
+}
+
+func (j *Job) PreSave() {
+	if j.Id == "" {
+		j.Id = NewId()
+	}
+
+	j.CreateAt = GetMillis()
+	j.LastActivityAt = j.CreateAt
+
+	if j.Status == "" {
+		j.Status = JOB_STATUS_PENDING
+	}
+
+	if j.Data == nil {
+		j.Data = make(map[string]string)
+	}
+}
+
+func (j *Job) IsValid() *AppError {
+	if len(j.Id) != 26 {
+		return NewAppError("Job.IsValid", "model.job.is_valid.id.app_error", nil, "id="+j.Id, http.StatusBadRequest)
+	}
+
+	if j.CreateAt == 0 {
+		return NewAppError("Job.IsValid", "model.job.is_valid.create_at.app_error", nil, "id="+j.Id, http.StatusBadRequest)
+	}
+
+	switch j.Type {
+	case JOB_TYPE_EXPORT_TEAM, JOB_TYPE_IMPORT_TEAM:
+		if len(j.TeamId) != 26 {
+			return NewAppError("Job.IsValid", "model.job.is_valid.team_id.app_error", nil, "id="+j.Id, http.StatusBadRequest)
+		}
+	case JOB_TYPE_RECONCILE_COUNTERS:
+		if len(j.TeamId) != 0 {
+			return NewAppError("Job.IsValid", "model.job.is_valid.team_id.app_error", nil, "id="+j.Id, http.StatusBadRequest)
+		}
+	default:
+		return NewAppError("Job.IsValid", "model.job.is_valid.type.app_error", nil, "id="+j.Id, http.StatusBadRequest)
+	}
+
+	if len(j.UserId) != 26 {
+		return NewAppError("Job.IsValid", "model.job.is_valid.user_id.app_error", nil, "id="+j.Id, http.StatusBadRequest)
+	}
+
+	switch j.Status {
+	case JOB_STATUS_PENDING, JOB_STATUS_IN_PROGRESS, JOB_STATUS_SUCCESS, JOB_STATUS_ERROR:
+	default:
+		return NewAppError("Job.IsValid", "model.job.is_valid.status.app_error", nil, "id="+j.Id, http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+func (j *Job) IsFinished() bool {
+	return j.Status == JOB_STATUS_SUCCESS || j.Status == JOB_STATUS_ERROR
+}
@@ -28,6 +28,10 @@ var PERMISSION_READ_OTHERS_INBOX_MESSAGES *Permission
 var PERMISSION_SET_READ_OTHERS_INBOX_MESSAGES *Permission
 var PERMISSION_READ_OTHERS_USER_POINT_HISTORY *Permission
 var PERMISSION_READ_OTHERS_VOTES *Permission
+var PERMISSION_READ_AUDITS *Permission
+var PERMISSION_READ_SYSTEM_STATUS *Permission
+var PERMISSION_MANAGE_CONFIG *Permission
+var PERMISSION_MANAGE_JOBS *Permission
 var PERMISSION_FAVORITE_POST *Permission
 var PERMISSION_MANAGE_OAUTH *Permission
 var PERMISSION_LOCK_POST *Permission
@@ -39,6 +43,8 @@ var PERMISSION_CREATE_REVIEW_VOTES *Permission
 var PERMISSION_COMPLETE_REVIEW_VOTES *Permission
 
 var PERMISSION_MANAGE_TEAM *Permission
+var PERMISSION_EXPORT_TEAM *Permission
+var PERMISSION_IMPORT_TEAM *Permission
 var PERMISSION_VIEW_TEAM *Permission
 var PERMISSION_ADD_USER_TO_TEAM *Permission
 var PERMISSION_INVITE_USER_TO_TEAM *Permission
@@ -61,6 +67,7 @@ var PERMISSION_JOIN_GROUPS *Permission
 var PERMISSION_MANAGE_COLLECTION_POSTS *Permission
 var PERMISSION_MANAGE_WEBHOOKS *Permission
 var PERMISSION_MANAGE_OTHERS_WEBHOOKS *Permission
+var PERMISSION_MANAGE_BOTS *Permission
 
 var PERMISSION_MANAGE_GROUP_PROPERTIES *Permission
 var PERMISSION_MANAGE_GROUP_MEMBERS *Permission
@@ -155,6 +162,26 @@ func initializePermissions() {
 		PERMISSION_SCOPE_SYSTEM,
 	}
 
+	PERMISSION_READ_AUDITS = &Permission{
+		"read_audits",
+		PERMISSION_SCOPE_SYSTEM,
+	}
+
+	PERMISSION_READ_SYSTEM_STATUS = &Permission{
+		"read_system_status",
+		PERMISSION_SCOPE_SYSTEM,
+	}
+
+	PERMISSION_MANAGE_CONFIG = &Permission{
+		"manage_config",
+		PERMISSION_SCOPE_SYSTEM,
+	}
+
+	PERMISSION_MANAGE_JOBS = &Permission{
+		"manage_jobs",
+		PERMISSION_SCOPE_SYSTEM,
+	}
+
 	PERMISSION_FAVORITE_POST = &Permission{
 		"favorite_post",
 		PERMISSION_SCOPE_SYSTEM,
@@ -205,6 +232,16 @@ func initializePermissions() {
 		PERMISSION_SCOPE_TEAM,
 	}
 
+	PERMISSION_EXPORT_TEAM = &Permission{
+		"export_team",
+		PERMISSION_SCOPE_TEAM,
+	}
+
+	PERMISSION_IMPORT_TEAM = &Permission{
+		"import_team",
+		PERMISSION_SCOPE_TEAM,
+	}
+
 	PERMISSION_VIEW_TEAM = &Permission{
 		"view_team",
 		PERMISSION_SCOPE_TEAM,
@@ -315,6 +352,11 @@ func initializePermissions() {
 		PERMISSION_SCOPE_TEAM,
 	}
 
+	PERMISSION_MANAGE_BOTS = &Permission{
+		"manage_bots",
+		PERMISSION_SCOPE_TEAM,
+	}
+
 	PERMISSION_MANAGE_GROUP_PROPERTIES = &Permission{
 		"manage_group_properties",
 		PERMISSION_SCOPE_GROUP,
@@ -353,6 +395,10 @@ func initializePermissions() {
 		PERMISSION_SET_READ_OTHERS_INBOX_MESSAGES,
 		PERMISSION_READ_OTHERS_USER_POINT_HISTORY,
 		PERMISSION_READ_OTHERS_VOTES,
+		PERMISSION_READ_AUDITS,
+		PERMISSION_READ_SYSTEM_STATUS,
+		PERMISSION_MANAGE_CONFIG,
+		PERMISSION_MANAGE_JOBS,
 		PERMISSION_FAVORITE_POST,
 		PERMISSION_MANAGE_OAUTH,
 		PERMISSION_PROTECT_POST,
@@ -362,6 +408,8 @@ func initializePermissions() {
 		PERMISSION_CREATE_REVIEW_VOTES,
 		PERMISSION_COMPLETE_REVIEW_VOTES,
 		PERMISSION_MANAGE_TEAM,
+		PERMISSION_EXPORT_TEAM,
+		PERMISSION_IMPORT_TEAM,
 		PERMISSION_VIEW_TEAM,
 		PERMISSION_ADD_USER_TO_TEAM,
 		PERMISSION_INVITE_USER_TO_TEAM,
@@ -384,6 +432,7 @@ func initializePermissions() {
 		PERMISSION_MANAGE_COLLECTION_POSTS,
 		PERMISSION_MANAGE_WEBHOOKS,
 		PERMISSION_MANAGE_OTHERS_WEBHOOKS,
+		PERMISSION_MANAGE_BOTS,
 		PERMISSION_MANAGE_GROUP_PROPERTIES,
 		PERMISSION_MANAGE_GROUP_MEMBERS,
 		PERMISSION_MANAGE_GROUP_MEMBER_TYPE,
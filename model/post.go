@@ -175,6 +175,20 @@ func (o *Post) PreSave() {
 	o.MakeNonNil()
 }
 
+// 並び順のキー。sqlstoreのORDER BYと同じく、指定が無ければ作成日時
+func (o *Post) SortKey(sortType string) int64 {
+	switch sortType {
+	case POST_SORT_TYPE_ACTIVE:
+		return o.UpdateAt
+	case POST_SORT_TYPE_VOTES:
+		return int64(o.Points)
+	case POST_SORT_TYPE_ANSWERS:
+		return int64(o.AnswerCount)
+	default:
+		return o.CreateAt
+	}
+}
+
 func (o *Post) IsLocked() bool {
 	return o.LockedAt > 0
 }
@@ -194,6 +208,11 @@ func (o *Post) AddProp(key string, value interface{}) {
 	o.Props[key] = value
 }
 
+func (o *Post) DelProp(key string) {
+	o.MakeNonNil()
+	delete(o.Props, key)
+}
+
 func GetLink(siteURL string, postId string) string {
 	return siteURL + "/questions/" + postId
 }
@@ -225,6 +244,8 @@ type GetPostsOptions struct {
 	TeamId         string
 	IncludeDeleted bool
 	OriginalId     string
+	// 指定した場合はPageの代わりにこの位置から取得する
+	Cursor *Cursor
 }
 
 type SearchPostsOptions struct {
@@ -247,6 +268,7 @@ type SearchPostsOptions struct {
 	TeamId         string
 	IncludeDeleted bool
 	OriginalId     string
+	Cursor         *Cursor
 }
 
 func (o *GetPostsOptions) GetPostsOptionsToJson() string {
@@ -1,9 +1,6 @@
 package model
 
-const (
-	POST_COUNTER_MAX = 5
-)
-
+// 閲覧はまとめて書き出されるので、1行が複数の閲覧(ViewsCount)を表す
 type PostViewsHistory struct {
 	Id         string `db:"Id, primarykey" json:"id"`
 	PostId     string `db:"PostId" json:"post_id"`
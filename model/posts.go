@@ -57,6 +57,7 @@ func PostsFromJson(data io.Reader) Posts {
 type PostsWithCount struct {
 	Posts      Posts `json:"posts"`
 	TotalCount int64 `json:"total_count"`
+	CursorPage
 }
 
 func (o *PostsWithCount) ToJson() []byte {
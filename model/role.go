@@ -94,11 +94,14 @@ func initializeRoles() {
 					PERMISSION_MANAGE_COLLECTION_POSTS.Id,
 					PERMISSION_MANAGE_WEBHOOKS.Id,
 					PERMISSION_MANAGE_OTHERS_WEBHOOKS.Id,
+					PERMISSION_MANAGE_BOTS.Id,
 					PERMISSION_CREATE_COLLECTION.Id,
 					PERMISSION_DELETE_COLLECTION.Id,
 					PERMISSION_EDIT_OTHERS_TEAM_POSTS.Id,
 					PERMISSION_DELETE_OTHERS_TEAM_POSTS.Id,
 					PERMISSION_MANAGE_TEAM.Id,
+					PERMISSION_EXPORT_TEAM.Id,
+					PERMISSION_IMPORT_TEAM.Id,
 				},
 				ROLE_TEAM_MEMBER_TYPE_NORMAL.Permissions...,
 			),
@@ -122,6 +125,10 @@ func initializeRoles() {
 					PERMISSION_SET_READ_OTHERS_INBOX_MESSAGES.Id,
 					PERMISSION_READ_OTHERS_USER_POINT_HISTORY.Id,
 					PERMISSION_READ_OTHERS_VOTES.Id,
+					PERMISSION_READ_AUDITS.Id,
+					PERMISSION_READ_SYSTEM_STATUS.Id,
+					PERMISSION_MANAGE_CONFIG.Id,
+					PERMISSION_MANAGE_JOBS.Id,
 				},
 				ROLE_MODERATOR.Permissions...,
 			),
@@ -1,25 +1,57 @@
 package model
 
+import (
+	"encoding/json"
+	"io"
+)
+
 const (
 	SESSION_COOKIE_TOKEN  = "QAAUTHTOKEN"
 	SESSION_COOKIE_USER   = "QAUSERID"
 	SESSION_COOKIE_CSRF   = "QACSRF"
+	SESSION_COOKIE_OPENID = "QAOPENIDSTATE"
 	SESSION_PROP_PLATFORM = "platform"
 	SESSION_PROP_OS       = "os"
 	SESSION_PROP_BROWSER  = "browser"
+	SESSION_PROP_IP       = "ip_address"
+
+	// LastActivityAtを更新するのに必要な最低経過時間
+	SESSION_ACTIVITY_TIMEOUT = 1000 * 60 * 5 // 5 minutes
 
 	SESSION_CACHE_SIZE = 35000
 )
 
 type Session struct {
-	Id          string        `db:"Id, primarykey" json:"id"`
-	Token       string        `db:"Token" json:"token"`
-	CreateAt    int64         `db:"CreateAt" json:"create_at"`
-	ExpiresAt   int64         `db:"ExpiresAt" json:"expires_at"`
-	UserId      string        `db:"UserId" json:"user_id"`
-	Props       StringMap     `db:"Props" json:"props"`
-	IsOAuth     bool          `db:"IsOAuth" json:"is_oauth"`
-	TeamMembers []*TeamMember `json:"team_members" db:"-"`
+	Id             string        `db:"Id, primarykey" json:"id"`
+	Token          string        `db:"Token" json:"token"`
+	CreateAt       int64         `db:"CreateAt" json:"create_at"`
+	ExpiresAt      int64         `db:"ExpiresAt" json:"expires_at"`
+	LastActivityAt int64         `db:"LastActivityAt" json:"last_activity_at"`
+	UserId         string        `db:"UserId" json:"user_id"`
+	Props          StringMap     `db:"Props" json:"props"`
+	IsOAuth        bool          `db:"IsOAuth" json:"is_oauth"`
+	TeamMembers    []*TeamMember `json:"team_members" db:"-"`
+}
+
+func (me *Session) ToJson() string {
+	b, _ := json.Marshal(me)
+	return string(b)
+}
+
+func SessionsToJson(o []*Session) string {
+	b, _ := json.Marshal(o)
+	return string(b)
+}
+
+func SessionsFromJson(data io.Reader) []*Session {
+	var o []*Session
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
+// 他の端末のセッション一覧を返す際などに、tokenを隠すため
+func (me *Session) Sanitize() {
+	me.Token = ""
 }
 
 func (me *Session) PreSave() {
@@ -32,6 +64,7 @@ func (me *Session) PreSave() {
 	}
 
 	me.CreateAt = GetMillis()
+	me.LastActivityAt = me.CreateAt
 
 	if me.Props == nil {
 		me.Props = make(map[string]string)
@@ -0,0 +1,12 @@
+package model
+
+const (
+	// 適用したデータマイグレーションを、この接頭辞に名前を付けたNameで記録する
+	SYSTEM_DATA_MIGRATION_PREFIX = "DataMigration_"
+)
+
+// Systemsテーブルの1行で、サーバー全体で1つの値を保存する
+type System struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
@@ -48,6 +48,16 @@ type TeamMembersGetOptions struct {
 	ExcludeDeletedUsers bool
 	// member type
 	Type string
+	// 指定した場合はOffsetの代わりにこの位置から取得する
+	Cursor *Cursor
+}
+
+// カーソルの並び順。ユーザー名順以外はUserId順になる
+func (o *TeamMembersGetOptions) CursorSort() string {
+	if o.Sort == TEAM_MEMBER_SORT_TYPE_USERNAME {
+		return TEAM_MEMBER_SORT_TYPE_USERNAME
+	}
+	return ""
 }
 
 func TeamMembersToJson(o []*TeamMember) string {
@@ -16,6 +16,8 @@ const (
 	USER_TYPE_NORMAL    = "normal"
 	USER_TYPE_MODERATOR = "moderator"
 	USER_TYPE_ADMIN     = "admin"
+	// 連携用のアカウント。ログインできず、ポイントやダイジェストメールの対象外
+	USER_TYPE_BOT = "bot"
 
 	ME                       = "me"
 	USER_NAME_MAX_LENGTH     = 64
@@ -31,6 +33,9 @@ const (
 
 	USER_PROPS_SUSPEND_BY = "suspendBy"
 	USER_PROPS_DELETE_BY  = "deleteBy"
+
+	USER_AUTH_SERVICE_OPENID  = "openid"
+	USER_AUTH_DATA_MAX_LENGTH = 128
 )
 
 // TODO: user's views count:
@@ -51,6 +56,8 @@ type User struct {
 	LastInboxMessageViewed int64     `db:"LastInboxMessageViewed" json:"last_inbox_message_viewed,omitempty"`
 	LastPictureUpdate      int64     `db:"LastPictureUpdate" json:"last_picture_update,omitempty"`
 	FailedAttempts         int       `db:"FailedAttempts" json:"failed_attempts,omitempty"`
+	AuthService            string    `db:"AuthService" json:"auth_service,omitempty"`
+	AuthData               *string   `db:"AuthData" json:"auth_data,omitempty"`
 
 	QuestionCount    int64  `db:"-" json:"question_count,omitempty"`
 	AnswerCount      int64  `db:"-" json:"answer_count,omitempty"`
@@ -67,7 +74,7 @@ func (u *User) IsValid() *AppError {
 		return InvalidUserError("id", "")
 	}
 
-	if u.Type != USER_TYPE_NORMAL && u.Type != USER_TYPE_MODERATOR && u.Type != USER_TYPE_ADMIN {
+	if u.Type != USER_TYPE_NORMAL && u.Type != USER_TYPE_MODERATOR && u.Type != USER_TYPE_ADMIN && u.Type != USER_TYPE_BOT {
 		return InvalidUserError("type", u.Id)
 	}
 
@@ -91,6 +98,14 @@ func (u *User) IsValid() *AppError {
 		return InvalidUserError("password_limit", u.Id)
 	}
 
+	if u.AuthData != nil && len(*u.AuthData) > USER_AUTH_DATA_MAX_LENGTH {
+		return InvalidUserError("auth_data", u.Id)
+	}
+
+	if len(u.AuthService) > 0 && (u.AuthData == nil || len(*u.AuthData) == 0) {
+		return InvalidUserError("auth_data_type", u.Id)
+	}
+
 	return nil
 }
 
@@ -99,6 +114,9 @@ func (u *User) DeepCopy() *User {
 	if u.Props != nil {
 		copyUser.Props = CopyStringMap(u.Props)
 	}
+	if u.AuthData != nil {
+		copyUser.AuthData = NewString(*u.AuthData)
+	}
 	return &copyUser
 }
 
@@ -188,9 +206,13 @@ func (u *User) Sanitize(options map[string]bool) {
 	u.Password = ""
 	u.Props = make(map[string]string)
 	u.LastInboxMessageViewed = 0
-	u.Type = ""
+	// botの投稿であることはクライアントで表示に使うので残す
+	if !u.IsBot() {
+		u.Type = ""
+	}
 	u.LastPictureUpdate = 0
 	u.FailedAttempts = 0
+	u.AuthData = nil
 
 	if len(options) != 0 && !options["email"] {
 		u.Email = ""
@@ -215,6 +237,8 @@ func (u *User) SanitizeInput() {
 	u.LastInboxMessageViewed = 0
 	u.LastPictureUpdate = 0
 	u.FailedAttempts = 0
+	u.AuthService = ""
+	u.AuthData = nil
 }
 
 func UserFromJson(data io.Reader) *User {
@@ -244,6 +268,15 @@ func GetSuspendTimeBySpan(suspendSpan string, currentTime int64) int64 {
 	return currentTime + int64(milliSeconds)
 }
 
+// 外部IdP経由で作成されたユーザーはパスワードを持たない
+func (u *User) IsSSOUser() bool {
+	return len(u.AuthService) > 0
+}
+
+func (u *User) IsBot() bool {
+	return u.Type == USER_TYPE_BOT
+}
+
 func (u *User) IsSuspending() bool {
 	return u.SuspendTime > 0 && u.SuspendTime > GetMillis()
 }
@@ -47,6 +47,7 @@ type UserFavoritePostWithPost struct {
 type UserFavoritePostsWithCount struct {
 	UserFavoritePosts []*UserFavoritePostWithPost `json:"user_favorite_posts"`
 	TotalCount        int64                       `json:"total_count"`
+	CursorPage
 }
 
 func (o *UserFavoritePostsWithCount) ToJson() []byte {
@@ -18,6 +18,8 @@ const (
 	USER_POINT_TYPE_DOWN_VOTED_CANCELED = "down_voted_canceled"
 	USER_POINT_TYPE_FLAGGED             = "flagged"
 	USER_POINT_TYPE_FLAGGED_CANCELED    = "flagged_canceled"
+	USER_POINT_TYPE_RESTORE_QUESTION    = "restore_question"
+	USER_POINT_TYPE_RESTORE_ANSWER      = "restore_answer"
 
 	USER_POINT_FOR_CREATE_QUESTION = 3
 	USER_POINT_FOR_CREATE_ANSWER   = 3
@@ -55,12 +55,23 @@ type AppError struct {
 	StatusCode    int    `json:"status_code,omitempty"`
 	Where         string `json:"-"`
 	params        map[string]interface{}
+	wrapped       error
 }
 
 func (er *AppError) Error() string {
 	return er.Where + ": " + er.Message + ", " + er.DetailedError
 }
 
+// 元のエラーを保持し、errors.Asなどで取り出せるようにする
+func (er *AppError) Wrap(err error) *AppError {
+	er.wrapped = err
+	return er
+}
+
+func (er *AppError) Unwrap() error {
+	return er.wrapped
+}
+
 func (er *AppError) Translate() {
 	// TODO: i18n
 	if er.params == nil {
@@ -69,6 +69,7 @@ type VoteWithPost struct {
 type VotesWithCount struct {
 	Votes      []*VoteWithPost `json:"votes"`
 	TotalCount int64           `json:"total_count"`
+	CursorPage
 }
 
 func (o *VotesWithCount) ToJson() []byte {
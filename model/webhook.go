@@ -5,25 +5,30 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"net/url"
-	"strconv"
 )
 
 type Webhook struct {
-	Id             string      `db:"Id, primarykey" json:"id"`
-	Token          string      `db:"Token" json:"token"`
-	UserId         string      `db:"UserId" json:"user_id"`
-	TeamId         string      `db:"TeamId" json:"team_id"`
-	QuestionEvents bool        `db:"QuestionEvents" json:"question_events"`
-	AnswerEvents   bool        `db:"AnswerEvents" json:"answer_events"`
-	CommentEvents  bool        `db:"CommentEvents" json:"comment_events"`
-	URLs           StringArray `db:"URLs" json:"urls"`
-	Name           string      `db:"Name" json:"name"`
-	Description    string      `db:"Description" json:"description"`
-	ContentType    string      `db:"ContentType" json:"content_type"`
-	CreateAt       int64       `db:"CreateAt" json:"create_at"`
-	UpdateAt       int64       `db:"UpdateAt" json:"update_at"`
-	DeleteAt       int64       `db:"DeleteAt" json:"delete_at"`
+	Id     string `db:"Id, primarykey" json:"id"`
+	Token  string `db:"Token" json:"token"`
+	UserId string `db:"UserId" json:"user_id"`
+	TeamId string `db:"TeamId" json:"team_id"`
+	// 購読するイベント。WebhookEventCatalog のいずれか
+	Events      StringArray `db:"Events" json:"events"`
+	URLs        StringArray `db:"URLs" json:"urls"`
+	Name        string      `db:"Name" json:"name"`
+	Description string      `db:"Description" json:"description"`
+	ContentType string      `db:"ContentType" json:"content_type"`
+	// 送信する本文の形式。WEBHOOK_PAYLOAD_FORMAT_* のいずれか
+	PayloadFormat string `db:"PayloadFormat" json:"payload_format"`
+	// PayloadFormat が template の場合に使う text/template
+	PayloadTemplate string `db:"PayloadTemplate" json:"payload_template"`
+	CreateAt        int64  `db:"CreateAt" json:"create_at"`
+	UpdateAt        int64  `db:"UpdateAt" json:"update_at"`
+	DeleteAt        int64  `db:"DeleteAt" json:"delete_at"`
+	// 連続した配信失敗の回数。成功すると0に戻る
+	FailureCount int `db:"FailureCount" json:"failure_count"`
+	// 失敗が続いて自動で無効化された日時。更新すると再び有効になる
+	DisabledAt int64 `db:"DisabledAt" json:"disabled_at"`
 }
 
 func (o *Webhook) ToJson() string {
@@ -59,9 +64,14 @@ func (o *Webhook) IsValid() *AppError {
 		return NewAppError("Webhook.IsValid", "model.hook.is_valid.team_id.app_error", nil, "", http.StatusBadRequest)
 	}
 
-	if !o.QuestionEvents && !o.AnswerEvents && !o.CommentEvents {
+	if len(o.Events) == 0 {
 		return NewAppError("Webhook.IsValid", "model.hook.is_valid.events.app_error", nil, "", http.StatusBadRequest)
 	}
+	for _, event := range o.Events {
+		if !IsValidWebhookEvent(event) {
+			return NewAppError("Webhook.IsValid", "model.hook.is_valid.event.app_error", map[string]interface{}{"Event": event}, "", http.StatusBadRequest)
+		}
+	}
 
 	if len(o.URLs) == 0 || len(fmt.Sprintf("%s", o.URLs)) > 1024 {
 		return NewAppError("Webhook.IsValid", "model.hook.is_valid.urls.app_error", nil, "", http.StatusBadRequest)
@@ -84,6 +94,23 @@ func (o *Webhook) IsValid() *AppError {
 		return NewAppError("Webhook.IsValid", "model.hook.is_valid.content_type.app_error", nil, "", http.StatusBadRequest)
 	}
 
+	if !IsValidWebhookPayloadFormat(o.PayloadFormat) {
+		return NewAppError("Webhook.IsValid", "model.hook.is_valid.payload_format.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if len(o.PayloadTemplate) > WEBHOOK_PAYLOAD_TEMPLATE_MAX_LENGTH {
+		return NewAppError("Webhook.IsValid", "model.hook.is_valid.payload_template.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if o.PayloadFormat == WEBHOOK_PAYLOAD_FORMAT_TEMPLATE {
+		if len(o.PayloadTemplate) == 0 {
+			return NewAppError("Webhook.IsValid", "model.hook.is_valid.payload_template.app_error", nil, "", http.StatusBadRequest)
+		}
+		if _, err := ParseWebhookTemplate(o.PayloadTemplate); err != nil {
+			return NewAppError("Webhook.IsValid", "model.hook.is_valid.payload_template_parse.app_error", nil, err.Error(), http.StatusBadRequest)
+		}
+	}
+
 	if o.CreateAt == 0 {
 		return NewAppError("Webhook.IsValid", "model.hook.is_valid.create_at.app_error", nil, "id="+o.Id, http.StatusBadRequest)
 	}
@@ -95,47 +122,6 @@ func (o *Webhook) IsValid() *AppError {
 	return nil
 }
 
-type WebhookPayload struct {
-	Token    string `json:"token"`
-	TeamId   string `json:"team_id"`
-	TeamName string `json:"team_name"`
-	// post.CreateAt
-	Timestamp int64 `json:"timestamp"`
-	// post.UserId(投稿の作者)
-	UserId string `json:"user_id"`
-	// post.User.Name(投稿の作者の名前)
-	UserName string `json:"user_name"`
-	PostId   string `json:"post_id"`
-	// post.Content
-	Content string `json:"content"`
-	// 質問の場合はそのタイトル、
-	// 回答またはコメントの場合はroot(質問)のタイトル。
-	Title    string `json:"title"`
-	PostType string `json:"post_type"`
-}
-
-func (o *WebhookPayload) ToJSON() string {
-	b, _ := json.Marshal(o)
-	return string(b)
-}
-
-func (o *WebhookPayload) ToFormValues() string {
-	// キーがcase sensitiveな map[string][]string
-	v := url.Values{}
-	v.Set("token", o.Token)
-	v.Set("team_id", o.TeamId)
-	v.Set("team_name", o.TeamName)
-	v.Set("timestamp", strconv.FormatInt(o.Timestamp/1000, 10))
-	v.Set("user_id", o.UserId)
-	v.Set("user_name", o.UserName)
-	v.Set("post_id", o.PostId)
-	v.Set("content", o.Content)
-	v.Set("title", o.Title)
-	v.Set("post_type", o.PostType)
-
-	return v.Encode()
-}
-
 func (o *Webhook) PreSave() {
 	if o.Id == "" {
 		o.Id = NewId()
@@ -143,10 +129,41 @@ func (o *Webhook) PreSave() {
 
 	o.Token = NewId()
 
+	if o.PayloadFormat == "" {
+		o.PayloadFormat = WEBHOOK_PAYLOAD_FORMAT_NATIVE
+	}
+
 	o.CreateAt = GetMillis()
 	o.UpdateAt = o.CreateAt
 
 	o.DeleteAt = int64(0)
+	o.FailureCount = 0
+	o.DisabledAt = int64(0)
+}
+
+func (o *Webhook) IsSubscribed(event string) bool {
+	for _, e := range o.Events {
+		if e == event {
+			return true
+		}
+	}
+
+	return false
+}
+
+// 送信先から外したURLへは、過去の履歴からも再送しない
+func (o *Webhook) HasURL(url string) bool {
+	for _, u := range o.URLs {
+		if u == url {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (o *Webhook) IsDisabled() bool {
+	return o.DisabledAt != 0
 }
 
 type WebhookResponse struct {
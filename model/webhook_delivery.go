@@ -0,0 +1,113 @@
+package model
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+const (
+	WEBHOOK_DELIVERY_STATUS_PENDING = "pending"
+	WEBHOOK_DELIVERY_STATUS_SUCCESS = "success"
+	WEBHOOK_DELIVERY_STATUS_FAILED  = "failed"
+
+	HEADER_WEBHOOK_SIGNATURE = "X-QA-Discussion-Signature"
+	HEADER_WEBHOOK_DELIVERY  = "X-QA-Discussion-Delivery"
+	HEADER_WEBHOOK_TIMESTAMP = "X-QA-Discussion-Timestamp"
+
+	WEBHOOK_SIGNATURE_PREFIX = "sha256="
+)
+
+// 送信待ちのwebhookを1URLにつき1行で永続化したキュー。
+// サーバーが再起動しても未送信・再送待ちのものは失われない。
+type WebhookDelivery struct {
+	Id            string `db:"Id, primarykey" json:"id"`
+	WebhookId     string `db:"WebhookId" json:"webhook_id"`
+	TeamId        string `db:"TeamId" json:"team_id"`
+	PostId        string `db:"PostId" json:"post_id"`
+	URL           string `db:"URL" json:"url"`
+	ContentType   string `db:"ContentType" json:"content_type"`
+	RequestBody   string `db:"RequestBody" json:"request_body"`
+	Status        string `db:"Status" json:"status"`
+	Attempts      int    `db:"Attempts" json:"attempts"`
+	NextAttemptAt int64  `db:"NextAttemptAt" json:"next_attempt_at"`
+	CreateAt      int64  `db:"CreateAt" json:"create_at"`
+	UpdateAt      int64  `db:"UpdateAt" json:"update_at"`
+}
+
+func (o *WebhookDelivery) ToJson() string {
+	b, _ := json.Marshal(o)
+	return string(b)
+}
+
+func WebhookDeliveryFromJson(data io.Reader) *WebhookDelivery {
+	var o *WebhookDelivery
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
+func WebhookDeliveryListToJson(list []*WebhookDelivery) string {
+	b, _ := json.Marshal(list)
+	return string(b)
+}
+
+func (o *WebhookDelivery) PreSave() {
+	if o.Id == "" {
+		o.Id = NewId()
+	}
+
+	o.CreateAt = GetMillis()
+	o.UpdateAt = o.CreateAt
+
+	if o.Status == "" {
+		o.Status = WEBHOOK_DELIVERY_STATUS_PENDING
+	}
+
+	if o.NextAttemptAt == 0 {
+		o.NextAttemptAt = o.CreateAt
+	}
+}
+
+func (o *WebhookDelivery) IsValid() *AppError {
+	if len(o.Id) != 26 {
+		return NewAppError("WebhookDelivery.IsValid", "model.webhook_delivery.is_valid.id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if len(o.WebhookId) != 26 {
+		return NewAppError("WebhookDelivery.IsValid", "model.webhook_delivery.is_valid.webhook_id.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if len(o.TeamId) != 26 {
+		return NewAppError("WebhookDelivery.IsValid", "model.webhook_delivery.is_valid.team_id.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if !IsValidHttpUrl(o.URL) {
+		return NewAppError("WebhookDelivery.IsValid", "model.webhook_delivery.is_valid.url.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	switch o.Status {
+	case WEBHOOK_DELIVERY_STATUS_PENDING, WEBHOOK_DELIVERY_STATUS_SUCCESS, WEBHOOK_DELIVERY_STATUS_FAILED:
+	default:
+		return NewAppError("WebhookDelivery.IsValid", "model.webhook_delivery.is_valid.status.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if o.CreateAt == 0 {
+		return NewAppError("WebhookDelivery.IsValid", "model.webhook_delivery.is_valid.create_at.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+// 受信側はtimestampが古すぎないこと、delivery idが未処理であることも確認することでリプレイを防げる
+func WebhookSignature(token string, timestamp int64, body string) string {
+	mac := hmac.New(sha256.New, []byte(token))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write([]byte(body))
+
+	return WEBHOOK_SIGNATURE_PREFIX + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,17 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookSignature(t *testing.T) {
+	// echo -n '1600000000.{"event":"ping"}' | openssl dgst -sha256 -hmac secret
+	assert.Equal(t, "sha256=f1629e66e53028c0a0ed0f843c4f60e3379f3b998c821cf99eae2fd6dcaa0969", WebhookSignature("secret", 1600000000, `{"event":"ping"}`))
+
+	signature := WebhookSignature("secret", 1600000000, `{"event":"ping"}`)
+	assert.NotEqual(t, signature, WebhookSignature("other", 1600000000, `{"event":"ping"}`))
+	assert.NotEqual(t, signature, WebhookSignature("secret", 1600000001, `{"event":"ping"}`))
+	assert.NotEqual(t, signature, WebhookSignature("secret", 1600000000, `{"event":"pong"}`))
+}
@@ -0,0 +1,186 @@
+package model
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+)
+
+const (
+	WEBHOOK_EVENT_QUESTION_CREATED       = "question_created"
+	WEBHOOK_EVENT_ANSWER_CREATED         = "answer_created"
+	WEBHOOK_EVENT_COMMENT_CREATED        = "comment_created"
+	WEBHOOK_EVENT_POST_EDITED            = "post_edited"
+	WEBHOOK_EVENT_POST_DELETED           = "post_deleted"
+	WEBHOOK_EVENT_BEST_ANSWER_SELECTED   = "best_answer_selected"
+	WEBHOOK_EVENT_VOTE_THRESHOLD_CROSSED = "vote_threshold_crossed"
+	WEBHOOK_EVENT_POST_LOCKED            = "post_locked"
+	WEBHOOK_EVENT_POST_UNLOCKED          = "post_unlocked"
+	WEBHOOK_EVENT_POST_PROTECTED         = "post_protected"
+	WEBHOOK_EVENT_POST_UNPROTECTED       = "post_unprotected"
+	WEBHOOK_EVENT_REVIEW_COMPLETED       = "review_completed"
+	WEBHOOK_EVENT_REVIEW_REJECTED        = "review_rejected"
+	WEBHOOK_EVENT_MEMBER_JOINED          = "member_joined"
+	WEBHOOK_EVENT_MEMBER_LEFT            = "member_left"
+	WEBHOOK_EVENT_COLLECTION_UPDATED     = "collection_updated"
+	// 疎通確認用。購読しなくても test endpoint から送れる
+	WEBHOOK_EVENT_PING = "ping"
+
+	WEBHOOK_COLLECTION_ACTION_POST_ADDED   = "post_added"
+	WEBHOOK_COLLECTION_ACTION_POST_REMOVED = "post_removed"
+	WEBHOOK_COLLECTION_ACTION_DELETED      = "deleted"
+)
+
+// この点数を跨いだ時に vote_threshold_crossed を送る
+var WEBHOOK_VOTE_THRESHOLDS = []int{10, 25, 50, 100, 250, 500, 1000}
+
+type WebhookEventType struct {
+	Event string `json:"event"`
+	// dataの形を変える時はversionを上げ、受信側が判別できるようにする
+	Version     int    `json:"version"`
+	Description string `json:"description"`
+}
+
+var WebhookEventCatalog = []*WebhookEventType{
+	{Event: WEBHOOK_EVENT_QUESTION_CREATED, Version: 1, Description: "A question was posted."},
+	{Event: WEBHOOK_EVENT_ANSWER_CREATED, Version: 1, Description: "An answer was posted."},
+	{Event: WEBHOOK_EVENT_COMMENT_CREATED, Version: 1, Description: "A comment was posted."},
+	{Event: WEBHOOK_EVENT_POST_EDITED, Version: 1, Description: "A question, answer or comment was edited."},
+	{Event: WEBHOOK_EVENT_POST_DELETED, Version: 1, Description: "A question, answer or comment was deleted."},
+	{Event: WEBHOOK_EVENT_BEST_ANSWER_SELECTED, Version: 1, Description: "An answer was selected as the best answer."},
+	{Event: WEBHOOK_EVENT_VOTE_THRESHOLD_CROSSED, Version: 1, Description: "A post's score reached a vote threshold."},
+	{Event: WEBHOOK_EVENT_POST_LOCKED, Version: 1, Description: "A post was locked."},
+	{Event: WEBHOOK_EVENT_POST_UNLOCKED, Version: 1, Description: "A post was unlocked."},
+	{Event: WEBHOOK_EVENT_POST_PROTECTED, Version: 1, Description: "A question was protected."},
+	{Event: WEBHOOK_EVENT_POST_UNPROTECTED, Version: 1, Description: "A question was unprotected."},
+	{Event: WEBHOOK_EVENT_REVIEW_COMPLETED, Version: 1, Description: "Reviews for a post were completed and the post was removed."},
+	{Event: WEBHOOK_EVENT_REVIEW_REJECTED, Version: 1, Description: "Reviews for a post were rejected."},
+	{Event: WEBHOOK_EVENT_MEMBER_JOINED, Version: 1, Description: "A user joined the team."},
+	{Event: WEBHOOK_EVENT_MEMBER_LEFT, Version: 1, Description: "A user left or was removed from the team."},
+	{Event: WEBHOOK_EVENT_COLLECTION_UPDATED, Version: 1, Description: "A post was added to or removed from a collection, or the collection was deleted."},
+	{Event: WEBHOOK_EVENT_PING, Version: 1, Description: "A test event sent on request."},
+}
+
+func GetWebhookEventType(event string) *WebhookEventType {
+	for _, t := range WebhookEventCatalog {
+		if t.Event == event {
+			return t
+		}
+	}
+
+	return nil
+}
+
+func IsValidWebhookEvent(event string) bool {
+	return event != WEBHOOK_EVENT_PING && GetWebhookEventType(event) != nil
+}
+
+func WebhookEventCatalogToJson() string {
+	b, _ := json.Marshal(WebhookEventCatalog)
+	return string(b)
+}
+
+// 全イベント共通の外枠。イベント固有の内容はDataに入る
+type WebhookPayload struct {
+	EventId  string `json:"event_id"`
+	Event    string `json:"event"`
+	Version  int    `json:"version"`
+	TeamId   string `json:"team_id"`
+	TeamName string `json:"team_name"`
+	// イベントの発生日時(ミリ秒)
+	Timestamp int64 `json:"timestamp"`
+	// イベントを起こしたユーザー
+	UserId   string      `json:"user_id"`
+	UserName string      `json:"user_name"`
+	Data     interface{} `json:"data"`
+}
+
+func (o *WebhookPayload) ToJSON() string {
+	b, _ := json.Marshal(o)
+	return string(b)
+}
+
+func (o *WebhookPayload) ToFormValues() string {
+	data, _ := json.Marshal(o.Data)
+
+	// キーがcase sensitiveな map[string][]string
+	v := url.Values{}
+	v.Set("event_id", o.EventId)
+	v.Set("event", o.Event)
+	v.Set("version", strconv.Itoa(o.Version))
+	v.Set("team_id", o.TeamId)
+	v.Set("team_name", o.TeamName)
+	v.Set("timestamp", strconv.FormatInt(o.Timestamp/1000, 10))
+	v.Set("user_id", o.UserId)
+	v.Set("user_name", o.UserName)
+	// 入れ子の内容はフォームで表せないのでJSON文字列で送る
+	v.Set("data", string(data))
+
+	return v.Encode()
+}
+
+// 投稿に関するイベントの場合はその投稿のidを返す。履歴を投稿で絞り込むのに使う
+func (o *WebhookPayload) GetPostId() string {
+	switch data := o.Data.(type) {
+	case *WebhookPostData:
+		return data.PostId
+	case *WebhookBestAnswerData:
+		return data.AnswerId
+	case *WebhookVoteThresholdData:
+		return data.PostId
+	case *WebhookReviewData:
+		return data.PostId
+	case *WebhookCollectionData:
+		return data.PostId
+	}
+
+	return ""
+}
+
+type WebhookPostData struct {
+	PostId   string `json:"post_id"`
+	PostType string `json:"post_type"`
+	ParentId string `json:"parent_id,omitempty"`
+	RootId   string `json:"root_id,omitempty"`
+	// 質問の場合はそのタイトル、
+	// 回答またはコメントの場合はroot(質問)のタイトル。
+	Title   string `json:"title,omitempty"`
+	Content string `json:"content,omitempty"`
+	// 投稿の作者
+	AuthorId string `json:"author_id"`
+}
+
+type WebhookBestAnswerData struct {
+	QuestionId string `json:"question_id"`
+	AnswerId   string `json:"answer_id"`
+	AuthorId   string `json:"author_id"`
+}
+
+type WebhookVoteThresholdData struct {
+	PostId    string `json:"post_id"`
+	PostType  string `json:"post_type"`
+	Points    int    `json:"points"`
+	Threshold int    `json:"threshold"`
+}
+
+type WebhookReviewData struct {
+	PostId   string `json:"post_id"`
+	PostType string `json:"post_type"`
+	Revision int64  `json:"revision"`
+}
+
+type WebhookMemberData struct {
+	MemberId   string `json:"member_id"`
+	MemberName string `json:"member_name"`
+}
+
+type WebhookCollectionData struct {
+	CollectionId string `json:"collection_id"`
+	Title        string `json:"title"`
+	Action       string `json:"action"`
+	PostId       string `json:"post_id,omitempty"`
+}
+
+type WebhookPingData struct {
+	WebhookId string `json:"webhook_id"`
+}
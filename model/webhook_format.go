@@ -0,0 +1,250 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"text/template"
+)
+
+const (
+	// WebhookPayload をそのまま送る
+	WEBHOOK_PAYLOAD_FORMAT_NATIVE = "native"
+	// Slack の incoming webhook に直接送れる blocks 形式
+	WEBHOOK_PAYLOAD_FORMAT_SLACK = "slack"
+	// Microsoft Teams の incoming webhook コネクタ向けの MessageCard 形式
+	WEBHOOK_PAYLOAD_FORMAT_TEAMS = "teams"
+	// Microsoft Teams の workflows 向けの Adaptive Card 形式
+	WEBHOOK_PAYLOAD_FORMAT_TEAMS_ADAPTIVE_CARD = "teams_adaptive_card"
+	// ユーザーが指定した text/template で本文を作る
+	WEBHOOK_PAYLOAD_FORMAT_TEMPLATE = "template"
+
+	WEBHOOK_PAYLOAD_TEMPLATE_MAX_LENGTH = 4096
+	WEBHOOK_MESSAGE_TEXT_MAX_RUNES      = 500
+	// テンプレートが巨大な本文を作らないよう、出力の大きさを制限する
+	WEBHOOK_TEMPLATE_OUTPUT_MAX_LENGTH = 64 * 1024
+
+	WEBHOOK_TEAMS_THEME_COLOR = "0076D7"
+)
+
+func IsValidWebhookPayloadFormat(format string) bool {
+	switch format {
+	case WEBHOOK_PAYLOAD_FORMAT_NATIVE,
+		WEBHOOK_PAYLOAD_FORMAT_SLACK,
+		WEBHOOK_PAYLOAD_FORMAT_TEAMS,
+		WEBHOOK_PAYLOAD_FORMAT_TEAMS_ADAPTIVE_CARD,
+		WEBHOOK_PAYLOAD_FORMAT_TEMPLATE:
+		return true
+	}
+
+	return false
+}
+
+// チャットツール向けの形式やテンプレートに渡す、イベントを人が読める形にまとめたもの
+type WebhookMessage struct {
+	Event      string
+	Summary    string
+	Title      string
+	Text       string
+	AuthorName string
+	TeamName   string
+	Tags       []string
+	Link       string
+	Timestamp  int64
+	// テンプレートからイベント固有の値を参照できるよう、元のpayloadも渡す
+	Payload *WebhookPayload
+}
+
+func ParseWebhookTemplate(text string) (*template.Template, error) {
+	return template.New("webhook").Option("missingkey=zero").Parse(text)
+}
+
+func (m *WebhookMessage) RenderTemplate(text string) (string, error) {
+	t, err := ParseWebhookTemplate(text)
+	if err != nil {
+		return "", err
+	}
+
+	buf := &limitedBuffer{max: WEBHOOK_TEMPLATE_OUTPUT_MAX_LENGTH}
+	if err := t.Execute(buf, m); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+type limitedBuffer struct {
+	bytes.Buffer
+	max int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.Len()+len(p) > b.max {
+		return 0, io.ErrShortBuffer
+	}
+
+	return b.Buffer.Write(p)
+}
+
+func (m *WebhookMessage) headline() string {
+	if m.Title == "" {
+		return m.Summary
+	}
+
+	return strings.TrimSuffix(m.Summary, ".") + ": " + m.Title
+}
+
+func (m *WebhookMessage) ToSlackJSON() string {
+	title := slackEscape(m.Title)
+	if m.Link != "" && title != "" {
+		title = "<" + m.Link + "|" + title + ">"
+	}
+
+	text := "*" + slackEscape(m.Summary) + "*"
+	if title != "" {
+		text += "\n" + title
+	}
+	if m.Text != "" {
+		text += "\n" + slackEscape(m.Text)
+	}
+
+	context := []map[string]interface{}{}
+	if m.AuthorName != "" {
+		context = append(context, map[string]interface{}{"type": "mrkdwn", "text": "by *" + slackEscape(m.AuthorName) + "*"})
+	}
+	if m.TeamName != "" {
+		context = append(context, map[string]interface{}{"type": "mrkdwn", "text": "team *" + slackEscape(m.TeamName) + "*"})
+	}
+	if len(m.Tags) > 0 {
+		context = append(context, map[string]interface{}{"type": "mrkdwn", "text": "tags `" + strings.Join(m.Tags, "` `") + "`"})
+	}
+
+	blocks := []map[string]interface{}{
+		{
+			"type": "section",
+			"text": map[string]interface{}{"type": "mrkdwn", "text": text},
+		},
+	}
+	if len(context) > 0 {
+		blocks = append(blocks, map[string]interface{}{"type": "context", "elements": context})
+	}
+
+	b, _ := json.Marshal(map[string]interface{}{
+		// 通知などblocksを表示できない場所で使われる
+		"text":   m.headline(),
+		"blocks": blocks,
+	})
+	return string(b)
+}
+
+// Slack の mrkdwn で特別な意味を持つ文字をエスケープする
+func slackEscape(s string) string {
+	s = strings.Replace(s, "&", "&amp;", -1)
+	s = strings.Replace(s, "<", "&lt;", -1)
+	return strings.Replace(s, ">", "&gt;", -1)
+}
+
+func (m *WebhookMessage) facts() []map[string]string {
+	facts := []map[string]string{}
+	if m.AuthorName != "" {
+		facts = append(facts, map[string]string{"name": "Author", "value": m.AuthorName})
+	}
+	if m.TeamName != "" {
+		facts = append(facts, map[string]string{"name": "Team", "value": m.TeamName})
+	}
+	if len(m.Tags) > 0 {
+		facts = append(facts, map[string]string{"name": "Tags", "value": strings.Join(m.Tags, ", ")})
+	}
+
+	return facts
+}
+
+func (m *WebhookMessage) ToTeamsMessageCardJSON() string {
+	card := map[string]interface{}{
+		"@type":      "MessageCard",
+		"@context":   "https://schema.org/extensions",
+		"summary":    m.headline(),
+		"themeColor": WEBHOOK_TEAMS_THEME_COLOR,
+		"title":      m.headline(),
+		"sections": []map[string]interface{}{
+			{
+				"text":  m.Text,
+				"facts": m.facts(),
+			},
+		},
+	}
+
+	if m.Link != "" {
+		card["potentialAction"] = []map[string]interface{}{
+			{
+				"@type":   "OpenUri",
+				"name":    "View",
+				"targets": []map[string]string{{"os": "default", "uri": m.Link}},
+			},
+		}
+	}
+
+	b, _ := json.Marshal(card)
+	return string(b)
+}
+
+func (m *WebhookMessage) ToTeamsAdaptiveCardJSON() string {
+	facts := []map[string]string{}
+	for _, fact := range m.facts() {
+		facts = append(facts, map[string]string{"title": fact["name"], "value": fact["value"]})
+	}
+
+	body := []map[string]interface{}{
+		{"type": "TextBlock", "text": m.Summary, "weight": "Bolder", "size": "Medium", "wrap": true},
+	}
+	if m.Title != "" {
+		body = append(body, map[string]interface{}{"type": "TextBlock", "text": m.Title, "weight": "Bolder", "wrap": true})
+	}
+	if m.Text != "" {
+		body = append(body, map[string]interface{}{"type": "TextBlock", "text": m.Text, "wrap": true})
+	}
+	if len(facts) > 0 {
+		body = append(body, map[string]interface{}{"type": "FactSet", "facts": facts})
+	}
+
+	content := map[string]interface{}{
+		"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+		"type":    "AdaptiveCard",
+		"version": "1.4",
+		"body":    body,
+	}
+	if m.Link != "" {
+		content["actions"] = []map[string]interface{}{
+			{"type": "Action.OpenUrl", "title": "View", "url": m.Link},
+		}
+	}
+
+	b, _ := json.Marshal(map[string]interface{}{
+		"type": "message",
+		"attachments": []map[string]interface{}{
+			{
+				"contentType": "application/vnd.microsoft.card.adaptive",
+				"content":     content,
+			},
+		},
+	})
+	return string(b)
+}
+
+// プレビューの結果。実際に送られる本文とContent-Type
+type WebhookPreview struct {
+	ContentType string `json:"content_type"`
+	Body        string `json:"body"`
+}
+
+func (o *WebhookPreview) ToJson() string {
+	b, _ := json.Marshal(o)
+	return string(b)
+}
+
+func WebhookPreviewFromJson(data io.Reader) *WebhookPreview {
+	var o *WebhookPreview
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
@@ -2,6 +2,9 @@ package model
 
 import (
 	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
 )
 
 type WebhooksHistory struct {
@@ -15,10 +18,55 @@ type WebhooksHistory struct {
 	RequestBody    string `db:"RequestBody" json:"request_body"`
 	ResponseBody   string `db:"ResponseBody" json:"response_body"`
 	ResponseStatus int    `db:"ResponseStatus" json:"response_status"`
-	CreateAt       int64  `db:"CreateAt" json:"create_at"`
+	// 同名のヘッダーが複数ある場合は ", " で連結する
+	RequestHeaders  StringMap `db:"RequestHeaders" json:"request_headers"`
+	ResponseHeaders StringMap `db:"ResponseHeaders" json:"response_headers"`
+	DeliveryId      string    `db:"DeliveryId" json:"delivery_id"`
+	// 何回目の送信か(1始まり)
+	Attempt int `db:"Attempt" json:"attempt"`
+	// ミリ秒
+	Latency  int64  `db:"Latency" json:"latency"`
+	Error    string `db:"Error" json:"error"`
+	CreateAt int64  `db:"CreateAt" json:"create_at"`
+}
+
+func (o *WebhooksHistory) ToJson() string {
+	b, _ := json.Marshal(o)
+	return string(b)
+}
+
+func WebhooksHistoryFromJson(data io.Reader) *WebhooksHistory {
+	var o *WebhooksHistory
+	json.NewDecoder(data).Decode(&o)
+	return o
 }
 
 func WebhooksHistoryListToJson(list []*WebhooksHistory) string {
 	b, _ := json.Marshal(list)
 	return string(b)
 }
+
+type SearchWebhooksHistoryOptions struct {
+	TeamId    string
+	WebhookId string
+	PostId    string
+	// ResponseStatusの範囲。0の場合は指定なし
+	MinStatus int
+	MaxStatus int
+	FromDate  int64
+	ToDate    int64
+	Page      int
+	PerPage   int
+	// 指定した場合はPageの代わりにこの位置から取得する
+	Cursor *Cursor
+}
+
+// 1つのヘッダーに複数の値がある場合はまとめて1つの文字列にする
+func HeaderToStringMap(header http.Header) StringMap {
+	m := make(StringMap, len(header))
+	for key, values := range header {
+		m[key] = strings.Join(values, ", ")
+	}
+
+	return m
+}
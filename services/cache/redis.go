@@ -12,6 +12,9 @@ type RedisCacheBackend struct {
 	endpoint string
 	password string
 	db       int
+	ctx      context.Context
+	// 共有するclient。nilの場合はコマンドごとに作って閉じる
+	client *redis.Client
 }
 
 func NewRedisBackend(settings *model.CacheSettings) *RedisCacheBackend {
@@ -19,18 +22,60 @@ func NewRedisBackend(settings *model.CacheSettings) *RedisCacheBackend {
 		endpoint: *settings.CacheEndpoint,
 		password: "",
 		db:       0, // default DB
+		ctx:      context.Background(),
 	}
 }
 
+// 呼び出しの多い処理で、コマンドごとに接続し直さないよう長く使うclientを渡す。
+// clientを閉じるのは呼び出し元
+func NewRedisBackendWithClient(client *redis.Client) *RedisCacheBackend {
+	return &RedisCacheBackend{
+		ctx:    context.Background(),
+		client: client,
+	}
+}
+
+// 閉じるのは呼び出し元
+func NewRedisClient(settings *model.CacheSettings) *redis.Client {
+	return newClient(*settings.CacheEndpoint, "", 0)
+}
+
+func newClient(endpoint string, password string, db int) *redis.Client {
+	rdb := redis.NewClient(&redis.Options{
+		Addr:     endpoint,
+		Password: password,
+		DB:       db,
+	})
+	rdb.AddHook(tracingHook{})
+
+	return rdb
+}
+
+// ctxはコマンドのspanの親になる
+func (b *RedisCacheBackend) WithContext(ctx context.Context) *RedisCacheBackend {
+	if ctx != nil {
+		b.ctx = ctx
+	}
+
+	return b
+}
+
+// 使い終わったらreleaseを呼ぶ
+func (b *RedisCacheBackend) getClient() (rdb *redis.Client, release func()) {
+	if b.client != nil {
+		return b.client, func() {}
+	}
+
+	rdb = newClient(b.endpoint, b.password, b.db)
+	return rdb, func() { rdb.Close() }
+}
+
 // ttlは毎回変更される。
 // expire 0 はttl無し、と言う意味。
 func (b *RedisCacheBackend) Set(key string, value interface{}, expireSeconds int) error {
-	var ctx = context.Background()
-	rdb := redis.NewClient(&redis.Options{
-		Addr:     b.endpoint,
-		Password: b.password,
-		DB:       b.db,
-	})
+	ctx := b.ctx
+	rdb, release := b.getClient()
+	defer release()
 
 	err := rdb.Set(ctx, key, value, time.Duration(expireSeconds)*time.Second).Err()
 	if err != nil {
@@ -41,102 +86,135 @@ func (b *RedisCacheBackend) Set(key string, value interface{}, expireSeconds int
 }
 
 func (b *RedisCacheBackend) Get(key string) (string, error) {
-	var ctx = context.Background()
-	rdb := redis.NewClient(&redis.Options{
-		Addr:     b.endpoint,
-		Password: b.password,
-		DB:       b.db,
-	})
+	ctx := b.ctx
+	rdb, release := b.getClient()
+	defer release()
 
 	return rdb.Get(ctx, key).Result()
 }
 
 func (b *RedisCacheBackend) HSet(key string, values map[string]interface{}) (int64, error) {
-	var ctx = context.Background()
-	rdb := redis.NewClient(&redis.Options{
-		Addr:     b.endpoint,
-		Password: b.password,
-		DB:       b.db,
-	})
+	ctx := b.ctx
+	rdb, release := b.getClient()
+	defer release()
 
 	return rdb.HSet(ctx, key, values).Result()
 }
 
 func (b *RedisCacheBackend) HGetAll(key string) (map[string]string, error) {
-	var ctx = context.Background()
-	rdb := redis.NewClient(&redis.Options{
-		Addr:     b.endpoint,
-		Password: b.password,
-		DB:       b.db,
-	})
+	ctx := b.ctx
+	rdb, release := b.getClient()
+	defer release()
 
 	return rdb.HGetAll(ctx, key).Result()
 }
 
 // 重複を許さない文字列集合
 func (b *RedisCacheBackend) SAdd(key string, members []string) (int64, error) {
-	var ctx = context.Background()
-	rdb := redis.NewClient(&redis.Options{
-		Addr:     b.endpoint,
-		Password: b.password,
-		DB:       b.db,
-	})
+	ctx := b.ctx
+	rdb, release := b.getClient()
+	defer release()
 
 	return rdb.SAdd(ctx, key, members).Result()
 }
 
 func (b *RedisCacheBackend) SMembers(key string) ([]string, error) {
-	var ctx = context.Background()
-	rdb := redis.NewClient(&redis.Options{
-		Addr:     b.endpoint,
-		Password: b.password,
-		DB:       b.db,
-	})
+	ctx := b.ctx
+	rdb, release := b.getClient()
+	defer release()
 
 	return rdb.SMembers(ctx, key).Result()
 }
 
-func (b *RedisCacheBackend) Del(keys []string) (int64, error) {
-	var ctx = context.Background()
-	rdb := redis.NewClient(&redis.Options{
-		Addr:     b.endpoint,
-		Password: b.password,
-		DB:       b.db,
+// 指定した数までランダムに取り出して削除する
+func (b *RedisCacheBackend) SPopN(key string, count int) ([]string, error) {
+	ctx := b.ctx
+	rdb, release := b.getClient()
+	defer release()
+
+	return rdb.SPopN(ctx, key, int64(count)).Result()
+}
+
+// キーが無い場合のみセットする。ロックに使う
+func (b *RedisCacheBackend) SetNX(key string, value interface{}, expireSeconds int) (bool, error) {
+	ctx := b.ctx
+	rdb, release := b.getClient()
+	defer release()
+
+	return rdb.SetNX(ctx, key, value, time.Duration(expireSeconds)*time.Second).Result()
+}
+
+// HyperLogLogに追加し、ttlを延ばす。
+// 推定値が変わった(新しい要素だった可能性が高い)場合にtrueを返す
+func (b *RedisCacheBackend) PFAdd(key string, members []string, expireSeconds int) (bool, error) {
+	ctx := b.ctx
+	rdb, release := b.getClient()
+	defer release()
+
+	elements := make([]interface{}, len(members))
+	for i, member := range members {
+		elements[i] = member
+	}
+
+	var added *redis.IntCmd
+	_, err := rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		added = pipe.PFAdd(ctx, key, elements...)
+		pipe.Expire(ctx, key, time.Duration(expireSeconds)*time.Second)
+		return nil
 	})
+	if err != nil {
+		return false, err
+	}
+
+	return added.Val() == 1, nil
+}
+
+// 複数のキーを渡すと和集合の推定値を返す
+func (b *RedisCacheBackend) PFCount(keys []string) (int64, error) {
+	ctx := b.ctx
+	rdb, release := b.getClient()
+	defer release()
+
+	return rdb.PFCount(ctx, keys...).Result()
+}
+
+func (b *RedisCacheBackend) Del(keys []string) (int64, error) {
+	ctx := b.ctx
+	rdb, release := b.getClient()
+	defer release()
 
 	return rdb.Del(ctx, keys...).Result()
 }
 
 func (b *RedisCacheBackend) Exists(key string) (int64, error) {
-	var ctx = context.Background()
-	rdb := redis.NewClient(&redis.Options{
-		Addr:     b.endpoint,
-		Password: b.password,
-		DB:       b.db,
-	})
+	ctx := b.ctx
+	rdb, release := b.getClient()
+	defer release()
 
 	return rdb.Exists(ctx, key).Result()
 }
 
 // ttlの変更はされ無い。
 func (b *RedisCacheBackend) IncrBy(key string, count int) (int64, error) {
-	var ctx = context.Background()
-	rdb := redis.NewClient(&redis.Options{
-		Addr:     b.endpoint,
-		Password: b.password,
-		DB:       b.db,
-	})
+	ctx := b.ctx
+	rdb, release := b.getClient()
+	defer release()
 
 	return rdb.IncrBy(ctx, key, int64(count)).Result()
 }
 
 func (b *RedisCacheBackend) FlushAll() (string, error) {
-	var ctx = context.Background()
-	rdb := redis.NewClient(&redis.Options{
-		Addr:     b.endpoint,
-		Password: b.password,
-		DB:       b.db,
-	})
+	ctx := b.ctx
+	rdb, release := b.getClient()
+	defer release()
 
 	return rdb.FlushAll(ctx).Result()
 }
+
+func (b *RedisCacheBackend) Ping() error {
+	ctx := b.ctx
+	rdb, release := b.getClient()
+	defer release()
+
+	return rdb.Ping(ctx).Err()
+}
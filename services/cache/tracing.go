@@ -0,0 +1,41 @@
+package cache
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-redis/redis/v8"
+	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/label"
+
+	"github.com/clear-ness/qa-discussion/services/tracing"
+)
+
+// redisコマンドごとにspanを作る
+type tracingHook struct{}
+
+func (tracingHook) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	_, ctx = tracing.StartSpan(ctx, "redis."+strings.ToUpper(cmd.Name()), label.String("db.system", "redis"))
+	return ctx, nil
+}
+
+func (tracingHook) AfterProcess(ctx context.Context, cmd redis.Cmder) error {
+	span := trace.SpanFromContext(ctx)
+	// キーが無いだけのredis.Nilはエラー扱いしない
+	if err := cmd.Err(); err != nil && err != redis.Nil {
+		tracing.SetSpanError(span, err)
+	}
+	span.End()
+
+	return nil
+}
+
+func (tracingHook) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	_, ctx = tracing.StartSpan(ctx, "redis.pipeline", label.String("db.system", "redis"), label.Int("redis.num_cmd", len(cmds)))
+	return ctx, nil
+}
+
+func (tracingHook) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error {
+	trace.SpanFromContext(ctx).End()
+	return nil
+}
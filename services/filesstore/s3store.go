@@ -1,9 +1,10 @@
 package filesstore
 
 import (
-	"bytes"
+	"context"
 	"io"
 	"net/http"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
@@ -12,6 +13,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 
 	"github.com/clear-ness/qa-discussion/model"
+	"github.com/clear-ness/qa-discussion/services/tracing"
 )
 
 type S3FileBackend struct {
@@ -23,6 +25,7 @@ type S3FileBackend struct {
 	bucket    string
 	encrypt   string
 	trace     bool
+	ctx       context.Context
 }
 
 func NewFileBackend(settings *model.FileSettings) *S3FileBackend {
@@ -33,15 +36,31 @@ func NewFileBackend(settings *model.FileSettings) *S3FileBackend {
 		secure:    settings.AmazonS3SSL == nil || *settings.AmazonS3SSL,
 		region:    *settings.AmazonS3Region,
 		bucket:    *settings.AmazonS3Bucket,
+		ctx:       context.Background(),
 	}
 }
 
+// バケットが設定されていない場合は読み書きも署名付きURLの発行もできない
+func (b *S3FileBackend) IsConfigured() bool {
+	return b.bucket != ""
+}
+
+// ctxはS3へのリクエストのspanの親になる
+func (b *S3FileBackend) WithContext(ctx context.Context) *S3FileBackend {
+	if ctx != nil {
+		b.ctx = ctx
+	}
+
+	return b
+}
+
 func (b *S3FileBackend) getSession() *session.Session {
 	creds := credentials.NewStaticCredentials(b.accessKey, b.secretKey, "")
 	sess, _ := session.NewSession(&aws.Config{
 		Credentials: creds,
-		Region:      aws.String(b.region)},
-	)
+		Region:      aws.String(b.region),
+		HTTPClient:  &http.Client{Transport: tracing.NewTransport("s3", nil)},
+	})
 
 	return sess
 }
@@ -53,19 +72,23 @@ func (b *S3FileBackend) s3New() *s3.S3 {
 	return s3Service
 }
 
-func (b *S3FileBackend) WriteFile(fr io.Reader, key string) *model.AppError {
-	var buf bytes.Buffer
-	if _, err := buf.ReadFrom(fr); err != nil {
-		return model.NewAppError("WriteFile", "api.file.write_file.s3.app_error", nil, err.Error(), http.StatusInternalServerError)
-	}
+// バケットへの疎通とアクセス権を確認する
+func (b *S3FileBackend) TestConnection() error {
+	_, err := b.s3New().HeadBucketWithContext(b.ctx, &s3.HeadBucketInput{
+		Bucket: aws.String(b.bucket),
+	})
+	return err
+}
 
+// exportのzipのような大きなファイルもあるため、メモリに溜めずにそのままアップロードする
+func (b *S3FileBackend) WriteFile(fr io.Reader, key string) *model.AppError {
 	sess := b.getSession()
 	uploader := s3manager.NewUploader(sess)
 
-	_, err := uploader.Upload(&s3manager.UploadInput{
+	_, err := uploader.UploadWithContext(b.ctx, &s3manager.UploadInput{
 		Bucket: aws.String(b.bucket),
 		Key:    aws.String(key),
-		Body:   &buf,
+		Body:   fr,
 	})
 	if err != nil {
 		return model.NewAppError("WriteFile", "api.file.write_file.s3.app_error", nil, err.Error(), http.StatusInternalServerError)
@@ -80,7 +103,7 @@ func (b *S3FileBackend) FileExists(key string) bool {
 		Key:    aws.String(key),
 	}
 
-	_, err := b.s3New().HeadObject(input)
+	_, err := b.s3New().HeadObjectWithContext(b.ctx, input)
 	if err != nil {
 		return false
 	} else {
@@ -90,12 +113,12 @@ func (b *S3FileBackend) FileExists(key string) bool {
 
 func (b *S3FileBackend) RemoveFile(key string) *model.AppError {
 	s3Service := b.s3New()
-	_, err := s3Service.DeleteObject(&s3.DeleteObjectInput{Bucket: aws.String(b.bucket), Key: aws.String(key)})
+	_, err := s3Service.DeleteObjectWithContext(b.ctx, &s3.DeleteObjectInput{Bucket: aws.String(b.bucket), Key: aws.String(key)})
 	if err != nil {
 		return model.NewAppError("RemoveFile", "api.file.remove_file.s3.app_error", nil, err.Error(), http.StatusInternalServerError)
 	}
 
-	if err := s3Service.WaitUntilObjectNotExists(&s3.HeadObjectInput{
+	if err := s3Service.WaitUntilObjectNotExistsWithContext(b.ctx, &s3.HeadObjectInput{
 		Bucket: aws.String(b.bucket),
 		Key:    aws.String(key),
 	}); err != nil {
@@ -104,3 +127,35 @@ func (b *S3FileBackend) RemoveFile(key string) *model.AppError {
 
 	return nil
 }
+
+// 呼び出し側で必ずCloseすること
+func (b *S3FileBackend) Reader(key string) (io.ReadCloser, *model.AppError) {
+	output, err := b.s3New().GetObjectWithContext(b.ctx, &s3.GetObjectInput{
+		Bucket: aws.String(b.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, model.NewAppError("Reader", "api.file.reader.s3.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	return output.Body, nil
+}
+
+// 認証無しで一定期間だけダウンロードできる署名付きURLを返す
+func (b *S3FileBackend) PresignedURL(key string, expire time.Duration) (string, *model.AppError) {
+	if !b.IsConfigured() {
+		return "", model.NewAppError("PresignedURL", "api.file.presigned_url.not_configured.app_error", nil, "", http.StatusNotImplemented)
+	}
+
+	req, _ := b.s3New().GetObjectRequest(&s3.GetObjectInput{
+		Bucket: aws.String(b.bucket),
+		Key:    aws.String(key),
+	})
+
+	url, err := req.Presign(expire)
+	if err != nil {
+		return "", model.NewAppError("PresignedURL", "api.file.presigned_url.s3.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	return url, nil
+}
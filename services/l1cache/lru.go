@@ -6,6 +6,8 @@ import (
 	"encoding/gob"
 	"sync"
 	"time"
+
+	"github.com/clear-ness/qa-discussion/services/metrics"
 )
 
 // LRU is a thread-safe fixed size LRU cache.
@@ -18,6 +20,8 @@ type LRU struct {
 	invalidateClusterEvent string
 	currentGeneration      int64
 	len                    int
+	name                   string
+	metrics                metrics.MetricsInterface
 }
 
 // LRUOptions contains options for initializing LRU cache
@@ -25,6 +29,8 @@ type LRUOptions struct {
 	Size                   int
 	DefaultExpiry          time.Duration
 	InvalidateClusterEvent string
+	Name                   string
+	Metrics                metrics.MetricsInterface
 }
 
 // entry is used to hold a value in the evictList.
@@ -43,6 +49,8 @@ func NewLRU(opts *LRUOptions) Cache {
 		items:                  make(map[string]*list.Element, opts.Size),
 		defaultExpiry:          opts.DefaultExpiry,
 		invalidateClusterEvent: opts.InvalidateClusterEvent,
+		name:                   opts.Name,
+		metrics:                opts.Metrics,
 	}
 }
 
@@ -81,7 +89,17 @@ func (l *LRU) SetWithExpiry(key string, value interface{}, ttl time.Duration) er
 func (l *LRU) Get(key string, value interface{}) error {
 	l.lock.Lock()
 	defer l.lock.Unlock()
-	return l.get(key, value)
+
+	err := l.get(key, value)
+	if l.metrics != nil {
+		if err == nil {
+			l.metrics.IncrementCacheHit(metrics.CACHE_LAYER_L1, l.name)
+		} else if err == ErrKeyNotFound {
+			l.metrics.IncrementCacheMiss(metrics.CACHE_LAYER_L1, l.name)
+		}
+	}
+
+	return err
 }
 
 // Remove deletes the value for a key.
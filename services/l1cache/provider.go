@@ -1,6 +1,10 @@
 package l1cache
 
-import "time"
+import (
+	"time"
+
+	"github.com/clear-ness/qa-discussion/services/metrics"
+)
 
 // CacheOptions contains options for initializaing a cache
 type CacheOptions struct {
@@ -17,11 +21,13 @@ type Provider interface {
 }
 
 type cacheProvider struct {
+	metrics metrics.MetricsInterface
 }
 
-// NewProvider creates a new CacheProvider
-func NewProvider() Provider {
-	return &cacheProvider{}
+// NewProvider creates a new CacheProvider.
+// metrics is optional and may be nil.
+func NewProvider(metrics metrics.MetricsInterface) Provider {
+	return &cacheProvider{metrics: metrics}
 }
 
 // NewCache creates a new cache with given opts
@@ -30,5 +36,7 @@ func (c *cacheProvider) NewCache(opts *CacheOptions) Cache {
 		Size:                   opts.Size,
 		DefaultExpiry:          opts.DefaultExpiry,
 		InvalidateClusterEvent: opts.InvalidateClusterEvent,
+		Name:                   opts.Name,
+		Metrics:                c.metrics,
 	})
 }
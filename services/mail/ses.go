@@ -1,6 +1,7 @@
 package mail
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/clear-ness/qa-discussion/model"
@@ -51,6 +52,12 @@ func (b *SesMailBackend) sesNew() *ses.SES {
 	return sesService
 }
 
+// 送信枠の取得で、認証情報とSESへの疎通を確認する
+func (b *SesMailBackend) TestConnection(ctx context.Context) error {
+	_, err := b.sesNew().GetSendQuotaWithContext(ctx, &ses.GetSendQuotaInput{})
+	return err
+}
+
 func (b *SesMailBackend) SendMail(mailData *MailData) *model.AppError {
 	input := &ses.SendEmailInput{
 		Destination: &ses.Destination{
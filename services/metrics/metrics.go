@@ -0,0 +1,258 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	METRICS_NAMESPACE = "qa_discussion"
+
+	CACHE_LAYER_REDIS = "redis"
+	CACHE_LAYER_L1    = "l1"
+)
+
+// 各パッケージはこのinterfaceを通して計測値を記録する。
+// 計測が無効な場合はnilが渡されるので、呼び出し側でnilチェックする。
+type MetricsInterface interface {
+	Handler() http.Handler
+
+	IncrementHttpRequest(handler string, method string, statusCode int)
+	ObserveHttpRequestDuration(handler string, method string, elapsed float64)
+
+	RegisterWebSocketConnectionsFunc(f func() []int64)
+
+	IncrementClusterMessageSent(event string)
+	IncrementClusterMessageReceived(event string)
+
+	IncrementCacheHit(layer string, name string)
+	IncrementCacheMiss(layer string, name string)
+
+	ObserveSearchQueryDuration(query string, success bool, elapsed float64)
+
+	IncrementJobRun(jobType string, status string)
+	ObserveJobDuration(jobType string, elapsed float64)
+
+	ObserveStoreMethodDuration(method string, success bool, elapsed float64)
+	IncrementStoreTransactionRetry(method string)
+	IncrementStoreTransactionRetriesExhausted(method string)
+}
+
+type MetricsImpl struct {
+	registry *prometheus.Registry
+
+	httpRequests        *prometheus.CounterVec
+	httpRequestDuration *prometheus.HistogramVec
+
+	clusterMessagesSent     *prometheus.CounterVec
+	clusterMessagesReceived *prometheus.CounterVec
+
+	cacheHits   *prometheus.CounterVec
+	cacheMisses *prometheus.CounterVec
+
+	searchQueryDuration *prometheus.HistogramVec
+
+	jobRuns     *prometheus.CounterVec
+	jobDuration *prometheus.HistogramVec
+
+	storeMethodDuration *prometheus.HistogramVec
+
+	storeTransactionRetries          *prometheus.CounterVec
+	storeTransactionRetriesExhausted *prometheus.CounterVec
+}
+
+func NewMetrics() *MetricsImpl {
+	m := &MetricsImpl{
+		registry: prometheus.NewRegistry(),
+	}
+
+	m.httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: METRICS_NAMESPACE,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "The total number of http API requests.",
+	}, []string{"handler", "method", "status_code"})
+
+	m.httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: METRICS_NAMESPACE,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Time to process http API requests in seconds.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"handler", "method"})
+
+	m.clusterMessagesSent = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: METRICS_NAMESPACE,
+		Subsystem: "cluster",
+		Name:      "messages_sent_total",
+		Help:      "The total number of cluster messages published.",
+	}, []string{"event"})
+
+	m.clusterMessagesReceived = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: METRICS_NAMESPACE,
+		Subsystem: "cluster",
+		Name:      "messages_received_total",
+		Help:      "The total number of cluster messages received from other servers.",
+	}, []string{"event"})
+
+	m.cacheHits = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: METRICS_NAMESPACE,
+		Subsystem: "cache",
+		Name:      "hits_total",
+		Help:      "The total number of cache hits.",
+	}, []string{"layer", "name"})
+
+	m.cacheMisses = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: METRICS_NAMESPACE,
+		Subsystem: "cache",
+		Name:      "misses_total",
+		Help:      "The total number of cache misses.",
+	}, []string{"layer", "name"})
+
+	m.searchQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: METRICS_NAMESPACE,
+		Subsystem: "search",
+		Name:      "query_duration_seconds",
+		Help:      "Time to execute Elasticsearch queries in seconds.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"query", "success"})
+
+	m.jobRuns = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: METRICS_NAMESPACE,
+		Subsystem: "jobs",
+		Name:      "runs_total",
+		Help:      "The total number of job runs by final status.",
+	}, []string{"type", "status"})
+
+	m.jobDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: METRICS_NAMESPACE,
+		Subsystem: "jobs",
+		Name:      "duration_seconds",
+		Help:      "Time to run jobs in seconds.",
+		Buckets:   []float64{0.1, 0.5, 1, 5, 10, 30, 60, 300, 600, 1800, 3600},
+	}, []string{"type"})
+
+	m.storeMethodDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: METRICS_NAMESPACE,
+		Subsystem: "db",
+		Name:      "store_time_seconds",
+		Help:      "Time to execute store methods in seconds.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "success"})
+
+	m.storeTransactionRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: METRICS_NAMESPACE,
+		Subsystem: "db",
+		Name:      "transaction_retries_total",
+		Help:      "The total number of transactions retried after a deadlock or lock wait timeout.",
+	}, []string{"method"})
+
+	m.storeTransactionRetriesExhausted = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: METRICS_NAMESPACE,
+		Subsystem: "db",
+		Name:      "transaction_retries_exhausted_total",
+		Help:      "The total number of transactions that still failed after all retries.",
+	}, []string{"method"})
+
+	m.registry.MustRegister(
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+		prometheus.NewGoCollector(),
+		m.httpRequests,
+		m.httpRequestDuration,
+		m.clusterMessagesSent,
+		m.clusterMessagesReceived,
+		m.cacheHits,
+		m.cacheMisses,
+		m.searchQueryDuration,
+		m.jobRuns,
+		m.jobDuration,
+		m.storeMethodDuration,
+		m.storeTransactionRetries,
+		m.storeTransactionRetriesExhausted,
+	)
+
+	return m
+}
+
+func (m *MetricsImpl) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+func (m *MetricsImpl) IncrementHttpRequest(handler string, method string, statusCode int) {
+	m.httpRequests.WithLabelValues(handler, method, strconv.Itoa(statusCode)).Inc()
+}
+
+func (m *MetricsImpl) ObserveHttpRequestDuration(handler string, method string, elapsed float64) {
+	m.httpRequestDuration.WithLabelValues(handler, method).Observe(elapsed)
+}
+
+// Hubごとの接続数はscrapeのたびにfから取得する
+func (m *MetricsImpl) RegisterWebSocketConnectionsFunc(f func() []int64) {
+	m.registry.MustRegister(&webSocketConnectionsCollector{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(METRICS_NAMESPACE, "websocket", "connections"),
+			"The number of websocket connections per hub.",
+			[]string{"hub"},
+			nil,
+		),
+		counts: f,
+	})
+}
+
+func (m *MetricsImpl) IncrementClusterMessageSent(event string) {
+	m.clusterMessagesSent.WithLabelValues(event).Inc()
+}
+
+func (m *MetricsImpl) IncrementClusterMessageReceived(event string) {
+	m.clusterMessagesReceived.WithLabelValues(event).Inc()
+}
+
+func (m *MetricsImpl) IncrementCacheHit(layer string, name string) {
+	m.cacheHits.WithLabelValues(layer, name).Inc()
+}
+
+func (m *MetricsImpl) IncrementCacheMiss(layer string, name string) {
+	m.cacheMisses.WithLabelValues(layer, name).Inc()
+}
+
+func (m *MetricsImpl) ObserveSearchQueryDuration(query string, success bool, elapsed float64) {
+	m.searchQueryDuration.WithLabelValues(query, strconv.FormatBool(success)).Observe(elapsed)
+}
+
+func (m *MetricsImpl) IncrementJobRun(jobType string, status string) {
+	m.jobRuns.WithLabelValues(jobType, status).Inc()
+}
+
+func (m *MetricsImpl) ObserveJobDuration(jobType string, elapsed float64) {
+	m.jobDuration.WithLabelValues(jobType).Observe(elapsed)
+}
+
+func (m *MetricsImpl) ObserveStoreMethodDuration(method string, success bool, elapsed float64) {
+	m.storeMethodDuration.WithLabelValues(method, strconv.FormatBool(success)).Observe(elapsed)
+}
+
+func (m *MetricsImpl) IncrementStoreTransactionRetry(method string) {
+	m.storeTransactionRetries.WithLabelValues(method).Inc()
+}
+
+func (m *MetricsImpl) IncrementStoreTransactionRetriesExhausted(method string) {
+	m.storeTransactionRetriesExhausted.WithLabelValues(method).Inc()
+}
+
+type webSocketConnectionsCollector struct {
+	desc   *prometheus.Desc
+	counts func() []int64
+}
+
+func (c *webSocketConnectionsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *webSocketConnectionsCollector) Collect(ch chan<- prometheus.Metric) {
+	for i, count := range c.counts() {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(count), strconv.Itoa(i))
+	}
+}
@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func scrape(t *testing.T, m *MetricsImpl) string {
+	recorder := httptest.NewRecorder()
+	m.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	body, err := ioutil.ReadAll(recorder.Body)
+	require.NoError(t, err)
+
+	return string(body)
+}
+
+func TestMetricsHandler(t *testing.T) {
+	m := NewMetrics()
+
+	// 値が1つも無いvecは出力されないので、全て1回ずつ記録する
+	m.IncrementHttpRequest("getPost", http.MethodGet, http.StatusOK)
+	m.ObserveHttpRequestDuration("getPost", http.MethodGet, 0.1)
+	m.RegisterWebSocketConnectionsFunc(func() []int64 { return []int64{3, 5} })
+	m.IncrementClusterMessageSent("invalidate_all_caches")
+	m.IncrementClusterMessageReceived("invalidate_all_caches")
+	m.IncrementCacheHit(CACHE_LAYER_REDIS, "post")
+	m.IncrementCacheMiss(CACHE_LAYER_L1, "user")
+	m.ObserveSearchQueryDuration("search_posts", true, 0.2)
+	m.IncrementJobRun("export_team", "success")
+	m.ObserveJobDuration("export_team", 12)
+	m.ObserveStoreMethodDuration("PostStore.Get", false, 0.01)
+	m.IncrementStoreTransactionRetry("PostStore.Update")
+	m.IncrementStoreTransactionRetriesExhausted("PostStore.Update")
+
+	body := scrape(t, m)
+
+	for _, series := range []string{
+		`qa_discussion_http_requests_total{handler="getPost",method="GET",status_code="200"} 1`,
+		`qa_discussion_http_request_duration_seconds_count{handler="getPost",method="GET"} 1`,
+		`qa_discussion_websocket_connections{hub="0"} 3`,
+		`qa_discussion_websocket_connections{hub="1"} 5`,
+		`qa_discussion_cluster_messages_sent_total{event="invalidate_all_caches"} 1`,
+		`qa_discussion_cluster_messages_received_total{event="invalidate_all_caches"} 1`,
+		`qa_discussion_cache_hits_total{layer="redis",name="post"} 1`,
+		`qa_discussion_cache_misses_total{layer="l1",name="user"} 1`,
+		`qa_discussion_search_query_duration_seconds_count{query="search_posts",success="true"} 1`,
+		`qa_discussion_jobs_runs_total{status="success",type="export_team"} 1`,
+		`qa_discussion_jobs_duration_seconds_bucket{type="export_team",le="30"} 1`,
+		`qa_discussion_db_store_time_seconds_count{method="PostStore.Get",success="false"} 1`,
+		`qa_discussion_db_transaction_retries_total{method="PostStore.Update"} 1`,
+		`qa_discussion_db_transaction_retries_exhausted_total{method="PostStore.Update"} 1`,
+		`go_goroutines`,
+	} {
+		assert.Contains(t, body, series)
+	}
+}
+
+func TestMetricsRegistriesAreIndependent(t *testing.T) {
+	// 同じプロセスで複数作っても登録が衝突しない
+	first := NewMetrics()
+	second := NewMetrics()
+
+	first.IncrementJobRun("import_team", "error")
+
+	assert.Contains(t, scrape(t, first), `qa_discussion_jobs_runs_total{status="error",type="import_team"} 1`)
+	assert.NotContains(t, scrape(t, second), `qa_discussion_jobs_runs_total`)
+}
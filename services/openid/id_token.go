@@ -0,0 +1,249 @@
+package openid
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/clear-ness/qa-discussion/model"
+)
+
+// 発行時刻と検証時刻のずれをどこまで許容するか
+const CLOCK_SKEW = 60 * time.Second
+
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+
+	publicKey *rsa.PublicKey
+}
+
+type jsonWebKeySet struct {
+	Keys []*jsonWebKey `json:"keys"`
+}
+
+type idTokenHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+type Claims map[string]interface{}
+
+func (c Claims) GetString(name string) string {
+	switch v := c[name].(type) {
+	case string:
+		return v
+	case float64:
+		return fmt.Sprintf("%.0f", v)
+	}
+	return ""
+}
+
+func (c Claims) GetBool(name string) bool {
+	switch v := c[name].(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true"
+	}
+	return false
+}
+
+func (c Claims) hasAudience(clientId string) bool {
+	switch aud := c["aud"].(type) {
+	case string:
+		return aud == clientId
+	case []interface{}:
+		for _, a := range aud {
+			if s, ok := a.(string); ok && s == clientId {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (c Claims) getTime(name string) (time.Time, bool) {
+	v, ok := c[name].(float64)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(v), 0), true
+}
+
+func invalidIdTokenError(reason string) *model.AppError {
+	return model.NewAppError("Provider.VerifyIdToken", "openid.verify_id_token."+reason+".app_error", nil, "", http.StatusUnauthorized)
+}
+
+// 署名(RS256)、iss、aud、exp、nonceを検証したうえでclaimsを返す
+func (p *Provider) VerifyIdToken(rawIdToken, nonce string) (Claims, *model.AppError) {
+	parts := strings.Split(rawIdToken, ".")
+	if len(parts) != 3 {
+		return nil, invalidIdTokenError("malformed")
+	}
+
+	headerBytes, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, invalidIdTokenError("malformed")
+	}
+
+	header := idTokenHeader{}
+	if err := json.Unmarshal(headerBytes, &header); err != nil {
+		return nil, invalidIdTokenError("malformed")
+	}
+
+	// alg=noneやHS256による署名すり替えを受け付けない
+	if header.Alg != "RS256" {
+		return nil, invalidIdTokenError("unsupported_alg")
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, invalidIdTokenError("malformed")
+	}
+
+	key, appErr := p.getKey(header.Kid)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	hashed := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, hashed[:], signature); err != nil {
+		return nil, invalidIdTokenError("signature")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, invalidIdTokenError("malformed")
+	}
+
+	claims := Claims{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, invalidIdTokenError("malformed")
+	}
+
+	if strings.TrimSuffix(claims.GetString("iss"), "/") != p.issuer {
+		return nil, invalidIdTokenError("issuer")
+	}
+
+	if !claims.hasAudience(p.clientId) {
+		return nil, invalidIdTokenError("audience")
+	}
+
+	now := time.Now()
+	expiresAt, ok := claims.getTime("exp")
+	if !ok || now.After(expiresAt.Add(CLOCK_SKEW)) {
+		return nil, invalidIdTokenError("expired")
+	}
+
+	if issuedAt, ok := claims.getTime("iat"); ok && issuedAt.After(now.Add(CLOCK_SKEW)) {
+		return nil, invalidIdTokenError("issued_at")
+	}
+
+	// リプレイ攻撃対策
+	if len(nonce) == 0 || claims.GetString("nonce") != nonce {
+		return nil, invalidIdTokenError("nonce")
+	}
+
+	return claims, nil
+}
+
+// キャッシュに無いkidの場合は鍵のローテーションとみなしてJWKSを取得し直す
+func (p *Provider) getKey(kid string) (*rsa.PublicKey, *model.AppError) {
+	p.mutex.RLock()
+	key, ok := p.keys[kid]
+	p.mutex.RUnlock()
+
+	if ok {
+		return key.publicKey, nil
+	}
+
+	if err := p.fetchKeys(); err != nil {
+		return nil, err
+	}
+
+	p.mutex.RLock()
+	key, ok = p.keys[kid]
+	p.mutex.RUnlock()
+
+	if !ok {
+		return nil, invalidIdTokenError("unknown_key")
+	}
+
+	return key.publicKey, nil
+}
+
+func (p *Provider) fetchKeys() *model.AppError {
+	discovery, err := p.Discover()
+	if err != nil {
+		return err
+	}
+
+	resp, respErr := p.client.Get(discovery.JwksUri)
+	if respErr != nil {
+		return model.NewAppError("Provider.fetchKeys", "openid.fetch_keys.request.app_error", nil, respErr.Error(), http.StatusInternalServerError)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return model.NewAppError("Provider.fetchKeys", "openid.fetch_keys.status.app_error", nil, "status="+resp.Status, http.StatusInternalServerError)
+	}
+
+	keySet := jsonWebKeySet{}
+	if err := json.NewDecoder(resp.Body).Decode(&keySet); err != nil {
+		return model.NewAppError("Provider.fetchKeys", "openid.fetch_keys.decode.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	keys := make(map[string]*jsonWebKey)
+	for _, key := range keySet.Keys {
+		if key.Kty != "RSA" || (len(key.Use) > 0 && key.Use != "sig") {
+			continue
+		}
+
+		publicKey, err := key.toPublicKey()
+		if err != nil {
+			return model.NewAppError("Provider.fetchKeys", "openid.fetch_keys.parse.app_error", nil, "kid="+key.Kid+", "+err.Error(), http.StatusInternalServerError)
+		}
+		key.publicKey = publicKey
+		keys[key.Kid] = key
+	}
+
+	p.mutex.Lock()
+	p.keys = keys
+	p.mutex.Unlock()
+
+	return nil
+}
+
+func (k *jsonWebKey) toPublicKey() (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, err
+	}
+
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, err
+	}
+
+	exponent := new(big.Int).SetBytes(e)
+	if !exponent.IsInt64() || exponent.Int64() > int64(^uint32(0)>>1) {
+		return nil, fmt.Errorf("invalid exponent")
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(exponent.Int64()),
+	}, nil
+}
@@ -0,0 +1,167 @@
+package openid
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/clear-ness/qa-discussion/model"
+)
+
+const (
+	DISCOVERY_PATH = "/.well-known/openid-configuration"
+)
+
+type Discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JwksUri               string `json:"jwks_uri"`
+}
+
+type TokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	IdToken     string `json:"id_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+type Provider struct {
+	issuer       string
+	clientId     string
+	clientSecret string
+	client       *http.Client
+
+	mutex     sync.RWMutex
+	discovery *Discovery
+	keys      map[string]*jsonWebKey
+}
+
+func NewProvider(settings *model.OpenIdSettings, client *http.Client) *Provider {
+	return &Provider{
+		issuer:       strings.TrimSuffix(*settings.Issuer, "/"),
+		clientId:     *settings.ClientId,
+		clientSecret: *settings.ClientSecret,
+		client:       client,
+		keys:         make(map[string]*jsonWebKey),
+	}
+}
+
+// 設定変更後も古いproviderを使い続けないよう、呼び出し側で比較する
+func (p *Provider) Matches(settings *model.OpenIdSettings) bool {
+	return p.issuer == strings.TrimSuffix(*settings.Issuer, "/") &&
+		p.clientId == *settings.ClientId &&
+		p.clientSecret == *settings.ClientSecret
+}
+
+func (p *Provider) ClientId() string {
+	return p.clientId
+}
+
+// discovery documentは一度取得したらキャッシュする
+func (p *Provider) Discover() (*Discovery, *model.AppError) {
+	p.mutex.RLock()
+	discovery := p.discovery
+	p.mutex.RUnlock()
+
+	if discovery != nil {
+		return discovery, nil
+	}
+
+	resp, err := p.client.Get(p.issuer + DISCOVERY_PATH)
+	if err != nil {
+		return nil, model.NewAppError("Provider.Discover", "openid.discover.request.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, model.NewAppError("Provider.Discover", "openid.discover.status.app_error", nil, "status="+resp.Status, http.StatusInternalServerError)
+	}
+
+	discovery = &Discovery{}
+	if err := json.NewDecoder(resp.Body).Decode(discovery); err != nil {
+		return nil, model.NewAppError("Provider.Discover", "openid.discover.decode.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	// なりすましを防ぐため、issuerは設定値と完全一致しなければならない
+	if strings.TrimSuffix(discovery.Issuer, "/") != p.issuer {
+		return nil, model.NewAppError("Provider.Discover", "openid.discover.issuer_mismatch.app_error", nil, "issuer="+discovery.Issuer, http.StatusInternalServerError)
+	}
+
+	if len(discovery.AuthorizationEndpoint) == 0 || len(discovery.TokenEndpoint) == 0 || len(discovery.JwksUri) == 0 {
+		return nil, model.NewAppError("Provider.Discover", "openid.discover.missing_endpoint.app_error", nil, "", http.StatusInternalServerError)
+	}
+
+	p.mutex.Lock()
+	p.discovery = discovery
+	p.mutex.Unlock()
+
+	return discovery, nil
+}
+
+func (p *Provider) AuthCodeURL(redirectUri, scope, state, nonce string) (string, *model.AppError) {
+	discovery, err := p.Discover()
+	if err != nil {
+		return "", err
+	}
+
+	authUrl, parseErr := url.Parse(discovery.AuthorizationEndpoint)
+	if parseErr != nil {
+		return "", model.NewAppError("Provider.AuthCodeURL", "openid.auth_code_url.parse.app_error", nil, parseErr.Error(), http.StatusInternalServerError)
+	}
+
+	query := authUrl.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", p.clientId)
+	query.Set("redirect_uri", redirectUri)
+	query.Set("scope", scope)
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	authUrl.RawQuery = query.Encode()
+
+	return authUrl.String(), nil
+}
+
+// authorization codeをtoken endpointでid tokenと交換する
+func (p *Provider) Exchange(code, redirectUri string) (*TokenResponse, *model.AppError) {
+	discovery, err := p.Discover()
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", redirectUri)
+
+	req, reqErr := http.NewRequest(http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if reqErr != nil {
+		return nil, model.NewAppError("Provider.Exchange", "openid.exchange.request.app_error", nil, reqErr.Error(), http.StatusInternalServerError)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(p.clientId), url.QueryEscape(p.clientSecret))
+
+	resp, respErr := p.client.Do(req)
+	if respErr != nil {
+		return nil, model.NewAppError("Provider.Exchange", "openid.exchange.request.app_error", nil, respErr.Error(), http.StatusInternalServerError)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, model.NewAppError("Provider.Exchange", "openid.exchange.status.app_error", nil, "status="+resp.Status, http.StatusUnauthorized)
+	}
+
+	token := &TokenResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(token); err != nil {
+		return nil, model.NewAppError("Provider.Exchange", "openid.exchange.decode.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	if len(token.IdToken) == 0 {
+		return nil, model.NewAppError("Provider.Exchange", "openid.exchange.missing_id_token.app_error", nil, "", http.StatusUnauthorized)
+	}
+
+	return token, nil
+}
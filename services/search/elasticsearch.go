@@ -3,9 +3,14 @@ package search
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"strings"
+	"time"
 
 	"github.com/clear-ness/qa-discussion/model"
+	"github.com/clear-ness/qa-discussion/services/metrics"
+	"github.com/clear-ness/qa-discussion/services/tracing"
 	"github.com/elastic/go-elasticsearch/v8"
 	"github.com/elastic/go-elasticsearch/v8/esapi"
 )
@@ -17,13 +22,32 @@ const (
 	INDEX_NAME_VOTES              = "votes"
 )
 
+// 管理コマンドで表示するインデックスの状態
+type IndexStatus struct {
+	Index     string `json:"index"`
+	Health    string `json:"health"`
+	Status    string `json:"status"`
+	DocsCount string `json:"docs.count"`
+	StoreSize string `json:"store.size"`
+}
+
+var AllIndexNames = []string{
+	INDEX_NAME_POSTS,
+	INDEX_NAME_POST_VIEWS_HISTORY,
+	INDEX_NAME_USER_POINT_HISTORY,
+	INDEX_NAME_VOTES,
+}
+
 type ESBackend struct {
-	es *elasticsearch.Client
+	es      *elasticsearch.Client
+	metrics metrics.MetricsInterface
+	ctx     context.Context
 }
 
-func NewESBackend(settings *model.SearchSettings) (*ESBackend, *error) {
+func NewESBackend(settings *model.SearchSettings, metrics metrics.MetricsInterface) (*ESBackend, *error) {
 	cfg := elasticsearch.Config{
 		Addresses: []string{*settings.SearchEndpoint},
+		Transport: tracing.NewTransport("elasticsearch", nil),
 	}
 
 	es, err := elasticsearch.NewClient(cfg)
@@ -31,11 +55,43 @@ func NewESBackend(settings *model.SearchSettings) (*ESBackend, *error) {
 		return nil, &err
 	}
 
-	s := ESBackend{es: es}
+	s := ESBackend{es: es, metrics: metrics, ctx: context.Background()}
 
 	return &s, nil
 }
 
+// clientは共有したまま、リクエストのcontextを持ったコピーを返す
+func (b *ESBackend) WithContext(ctx context.Context) *ESBackend {
+	backend := *b
+	backend.ctx = ctx
+
+	return &backend
+}
+
+// 検索クエリの所要時間を記録する
+func (b *ESBackend) observeQuery(query string, start time.Time, err error) {
+	if b.metrics == nil {
+		return
+	}
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	b.metrics.ObserveSearchQueryDuration(query, err == nil, elapsed)
+}
+
+func (b *ESBackend) Ping() error {
+	res, err := b.es.Ping(b.es.Ping.WithContext(b.ctx))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return errors.New(res.Status())
+	}
+
+	return nil
+}
+
 // インデックス(rdbで言うデータベースに相当)をmapping付きで作成する
 func (b *ESBackend) CreateIndex(mapping string, indexName string) error {
 	res, err := b.es.Indices.Exists([]string{indexName})
@@ -56,7 +112,7 @@ func (b *ESBackend) CreateIndex(mapping string, indexName string) error {
 
 // TODO: (jobで定期的に？)もはや検索範囲外になったindexing達を削除
 func (b *ESBackend) Indexing(payload []byte, id string, indexName string) error {
-	ctx := context.Background()
+	ctx := b.ctx
 	res, err := esapi.CreateRequest{
 		Index:      indexName,
 		DocumentID: id,
@@ -71,7 +127,7 @@ func (b *ESBackend) Indexing(payload []byte, id string, indexName string) error
 }
 
 func (b *ESBackend) DeleteIndex(itemId string, indexName string) error {
-	ctx := context.Background()
+	ctx := b.ctx
 	res, err := esapi.DeleteRequest{
 		Index:      indexName,
 		DocumentID: itemId,
@@ -83,3 +139,40 @@ func (b *ESBackend) DeleteIndex(itemId string, indexName string) error {
 
 	return nil
 }
+
+// インデックスごとの件数や容量を返す。まだ作られていないインデックスはStatusをmissingにする
+func (b *ESBackend) IndexStatuses(indexNames []string) ([]*IndexStatus, error) {
+	res, err := b.es.Cat.Indices(
+		b.es.Cat.Indices.WithFormat("json"),
+		b.es.Cat.Indices.WithContext(b.ctx),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return nil, errors.New(res.Status())
+	}
+
+	var existing []*IndexStatus
+	if err := json.NewDecoder(res.Body).Decode(&existing); err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]*IndexStatus, len(existing))
+	for _, status := range existing {
+		byName[status.Index] = status
+	}
+
+	statuses := make([]*IndexStatus, 0, len(indexNames))
+	for _, name := range indexNames {
+		status, ok := byName[name]
+		if !ok {
+			status = &IndexStatus{Index: name, Status: "missing"}
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
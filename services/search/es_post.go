@@ -76,6 +76,14 @@ type HotESPostSearchResults struct {
 }
 
 func (b *ESBackend) RelatedESPosts(term string, limit int) (*ESPostSearchResults, error) {
+	start := time.Now()
+	results, err := b.relatedESPosts(term, limit)
+	b.observeQuery("related_posts", start, err)
+
+	return results, err
+}
+
+func (b *ESBackend) relatedESPosts(term string, limit int) (*ESPostSearchResults, error) {
 	var results ESPostSearchResults
 
 	var buf bytes.Buffer
@@ -97,6 +105,7 @@ func (b *ESBackend) RelatedESPosts(term string, limit int) (*ESPostSearchResults
 	res, err := b.es.Search(
 		b.es.Search.WithIndex(INDEX_NAME_POSTS),
 		b.es.Search.WithBody(&buf),
+		b.es.Search.WithContext(b.ctx),
 	)
 	if err != nil {
 		return &results, err
@@ -160,6 +169,14 @@ func (b *ESBackend) HotESAnswerPosts(interval string, teamId string, limit int)
 }
 
 func (b *ESBackend) HotESPosts(indexName string, interval string, teamId string, limit int) (*HotESPostSearchResults, error) {
+	start := time.Now()
+	results, err := b.hotESPosts(indexName, interval, teamId, limit)
+	b.observeQuery("hot_"+indexName, start, err)
+
+	return results, err
+}
+
+func (b *ESBackend) hotESPosts(indexName string, interval string, teamId string, limit int) (*HotESPostSearchResults, error) {
 	// TODO: yesterdayから、を考慮
 	curTime := model.GetMillis()
 
@@ -243,6 +260,7 @@ func (b *ESBackend) HotESPosts(indexName string, interval string, teamId string,
 	res, err := b.es.Search(
 		b.es.Search.WithIndex(indexName),
 		b.es.Search.WithBody(&buf),
+		b.es.Search.WithContext(b.ctx),
 	)
 	if err != nil {
 		return &results, err
@@ -79,6 +79,14 @@ func (b *ESBackend) TopPostsByTag(interval string, teamId string, postType strin
 }
 
 func (b *ESBackend) topUsersOrPostsByTag(interval string, teamId string, postType string, tag string, limit int, groupBy string) (*TopUsersOrPostsByTag, error) {
+	start := time.Now()
+	results, err := b.searchTopUsersOrPostsByTag(interval, teamId, postType, tag, limit, groupBy)
+	b.observeQuery("top_by_tag_"+groupBy, start, err)
+
+	return results, err
+}
+
+func (b *ESBackend) searchTopUsersOrPostsByTag(interval string, teamId string, postType string, tag string, limit int, groupBy string) (*TopUsersOrPostsByTag, error) {
 	var results TopUsersOrPostsByTag
 
 	curTime := model.GetMillis()
@@ -169,6 +177,7 @@ func (b *ESBackend) topUsersOrPostsByTag(interval string, teamId string, postTyp
 	res, err := b.es.Search(
 		b.es.Search.WithIndex(INDEX_NAME_USER_POINT_HISTORY),
 		b.es.Search.WithBody(&buf),
+		b.es.Search.WithContext(b.ctx),
 	)
 	if err != nil {
 		return &results, err
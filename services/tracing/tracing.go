@@ -0,0 +1,125 @@
+package tracing
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp"
+	"go.opentelemetry.io/otel/exporters/stdout"
+	"go.opentelemetry.io/otel/label"
+	"go.opentelemetry.io/otel/propagators"
+	export "go.opentelemetry.io/otel/sdk/export/trace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/semconv"
+
+	"github.com/clear-ness/qa-discussion/model"
+)
+
+const (
+	TRACER_NAME = "github.com/clear-ness/qa-discussion"
+
+	SHUTDOWN_TIMEOUT = 5 * time.Second
+)
+
+// 有効化されていない場合はglobalのTracerProviderがnoopのままなので、
+// StartSpan等はどこからでも呼んでよい。
+type Tracer struct {
+	exporter  export.SpanExporter
+	processor *sdktrace.BatchSpanProcessor
+}
+
+func New(settings *model.TracingSettings) (*Tracer, error) {
+	exporter, err := newExporter(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	processor := sdktrace.NewBatchSpanProcessor(exporter)
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithConfig(sdktrace.Config{
+			DefaultSampler: sdktrace.ParentBased(sdktrace.TraceIDRatioBased(*settings.SampleRate)),
+		}),
+		sdktrace.WithResource(resource.New(semconv.ServiceNameKey.String(*settings.ServiceName))),
+		sdktrace.WithSpanProcessor(processor),
+	)
+
+	global.SetTracerProvider(provider)
+	// 受け取ったtraceparentヘッダーを引き継ぎ、外部へのリクエストにも付与する
+	global.SetTextMapPropagator(propagators.TraceContext{})
+
+	return &Tracer{
+		exporter:  exporter,
+		processor: processor,
+	}, nil
+}
+
+func newExporter(settings *model.TracingSettings) (export.SpanExporter, error) {
+	switch *settings.Exporter {
+	case model.TRACING_EXPORTER_STDOUT:
+		exporter, err := stdout.NewExporter(stdout.WithWriter(os.Stdout), stdout.WithPrettyPrint(), stdout.WithoutMetricExport())
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create stdout exporter")
+		}
+		return exporter, nil
+	case model.TRACING_EXPORTER_OTLP:
+		opts := []otlp.ExporterOption{otlp.WithAddress(*settings.OTLPEndpoint)}
+		if *settings.OTLPInsecure {
+			opts = append(opts, otlp.WithInsecure())
+		}
+
+		exporter, err := otlp.NewExporter(opts...)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create otlp exporter")
+		}
+		return exporter, nil
+	}
+
+	return nil, errors.Errorf("unknown tracing exporter: %s", *settings.Exporter)
+}
+
+// 溜まっているspanを書き出してからexporterを閉じる
+func (t *Tracer) Shutdown() error {
+	t.processor.Shutdown()
+
+	ctx, cancel := context.WithTimeout(context.Background(), SHUTDOWN_TIMEOUT)
+	defer cancel()
+
+	return t.exporter.Shutdown(ctx)
+}
+
+func StartSpan(ctx context.Context, name string, attrs ...label.KeyValue) (trace.Span, context.Context) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	ctx, span := global.Tracer(TRACER_NAME).Start(ctx, name, trace.WithAttributes(attrs...))
+	return span, ctx
+}
+
+// spanをエラーとして記録する。
+// *model.AppErrorのnilをそのまま渡すとnilにならないので、呼び出し側でnilチェックする。
+func SetSpanError(span trace.Span, err error) {
+	span.RecordError(context.Background(), err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// ログやレスポンスヘッダーに載せるためのtrace id。
+// 記録されていない(無効・サンプリング対象外)場合は空文字を返す。
+func TraceIdFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+
+	sc := trace.SpanFromContext(ctx).SpanContext()
+	if !sc.IsSampled() || !sc.TraceID.IsValid() {
+		return ""
+	}
+
+	return sc.TraceID.String()
+}
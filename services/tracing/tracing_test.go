@@ -0,0 +1,106 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/api/trace"
+
+	"github.com/clear-ness/qa-discussion/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// globalのTracerProviderを差し替えるので、テストの最後にnoopへ戻す
+func enableTracing(t *testing.T, sampleRate float64) func() {
+	settings := model.TracingSettings{}
+	settings.SetDefaults()
+	*settings.Enable = true
+	*settings.Exporter = model.TRACING_EXPORTER_STDOUT
+	*settings.SampleRate = sampleRate
+
+	tracer, err := New(&settings)
+	require.NoError(t, err)
+
+	return func() {
+		require.NoError(t, tracer.Shutdown())
+		global.SetTracerProvider(trace.NoopTracerProvider())
+	}
+}
+
+func TestTraceIdFromContext(t *testing.T) {
+	t.Run("no context", func(t *testing.T) {
+		assert.Equal(t, "", TraceIdFromContext(nil))
+		assert.Equal(t, "", TraceIdFromContext(context.Background()))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		// 無効な場合はnoopのspanになり、何も記録しない
+		span, ctx := StartSpan(nil, "disabled")
+		defer span.End()
+
+		assert.False(t, span.IsRecording())
+		assert.Equal(t, "", TraceIdFromContext(ctx))
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		defer enableTracing(t, 1)()
+
+		span, ctx := StartSpan(context.Background(), "enabled")
+		defer span.End()
+
+		traceId := TraceIdFromContext(ctx)
+		assert.Len(t, traceId, 32)
+		assert.Equal(t, span.SpanContext().TraceID.String(), traceId)
+	})
+
+	t.Run("not sampled", func(t *testing.T) {
+		defer enableTracing(t, 0)()
+
+		span, ctx := StartSpan(context.Background(), "not sampled")
+		defer span.End()
+
+		assert.Equal(t, "", TraceIdFromContext(ctx))
+	})
+}
+
+func TestNewUnknownExporter(t *testing.T) {
+	settings := model.TracingSettings{}
+	settings.SetDefaults()
+	*settings.Exporter = "unknown"
+
+	_, err := New(&settings)
+	require.Error(t, err)
+}
+
+func TestTransport(t *testing.T) {
+	defer enableTracing(t, 1)()
+
+	var serverTraceId string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		span, ctx := StartServerSpan(r, "test")
+		defer EndServerSpan(span, http.StatusOK)
+
+		serverTraceId = TraceIdFromContext(ctx)
+	}))
+	defer server.Close()
+
+	span, ctx := StartSpan(context.Background(), "client")
+	defer span.End()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+
+	client := &http.Client{Transport: NewTransport("test", nil)}
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	// traceparentヘッダーで呼び出し元のtraceを引き継ぐ
+	assert.Equal(t, TraceIdFromContext(ctx), serverTraceId)
+	assert.NotEmpty(t, serverTraceId)
+}
@@ -0,0 +1,75 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/semconv"
+)
+
+// 外部へのHTTPリクエストごとにclient spanを作り、traceparentヘッダーを付与する。
+// 親spanはreq.Context()から取るので、呼び出し側はcontext付きでリクエストを作る。
+type Transport struct {
+	name string
+	base http.RoundTripper
+}
+
+func NewTransport(name string, base http.RoundTripper) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &Transport{
+		name: name,
+		base: base,
+	}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, span := global.Tracer(TRACER_NAME).Start(
+		req.Context(),
+		t.name+" "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.HTTPClientAttributesFromHTTPRequest(req)...),
+	)
+
+	req = req.WithContext(ctx)
+	global.TextMapPropagator().Inject(ctx, req.Header)
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		SetSpanError(span, err)
+		span.End()
+		return resp, err
+	}
+
+	span.SetAttributes(semconv.HTTPAttributesFromHTTPStatusCode(resp.StatusCode)...)
+	span.SetStatus(semconv.SpanStatusFromHTTPStatusCode(resp.StatusCode))
+	span.End()
+
+	return resp, nil
+}
+
+// 受け取ったリクエストのroot spanを作る。
+// traceparentヘッダーがあれば呼び出し元のtraceを引き継ぐ。
+func StartServerSpan(r *http.Request, name string) (trace.Span, context.Context) {
+	ctx := global.TextMapPropagator().Extract(r.Context(), r.Header)
+	_, span := global.Tracer(TRACER_NAME).Start(
+		ctx,
+		name,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(semconv.HTTPServerAttributesFromHTTPRequest("", name, r)...),
+	)
+
+	// リクエストから起動した非同期処理がクライアントの切断で中断されないよう、
+	// r.Context()のキャンセルは引き継がずspanだけを載せる
+	return span, trace.ContextWithSpan(context.Background(), span)
+}
+
+func EndServerSpan(span trace.Span, statusCode int) {
+	span.SetAttributes(semconv.HTTPAttributesFromHTTPStatusCode(statusCode)...)
+	span.SetStatus(semconv.SpanStatusFromHTTPStatusCode(statusCode))
+	span.End()
+}
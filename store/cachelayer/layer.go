@@ -1,8 +1,11 @@
 package cachelayer
 
 import (
-	"github.com/clear-ness/qa-discussion/model"
+	"context"
+
 	"github.com/clear-ness/qa-discussion/services/cache"
+	"github.com/clear-ness/qa-discussion/services/configservice"
+	"github.com/clear-ness/qa-discussion/services/metrics"
 	"github.com/clear-ness/qa-discussion/store"
 )
 
@@ -13,13 +16,22 @@ type CacheStore struct {
 	userFavoritePost    CacheUserFavoritePostStore
 	notificationSetting CacheNotificationSettingStore
 	vote                CacheVoteStore
-	config              *model.Config
+	// redisのclientは使う度に今の設定から作るので、設定の変更がそのまま反映される
+	config  configservice.ConfigService
+	metrics metrics.MetricsInterface
+	ctx     context.Context
+}
+
+func NewCacheLayer(baseStore store.Store, metrics metrics.MetricsInterface, cfg configservice.ConfigService) CacheStore {
+	return newCacheLayer(baseStore, metrics, cfg, context.Background())
 }
 
-func NewCacheLayer(baseStore store.Store, cfg *model.Config) CacheStore {
+func newCacheLayer(baseStore store.Store, metrics metrics.MetricsInterface, cfg configservice.ConfigService, ctx context.Context) CacheStore {
 	cacheStore := CacheStore{
-		Store:  baseStore,
-		config: cfg,
+		Store:   baseStore,
+		config:  cfg,
+		metrics: metrics,
+		ctx:     ctx,
 	}
 
 	cacheStore.post = CachePostStore{
@@ -75,59 +87,74 @@ func (s CacheStore) DropAllTables() {
 	s.Store.DropAllTables()
 }
 
+func (s CacheStore) WithContext(ctx context.Context) store.Store {
+	return newCacheLayer(s.Store.WithContext(ctx), s.metrics, s.config, ctx)
+}
+
+func (s *CacheStore) redis() *cache.RedisCacheBackend {
+	return cache.NewRedisBackend(&s.config.Config().CacheSettings).WithContext(s.ctx)
+}
+
 func (s *CacheStore) Invalidate() {
 	// deletes all keys from all databases
-	cache.NewRedisBackend(&s.config.CacheSettings).FlushAll()
+	s.redis().FlushAll()
 }
 
 func (s *CacheStore) addToCache(key string, value interface{}, ttl int) {
 	// TODO: 非同期実行？
-	cache.NewRedisBackend(&s.config.CacheSettings).Set(key, value, ttl)
+	s.redis().Set(key, value, ttl)
 }
 
 func (s *CacheStore) incrementBy(key string, count int) {
-	cache.NewRedisBackend(&s.config.CacheSettings).IncrBy(key, count)
+	s.redis().IncrBy(key, count)
 }
 
-func (s *CacheStore) readCache(key string) *string {
-	val, err := cache.NewRedisBackend(&s.config.CacheSettings).Get(key)
+// nameはmetricsのラベルとして使う、キャッシュの種類名
+func (s *CacheStore) readCache(name string, key string) *string {
+	val, err := s.redis().Get(key)
 	// キーが無ければerrが返る
 	if err == nil {
+		s.recordHit(name)
 		return &val
 	}
 
+	s.recordMiss(name)
 	return nil
 }
 
 func (s *CacheStore) addToHashCache(key string, values map[string]interface{}) {
-	cache.NewRedisBackend(&s.config.CacheSettings).HSet(key, values)
+	s.redis().HSet(key, values)
 }
 
-func (s *CacheStore) readHashCache(key string) map[string]string {
-	val, err := cache.NewRedisBackend(&s.config.CacheSettings).HGetAll(key)
+func (s *CacheStore) readHashCache(name string, key string) map[string]string {
+	val, err := s.redis().HGetAll(key)
 	// キーが無ければerrが返る
 	if err == nil {
+		s.recordHit(name)
 		return val
 	}
 
+	s.recordMiss(name)
 	return nil
 }
 
 func (s *CacheStore) addToSetCache(key string, members []string) {
-	cache.NewRedisBackend(&s.config.CacheSettings).SAdd(key, members)
+	s.redis().SAdd(key, members)
 }
 
-func (s *CacheStore) readSetCache(key string) *[]string {
-	members, err := cache.NewRedisBackend(&s.config.CacheSettings).SMembers(key)
+func (s *CacheStore) readSetCache(name string, key string) *[]string {
+	members, err := s.redis().SMembers(key)
 	if err == nil {
+		s.recordHit(name)
 		return &members
 	}
 
+	s.recordMiss(name)
 	return nil
 }
 
 func (s *CacheStore) existsKey(key string) bool {
-	count, err := cache.NewRedisBackend(&s.config.CacheSettings).Exists(key)
+	count, err := s.redis().Exists(key)
 	if err != nil || count <= 0 {
 		return false
 	}
@@ -135,7 +162,19 @@ func (s *CacheStore) existsKey(key string) bool {
 	return true
 }
 
+func (s *CacheStore) recordHit(name string) {
+	if s.metrics != nil {
+		s.metrics.IncrementCacheHit(metrics.CACHE_LAYER_REDIS, name)
+	}
+}
+
+func (s *CacheStore) recordMiss(name string) {
+	if s.metrics != nil {
+		s.metrics.IncrementCacheMiss(metrics.CACHE_LAYER_REDIS, name)
+	}
+}
+
 func (s *CacheStore) deleteCache(keys []string) (int64, error) {
 	// 実際に消された数が返る
-	return cache.NewRedisBackend(&s.config.CacheSettings).Del(keys)
+	return s.redis().Del(keys)
 }
@@ -48,7 +48,7 @@ func NotificationSettingFromHash(hash map[string]string) *model.NotificationSett
 func (s CacheNotificationSettingStore) Get(userId string) (*model.NotificationSetting, *model.AppError) {
 	notificationKey := userId + "notification"
 
-	if hash := s.rootStore.readHashCache(notificationKey); hash != nil {
+	if hash := s.rootStore.readHashCache("notification_setting", notificationKey); hash != nil {
 		return NotificationSettingFromHash(hash), nil
 	}
 
@@ -1,15 +1,24 @@
 package cachelayer
 
 import (
+	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/clear-ness/qa-discussion/model"
 	"github.com/clear-ness/qa-discussion/store"
 )
 
 const (
-	POST_VIEW_KEY_TTL    = 15 * 60
-	POST_COUNTER_KEY_TTL = 3 * 60
+	// 投稿・日ごとに閲覧したユーザー(未ログインならIP)をHyperLogLogに溜める
+	POST_VIEWS_KEY_PREFIX = "post_views:"
+	// 既にPosts.Viewsに書き出した推定値
+	POST_VIEWS_FLUSHED_KEY_PREFIX = "post_views_flushed:"
+	// 書き出しが必要な "postId:teamId:日付" の集合
+	POST_VIEWS_DIRTY_KEY      = "post_views_dirty"
+	POST_VIEWS_FLUSH_LOCK_KEY = "post_views_flush_lock"
+	POST_VIEWS_DAY_FORMAT     = "20060102"
 )
 
 type CachePostStore struct {
@@ -17,56 +26,134 @@ type CachePostStore struct {
 	rootStore *CacheStore
 }
 
+func postViewsKey(postId string, day string) string {
+	return POST_VIEWS_KEY_PREFIX + postId + ":" + day
+}
+
+func postViewsFlushedKey(postId string, day string) string {
+	return POST_VIEWS_FLUSHED_KEY_PREFIX + postId + ":" + day
+}
+
+func (s CachePostStore) postViewsTTL() int {
+	return *s.rootStore.config.Config().PostViewsSettings.RetentionDays * 24 * 60 * 60
+}
+
+// 閲覧はDBに書かずRedisに溜め、FlushBufferedViewsでまとめて書き出す。
 // (どちらかと言うと)views countを意図的に増やす事を防止する方向に倒す。
 func (s CachePostStore) ViewPost(postId string, teamId string, userId string, ipAddress string, count int) *model.AppError {
-	viewKey := postId
+	visitor := ""
 	if userId != "" {
-		viewKey += userId
+		visitor = userId
 	} else if ipAddress != "" {
-		viewKey += ipAddress
+		visitor = "ip:" + ipAddress
 	} else {
 		return nil
 	}
 
-	exists := s.rootStore.existsKey(viewKey)
-	if exists {
+	day := time.Now().Format(POST_VIEWS_DAY_FORMAT)
+	added, err := s.rootStore.redis().PFAdd(postViewsKey(postId, day), []string{visitor}, s.postViewsTTL())
+	if err != nil {
+		return model.NewAppError("CachePostStore.ViewPost", "store.cache_post.view_post.app_error", nil, "post_id="+postId+", "+err.Error(), http.StatusInternalServerError)
+	}
+
+	if !added {
 		return nil
-	} else {
-		// バッファーA
-		s.rootStore.addToCache(viewKey, "", POST_VIEW_KEY_TTL)
 	}
 
-	counterKey := postId + "counters"
+	s.rootStore.addToSetCache(POST_VIEWS_DIRTY_KEY, []string{postId + ":" + teamId + ":" + day})
 
-	countNum := 0
-	countStr := s.rootStore.readCache(counterKey)
-	if countStr == nil {
-		s.rootStore.addToCache(counterKey, 1, POST_COUNTER_KEY_TTL)
-	} else {
-		if val, err := strconv.Atoi(*countStr); err == nil {
-			countNum = val
-		} else {
-			return nil
+	return nil
+}
+
+// ユニーク閲覧数の推定値が前回の書き出しから増えた分をPosts.ViewsとPostViewsHistoryに書き出す。
+// 複数のサーバーから同時に書き出すと二重に加算されるので、ロックを取れたサーバーだけが実行する。
+func (s CachePostStore) FlushBufferedViews(limit int) (int, *model.AppError) {
+	interval := *s.rootStore.config.Config().PostViewsSettings.FlushIntervalSeconds
+	locked, err := s.rootStore.redis().SetNX(POST_VIEWS_FLUSH_LOCK_KEY, model.GetMillis(), interval)
+	if err != nil {
+		return 0, model.NewAppError("CachePostStore.FlushBufferedViews", "store.cache_post.flush_buffered_views.lock.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	if !locked {
+		return 0, nil
+	}
+	defer s.rootStore.deleteCache([]string{POST_VIEWS_FLUSH_LOCK_KEY})
+
+	flushed := 0
+	for {
+		// 取り出した後に閲覧されると再び追加されるので、取りこぼしは次回に書き出される
+		members, err := s.rootStore.redis().SPopN(POST_VIEWS_DIRTY_KEY, limit)
+		if err != nil {
+			return flushed, model.NewAppError("CachePostStore.FlushBufferedViews", "store.cache_post.flush_buffered_views.pop.app_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+
+		for i, member := range members {
+			ok, appErr := s.flushViews(member)
+			if appErr != nil {
+				// 残りは次回に書き出す
+				s.rootStore.addToSetCache(POST_VIEWS_DIRTY_KEY, members[i:])
+				return flushed, appErr
+			}
+
+			if ok {
+				flushed++
+			}
+		}
+
+		if len(members) < limit {
+			return flushed, nil
 		}
 	}
+}
+
+func (s CachePostStore) flushViews(member string) (bool, *model.AppError) {
+	parts := strings.Split(member, ":")
+	if len(parts) != 3 {
+		return false, nil
+	}
+	postId, teamId, day := parts[0], parts[1], parts[2]
+
+	count, err := s.rootStore.redis().PFCount([]string{postViewsKey(postId, day)})
+	if err != nil {
+		return false, model.NewAppError("CachePostStore.FlushBufferedViews", "store.cache_post.flush_buffered_views.count.app_error", nil, "post_id="+postId+", "+err.Error(), http.StatusInternalServerError)
+	}
+
+	flushedCount := int64(0)
+	if flushedStr := s.rootStore.readCache("post_views_flushed", postViewsFlushedKey(postId, day)); flushedStr != nil {
+		flushedCount, _ = strconv.ParseInt(*flushedStr, 10, 64)
+	}
+
+	// 推定値なので減る事もあるが、その場合は書き出さない
+	delta := count - flushedCount
+	if delta <= 0 {
+		return false, nil
+	}
+
+	if appErr := s.PostStore.ViewPost(postId, teamId, "", "", int(delta)); appErr != nil {
+		return false, appErr
+	}
 
-	countNum = countNum + 1
+	s.rootStore.addToCache(postViewsFlushedKey(postId, day), count, s.postViewsTTL())
 
-	if countNum >= model.POST_COUNTER_MAX {
-		// counterバッファーを初期化する(ttlも変更)
-		s.rootStore.addToCache(counterKey, 0, POST_COUNTER_KEY_TTL)
+	return true, nil
+}
+
+// まだ書き出していない分も含めて、日ごとのユニーク閲覧数の推定値を返す
+func (s CachePostStore) GetDailyUniqueViews(postId string, days int) (model.Analytics, *model.AppError) {
+	now := time.Now()
+
+	rows := model.Analytics{}
+	for i := 0; i < days; i++ {
+		date := now.AddDate(0, 0, -i)
 
-		err := s.PostStore.ViewPost(postId, teamId, userId, ipAddress, model.POST_COUNTER_MAX)
+		count, err := s.rootStore.redis().PFCount([]string{postViewsKey(postId, date.Format(POST_VIEWS_DAY_FORMAT))})
 		if err != nil {
-			return err
+			return nil, model.NewAppError("CachePostStore.GetDailyUniqueViews", "store.cache_post.get_daily_unique_views.app_error", nil, "post_id="+postId+", "+err.Error(), http.StatusInternalServerError)
 		}
 
-	} else {
-		// ttlは変更させ無い
-		s.rootStore.incrementBy(counterKey, 1)
+		rows = append(rows, &model.Analytic{Name: date.Format("2006-01-02"), Value: float64(count)})
 	}
 
-	return nil
+	return rows, nil
 }
 
 // TODO: keyをconst定義
@@ -13,7 +13,7 @@ type CacheTeamStore struct {
 }
 
 func (s CacheTeamStore) GetActiveMemberCount(teamId string) (int64, *model.AppError) {
-	if countStr := s.rootStore.readCache(teamId); countStr != nil {
+	if countStr := s.rootStore.readCache("team_member_count", teamId); countStr != nil {
 		if val, err := strconv.Atoi(*countStr); err == nil {
 			return int64(val), nil
 		}
@@ -15,7 +15,7 @@ type CacheUserFavoritePostStore struct {
 func (s CacheUserFavoritePostStore) GetCountByPostId(postId string) (int64, *model.AppError) {
 	favoriteKey := postId + "favorites"
 
-	if countStr := s.rootStore.readCache(favoriteKey); countStr != nil {
+	if countStr := s.rootStore.readCache("favorites", favoriteKey); countStr != nil {
 		if val, err := strconv.Atoi(*countStr); err == nil {
 			return int64(val), nil
 		}
@@ -14,7 +14,7 @@ func (s CacheVoteStore) GetVoteTypesForPost(userId string, postId string) ([]str
 	key := userId + postId + "votes"
 
 	// TODO: voteが空の場合にエラーなはず
-	if types := s.rootStore.readSetCache(key); types != nil {
+	if types := s.rootStore.readSetCache("vote_types", key); types != nil {
 		return *types, nil
 	}
 
@@ -2,9 +2,11 @@ package store
 
 const (
 	MISSING_ACCOUNT_ERROR         = "store.sql_user.missing_account.const"
+	MISSING_AUTH_ACCOUNT_ERROR    = "store.sql_user.get_by_auth.missing_account.app_error"
 	MISSING_GROUP_MEMBER_ERROR    = "store.sql_group.get_member.missing.app_error"
 	MISSING_COLLECTION_POST_ERROR = "store.sql_collection.get_post.missing.app_error"
 	MISSING_TEAM_MEMBER_ERROR     = "store.sql_team.get_member.missing.app_error"
 	MISSING_GROUPS_ERROR          = "store.sql_group.get_groups.not_found.app_error"
 	MISSING_STATUS_ERROR          = "store.sql_status.get.missing.app_error"
+	MISSING_SYSTEM_ERROR          = "store.sql_system.get_by_name.missing.app_error"
 )
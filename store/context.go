@@ -0,0 +1,24 @@
+package store
+
+import (
+	"context"
+)
+
+type contextKey string
+
+const masterReadsContextKey contextKey = "master_reads"
+
+// 書き込んだ直後のユーザーのリクエストなど、レプリカの遅延で古いデータを返してはいけない場合に、
+// WithContextに渡すとGetReplicaがmasterを返すようになる
+func WithMasterReads(ctx context.Context) context.Context {
+	return context.WithValue(ctx, masterReadsContextKey, true)
+}
+
+func IsMasterReads(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+
+	masterReads, _ := ctx.Value(masterReadsContextKey).(bool)
+	return masterReads
+}
@@ -0,0 +1,428 @@
+// store/store.go のinterfaceから、全てのstoreメソッドをラップするレイヤーを生成する。
+// store ディレクトリで go generate を実行すると
+// store/timerlayer/timerlayer.go と store/tracinglayer/tracinglayer.go が更新される。
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+var (
+	inputFile = flag.String("in", "store.go", "store interfaces file")
+	outputDir = flag.String("outdir", ".", "directory containing the generated layer packages")
+)
+
+type layer struct {
+	Name     string
+	Template string
+	Output   string
+}
+
+var layers = []layer{
+	{Name: "timerlayer", Template: timerLayerTemplate, Output: "timerlayer/timerlayer.go"},
+	{Name: "tracinglayer", Template: tracingLayerTemplate, Output: "tracinglayer/tracinglayer.go"},
+}
+
+type param struct {
+	Name     string
+	Type     string
+	Variadic bool
+}
+
+type method struct {
+	Name    string
+	Params  []param
+	Results []string
+}
+
+type subStore struct {
+	Accessor  string
+	Interface string
+	Methods   []method
+}
+
+type layerData struct {
+	Imports []string
+	Stores  []subStore
+}
+
+// 生成するコードのローカル変数と衝突する引数名
+var reservedNames = map[string]bool{
+	"s":          true,
+	"start":      true,
+	"elapsed":    true,
+	"success":    true,
+	"err":        true,
+	"timemodule": true,
+	"metrics":    true,
+	"span":       true,
+	"tracing":    true,
+	"ctx":        true,
+}
+
+func main() {
+	flag.Parse()
+
+	data, err := parseStore(*inputFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, l := range layers {
+		out, err := render(l, data)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if err := ioutil.WriteFile(path.Join(*outputDir, l.Output), out, 0644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+func parseStore(filename string) (*layerData, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	importPaths := map[string]string{}
+	for _, spec := range file.Imports {
+		importPath, _ := strconv.Unquote(spec.Path.Value)
+		name := path.Base(importPath)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		importPaths[name] = importPath
+	}
+
+	interfaces := map[string]*ast.InterfaceType{}
+	ast.Inspect(file, func(n ast.Node) bool {
+		if spec, ok := n.(*ast.TypeSpec); ok {
+			if iface, ok := spec.Type.(*ast.InterfaceType); ok {
+				interfaces[spec.Name.Name] = iface
+			}
+		}
+		return true
+	})
+
+	root, ok := interfaces["Store"]
+	if !ok {
+		return nil, fmt.Errorf("Store interface not found in %s", filename)
+	}
+
+	usedPackages := map[string]bool{}
+	data := &layerData{}
+
+	for _, field := range root.Methods.List {
+		fn, ok := field.Type.(*ast.FuncType)
+		if !ok || len(field.Names) == 0 || fn.Results == nil || len(fn.Results.List) != 1 {
+			continue
+		}
+
+		// WithContext(ctx) Storeのように引数を取るメソッドはsub storeのアクセサではない
+		if fn.Params != nil && len(fn.Params.List) > 0 {
+			continue
+		}
+
+		ident, ok := fn.Results.List[0].Type.(*ast.Ident)
+		if !ok {
+			continue
+		}
+
+		iface, ok := interfaces[ident.Name]
+		if !ok {
+			continue
+		}
+
+		sub := subStore{
+			Accessor:  field.Names[0].Name,
+			Interface: ident.Name,
+		}
+
+		for _, m := range iface.Methods.List {
+			mfn, ok := m.Type.(*ast.FuncType)
+			if !ok || len(m.Names) == 0 {
+				continue
+			}
+
+			sub.Methods = append(sub.Methods, parseMethod(m.Names[0].Name, mfn, usedPackages))
+		}
+
+		data.Stores = append(data.Stores, sub)
+	}
+
+	for name := range usedPackages {
+		importPath, ok := importPaths[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown package %s", name)
+		}
+		data.Imports = append(data.Imports, importPath)
+	}
+	sort.Strings(data.Imports)
+
+	return data, nil
+}
+
+func parseMethod(name string, fn *ast.FuncType, usedPackages map[string]bool) method {
+	m := method{Name: name}
+
+	index := 0
+	for _, field := range fn.Params.List {
+		typ := exprString(field.Type, usedPackages)
+		variadic := false
+		if ellipsis, ok := field.Type.(*ast.Ellipsis); ok {
+			typ = "..." + exprString(ellipsis.Elt, usedPackages)
+			variadic = true
+		}
+
+		names := field.Names
+		if len(names) == 0 {
+			names = []*ast.Ident{nil}
+		}
+
+		for _, n := range names {
+			paramName := fmt.Sprintf("param%d", index)
+			if n != nil && n.Name != "_" {
+				paramName = n.Name
+			}
+			if reservedNames[paramName] {
+				paramName += "Param"
+			}
+
+			m.Params = append(m.Params, param{Name: paramName, Type: typ, Variadic: variadic})
+			index++
+		}
+	}
+
+	if fn.Results != nil {
+		for _, field := range fn.Results.List {
+			count := len(field.Names)
+			if count == 0 {
+				count = 1
+			}
+			for i := 0; i < count; i++ {
+				m.Results = append(m.Results, exprString(field.Type, usedPackages))
+			}
+		}
+	}
+
+	return m
+}
+
+func exprString(expr ast.Expr, usedPackages map[string]bool) string {
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if ident, ok := sel.X.(*ast.Ident); ok {
+				usedPackages[ident.Name] = true
+			}
+		}
+		return true
+	})
+
+	var buf bytes.Buffer
+	format.Node(&buf, token.NewFileSet(), expr)
+	return buf.String()
+}
+
+func (m method) ParamsDecl() string {
+	parts := make([]string, 0, len(m.Params))
+	for _, p := range m.Params {
+		parts = append(parts, p.Name+" "+p.Type)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (m method) CallArgs() string {
+	parts := make([]string, 0, len(m.Params))
+	for _, p := range m.Params {
+		if p.Variadic {
+			parts = append(parts, p.Name+"...")
+		} else {
+			parts = append(parts, p.Name)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (m method) ResultsDecl() string {
+	switch len(m.Results) {
+	case 0:
+		return ""
+	case 1:
+		return m.Results[0]
+	}
+	return "(" + strings.Join(m.Results, ", ") + ")"
+}
+
+// 最後の戻り値がエラーなら、それで成否を判定する
+func (m method) HasError() bool {
+	if len(m.Results) == 0 {
+		return false
+	}
+	last := m.Results[len(m.Results)-1]
+	return last == "*model.AppError" || last == "error"
+}
+
+func (m method) ResultVars() string {
+	vars := make([]string, 0, len(m.Results))
+	for i := range m.Results {
+		if i == len(m.Results)-1 && m.HasError() {
+			vars = append(vars, "err")
+		} else {
+			vars = append(vars, fmt.Sprintf("result%d", i))
+		}
+	}
+	return strings.Join(vars, ", ")
+}
+
+const timerLayerTemplate = `// Code generated by store/layer_generators. DO NOT EDIT.
+
+package timerlayer
+
+import (
+	"context"
+	timemodule "time"
+
+{{range .Imports}}	"{{.}}"
+{{end}}	"github.com/clear-ness/qa-discussion/services/metrics"
+	"github.com/clear-ness/qa-discussion/store"
+)
+
+type TimerLayer struct {
+	store.Store
+	Metrics metrics.MetricsInterface
+{{range .Stores}}	{{.Interface}} store.{{.Interface}}
+{{end}}}
+{{range .Stores}}
+func (s *TimerLayer) {{.Accessor}}() store.{{.Interface}} {
+	return s.{{.Interface}}
+}
+{{end}}
+func (s *TimerLayer) WithContext(ctx context.Context) store.Store {
+	return New(s.Store.WithContext(ctx), s.Metrics)
+}
+{{range $store := .Stores}}
+type TimerLayer{{$store.Interface}} struct {
+	store.{{$store.Interface}}
+	Root *TimerLayer
+}
+{{end}}
+{{range $store := .Stores}}{{range $method := $store.Methods}}
+func (s *TimerLayer{{$store.Interface}}) {{$method.Name}}({{$method.ParamsDecl}}) {{$method.ResultsDecl}} {
+	start := timemodule.Now()
+
+	{{if $method.Results}}{{$method.ResultVars}} := {{end}}s.{{$store.Interface}}.{{$method.Name}}({{$method.CallArgs}})
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := {{if $method.HasError}}err == nil{{else}}true{{end}}
+		s.Root.Metrics.ObserveStoreMethodDuration("{{$store.Interface}}.{{$method.Name}}", success, elapsed)
+	}{{if $method.Results}}
+
+	return {{$method.ResultVars}}{{end}}
+}
+{{end}}{{end}}
+func New(childStore store.Store, metrics metrics.MetricsInterface) *TimerLayer {
+	newStore := TimerLayer{
+		Store:   childStore,
+		Metrics: metrics,
+	}
+{{range .Stores}}
+	newStore.{{.Interface}} = &TimerLayer{{.Interface}}{ {{- .Interface}}: childStore.{{.Accessor}}(), Root: &newStore}
+{{- end}}
+
+	return &newStore
+}
+`
+
+// リクエストのcontextを親にして、storeメソッドごとにspanを作る。
+// リクエスト単位でNewし直して使う。
+const tracingLayerTemplate = `// Code generated by store/layer_generators. DO NOT EDIT.
+
+package tracinglayer
+
+import (
+	"context"
+
+{{range .Imports}}	"{{.}}"
+{{end}}	"github.com/clear-ness/qa-discussion/services/tracing"
+	"github.com/clear-ness/qa-discussion/store"
+)
+
+type TracingLayer struct {
+	store.Store
+	ctx context.Context
+{{range .Stores}}	{{.Interface}} store.{{.Interface}}
+{{end}}}
+{{range .Stores}}
+func (s *TracingLayer) {{.Accessor}}() store.{{.Interface}} {
+	return s.{{.Interface}}
+}
+{{end}}
+func (s *TracingLayer) WithContext(ctx context.Context) store.Store {
+	return New(s.Store.WithContext(ctx), ctx)
+}
+{{range $store := .Stores}}
+type TracingLayer{{$store.Interface}} struct {
+	store.{{$store.Interface}}
+	Root *TracingLayer
+}
+{{end}}
+{{range $store := .Stores}}{{range $method := $store.Methods}}
+func (s *TracingLayer{{$store.Interface}}) {{$method.Name}}({{$method.ParamsDecl}}) {{$method.ResultsDecl}} {
+	span, _ := tracing.StartSpan(s.Root.ctx, "{{$store.Interface}}.{{$method.Name}}")
+	defer span.End()
+
+	{{if $method.Results}}{{$method.ResultVars}} := {{end}}s.{{$store.Interface}}.{{$method.Name}}({{$method.CallArgs}}){{if $method.HasError}}
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}{{end}}{{if $method.Results}}
+
+	return {{$method.ResultVars}}{{end}}
+}
+{{end}}{{end}}
+func New(childStore store.Store, ctx context.Context) *TracingLayer {
+	newStore := TracingLayer{
+		Store: childStore,
+		ctx:   ctx,
+	}
+{{range .Stores}}
+	newStore.{{.Interface}} = &TracingLayer{{.Interface}}{ {{- .Interface}}: childStore.{{.Accessor}}(), Root: &newStore}
+{{- end}}
+
+	return &newStore
+}
+`
+
+func render(l layer, data *layerData) ([]byte, error) {
+	t, err := template.New(l.Name).Parse(l.Template)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		return buf.Bytes(), fmt.Errorf("failed to format generated code: %v", err)
+	}
+	return formatted, nil
+}
@@ -0,0 +1,105 @@
+package memstore
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/clear-ness/qa-discussion/model"
+)
+
+type MemAuditStore struct {
+	*MemStore
+}
+
+// 新しい順に並べたページを返す。呼び出し元でロックを取る
+func (s *MemAuditStore) pageAudits(matched []*model.Audit, offset, limit int) model.Audits {
+	sort.Slice(matched, func(a, b int) bool {
+		if matched[a].CreateAt == matched[b].CreateAt {
+			return matched[a].Id < matched[b].Id
+		}
+		return matched[a].CreateAt > matched[b].CreateAt
+	})
+
+	var audits model.Audits
+	start, end := paginate(len(matched), offset, limit)
+	for _, audit := range matched[start:end] {
+		audits = append(audits, *clone(audit).(*model.Audit))
+	}
+
+	return audits
+}
+
+func (s *MemAuditStore) Save(audit *model.Audit) *model.AppError {
+	audit.Id = model.NewId()
+	if audit.CreateAt == 0 {
+		audit.CreateAt = model.GetMillis()
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.tables.audits[audit.Id] = clone(audit).(*model.Audit)
+
+	return nil
+}
+
+func (s *MemAuditStore) Get(user_id string, offset int, limit int) (model.Audits, *model.AppError) {
+	if limit > 1000 {
+		return nil, model.NewAppError("MemAuditStore.Get", "store.sql_audit.get.limit.app_error", nil, "user_id="+user_id, http.StatusBadRequest)
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	matched := []*model.Audit{}
+	for _, audit := range s.tables.audits {
+		if len(user_id) == 0 || audit.UserId == user_id {
+			matched = append(matched, audit)
+		}
+	}
+
+	return s.pageAudits(matched, offset, limit), nil
+}
+
+func (s *MemAuditStore) Search(options *model.SearchAuditsOptions) (model.Audits, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	matched := []*model.Audit{}
+	for _, audit := range s.tables.audits {
+		if options.UserId != "" && audit.UserId != options.UserId {
+			continue
+		}
+		if options.Action != "" && audit.Action != options.Action {
+			continue
+		}
+		if options.Status != "" && audit.Status != options.Status {
+			continue
+		}
+		if options.IpAddress != "" && audit.IpAddress != options.IpAddress {
+			continue
+		}
+		if options.FromDate != 0 && audit.CreateAt < options.FromDate {
+			continue
+		}
+		if options.ToDate != 0 && audit.CreateAt > options.ToDate {
+			continue
+		}
+		matched = append(matched, audit)
+	}
+
+	return s.pageAudits(matched, options.Page*options.PerPage, options.PerPage), nil
+}
+
+func (s *MemAuditStore) PermanentDeleteByUser(userId string) *model.AppError {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for id, audit := range s.tables.audits {
+		if audit.UserId == userId {
+			delete(s.tables.audits, id)
+		}
+	}
+
+	return nil
+}
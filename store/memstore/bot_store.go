@@ -0,0 +1,84 @@
+package memstore
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/clear-ness/qa-discussion/model"
+)
+
+type MemBotStore struct {
+	*MemStore
+}
+
+func (s *MemBotStore) Save(bot *model.Bot) (*model.Bot, *model.AppError) {
+	bot.PreSave()
+	if err := bot.IsValid(); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.tables.bots[bot.UserId]; ok {
+		return nil, model.NewAppError("MemBotStore.Save", "store.sql_bot.save.app_error", nil, "user_id="+bot.UserId+", duplicate entry", http.StatusInternalServerError)
+	}
+	s.tables.bots[bot.UserId] = clone(bot).(*model.Bot)
+
+	return bot, nil
+}
+
+func (s *MemBotStore) Get(userId string) (*model.Bot, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	bot, ok := s.tables.bots[userId]
+	if !ok || bot.DeleteAt != 0 {
+		return nil, model.NewAppError("MemBotStore.Get", "store.sql_bot.get.missing.app_error", nil, "user_id="+userId, http.StatusNotFound)
+	}
+
+	return clone(bot).(*model.Bot), nil
+}
+
+func (s *MemBotStore) GetByTeam(teamId string, offset, limit int) ([]*model.Bot, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	matched := []*model.Bot{}
+	for _, bot := range s.tables.bots {
+		if bot.TeamId == teamId && bot.DeleteAt == 0 {
+			matched = append(matched, bot)
+		}
+	}
+
+	sort.Slice(matched, func(a, b int) bool {
+		if matched[a].CreateAt == matched[b].CreateAt {
+			return matched[a].UserId < matched[b].UserId
+		}
+		return matched[a].CreateAt > matched[b].CreateAt
+	})
+
+	var bots []*model.Bot
+	start, end := paginate(len(matched), offset, limit)
+	for _, bot := range matched[start:end] {
+		bots = append(bots, clone(bot).(*model.Bot))
+	}
+
+	return bots, nil
+}
+
+func (s *MemBotStore) Update(bot *model.Bot) (*model.Bot, *model.AppError) {
+	bot.PreUpdate()
+	if err := bot.IsValid(); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.tables.bots[bot.UserId]; ok {
+		s.tables.bots[bot.UserId] = clone(bot).(*model.Bot)
+	}
+
+	return bot, nil
+}
@@ -0,0 +1,44 @@
+package memstore
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// DBに保存して読み出した時と同じ値を返す。
+// db:"-"のフィールドは保存されず、map等はjsonとして保存されるので、呼び出し元と値を共有しない
+func clone(src interface{}) interface{} {
+	srcValue := reflect.ValueOf(src).Elem()
+	dst := reflect.New(srcValue.Type())
+	dstValue := dst.Elem()
+
+	for i := 0; i < srcValue.NumField(); i++ {
+		field := srcValue.Type().Field(i)
+		if field.PkgPath != "" || !isPersisted(field) {
+			continue
+		}
+
+		value := srcValue.Field(i)
+		switch value.Kind() {
+		case reflect.Map, reflect.Slice, reflect.Ptr, reflect.Interface:
+			if value.IsNil() {
+				continue
+			}
+			copied := reflect.New(value.Type())
+			b, _ := json.Marshal(value.Interface())
+			if err := json.Unmarshal(b, copied.Interface()); err == nil {
+				dstValue.Field(i).Set(copied.Elem())
+			}
+		default:
+			dstValue.Field(i).Set(value)
+		}
+	}
+
+	return dst.Interface()
+}
+
+func isPersisted(field reflect.StructField) bool {
+	tag := field.Tag.Get("db")
+	return strings.TrimSpace(strings.Split(tag, ",")[0]) != "-"
+}
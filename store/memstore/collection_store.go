@@ -0,0 +1,233 @@
+package memstore
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/clear-ness/qa-discussion/store"
+)
+
+type MemCollectionStore struct {
+	*MemStore
+}
+
+func (s *MemCollectionStore) sortedCollections() model.CollectionList {
+	cols := model.CollectionList{}
+	for _, col := range s.tables.collections {
+		cols = append(cols, col)
+	}
+	sort.Slice(cols, func(a, b int) bool {
+		return cols[a].Id < cols[b].Id
+	})
+
+	return cols
+}
+
+func (s *MemCollectionStore) GetTeamCollections(teamId string) (*model.CollectionList, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	data := model.CollectionList{}
+	for _, col := range s.sortedCollections() {
+		if col.TeamId == teamId {
+			data = append(data, clone(col).(*model.Collection))
+		}
+	}
+
+	if len(data) == 0 {
+		return nil, model.NewAppError("MemCollectionStore.GetTeamCollections", "store.sql_collection.get_collections.not_found.app_error", nil, "teamId="+teamId, http.StatusNotFound)
+	}
+
+	return &data, nil
+}
+
+func (s *MemCollectionStore) Save(collection *model.Collection, maxCollectionsPerTeam int64) (*model.Collection, *model.AppError) {
+	if collection.DeleteAt != 0 {
+		return nil, model.NewAppError("MemCollectionStore.Save", "store.sql_collection.save.already_deleted.app_error", nil, "", http.StatusInternalServerError)
+	}
+
+	if len(collection.Id) > 0 {
+		return nil, model.NewAppError("MemCollectionStore.Save", "store.sql_collection.save.existing.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	collection.PreSave()
+	if err := collection.IsValid(); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if maxCollectionsPerTeam >= 0 {
+		count := int64(0)
+		for _, other := range s.tables.collections {
+			if other.TeamId == collection.TeamId && other.DeleteAt == 0 {
+				count++
+			}
+		}
+		if count >= maxCollectionsPerTeam {
+			return nil, model.NewAppError("MemCollectionStore.Save", "store.sql_collection.save.too_many.app_error", nil, "", http.StatusBadRequest)
+		}
+	}
+
+	if _, ok := s.tables.collections[collection.Id]; ok {
+		return nil, model.NewAppError("MemCollectionStore.Save", "store.sql_collection.save.exists.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	s.tables.collections[collection.Id] = clone(collection).(*model.Collection)
+
+	return collection, nil
+}
+
+func (s *MemCollectionStore) Get(id string) (*model.Collection, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	col, ok := s.tables.collections[id]
+	if !ok {
+		return nil, model.NewAppError("MemCollectionStore.Get", "store.sql_collection.get.not_found.app_error", nil, "id="+id, http.StatusNotFound)
+	}
+
+	return clone(col).(*model.Collection), nil
+}
+
+func (s *MemCollectionStore) GetPost(collectionId string, postId string) (*model.CollectionPost, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	colPost, ok := s.tables.collectionPosts[collectionPostKey{collectionId, postId}]
+	if !ok {
+		return nil, model.NewAppError("MemCollectionStore.GetPost", store.MISSING_COLLECTION_POST_ERROR, nil, "collection_id="+collectionId+"post_id="+postId, http.StatusNotFound)
+	}
+
+	return clone(colPost).(*model.CollectionPost), nil
+}
+
+func (s *MemCollectionStore) GetPosts(collectionId string, offset, limit int) (*model.CollectionPosts, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	colPosts := model.CollectionPosts{}
+	if col, ok := s.tables.collections[collectionId]; ok && col.DeleteAt == 0 {
+		for key, colPost := range s.tables.collectionPosts {
+			if key.collectionId == collectionId {
+				colPosts = append(colPosts, *colPost)
+			}
+		}
+	}
+
+	sort.Slice(colPosts, func(a, b int) bool {
+		return colPosts[a].PostId < colPosts[b].PostId
+	})
+
+	start, end := paginate(len(colPosts), offset, limit)
+	colPosts = colPosts[start:end]
+
+	return &colPosts, nil
+}
+
+func (s *MemCollectionStore) SaveMultiplePosts(colPosts []*model.CollectionPost) ([]*model.CollectionPost, *model.AppError) {
+	colPosts, err := s.saveMultiplePosts(colPosts)
+	if err != nil {
+		return nil, model.NewAppError("SaveMultiplePosts", "app.collection.save_multiple.internal_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	return colPosts, nil
+}
+
+func (s *MemCollectionStore) SavePost(colPost *model.CollectionPost) (*model.CollectionPost, *model.AppError) {
+	newColPosts, appErr := s.SaveMultiplePosts([]*model.CollectionPost{colPost})
+	if appErr != nil {
+		return nil, appErr
+	}
+	return newColPosts[0], nil
+}
+
+func (s *MemCollectionStore) saveMultiplePosts(colPosts []*model.CollectionPost) ([]*model.CollectionPost, error) {
+	for _, colPost := range colPosts {
+		if err := colPost.IsValid(); err != nil {
+			return nil, err
+		}
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	keys := map[collectionPostKey]bool{}
+	for _, colPost := range colPosts {
+		key := collectionPostKey{colPost.CollectionId, colPost.PostId}
+		if _, ok := s.tables.collectionPosts[key]; ok || keys[key] {
+			return nil, errors.Errorf("collection_posts_save: duplicate entry collection_id=%s post_id=%s", colPost.CollectionId, colPost.PostId)
+		}
+		keys[key] = true
+	}
+
+	for _, colPost := range colPosts {
+		s.tables.collectionPosts[collectionPostKey{colPost.CollectionId, colPost.PostId}] = clone(colPost).(*model.CollectionPost)
+	}
+
+	return colPosts, nil
+}
+
+func (s *MemCollectionStore) GetCollectionsForTeam(teamId string, offset int, limit int, title string, cursor *model.Cursor) (*model.CollectionList, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	terms := fulltextTerms{optional: strings.Fields(title)}
+
+	cols := model.CollectionList{}
+	for _, col := range s.sortedCollections() {
+		if col.TeamId != teamId || col.DeleteAt != 0 {
+			continue
+		}
+		if title != "" && !terms.match(col.Title) {
+			continue
+		}
+		cols = append(cols, clone(col).(*model.Collection))
+	}
+
+	keys := func(i int) []interface{} {
+		return []interface{}{cols[i].CreateAt, cols[i].Id}
+	}
+
+	page := model.CollectionList{}
+	for _, i := range keysetPage(len(cols), keys, true, cursor, true, offset, limit) {
+		page = append(page, cols[i])
+	}
+
+	return &page, nil
+}
+
+func (s *MemCollectionStore) RemovePosts(collectionId string, postIds []string) *model.AppError {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, postId := range postIds {
+		delete(s.tables.collectionPosts, collectionPostKey{collectionId, postId})
+	}
+
+	return nil
+}
+
+func (s *MemCollectionStore) RemovePost(collectionId string, postId string) *model.AppError {
+	return s.RemovePosts(collectionId, []string{postId})
+}
+
+func (s *MemCollectionStore) Delete(collectionId string, time int64) *model.AppError {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	col, ok := s.tables.collections[collectionId]
+	if !ok || col.DeleteAt != 0 {
+		return model.NewAppError("MemCollectionStore.DeleteCollection", "store.sql_collection.delete_collection.app_error", nil, "id="+collectionId, http.StatusInternalServerError)
+	}
+
+	col.DeleteAt = time
+	col.UpdateAt = time
+
+	return nil
+}
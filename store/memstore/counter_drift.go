@@ -0,0 +1,317 @@
+package memstore
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/clear-ness/qa-discussion/model"
+)
+
+func appendCounterDrift(drifts []*model.CounterDrift, table, teamId, key, column string, actual, expected int64) []*model.CounterDrift {
+	if actual == expected {
+		return drifts
+	}
+
+	return append(drifts, &model.CounterDrift{
+		Table:    table,
+		TeamId:   teamId,
+		Key:      key,
+		Column:   column,
+		Actual:   actual,
+		Expected: expected,
+	})
+}
+
+// 照合した後に値が変わっていれば修正しない
+func updateCounterIfUnchanged(counter *int, drift *model.CounterDrift) bool {
+	if int64(*counter) != drift.Actual {
+		return false
+	}
+
+	*counter = int(drift.Expected)
+	return true
+}
+
+func (s *MemPostStore) GetCounterDrifts(after *model.CounterCursor, limit int, importedPropKey string) (*model.CounterDriftBatch, *model.AppError) {
+	afterId := ""
+	if after != nil {
+		afterId = after.Key
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var posts []*model.Post
+	for _, post := range s.sortedPosts() {
+		if post.Id <= afterId || post.OriginalId != "" || post.DeleteAt != 0 {
+			continue
+		}
+
+		posts = append(posts, post)
+		if len(posts) == limit {
+			break
+		}
+	}
+
+	batch := &model.CounterDriftBatch{Checked: len(posts)}
+	for _, post := range posts {
+		answerCount := 0
+		for _, child := range s.tables.posts {
+			if child.ParentId == post.Id && child.Type == model.POST_TYPE_ANSWER && child.OriginalId == "" && child.DeleteAt == 0 {
+				answerCount++
+			}
+		}
+
+		upVotes, downVotes, flagCount := 0, 0, 0
+		for _, vote := range s.tables.votes {
+			if vote.PostId != post.Id {
+				continue
+			}
+
+			switch vote.Type {
+			case model.VOTE_TYPE_UP_VOTE:
+				upVotes++
+			case model.VOTE_TYPE_DOWN_VOTE:
+				downVotes++
+			case model.VOTE_TYPE_FLAG:
+				flagCount++
+			}
+		}
+
+		views := 0
+		for _, history := range s.tables.postViewsHistory {
+			if history.PostId == post.Id {
+				views += history.ViewsCount
+			}
+		}
+
+		if _, ok := post.Props[importedPropKey]; ok && importedPropKey != "" {
+			upVotes, downVotes = post.UpVotes, post.DownVotes
+		}
+
+		drifts := appendCounterDrift(batch.Drifts, model.COUNTER_TABLE_POSTS, "", post.Id, model.COUNTER_COLUMN_ANSWER_COUNT, int64(post.AnswerCount), int64(answerCount))
+		drifts = appendCounterDrift(drifts, model.COUNTER_TABLE_POSTS, "", post.Id, model.COUNTER_COLUMN_UP_VOTES, int64(post.UpVotes), int64(upVotes))
+		drifts = appendCounterDrift(drifts, model.COUNTER_TABLE_POSTS, "", post.Id, model.COUNTER_COLUMN_DOWN_VOTES, int64(post.DownVotes), int64(downVotes))
+		drifts = appendCounterDrift(drifts, model.COUNTER_TABLE_POSTS, "", post.Id, model.COUNTER_COLUMN_POINTS, int64(post.Points), int64(upVotes-downVotes))
+		drifts = appendCounterDrift(drifts, model.COUNTER_TABLE_POSTS, "", post.Id, model.COUNTER_COLUMN_FLAG_COUNT, int64(post.FlagCount), int64(flagCount))
+		batch.Drifts = appendCounterDrift(drifts, model.COUNTER_TABLE_POSTS, "", post.Id, model.COUNTER_COLUMN_VIEWS, int64(post.Views), int64(views))
+	}
+
+	if len(posts) == limit {
+		batch.Next = &model.CounterCursor{Key: posts[len(posts)-1].Id}
+	}
+
+	return batch, nil
+}
+
+func (s *MemPostStore) FixCounterDrift(drift *model.CounterDrift) (bool, *model.AppError) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	post, ok := s.tables.posts[drift.Key]
+	if !ok {
+		return false, nil
+	}
+
+	var counter *int
+	switch drift.Column {
+	case model.COUNTER_COLUMN_ANSWER_COUNT:
+		counter = &post.AnswerCount
+	case model.COUNTER_COLUMN_UP_VOTES:
+		counter = &post.UpVotes
+	case model.COUNTER_COLUMN_DOWN_VOTES:
+		counter = &post.DownVotes
+	case model.COUNTER_COLUMN_POINTS:
+		counter = &post.Points
+	case model.COUNTER_COLUMN_FLAG_COUNT:
+		counter = &post.FlagCount
+	case model.COUNTER_COLUMN_VIEWS:
+		counter = &post.Views
+	default:
+		return false, model.NewAppError("MemPostStore.FixCounterDrift", "store.sql_post.fix_counter_drift.column.app_error", nil, "column="+drift.Column, http.StatusBadRequest)
+	}
+
+	return updateCounterIfUnchanged(counter, drift), nil
+}
+
+func (s *MemTagStore) GetPostTagsAfter(after *model.CounterCursor, limit int) (model.Tags, *model.AppError) {
+	cursor := model.CounterCursor{}
+	if after != nil {
+		cursor = *after
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var tags model.Tags
+	for _, tag := range s.tables.tags {
+		if tag.Type != "" {
+			continue
+		}
+		if tag.TeamId < cursor.TeamId || (tag.TeamId == cursor.TeamId && !lessFold(cursor.Key, tag.Content)) {
+			continue
+		}
+
+		tags = append(tags, *clone(tag).(*model.Tag))
+	}
+
+	sort.Slice(tags, func(a, b int) bool {
+		if tags[a].TeamId != tags[b].TeamId {
+			return tags[a].TeamId < tags[b].TeamId
+		}
+		return lessFold(tags[a].Content, tags[b].Content)
+	})
+
+	start, end := paginate(len(tags), 0, limit)
+	if start == end {
+		return nil, nil
+	}
+
+	return tags[start:end], nil
+}
+
+func (s *MemTagStore) FixCounterDrift(drift *model.CounterDrift) (bool, *model.AppError) {
+	if drift.Column != model.COUNTER_COLUMN_POST_COUNT {
+		return false, model.NewAppError("MemTagStore.FixCounterDrift", "store.sql_tag.fix_counter_drift.column.app_error", nil, "column="+drift.Column, http.StatusBadRequest)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	tag, ok := s.tables.tags[newTagKey(drift.Key, drift.TeamId, "")]
+	if !ok {
+		return false, nil
+	}
+
+	return updateCounterIfUnchanged(&tag.PostCount, drift), nil
+}
+
+func (us *MemUserStore) pointsFromHistory(userId string, teamId string) int {
+	points := 0
+	for _, history := range us.tables.userPointHistory {
+		if history.UserId == userId && history.TeamId == teamId {
+			points += history.Points
+		}
+	}
+
+	return points
+}
+
+func (us *MemUserStore) GetPointsDrifts(after *model.CounterCursor, limit int, importedPropKey string) (*model.CounterDriftBatch, *model.AppError) {
+	afterId := ""
+	if after != nil {
+		afterId = after.Key
+	}
+
+	us.mutex.RLock()
+	defer us.mutex.RUnlock()
+
+	var userIds []string
+	for id, user := range us.tables.users {
+		if id <= afterId || user.DeleteAt != 0 {
+			continue
+		}
+		if _, ok := user.Props[importedPropKey]; ok && importedPropKey != "" {
+			continue
+		}
+
+		userIds = append(userIds, id)
+	}
+	sort.Strings(userIds)
+
+	start, end := paginate(len(userIds), 0, limit)
+	userIds = userIds[start:end]
+
+	batch := &model.CounterDriftBatch{Checked: len(userIds)}
+	for _, userId := range userIds {
+		batch.Drifts = appendCounterDrift(batch.Drifts, model.COUNTER_TABLE_USERS, "", userId, model.COUNTER_COLUMN_POINTS, int64(us.tables.users[userId].Points), int64(us.pointsFromHistory(userId, "")))
+	}
+
+	if len(userIds) == limit {
+		batch.Next = &model.CounterCursor{Key: userIds[len(userIds)-1]}
+	}
+
+	return batch, nil
+}
+
+func (us *MemUserStore) FixPointsDrift(drift *model.CounterDrift) (bool, *model.AppError) {
+	if drift.Column != model.COUNTER_COLUMN_POINTS {
+		return false, model.NewAppError("MemUserStore.FixPointsDrift", "store.sql_user.fix_points_drift.column.app_error", nil, "column="+drift.Column, http.StatusBadRequest)
+	}
+
+	us.mutex.Lock()
+	defer us.mutex.Unlock()
+
+	user, ok := us.tables.users[drift.Key]
+	if !ok {
+		return false, nil
+	}
+
+	return updateCounterIfUnchanged(&user.Points, drift), nil
+}
+
+func (s *MemTeamStore) GetMemberPointsDrifts(after *model.CounterCursor, limit int, importedPropKey string) (*model.CounterDriftBatch, *model.AppError) {
+	cursor := model.CounterCursor{}
+	if after != nil {
+		cursor = *after
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var members []*model.TeamMember
+	for _, member := range s.tables.teamMembers {
+		if member.TeamId < cursor.TeamId || (member.TeamId == cursor.TeamId && member.UserId <= cursor.Key) || member.DeleteAt != 0 {
+			continue
+		}
+
+		user, ok := s.tables.users[member.UserId]
+		if !ok {
+			continue
+		}
+		if _, ok := user.Props[importedPropKey]; ok && importedPropKey != "" {
+			continue
+		}
+
+		members = append(members, member)
+	}
+
+	sort.Slice(members, func(a, b int) bool {
+		if members[a].TeamId != members[b].TeamId {
+			return members[a].TeamId < members[b].TeamId
+		}
+		return members[a].UserId < members[b].UserId
+	})
+
+	start, end := paginate(len(members), 0, limit)
+	members = members[start:end]
+
+	userStore := &MemUserStore{s.MemStore}
+	batch := &model.CounterDriftBatch{Checked: len(members)}
+	for _, member := range members {
+		batch.Drifts = appendCounterDrift(batch.Drifts, model.COUNTER_TABLE_TEAM_MEMBERS, member.TeamId, member.UserId, model.COUNTER_COLUMN_POINTS, int64(member.Points), int64(userStore.pointsFromHistory(member.UserId, member.TeamId)))
+	}
+
+	if len(members) == limit {
+		last := members[len(members)-1]
+		batch.Next = &model.CounterCursor{TeamId: last.TeamId, Key: last.UserId}
+	}
+
+	return batch, nil
+}
+
+func (s *MemTeamStore) FixMemberPointsDrift(drift *model.CounterDrift) (bool, *model.AppError) {
+	if drift.Column != model.COUNTER_COLUMN_POINTS {
+		return false, model.NewAppError("MemTeamStore.FixMemberPointsDrift", "store.sql_team.fix_member_points_drift.column.app_error", nil, "column="+drift.Column, http.StatusBadRequest)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	member, ok := s.tables.teamMembers[teamMemberKey{drift.TeamId, drift.Key}]
+	if !ok || member.DeleteAt != 0 {
+		return false, nil
+	}
+
+	return updateCounterIfUnchanged(&member.Points, drift), nil
+}
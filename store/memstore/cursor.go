@@ -0,0 +1,84 @@
+package memstore
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/clear-ness/qa-discussion/utils"
+)
+
+// sqlstoreのkeysetと同じく、行のキーの組で並べてカーソルの位置から続きを返す。
+// keysはi番目の行のキーで、要素はint64かstring。cursorがnilならoffsetから返す。
+// 戻り値は表示順に並べた行の添字。
+func keysetPage(length int, keys func(i int) []interface{}, desc bool, cursor *model.Cursor, withKey bool, offset, limit int) []int {
+	var after []interface{}
+	before := false
+	if cursor != nil {
+		after = cursorValues(cursor, withKey)
+		before = cursor.Before
+		offset = 0
+	}
+	descending := desc != before
+
+	rows := make([]int, 0, length)
+	for i := 0; i < length; i++ {
+		if after != nil {
+			c := compareKeys(keys(i), after)
+			if (descending && c >= 0) || (!descending && c <= 0) {
+				continue
+			}
+		}
+		rows = append(rows, i)
+	}
+
+	sort.SliceStable(rows, func(a, b int) bool {
+		c := compareKeys(keys(rows[a]), keys(rows[b]))
+		if descending {
+			return c > 0
+		}
+		return c < 0
+	})
+
+	start, end := paginate(len(rows), offset, limit)
+	rows = rows[start:end]
+	if before {
+		utils.ReverseSlice(rows)
+	}
+
+	return rows
+}
+
+// sqlstoreのcursorValuesと同じく、カーソルのキーを行のキーと同じ順に並べる
+func cursorValues(cursor *model.Cursor, withKey bool) []interface{} {
+	var values []interface{}
+	if withKey {
+		values = append(values, cursor.Key)
+	}
+	for _, id := range cursor.Ids {
+		values = append(values, id)
+	}
+
+	return values
+}
+
+// 文字列はmysqlの照合順序と同じく大文字小文字を区別しない
+func compareKeys(a, b []interface{}) int {
+	for i := range a {
+		switch x := a[i].(type) {
+		case int64:
+			y := b[i].(int64)
+			if x < y {
+				return -1
+			} else if x > y {
+				return 1
+			}
+		case string:
+			if c := strings.Compare(strings.ToLower(x), strings.ToLower(b[i].(string))); c != 0 {
+				return c
+			}
+		}
+	}
+
+	return 0
+}
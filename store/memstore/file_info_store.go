@@ -0,0 +1,100 @@
+package memstore
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/clear-ness/qa-discussion/model"
+)
+
+type MemFileInfoStore struct {
+	*MemStore
+}
+
+func (s *MemFileInfoStore) Save(info *model.FileInfo) (*model.FileInfo, *model.AppError) {
+	info.PreSave()
+	if err := info.IsValid(); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.tables.fileInfos[info.Id]; ok {
+		return nil, model.NewAppError("MemFileInfoStore.Save", "store.sql_file_info.save.app_error", nil, "duplicate entry id="+info.Id, http.StatusInternalServerError)
+	}
+	s.tables.fileInfos[info.Id] = clone(info).(*model.FileInfo)
+
+	return info, nil
+}
+
+func (s *MemFileInfoStore) Get(id string) (*model.FileInfo, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	info, ok := s.tables.fileInfos[id]
+	if !ok || info.DeleteAt != 0 {
+		return nil, model.NewAppError("MemFileInfoStore.Get", "store.sql_file_info.get.app_error", nil, "id="+id, http.StatusNotFound)
+	}
+
+	return clone(info).(*model.FileInfo), nil
+}
+
+func (s *MemFileInfoStore) AttachToPost(fileId, postId, userId string) *model.AppError {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	info, ok := s.tables.fileInfos[fileId]
+	if !ok || info.PostId != "" || info.UserId != userId || info.DeleteAt != 0 {
+		return model.NewAppError("MemFileInfoStore.AttachToPost",
+			"store.sql_file_info.attach_to_post.app_error", nil, "post_id="+postId+", file_id="+fileId, http.StatusBadRequest)
+	}
+	info.PostId = postId
+
+	return nil
+}
+
+func (s *MemFileInfoStore) DeleteForPost(postId string) (string, *model.AppError) {
+	curTime := model.GetMillis()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, info := range s.tables.fileInfos {
+		if info.PostId == postId {
+			info.DeleteAt = curTime
+		}
+	}
+
+	return postId, nil
+}
+
+func (s *MemFileInfoStore) GetForPosts(postIds []string) ([]*model.FileInfo, *model.AppError) {
+	if len(postIds) == 0 {
+		return []*model.FileInfo{}, nil
+	}
+
+	ids := map[string]bool{}
+	for _, postId := range postIds {
+		ids[postId] = true
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var infos []*model.FileInfo
+	for _, info := range s.tables.fileInfos {
+		if ids[info.PostId] && info.DeleteAt == 0 {
+			infos = append(infos, clone(info).(*model.FileInfo))
+		}
+	}
+
+	sort.Slice(infos, func(a, b int) bool {
+		if infos[a].CreateAt == infos[b].CreateAt {
+			return infos[a].Id < infos[b].Id
+		}
+		return infos[a].CreateAt < infos[b].CreateAt
+	})
+
+	return infos, nil
+}
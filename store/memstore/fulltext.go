@@ -0,0 +1,129 @@
+package memstore
+
+import (
+	"strings"
+	"unicode"
+)
+
+// sqlstoreのfulltextTermsと同じ意味で、mysqlのboolean modeの全文検索を模倣する
+type fulltextTerms struct {
+	// 全て含む
+	required []string
+	// いずれかを含む
+	optional []string
+	// いずれも含まない
+	excluded []string
+	// optionalの各語をフレーズとして扱う
+	phrase bool
+}
+
+func fulltextWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(c rune) bool {
+		return !unicode.IsLetter(c) && !unicode.IsDigit(c) && c != '_'
+	})
+}
+
+// 末尾の*は前方一致として扱う
+func wordMatches(word string, term string) bool {
+	if strings.HasSuffix(term, "*") {
+		return strings.HasPrefix(word, strings.TrimSuffix(term, "*"))
+	}
+
+	return word == term
+}
+
+// 語の列が連続して現れるか
+func containsTerm(words []string, term string) bool {
+	termWords := fulltextWords(strings.TrimSuffix(term, "*"))
+	if len(termWords) == 0 {
+		return false
+	}
+	if strings.HasSuffix(term, "*") {
+		termWords[len(termWords)-1] += "*"
+	}
+
+	for i := 0; i+len(termWords) <= len(words); i++ {
+		matched := true
+		for j, termWord := range termWords {
+			if !wordMatches(words[i+j], termWord) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// 語の何れかを含むか
+func containsAnyWord(words []string, term string) bool {
+	for _, termWord := range strings.Fields(term) {
+		if containsTerm(words, termWord) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (t fulltextTerms) match(texts ...string) bool {
+	words := []string{}
+	for _, text := range texts {
+		words = append(words, fulltextWords(text)...)
+	}
+
+	for _, term := range t.required {
+		if !containsTerm(words, term) {
+			return false
+		}
+	}
+
+	if len(t.required) == 0 {
+		if len(t.optional) == 0 {
+			return false
+		}
+
+		found := false
+		for _, term := range t.optional {
+			if (t.phrase && containsTerm(words, term)) || (!t.phrase && containsAnyWord(words, term)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	for _, term := range t.excluded {
+		if containsAnyWord(words, term) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// 一致した語の数。relevanceの並び替えに使う
+func (t fulltextTerms) score(texts ...string) int {
+	words := []string{}
+	for _, text := range texts {
+		words = append(words, fulltextWords(text)...)
+	}
+
+	score := 0
+	for _, term := range append(append([]string{}, t.required...), t.optional...) {
+		for _, termWord := range fulltextWords(term) {
+			for _, word := range words {
+				if wordMatches(word, termWord) {
+					score++
+				}
+			}
+		}
+	}
+
+	return score
+}
@@ -0,0 +1,128 @@
+package memstore
+
+import (
+	"net/http"
+
+	"github.com/clear-ness/qa-discussion/model"
+)
+
+type MemInboxMessageStore struct {
+	*MemStore
+}
+
+// 最後に受信箱を見た時刻。ユーザーがいなければ0
+func (s *MemInboxMessageStore) lastInboxMessageViewed(userId string) int64 {
+	if user, ok := s.tables.users[userId]; ok {
+		return user.LastInboxMessageViewed
+	}
+
+	return 0
+}
+
+func (s *MemInboxMessageStore) GetSingle(id string) (*model.InboxMessage, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	message, ok := s.tables.inboxMessages[id]
+	if !ok {
+		return nil, model.NewAppError("MemInboxMessageStore.GetSingle", "store.sql_inbox_message.get_single.app_error", nil, "id="+id, http.StatusNotFound)
+	}
+
+	return clone(message).(*model.InboxMessage), nil
+}
+
+func (s *MemInboxMessageStore) GetInboxMessages(time int64, userId string, direction string, page, perPage int, teamId string, cursor *model.Cursor) ([]*model.InboxMessage, *model.AppError) {
+	if direction != ">" && direction != "<=" {
+		return nil, model.NewAppError("MemInboxMessageStore.GetInboxMessages", "store.sql_inbox_message.get_inbox_messages.app_error", nil, "", http.StatusInternalServerError)
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	matched := []*model.InboxMessage{}
+	for _, message := range s.tables.inboxMessages {
+		if message.UserId != userId || message.TeamId != teamId {
+			continue
+		}
+		if (direction == ">" && message.CreateAt <= time) || (direction == "<=" && message.CreateAt > time) {
+			continue
+		}
+		matched = append(matched, message)
+	}
+
+	keys := func(i int) []interface{} {
+		return []interface{}{matched[i].CreateAt, matched[i].Id}
+	}
+
+	lastMessageViewed := s.lastInboxMessageViewed(userId)
+
+	var messages []*model.InboxMessage
+	for _, i := range keysetPage(len(matched), keys, true, cursor, true, page*perPage, perPage) {
+		message := clone(matched[i]).(*model.InboxMessage)
+		if message.CreateAt > lastMessageViewed {
+			message.IsUnread = true
+		}
+		messages = append(messages, message)
+	}
+
+	return messages, nil
+}
+
+func (s *MemInboxMessageStore) GetInboxMessagesUnreadCount(userId string, fromDate int64, teamId string) (int64, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if fromDate == 0 {
+		fromDate = s.lastInboxMessageViewed(userId)
+	}
+
+	count := int64(0)
+	for _, message := range s.tables.inboxMessages {
+		if message.UserId == userId && message.TeamId == teamId && message.CreateAt > fromDate {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (s *MemInboxMessageStore) SaveInboxMessage(inboxMessage *model.InboxMessage) (*model.InboxMessage, *model.AppError) {
+	if len(inboxMessage.Id) > 0 {
+		return nil, model.NewAppError("MemInboxMessageStore.SaveInboxMessage", "store.sql_inbox_message.save_inbox_message.existing.app_error", nil, "id="+inboxMessage.Id, http.StatusBadRequest)
+	}
+
+	inboxMessage.PreSave()
+	if err := inboxMessage.IsValid(); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.tables.inboxMessages[inboxMessage.Id] = clone(inboxMessage).(*model.InboxMessage)
+
+	return inboxMessage, nil
+}
+
+func (s *MemInboxMessageStore) SaveMultipleInboxMessages(inboxMessages []*model.InboxMessage) ([]*model.InboxMessage, *model.AppError) {
+	for _, message := range inboxMessages {
+		if len(message.Id) > 0 {
+			return nil, model.NewAppError("MemInboxMessageStore.SaveMultipleInboxMessages", "store.sql_inbox_message.save_multiple_inbox_messages.existing.app_error", nil, "id="+message.Id, http.StatusInternalServerError)
+		}
+
+		message.PreSave()
+
+		if err := message.IsValid(); err != nil {
+			return nil, model.NewAppError("MemInboxMessageStore.SaveMultipleInboxMessages", "store.sql_inbox_message.save_multiple_inbox_messages.app_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, message := range inboxMessages {
+		s.tables.inboxMessages[message.Id] = clone(message).(*model.InboxMessage)
+	}
+
+	return inboxMessages, nil
+}
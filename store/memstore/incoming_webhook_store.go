@@ -0,0 +1,141 @@
+package memstore
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/clear-ness/qa-discussion/model"
+)
+
+type MemIncomingWebhookStore struct {
+	*MemStore
+}
+
+// Tokenはユニークキー。呼び出し元でロックを取る
+func (s *MemIncomingWebhookStore) tokenExists(hook *model.IncomingWebhook) bool {
+	for _, other := range s.tables.incomingWebhooks {
+		if other.Id != hook.Id && other.Token == hook.Token {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *MemIncomingWebhookStore) Save(hook *model.IncomingWebhook) (*model.IncomingWebhook, *model.AppError) {
+	if len(hook.Id) > 0 {
+		return nil, model.NewAppError("MemIncomingWebhookStore.Save", "store.sql_incoming_webhooks.save.existing.app_error", nil, "id="+hook.Id, http.StatusBadRequest)
+	}
+
+	hook.PreSave()
+	if err := hook.IsValid(); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.tokenExists(hook) {
+		return nil, model.NewAppError("MemIncomingWebhookStore.Save", "store.sql_incoming_webhooks.save.app_error", nil, "id="+hook.Id+", duplicate token", http.StatusInternalServerError)
+	}
+	s.tables.incomingWebhooks[hook.Id] = clone(hook).(*model.IncomingWebhook)
+
+	return hook, nil
+}
+
+func (s *MemIncomingWebhookStore) Get(id string) (*model.IncomingWebhook, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	hook, ok := s.tables.incomingWebhooks[id]
+	if !ok || hook.DeleteAt != 0 {
+		return nil, model.NewAppError("MemIncomingWebhookStore.Get", "store.sql_incoming_webhooks.get.missing.app_error", nil, "id="+id, http.StatusNotFound)
+	}
+
+	return clone(hook).(*model.IncomingWebhook), nil
+}
+
+func (s *MemIncomingWebhookStore) GetByToken(token string) (*model.IncomingWebhook, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for _, hook := range s.tables.incomingWebhooks {
+		if hook.Token == token && hook.DeleteAt == 0 {
+			return clone(hook).(*model.IncomingWebhook), nil
+		}
+	}
+
+	return nil, model.NewAppError("MemIncomingWebhookStore.GetByToken", "store.sql_incoming_webhooks.get_by_token.missing.app_error", nil, "", http.StatusNotFound)
+}
+
+func (s *MemIncomingWebhookStore) GetByTeam(teamId string, offset, limit int) ([]*model.IncomingWebhook, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	matched := []*model.IncomingWebhook{}
+	for _, hook := range s.tables.incomingWebhooks {
+		if hook.TeamId == teamId && hook.DeleteAt == 0 {
+			matched = append(matched, hook)
+		}
+	}
+
+	sort.Slice(matched, func(a, b int) bool {
+		if matched[a].CreateAt == matched[b].CreateAt {
+			return matched[a].Id < matched[b].Id
+		}
+		return matched[a].CreateAt > matched[b].CreateAt
+	})
+
+	var hooks []*model.IncomingWebhook
+	start, end := paginate(len(matched), offset, limit)
+	for _, hook := range matched[start:end] {
+		hooks = append(hooks, clone(hook).(*model.IncomingWebhook))
+	}
+
+	return hooks, nil
+}
+
+func (s *MemIncomingWebhookStore) Update(hook *model.IncomingWebhook) (*model.IncomingWebhook, *model.AppError) {
+	hook.UpdateAt = model.GetMillis()
+	if err := hook.IsValid(); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.tokenExists(hook) {
+		return nil, model.NewAppError("MemIncomingWebhookStore.Update", "store.sql_incoming_webhooks.update.app_error", nil, "id="+hook.Id+", duplicate token", http.StatusInternalServerError)
+	}
+	if _, ok := s.tables.incomingWebhooks[hook.Id]; ok {
+		s.tables.incomingWebhooks[hook.Id] = clone(hook).(*model.IncomingWebhook)
+	}
+
+	return hook, nil
+}
+
+func (s *MemIncomingWebhookStore) Delete(hookId string, time int64) *model.AppError {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if hook, ok := s.tables.incomingWebhooks[hookId]; ok {
+		hook.DeleteAt = time
+		hook.UpdateAt = time
+	}
+
+	return nil
+}
+
+func (s *MemIncomingWebhookStore) DeleteByBot(botUserId string, time int64) *model.AppError {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, hook := range s.tables.incomingWebhooks {
+		if hook.BotUserId == botUserId && hook.DeleteAt == 0 {
+			hook.DeleteAt = time
+			hook.UpdateAt = time
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package memstore
+
+import (
+	"net/http"
+
+	"github.com/clear-ness/qa-discussion/model"
+)
+
+type MemJobStore struct {
+	*MemStore
+}
+
+func (s *MemJobStore) Save(job *model.Job) (*model.Job, *model.AppError) {
+	if len(job.Id) > 0 {
+		return nil, model.NewAppError("MemJobStore.Save", "store.sql_job.save.existing.app_error", nil, "id="+job.Id, http.StatusBadRequest)
+	}
+
+	job.PreSave()
+	if err := job.IsValid(); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.tables.jobs[job.Id] = clone(job).(*model.Job)
+
+	return job, nil
+}
+
+func (s *MemJobStore) Get(id string) (*model.Job, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	job, ok := s.tables.jobs[id]
+	if !ok {
+		return nil, model.NewAppError("MemJobStore.Get", "store.sql_job.get.missing.app_error", nil, "id="+id, http.StatusNotFound)
+	}
+
+	return clone(job).(*model.Job), nil
+}
+
+func (s *MemJobStore) Update(job *model.Job) (*model.Job, *model.AppError) {
+	job.LastActivityAt = model.GetMillis()
+	if err := job.IsValid(); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.tables.jobs[job.Id]; ok {
+		s.tables.jobs[job.Id] = clone(job).(*model.Job)
+	}
+
+	return job, nil
+}
+
+func (s *MemJobStore) GetActiveCountByTeamAndType(teamId string, jobType string) (int64, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	count := int64(0)
+	for _, job := range s.tables.jobs {
+		if job.TeamId == teamId && job.Type == jobType && (job.Status == model.JOB_STATUS_PENDING || job.Status == model.JOB_STATUS_IN_PROGRESS) {
+			count++
+		}
+	}
+
+	return count, nil
+}
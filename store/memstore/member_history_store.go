@@ -0,0 +1,92 @@
+package memstore
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/clear-ness/qa-discussion/mlog"
+	"github.com/clear-ness/qa-discussion/model"
+)
+
+type MemTeamMemberHistoryStore struct {
+	*MemStore
+}
+
+func (s *MemTeamMemberHistoryStore) LogJoinEvent(userId string, teamId string, joinTime int64) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, history := range s.tables.teamMemberHistory {
+		if history.TeamId == teamId && history.UserId == userId && history.JoinTime == joinTime {
+			return errors.Errorf("LogJoinEvent userId=%s teamId=%s joinTime=%d: duplicate entry", userId, teamId, joinTime)
+		}
+	}
+
+	s.tables.teamMemberHistory = append(s.tables.teamMemberHistory, &model.TeamMemberHistory{
+		TeamId:   teamId,
+		UserId:   userId,
+		JoinTime: joinTime,
+	})
+
+	return nil
+}
+
+func (s *MemTeamMemberHistoryStore) LogLeaveEvent(userId string, teamId string, leaveTime int64) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	rows := 0
+	for _, history := range s.tables.teamMemberHistory {
+		if history.TeamId == teamId && history.UserId == userId && history.LeaveTime == nil {
+			history.LeaveTime = model.NewInt64(leaveTime)
+			rows++
+		}
+	}
+
+	if rows != 1 {
+		mlog.Warn("Team join event for user and team not found", mlog.String("user", userId), mlog.String("team", teamId))
+	}
+
+	return nil
+}
+
+type MemGroupMemberHistoryStore struct {
+	*MemStore
+}
+
+func (s *MemGroupMemberHistoryStore) LogJoinEvent(userId string, groupId string, joinTime int64) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, history := range s.tables.groupMemberHistory {
+		if history.GroupId == groupId && history.UserId == userId && history.JoinTime == joinTime {
+			return errors.Errorf("LogJoinEvent userId=%s groupId=%s joinTime=%d: duplicate entry", userId, groupId, joinTime)
+		}
+	}
+
+	s.tables.groupMemberHistory = append(s.tables.groupMemberHistory, &model.GroupMemberHistory{
+		GroupId:  groupId,
+		UserId:   userId,
+		JoinTime: joinTime,
+	})
+
+	return nil
+}
+
+func (s *MemGroupMemberHistoryStore) LogLeaveEvent(userId string, groupId string, leaveTime int64) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	rows := 0
+	for _, history := range s.tables.groupMemberHistory {
+		if history.GroupId == groupId && history.UserId == userId && history.LeaveTime == nil {
+			history.LeaveTime = model.NewInt64(leaveTime)
+			rows++
+		}
+	}
+
+	if rows != 1 {
+		mlog.Warn("Group join event for user and group not found", mlog.String("user", userId), mlog.String("group", groupId))
+	}
+
+	return nil
+}
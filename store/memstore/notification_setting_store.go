@@ -0,0 +1,70 @@
+package memstore
+
+import (
+	"net/http"
+
+	"github.com/clear-ness/qa-discussion/model"
+)
+
+type MemNotificationSettingStore struct {
+	*MemStore
+}
+
+func (s *MemNotificationSettingStore) getByUserId(userId string) *model.NotificationSetting {
+	for _, setting := range s.tables.notificationSetting {
+		if setting.UserId == userId {
+			return setting
+		}
+	}
+
+	return nil
+}
+
+func (s *MemNotificationSettingStore) Get(userId string) (*model.NotificationSetting, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	setting := s.getByUserId(userId)
+	if setting == nil {
+		return nil, model.NewAppError("MemNotificationSettingStore.get", "store.sql_notification_setting.get.select.app_error", nil, "user_id="+userId, http.StatusNotFound)
+	}
+
+	return clone(setting).(*model.NotificationSetting), nil
+}
+
+func (s *MemNotificationSettingStore) Save(userId, inboxInterval string) *model.AppError {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	current := s.getByUserId(userId)
+	if current == nil {
+		setting := &model.NotificationSetting{
+			UserId:        userId,
+			InboxInterval: inboxInterval,
+		}
+
+		setting.PreSave()
+		if err := setting.IsValid(); err != nil {
+			return err
+		}
+
+		s.tables.notificationSetting[setting.Id] = setting
+
+		return nil
+	}
+
+	if current.InboxInterval == inboxInterval {
+		return model.NewAppError("MemNotificationSettingStore.save", "store.sql_notification_setting.save.same_value.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	setting := clone(current).(*model.NotificationSetting)
+	setting.InboxInterval = inboxInterval
+	if err := setting.IsValid(); err != nil {
+		return err
+	}
+
+	setting.PreUpdate()
+	s.tables.notificationSetting[setting.Id] = setting
+
+	return nil
+}
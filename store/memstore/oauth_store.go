@@ -0,0 +1,318 @@
+package memstore
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/clear-ness/qa-discussion/model"
+)
+
+type MemOAuthStore struct {
+	*MemStore
+}
+
+// 主キー順に並べたページを返す
+func pageOAuthApps(matched []*model.OAuthApp, offset, limit int) []*model.OAuthApp {
+	sort.Slice(matched, func(a, b int) bool {
+		return matched[a].Id < matched[b].Id
+	})
+
+	var apps []*model.OAuthApp
+	start, end := paginate(len(matched), offset, limit)
+	for _, app := range matched[start:end] {
+		apps = append(apps, clone(app).(*model.OAuthApp))
+	}
+
+	return apps
+}
+
+// (ClientId, UserId)はユニークキー。呼び出し元でロックを取る
+func (as *MemOAuthStore) getAccessDataForApp(userId, clientId string) *model.AccessData {
+	for _, accessData := range as.tables.oauthAccessData {
+		if accessData.UserId == userId && accessData.ClientId == clientId {
+			return accessData
+		}
+	}
+
+	return nil
+}
+
+func (as *MemOAuthStore) SaveApp(app *model.OAuthApp) (*model.OAuthApp, *model.AppError) {
+	if len(app.Id) > 0 {
+		return nil, model.NewAppError("MemOAuthStore.SaveApp", "store.sql_oauth.save_app.existing.app_error", nil, "app_id="+app.Id, http.StatusBadRequest)
+	}
+
+	app.PreSave()
+	if err := app.IsValid(); err != nil {
+		return nil, err
+	}
+
+	as.mutex.Lock()
+	defer as.mutex.Unlock()
+
+	as.tables.oauthApps[app.Id] = clone(app).(*model.OAuthApp)
+
+	return app, nil
+}
+
+func (as *MemOAuthStore) GetApp(id string) (*model.OAuthApp, *model.AppError) {
+	as.mutex.RLock()
+	defer as.mutex.RUnlock()
+
+	app, ok := as.tables.oauthApps[id]
+	if !ok {
+		return nil, model.NewAppError("MemOAuthStore.GetApp", "store.sql_oauth.get_app.find.app_error", nil, "app_id="+id, http.StatusNotFound)
+	}
+
+	return clone(app).(*model.OAuthApp), nil
+}
+
+func (as *MemOAuthStore) UpdateApp(app *model.OAuthApp) (*model.OAuthApp, *model.AppError) {
+	app.PreUpdate()
+
+	if err := app.IsValid(); err != nil {
+		return nil, err
+	}
+
+	as.mutex.Lock()
+	defer as.mutex.Unlock()
+
+	oldApp, ok := as.tables.oauthApps[app.Id]
+	if !ok {
+		return nil, model.NewAppError("MemOAuthStore.UpdateApp", "store.sql_oauth.update_app.find.app_error", nil, "app_id="+app.Id, http.StatusBadRequest)
+	}
+
+	app.CreateAt = oldApp.CreateAt
+	app.UserId = oldApp.UserId
+	as.tables.oauthApps[app.Id] = clone(app).(*model.OAuthApp)
+
+	return app, nil
+}
+
+func (as *MemOAuthStore) GetAppByUserId(userId string, offset, limit int) ([]*model.OAuthApp, *model.AppError) {
+	as.mutex.RLock()
+	defer as.mutex.RUnlock()
+
+	matched := []*model.OAuthApp{}
+	for _, app := range as.tables.oauthApps {
+		if app.UserId == userId {
+			matched = append(matched, app)
+		}
+	}
+
+	return pageOAuthApps(matched, offset, limit), nil
+}
+
+// アプリと、そのアプリで発行したセッション、トークン、認可を全て消す
+func (as *MemOAuthStore) DeleteApp(id string) *model.AppError {
+	as.mutex.Lock()
+	defer as.mutex.Unlock()
+
+	delete(as.tables.oauthApps, id)
+
+	for token, accessData := range as.tables.oauthAccessData {
+		if accessData.ClientId != id {
+			continue
+		}
+		for sessionId, session := range as.tables.sessions {
+			if session.Token == token {
+				delete(as.tables.sessions, sessionId)
+			}
+		}
+		delete(as.tables.oauthAccessData, token)
+	}
+
+	for key := range as.tables.oauthAuthorizedApps {
+		if key.clientId == id {
+			delete(as.tables.oauthAuthorizedApps, key)
+		}
+	}
+
+	return nil
+}
+
+func (as *MemOAuthStore) GetAuthorizedApps(userId string, offset, limit int) ([]*model.OAuthApp, *model.AppError) {
+	as.mutex.RLock()
+	defer as.mutex.RUnlock()
+
+	matched := []*model.OAuthApp{}
+	for key := range as.tables.oauthAuthorizedApps {
+		if key.userId != userId {
+			continue
+		}
+		if app, ok := as.tables.oauthApps[key.clientId]; ok {
+			matched = append(matched, app)
+		}
+	}
+
+	return pageOAuthApps(matched, offset, limit), nil
+}
+
+func (as *MemOAuthStore) DeleteAuthorizedApp(userId string, clientId string) *model.AppError {
+	as.mutex.Lock()
+	defer as.mutex.Unlock()
+
+	delete(as.tables.oauthAuthorizedApps, oauthAuthorizedAppKey{userId, clientId})
+
+	return nil
+}
+
+func (as *MemOAuthStore) SaveAuthData(authData *model.AuthData) (*model.AuthData, *model.AppError) {
+	authData.PreSave()
+
+	if err := authData.IsValid(); err != nil {
+		return nil, err
+	}
+
+	as.mutex.Lock()
+	defer as.mutex.Unlock()
+
+	if _, ok := as.tables.oauthAuthData[authData.Code]; ok {
+		return nil, model.NewAppError("MemOAuthStore.SaveAuthData", "store.sql_oauth.save_auth_data.app_error", nil, "duplicate entry", http.StatusInternalServerError)
+	}
+	as.tables.oauthAuthData[authData.Code] = clone(authData).(*model.AuthData)
+
+	return authData, nil
+}
+
+func (as *MemOAuthStore) SaveAuthorizedApp(app *model.OAuthAuthorizedApp) *model.AppError {
+	if err := app.IsValid(); err != nil {
+		return err
+	}
+
+	as.mutex.Lock()
+	defer as.mutex.Unlock()
+
+	key := oauthAuthorizedAppKey{app.UserId, app.ClientId}
+	if _, ok := as.tables.oauthAuthorizedApps[key]; ok {
+		return model.NewAppError("MemOAuthStore.SaveAuthorizedApp", "store.sql_oauth.save_authorized_app.app_error", nil, "duplicate entry", http.StatusInternalServerError)
+	}
+	as.tables.oauthAuthorizedApps[key] = clone(app).(*model.OAuthAuthorizedApp)
+
+	return nil
+}
+
+func (as *MemOAuthStore) GetAccessData(token string) (*model.AccessData, *model.AppError) {
+	as.mutex.RLock()
+	defer as.mutex.RUnlock()
+
+	accessData, ok := as.tables.oauthAccessData[token]
+	if !ok {
+		return nil, model.NewAppError("MemOAuthStore.GetAccessData", "store.sql_oauth.get_access_data.app_error", nil, "sql: no rows in result set", http.StatusInternalServerError)
+	}
+
+	return clone(accessData).(*model.AccessData), nil
+}
+
+func (as *MemOAuthStore) RemoveAccessData(token string) *model.AppError {
+	as.mutex.Lock()
+	defer as.mutex.Unlock()
+
+	delete(as.tables.oauthAccessData, token)
+
+	return nil
+}
+
+func (as *MemOAuthStore) SaveAccessData(accessData *model.AccessData) (*model.AccessData, *model.AppError) {
+	if err := accessData.IsValid(); err != nil {
+		return nil, err
+	}
+
+	as.mutex.Lock()
+	defer as.mutex.Unlock()
+
+	if _, ok := as.tables.oauthAccessData[accessData.Token]; ok || as.getAccessDataForApp(accessData.UserId, accessData.ClientId) != nil {
+		return nil, model.NewAppError("MemOAuthStore.SaveAccessData", "store.sql_oauth.save_access_data.app_error", nil, "duplicate entry", http.StatusInternalServerError)
+	}
+	as.tables.oauthAccessData[accessData.Token] = clone(accessData).(*model.AccessData)
+
+	return accessData, nil
+}
+
+func (as *MemOAuthStore) GetAuthData(code string) (*model.AuthData, *model.AppError) {
+	as.mutex.RLock()
+	defer as.mutex.RUnlock()
+
+	authData, ok := as.tables.oauthAuthData[code]
+	if !ok {
+		return nil, model.NewAppError("MemOAuthStore.GetAuthData", "store.sql_oauth.get_auth_data.find.app_error", nil, "", http.StatusNotFound)
+	}
+
+	return clone(authData).(*model.AuthData), nil
+}
+
+func (as *MemOAuthStore) RemoveAuthData(code string) *model.AppError {
+	as.mutex.Lock()
+	defer as.mutex.Unlock()
+
+	delete(as.tables.oauthAuthData, code)
+
+	return nil
+}
+
+func (as *MemOAuthStore) GetPreviousAccessData(userId, clientId string) (*model.AccessData, *model.AppError) {
+	as.mutex.RLock()
+	defer as.mutex.RUnlock()
+
+	accessData := as.getAccessDataForApp(userId, clientId)
+	if accessData == nil {
+		return nil, nil
+	}
+
+	return clone(accessData).(*model.AccessData), nil
+}
+
+// 主キーのTokenも書き換わるので、古いキーを消して入れ直す
+func (as *MemOAuthStore) UpdateAccessData(accessData *model.AccessData) (*model.AccessData, *model.AppError) {
+	if err := accessData.IsValid(); err != nil {
+		return nil, err
+	}
+
+	as.mutex.Lock()
+	defer as.mutex.Unlock()
+
+	current := as.getAccessDataForApp(accessData.UserId, accessData.ClientId)
+	if current == nil {
+		return accessData, nil
+	}
+
+	if other, ok := as.tables.oauthAccessData[accessData.Token]; ok && other != current {
+		return nil, model.NewAppError("MemOAuthStore.Update", "store.sql_oauth.update_access_data.app_error", nil,
+			"clientId="+accessData.ClientId+",userId="+accessData.UserId+", duplicate token", http.StatusInternalServerError)
+	}
+
+	updated := clone(current).(*model.AccessData)
+	updated.Token = accessData.Token
+	updated.ExpiresAt = accessData.ExpiresAt
+	updated.RefreshToken = accessData.RefreshToken
+
+	delete(as.tables.oauthAccessData, current.Token)
+	as.tables.oauthAccessData[updated.Token] = updated
+
+	return accessData, nil
+}
+
+func (as *MemOAuthStore) GetAccessDataByRefreshToken(token string) (*model.AccessData, *model.AppError) {
+	as.mutex.RLock()
+	defer as.mutex.RUnlock()
+
+	for _, accessData := range as.tables.oauthAccessData {
+		if accessData.RefreshToken == token {
+			return clone(accessData).(*model.AccessData), nil
+		}
+	}
+
+	return nil, model.NewAppError("MemOAuthStore.GetAccessData", "store.sql_oauth.get_access_data.app_error", nil, "sql: no rows in result set", http.StatusInternalServerError)
+}
+
+func (as *MemOAuthStore) GetAccessDataByUserForApp(userId, clientId string) ([]*model.AccessData, *model.AppError) {
+	as.mutex.RLock()
+	defer as.mutex.RUnlock()
+
+	var accessData []*model.AccessData
+	if current := as.getAccessDataForApp(userId, clientId); current != nil {
+		accessData = append(accessData, clone(current).(*model.AccessData))
+	}
+
+	return accessData, nil
+}
@@ -0,0 +1,1553 @@
+package memstore
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/clear-ness/qa-discussion/utils"
+)
+
+// migrationのPosts.Content(text)から求まる上限と同じ
+const maxPostSize = 65535 / 4
+
+// https://stackoverflow.com/questions/25088183/mysql-fulltext-search-with-symbol-produces-error-syntax-error-unexpected
+var specialSearchChar = []string{
+	"<",
+	">",
+	"+",
+	"-",
+	"(",
+	")",
+	"~",
+	"@",
+	":",
+	".",
+}
+
+type MemPostStore struct {
+	*MemStore
+}
+
+// sqlstoreの"TeamId IS NULL"と同じ条件。
+// TeamIdは空文字で保存されNULLにはならないため、teamIdが空の場合は何もヒットしない
+func matchesNullableTeam(postTeamId string, teamId string) bool {
+	return teamId != "" && postTeamId == teamId
+}
+
+func (s *MemPostStore) sortedPosts() []*model.Post {
+	posts := make([]*model.Post, 0, len(s.tables.posts))
+	for _, post := range s.tables.posts {
+		posts = append(posts, post)
+	}
+	sort.Slice(posts, func(a, b int) bool {
+		return posts[a].Id < posts[b].Id
+	})
+
+	return posts
+}
+
+func (s *MemPostStore) getActivePost(id string) (*model.Post, bool) {
+	post, ok := s.tables.posts[id]
+	if !ok || post.DeleteAt != 0 {
+		return nil, false
+	}
+
+	return post, true
+}
+
+// teamに属さない投稿はUsers、それ以外はTeamMembersのポイントを加算する
+func (s *MemPostStore) addPoints(teamId string, userId string, points int, curTime int64) {
+	if len(teamId) == 0 {
+		if user, ok := s.tables.users[userId]; ok {
+			user.Points += points
+			user.UpdateAt = curTime
+		}
+		return
+	}
+
+	if member, ok := s.tables.teamMembers[teamMemberKey{teamId, userId}]; ok && member.DeleteAt == 0 {
+		member.Points += points
+	}
+}
+
+func (s *MemPostStore) createSystemReview(post *model.Post, userId string, tagContents string, time int64) {
+	review := &model.Vote{
+		PostId:       post.Id,
+		UserId:       userId,
+		Type:         model.VOTE_TYPE_SYSTEM,
+		Tags:         tagContents,
+		TeamId:       post.TeamId,
+		FirstPostRev: 1,
+		CreateAt:     time,
+	}
+
+	key := voteKey{review.UserId, review.Type, review.PostId}
+	if _, ok := s.tables.votes[key]; !ok {
+		s.tables.votes[key] = review
+	}
+}
+
+// 既に存在するタグはPostCountを加算する
+func (s *MemPostStore) upsertTags(addedTags []string, time int64, teamId string) *model.AppError {
+	tags := []*model.Tag{}
+	for _, tagContent := range addedTags {
+		tag := &model.Tag{
+			Content:   tagContent,
+			TeamId:    teamId,
+			Type:      "",
+			PostCount: 1,
+			CreateAt:  time,
+			UpdateAt:  time,
+		}
+
+		tag.PreSave()
+		if err := tag.IsValid(); err != nil {
+			return err
+		}
+		tags = append(tags, tag)
+	}
+
+	for _, tag := range tags {
+		key := newTagKey(tag.Content, tag.TeamId, tag.Type)
+		if existing, ok := s.tables.tags[key]; ok {
+			existing.PostCount++
+			existing.UpdateAt = tag.UpdateAt
+		} else {
+			s.tables.tags[key] = tag
+		}
+	}
+
+	return nil
+}
+
+func (s *MemPostStore) decrementTags(tagContents []string, teamId string, curTime int64) {
+	for _, tag := range s.tables.tags {
+		for _, tagContent := range tagContents {
+			if tag.TeamId == teamId && strings.EqualFold(tag.Content, tagContent) {
+				tag.PostCount--
+				if curTime != 0 {
+					tag.UpdateAt = curTime
+				}
+			}
+		}
+	}
+}
+
+func (s *MemPostStore) SaveQuestion(post *model.Post) (*model.Post, *model.AppError) {
+	if len(post.Id) > 0 {
+		return nil, model.NewAppError("MemPostStore.saveQuestion", "store.sql_post.save_question.existing.app_error", nil, "id="+post.Id, http.StatusBadRequest)
+	}
+
+	post.PreSave()
+	if err := post.IsValid(maxPostSize); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	curTime := model.GetMillis()
+
+	if err := s.upsertTags(strings.Fields(post.Tags), curTime, post.TeamId); err != nil {
+		return nil, err
+	}
+
+	s.tables.posts[post.Id] = clone(post).(*model.Post)
+	s.addPoints(post.TeamId, post.UserId, model.USER_POINT_FOR_CREATE_QUESTION, curTime)
+
+	s.saveUserPointHistory(&model.UserPointHistory{
+		Id:       model.NewId(),
+		TeamId:   post.TeamId,
+		UserId:   post.UserId,
+		Type:     model.USER_POINT_TYPE_CREATE_QUESTION,
+		PostId:   post.Id,
+		PostType: post.Type,
+		Tags:     post.Tags,
+		Points:   model.USER_POINT_FOR_CREATE_QUESTION,
+		CreateAt: curTime,
+	})
+
+	if s.getPostCount("", post.UserId, post.TeamId, 0, 0) <= 1 {
+		s.createSystemReview(post, "", model.SYSTEM_TAG_FIRST_POSTS, curTime)
+	}
+
+	return post, nil
+}
+
+func (s *MemPostStore) SaveUserPointHistory(history *model.UserPointHistory) (*model.UserPointHistory, *model.AppError) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.saveUserPointHistory(history), nil
+}
+
+// botはポイントの対象外
+func (s *MemPostStore) saveUserPointHistory(history *model.UserPointHistory) *model.UserPointHistory {
+	if user, ok := s.tables.users[history.UserId]; ok && user.Type == model.USER_TYPE_BOT {
+		return nil
+	}
+
+	s.tables.userPointHistory[history.Id] = clone(history).(*model.UserPointHistory)
+
+	return history
+}
+
+// importでは作成日時などを元データのまま保存し、ポイントの付与やシステムレビューの作成は行わない。
+// OriginalIdが指定された場合は編集履歴として保存する。
+func (s *MemPostStore) ImportPost(post *model.Post) (*model.Post, *model.AppError) {
+	if len(post.Id) > 0 {
+		return nil, model.NewAppError("MemPostStore.ImportPost", "store.sql_post.import_post.existing.app_error", nil, "id="+post.Id, http.StatusBadRequest)
+	}
+
+	post.Id = model.NewId()
+	if post.CreateAt == 0 {
+		post.CreateAt = model.GetMillis()
+	}
+	if post.UpdateAt == 0 {
+		post.UpdateAt = post.CreateAt
+	}
+	post.MakeNonNil()
+
+	if err := post.IsValid(maxPostSize); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if len(post.OriginalId) == 0 {
+		switch post.Type {
+		case model.POST_TYPE_QUESTION:
+			if err := s.upsertTags(strings.Fields(post.Tags), post.CreateAt, post.TeamId); err != nil {
+				return nil, model.NewAppError("MemPostStore.ImportPost", "store.sql_post.import_post.app_error", nil, err.Error(), http.StatusInternalServerError)
+			}
+		case model.POST_TYPE_ANSWER:
+			if parent, ok := s.tables.posts[post.ParentId]; ok {
+				parent.AnswerCount++
+			}
+		}
+	}
+
+	s.tables.posts[post.Id] = clone(post).(*model.Post)
+
+	return post, nil
+}
+
+// 再実行時に重複して取り込まないよう、元データのidをPropsに持つ投稿(編集履歴を含む)を返す。
+// Contentなどは読み込まない。
+func (s *MemPostStore) GetImportedPosts(teamId string, propKey string, propPrefix string) ([]*model.Post, *model.AppError) {
+	pattern := "%" + sanitizeSearchTerm(`"`+propKey+`":"`+propPrefix, "*") + "%"
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var posts []*model.Post
+	for _, post := range s.sortedPosts() {
+		if post.TeamId != teamId || !likeMatch(model.StringInterfaceToJson(post.Props), pattern, '*') {
+			continue
+		}
+
+		imported := &model.Post{
+			Id:         post.Id,
+			Type:       post.Type,
+			RootId:     post.RootId,
+			ParentId:   post.ParentId,
+			OriginalId: post.OriginalId,
+			UserId:     post.UserId,
+			Props:      post.Props,
+		}
+		posts = append(posts, clone(imported).(*model.Post))
+	}
+
+	return posts, nil
+}
+
+// 投票者の分からない投票数を取り込むため、加算ではなく値をそのまま設定する
+func (s *MemPostStore) SetVoteCounts(postId string, upVotes int, downVotes int) *model.AppError {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if post, ok := s.tables.posts[postId]; ok {
+		post.UpVotes = upVotes
+		post.DownVotes = downVotes
+		post.Points = upVotes - downVotes
+	}
+
+	return nil
+}
+
+func (s *MemPostStore) SaveAnswer(post *model.Post) (*model.Post, *model.AppError) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	parent, ok := s.getActivePost(post.ParentId)
+	if !ok {
+		return nil, model.NewAppError("MemPostStore.SaveAnswer", "store.sql_post.save_answer.parent.app_error", nil, "parent_id="+post.ParentId, http.StatusInternalServerError)
+	}
+
+	if len(post.Id) > 0 {
+		return nil, model.NewAppError("MemPostStore.saveAnswer", "store.sql_post.save_answer.existing.app_error", nil, "id="+post.Id, http.StatusBadRequest)
+	}
+
+	post.PreSave()
+	if err := post.IsValid(maxPostSize); err != nil {
+		return nil, err
+	}
+
+	curTime := model.GetMillis()
+
+	s.tables.posts[post.Id] = clone(post).(*model.Post)
+	parent.AnswerCount++
+	parent.UpdateAt = curTime
+
+	// prevent self point gain
+	if post.UserId != parent.UserId {
+		s.addPoints(post.TeamId, post.UserId, model.USER_POINT_FOR_CREATE_ANSWER, curTime)
+
+		s.saveUserPointHistory(&model.UserPointHistory{
+			Id:       model.NewId(),
+			TeamId:   post.TeamId,
+			UserId:   post.UserId,
+			Type:     model.USER_POINT_TYPE_CREATE_ANSWER,
+			PostId:   post.Id,
+			PostType: post.Type,
+			Tags:     parent.Tags,
+			Points:   model.USER_POINT_FOR_CREATE_ANSWER,
+			CreateAt: curTime,
+		})
+	}
+
+	tags := []string{}
+	if s.getPostCount("", post.UserId, post.TeamId, 0, 0) <= 1 {
+		tags = append(tags, model.SYSTEM_TAG_FIRST_POSTS)
+	}
+	if (curTime - parent.CreateAt) > model.LATE_ANSWERS_MILLIS {
+		tags = append(tags, model.SYSTEM_TAG_LATE_ANSWERS)
+	}
+
+	if len(tags) > 0 {
+		s.createSystemReview(post, "", strings.Join(tags, " "), curTime)
+	}
+
+	return post, nil
+}
+
+func (s *MemPostStore) SaveComment(post *model.Post) (*model.Post, *model.AppError) {
+	if len(post.Id) > 0 {
+		return nil, model.NewAppError("MemPostStore.SaveComment", "store.sql_post.save_comment.existing.app_error", nil, "id="+post.Id, http.StatusBadRequest)
+	}
+
+	post.PreSave()
+	if err := post.IsValid(maxPostSize); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if len(s.getCommentsForPost(post.ParentId, model.POST_COMMENT_LIMIT)) >= model.POST_COMMENT_LIMIT {
+		return nil, model.NewAppError("MemPostStore.saveComment", "store.sql_post.save_comment.max_limit.app_error", nil, "id="+post.Id, http.StatusBadRequest)
+	}
+
+	curTime := model.GetMillis()
+
+	s.tables.posts[post.Id] = clone(post).(*model.Post)
+
+	if s.getPostCount("", post.UserId, post.TeamId, 0, 0) <= 1 {
+		s.createSystemReview(post, "", model.SYSTEM_TAG_FIRST_POSTS, curTime)
+	}
+
+	return post, nil
+}
+
+// 編集前の投稿は、OriginalIdに元のIdを持つ削除済みの投稿(編集履歴)として保存する
+func (s *MemPostStore) Update(newPost *model.Post, oldPost *model.Post) (*model.Post, *model.AppError) {
+	removedTags := []string{}
+	addedTags := []string{}
+
+	if model.POST_TYPE_QUESTION == newPost.Type {
+		removedTags = utils.StringSliceDiff(strings.Fields(oldPost.Tags), strings.Fields(newPost.Tags))
+		addedTags = utils.StringSliceDiff(strings.Fields(newPost.Tags), strings.Fields(oldPost.Tags))
+	}
+
+	newPost.UpdateAt = model.GetMillis()
+
+	oldPost.DeleteAt = newPost.UpdateAt
+	oldPost.UpdateAt = newPost.UpdateAt
+	oldPost.OriginalId = oldPost.Id
+	oldPost.Id = model.NewId()
+
+	if err := newPost.IsValid(maxPostSize); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	curTime := model.GetMillis()
+
+	if err := s.upsertTags(addedTags, curTime, newPost.TeamId); err != nil {
+		return nil, model.NewAppError("MemPostStore.update", "store.sql_post.update.updating.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	s.decrementTags(removedTags, newPost.TeamId, curTime)
+
+	if _, ok := s.tables.posts[newPost.Id]; ok {
+		s.tables.posts[newPost.Id] = clone(newPost).(*model.Post)
+	}
+
+	s.tables.posts[oldPost.Id] = clone(oldPost).(*model.Post)
+
+	return newPost, nil
+}
+
+func (s *MemPostStore) GetSingle(id string, includeDeleted bool) (*model.Post, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	post, ok := s.tables.posts[id]
+	if !ok || (!includeDeleted && post.DeleteAt != 0) {
+		return nil, model.NewAppError("MemPostStore.GetSingle", "store.sql_post.get.app_error", nil, "id="+id, http.StatusNotFound)
+	}
+
+	return clone(post).(*model.Post), nil
+}
+
+func (s *MemPostStore) GetSingleByType(id string, postType string) (*model.Post, *model.AppError) {
+	if postType != model.POST_TYPE_QUESTION && postType != model.POST_TYPE_ANSWER && postType != model.POST_TYPE_COMMENT {
+		return nil, model.NewAppError("MemPostStore.GetSingleByType", "store.sql_post.get_by_type.app_error", nil, "id="+id, http.StatusNotFound)
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	post, ok := s.getActivePost(id)
+	if !ok || post.Type != postType {
+		return nil, model.NewAppError("MemPostStore.GetSingleByType", "store.sql_post.get_by_type.app_error", nil, "id="+id, http.StatusNotFound)
+	}
+
+	return clone(post).(*model.Post), nil
+}
+
+func (s *MemPostStore) GetPostCount(postType string, userId string, teamId string, fromDate int64, toDate int64) (int64, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.getPostCount(postType, userId, teamId, fromDate, toDate), nil
+}
+
+func (s *MemPostStore) getPostCount(postType string, userId string, teamId string, fromDate int64, toDate int64) int64 {
+	count := int64(0)
+	for _, post := range s.tables.posts {
+		if post.DeleteAt != 0 || !matchesNullableTeam(post.TeamId, teamId) {
+			continue
+		}
+		if postType != "" && post.Type != postType {
+			continue
+		}
+		if userId != "" && post.UserId != userId {
+			continue
+		}
+		if fromDate != 0 && post.CreateAt < fromDate {
+			continue
+		}
+		if toDate != 0 && post.CreateAt > toDate {
+			continue
+		}
+		count++
+	}
+
+	return count
+}
+
+func (s *MemPostStore) GetPostsByIds(postIds []string) (model.Posts, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var posts model.Posts
+	for _, postId := range uniqueStrings(postIds) {
+		if post, ok := s.getActivePost(postId); ok {
+			posts = append(posts, clone(post).(*model.Post))
+		}
+	}
+
+	sort.SliceStable(posts, func(a, b int) bool {
+		return posts[a].CreateAt > posts[b].CreateAt
+	})
+
+	return posts, nil
+}
+
+func (s *MemPostStore) GetPosts(options *model.GetPostsOptions, getCount bool) (model.Posts, int64, *model.AppError) {
+	searchOptions := &model.SearchPostsOptions{
+		UserId:         options.UserId,
+		SortType:       options.SortType,
+		PostType:       options.PostType,
+		Ids:            []string{},
+		ParentId:       options.ParentId,
+		FromDate:       options.FromDate,
+		ToDate:         options.ToDate,
+		Page:           options.Page,
+		PerPage:        options.PerPage,
+		TeamId:         options.TeamId,
+		IncludeDeleted: options.IncludeDeleted,
+		OriginalId:     options.OriginalId,
+		Cursor:         options.Cursor,
+	}
+
+	if options.Title != "" {
+		searchOptions.Terms = options.Title
+		if options.Tagged != "" {
+			searchOptions.Terms += " " + options.Tagged
+		}
+		searchOptions.TermsType = model.TERMS_TYPE_SIMILAR
+	} else if options.Link != "" {
+		searchOptions.Terms = options.Link
+		searchOptions.TermsType = model.TERMS_TYPE_LINK
+	} else if options.Tagged != "" {
+		searchOptions.Terms = options.Tagged
+		searchOptions.TermsType = model.TERMS_TYPE_TAG
+	}
+
+	if options.SortType == model.POST_SORT_TYPE_VOTES {
+		searchOptions.MinVotes = options.Min
+		searchOptions.MaxVotes = options.Max
+	} else if options.SortType == model.POST_SORT_TYPE_ANSWERS {
+		searchOptions.MinAnswers = options.Min
+		searchOptions.MaxAnswers = options.Max
+	}
+
+	if options.NoAnswers {
+		zero := 0
+		searchOptions.MinAnswers = &zero
+		searchOptions.MaxAnswers = &zero
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	matched := s.searchPosts(searchOptions)
+
+	totalCount := int64(0)
+	if getCount {
+		totalCount = int64(len(matched))
+	}
+
+	var posts model.Posts
+	offset := searchOptions.Page * searchOptions.PerPage
+	cursor := searchOptions.Cursor
+	if cursor != nil && cursor.IsOffset() {
+		offset = cursor.Offset
+		cursor = nil
+	}
+
+	if searchOptions.TermsType == model.TERMS_TYPE_SIMILAR && searchOptions.SortType == model.POST_SORT_TYPE_RELEVANCE {
+		start, end := paginate(len(matched), offset, searchOptions.PerPage)
+		for _, post := range matched[start:end] {
+			posts = append(posts, clone(post).(*model.Post))
+		}
+
+		return posts, totalCount, nil
+	}
+
+	// sqlstoreと同じく並びのキーが同じ場合はIdの降順
+	keys := func(i int) []interface{} {
+		return []interface{}{matched[i].SortKey(searchOptions.SortType), matched[i].Id}
+	}
+	for _, i := range keysetPage(len(matched), keys, true, cursor, true, offset, searchOptions.PerPage) {
+		posts = append(posts, clone(matched[i]).(*model.Post))
+	}
+
+	return posts, totalCount, nil
+}
+
+// advanced search
+func (s *MemPostStore) SearchPosts(paramsList []*model.SearchParams, sortType string, page, perPage int, teamId string) (model.Posts, int64, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	// get conjunction of results
+	var finalPostMap map[string]*model.Post
+	for _, params := range paramsList {
+		if params.Terms == "*" {
+			continue
+		}
+
+		fromDate := int64(0)
+		if params.FromDate != "" {
+			fromDate = params.GetFromDateMillis()
+		}
+		toDate := int64(0)
+		if params.ToDate != "" {
+			toDate = params.GetToDateMillis()
+		}
+
+		matched := s.searchPosts(&model.SearchPostsOptions{
+			Terms:         params.Terms,
+			ExcludedTerms: params.ExcludedTerms,
+			TermsType:     params.TermsType,
+			UserId:        params.User,
+			SortType:      sortType,
+			MinVotes:      params.MinVotes,
+			MaxVotes:      params.MaxVotes,
+			MinAnswers:    params.MinAnswers,
+			MaxAnswers:    params.MaxAnswers,
+			PostType:      params.PostType,
+			Ids:           params.Ids,
+			ParentId:      params.Parent,
+			FromDate:      fromDate,
+			ToDate:        toDate,
+			TeamId:        teamId,
+		})
+		if len(matched) > model.POST_SEARCH_MAX_COUNT*5 {
+			matched = matched[:model.POST_SEARCH_MAX_COUNT*5]
+		}
+
+		postMap := map[string]*model.Post{}
+		for _, post := range matched {
+			if finalPostMap == nil || finalPostMap[post.Id] != nil {
+				postMap[post.Id] = post
+			}
+		}
+		finalPostMap = postMap
+	}
+
+	var posts model.Posts
+	for _, post := range finalPostMap {
+		posts = append(posts, clone(post).(*model.Post))
+	}
+
+	sort.Slice(posts, func(i, j int) bool {
+		switch sortType {
+		case model.POST_SORT_TYPE_ACTIVE:
+			return posts[i].UpdateAt > posts[j].UpdateAt
+		case model.POST_SORT_TYPE_VOTES:
+			return posts[i].Points > posts[j].Points
+		default:
+			return posts[i].CreateAt > posts[j].CreateAt
+		}
+	})
+
+	if len(posts) > model.POST_SEARCH_MAX_COUNT {
+		posts = posts[:model.POST_SEARCH_MAX_COUNT]
+	}
+
+	totalCount := int64(len(posts))
+
+	if len(posts) > page*perPage {
+		start, end := paginate(len(posts), page*perPage, perPage)
+		return posts[start:end], totalCount, nil
+	}
+
+	return nil, totalCount, nil
+}
+
+// sqlstoreのsearchPostsと同じ条件で絞り込み、並び替えた投稿を返す。LIMIT, OFFSETは呼び出し元で適用する
+func (s *MemPostStore) searchPosts(options *model.SearchPostsOptions) []*model.Post {
+	terms := options.Terms
+	excludedTerms := options.ExcludedTerms
+
+	for _, c := range specialSearchChar {
+		if options.TermsType != model.TERMS_TYPE_LINK {
+			terms = strings.Replace(terms, c, " ", -1)
+		}
+		excludedTerms = strings.Replace(excludedTerms, c, " ", -1)
+	}
+
+	fulltext := fulltextTerms{
+		excluded: strings.Fields(excludedTerms),
+		phrase:   options.TermsType == model.TERMS_TYPE_LINK,
+	}
+	for _, t := range strings.Fields(terms) {
+		if len(t) >= model.TAG_MIN_RUNES {
+			if options.TermsType == model.TERMS_TYPE_SIMILAR || options.TermsType == model.TERMS_TYPE_LINK {
+				fulltext.optional = append(fulltext.optional, t)
+			} else {
+				fulltext.required = append(fulltext.required, t)
+			}
+		}
+	}
+
+	searchColumns := func(post *model.Post) []string {
+		switch options.TermsType {
+		case model.TERMS_TYPE_TAG:
+			return []string{post.Tags}
+		case model.TERMS_TYPE_SIMILAR:
+			return []string{post.Title, post.Tags}
+		case model.TERMS_TYPE_TITLE:
+			return []string{post.Title}
+		case model.TERMS_TYPE_BODY, model.TERMS_TYPE_LINK:
+			return []string{post.Content}
+		default:
+			return []string{post.Title, post.Tags, post.Content}
+		}
+	}
+
+	posts := []*model.Post{}
+	for _, post := range s.sortedPosts() {
+		if !options.IncludeDeleted && post.DeleteAt != 0 {
+			continue
+		}
+		if options.PostType != "" {
+			if post.Type != options.PostType {
+				continue
+			}
+		} else if post.Type != model.POST_TYPE_QUESTION && post.Type != model.POST_TYPE_ANSWER {
+			// search questions and answers when no PostType
+			continue
+		}
+		if post.TeamId != options.TeamId {
+			continue
+		}
+		if len(options.Ids) > 0 && post.Id != options.Ids[0] {
+			continue
+		}
+		if options.FromDate != 0 && post.CreateAt < options.FromDate {
+			continue
+		}
+		if options.ToDate != 0 && post.CreateAt > options.ToDate {
+			continue
+		}
+		if (options.PostType == model.POST_TYPE_ANSWER || options.PostType == model.POST_TYPE_COMMENT || options.PostType == "") && options.ParentId != "" && post.ParentId != options.ParentId {
+			continue
+		}
+		if options.OriginalId != "" && post.OriginalId != options.OriginalId {
+			continue
+		}
+		if options.UserId != "" && post.UserId != options.UserId {
+			continue
+		}
+		if options.MinVotes != nil && post.Points < *options.MinVotes {
+			continue
+		}
+		if options.MaxVotes != nil && post.Points > *options.MaxVotes {
+			continue
+		}
+		if options.PostType == model.POST_TYPE_QUESTION && options.MinAnswers != nil && post.AnswerCount < *options.MinAnswers {
+			continue
+		}
+		if options.PostType == model.POST_TYPE_QUESTION && options.MaxAnswers != nil && post.AnswerCount > *options.MaxAnswers {
+			continue
+		}
+		if options.TermsType != "" && !fulltext.match(searchColumns(post)...) {
+			continue
+		}
+
+		posts = append(posts, post)
+	}
+
+	sort.SliceStable(posts, func(a, b int) bool {
+		switch {
+		case options.SortType == model.POST_SORT_TYPE_ACTIVE:
+			return posts[a].UpdateAt > posts[b].UpdateAt
+		case options.SortType == model.POST_SORT_TYPE_VOTES:
+			return posts[a].Points > posts[b].Points
+		case options.SortType == model.POST_SORT_TYPE_ANSWERS:
+			return posts[a].AnswerCount > posts[b].AnswerCount
+		case options.TermsType == model.TERMS_TYPE_SIMILAR && options.SortType == model.POST_SORT_TYPE_RELEVANCE:
+			// 似ている順
+			return fulltext.score(searchColumns(posts[a])...) > fulltext.score(searchColumns(posts[b])...)
+		default:
+			return posts[a].CreateAt > posts[b].CreateAt
+		}
+	})
+
+	return posts
+}
+
+func (s *MemPostStore) DeleteQuestion(postId string, time int64, deleteById string) *model.AppError {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	post, ok := s.getActivePost(postId)
+	if !ok {
+		return model.NewAppError("MemPostStore.DeleteQuestion", "store.sql_post.delete_question.app_error", nil, "id="+postId, http.StatusInternalServerError)
+	}
+
+	post.AddProp(model.POST_PROPS_DELETE_BY, deleteById)
+	post.DeleteAt = time
+	post.UpdateAt = time
+
+	s.decrementTags(strings.Fields(post.Tags), post.TeamId, 0)
+	s.addPoints(post.TeamId, post.UserId, -model.USER_POINT_FOR_CREATE_QUESTION, time)
+	s.invalidateReviewsForPost(post.Id, time, post.TeamId)
+
+	s.saveUserPointHistory(&model.UserPointHistory{
+		Id:       model.NewId(),
+		TeamId:   post.TeamId,
+		UserId:   post.UserId,
+		Type:     model.USER_POINT_TYPE_DELETE_QUESTION,
+		PostId:   post.Id,
+		PostType: post.Type,
+		Tags:     post.Tags,
+		Points:   -(model.USER_POINT_FOR_CREATE_QUESTION),
+		CreateAt: time,
+	})
+
+	return nil
+}
+
+func (s *MemPostStore) invalidateReviewsForPost(postId string, time int64, teamId string) {
+	rev := s.getCurrentRevisionForPost(postId, teamId)
+
+	for _, vote := range s.tables.votes {
+		if vote.PostId != postId || vote.InvalidateAt != 0 || vote.CompletedAt != 0 || vote.RejectedAt != 0 {
+			continue
+		}
+		if vote.Type == model.VOTE_TYPE_REVIEW || vote.Type == model.VOTE_TYPE_FLAG || vote.Type == model.VOTE_TYPE_SYSTEM {
+			vote.InvalidateAt = time
+			vote.LastPostRev = int(rev)
+		}
+	}
+}
+
+func (s *MemPostStore) DeleteAnswer(postId string, time int64, deleteById string) *model.AppError {
+	appErr := func() *model.AppError {
+		return model.NewAppError("MemPostStore.DeleteAnswer", "store.sql_post.delete_answer.app_error", nil, "id="+postId, http.StatusInternalServerError)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	post, ok := s.getActivePost(postId)
+	if !ok {
+		return appErr()
+	}
+
+	parent, ok := s.getActivePost(post.ParentId)
+	if !ok {
+		return appErr()
+	}
+
+	post.AddProp(model.POST_PROPS_DELETE_BY, deleteById)
+	post.DeleteAt = time
+	post.UpdateAt = time
+
+	if parent.Type == model.POST_TYPE_QUESTION {
+		parent.AnswerCount--
+	}
+
+	curTime := model.GetMillis()
+
+	s.addPoints(post.TeamId, post.UserId, -model.USER_POINT_FOR_CREATE_ANSWER, curTime)
+	s.invalidateReviewsForPost(post.Id, time, post.TeamId)
+
+	s.saveUserPointHistory(&model.UserPointHistory{
+		Id:       model.NewId(),
+		TeamId:   post.TeamId,
+		UserId:   post.UserId,
+		Type:     model.USER_POINT_TYPE_DELETE_ANSWER,
+		PostId:   post.Id,
+		PostType: post.Type,
+		Tags:     parent.Tags,
+		Points:   -(model.USER_POINT_FOR_CREATE_ANSWER),
+		CreateAt: curTime,
+	})
+
+	return nil
+}
+
+func (s *MemPostStore) DeleteComment(postId string, time int64, deleteById string) *model.AppError {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	post, ok := s.getActivePost(postId)
+	if !ok {
+		return model.NewAppError("MemPostStore.DeleteComment", "store.sql_post.delete_comment.app_error", nil, "id="+postId, http.StatusInternalServerError)
+	}
+
+	post.AddProp(model.POST_PROPS_DELETE_BY, deleteById)
+	post.DeleteAt = time
+	post.UpdateAt = time
+
+	s.invalidateReviewsForPost(postId, time, post.TeamId)
+
+	return nil
+}
+
+func (s *MemPostStore) RestorePost(postId string, time int64) *model.AppError {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	post, ok := s.tables.posts[postId]
+	if !ok {
+		return model.NewAppError("MemPostStore.RestorePost", "store.sql_post.restore_post.app_error", nil, "id="+postId, http.StatusInternalServerError)
+	}
+	// 編集履歴も削除済みの投稿として残っているので除く
+	if post.DeleteAt == 0 || post.OriginalId != "" {
+		return model.NewAppError("MemPostStore.RestorePost", "store.sql_post.restore_post.not_deleted.app_error", nil, "id="+postId, http.StatusBadRequest)
+	}
+
+	var parent *model.Post
+	if post.Type != model.POST_TYPE_QUESTION {
+		if parent, ok = s.getActivePost(post.ParentId); !ok {
+			return model.NewAppError("MemPostStore.RestorePost", "store.sql_post.restore_post.parent_deleted.app_error", nil, "id="+postId, http.StatusBadRequest)
+		}
+	}
+
+	post.DelProp(model.POST_PROPS_DELETE_BY)
+	post.DeleteAt = 0
+	post.UpdateAt = time
+
+	var points int
+	var pointType, tags string
+	switch post.Type {
+	case model.POST_TYPE_QUESTION:
+		for _, tag := range s.tables.tags {
+			for _, tagContent := range strings.Fields(post.Tags) {
+				if tag.TeamId == post.TeamId && strings.EqualFold(tag.Content, tagContent) {
+					tag.PostCount++
+				}
+			}
+		}
+
+		points, pointType, tags = model.USER_POINT_FOR_CREATE_QUESTION, model.USER_POINT_TYPE_RESTORE_QUESTION, post.Tags
+	case model.POST_TYPE_ANSWER:
+		if parent.Type == model.POST_TYPE_QUESTION {
+			parent.AnswerCount++
+		}
+
+		points, pointType, tags = model.USER_POINT_FOR_CREATE_ANSWER, model.USER_POINT_TYPE_RESTORE_ANSWER, parent.Tags
+	default:
+		return nil
+	}
+
+	s.addPoints(post.TeamId, post.UserId, points, time)
+
+	s.saveUserPointHistory(&model.UserPointHistory{
+		Id:       model.NewId(),
+		TeamId:   post.TeamId,
+		UserId:   post.UserId,
+		Type:     pointType,
+		PostId:   post.Id,
+		PostType: post.Type,
+		Tags:     tags,
+		Points:   points,
+		CreateAt: time,
+	})
+
+	return nil
+}
+
+func (s *MemPostStore) SelectBestAnswer(postId, bestId string) *model.AppError {
+	appErr := func() *model.AppError {
+		return model.NewAppError("MemPostStore.SelectBestAnswer", "store.sql_post.select_best_answer.app_error", nil, "id="+postId, http.StatusInternalServerError)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	post, ok := s.getActivePost(postId)
+	if !ok {
+		return appErr()
+	}
+
+	if post.BestId != "" {
+		return model.NewAppError("MemPostStore.SelectBestAnswer", "store.sql_post.select_best_answer.best_exists.app_error", nil, "", http.StatusInternalServerError)
+	}
+
+	ans, ok := s.getActivePost(bestId)
+	if !ok || ans.Type != model.POST_TYPE_ANSWER {
+		return appErr()
+	}
+
+	if ans.ParentId != post.Id {
+		return model.NewAppError("MemPostStore.SelectBestAnswer", "store.sql_post.select_best_answer.invalid_answer.app_error", nil, "", http.StatusInternalServerError)
+	}
+
+	curTime := model.GetMillis()
+
+	if post.Type == model.POST_TYPE_QUESTION {
+		post.BestId = ans.Id
+		post.UpdateAt = curTime
+	}
+
+	// prevent self point gain
+	if post.UserId == ans.UserId {
+		return nil
+	}
+
+	s.addPoints(post.TeamId, post.UserId, model.USER_POINT_FOR_SELECT_ANSWER, curTime)
+	s.addPoints(ans.TeamId, ans.UserId, model.USER_POINT_FOR_SELECTED_ANSWER, curTime)
+
+	s.saveUserPointHistory(&model.UserPointHistory{
+		Id:       model.NewId(),
+		TeamId:   post.TeamId,
+		UserId:   post.UserId,
+		Type:     model.USER_POINT_TYPE_SELECT_ANSWER,
+		PostId:   post.Id,
+		PostType: post.Type,
+		Tags:     post.Tags,
+		Points:   model.USER_POINT_FOR_SELECT_ANSWER,
+		CreateAt: curTime,
+	})
+
+	s.saveUserPointHistory(&model.UserPointHistory{
+		Id:       model.NewId(),
+		TeamId:   ans.TeamId,
+		UserId:   ans.UserId,
+		Type:     model.USER_POINT_TYPE_SELECTED_ANSWER,
+		PostId:   ans.Id,
+		PostType: ans.Type,
+		Tags:     post.Tags,
+		Points:   model.USER_POINT_FOR_SELECTED_ANSWER,
+		CreateAt: curTime,
+	})
+
+	return nil
+}
+
+func (s *MemPostStore) GetCommentsForPost(postId string, limit int) ([]*model.Post, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.getCommentsForPost(postId, limit), nil
+}
+
+func (s *MemPostStore) getCommentsForPost(postId string, limit int) []*model.Post {
+	var comments []*model.Post
+	for _, post := range s.sortedPosts() {
+		if post.ParentId == postId && post.Type == model.POST_TYPE_COMMENT && post.DeleteAt == 0 {
+			comments = append(comments, clone(post).(*model.Post))
+		}
+	}
+
+	sort.SliceStable(comments, func(a, b int) bool {
+		return comments[a].CreateAt > comments[b].CreateAt
+	})
+
+	if limit >= 0 && len(comments) > limit {
+		comments = comments[:limit]
+	}
+
+	return comments
+}
+
+func (s *MemPostStore) GetChildPostsCount(id string) (int64, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	count := int64(0)
+	for _, post := range s.tables.posts {
+		if post.ParentId == id && (post.Type == model.POST_TYPE_ANSWER || post.Type == model.POST_TYPE_COMMENT) && post.DeleteAt == 0 {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// 回答はその質問、コメントはその投稿のタグを使う
+func (s *MemPostStore) getTagsForPost(post *model.Post) (string, *model.AppError) {
+	tags := post.Tags
+
+	if post.Type == model.POST_TYPE_ANSWER {
+		parent, ok := s.getActivePost(post.ParentId)
+		if !ok {
+			return "", model.NewAppError("MemPostStore.getTagsForPost", "store.sql_post.get_tags_for_post.parent.app_error", nil, "parent_id="+post.ParentId, http.StatusInternalServerError)
+		}
+		tags = parent.Tags
+	} else if post.Type == model.POST_TYPE_COMMENT {
+		root, ok := s.getActivePost(post.RootId)
+		if !ok {
+			return "", model.NewAppError("MemPostStore.getTagsForPost", "store.sql_post.get_tags_for_post.root.app_error", nil, "root_id="+post.RootId, http.StatusInternalServerError)
+		}
+		tags = root.Tags
+	}
+
+	return tags, nil
+}
+
+// 投票と、それに伴う投稿のカウンタ・投稿者のポイントの更新
+type voteChange struct {
+	voteType    string
+	where       string
+	errorId     string
+	points      int
+	historyType string
+	// 自分の投稿への投票ではポイントを付与しない
+	skipSelf bool
+	apply    func(post *model.Post)
+}
+
+func (s *MemPostStore) votePost(postId string, userId string, change voteChange) (*model.Vote, *model.AppError) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	post, ok := s.getActivePost(postId)
+	if !ok {
+		return nil, model.NewAppError("MemPostStore."+change.where, change.errorId+".app_error", nil, "id="+postId, http.StatusInternalServerError)
+	}
+
+	// タグの取得に失敗した場合は何も更新しない
+	var tags string
+	if !change.skipSelf || userId != post.UserId {
+		var appErr *model.AppError
+		if tags, appErr = s.getTagsForPost(post); appErr != nil {
+			return nil, appErr
+		}
+	}
+
+	curTime := model.GetMillis()
+
+	vote := &model.Vote{
+		PostId:   post.Id,
+		UserId:   userId,
+		Type:     change.voteType,
+		TeamId:   post.TeamId,
+		CreateAt: curTime,
+	}
+	if change.voteType == model.VOTE_TYPE_FLAG {
+		vote.FirstPostRev = int(s.getCurrentRevisionForPost(post.Id, post.TeamId))
+	}
+
+	key := voteKey{vote.UserId, vote.Type, vote.PostId}
+	if _, ok := s.tables.votes[key]; ok {
+		return nil, model.NewAppError("MemPostStore."+change.where, change.errorId+".inserting.app_error", nil, "id="+postId, http.StatusInternalServerError)
+	}
+	s.tables.votes[key] = clone(vote).(*model.Vote)
+
+	change.apply(post)
+	post.UpdateAt = curTime
+
+	if change.skipSelf && userId == post.UserId {
+		return vote, nil
+	}
+
+	s.addPoints(post.TeamId, post.UserId, change.points, curTime)
+
+	s.saveUserPointHistory(&model.UserPointHistory{
+		Id:       model.NewId(),
+		TeamId:   post.TeamId,
+		UserId:   post.UserId,
+		Type:     change.historyType,
+		PostId:   post.Id,
+		PostType: post.Type,
+		Tags:     tags,
+		Points:   change.points,
+		CreateAt: curTime,
+	})
+
+	return vote, nil
+}
+
+func (s *MemPostStore) cancelVotePost(postId string, userId string, change voteChange) (*model.Vote, *model.AppError) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	vote, ok := s.tables.votes[voteKey{userId, change.voteType, postId}]
+	if !ok {
+		return nil, model.NewAppError("MemPostStore."+change.where, change.errorId+".app_error", nil, "id="+postId, http.StatusInternalServerError)
+	}
+
+	post, ok := s.getActivePost(postId)
+	if !ok {
+		return nil, model.NewAppError("MemPostStore."+change.where, change.errorId+".app_error", nil, "id="+postId, http.StatusInternalServerError)
+	}
+
+	var tags string
+	if !change.skipSelf || userId != post.UserId {
+		var appErr *model.AppError
+		if tags, appErr = s.getTagsForPost(post); appErr != nil {
+			return nil, appErr
+		}
+	}
+
+	delete(s.tables.votes, voteKey{userId, change.voteType, postId})
+
+	curTime := model.GetMillis()
+
+	change.apply(post)
+	post.UpdateAt = curTime
+
+	result := clone(vote).(*model.Vote)
+
+	if change.skipSelf && userId == post.UserId {
+		return result, nil
+	}
+
+	s.addPoints(post.TeamId, post.UserId, change.points, curTime)
+
+	s.saveUserPointHistory(&model.UserPointHistory{
+		Id:       model.NewId(),
+		TeamId:   post.TeamId,
+		UserId:   post.UserId,
+		Type:     change.historyType,
+		PostId:   post.Id,
+		PostType: post.Type,
+		Tags:     tags,
+		Points:   change.points,
+		CreateAt: curTime,
+	})
+
+	return result, nil
+}
+
+func (s *MemPostStore) UpVotePost(postId string, userId string) (*model.Vote, *model.AppError) {
+	return s.votePost(postId, userId, voteChange{
+		voteType:    model.VOTE_TYPE_UP_VOTE,
+		where:       "UpVotePost",
+		errorId:     "store.sql_post.upvote_post",
+		points:      model.USER_POINT_FOR_VOTED,
+		historyType: model.USER_POINT_TYPE_VOTED,
+		skipSelf:    true,
+		apply: func(post *model.Post) {
+			post.UpVotes++
+			post.Points++
+		},
+	})
+}
+
+func (s *MemPostStore) CancelUpVotePost(postId string, userId string) (*model.Vote, *model.AppError) {
+	return s.cancelVotePost(postId, userId, voteChange{
+		voteType:    model.VOTE_TYPE_UP_VOTE,
+		where:       "CancelUpVotePost",
+		errorId:     "store.sql_post.cancel_upvote_post",
+		points:      -(model.USER_POINT_FOR_VOTED),
+		historyType: model.USER_POINT_TYPE_VOTED_CANCELED,
+		skipSelf:    true,
+		apply: func(post *model.Post) {
+			post.UpVotes--
+			if post.UpVotes < 0 {
+				post.UpVotes = 0
+			}
+			post.Points--
+		},
+	})
+}
+
+func (s *MemPostStore) DownVotePost(postId string, userId string) (*model.Vote, *model.AppError) {
+	return s.votePost(postId, userId, voteChange{
+		voteType:    model.VOTE_TYPE_DOWN_VOTE,
+		where:       "DownVotePost",
+		errorId:     "store.sql_post.downvote_post",
+		points:      model.USER_POINT_FOR_DOWN_VOTED,
+		historyType: model.USER_POINT_TYPE_DOWN_VOTED,
+		skipSelf:    true,
+		apply: func(post *model.Post) {
+			post.DownVotes++
+			post.Points--
+		},
+	})
+}
+
+func (s *MemPostStore) CancelDownVotePost(postId string, userId string) (*model.Vote, *model.AppError) {
+	return s.cancelVotePost(postId, userId, voteChange{
+		voteType:    model.VOTE_TYPE_DOWN_VOTE,
+		where:       "CancelDownVotePost",
+		errorId:     "store.sql_post.cancel_downvote_post",
+		points:      -(model.USER_POINT_FOR_DOWN_VOTED),
+		historyType: model.USER_POINT_TYPE_DOWN_VOTED_CANCELED,
+		skipSelf:    true,
+		apply: func(post *model.Post) {
+			post.DownVotes--
+			if post.DownVotes < 0 {
+				post.DownVotes = 0
+			}
+			post.Points++
+		},
+	})
+}
+
+func (s *MemPostStore) FlagPost(postId string, userId string) (*model.Vote, *model.AppError) {
+	return s.votePost(postId, userId, voteChange{
+		voteType:    model.VOTE_TYPE_FLAG,
+		where:       "FlagPost",
+		errorId:     "store.sql_post.flag_post",
+		points:      model.USER_POINT_FOR_FLAGGED,
+		historyType: model.USER_POINT_TYPE_FLAGGED,
+		apply: func(post *model.Post) {
+			post.FlagCount++
+		},
+	})
+}
+
+func (s *MemPostStore) CancelFlagPost(postId string, userId string) (*model.Vote, *model.AppError) {
+	return s.cancelVotePost(postId, userId, voteChange{
+		voteType:    model.VOTE_TYPE_FLAG,
+		where:       "CancelFlagPost",
+		errorId:     "store.sql_post.cancel_flag_post",
+		points:      -(model.USER_POINT_FOR_FLAGGED),
+		historyType: model.USER_POINT_TYPE_FLAGGED_CANCELED,
+		apply: func(post *model.Post) {
+			post.FlagCount--
+		},
+	})
+}
+
+// LockedAt, ProtectedAtとPropsを更新する
+func (s *MemPostStore) updateModeration(postId string, where string, errorId string, update func(post *model.Post)) *model.AppError {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	post, ok := s.getActivePost(postId)
+	if !ok {
+		return model.NewAppError("MemPostStore."+where, errorId, nil, "id="+postId, http.StatusInternalServerError)
+	}
+
+	update(post)
+
+	return nil
+}
+
+func (s *MemPostStore) LockPost(postId string, time int64, userId string) *model.AppError {
+	return s.updateModeration(postId, "LockPost", "store.sql_post.lock_post.app_error", func(post *model.Post) {
+		post.AddProp(model.POST_PROPS_LOCKED_BY, userId)
+		post.LockedAt = time
+		post.UpdateAt = time
+	})
+}
+
+func (s *MemPostStore) CancelLockPost(postId string, userId string) *model.AppError {
+	return s.updateModeration(postId, "CancelLockPost", "store.sql_post.cancel_lock_post.app_error", func(post *model.Post) {
+		post.AddProp(model.POST_PROPS_LOCKED_BY, "")
+		post.LockedAt = 0
+		post.UpdateAt = model.GetMillis()
+	})
+}
+
+func (s *MemPostStore) ProtectPost(postId string, time int64, userId string) *model.AppError {
+	return s.updateModeration(postId, "ProtectPost", "store.sql_post.protect_post.app_error", func(post *model.Post) {
+		post.AddProp(model.POST_PROPS_PROTECTED_BY, userId)
+		post.ProtectedAt = time
+		post.UpdateAt = time
+	})
+}
+
+func (s *MemPostStore) CancelProtectPost(postId string, userId string) *model.AppError {
+	return s.updateModeration(postId, "CancelProtectPost", "store.sql_post.cancel_protect_post.app_error", func(post *model.Post) {
+		post.AddProp(model.POST_PROPS_PROTECTED_BY, "")
+		post.ProtectedAt = 0
+		post.UpdateAt = model.GetMillis()
+	})
+}
+
+func (s *MemPostStore) ViewPost(postId string, teamId string, userId string, ipAddress string, count int) *model.AppError {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	curTime := model.GetMillis()
+
+	if post, ok := s.tables.posts[postId]; ok {
+		post.Views += count
+		post.UpdateAt = curTime
+	}
+
+	s.savePostViewsHistory(postId, teamId, userId, ipAddress, count, curTime)
+
+	return nil
+}
+
+func (s *MemPostStore) FlushBufferedViews(limit int) (int, *model.AppError) {
+	return 0, nil
+}
+
+func (s *MemPostStore) GetDailyUniqueViews(postId string, days int) (model.Analytics, *model.AppError) {
+	start := utils.MillisFromTime(utils.StartOfDay(time.Now().AddDate(0, 0, -(days - 1))))
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	values := map[string]float64{}
+	for _, history := range s.tables.postViewsHistory {
+		if history.PostId == postId && history.CreateAt >= start {
+			values[dateFromMillis(history.CreateAt)] += float64(history.ViewsCount)
+		}
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(names)))
+
+	rows := model.Analytics{}
+	for _, name := range names {
+		rows = append(rows, &model.Analytic{Name: name, Value: values[name]})
+	}
+
+	return rows, nil
+}
+
+func (s *MemPostStore) SavePostViewsHistory(postId string, teamId string, userId string, ipAddress string, count int, time int64) (*model.PostViewsHistory, *model.AppError) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.savePostViewsHistory(postId, teamId, userId, ipAddress, count, time), nil
+}
+
+func (s *MemPostStore) savePostViewsHistory(postId string, teamId string, userId string, ipAddress string, count int, time int64) *model.PostViewsHistory {
+	history := &model.PostViewsHistory{
+		Id:         model.NewId(),
+		PostId:     postId,
+		TeamId:     teamId,
+		UserId:     userId,
+		IpAddress:  ipAddress,
+		ViewsCount: count,
+		CreateAt:   time,
+	}
+
+	s.tables.postViewsHistory[history.Id] = clone(history).(*model.PostViewsHistory)
+
+	return history
+}
+
+func (s *MemPostStore) RelatedSearch(term string, limit int) ([]*model.RelatedPostSearchResult, *model.AppError) {
+	return nil, nil
+}
+
+func (s *MemPostStore) HotSearch(interval string, teamId string, limit int) ([]string, *model.AppError) {
+	return nil, nil
+}
+
+func (s *MemPostStore) GetCurrentRevisionForPost(postId, teamId string) (int64, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.getCurrentRevisionForPost(postId, teamId), nil
+}
+
+// 編集履歴の数+1が現在のリビジョン
+func (s *MemPostStore) getCurrentRevisionForPost(postId, teamId string) int64 {
+	count := int64(0)
+	for _, post := range s.tables.posts {
+		if post.OriginalId == postId && matchesNullableTeam(post.TeamId, teamId) {
+			count++
+		}
+	}
+
+	return count + 1
+}
+
+func (s *MemPostStore) GetRevisionPost(postId, teamId string, offset int) (*model.Post, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	revisions := []*model.Post{}
+	for _, post := range s.sortedPosts() {
+		if post.OriginalId == postId && matchesNullableTeam(post.TeamId, teamId) {
+			revisions = append(revisions, post)
+		}
+	}
+
+	sort.SliceStable(revisions, func(a, b int) bool {
+		return revisions[a].UpdateAt < revisions[b].UpdateAt
+	})
+
+	if offset < 0 || offset >= len(revisions) {
+		return nil, model.NewAppError("MemPostStore.GetRevisionPost", "store.sql_post.get_revision_post.app_error", nil, "id="+postId, http.StatusInternalServerError)
+	}
+
+	return clone(revisions[offset]).(*model.Post), nil
+}
+
+func (s *MemPostStore) GetAnsweredRate(teamId string) (float64, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	answered := map[string]bool{}
+	for _, post := range s.tables.posts {
+		if post.Type == model.POST_TYPE_ANSWER && matchesNullableTeam(post.TeamId, teamId) && post.DeleteAt == 0 {
+			answered[post.ParentId] = true
+		}
+	}
+
+	totalCount := s.getPostCount(model.POST_TYPE_QUESTION, "", teamId, 0, 0)
+
+	return float64(len(answered)) / float64(totalCount), nil
+}
+
+// 削除済みの投稿や編集履歴も含めて集計する
+func (s *MemPostStore) postAnalytics(teamId string, value func(posts []*model.Post) float64) model.Analytics {
+	start, end := analyticsRange()
+
+	postsByDate := map[string][]*model.Post{}
+	for _, post := range s.tables.posts {
+		if len(teamId) > 0 && post.TeamId != teamId {
+			continue
+		}
+		if post.CreateAt < start || post.CreateAt > end {
+			continue
+		}
+		date := dateFromMillis(post.CreateAt)
+		postsByDate[date] = append(postsByDate[date], post)
+	}
+
+	values := map[string]float64{}
+	for date, posts := range postsByDate {
+		values[date] = value(posts)
+	}
+
+	return dailyAnalytics(values)
+}
+
+func (s *MemPostStore) AnalyticsPostCounts(teamId string) (model.Analytics, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.postAnalytics(teamId, func(posts []*model.Post) float64 {
+		return float64(len(posts))
+	}), nil
+}
+
+func (s *MemPostStore) AnalyticsActiveAuthorCounts(teamId string) (model.Analytics, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.postAnalytics(teamId, func(posts []*model.Post) float64 {
+		authors := map[string]bool{}
+		for _, post := range posts {
+			authors[post.UserId] = true
+		}
+		return float64(len(authors))
+	}), nil
+}
+
+// 削除済みの投稿と、親が削除済みの投稿は移行対象外とする
+func (s *MemPostStore) GetPostsForExport(teamId string, postType string, afterId string, limit int) ([]*model.Post, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var posts []*model.Post
+	for _, post := range s.sortedPosts() {
+		if post.TeamId != teamId || post.Type != postType || post.OriginalId != "" || post.DeleteAt != 0 || post.Id <= afterId {
+			continue
+		}
+		if postType != model.POST_TYPE_QUESTION {
+			if _, ok := s.getActivePost(post.ParentId); !ok {
+				continue
+			}
+		}
+
+		posts = append(posts, clone(post).(*model.Post))
+		if len(posts) == limit {
+			break
+		}
+	}
+
+	return posts, nil
+}
+
+func (s *MemPostStore) GetRevisionsForExport(teamId string, afterId string, limit int) ([]*model.Post, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var posts []*model.Post
+	for _, revision := range s.sortedPosts() {
+		if revision.TeamId != teamId || revision.OriginalId == "" || revision.Id <= afterId {
+			continue
+		}
+		if _, ok := s.getActivePost(revision.OriginalId); !ok {
+			continue
+		}
+
+		posts = append(posts, clone(revision).(*model.Post))
+		if len(posts) == limit {
+			break
+		}
+	}
+
+	return posts, nil
+}
@@ -0,0 +1,50 @@
+package memstore
+
+import (
+	"github.com/clear-ness/qa-discussion/model"
+)
+
+type MemPostViewsHistoryStore struct {
+	*MemStore
+}
+
+func (s *MemPostViewsHistoryStore) GetViewsHistoryCount(teamId string, fromDate int64, toDate int64) (int64, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	count := int64(0)
+	for _, history := range s.tables.postViewsHistory {
+		if !matchesNullableTeam(history.TeamId, teamId) {
+			continue
+		}
+		if fromDate != 0 && history.CreateAt < fromDate {
+			continue
+		}
+		if toDate != 0 && history.CreateAt > toDate {
+			continue
+		}
+		count += int64(history.ViewsCount)
+	}
+
+	return count, nil
+}
+
+func (s *MemPostViewsHistoryStore) AnalyticsPostViewsHistoryCounts(teamId string) (model.Analytics, *model.AppError) {
+	start, end := analyticsRange()
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	values := map[string]float64{}
+	for _, history := range s.tables.postViewsHistory {
+		if len(teamId) > 0 && history.TeamId != teamId {
+			continue
+		}
+		if history.CreateAt < start || history.CreateAt > end {
+			continue
+		}
+		values[dateFromMillis(history.CreateAt)] += float64(history.ViewsCount)
+	}
+
+	return dailyAnalytics(values), nil
+}
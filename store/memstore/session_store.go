@@ -0,0 +1,139 @@
+package memstore
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/clear-ness/qa-discussion/model"
+)
+
+type MemSessionStore struct {
+	*MemStore
+}
+
+// 退会していないチームのメンバー情報。呼び出し元でロックを取る
+func (me *MemSessionStore) activeTeamMembers(userId string) []*model.TeamMember {
+	members := []*model.TeamMember{}
+	for key, member := range me.tables.teamMembers {
+		if key.userId == userId && member.DeleteAt == 0 {
+			members = append(members, clone(member).(*model.TeamMember))
+		}
+	}
+
+	sort.Slice(members, func(a, b int) bool {
+		return members[a].TeamId < members[b].TeamId
+	})
+
+	return members
+}
+
+func (me *MemSessionStore) Save(session *model.Session) (*model.Session, *model.AppError) {
+	if len(session.Id) > 0 {
+		return nil, model.NewAppError("MemSessionStore.Save", "store.sql_session.save.existing.app_error", nil, "id="+session.Id, http.StatusBadRequest)
+	}
+
+	session.PreSave()
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	me.tables.sessions[session.Id] = clone(session).(*model.Session)
+	session.TeamMembers = me.activeTeamMembers(session.UserId)
+
+	return session, nil
+}
+
+func (me *MemSessionStore) Get(sessionIdOrToken string) (*model.Session, *model.AppError) {
+	me.mutex.RLock()
+	defer me.mutex.RUnlock()
+
+	session, ok := me.tables.sessions[sessionIdOrToken]
+	if !ok {
+		for _, other := range me.tables.sessions {
+			if other.Token == sessionIdOrToken {
+				session, ok = other, true
+				break
+			}
+		}
+	}
+
+	if !ok {
+		return nil, model.NewAppError("MemSessionStore.Get", "store.sql_session.get.app_error", nil, "sessionIdOrToken="+sessionIdOrToken, http.StatusNotFound)
+	}
+
+	session = clone(session).(*model.Session)
+	session.TeamMembers = me.activeTeamMembers(session.UserId)
+
+	return session, nil
+}
+
+func (me *MemSessionStore) Remove(sessionIdOrToken string) *model.AppError {
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	for id, session := range me.tables.sessions {
+		if id == sessionIdOrToken || session.Token == sessionIdOrToken {
+			delete(me.tables.sessions, id)
+		}
+	}
+
+	return nil
+}
+
+func (me *MemSessionStore) RemoveByUserId(userId string) *model.AppError {
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	for id, session := range me.tables.sessions {
+		if session.UserId == userId {
+			delete(me.tables.sessions, id)
+		}
+	}
+
+	return nil
+}
+
+func (me *MemSessionStore) GetSessions(userId string) ([]*model.Session, *model.AppError) {
+	me.mutex.RLock()
+	defer me.mutex.RUnlock()
+
+	var sessions []*model.Session
+	for _, session := range me.tables.sessions {
+		if session.UserId == userId {
+			session = clone(session).(*model.Session)
+			session.TeamMembers = me.activeTeamMembers(userId)
+			sessions = append(sessions, session)
+		}
+	}
+
+	sort.Slice(sessions, func(a, b int) bool {
+		if sessions[a].CreateAt == sessions[b].CreateAt {
+			return sessions[a].Id < sessions[b].Id
+		}
+		return sessions[a].CreateAt > sessions[b].CreateAt
+	})
+
+	return sessions, nil
+}
+
+func (me *MemSessionStore) UpdateLastActivityAt(sessionId string, time int64) *model.AppError {
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	if session, ok := me.tables.sessions[sessionId]; ok {
+		session.LastActivityAt = time
+	}
+
+	return nil
+}
+
+func (me *MemSessionStore) UpdateExpiresAt(sessionId string, time int64) *model.AppError {
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	if session, ok := me.tables.sessions[sessionId]; ok {
+		session.ExpiresAt = time
+	}
+
+	return nil
+}
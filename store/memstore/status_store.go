@@ -0,0 +1,68 @@
+package memstore
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/clear-ness/qa-discussion/store"
+)
+
+type MemStatusStore struct {
+	*MemStore
+}
+
+func (s *MemStatusStore) Get(userId string) (*model.Status, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	status, ok := s.tables.statuses[userId]
+	if !ok {
+		return nil, model.NewAppError("MemStatusStore.Get", store.MISSING_STATUS_ERROR, nil, "user_id="+userId, http.StatusNotFound)
+	}
+
+	return clone(status).(*model.Status), nil
+}
+
+func (s *MemStatusStore) GetByIds(userIds []string) ([]*model.Status, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var statuses []*model.Status
+	for _, userId := range uniqueStrings(userIds) {
+		if status, ok := s.tables.statuses[userId]; ok {
+			statuses = append(statuses, &model.Status{
+				UserId:         status.UserId,
+				Status:         status.Status,
+				Manual:         status.Manual,
+				LastActivityAt: status.LastActivityAt,
+			})
+		}
+	}
+
+	sort.Slice(statuses, func(a, b int) bool {
+		return statuses[a].UserId < statuses[b].UserId
+	})
+
+	return statuses, nil
+}
+
+func (s *MemStatusStore) SaveOrUpdate(status *model.Status) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.tables.statuses[status.UserId] = clone(status).(*model.Status)
+
+	return nil
+}
+
+func (s *MemStatusStore) UpdateLastActivityAt(userId string, lastActivityAt int64) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if status, ok := s.tables.statuses[userId]; ok {
+		status.LastActivityAt = lastActivityAt
+	}
+
+	return nil
+}
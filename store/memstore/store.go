@@ -0,0 +1,236 @@
+package memstore
+
+import (
+	"context"
+	"sync"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/clear-ness/qa-discussion/store"
+	"github.com/go-gorp/gorp"
+)
+
+// DBを使わずに動くテスト用のstore
+const DRIVER_NAME = "memory"
+
+type MemStoreStores struct {
+	team                store.TeamStore
+	teamMemberHistory   store.TeamMemberHistoryStore
+	userGroup           store.UserGroupStore
+	groupMemberHistory  store.GroupMemberHistoryStore
+	collection          store.CollectionStore
+	user                store.UserStore
+	token               store.TokenStore
+	session             store.SessionStore
+	post                store.PostStore
+	tag                 store.TagStore
+	vote                store.VoteStore
+	userPointHistory    store.UserPointHistoryStore
+	userFavoritePost    store.UserFavoritePostStore
+	inboxMessage        store.InboxMessageStore
+	fileInfo            store.FileInfoStore
+	notificationSetting store.NotificationSettingStore
+	postViewsHistory    store.PostViewsHistoryStore
+	webhook             store.WebhookStore
+	webhooksHistory     store.WebhooksHistoryStore
+	webhookDelivery     store.WebhookDeliveryStore
+	incomingWebhook     store.IncomingWebhookStore
+	bot                 store.BotStore
+	audit               store.AuditStore
+	oauth               store.OAuthStore
+	status              store.StatusStore
+	job                 store.JobStore
+	system              store.SystemStore
+}
+
+// 全てのテーブルを1つのロックで守り、sqlのトランザクションと同じく操作の途中の状態を見せない
+type MemStore struct {
+	mutex  sync.RWMutex
+	tables *tables
+	stores MemStoreStores
+}
+
+func New() *MemStore {
+	s := &MemStore{
+		tables: newTables(),
+	}
+
+	s.stores.team = &MemTeamStore{s}
+	s.stores.teamMemberHistory = &MemTeamMemberHistoryStore{s}
+	s.stores.userGroup = &MemUserGroupStore{s}
+	s.stores.groupMemberHistory = &MemGroupMemberHistoryStore{s}
+	s.stores.collection = &MemCollectionStore{s}
+	s.stores.user = &MemUserStore{s}
+	s.stores.token = &MemTokenStore{s}
+	s.stores.session = &MemSessionStore{s}
+	s.stores.post = &MemPostStore{s}
+	s.stores.tag = &MemTagStore{s}
+	s.stores.vote = &MemVoteStore{s}
+	s.stores.userPointHistory = &MemUserPointHistoryStore{s}
+	s.stores.userFavoritePost = &MemUserFavoritePostStore{s}
+	s.stores.inboxMessage = &MemInboxMessageStore{s}
+	s.stores.fileInfo = &MemFileInfoStore{s}
+	s.stores.notificationSetting = &MemNotificationSettingStore{s}
+	s.stores.postViewsHistory = &MemPostViewsHistoryStore{s}
+	s.stores.webhook = &MemWebhookStore{s}
+	s.stores.webhooksHistory = &MemWebhooksHistoryStore{s}
+	s.stores.webhookDelivery = &MemWebhookDeliveryStore{s}
+	s.stores.incomingWebhook = &MemIncomingWebhookStore{s}
+	s.stores.bot = &MemBotStore{s}
+	s.stores.audit = &MemAuditStore{s}
+	s.stores.oauth = &MemOAuthStore{s}
+	s.stores.status = &MemStatusStore{s}
+	s.stores.job = &MemJobStore{s}
+	s.stores.system = &MemSystemStore{s}
+
+	return s
+}
+
+func (s *MemStore) DriverName() string {
+	return DRIVER_NAME
+}
+
+func (s *MemStore) GetMaster() *gorp.DbMap {
+	return nil
+}
+
+func (s *MemStore) GetReplica() *gorp.DbMap {
+	return nil
+}
+
+func (s *MemStore) TotalMasterDbConnections() int {
+	return 0
+}
+
+func (s *MemStore) TotalReadDbConnections() int {
+	return 0
+}
+
+func (s *MemStore) Close() {
+}
+
+func (s *MemStore) GetAllConns() []*gorp.DbMap {
+	return []*gorp.DbMap{}
+}
+
+func (s *MemStore) GetQueryBuilder() sq.StatementBuilderType {
+	return sq.StatementBuilder.PlaceholderFormat(sq.Question)
+}
+
+func (s *MemStore) DropAllTables() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.tables = newTables()
+}
+
+func (s *MemStore) WithContext(ctx context.Context) store.Store {
+	return s
+}
+
+func (s *MemStore) Team() store.TeamStore {
+	return s.stores.team
+}
+
+func (s *MemStore) TeamMemberHistory() store.TeamMemberHistoryStore {
+	return s.stores.teamMemberHistory
+}
+
+func (s *MemStore) UserGroup() store.UserGroupStore {
+	return s.stores.userGroup
+}
+
+func (s *MemStore) GroupMemberHistory() store.GroupMemberHistoryStore {
+	return s.stores.groupMemberHistory
+}
+
+func (s *MemStore) Collection() store.CollectionStore {
+	return s.stores.collection
+}
+
+func (s *MemStore) User() store.UserStore {
+	return s.stores.user
+}
+
+func (s *MemStore) Token() store.TokenStore {
+	return s.stores.token
+}
+
+func (s *MemStore) Session() store.SessionStore {
+	return s.stores.session
+}
+
+func (s *MemStore) Post() store.PostStore {
+	return s.stores.post
+}
+
+func (s *MemStore) Tag() store.TagStore {
+	return s.stores.tag
+}
+
+func (s *MemStore) Vote() store.VoteStore {
+	return s.stores.vote
+}
+
+func (s *MemStore) UserPointHistory() store.UserPointHistoryStore {
+	return s.stores.userPointHistory
+}
+
+func (s *MemStore) UserFavoritePost() store.UserFavoritePostStore {
+	return s.stores.userFavoritePost
+}
+
+func (s *MemStore) InboxMessage() store.InboxMessageStore {
+	return s.stores.inboxMessage
+}
+
+func (s *MemStore) FileInfo() store.FileInfoStore {
+	return s.stores.fileInfo
+}
+
+func (s *MemStore) NotificationSetting() store.NotificationSettingStore {
+	return s.stores.notificationSetting
+}
+
+func (s *MemStore) PostViewsHistory() store.PostViewsHistoryStore {
+	return s.stores.postViewsHistory
+}
+
+func (s *MemStore) Webhook() store.WebhookStore {
+	return s.stores.webhook
+}
+
+func (s *MemStore) WebhooksHistory() store.WebhooksHistoryStore {
+	return s.stores.webhooksHistory
+}
+
+func (s *MemStore) WebhookDelivery() store.WebhookDeliveryStore {
+	return s.stores.webhookDelivery
+}
+
+func (s *MemStore) IncomingWebhook() store.IncomingWebhookStore {
+	return s.stores.incomingWebhook
+}
+
+func (s *MemStore) Bot() store.BotStore {
+	return s.stores.bot
+}
+
+func (s *MemStore) Audit() store.AuditStore {
+	return s.stores.audit
+}
+
+func (s *MemStore) OAuth() store.OAuthStore {
+	return s.stores.oauth
+}
+
+func (s *MemStore) Status() store.StatusStore {
+	return s.stores.status
+}
+
+func (s *MemStore) Job() store.JobStore {
+	return s.stores.job
+}
+
+func (s *MemStore) System() store.SystemStore {
+	return s.stores.system
+}
@@ -0,0 +1,14 @@
+package memstore
+
+import (
+	"testing"
+
+	"github.com/clear-ness/qa-discussion/store"
+	"github.com/clear-ness/qa-discussion/store/storetest"
+)
+
+var _ store.Store = (*MemStore)(nil)
+
+func TestMemStore(t *testing.T) {
+	storetest.StoreTest(t, New())
+}
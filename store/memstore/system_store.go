@@ -0,0 +1,33 @@
+package memstore
+
+import (
+	"net/http"
+
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/clear-ness/qa-discussion/store"
+)
+
+type MemSystemStore struct {
+	*MemStore
+}
+
+func (s *MemSystemStore) GetByName(name string) (*model.System, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	system, ok := s.tables.systems[name]
+	if !ok {
+		return nil, model.NewAppError("MemSystemStore.GetByName", store.MISSING_SYSTEM_ERROR, nil, "name="+name, http.StatusNotFound)
+	}
+
+	return clone(system).(*model.System), nil
+}
+
+func (s *MemSystemStore) SaveOrUpdate(system *model.System) *model.AppError {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.tables.systems[system.Name] = clone(system).(*model.System)
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package memstore
+
+import (
+	"strings"
+
+	"github.com/clear-ness/qa-discussion/model"
+)
+
+// 複合主キーのテーブル用のキー
+type teamMemberKey struct{ teamId, userId string }
+type groupMemberKey struct{ groupId, userId string }
+type collectionPostKey struct{ collectionId, postId string }
+type tagKey struct{ content, teamId, tagType string }
+type voteKey struct{ userId, voteType, postId string }
+type userFavoritePostKey struct{ postId, userId string }
+type oauthAuthorizedAppKey struct{ userId, clientId string }
+
+// migrationの各テーブルに対応する
+type tables struct {
+	teams               map[string]*model.Team
+	teamMembers         map[teamMemberKey]*model.TeamMember
+	teamMemberHistory   []*model.TeamMemberHistory
+	userGroups          map[string]*model.UserGroup
+	groupMembers        map[groupMemberKey]*model.GroupMember
+	groupMemberHistory  []*model.GroupMemberHistory
+	collections         map[string]*model.Collection
+	collectionPosts     map[collectionPostKey]*model.CollectionPost
+	users               map[string]*model.User
+	tokens              map[string]*model.Token
+	sessions            map[string]*model.Session
+	posts               map[string]*model.Post
+	tags                map[tagKey]*model.Tag
+	votes               map[voteKey]*model.Vote
+	userPointHistory    map[string]*model.UserPointHistory
+	inboxMessages       map[string]*model.InboxMessage
+	userFavoritePosts   map[userFavoritePostKey]*model.UserFavoritePost
+	fileInfos           map[string]*model.FileInfo
+	notificationSetting map[string]*model.NotificationSetting
+	postViewsHistory    map[string]*model.PostViewsHistory
+	webhooks            map[string]*model.Webhook
+	webhooksHistory     map[string]*model.WebhooksHistory
+	webhookDeliveries   map[string]*model.WebhookDelivery
+	incomingWebhooks    map[string]*model.IncomingWebhook
+	bots                map[string]*model.Bot
+	audits              map[string]*model.Audit
+	oauthApps           map[string]*model.OAuthApp
+	oauthAuthData       map[string]*model.AuthData
+	oauthAccessData     map[string]*model.AccessData
+	oauthAuthorizedApps map[oauthAuthorizedAppKey]*model.OAuthAuthorizedApp
+	statuses            map[string]*model.Status
+	jobs                map[string]*model.Job
+	systems             map[string]*model.System
+}
+
+func newTables() *tables {
+	return &tables{
+		teams:               map[string]*model.Team{},
+		teamMembers:         map[teamMemberKey]*model.TeamMember{},
+		userGroups:          map[string]*model.UserGroup{},
+		groupMembers:        map[groupMemberKey]*model.GroupMember{},
+		collections:         map[string]*model.Collection{},
+		collectionPosts:     map[collectionPostKey]*model.CollectionPost{},
+		users:               map[string]*model.User{},
+		tokens:              map[string]*model.Token{},
+		sessions:            map[string]*model.Session{},
+		posts:               map[string]*model.Post{},
+		tags:                map[tagKey]*model.Tag{},
+		votes:               map[voteKey]*model.Vote{},
+		userPointHistory:    map[string]*model.UserPointHistory{},
+		inboxMessages:       map[string]*model.InboxMessage{},
+		userFavoritePosts:   map[userFavoritePostKey]*model.UserFavoritePost{},
+		fileInfos:           map[string]*model.FileInfo{},
+		notificationSetting: map[string]*model.NotificationSetting{},
+		postViewsHistory:    map[string]*model.PostViewsHistory{},
+		webhooks:            map[string]*model.Webhook{},
+		webhooksHistory:     map[string]*model.WebhooksHistory{},
+		webhookDeliveries:   map[string]*model.WebhookDelivery{},
+		incomingWebhooks:    map[string]*model.IncomingWebhook{},
+		bots:                map[string]*model.Bot{},
+		audits:              map[string]*model.Audit{},
+		oauthApps:           map[string]*model.OAuthApp{},
+		oauthAuthData:       map[string]*model.AuthData{},
+		oauthAccessData:     map[string]*model.AccessData{},
+		oauthAuthorizedApps: map[oauthAuthorizedAppKey]*model.OAuthAuthorizedApp{},
+		statuses:            map[string]*model.Status{},
+		jobs:                map[string]*model.Job{},
+		systems:             map[string]*model.System{},
+	}
+}
+
+// mysqlの照合順序と同じく、大文字小文字の違うタグは同じ主キーとして扱う
+func newTagKey(content, teamId, tagType string) tagKey {
+	return tagKey{strings.ToLower(content), teamId, tagType}
+}
+
+// LIMIT, OFFSETを適用した範囲を返す
+func paginate(length, offset, limit int) (int, int) {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > length {
+		offset = length
+	}
+
+	end := length
+	if limit >= 0 && offset+limit < length {
+		end = offset + limit
+	}
+
+	return offset, end
+}
@@ -0,0 +1,147 @@
+package memstore
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/clear-ness/qa-discussion/model"
+)
+
+type MemTagStore struct {
+	*MemStore
+}
+
+func (s *MemTagStore) getTags(options *model.GetTagsOptions) model.Tags {
+	var tags model.Tags
+	for _, tag := range s.tables.tags {
+		if options.Content != "" {
+			if !strings.EqualFold(tag.Content, options.Content) {
+				continue
+			}
+		} else if options.InName != "" && !likeMatch(tag.Content, options.InName+"%", '\\') {
+			continue
+		}
+
+		if tag.TeamId != options.TeamId || tag.Type != options.Type {
+			continue
+		}
+
+		if options.SortType == model.POST_SORT_TYPE_POPULAR && options.Min != nil && tag.PostCount < *options.Min {
+			continue
+		}
+		if options.SortType == model.POST_SORT_TYPE_POPULAR && options.Max != nil && tag.PostCount > *options.Max {
+			continue
+		}
+
+		if options.FromDate != 0 && tag.CreateAt < options.FromDate {
+			continue
+		}
+		if options.ToDate != 0 && tag.CreateAt > options.ToDate {
+			continue
+		}
+
+		tags = append(tags, *clone(tag).(*model.Tag))
+	}
+
+	sort.Slice(tags, func(a, b int) bool {
+		return lessFold(tags[a].Content, tags[b].Content)
+	})
+
+	sort.SliceStable(tags, func(a, b int) bool {
+		switch options.SortType {
+		case model.POST_SORT_TYPE_NAME:
+			return false
+		case model.POST_SORT_TYPE_POPULAR:
+			return tags[a].PostCount > tags[b].PostCount
+		default:
+			return tags[a].CreateAt > tags[b].CreateAt
+		}
+	})
+
+	return tags
+}
+
+func (s *MemTagStore) GetTags(options *model.GetTagsOptions) (model.Tags, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	tags := s.getTags(options)
+
+	start, end := paginate(len(tags), options.Page*options.PerPage, options.PerPage)
+	if start == end {
+		return nil, nil
+	}
+
+	return tags[start:end], nil
+}
+
+func (s *MemTagStore) GetTagsCount(options *model.GetTagsOptions) (int64, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return int64(len(s.getTags(options))), nil
+}
+
+func (s *MemTagStore) CreateTags(addedTags []string, time int64, teamId string, tagType string) *model.AppError {
+	if len(addedTags) <= 0 {
+		return model.NewAppError("MemTagStore.CreateTags", "store.sql_tag.create_tags.no_tags.app_error", nil, "", http.StatusInternalServerError)
+	}
+
+	tags := map[tagKey]*model.Tag{}
+	for _, tagContent := range addedTags {
+		tag := &model.Tag{
+			Content:   tagContent,
+			TeamId:    teamId,
+			Type:      tagType,
+			PostCount: 1,
+			CreateAt:  time,
+			UpdateAt:  time,
+		}
+
+		tag.PreSave()
+		if err := tag.IsValid(); err != nil {
+			return model.NewAppError("MemTagStore.CreateTags", "store.sql_tag.create_tags.inserting.app_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+
+		key := newTagKey(tag.Content, tag.TeamId, tag.Type)
+		if _, ok := tags[key]; ok {
+			return model.NewAppError("MemTagStore.CreateTags", "store.sql_tag.create_tags.inserting.app_error", nil, "duplicate entry content="+tagContent, http.StatusInternalServerError)
+		}
+		tags[key] = tag
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for key := range tags {
+		if _, ok := s.tables.tags[key]; ok {
+			return model.NewAppError("MemTagStore.CreateTags", "store.sql_tag.create_tags.inserting.app_error", nil, "duplicate entry content="+key.content, http.StatusInternalServerError)
+		}
+	}
+
+	for key, tag := range tags {
+		s.tables.tags[key] = tag
+	}
+
+	return nil
+}
+
+// 投稿数は投稿を取り込む時に数えるので0で作る。既にあればそのまま
+func (s *MemTagStore) ImportTag(tag *model.Tag) *model.AppError {
+	tag.PostCount = 0
+	tag.PreSave()
+	if err := tag.IsValid(); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	key := newTagKey(tag.Content, tag.TeamId, tag.Type)
+	if _, ok := s.tables.tags[key]; !ok {
+		s.tables.tags[key] = clone(tag).(*model.Tag)
+	}
+
+	return nil
+}
@@ -0,0 +1,407 @@
+package memstore
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/clear-ness/qa-discussion/store"
+	"github.com/clear-ness/qa-discussion/store/sqlstore"
+)
+
+type MemTeamStore struct {
+	*MemStore
+}
+
+func (s *MemTeamStore) Save(team *model.Team) (*model.Team, *model.AppError) {
+	if len(team.Id) > 0 {
+		return nil, model.NewAppError("MemTeamStore.Save", "store.sql_team.save.existing.app_error", nil, "id="+team.Id, http.StatusBadRequest)
+	}
+
+	team.PreSave()
+
+	if err := team.IsValid(); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, other := range s.tables.teams {
+		if strings.EqualFold(other.Name, team.Name) {
+			return nil, model.NewAppError("MemTeamStore.Save", "store.sql_team.save.domain_exists.app_error", nil, "id="+team.Id, http.StatusBadRequest)
+		}
+	}
+
+	s.tables.teams[team.Id] = clone(team).(*model.Team)
+
+	return team, nil
+}
+
+func (s *MemTeamStore) GetMember(teamId string, userId string) (*model.TeamMember, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	member, ok := s.tables.teamMembers[teamMemberKey{teamId, userId}]
+	if !ok {
+		return nil, model.NewAppError("MemTeamStore.GetMember", "store.sql_team.get_member.missing.app_error", nil, "teamId="+teamId+" userId="+userId, http.StatusNotFound)
+	}
+
+	return clone(member).(*model.TeamMember), nil
+}
+
+func (s *MemTeamStore) GetActiveMemberCount(teamId string) (int64, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return int64(s.activeMemberCount(teamId)), nil
+}
+
+// 削除済みのメンバー、ユーザーは数えない
+func (s *MemTeamStore) activeMemberCount(teamId string) int {
+	count := 0
+	for key, member := range s.tables.teamMembers {
+		if key.teamId != teamId || member.DeleteAt != 0 {
+			continue
+		}
+		if user, ok := s.tables.users[member.UserId]; ok && user.DeleteAt == 0 {
+			count++
+		}
+	}
+
+	return count
+}
+
+func (s *MemTeamStore) SaveMember(member *model.TeamMember, maxUsersPerTeam int) (*model.TeamMember, *model.AppError) {
+	members, err := s.SaveMultipleMembers([]*model.TeamMember{member}, maxUsersPerTeam)
+	if err != nil {
+		return nil, err
+	}
+	return members[0], nil
+}
+
+func (s *MemTeamStore) SaveMultipleMembers(members []*model.TeamMember, maxUsersPerTeam int) ([]*model.TeamMember, *model.AppError) {
+	newTeamMembers := map[string]int{}
+	for _, member := range members {
+		newTeamMembers[member.TeamId]++
+
+		if err := member.IsValid(); err != nil {
+			return nil, err
+		}
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if maxUsersPerTeam >= 0 {
+		for teamId, newMembers := range newTeamMembers {
+			if s.activeMemberCount(teamId)+newMembers > maxUsersPerTeam {
+				return nil, model.NewAppError("MemUserStore.Save", "store.sql_user.save.max_accounts.app_error", nil, "", http.StatusBadRequest)
+			}
+		}
+	}
+
+	// bulk insertと同じく、1件でも重複していれば全て保存しない
+	keys := map[teamMemberKey]bool{}
+	for _, member := range members {
+		key := teamMemberKey{member.TeamId, member.UserId}
+		if _, ok := s.tables.teamMembers[key]; ok || keys[key] {
+			return nil, model.NewAppError("MemTeamStore.SaveMember", sqlstore.TEAM_MEMBER_EXISTS_ERROR, nil, "team_id="+member.TeamId+", user_id="+member.UserId, http.StatusBadRequest)
+		}
+		keys[key] = true
+	}
+
+	newMembers := []*model.TeamMember{}
+	for _, member := range members {
+		s.tables.teamMembers[teamMemberKey{member.TeamId, member.UserId}] = clone(member).(*model.TeamMember)
+		newMembers = append(newMembers, clone(member).(*model.TeamMember))
+	}
+
+	return newMembers, nil
+}
+
+func (s *MemTeamStore) GetTeamsByUserId(userId string) ([]*model.Team, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var teams []*model.Team
+	for key, member := range s.tables.teamMembers {
+		if key.userId != userId || member.DeleteAt != 0 {
+			continue
+		}
+		if team, ok := s.tables.teams[key.teamId]; ok && team.DeleteAt == 0 {
+			teams = append(teams, clone(team).(*model.Team))
+		}
+	}
+
+	sort.Slice(teams, func(a, b int) bool {
+		return teams[a].Id < teams[b].Id
+	})
+
+	return teams, nil
+}
+
+func (s *MemTeamStore) GetTeamsForUser(userId string) ([]*model.TeamMember, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	members := []*model.TeamMember{}
+	for key, member := range s.tables.teamMembers {
+		if key.userId == userId {
+			members = append(members, clone(member).(*model.TeamMember))
+		}
+	}
+
+	sort.Slice(members, func(a, b int) bool {
+		return members[a].TeamId < members[b].TeamId
+	})
+
+	return members, nil
+}
+
+func (s *MemTeamStore) Get(id string) (*model.Team, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	team, ok := s.tables.teams[id]
+	if !ok {
+		return nil, model.NewAppError("MemTeamStore.Get", "store.sql_team.get.find.app_error", nil, "id="+id, http.StatusNotFound)
+	}
+
+	return clone(team).(*model.Team), nil
+}
+
+func (s *MemTeamStore) Update(team *model.Team) (*model.Team, *model.AppError) {
+	team.PreUpdate()
+
+	if err := team.IsValid(); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	oldTeam, ok := s.tables.teams[team.Id]
+	if !ok {
+		return nil, model.NewAppError("MemTeamStore.Update", "store.sql_team.update.find.app_error", nil, "id="+team.Id, http.StatusBadRequest)
+	}
+
+	for _, other := range s.tables.teams {
+		if other.Id != team.Id && strings.EqualFold(other.Name, team.Name) {
+			return nil, model.NewAppError("MemTeamStore.Update", "store.sql_team.update.updating.app_error", nil, "id="+team.Id, http.StatusInternalServerError)
+		}
+	}
+
+	team.CreateAt = oldTeam.CreateAt
+	team.UpdateAt = model.GetMillis()
+
+	s.tables.teams[team.Id] = clone(team).(*model.Team)
+
+	return team, nil
+}
+
+func (s *MemTeamStore) RemoveAllMembersByTeam(teamId string) *model.AppError {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for key := range s.tables.teamMembers {
+		if key.teamId == teamId {
+			delete(s.tables.teamMembers, key)
+		}
+	}
+
+	return nil
+}
+
+func (s *MemTeamStore) PermanentDelete(teamId string) *model.AppError {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.tables.teams, teamId)
+
+	return nil
+}
+
+func (s *MemTeamStore) GetByInviteId(inviteId string) (*model.Team, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var found *model.Team
+	for _, team := range s.tables.teams {
+		if team.InviteId == inviteId {
+			found = team
+			break
+		}
+	}
+
+	if found == nil {
+		return nil, model.NewAppError("MemTeamStore.GetByInviteId", "store.sql_team.get_by_invite_id.finding.app_error", nil, "inviteId="+inviteId, http.StatusNotFound)
+	}
+
+	if len(inviteId) == 0 {
+		return nil, model.NewAppError("MemTeamStore.GetByInviteId", "store.sql_team.get_by_invite_id.find.app_error", nil, "inviteId="+inviteId, http.StatusNotFound)
+	}
+
+	return clone(found).(*model.Team), nil
+}
+
+func (s *MemTeamStore) GetMembers(teamId string, offset int, limit int, teamMembersGetOptions *model.TeamMembersGetOptions) ([]*model.TeamMember, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	members := []*model.TeamMember{}
+	for key, member := range s.tables.teamMembers {
+		if key.teamId != teamId || member.DeleteAt != 0 {
+			continue
+		}
+
+		if teamMembersGetOptions != nil {
+			if teamMembersGetOptions.Type == model.TEAM_MEMBER_TYPE_NORMAL || teamMembersGetOptions.Type == model.TEAM_MEMBER_TYPE_ADMIN {
+				if member.Type != teamMembersGetOptions.Type {
+					continue
+				}
+			}
+
+			if teamMembersGetOptions.ExcludeDeletedUsers {
+				if user, ok := s.tables.users[member.UserId]; !ok || user.DeleteAt != 0 {
+					continue
+				}
+			}
+		}
+
+		members = append(members, clone(member).(*model.TeamMember))
+	}
+
+	keys := func(i int) []interface{} {
+		return []interface{}{members[i].UserId}
+	}
+	var cursor *model.Cursor
+	if teamMembersGetOptions != nil {
+		if teamMembersGetOptions.Sort == model.TEAM_MEMBER_SORT_TYPE_USERNAME {
+			keys = func(i int) []interface{} {
+				username := ""
+				if user, ok := s.tables.users[members[i].UserId]; ok {
+					username = user.Username
+				}
+				return []interface{}{username, members[i].UserId}
+			}
+		}
+		cursor = teamMembersGetOptions.Cursor
+	}
+
+	page := []*model.TeamMember{}
+	for _, i := range keysetPage(len(members), keys, false, cursor, false, offset, limit) {
+		page = append(page, members[i])
+	}
+
+	return page, nil
+}
+
+func (s *MemTeamStore) GetMembersByIds(teamId string, userIds []string) ([]*model.TeamMember, *model.AppError) {
+	if len(userIds) == 0 {
+		return nil, model.NewAppError("MemTeamStore.GetMembersByIds", "store.sql_team.get_members_by_ids.app_error", nil, "Invalid list of user ids", http.StatusInternalServerError)
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	members := []*model.TeamMember{}
+	for _, userId := range uniqueStrings(userIds) {
+		if member, ok := s.tables.teamMembers[teamMemberKey{teamId, userId}]; ok && member.DeleteAt == 0 {
+			members = append(members, clone(member).(*model.TeamMember))
+		}
+	}
+
+	return members, nil
+}
+
+func (s *MemTeamStore) UpdateLastTeamIconUpdate(teamId string, curTime int64) *model.AppError {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if team, ok := s.tables.teams[teamId]; ok {
+		team.LastPictureUpdate = curTime
+		team.UpdateAt = curTime
+	}
+
+	return nil
+}
+
+func (s *MemTeamStore) AutocompletePublic(name string) ([]*model.Team, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var teams []*model.Team
+	for _, team := range s.tables.teams {
+		if team.Type == model.TEAM_TYPE_PUBLIC && team.DeleteAt == 0 && likeMatch(team.Name, name+"%", 0) {
+			teams = append(teams, clone(team).(*model.Team))
+		}
+	}
+
+	sort.Slice(teams, func(a, b int) bool {
+		return teams[a].Id < teams[b].Id
+	})
+	if len(teams) > model.TEAM_SEARCH_DEFAULT_LIMIT {
+		teams = teams[:model.TEAM_SEARCH_DEFAULT_LIMIT]
+	}
+
+	sort.Slice(teams, func(a, b int) bool {
+		return strings.ToLower(teams[a].Name) < strings.ToLower(teams[b].Name)
+	})
+
+	return teams, nil
+}
+
+func (s *MemTeamStore) GetAllWithAllowedDomains() ([]*model.Team, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var teams []*model.Team
+	for _, team := range s.tables.teams {
+		if team.AllowedDomains != "" && team.DeleteAt == 0 {
+			teams = append(teams, clone(team).(*model.Team))
+		}
+	}
+
+	sort.Slice(teams, func(a, b int) bool {
+		return teams[a].Id < teams[b].Id
+	})
+
+	return teams, nil
+}
+
+func (s *MemTeamStore) UpdateMultipleMembers(members []*model.TeamMember) ([]*model.TeamMember, *model.AppError) {
+	for _, member := range members {
+		if err := member.IsValid(); err != nil {
+			return nil, err
+		}
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// トランザクションと同じく、1件でも存在しなければ全て更新しない
+	for _, member := range members {
+		if _, ok := s.tables.teamMembers[teamMemberKey{member.TeamId, member.UserId}]; !ok {
+			return nil, model.NewAppError("MemTeamStore.GetMember", store.MISSING_TEAM_MEMBER_ERROR, nil, "team_id="+member.TeamId+"user_id="+member.UserId, http.StatusNotFound)
+		}
+	}
+
+	updatedMembers := []*model.TeamMember{}
+	for _, member := range members {
+		s.tables.teamMembers[teamMemberKey{member.TeamId, member.UserId}] = clone(member).(*model.TeamMember)
+		updatedMembers = append(updatedMembers, clone(member).(*model.TeamMember))
+	}
+
+	return updatedMembers, nil
+}
+
+func (s *MemTeamStore) UpdateMember(member *model.TeamMember) (*model.TeamMember, *model.AppError) {
+	updatedMembers, err := s.UpdateMultipleMembers([]*model.TeamMember{member})
+	if err != nil {
+		return nil, err
+	}
+
+	return updatedMembers[0], nil
+}
@@ -0,0 +1,49 @@
+package memstore
+
+import (
+	"net/http"
+
+	"github.com/clear-ness/qa-discussion/model"
+)
+
+type MemTokenStore struct {
+	*MemStore
+}
+
+func (s *MemTokenStore) Save(token *model.Token) *model.AppError {
+	if err := token.IsValid(); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.tables.tokens[token.Token]; ok {
+		return model.NewAppError("MemTokenStore.Save", "store.sql_recover.save.app_error", nil, "", http.StatusInternalServerError)
+	}
+
+	s.tables.tokens[token.Token] = clone(token).(*model.Token)
+
+	return nil
+}
+
+func (s *MemTokenStore) GetByToken(tokenString string) (*model.Token, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	token, ok := s.tables.tokens[tokenString]
+	if !ok {
+		return nil, model.NewAppError("MemTokenStore.GetByToken", "store.sql_recover.get_by_code.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	return clone(token).(*model.Token), nil
+}
+
+func (s *MemTokenStore) Delete(token string) *model.AppError {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.tables.tokens, token)
+
+	return nil
+}
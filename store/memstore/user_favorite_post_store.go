@@ -0,0 +1,133 @@
+package memstore
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/clear-ness/qa-discussion/model"
+)
+
+type MemUserFavoritePostStore struct {
+	*MemStore
+}
+
+func (s *MemUserFavoritePostStore) GetByPostIdForUser(userId string, postId string) (*model.UserFavoritePost, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	favoritePost, ok := s.tables.userFavoritePosts[userFavoritePostKey{postId, userId}]
+	if !ok {
+		return nil, nil
+	}
+
+	return clone(favoritePost).(*model.UserFavoritePost), nil
+}
+
+func (s *MemUserFavoritePostStore) GetCountByPostId(postId string) (int64, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	count := int64(0)
+	for key := range s.tables.userFavoritePosts {
+		if key.postId == postId {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (s *MemUserFavoritePostStore) GetUserFavoritePostsBeforeTime(time int64, userId string, page, perPage int, getCount bool, teamId string, cursor *model.Cursor) ([]*model.UserFavoritePost, int64, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	matched := []*model.UserFavoritePost{}
+	for _, favoritePost := range s.tables.userFavoritePosts {
+		if favoritePost.UserId == userId && favoritePost.CreateAt <= time && favoritePost.TeamId == teamId {
+			matched = append(matched, favoritePost)
+		}
+	}
+
+	totalCount := int64(0)
+	if getCount {
+		totalCount = int64(len(matched))
+	}
+
+	keys := func(i int) []interface{} {
+		return []interface{}{matched[i].CreateAt, matched[i].PostId}
+	}
+
+	var favoritePosts []*model.UserFavoritePost
+	for _, i := range keysetPage(len(matched), keys, true, cursor, true, page*perPage, perPage) {
+		favoritePosts = append(favoritePosts, clone(matched[i]).(*model.UserFavoritePost))
+	}
+
+	return favoritePosts, totalCount, nil
+}
+
+func (s *MemUserFavoritePostStore) Save(postId string, userId string, teamId string) *model.AppError {
+	favoritePost := &model.UserFavoritePost{
+		PostId: postId,
+		UserId: userId,
+		TeamId: teamId,
+	}
+
+	favoritePost.PreSave()
+	if err := favoritePost.IsValid(); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	key := userFavoritePostKey{postId, userId}
+	if _, ok := s.tables.userFavoritePosts[key]; ok {
+		return model.NewAppError("MemUserFavoritePostStore.FavoritePost", "store.sql_user_favorite_post.favoritePost.inserting.app_error", nil, "duplicate entry post_id="+postId+", user_id="+userId, http.StatusInternalServerError)
+	}
+	s.tables.userFavoritePosts[key] = favoritePost
+
+	return nil
+}
+
+func (s *MemUserFavoritePostStore) Delete(postId string, userId string) *model.AppError {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.tables.userFavoritePosts, userFavoritePostKey{postId, userId})
+
+	return nil
+}
+
+func (s *MemUserFavoritePostStore) GetForExport(teamId string, offset, limit int) ([]*model.UserFavoritePost, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	matched := []*model.UserFavoritePost{}
+	for _, favoritePost := range s.tables.userFavoritePosts {
+		if favoritePost.TeamId != teamId {
+			continue
+		}
+		if post, ok := s.tables.posts[favoritePost.PostId]; !ok || post.DeleteAt != 0 {
+			continue
+		}
+		matched = append(matched, favoritePost)
+	}
+
+	sort.Slice(matched, func(a, b int) bool {
+		if matched[a].CreateAt != matched[b].CreateAt {
+			return matched[a].CreateAt < matched[b].CreateAt
+		}
+		if matched[a].PostId != matched[b].PostId {
+			return matched[a].PostId < matched[b].PostId
+		}
+		return matched[a].UserId < matched[b].UserId
+	})
+
+	var favorites []*model.UserFavoritePost
+	start, end := paginate(len(matched), offset, limit)
+	for _, favoritePost := range matched[start:end] {
+		favorites = append(favorites, clone(favoritePost).(*model.UserFavoritePost))
+	}
+
+	return favorites, nil
+}
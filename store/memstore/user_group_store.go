@@ -0,0 +1,376 @@
+package memstore
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/clear-ness/qa-discussion/store"
+)
+
+type MemUserGroupStore struct {
+	*MemStore
+}
+
+func sortGroupsByName(groups model.UserGroupList) {
+	sort.SliceStable(groups, func(a, b int) bool {
+		return lessFold(groups[a].Name, groups[b].Name)
+	})
+}
+
+func (s *MemUserGroupStore) sortedGroups() model.UserGroupList {
+	groups := model.UserGroupList{}
+	for _, group := range s.tables.userGroups {
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(a, b int) bool {
+		return groups[a].Id < groups[b].Id
+	})
+
+	return groups
+}
+
+func (s *MemUserGroupStore) GetTeamGroups(teamId string) (*model.UserGroupList, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	data := model.UserGroupList{}
+	for _, group := range s.sortedGroups() {
+		if group.TeamId == teamId {
+			data = append(data, clone(group).(*model.UserGroup))
+		}
+	}
+	sortGroupsByName(data)
+
+	return &data, nil
+}
+
+func (s *MemUserGroupStore) Save(group *model.UserGroup, maxGroupsPerTeam int64) (*model.UserGroup, *model.AppError) {
+	if group.DeleteAt != 0 {
+		return nil, model.NewAppError("MemUserGroupStore.Save", "store.sql_group.save.already_deleted.app_error", nil, "", http.StatusInternalServerError)
+	}
+
+	if len(group.Id) > 0 {
+		return nil, model.NewAppError("MemUserGroupStore.Save", "store.sql_group.save.existing.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	group.PreSave()
+	if err := group.IsValid(); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if maxGroupsPerTeam >= 0 {
+		count := int64(0)
+		for _, other := range s.tables.userGroups {
+			if other.TeamId == group.TeamId && other.DeleteAt == 0 {
+				count++
+			}
+		}
+		if count >= maxGroupsPerTeam {
+			return nil, model.NewAppError("MemUserGroupStore.Save", "store.sql_group.save.too_many.app_error", nil, "", http.StatusBadRequest)
+		}
+	}
+
+	if s.nameExists(group) {
+		return nil, model.NewAppError("MemUserGroupStore.Save", "store.sql_group.save.exists.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	s.tables.userGroups[group.Id] = clone(group).(*model.UserGroup)
+
+	return group, nil
+}
+
+// (Name, TeamId)のユニーク制約
+func (s *MemUserGroupStore) nameExists(group *model.UserGroup) bool {
+	for _, other := range s.tables.userGroups {
+		if other.Id != group.Id && other.TeamId == group.TeamId && strings.EqualFold(other.Name, group.Name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *MemUserGroupStore) SaveMultipleMembers(members []*model.GroupMember) ([]*model.GroupMember, *model.AppError) {
+	members, err := s.saveMultipleMembers(members)
+	if err != nil {
+		return nil, model.NewAppError("SaveMultipleMembers", "app.group.save_multiple.internal_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	return members, nil
+}
+
+func (s *MemUserGroupStore) SaveMember(member *model.GroupMember) (*model.GroupMember, *model.AppError) {
+	newMembers, appErr := s.SaveMultipleMembers([]*model.GroupMember{member})
+	if appErr != nil {
+		return nil, appErr
+	}
+	return newMembers[0], nil
+}
+
+func (s *MemUserGroupStore) saveMultipleMembers(members []*model.GroupMember) ([]*model.GroupMember, error) {
+	for _, member := range members {
+		if err := member.IsValid(); err != nil {
+			return nil, err
+		}
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	keys := map[groupMemberKey]bool{}
+	for _, member := range members {
+		key := groupMemberKey{member.GroupId, member.UserId}
+		if _, ok := s.tables.groupMembers[key]; ok || keys[key] {
+			return nil, errors.Errorf("group_members_save: duplicate entry group_id=%s user_id=%s", member.GroupId, member.UserId)
+		}
+		keys[key] = true
+	}
+
+	for _, member := range members {
+		s.tables.groupMembers[groupMemberKey{member.GroupId, member.UserId}] = clone(member).(*model.GroupMember)
+	}
+
+	return members, nil
+}
+
+func (s *MemUserGroupStore) GetGroupsForTeam(teamId string, groupType string, offset int, limit int) (*model.UserGroupList, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	groups := model.UserGroupList{}
+	for _, group := range s.sortedGroups() {
+		if group.TeamId != teamId || group.DeleteAt != 0 {
+			continue
+		}
+		if (groupType == model.GROUP_TYPE_PUBLIC || groupType == model.GROUP_TYPE_PRIVATE) && group.Type != groupType {
+			continue
+		}
+		groups = append(groups, clone(group).(*model.UserGroup))
+	}
+	sortGroupsByName(groups)
+
+	start, end := paginate(len(groups), offset, limit)
+	groups = groups[start:end]
+
+	return &groups, nil
+}
+
+func (s *MemUserGroupStore) AutocompleteInTeam(teamId string, term string, groupType string, includeDeleted bool) (*model.UserGroupList, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	likeTerm := sanitizeSearchTerm(term, "*")
+
+	groups := model.UserGroupList{}
+	for _, group := range s.sortedGroups() {
+		if group.TeamId != teamId {
+			continue
+		}
+		if !includeDeleted && group.DeleteAt != 0 {
+			continue
+		}
+		if (groupType == model.GROUP_TYPE_PUBLIC || groupType == model.GROUP_TYPE_PRIVATE) && group.Type != groupType {
+			continue
+		}
+		if likeTerm != "" && !likeMatch(group.Name, likeTerm+"%", '*') {
+			continue
+		}
+
+		groups = append(groups, clone(group).(*model.UserGroup))
+		if len(groups) == model.GROUP_SEARCH_DEFAULT_LIMIT {
+			break
+		}
+	}
+
+	sort.Slice(groups, func(a, b int) bool {
+		return strings.ToLower(groups[a].Name) < strings.ToLower(groups[b].Name)
+	})
+
+	return &groups, nil
+}
+
+func (s *MemUserGroupStore) GetGroups(teamId string, userId string, includeDeleted bool) (*model.UserGroupList, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	groups := model.UserGroupList{}
+	for _, group := range s.sortedGroups() {
+		if group.TeamId != teamId || (!includeDeleted && group.DeleteAt != 0) {
+			continue
+		}
+		if _, ok := s.tables.groupMembers[groupMemberKey{group.Id, userId}]; ok {
+			groups = append(groups, clone(group).(*model.UserGroup))
+		}
+	}
+	sortGroupsByName(groups)
+
+	if len(groups) == 0 {
+		return nil, model.NewAppError("MemUserGroupStore.GetGroups", store.MISSING_GROUPS_ERROR, nil, "teamId="+teamId+", userId="+userId, http.StatusBadRequest)
+	}
+
+	return &groups, nil
+}
+
+func (s *MemUserGroupStore) Get(id string) (*model.UserGroup, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	group, ok := s.tables.userGroups[id]
+	if !ok {
+		return nil, model.NewAppError("MemUserGroupStore.Get", "store.sql_group.get.not_found.app_error", nil, "id="+id, http.StatusNotFound)
+	}
+
+	return clone(group).(*model.UserGroup), nil
+}
+
+func (s *MemUserGroupStore) GetAllGroupMembersForUser(userId string) (map[string]string, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	result := make(map[string]string)
+	for key, member := range s.tables.groupMembers {
+		if key.userId != userId {
+			continue
+		}
+		if group, ok := s.tables.userGroups[key.groupId]; ok && group.DeleteAt == 0 {
+			result[member.GroupId] = member.Type
+		}
+	}
+
+	return result, nil
+}
+
+func (s *MemUserGroupStore) Update(group *model.UserGroup) (*model.UserGroup, *model.AppError) {
+	group.PreUpdate()
+
+	if group.DeleteAt != 0 {
+		return nil, model.NewAppError("MemUserGroupStore.Update", "store.sql_group.update.deleted.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if err := group.IsValid(); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.nameExists(group) {
+		return nil, model.NewAppError("MemUserGroupStore.Update", "store.sql_group.update.uniq.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if _, ok := s.tables.userGroups[group.Id]; !ok {
+		return nil, model.NewAppError("MemUserGroupStore.Update", "store.sql_group.update.app_error", nil, "", http.StatusInternalServerError)
+	}
+
+	s.tables.userGroups[group.Id] = clone(group).(*model.UserGroup)
+
+	return group, nil
+}
+
+func (s *MemUserGroupStore) Delete(groupId string, time int64) *model.AppError {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	group, ok := s.tables.userGroups[groupId]
+	if !ok || group.DeleteAt != 0 {
+		return model.NewAppError("MemUserGroupStore.DeleteGroup", "store.sql_group.delete_group.app_error", nil, "id="+groupId, http.StatusInternalServerError)
+	}
+
+	group.DeleteAt = time
+	group.UpdateAt = time
+
+	return nil
+}
+
+func (s *MemUserGroupStore) GetMembers(groupId string, memberType string, offset, limit int, cursor *model.Cursor) (*model.GroupMembers, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	members := model.GroupMembers{}
+	if group, ok := s.tables.userGroups[groupId]; ok && group.DeleteAt == 0 {
+		for key, member := range s.tables.groupMembers {
+			if key.groupId == groupId && (memberType == "" || member.Type == memberType) {
+				members = append(members, *member)
+			}
+		}
+	}
+
+	keys := func(i int) []interface{} {
+		return []interface{}{members[i].UserId}
+	}
+
+	page := model.GroupMembers{}
+	for _, i := range keysetPage(len(members), keys, false, cursor, false, offset, limit) {
+		page = append(page, members[i])
+	}
+
+	return &page, nil
+}
+
+func (s *MemUserGroupStore) GetMember(groupId string, userId string) (*model.GroupMember, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	member, ok := s.tables.groupMembers[groupMemberKey{groupId, userId}]
+	if !ok {
+		return nil, model.NewAppError("MemUserGroupStore.GetMember", store.MISSING_GROUP_MEMBER_ERROR, nil, "group_id="+groupId+"user_id="+userId, http.StatusNotFound)
+	}
+
+	return clone(member).(*model.GroupMember), nil
+}
+
+func (s *MemUserGroupStore) UpdateMultipleMembers(members []*model.GroupMember) ([]*model.GroupMember, *model.AppError) {
+	for _, member := range members {
+		if err := member.IsValid(); err != nil {
+			return nil, err
+		}
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, member := range members {
+		if _, ok := s.tables.groupMembers[groupMemberKey{member.GroupId, member.UserId}]; !ok {
+			return nil, model.NewAppError("MemUserGroupStore.GetMember", store.MISSING_GROUP_MEMBER_ERROR, nil, "group_id="+member.GroupId+"user_id="+member.UserId, http.StatusNotFound)
+		}
+	}
+
+	updatedMembers := []*model.GroupMember{}
+	for _, member := range members {
+		s.tables.groupMembers[groupMemberKey{member.GroupId, member.UserId}] = clone(member).(*model.GroupMember)
+		updatedMembers = append(updatedMembers, clone(member).(*model.GroupMember))
+	}
+
+	return updatedMembers, nil
+}
+
+func (s *MemUserGroupStore) UpdateMember(member *model.GroupMember) (*model.GroupMember, *model.AppError) {
+	updatedMembers, err := s.UpdateMultipleMembers([]*model.GroupMember{member})
+	if err != nil {
+		return nil, err
+	}
+
+	return updatedMembers[0], nil
+}
+
+func (s *MemUserGroupStore) RemoveMembers(groupId string, userIds []string) *model.AppError {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, userId := range userIds {
+		delete(s.tables.groupMembers, groupMemberKey{groupId, userId})
+	}
+
+	return nil
+}
+
+func (s *MemUserGroupStore) RemoveMember(groupId string, userId string) *model.AppError {
+	return s.RemoveMembers(groupId, []string{userId})
+}
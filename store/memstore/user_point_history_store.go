@@ -0,0 +1,50 @@
+package memstore
+
+import (
+	"sort"
+
+	"github.com/clear-ness/qa-discussion/model"
+)
+
+type MemUserPointHistoryStore struct {
+	*MemStore
+}
+
+func (s *MemUserPointHistoryStore) GetUserPointHistoryBeforeTime(time int64, userId string, page, perPage int, teamId string) ([]*model.UserPointHistory, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	matched := []*model.UserPointHistory{}
+	for _, history := range s.tables.userPointHistory {
+		if history.UserId == userId && history.CreateAt <= time && matchesNullableTeam(history.TeamId, teamId) {
+			matched = append(matched, history)
+		}
+	}
+
+	sort.Slice(matched, func(a, b int) bool {
+		if matched[a].CreateAt == matched[b].CreateAt {
+			return matched[a].Id < matched[b].Id
+		}
+		return matched[a].CreateAt > matched[b].CreateAt
+	})
+
+	var history []*model.UserPointHistory
+	start, end := paginate(len(matched), page*perPage, perPage)
+	for _, h := range matched[start:end] {
+		history = append(history, clone(h).(*model.UserPointHistory))
+	}
+
+	return history, nil
+}
+
+func (s *MemUserPointHistoryStore) TopAskersByTag(interval string, teamId string, tag string, limit int) ([]*model.TopUserByTagResult, *model.AppError) {
+	return nil, nil
+}
+
+func (s *MemUserPointHistoryStore) TopAnswerersByTag(interval string, teamId string, tag string, limit int) ([]*model.TopUserByTagResult, *model.AppError) {
+	return nil, nil
+}
+
+func (s *MemUserPointHistoryStore) TopAnswersByTag(interval string, teamId string, tag string, limit int) ([]*model.TopPostByTagResult, *model.AppError) {
+	return nil, nil
+}
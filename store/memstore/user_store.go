@@ -0,0 +1,473 @@
+package memstore
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/clear-ness/qa-discussion/store"
+)
+
+type MemUserStore struct {
+	*MemStore
+}
+
+func (us *MemUserStore) sortedUsers() []*model.User {
+	users := []*model.User{}
+	for _, user := range us.tables.users {
+		users = append(users, user)
+	}
+	sort.Slice(users, func(a, b int) bool {
+		return users[a].Id < users[b].Id
+	})
+
+	return users
+}
+
+// Email, AuthDataのユニーク制約。違反したカラム名を返す
+func (us *MemUserStore) uniqueViolation(user *model.User) string {
+	for _, other := range us.tables.users {
+		if other.Id == user.Id {
+			continue
+		}
+		if strings.EqualFold(other.Email, user.Email) {
+			return "Email"
+		}
+		if other.AuthData != nil && user.AuthData != nil && *other.AuthData == *user.AuthData {
+			return "AuthData"
+		}
+	}
+
+	return ""
+}
+
+func (us *MemUserStore) Save(user *model.User) (*model.User, *model.AppError) {
+	if len(user.Id) > 0 {
+		return nil, model.NewAppError("MemUserStore.Save", "store.sql_user.save.existing.app_error", nil, "user_id="+user.Id, http.StatusBadRequest)
+	}
+
+	user.PreSave()
+	if err := user.IsValid(); err != nil {
+		return nil, err
+	}
+
+	us.mutex.Lock()
+	defer us.mutex.Unlock()
+
+	switch us.uniqueViolation(user) {
+	case "Email":
+		return nil, model.NewAppError("MemUserStore.Save", "store.sql_user.save.email_exists.app_error", nil, "user_id="+user.Id, http.StatusBadRequest)
+	case "AuthData":
+		return nil, model.NewAppError("MemUserStore.Save", "store.sql_user.save.auth_data_exists.app_error", nil, "user_id="+user.Id, http.StatusBadRequest)
+	}
+
+	us.tables.users[user.Id] = clone(user).(*model.User)
+
+	return user, nil
+}
+
+func (us *MemUserStore) Update(user *model.User, trustedUpdateData bool) (*model.UserUpdate, *model.AppError) {
+	user.PreUpdate()
+
+	if err := user.IsValid(); err != nil {
+		return nil, err
+	}
+
+	us.mutex.Lock()
+	defer us.mutex.Unlock()
+
+	stored, ok := us.tables.users[user.Id]
+	if !ok {
+		return nil, model.NewAppError("MemUserStore.Update", "store.sql_user.update.find.app_error", nil, "user_id="+user.Id, http.StatusBadRequest)
+	}
+
+	oldUser := clone(stored).(*model.User)
+	user.CreateAt = oldUser.CreateAt
+	user.SuspendTime = oldUser.SuspendTime
+	user.Password = oldUser.Password
+	user.Props = oldUser.Props
+	user.EmailVerified = oldUser.EmailVerified
+	user.FailedAttempts = oldUser.FailedAttempts
+	user.Points = oldUser.Points
+	user.LastInboxMessageViewed = oldUser.LastInboxMessageViewed
+	user.AuthService = oldUser.AuthService
+	user.AuthData = oldUser.AuthData
+
+	if !trustedUpdateData {
+		user.Type = oldUser.Type
+		user.DeleteAt = oldUser.DeleteAt
+	}
+
+	if user.Email != oldUser.Email {
+		user.EmailVerified = false
+	}
+
+	if us.uniqueViolation(user) == "Email" {
+		return nil, model.NewAppError("MemUserStore.Update", "store.sql_user.update.email_taken.app_error", nil, "user_id="+user.Id, http.StatusBadRequest)
+	}
+
+	us.tables.users[user.Id] = clone(user).(*model.User)
+
+	user.Sanitize(map[string]bool{})
+	oldUser.Sanitize(map[string]bool{})
+	return &model.UserUpdate{New: user, Old: oldUser}, nil
+}
+
+func (us *MemUserStore) Get(id string) (*model.User, *model.AppError) {
+	us.mutex.RLock()
+	defer us.mutex.RUnlock()
+
+	user, ok := us.tables.users[id]
+	if !ok || user.DeleteAt != 0 {
+		return nil, model.NewAppError("MemUserStore.Get", store.MISSING_ACCOUNT_ERROR, nil, "user_id="+id, http.StatusNotFound)
+	}
+
+	return clone(user).(*model.User), nil
+}
+
+func (us *MemUserStore) GetByIds(userIds []string) ([]*model.User, *model.AppError) {
+	us.mutex.RLock()
+	defer us.mutex.RUnlock()
+
+	return us.getByIds(userIds), nil
+}
+
+func (us *MemUserStore) getByIds(userIds []string) []*model.User {
+	ids := map[string]bool{}
+	for _, id := range userIds {
+		ids[id] = true
+	}
+
+	var users []*model.User
+	for _, user := range us.sortedUsers() {
+		if ids[user.Id] && user.DeleteAt == 0 {
+			users = append(users, clone(user).(*model.User))
+		}
+	}
+
+	return users
+}
+
+func (us *MemUserStore) GetByEmail(email string) (*model.User, *model.AppError) {
+	email = strings.ToLower(email)
+
+	us.mutex.RLock()
+	defer us.mutex.RUnlock()
+
+	for _, user := range us.sortedUsers() {
+		if strings.EqualFold(user.Email, email) {
+			return clone(user).(*model.User), nil
+		}
+	}
+
+	return nil, model.NewAppError("MemUserStore.GetByEmail", store.MISSING_ACCOUNT_ERROR, nil, "email="+email, http.StatusInternalServerError)
+}
+
+func (us *MemUserStore) GetByUsername(username string) (*model.User, *model.AppError) {
+	us.mutex.RLock()
+	defer us.mutex.RUnlock()
+
+	for _, user := range us.sortedUsers() {
+		if strings.EqualFold(user.Username, model.NormalizeUsername(username)) {
+			return clone(user).(*model.User), nil
+		}
+	}
+
+	return nil, model.NewAppError("MemUserStore.GetByUsername", store.MISSING_ACCOUNT_ERROR, nil, "username="+username, http.StatusNotFound)
+}
+
+// sqlstoreと同じ計算式で、インポートした投稿・投票数からポイントを計算し直す
+func (us *MemUserStore) RecomputePoints(teamId string, userIds []string) *model.AppError {
+	if len(userIds) == 0 {
+		return nil
+	}
+
+	us.mutex.Lock()
+	defer us.mutex.Unlock()
+
+	points := map[string]int{}
+	for _, post := range us.tables.posts {
+		if post.TeamId != teamId || post.DeleteAt != 0 {
+			continue
+		}
+
+		switch post.Type {
+		case model.POST_TYPE_QUESTION:
+			points[post.UserId] += model.USER_POINT_FOR_CREATE_QUESTION
+			if post.BestId != "" {
+				points[post.UserId] += model.USER_POINT_FOR_SELECT_ANSWER
+			}
+		case model.POST_TYPE_ANSWER:
+			points[post.UserId] += model.USER_POINT_FOR_CREATE_ANSWER
+		}
+		points[post.UserId] += post.UpVotes*model.USER_POINT_FOR_VOTED + post.DownVotes*model.USER_POINT_FOR_DOWN_VOTED + post.FlagCount*model.USER_POINT_FOR_FLAGGED
+	}
+
+	for _, question := range us.tables.posts {
+		if question.BestId == "" {
+			continue
+		}
+		answer, ok := us.tables.posts[question.BestId]
+		if !ok || answer.TeamId != teamId || answer.DeleteAt != 0 || answer.UserId == question.UserId {
+			continue
+		}
+		points[answer.UserId] += model.USER_POINT_FOR_SELECTED_ANSWER
+	}
+
+	for _, userId := range userIds {
+		if len(teamId) == 0 {
+			if user, ok := us.tables.users[userId]; ok {
+				user.Points = points[userId]
+			}
+		} else if member, ok := us.tables.teamMembers[teamMemberKey{teamId, userId}]; ok {
+			member.Points = points[userId]
+		}
+	}
+
+	return nil
+}
+
+func (us *MemUserStore) GetByAuth(authData *string, authService string) (*model.User, *model.AppError) {
+	if authData == nil || *authData == "" {
+		return nil, model.NewAppError("MemUserStore.GetByAuth", store.MISSING_AUTH_ACCOUNT_ERROR, nil, "authData='', authService="+authService, http.StatusBadRequest)
+	}
+
+	us.mutex.RLock()
+	defer us.mutex.RUnlock()
+
+	for _, user := range us.sortedUsers() {
+		if user.AuthData != nil && *user.AuthData == *authData && user.AuthService == authService {
+			return clone(user).(*model.User), nil
+		}
+	}
+
+	return nil, model.NewAppError("MemUserStore.GetByAuth", store.MISSING_AUTH_ACCOUNT_ERROR, nil, "authData="+*authData+", authService="+authService, http.StatusBadRequest)
+}
+
+func (us *MemUserStore) GetUsersByDates(options *model.GetUsersOptions) ([]*model.User, *model.AppError) {
+	us.mutex.RLock()
+	defer us.mutex.RUnlock()
+
+	votes := options.SortType == "votes"
+
+	var users []*model.User
+	for _, user := range us.sortedUsers() {
+		if user.DeleteAt != 0 {
+			continue
+		}
+		if options.FromDate != 0 && user.CreateAt < options.FromDate {
+			continue
+		}
+		if options.ToDate != 0 && user.CreateAt > options.ToDate {
+			continue
+		}
+		// botはポイントを持たないので順位に含めない
+		if votes && user.Type == model.USER_TYPE_BOT {
+			continue
+		}
+		if votes && options.Min != nil && user.Points < *options.Min {
+			continue
+		}
+		if votes && options.Max != nil && user.Points > *options.Max {
+			continue
+		}
+		if len(options.Username) > 0 && !likeMatch(user.Username, options.Username+"%", '\\') {
+			continue
+		}
+
+		users = append(users, clone(user).(*model.User))
+	}
+
+	sort.SliceStable(users, func(a, b int) bool {
+		if votes {
+			return users[a].Points > users[b].Points
+		}
+		return users[a].CreateAt > users[b].CreateAt
+	})
+
+	start, end := paginate(len(users), options.Page*options.PerPage, options.PerPage)
+	if start == end {
+		return nil, nil
+	}
+
+	return users[start:end], nil
+}
+
+func (us *MemUserStore) GetForLogin(loginId string) (*model.User, *model.AppError) {
+	us.mutex.RLock()
+	defer us.mutex.RUnlock()
+
+	users := []*model.User{}
+	for _, user := range us.sortedUsers() {
+		if strings.EqualFold(user.Email, loginId) && user.Type != model.USER_TYPE_BOT {
+			users = append(users, clone(user).(*model.User))
+		}
+	}
+
+	if len(users) == 0 {
+		return nil, model.NewAppError("MemUserStore.GetForLogin", "store.sql_user.get_for_login.app_error", nil, "", http.StatusInternalServerError)
+	}
+	if len(users) > 1 {
+		return nil, model.NewAppError("MemUserStore.GetForLogin", "store.sql_user.get_for_login.multiple_users", nil, "", http.StatusInternalServerError)
+	}
+
+	return users[0], nil
+}
+
+func (us *MemUserStore) VerifyEmail(userId, email string) (string, *model.AppError) {
+	us.mutex.Lock()
+	defer us.mutex.Unlock()
+
+	if user, ok := us.tables.users[userId]; ok {
+		user.Email = email
+		user.EmailVerified = true
+		user.UpdateAt = model.GetMillis()
+	}
+
+	return userId, nil
+}
+
+func (us *MemUserStore) GetByInboxInterval(fromUserId string, inboxInterval string, limit int) ([]*model.User, *model.AppError) {
+	us.mutex.RLock()
+	defer us.mutex.RUnlock()
+
+	var userIds []string
+	for _, setting := range us.tables.notificationSetting {
+		if setting.InboxInterval == inboxInterval && setting.UserId > fromUserId {
+			userIds = append(userIds, setting.UserId)
+		}
+	}
+	sort.Strings(userIds)
+	if limit >= 0 && len(userIds) > limit {
+		userIds = userIds[:limit]
+	}
+
+	return us.getByIds(userIds), nil
+}
+
+func (us *MemUserStore) UpdateLastInboxMessageViewed(message *model.InboxMessage, userId string) *model.AppError {
+	us.mutex.Lock()
+	defer us.mutex.Unlock()
+
+	user, ok := us.tables.users[userId]
+	if !ok {
+		return model.NewAppError("MemUserStore.UpdateLastInboxMessageViewed", "store.sql_user.update_last_inbox_message_viewed.get_last_message_viewed.app_error", nil, "userId="+userId, http.StatusInternalServerError)
+	}
+
+	if user.LastInboxMessageViewed >= message.CreateAt {
+		return model.NewAppError("MemUserStore.UpdateLastInboxMessageViewed", "store.sql_user.update_last_inbox_message_viewed.already_read_message.app_error", nil, "userId="+userId, http.StatusBadRequest)
+	}
+
+	user.LastInboxMessageViewed = message.CreateAt
+	user.UpdateAt = model.GetMillis()
+
+	return nil
+}
+
+func (us *MemUserStore) SuspendUser(userId string, suspendSpan string, moderatorId string) *model.AppError {
+	us.mutex.Lock()
+	defer us.mutex.Unlock()
+
+	user, ok := us.tables.users[userId]
+	if !ok || user.DeleteAt != 0 {
+		return model.NewAppError("MemUserStore.SuspendUser", "store.sql_user.suspend_user.app_error", nil, "id="+userId, http.StatusInternalServerError)
+	}
+
+	curTime := model.GetMillis()
+
+	user.AddProp(model.USER_PROPS_SUSPEND_BY, moderatorId)
+	user.SuspendTime = model.GetSuspendTimeBySpan(suspendSpan, curTime)
+	user.UpdateAt = curTime
+
+	return nil
+}
+
+func (us *MemUserStore) Delete(userId string, time int64, deleteById string) *model.AppError {
+	us.mutex.Lock()
+	defer us.mutex.Unlock()
+
+	user, ok := us.tables.users[userId]
+	if !ok || user.DeleteAt != 0 {
+		return model.NewAppError("MemUserStore.DeleteUser", "store.sql_user.delete_user.app_error", nil, "id="+userId, http.StatusInternalServerError)
+	}
+
+	user.AddProp(model.USER_PROPS_DELETE_BY, deleteById)
+	user.DeleteAt = time
+	user.UpdateAt = time
+
+	return nil
+}
+
+func (us *MemUserStore) UpdatePassword(userId, hashedPassword string) *model.AppError {
+	us.mutex.Lock()
+	defer us.mutex.Unlock()
+
+	if user, ok := us.tables.users[userId]; ok {
+		user.Password = hashedPassword
+		user.UpdateAt = model.GetMillis()
+		user.FailedAttempts = 0
+	}
+
+	return nil
+}
+
+func (us *MemUserStore) UpdateFailedPasswordAttempts(userId string, attempts int) *model.AppError {
+	us.mutex.Lock()
+	defer us.mutex.Unlock()
+
+	if user, ok := us.tables.users[userId]; ok {
+		user.FailedAttempts = attempts
+	}
+
+	return nil
+}
+
+func (us *MemUserStore) Count(options *model.UserCountOptions) (int64, *model.AppError) {
+	us.mutex.RLock()
+	defer us.mutex.RUnlock()
+
+	count := int64(0)
+	for _, user := range us.tables.users {
+		if options.IncludeDeleted || user.DeleteAt == 0 {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (us *MemUserStore) UpdateLastPictureUpdate(userId string, time int64) *model.AppError {
+	us.mutex.Lock()
+	defer us.mutex.Unlock()
+
+	if user, ok := us.tables.users[userId]; ok {
+		user.LastPictureUpdate = time
+		user.UpdateAt = time
+	}
+
+	return nil
+}
+
+// チームに所属したことのあるユーザーをId順に返す(退会済みのメンバーも含む)
+func (us *MemUserStore) GetTeamUsersForExport(teamId string, afterId string, limit int) ([]*model.User, *model.AppError) {
+	us.mutex.RLock()
+	defer us.mutex.RUnlock()
+
+	var users []*model.User
+	for _, user := range us.sortedUsers() {
+		if user.Id <= afterId {
+			continue
+		}
+		if _, ok := us.tables.teamMembers[teamMemberKey{teamId, user.Id}]; !ok {
+			continue
+		}
+
+		users = append(users, clone(user).(*model.User))
+		if len(users) == limit {
+			break
+		}
+	}
+
+	return users, nil
+}
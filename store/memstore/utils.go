@@ -0,0 +1,109 @@
+package memstore
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/clear-ness/qa-discussion/utils"
+)
+
+// mysqlのLIKEと同じく大文字小文字を区別せずに比較する。escapeが0の場合はエスケープしない
+func likeMatch(value string, pattern string, escape rune) bool {
+	var expr strings.Builder
+	expr.WriteString("(?is)^")
+
+	escaped := false
+	for _, c := range pattern {
+		switch {
+		case escaped:
+			expr.WriteString(regexp.QuoteMeta(string(c)))
+			escaped = false
+		case escape != 0 && c == escape:
+			escaped = true
+		case c == '%':
+			expr.WriteString(".*")
+		case c == '_':
+			expr.WriteString(".")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	expr.WriteString("$")
+
+	re, err := regexp.Compile(expr.String())
+	if err != nil {
+		return false
+	}
+
+	return re.MatchString(value)
+}
+
+// mysqlの照合順序と同じく大文字小文字を区別せずに比較する
+func lessFold(a, b string) bool {
+	return strings.ToLower(a) < strings.ToLower(b)
+}
+
+// IN句と同じく、同じ値が複数あっても1度だけ扱う
+func uniqueStrings(values []string) []string {
+	seen := map[string]bool{}
+	result := []string{}
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			result = append(result, value)
+		}
+	}
+
+	return result
+}
+
+var escapeLikeSearchChar = []string{
+	"%",
+	"_",
+}
+
+// sqlstoreと同じくLIKEのワイルドカードをエスケープする
+func sanitizeSearchTerm(term string, escapeChar string) string {
+	term = strings.Replace(term, escapeChar, "", -1)
+
+	for _, c := range escapeLikeSearchChar {
+		term = strings.Replace(term, c, escapeChar+c, -1)
+	}
+
+	return term
+}
+
+// sqlstoreのdateFromMillisExprと同じくYYYY-MM-DDにする
+func dateFromMillis(millis int64) string {
+	return time.Unix(0, millis*int64(time.Millisecond)).Format("2006-01-02")
+}
+
+// analyticsの集計対象の期間。sqlstoreと同じく昨日までの約1か月
+func analyticsRange() (int64, int64) {
+	end := utils.MillisFromTime(utils.EndOfDay(utils.Yesterday()))
+	start := utils.MillisFromTime(utils.StartOfDay(utils.Yesterday().AddDate(0, 0, -31)))
+
+	return start, end
+}
+
+// 日付ごとの集計結果を新しい日付から30件返す
+func dailyAnalytics(values map[string]float64) model.Analytics {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(names)))
+
+	var rows model.Analytics
+	for i, name := range names {
+		if i == 30 {
+			break
+		}
+		rows = append(rows, &model.Analytic{Name: name, Value: values[name]})
+	}
+
+	return rows
+}
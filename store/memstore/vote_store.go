@@ -0,0 +1,311 @@
+package memstore
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/clear-ness/qa-discussion/model"
+)
+
+type MemVoteStore struct {
+	*MemStore
+}
+
+// 主キー(UserId, Type, PostId)順に返す
+func (s *MemVoteStore) sortedVotes() []*model.Vote {
+	votes := make([]*model.Vote, 0, len(s.tables.votes))
+	for _, vote := range s.tables.votes {
+		votes = append(votes, vote)
+	}
+	sort.Slice(votes, func(a, b int) bool {
+		if votes[a].UserId != votes[b].UserId {
+			return votes[a].UserId < votes[b].UserId
+		}
+		if votes[a].Type != votes[b].Type {
+			return votes[a].Type < votes[b].Type
+		}
+		return votes[a].PostId < votes[b].PostId
+	})
+
+	return votes
+}
+
+func isReviewType(voteType string) bool {
+	return voteType == model.VOTE_TYPE_REVIEW || voteType == model.VOTE_TYPE_FLAG || voteType == model.VOTE_TYPE_SYSTEM
+}
+
+func sortVotesByCreateAtDesc(votes []*model.Vote) {
+	sort.SliceStable(votes, func(a, b int) bool {
+		return votes[a].CreateAt > votes[b].CreateAt
+	})
+}
+
+func (s *MemVoteStore) GetVotesBeforeTime(time int64, userId string, page, perPage int, excludeFlag bool, getCount bool, teamId string, cursor *model.Cursor) ([]*model.Vote, int64, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	matched := []*model.Vote{}
+	for _, vote := range s.sortedVotes() {
+		if vote.UserId != userId || vote.CreateAt > time || !matchesNullableTeam(vote.TeamId, teamId) {
+			continue
+		}
+		if vote.Type != model.VOTE_TYPE_UP_VOTE && vote.Type != model.VOTE_TYPE_DOWN_VOTE && (excludeFlag || vote.Type != model.VOTE_TYPE_FLAG) {
+			continue
+		}
+		matched = append(matched, vote)
+	}
+
+	totalCount := int64(0)
+	if getCount {
+		totalCount = int64(len(matched))
+	}
+
+	keys := func(i int) []interface{} {
+		return []interface{}{matched[i].CreateAt, matched[i].PostId, matched[i].Type}
+	}
+
+	var votes []*model.Vote
+	for _, i := range keysetPage(len(matched), keys, true, cursor, true, page*perPage, perPage) {
+		votes = append(votes, clone(matched[i]).(*model.Vote))
+	}
+
+	return votes, totalCount, nil
+}
+
+func (s *MemVoteStore) GetByPostIdForUser(userId string, postId string, voteType string) (*model.Vote, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	vote, ok := s.tables.votes[voteKey{userId, voteType, postId}]
+	if !ok {
+		return nil, nil
+	}
+
+	return clone(vote).(*model.Vote), nil
+}
+
+func (s *MemVoteStore) GetVoteTypesForPost(userId string, postId string) ([]string, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	types := []string{}
+	for _, vote := range s.sortedVotes() {
+		if vote.UserId == userId && vote.PostId == postId {
+			types = append(types, vote.Type)
+		}
+	}
+
+	return types, nil
+}
+
+func (s *MemVoteStore) CreateReviewVote(post *model.Post, userId string, tagContents string, revision int64) (*model.Vote, *model.AppError) {
+	curTime := model.GetMillis()
+
+	review := &model.Vote{
+		PostId:       post.Id,
+		UserId:       userId,
+		Type:         model.VOTE_TYPE_REVIEW,
+		Tags:         tagContents,
+		TeamId:       post.TeamId,
+		FirstPostRev: int(revision),
+		CreateAt:     curTime,
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	key := voteKey{review.UserId, review.Type, review.PostId}
+	if _, ok := s.tables.votes[key]; ok {
+		return nil, model.NewAppError("MemVoteStore.CreateReviewVote", "store.sql_vote.create_review_vote.inserting.app_error", nil, "duplicate entry post_id="+post.Id+", user_id="+userId, http.StatusInternalServerError)
+	}
+	s.tables.votes[key] = clone(review).(*model.Vote)
+
+	return review, nil
+}
+
+func (s *MemVoteStore) GetRejectedReviewsCount(postId string, currentRevision int64) (int64, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	count := int64(0)
+	for _, vote := range s.tables.votes {
+		if isReviewType(vote.Type) && vote.PostId == postId && int64(vote.LastPostRev) == currentRevision && vote.RejectedAt > 0 {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// 未処理のレビューを処理済みにする
+func (s *MemVoteStore) resolveReviews(postId string, resolve func(vote *model.Vote)) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, vote := range s.tables.votes {
+		if vote.PostId == postId && isReviewType(vote.Type) && vote.InvalidateAt == 0 && vote.CompletedAt == 0 && vote.RejectedAt == 0 {
+			resolve(vote)
+		}
+	}
+}
+
+func (s *MemVoteStore) RejectReviewsForPost(postId string, rejectedBy string, revision int64) *model.AppError {
+	curTime := model.GetMillis()
+
+	s.resolveReviews(postId, func(vote *model.Vote) {
+		vote.RejectedAt = curTime
+		vote.RejectedBy = rejectedBy
+		vote.LastPostRev = int(revision)
+	})
+
+	return nil
+}
+
+func (s *MemVoteStore) CompleteReviewsForPost(postId string, completedBy string, revision int64) *model.AppError {
+	curTime := model.GetMillis()
+
+	s.resolveReviews(postId, func(vote *model.Vote) {
+		vote.CompletedAt = curTime
+		vote.CompletedBy = completedBy
+		vote.LastPostRev = int(revision)
+	})
+
+	return nil
+}
+
+func (s *MemVoteStore) GetReviews(options *model.SearchReviewsOptions, getCount bool) ([]*model.Vote, int64, *model.AppError) {
+	terms := options.Tagged
+	for _, c := range specialSearchChar {
+		terms = strings.Replace(terms, c, " ", -1)
+	}
+
+	fulltext := fulltextTerms{}
+	for _, t := range strings.Fields(terms) {
+		if len(t) >= model.TAG_MIN_RUNES {
+			fulltext.required = append(fulltext.required, t)
+		}
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	matched := []*model.Vote{}
+	for _, vote := range s.sortedVotes() {
+		if options.ReviewType != "" {
+			if vote.Type != options.ReviewType {
+				continue
+			}
+		} else if !isReviewType(vote.Type) {
+			continue
+		}
+
+		if !matchesNullableTeam(vote.TeamId, options.TeamId) {
+			continue
+		}
+		if options.FromDate != 0 && vote.CreateAt < options.FromDate {
+			continue
+		}
+		if options.ToDate != 0 && vote.CreateAt > options.ToDate {
+			continue
+		}
+		if !options.IncludeCompleted && vote.CompletedAt != 0 {
+			continue
+		}
+		if !options.IncludeRejected && vote.RejectedAt != 0 {
+			continue
+		}
+		if !options.IncludeInvalidated && vote.InvalidateAt != 0 {
+			continue
+		}
+		if options.PostId != "" && vote.PostId != options.PostId {
+			continue
+		}
+		if options.UserId != "" && vote.UserId != options.UserId {
+			continue
+		}
+		if options.Tagged != "" && !fulltext.match(vote.Tags) {
+			continue
+		}
+
+		matched = append(matched, vote)
+	}
+	sortVotesByCreateAtDesc(matched)
+
+	totalCount := int64(0)
+	if getCount {
+		totalCount = int64(len(matched))
+	}
+
+	var votes []*model.Vote
+	start, end := paginate(len(matched), options.Page*options.PerPage, options.PerPage)
+	for _, vote := range matched[start:end] {
+		votes = append(votes, clone(vote).(*model.Vote))
+	}
+
+	return votes, totalCount, nil
+}
+
+func (s *MemVoteStore) AnalyticsVoteCounts(teamId string, voteType string) (model.Analytics, *model.AppError) {
+	start, end := analyticsRange()
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	values := map[string]float64{}
+	for _, vote := range s.tables.votes {
+		if len(teamId) > 0 && vote.TeamId != teamId {
+			continue
+		}
+		if len(voteType) > 0 && vote.Type != voteType {
+			continue
+		}
+		if vote.CreateAt < start || vote.CreateAt > end {
+			continue
+		}
+		values[dateFromMillis(vote.CreateAt)]++
+	}
+
+	return dailyAnalytics(values), nil
+}
+
+// レビューやシステムの投票は移行対象外とし、ユーザーによる投票とフラグのみ返す
+func (s *MemVoteStore) GetVotesForExport(teamId string, offset, limit int) ([]*model.Vote, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	matched := []*model.Vote{}
+	for _, vote := range s.tables.votes {
+		if vote.TeamId != teamId {
+			continue
+		}
+		if vote.Type != model.VOTE_TYPE_UP_VOTE && vote.Type != model.VOTE_TYPE_DOWN_VOTE && vote.Type != model.VOTE_TYPE_FLAG {
+			continue
+		}
+		if post, ok := s.tables.posts[vote.PostId]; !ok || post.DeleteAt != 0 {
+			continue
+		}
+		matched = append(matched, vote)
+	}
+
+	sort.Slice(matched, func(a, b int) bool {
+		if matched[a].CreateAt != matched[b].CreateAt {
+			return matched[a].CreateAt < matched[b].CreateAt
+		}
+		if matched[a].PostId != matched[b].PostId {
+			return matched[a].PostId < matched[b].PostId
+		}
+		if matched[a].UserId != matched[b].UserId {
+			return matched[a].UserId < matched[b].UserId
+		}
+		return matched[a].Type < matched[b].Type
+	})
+
+	var votes []*model.Vote
+	start, end := paginate(len(matched), offset, limit)
+	for _, vote := range matched[start:end] {
+		votes = append(votes, clone(vote).(*model.Vote))
+	}
+
+	return votes, nil
+}
@@ -0,0 +1,113 @@
+package memstore
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/clear-ness/qa-discussion/model"
+)
+
+type MemWebhookDeliveryStore struct {
+	*MemStore
+}
+
+func (s *MemWebhookDeliveryStore) Save(delivery *model.WebhookDelivery) (*model.WebhookDelivery, *model.AppError) {
+	if len(delivery.Id) > 0 {
+		return nil, model.NewAppError("MemWebhookDeliveryStore.Save", "store.sql_webhook_delivery.save.existing.app_error", nil, "id="+delivery.Id, http.StatusBadRequest)
+	}
+
+	delivery.PreSave()
+	if err := delivery.IsValid(); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.tables.webhookDeliveries[delivery.Id] = clone(delivery).(*model.WebhookDelivery)
+
+	return delivery, nil
+}
+
+func (s *MemWebhookDeliveryStore) GetDue(now int64, limit int) ([]*model.WebhookDelivery, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	matched := []*model.WebhookDelivery{}
+	for _, delivery := range s.tables.webhookDeliveries {
+		if delivery.Status == model.WEBHOOK_DELIVERY_STATUS_PENDING && delivery.NextAttemptAt <= now {
+			matched = append(matched, delivery)
+		}
+	}
+
+	sort.Slice(matched, func(a, b int) bool {
+		if matched[a].NextAttemptAt == matched[b].NextAttemptAt {
+			return matched[a].Id < matched[b].Id
+		}
+		return matched[a].NextAttemptAt < matched[b].NextAttemptAt
+	})
+
+	var deliveries []*model.WebhookDelivery
+	_, end := paginate(len(matched), 0, limit)
+	for _, delivery := range matched[:end] {
+		deliveries = append(deliveries, clone(delivery).(*model.WebhookDelivery))
+	}
+
+	return deliveries, nil
+}
+
+func (s *MemWebhookDeliveryStore) Claim(delivery *model.WebhookDelivery, lockUntil int64) (bool, *model.AppError) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	current, ok := s.tables.webhookDeliveries[delivery.Id]
+	if !ok || current.Status != model.WEBHOOK_DELIVERY_STATUS_PENDING || current.NextAttemptAt != delivery.NextAttemptAt {
+		return false, nil
+	}
+
+	current.NextAttemptAt = lockUntil
+	delivery.NextAttemptAt = lockUntil
+	return true, nil
+}
+
+func (s *MemWebhookDeliveryStore) Update(delivery *model.WebhookDelivery) (*model.WebhookDelivery, *model.AppError) {
+	delivery.UpdateAt = model.GetMillis()
+	if err := delivery.IsValid(); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.tables.webhookDeliveries[delivery.Id]; ok {
+		s.tables.webhookDeliveries[delivery.Id] = clone(delivery).(*model.WebhookDelivery)
+	}
+
+	return delivery, nil
+}
+
+func (s *MemWebhookDeliveryStore) PermanentDeleteFinishedBefore(time int64, limit int) (int64, *model.AppError) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	finished := []*model.WebhookDelivery{}
+	for _, delivery := range s.tables.webhookDeliveries {
+		if delivery.Status != model.WEBHOOK_DELIVERY_STATUS_PENDING && delivery.UpdateAt < time {
+			finished = append(finished, delivery)
+		}
+	}
+
+	sort.Slice(finished, func(a, b int) bool {
+		if finished[a].UpdateAt == finished[b].UpdateAt {
+			return finished[a].Id < finished[b].Id
+		}
+		return finished[a].UpdateAt < finished[b].UpdateAt
+	})
+
+	_, end := paginate(len(finished), 0, limit)
+	for _, delivery := range finished[:end] {
+		delete(s.tables.webhookDeliveries, delivery.Id)
+	}
+
+	return int64(end), nil
+}
@@ -0,0 +1,135 @@
+package memstore
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/clear-ness/qa-discussion/model"
+)
+
+type MemWebhookStore struct {
+	*MemStore
+}
+
+func (s *MemWebhookStore) GetByTeam(teamId string, userId string, offset, limit int) ([]*model.Webhook, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	matched := []*model.Webhook{}
+	for _, webhook := range s.tables.webhooks {
+		if webhook.TeamId != teamId || webhook.DeleteAt != 0 {
+			continue
+		}
+		if len(userId) > 0 && webhook.UserId != userId {
+			continue
+		}
+		matched = append(matched, webhook)
+	}
+
+	sort.Slice(matched, func(a, b int) bool {
+		return matched[a].Id < matched[b].Id
+	})
+
+	if limit < 0 || offset < 0 {
+		offset, limit = 0, -1
+	}
+
+	var webhooks []*model.Webhook
+	start, end := paginate(len(matched), offset, limit)
+	for _, webhook := range matched[start:end] {
+		webhooks = append(webhooks, clone(webhook).(*model.Webhook))
+	}
+
+	return webhooks, nil
+}
+
+func (s *MemWebhookStore) Save(webhook *model.Webhook) (*model.Webhook, *model.AppError) {
+	if len(webhook.Id) > 0 {
+		return nil, model.NewAppError("MemWebhookStore.Save", "store.sql_webhooks.save.override.app_error", nil, "id="+webhook.Id, http.StatusBadRequest)
+	}
+
+	webhook.PreSave()
+	if err := webhook.IsValid(); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.tables.webhooks[webhook.Id] = clone(webhook).(*model.Webhook)
+
+	return webhook, nil
+}
+
+func (s *MemWebhookStore) Get(id string) (*model.Webhook, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	webhook, ok := s.tables.webhooks[id]
+	if !ok || webhook.DeleteAt != 0 {
+		return nil, model.NewAppError("MemWebhookStore.Get", "store.sql_webhooks.get.missing.app_error", nil, "id="+id, http.StatusNotFound)
+	}
+
+	return clone(webhook).(*model.Webhook), nil
+}
+
+func (s *MemWebhookStore) Update(hook *model.Webhook) (*model.Webhook, *model.AppError) {
+	hook.UpdateAt = model.GetMillis()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.tables.webhooks[hook.Id]; ok {
+		s.tables.webhooks[hook.Id] = clone(hook).(*model.Webhook)
+	}
+
+	return hook, nil
+}
+
+func (s *MemWebhookStore) Delete(webhookId string, time int64) *model.AppError {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if webhook, ok := s.tables.webhooks[webhookId]; ok {
+		webhook.DeleteAt = time
+		webhook.UpdateAt = time
+	}
+
+	return nil
+}
+
+func (s *MemWebhookStore) IncrementFailureCount(webhookId string) (int, *model.AppError) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	webhook, ok := s.tables.webhooks[webhookId]
+	if !ok {
+		return 0, model.NewAppError("MemWebhookStore.IncrementFailureCount", "store.sql_webhooks.increment_failure_count.app_error", nil, "id="+webhookId, http.StatusInternalServerError)
+	}
+	webhook.FailureCount++
+
+	return webhook.FailureCount, nil
+}
+
+func (s *MemWebhookStore) ResetFailureCount(webhookId string) *model.AppError {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if webhook, ok := s.tables.webhooks[webhookId]; ok {
+		webhook.FailureCount = 0
+	}
+
+	return nil
+}
+
+func (s *MemWebhookStore) Disable(webhookId string, time int64) *model.AppError {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if webhook, ok := s.tables.webhooks[webhookId]; ok && webhook.DisabledAt == 0 {
+		webhook.DisabledAt = time
+		webhook.UpdateAt = time
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package memstore
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/pkg/errors"
+)
+
+type MemWebhooksHistoryStore struct {
+	*MemStore
+}
+
+func (s *MemWebhooksHistoryStore) LogWebhookEvent(history *model.WebhooksHistory) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.tables.webhooksHistory[history.Id]; ok {
+		return errors.Errorf("LogWebhookEvent Id=%s: duplicate entry", history.Id)
+	}
+	s.tables.webhooksHistory[history.Id] = clone(history).(*model.WebhooksHistory)
+
+	return nil
+}
+
+func (s *MemWebhooksHistoryStore) Get(id string) (*model.WebhooksHistory, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	history, ok := s.tables.webhooksHistory[id]
+	if !ok {
+		return nil, model.NewAppError("MemWebhooksHistoryStore.Get", "store.sql_webhooks_history.get.missing.app_error", nil, "id="+id, http.StatusNotFound)
+	}
+
+	return clone(history).(*model.WebhooksHistory), nil
+}
+
+func (s *MemWebhooksHistoryStore) Search(options *model.SearchWebhooksHistoryOptions) ([]*model.WebhooksHistory, *model.AppError) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	matched := []*model.WebhooksHistory{}
+	for _, history := range s.tables.webhooksHistory {
+		if history.TeamId != options.TeamId {
+			continue
+		}
+		if options.WebhookId != "" && history.WebhookId != options.WebhookId {
+			continue
+		}
+		if options.PostId != "" && history.PostId != options.PostId {
+			continue
+		}
+		if options.MinStatus != 0 && history.ResponseStatus < options.MinStatus {
+			continue
+		}
+		if options.MaxStatus != 0 && history.ResponseStatus > options.MaxStatus {
+			continue
+		}
+		if options.FromDate != 0 && history.CreateAt < options.FromDate {
+			continue
+		}
+		if options.ToDate != 0 && history.CreateAt > options.ToDate {
+			continue
+		}
+		matched = append(matched, history)
+	}
+
+	keys := func(i int) []interface{} {
+		return []interface{}{matched[i].CreateAt, matched[i].Id}
+	}
+
+	var histories []*model.WebhooksHistory
+	for _, i := range keysetPage(len(matched), keys, true, options.Cursor, true, options.Page*options.PerPage, options.PerPage) {
+		histories = append(histories, clone(matched[i]).(*model.WebhooksHistory))
+	}
+
+	return histories, nil
+}
+
+// sqlと同じくlimit件ずつ削除する。古いものから消す
+func (s *MemWebhooksHistoryStore) PermanentDeleteBefore(time int64, limit int) (int64, *model.AppError) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	expired := []*model.WebhooksHistory{}
+	for _, history := range s.tables.webhooksHistory {
+		if history.CreateAt < time {
+			expired = append(expired, history)
+		}
+	}
+
+	sort.Slice(expired, func(a, b int) bool {
+		if expired[a].CreateAt == expired[b].CreateAt {
+			return expired[a].Id < expired[b].Id
+		}
+		return expired[a].CreateAt < expired[b].CreateAt
+	})
+
+	_, end := paginate(len(expired), 0, limit)
+	for _, history := range expired[:end] {
+		delete(s.tables.webhooksHistory, history.Id)
+	}
+
+	return int64(end), nil
+}
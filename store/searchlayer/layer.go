@@ -1,7 +1,11 @@
 package searchlayer
 
 import (
+	"context"
+	"sync"
+
 	"github.com/clear-ness/qa-discussion/model"
+	"github.com/clear-ness/qa-discussion/services/metrics"
 	"github.com/clear-ness/qa-discussion/services/search"
 	"github.com/clear-ness/qa-discussion/store"
 )
@@ -13,13 +17,29 @@ type SearchStore struct {
 	userPointHistory *SearchUserPointHistoryStore
 	postViewsHistory *SearchPostViewsHistoryStore
 	config           *model.Config
-	esBackend        *search.ESBackend
+	metrics          metrics.MetricsInterface
+
+	// 設定の変更で差し替えるので、backend()で取り出す
+	esBackend     *search.ESBackend
+	esBackendLock sync.RWMutex
 }
 
-func NewSearchLayer(baseStore store.Store, cfg *model.Config) *SearchStore {
+func NewSearchLayer(baseStore store.Store, metrics metrics.MetricsInterface, cfg *model.Config) *SearchStore {
+	setting := *cfg
+	esBackend, err := search.NewESBackend(&setting.SearchSettings, metrics)
+	if err != nil {
+		return nil
+	}
+
+	return newSearchLayer(baseStore, cfg, metrics, esBackend)
+}
+
+func newSearchLayer(baseStore store.Store, cfg *model.Config, metrics metrics.MetricsInterface, esBackend *search.ESBackend) *SearchStore {
 	searchStore := &SearchStore{
-		Store:  baseStore,
-		config: cfg,
+		Store:     baseStore,
+		config:    cfg,
+		metrics:   metrics,
+		esBackend: esBackend,
 	}
 
 	searchStore.post = &SearchPostStore{
@@ -42,14 +62,33 @@ func NewSearchLayer(baseStore store.Store, cfg *model.Config) *SearchStore {
 		rootStore:             searchStore,
 	}
 
-	setting := *cfg
-	esBackend, err := search.NewESBackend(&setting.SearchSettings)
+	return searchStore
+}
+
+// ESのclientは使い回す
+func (s *SearchStore) WithContext(ctx context.Context) store.Store {
+	return newSearchLayer(s.Store.WithContext(ctx), s.config, s.metrics, s.backend().WithContext(ctx))
+}
+
+func (s *SearchStore) backend() *search.ESBackend {
+	s.esBackendLock.RLock()
+	defer s.esBackendLock.RUnlock()
+
+	return s.esBackend
+}
+
+// SearchSettingsが変わった場合に、新しい接続先のclientに差し替える
+func (s *SearchStore) UpdateConfig(cfg *model.Config) error {
+	esBackend, err := search.NewESBackend(&cfg.SearchSettings, s.metrics)
 	if err != nil {
-		return nil
+		return *err
 	}
-	searchStore.esBackend = esBackend
 
-	return searchStore
+	s.esBackendLock.Lock()
+	s.esBackend = esBackend
+	s.esBackendLock.Unlock()
+
+	return nil
 }
 
 func (s *SearchStore) Post() store.PostStore {
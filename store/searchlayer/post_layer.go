@@ -22,7 +22,7 @@ type SearchPostStore struct {
 func (s SearchPostStore) IndexPost(post *model.Post) {
 	esPost := search.ESPostFromPost(post)
 	go func() {
-		s.rootStore.esBackend.IndexESPost(esPost)
+		s.rootStore.backend().IndexESPost(esPost)
 	}()
 }
 
@@ -74,7 +74,7 @@ func (s *SearchPostStore) Update(newPost *model.Post, oldPost *model.Post) (*mod
 func (s *SearchPostStore) DeletePost(post *model.Post) {
 	esPost := search.ESPostFromPost(post)
 	go func() {
-		s.rootStore.esBackend.DeleteESPost(esPost)
+		s.rootStore.backend().DeleteESPost(esPost)
 	}()
 }
 
@@ -106,6 +106,24 @@ func (s *SearchPostStore) DeleteAnswer(postId string, time int64, deleteById str
 	return err
 }
 
+// 削除でインデックスから消した質問と回答を戻す
+func (s *SearchPostStore) RestorePost(postId string, time int64) *model.AppError {
+	if err := s.PostStore.RestorePost(postId, time); err != nil {
+		return err
+	}
+
+	post, err := s.PostStore.GetSingle(postId, false)
+	if err != nil {
+		return err
+	}
+
+	if post.Type == model.POST_TYPE_QUESTION || post.Type == model.POST_TYPE_ANSWER {
+		s.IndexPost(post)
+	}
+
+	return nil
+}
+
 func (s *SearchPostStore) UpVotePost(postId string, userId string) (*model.Vote, *model.AppError) {
 	vote, err := s.PostStore.UpVotePost(postId, userId)
 	if err == nil {
@@ -161,7 +179,7 @@ func (s *SearchPostStore) CancelFlagPost(postId string, userId string) (*model.V
 }
 
 func (s *SearchPostStore) RelatedSearch(term string, limit int) ([]*model.RelatedPostSearchResult, *model.AppError) {
-	results, err := s.rootStore.esBackend.RelatedESPosts(term, limit)
+	results, err := s.rootStore.backend().RelatedESPosts(term, limit)
 
 	if err != nil {
 		return nil, model.NewAppError("SearchPostStore.RelatedSearch", "search_post.related_search.search.app_error", nil, err.Error(), http.StatusInternalServerError)
@@ -197,7 +215,7 @@ func (s *SearchPostStore) HotSearch(interval string, teamId string, limit int) (
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		results, err := s.rootStore.esBackend.HotESPostViewsHistory(interval, teamId, limit)
+		results, err := s.rootStore.backend().HotESPostViewsHistory(interval, teamId, limit)
 
 		if err != nil {
 			appErr := model.NewAppError("SearchPostStore.HotSearch", "store.search_post.hot_search.views.app_error", nil, err.Error(), http.StatusInternalServerError)
@@ -211,7 +229,7 @@ func (s *SearchPostStore) HotSearch(interval string, teamId string, limit int) (
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		results, err := s.rootStore.esBackend.HotESVotes(interval, teamId, limit)
+		results, err := s.rootStore.backend().HotESVotes(interval, teamId, limit)
 
 		if err != nil {
 			appErr := model.NewAppError("SearchPostStore.HotSearch", "store.search_post.hot_search.votes.app_error", nil, err.Error(), http.StatusInternalServerError)
@@ -226,7 +244,7 @@ func (s *SearchPostStore) HotSearch(interval string, teamId string, limit int) (
 	go func() {
 		defer wg.Done()
 
-		results, err := s.rootStore.esBackend.HotESAnswerPosts(interval, teamId, limit)
+		results, err := s.rootStore.backend().HotESAnswerPosts(interval, teamId, limit)
 		if err != nil {
 			appErr := model.NewAppError("SearchPostStore.HotSearch", "store.search_post.hot_search.answers.app_error", nil, err.Error(), http.StatusInternalServerError)
 			pchan <- store.StoreResult{Data: results, Err: appErr}
@@ -306,5 +324,5 @@ func (s *SearchPostStore) SetupIndex() {
       }
     }
 		}`
-	s.rootStore.esBackend.CreateIndex(postMapping, search.INDEX_NAME_POSTS)
+	s.rootStore.backend().CreateIndex(postMapping, search.INDEX_NAME_POSTS)
 }
@@ -14,7 +14,7 @@ type SearchPostViewsHistoryStore struct {
 func (s SearchPostViewsHistoryStore) IndexPostViewsHistory(history *model.PostViewsHistory) {
 	esHistory := search.ESPostViewsHistoryFromObj(history)
 	go func() {
-		s.rootStore.esBackend.IndexESPostViewsHistory(esHistory)
+		s.rootStore.backend().IndexESPostViewsHistory(esHistory)
 	}()
 }
 
@@ -34,5 +34,5 @@ func (s *SearchPostViewsHistoryStore) SetupIndex() {
       }
     }
 		}`
-	s.rootStore.esBackend.CreateIndex(historyMapping, search.INDEX_NAME_POST_VIEWS_HISTORY)
+	s.rootStore.backend().CreateIndex(historyMapping, search.INDEX_NAME_POST_VIEWS_HISTORY)
 }
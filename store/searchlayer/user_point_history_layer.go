@@ -16,12 +16,12 @@ type SearchUserPointHistoryStore struct {
 func (s SearchUserPointHistoryStore) IndexUserPointHistory(history *model.UserPointHistory) {
 	esHistory := search.ESUserPointHistoryFromObj(history)
 	go func() {
-		s.rootStore.esBackend.IndexESUserPointHistory(esHistory)
+		s.rootStore.backend().IndexESUserPointHistory(esHistory)
 	}()
 }
 
 func (s *SearchUserPointHistoryStore) TopAskersByTag(interval string, teamId string, tag string, limit int) ([]*model.TopUserByTagResult, *model.AppError) {
-	results, err := s.rootStore.esBackend.TopUsersByTag(interval, teamId, model.POST_TYPE_QUESTION, tag, limit)
+	results, err := s.rootStore.backend().TopUsersByTag(interval, teamId, model.POST_TYPE_QUESTION, tag, limit)
 	if err != nil {
 		return nil, model.NewAppError("SearchUserPointHistoryStore.TopAskersByTag", "search_user_point_history.top_askers_by_tag.search.app_error", nil, err.Error(), http.StatusInternalServerError)
 	}
@@ -44,7 +44,7 @@ func (s *SearchUserPointHistoryStore) TopAskersByTag(interval string, teamId str
 }
 
 func (s *SearchUserPointHistoryStore) TopAnswerersByTag(interval string, teamId string, tag string, limit int) ([]*model.TopUserByTagResult, *model.AppError) {
-	results, err := s.rootStore.esBackend.TopUsersByTag(interval, teamId, model.POST_TYPE_ANSWER, tag, limit)
+	results, err := s.rootStore.backend().TopUsersByTag(interval, teamId, model.POST_TYPE_ANSWER, tag, limit)
 	if err != nil {
 		return nil, model.NewAppError("SearchUserPointHistoryStore.TopAnswerersByTag", "search_user_point_history.top_answerers_by_tag.search.app_error", nil, err.Error(), http.StatusInternalServerError)
 	}
@@ -67,7 +67,7 @@ func (s *SearchUserPointHistoryStore) TopAnswerersByTag(interval string, teamId
 }
 
 func (s *SearchUserPointHistoryStore) TopAnswersByTag(interval string, teamId string, tag string, limit int) ([]*model.TopPostByTagResult, *model.AppError) {
-	results, err := s.rootStore.esBackend.TopPostsByTag(interval, teamId, model.POST_TYPE_ANSWER, tag, limit)
+	results, err := s.rootStore.backend().TopPostsByTag(interval, teamId, model.POST_TYPE_ANSWER, tag, limit)
 	if err != nil {
 		return nil, model.NewAppError("SearchUserPointHistoryStore.TopAnswersByTag", "search_user_point_history.top_answers_by_tag.search.app_error", nil, err.Error(), http.StatusInternalServerError)
 	}
@@ -107,5 +107,5 @@ func (s *SearchUserPointHistoryStore) SetupIndex() {
       }
     }
 		}`
-	s.rootStore.esBackend.CreateIndex(historyMapping, search.INDEX_NAME_USER_POINT_HISTORY)
+	s.rootStore.backend().CreateIndex(historyMapping, search.INDEX_NAME_USER_POINT_HISTORY)
 }
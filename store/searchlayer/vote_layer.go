@@ -14,14 +14,14 @@ type SearchVoteStore struct {
 func (s SearchVoteStore) IndexVote(vote *model.Vote) {
 	esVote := search.ESVoteFromVote(vote)
 	go func() {
-		s.rootStore.esBackend.IndexESVote(esVote)
+		s.rootStore.backend().IndexESVote(esVote)
 	}()
 }
 
 func (s *SearchVoteStore) DeleteVote(vote *model.Vote) {
 	esVote := search.ESVoteFromVote(vote)
 	go func() {
-		s.rootStore.esBackend.DeleteESVote(esVote)
+		s.rootStore.backend().DeleteESVote(esVote)
 	}()
 }
 
@@ -47,5 +47,5 @@ func (s *SearchVoteStore) SetupIndex() {
       }
     }
 		}`
-	s.rootStore.esBackend.CreateIndex(voteMapping, search.INDEX_NAME_VOTES)
+	s.rootStore.backend().CreateIndex(voteMapping, search.INDEX_NAME_VOTES)
 }
@@ -26,7 +26,10 @@ func NewSqlAuditStore(sqlStore store.Store) store.AuditStore {
 
 func (s SqlAuditStore) Save(audit *model.Audit) *model.AppError {
 	audit.Id = model.NewId()
-	audit.CreateAt = model.GetMillis()
+	// キュー経由で遅れて保存される場合があるので、記録された時刻を優先する
+	if audit.CreateAt == 0 {
+		audit.CreateAt = model.GetMillis()
+	}
 
 	if err := s.GetMaster().Insert(audit); err != nil {
 		return model.NewAppError("SqlAuditStore.Save", "store.sql_audit.save.saving.app_error", nil, "user_id="+audit.UserId+" action="+audit.Action, http.StatusInternalServerError)
@@ -63,6 +66,47 @@ func (s SqlAuditStore) Get(user_id string, offset int, limit int) (model.Audits,
 	return audits, nil
 }
 
+func (s SqlAuditStore) Search(options *model.SearchAuditsOptions) (model.Audits, *model.AppError) {
+	query := s.GetQueryBuilder().
+		Select("*").
+		From("Audits")
+
+	if options.UserId != "" {
+		query = query.Where(sq.Eq{"UserId": options.UserId})
+	}
+	if options.Action != "" {
+		query = query.Where(sq.Eq{"Action": options.Action})
+	}
+	if options.Status != "" {
+		query = query.Where(sq.Eq{"Status": options.Status})
+	}
+	if options.IpAddress != "" {
+		query = query.Where(sq.Eq{"IpAddress": options.IpAddress})
+	}
+	if options.FromDate != 0 {
+		query = query.Where(sq.GtOrEq{"CreateAt": options.FromDate})
+	}
+	if options.ToDate != 0 {
+		query = query.Where(sq.LtOrEq{"CreateAt": options.ToDate})
+	}
+
+	query = query.OrderBy("CreateAt DESC").
+		Limit(uint64(options.PerPage)).
+		Offset(uint64(options.Page * options.PerPage))
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return nil, model.NewAppError("SqlAuditStore.Search", "store.sql_audit.search.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	var audits model.Audits
+	if _, err := s.GetReplica().Select(&audits, queryString, args...); err != nil {
+		return nil, model.NewAppError("SqlAuditStore.Search", "store.sql_audit.search.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	return audits, nil
+}
+
 func (s SqlAuditStore) PermanentDeleteByUser(userId string) *model.AppError {
 	if _, err := s.GetMaster().Exec("DELETE FROM Audits WHERE UserId = :userId",
 		map[string]interface{}{"userId": userId}); err != nil {
@@ -0,0 +1,81 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/clear-ness/qa-discussion/store"
+)
+
+type SqlBotStore struct {
+	store.Store
+}
+
+func NewSqlBotStore(sqlStore store.Store) store.BotStore {
+	s := &SqlBotStore{
+		Store: sqlStore,
+	}
+
+	for _, db := range sqlStore.GetAllConns() {
+		db.AddTableWithName(model.Bot{}, "Bots").SetKeys(false, "UserId")
+	}
+
+	return s
+}
+
+func (s SqlBotStore) Save(bot *model.Bot) (*model.Bot, *model.AppError) {
+	bot.PreSave()
+	if err := bot.IsValid(); err != nil {
+		return nil, err
+	}
+
+	if err := s.GetMaster().Insert(bot); err != nil {
+		return nil, model.NewAppError("SqlBotStore.Save", "store.sql_bot.save.app_error", nil, "user_id="+bot.UserId+", "+err.Error(), http.StatusInternalServerError)
+	}
+
+	return bot, nil
+}
+
+func (s SqlBotStore) Get(userId string) (*model.Bot, *model.AppError) {
+	var bot model.Bot
+	if err := s.GetReplica().SelectOne(&bot, "SELECT * FROM Bots WHERE UserId = :UserId AND DeleteAt = 0", map[string]interface{}{"UserId": userId}); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.NewAppError("SqlBotStore.Get", "store.sql_bot.get.missing.app_error", nil, "user_id="+userId, http.StatusNotFound)
+		}
+		return nil, model.NewAppError("SqlBotStore.Get", "store.sql_bot.get.app_error", nil, "user_id="+userId+", err="+err.Error(), http.StatusInternalServerError)
+	}
+
+	return &bot, nil
+}
+
+func (s SqlBotStore) GetByTeam(teamId string, offset, limit int) ([]*model.Bot, *model.AppError) {
+	var bots []*model.Bot
+	if _, err := s.GetReplica().Select(&bots, `
+		SELECT
+			*
+		FROM
+			Bots
+		WHERE
+			TeamId = :TeamId
+			AND DeleteAt = 0
+		ORDER BY CreateAt DESC
+		LIMIT :Limit OFFSET :Offset`, map[string]interface{}{"TeamId": teamId, "Limit": limit, "Offset": offset}); err != nil {
+		return nil, model.NewAppError("SqlBotStore.GetByTeam", "store.sql_bot.get_by_team.app_error", nil, "teamId="+teamId+", err="+err.Error(), http.StatusInternalServerError)
+	}
+
+	return bots, nil
+}
+
+func (s SqlBotStore) Update(bot *model.Bot) (*model.Bot, *model.AppError) {
+	bot.PreUpdate()
+	if err := bot.IsValid(); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.GetMaster().Update(bot); err != nil {
+		return nil, model.NewAppError("SqlBotStore.Update", "store.sql_bot.update.app_error", nil, "user_id="+bot.UserId+", "+err.Error(), http.StatusInternalServerError)
+	}
+
+	return bot, nil
+}
@@ -3,12 +3,14 @@ package sqlstore
 import (
 	"database/sql"
 	"net/http"
+	"strings"
 
 	"github.com/pkg/errors"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/clear-ness/qa-discussion/model"
 	"github.com/clear-ness/qa-discussion/store"
+	"github.com/clear-ness/qa-discussion/utils"
 )
 
 type SqlCollectionStore struct {
@@ -82,7 +84,7 @@ func (s SqlCollectionStore) Save(collection *model.Collection, maxCollectionsPer
 	}
 
 	if err := s.GetMaster().Insert(collection); err != nil {
-		if IsUniqueConstraintError(err, []string{"PRIMARY"}) {
+		if IsUniqueConstraintError(err, []string{"PRIMARY", "collections_pkey"}) {
 			return nil, model.NewAppError("SqlCollectionStore.Save", "store.sql_collection.save.exists.app_error", nil, err.Error(), http.StatusBadRequest)
 		}
 
@@ -176,17 +178,30 @@ func (s SqlCollectionStore) saveMultiplePosts(colPosts []*model.CollectionPost)
 	return colPosts, nil
 }
 
-func (s SqlCollectionStore) GetCollectionsForTeam(teamId string, offset int, limit int, title string) (*model.CollectionList, *model.AppError) {
+var collectionsKeyset = keyset{columns: []string{"Collections.CreateAt", "Collections.Id"}, desc: true}
+
+func (s SqlCollectionStore) GetCollectionsForTeam(teamId string, offset int, limit int, title string, cursor *model.Cursor) (*model.CollectionList, *model.AppError) {
 	args := map[string]interface{}{
 		"TeamId": teamId,
 		"Limit":  limit,
 		"Offset": offset,
 	}
 
-	fulltextClause := ""
+	titleClause := ""
 	if title != "" {
-		fulltextClause = "AND MATCH(Title) AGAINST (:Title IN BOOLEAN MODE)"
+		titleClause = "AND " + fulltextClause(s.DriverName(), []string{"Title"}, ":Title")
 		args["Title"] = title
+		if s.DriverName() == model.DATABASE_DRIVER_POSTGRES {
+			args["Title"] = fulltextTerms{optional: strings.Fields(title)}.query(s.DriverName())
+		}
+	}
+
+	cursorClause := ""
+	before := false
+	if cursor != nil {
+		cursorClause = "AND " + collectionsKeyset.namedWhere(cursorValues(cursor, true), cursor.Before, args)
+		before = cursor.Before
+		args["Offset"] = 0
 	}
 
 	cols := &model.CollectionList{}
@@ -198,8 +213,9 @@ func (s SqlCollectionStore) GetCollectionsForTeam(teamId string, offset int, lim
 		WHERE
 			Collections.TeamId = :TeamId
 			AND Collections.DeleteAt = 0
-			`+fulltextClause+`
-		ORDER BY Collections.CreateAt DESC
+			`+titleClause+`
+			`+cursorClause+`
+		ORDER BY `+collectionsKeyset.orderBy(before)+`
 		LIMIT :Limit
 		OFFSET :Offset
 		`, args)
@@ -207,6 +223,10 @@ func (s SqlCollectionStore) GetCollectionsForTeam(teamId string, offset int, lim
 		return nil, model.NewAppError("SqlCollectionStore.GetCollectionsForTeam", "store.sql_collection.get_collections_for_team.app_error", nil, "teamId="+teamId+", err="+err.Error(), http.StatusInternalServerError)
 	}
 
+	if before {
+		utils.ReverseSlice(*cols)
+	}
+
 	return cols, nil
 }
 
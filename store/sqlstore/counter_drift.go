@@ -0,0 +1,49 @@
+package sqlstore
+
+import (
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/go-gorp/gorp"
+)
+
+// 投票者やポイントの履歴が無いまま取り込んだデータを、PropsのキーのLIKEで見分ける
+func importedPropsCondition(column string, importedPropKey string, params map[string]interface{}) string {
+	if importedPropKey == "" {
+		return "1 = 0"
+	}
+
+	params["ImportedPattern"] = "%" + sanitizeSearchTerm(`"`+importedPropKey+`":`, "*") + "%"
+	return "COALESCE(" + column + ", '') LIKE :ImportedPattern escape '*'"
+}
+
+// 照合した後に値が変わっていれば、その間の更新を優先して修正しない
+func updateCounterIfUnchanged(db *gorp.DbMap, table string, where string, params map[string]interface{}, drift *model.CounterDrift) (bool, error) {
+	params["Expected"] = drift.Expected
+	params["Actual"] = drift.Actual
+
+	result, err := db.Exec("UPDATE "+table+" SET "+drift.Column+" = :Expected WHERE "+where+" AND "+drift.Column+" = :Actual", params)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rows == 1, nil
+}
+
+func appendCounterDrift(drifts []*model.CounterDrift, table, teamId, key, column string, actual, expected int64) []*model.CounterDrift {
+	if actual == expected {
+		return drifts
+	}
+
+	return append(drifts, &model.CounterDrift{
+		Table:    table,
+		TeamId:   teamId,
+		Key:      key,
+		Column:   column,
+		Actual:   actual,
+		Expected: expected,
+	})
+}
@@ -0,0 +1,80 @@
+package sqlstore
+
+import (
+	"fmt"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/clear-ness/qa-discussion/model"
+)
+
+// 複数の列の組で並べ、カーソルの位置から続きを取得するための条件を作る。
+// 最後の列は一意なIdにして、同じキーの行が前後のページで重複したり抜けたりしないようにする。
+type keyset struct {
+	columns []string
+	desc    bool
+}
+
+// Beforeの場合は逆順に取得して呼び出し側で並べ直す
+func (k keyset) descending(before bool) bool {
+	return k.desc != before
+}
+
+func (k keyset) orderBy(before bool) string {
+	direction := " ASC"
+	if k.descending(before) {
+		direction = " DESC"
+	}
+
+	terms := make([]string, len(k.columns))
+	for i, column := range k.columns {
+		terms[i] = column + direction
+	}
+
+	return strings.Join(terms, ", ")
+}
+
+// 行値の比較はmysqlとpostgresのどちらでも使える
+func (k keyset) comparison(placeholders []string, before bool) string {
+	op := " > "
+	if k.descending(before) {
+		op = " < "
+	}
+
+	return "(" + strings.Join(k.columns, ", ") + ")" + op + "(" + strings.Join(placeholders, ", ") + ")"
+}
+
+// カーソルのキーをkeysetの列と同じ順に並べる。数値の並びのキーを使わない一覧はwithKeyをfalseにする
+func cursorValues(cursor *model.Cursor, withKey bool) []interface{} {
+	var values []interface{}
+	if withKey {
+		values = append(values, cursor.Key)
+	}
+	for _, id := range cursor.Ids {
+		values = append(values, id)
+	}
+
+	return values
+}
+
+// squirrelのクエリ用。valuesはcolumnsと同じ順
+func (k keyset) where(values []interface{}, before bool) sq.Sqlizer {
+	placeholders := make([]string, len(values))
+	for i := range values {
+		placeholders[i] = "?"
+	}
+
+	return sq.Expr(k.comparison(placeholders, before), values...)
+}
+
+// 名前付きパラメータのクエリ用。argsに値を追加する
+func (k keyset) namedWhere(values []interface{}, before bool, args map[string]interface{}) string {
+	placeholders := make([]string, len(values))
+	for i, value := range values {
+		name := fmt.Sprintf("Cursor%d", i)
+		placeholders[i] = ":" + name
+		args[name] = value
+	}
+
+	return k.comparison(placeholders, before)
+}
@@ -0,0 +1,143 @@
+package sqlstore
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/clear-ness/qa-discussion/model"
+)
+
+// mysqlとpostgresで文法が異なる部分をここにまとめる
+
+// 既に存在するタグはPostCountを加算する
+func tagsUpsertClause(driverName string) string {
+	if driverName == model.DATABASE_DRIVER_POSTGRES {
+		return " ON CONFLICT (Content, TeamId, Type) DO UPDATE SET PostCount = Tags.PostCount + 1, UpdateAt = EXCLUDED.UpdateAt"
+	}
+
+	return " ON DUPLICATE KEY UPDATE PostCount = PostCount + 1, UpdateAt = VALUES(UpdateAt)"
+}
+
+// インポートしたタグが既に存在する場合は何もしない
+func tagsInsertIgnoreClause(driverName string) string {
+	if driverName == model.DATABASE_DRIVER_POSTGRES {
+		return " ON CONFLICT (Content, TeamId, Type) DO NOTHING"
+	}
+
+	return " ON DUPLICATE KEY UPDATE Content = Content"
+}
+
+func systemsUpsertClause(driverName string) string {
+	if driverName == model.DATABASE_DRIVER_POSTGRES {
+		return " ON CONFLICT (Name) DO UPDATE SET Value = EXCLUDED.Value"
+	}
+
+	return " ON DUPLICATE KEY UPDATE Value = VALUES(Value)"
+}
+
+// postgresではmigrationのGINインデックスと同じ式にしないとインデックスが使われない
+func tsvectorExpr(columns []string) string {
+	cols := make([]string, len(columns))
+	for i, column := range columns {
+		cols[i] = fmt.Sprintf("COALESCE(%s, '')", column)
+	}
+
+	return fmt.Sprintf("to_tsvector('simple', %s)", strings.Join(cols, " || ' ' || "))
+}
+
+// mysqlはFULLTEXTインデックス、postgresはtsvectorで全文検索する
+func fulltextClause(driverName string, columns []string, placeholder string) string {
+	if driverName == model.DATABASE_DRIVER_POSTGRES {
+		return fmt.Sprintf("%s @@ to_tsquery('simple', %s)", tsvectorExpr(columns), placeholder)
+	}
+
+	return fmt.Sprintf("MATCH(%s) AGAINST (%s IN BOOLEAN MODE)", strings.Join(columns, ", "), placeholder)
+}
+
+type fulltextTerms struct {
+	// 全て含む
+	required []string
+	// いずれかを含む
+	optional []string
+	// いずれも含まない
+	excluded []string
+	// optionalの各語をフレーズとして扱う
+	phrase bool
+}
+
+// fulltextClauseのplaceholderに渡す検索語
+func (t fulltextTerms) query(driverName string) string {
+	if driverName == model.DATABASE_DRIVER_POSTGRES {
+		return t.tsquery()
+	}
+
+	terms := []string{}
+	for _, term := range t.required {
+		terms = append(terms, "+"+term)
+	}
+	for _, term := range t.optional {
+		if t.phrase {
+			term = "\"" + term + "\""
+		}
+		terms = append(terms, term)
+	}
+
+	query := strings.Join(terms, " ")
+	if len(t.excluded) > 0 {
+		query += " -(" + strings.Join(t.excluded, " ") + ")"
+	}
+
+	return query
+}
+
+func (t fulltextTerms) tsquery() string {
+	parts := []string{}
+	for _, term := range t.required {
+		parts = append(parts, quoteTsqueryTerm(term))
+	}
+
+	if len(t.optional) > 0 {
+		optional := make([]string, len(t.optional))
+		for i, term := range t.optional {
+			optional[i] = quoteTsqueryTerm(term)
+		}
+		parts = append(parts, "("+strings.Join(optional, " | ")+")")
+	}
+
+	// mysqlのboolean modeと同様に、除外する語だけでは何もヒットさせない
+	if len(parts) == 0 {
+		return ""
+	}
+
+	for _, term := range t.excluded {
+		parts = append(parts, "!"+quoteTsqueryTerm(term))
+	}
+
+	return strings.Join(parts, " & ")
+}
+
+// クォートした語は記号を含んでいても、to_tsqueryが分割してフレーズとして扱う
+func quoteTsqueryTerm(term string) string {
+	term = strings.Replace(term, "\\", "\\\\", -1)
+	term = strings.Replace(term, "'", "''", -1)
+
+	return "'" + term + "'"
+}
+
+// ミリ秒のカラムを日付(YYYY-MM-DD)にする
+func dateFromMillisExpr(driverName string, column string) string {
+	if driverName == model.DATABASE_DRIVER_POSTGRES {
+		return fmt.Sprintf("TO_CHAR(TO_TIMESTAMP(%s / 1000), 'YYYY-MM-DD')", column)
+	}
+
+	return fmt.Sprintf("DATE(FROM_UNIXTIME(%s / 1000))", column)
+}
+
+// postgresはDELETEにLIMITを付けられないため、サブクエリで対象のIdを絞る
+func deleteWithLimitQuery(driverName string, table string, where string) string {
+	if driverName == model.DATABASE_DRIVER_POSTGRES {
+		return fmt.Sprintf("DELETE FROM %s WHERE Id IN (SELECT Id FROM %s WHERE %s LIMIT :Limit)", table, table, where)
+	}
+
+	return fmt.Sprintf("DELETE FROM %s WHERE %s LIMIT :Limit", table, where)
+}
@@ -105,3 +105,27 @@ func (s SqlFileInfoStore) DeleteForPost(postId string) (string, *model.AppError)
 
 	return postId, nil
 }
+
+func (s SqlFileInfoStore) GetForPosts(postIds []string) ([]*model.FileInfo, *model.AppError) {
+	if len(postIds) == 0 {
+		return []*model.FileInfo{}, nil
+	}
+
+	keys, params := MapStringsToQueryParams(postIds, "Post")
+
+	var infos []*model.FileInfo
+	if _, err := s.GetReplica().Select(&infos, `
+		SELECT
+			*
+		FROM
+			FileInfo
+		WHERE
+			PostId IN `+keys+`
+			AND DeleteAt = 0
+		ORDER BY
+			CreateAt ASC`, params); err != nil {
+		return nil, model.NewAppError("SqlFileInfoStore.GetForPosts", "store.sql_file_info.get_for_posts.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	return infos, nil
+}
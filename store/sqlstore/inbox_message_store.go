@@ -7,6 +7,7 @@ import (
 	sq "github.com/Masterminds/squirrel"
 	"github.com/clear-ness/qa-discussion/model"
 	"github.com/clear-ness/qa-discussion/store"
+	"github.com/clear-ness/qa-discussion/utils"
 )
 
 type SqlInboxMessageStore struct {
@@ -55,7 +56,9 @@ func (s SqlInboxMessageStore) GetSingle(id string) (*model.InboxMessage, *model.
 	return message, nil
 }
 
-func (s SqlInboxMessageStore) GetInboxMessages(time int64, userId string, direction string, page, perPage int, teamId string) ([]*model.InboxMessage, *model.AppError) {
+var inboxMessagesKeyset = keyset{columns: []string{"CreateAt", "Id"}, desc: true}
+
+func (s SqlInboxMessageStore) GetInboxMessages(time int64, userId string, direction string, page, perPage int, teamId string, cursor *model.Cursor) ([]*model.InboxMessage, *model.AppError) {
 	offset := page * perPage
 
 	if direction != ">" && direction != "<=" {
@@ -74,7 +77,14 @@ func (s SqlInboxMessageStore) GetInboxMessages(time int64, userId string, direct
 		sq.Expr(`TeamId = ?`, teamId),
 	})
 
-	query = query.OrderBy("CreateAt DESC").
+	before := false
+	if cursor != nil {
+		query = query.Where(inboxMessagesKeyset.where(cursorValues(cursor, true), cursor.Before))
+		before = cursor.Before
+		offset = 0
+	}
+
+	query = query.OrderBy(inboxMessagesKeyset.orderBy(before)).
 		Limit(uint64(perPage)).
 		Offset(uint64(offset))
 
@@ -89,6 +99,10 @@ func (s SqlInboxMessageStore) GetInboxMessages(time int64, userId string, direct
 		return nil, model.NewAppError("SqlInboxMessageStore.GetInboxMessages", "store.sql_inbox_message.get_inbox_messages.get.app_error", nil, "", http.StatusInternalServerError)
 	}
 
+	if before {
+		utils.ReverseSlice(messages)
+	}
+
 	lastMessageViewed, err := s.GetReplica().SelectInt(`
 		SELECT
 			LastInboxMessageViewed
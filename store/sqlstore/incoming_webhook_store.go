@@ -0,0 +1,115 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/clear-ness/qa-discussion/store"
+)
+
+type SqlIncomingWebhookStore struct {
+	store.Store
+}
+
+func NewSqlIncomingWebhookStore(sqlStore store.Store) store.IncomingWebhookStore {
+	s := &SqlIncomingWebhookStore{
+		Store: sqlStore,
+	}
+
+	for _, db := range sqlStore.GetAllConns() {
+		db.AddTableWithName(model.IncomingWebhook{}, "IncomingWebhooks").SetKeys(false, "Id")
+	}
+
+	return s
+}
+
+func (s SqlIncomingWebhookStore) Save(hook *model.IncomingWebhook) (*model.IncomingWebhook, *model.AppError) {
+	if len(hook.Id) > 0 {
+		return nil, model.NewAppError("SqlIncomingWebhookStore.Save", "store.sql_incoming_webhooks.save.existing.app_error", nil, "id="+hook.Id, http.StatusBadRequest)
+	}
+
+	hook.PreSave()
+	if err := hook.IsValid(); err != nil {
+		return nil, err
+	}
+
+	if err := s.GetMaster().Insert(hook); err != nil {
+		return nil, model.NewAppError("SqlIncomingWebhookStore.Save", "store.sql_incoming_webhooks.save.app_error", nil, "id="+hook.Id+", "+err.Error(), http.StatusInternalServerError)
+	}
+
+	return hook, nil
+}
+
+func (s SqlIncomingWebhookStore) Get(id string) (*model.IncomingWebhook, *model.AppError) {
+	var hook model.IncomingWebhook
+	if err := s.GetReplica().SelectOne(&hook, "SELECT * FROM IncomingWebhooks WHERE Id = :Id AND DeleteAt = 0", map[string]interface{}{"Id": id}); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.NewAppError("SqlIncomingWebhookStore.Get", "store.sql_incoming_webhooks.get.missing.app_error", nil, "id="+id, http.StatusNotFound)
+		}
+		return nil, model.NewAppError("SqlIncomingWebhookStore.Get", "store.sql_incoming_webhooks.get.app_error", nil, "id="+id+", err="+err.Error(), http.StatusInternalServerError)
+	}
+
+	return &hook, nil
+}
+
+func (s SqlIncomingWebhookStore) GetByToken(token string) (*model.IncomingWebhook, *model.AppError) {
+	var hook model.IncomingWebhook
+	// 再発行直後の古いtokenを受け付けないよう、masterから読む
+	if err := s.GetMaster().SelectOne(&hook, "SELECT * FROM IncomingWebhooks WHERE Token = :Token AND DeleteAt = 0", map[string]interface{}{"Token": token}); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.NewAppError("SqlIncomingWebhookStore.GetByToken", "store.sql_incoming_webhooks.get_by_token.missing.app_error", nil, "", http.StatusNotFound)
+		}
+		return nil, model.NewAppError("SqlIncomingWebhookStore.GetByToken", "store.sql_incoming_webhooks.get_by_token.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	return &hook, nil
+}
+
+func (s SqlIncomingWebhookStore) GetByTeam(teamId string, offset, limit int) ([]*model.IncomingWebhook, *model.AppError) {
+	var hooks []*model.IncomingWebhook
+	if _, err := s.GetReplica().Select(&hooks, `
+		SELECT
+			*
+		FROM
+			IncomingWebhooks
+		WHERE
+			TeamId = :TeamId
+			AND DeleteAt = 0
+		ORDER BY CreateAt DESC
+		LIMIT :Limit OFFSET :Offset`, map[string]interface{}{"TeamId": teamId, "Limit": limit, "Offset": offset}); err != nil {
+		return nil, model.NewAppError("SqlIncomingWebhookStore.GetByTeam", "store.sql_incoming_webhooks.get_by_team.app_error", nil, "teamId="+teamId+", err="+err.Error(), http.StatusInternalServerError)
+	}
+
+	return hooks, nil
+}
+
+func (s SqlIncomingWebhookStore) Update(hook *model.IncomingWebhook) (*model.IncomingWebhook, *model.AppError) {
+	hook.UpdateAt = model.GetMillis()
+	if err := hook.IsValid(); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.GetMaster().Update(hook); err != nil {
+		return nil, model.NewAppError("SqlIncomingWebhookStore.Update", "store.sql_incoming_webhooks.update.app_error", nil, "id="+hook.Id+", "+err.Error(), http.StatusInternalServerError)
+	}
+
+	return hook, nil
+}
+
+func (s SqlIncomingWebhookStore) Delete(hookId string, time int64) *model.AppError {
+	if _, err := s.GetMaster().Exec("UPDATE IncomingWebhooks SET DeleteAt = :DeleteAt, UpdateAt = :UpdateAt WHERE Id = :Id", map[string]interface{}{"DeleteAt": time, "UpdateAt": time, "Id": hookId}); err != nil {
+		return model.NewAppError("SqlIncomingWebhookStore.Delete", "store.sql_incoming_webhooks.delete.app_error", nil, "id="+hookId+", err="+err.Error(), http.StatusInternalServerError)
+	}
+
+	return nil
+}
+
+// botを削除した時に、そのbotで投稿するwebhookもまとめて削除する
+func (s SqlIncomingWebhookStore) DeleteByBot(botUserId string, time int64) *model.AppError {
+	if _, err := s.GetMaster().Exec("UPDATE IncomingWebhooks SET DeleteAt = :DeleteAt, UpdateAt = :UpdateAt WHERE BotUserId = :BotUserId AND DeleteAt = 0", map[string]interface{}{"DeleteAt": time, "UpdateAt": time, "BotUserId": botUserId}); err != nil {
+		return model.NewAppError("SqlIncomingWebhookStore.DeleteByBot", "store.sql_incoming_webhooks.delete_by_bot.app_error", nil, "bot_user_id="+botUserId+", err="+err.Error(), http.StatusInternalServerError)
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/clear-ness/qa-discussion/store"
+)
+
+type SqlJobStore struct {
+	store.Store
+}
+
+func NewSqlJobStore(sqlStore store.Store) store.JobStore {
+	s := &SqlJobStore{
+		Store: sqlStore,
+	}
+
+	for _, db := range sqlStore.GetAllConns() {
+		db.AddTableWithName(model.Job{}, "Jobs").SetKeys(false, "Id")
+	}
+
+	return s
+}
+
+func (s SqlJobStore) Save(job *model.Job) (*model.Job, *model.AppError) {
+	if len(job.Id) > 0 {
+		return nil, model.NewAppError("SqlJobStore.Save", "store.sql_job.save.existing.app_error", nil, "id="+job.Id, http.StatusBadRequest)
+	}
+
+	job.PreSave()
+	if err := job.IsValid(); err != nil {
+		return nil, err
+	}
+
+	if err := s.GetMaster().Insert(job); err != nil {
+		return nil, model.NewAppError("SqlJobStore.Save", "store.sql_job.save.app_error", nil, "id="+job.Id+", "+err.Error(), http.StatusInternalServerError)
+	}
+
+	return job, nil
+}
+
+func (s SqlJobStore) Get(id string) (*model.Job, *model.AppError) {
+	var job model.Job
+	if err := s.GetMaster().SelectOne(&job, "SELECT * FROM Jobs WHERE Id = :Id", map[string]interface{}{"Id": id}); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.NewAppError("SqlJobStore.Get", "store.sql_job.get.missing.app_error", nil, "id="+id, http.StatusNotFound)
+		}
+		return nil, model.NewAppError("SqlJobStore.Get", "store.sql_job.get.app_error", nil, "id="+id+", "+err.Error(), http.StatusInternalServerError)
+	}
+
+	return &job, nil
+}
+
+func (s SqlJobStore) Update(job *model.Job) (*model.Job, *model.AppError) {
+	job.LastActivityAt = model.GetMillis()
+	if err := job.IsValid(); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.GetMaster().Update(job); err != nil {
+		return nil, model.NewAppError("SqlJobStore.Update", "store.sql_job.update.app_error", nil, "id="+job.Id+", "+err.Error(), http.StatusInternalServerError)
+	}
+
+	return job, nil
+}
+
+// 同じチームで同じ種類のjobが多重に走らないよう、未完了のjob数を返す
+func (s SqlJobStore) GetActiveCountByTeamAndType(teamId string, jobType string) (int64, *model.AppError) {
+	count, err := s.GetMaster().SelectInt(`
+		SELECT
+			COUNT(*)
+		FROM
+			Jobs
+		WHERE
+			TeamId = :TeamId
+			AND Type = :Type
+			AND Status IN (:Pending, :InProgress)`, map[string]interface{}{
+		"TeamId":     teamId,
+		"Type":       jobType,
+		"Pending":    model.JOB_STATUS_PENDING,
+		"InProgress": model.JOB_STATUS_IN_PROGRESS,
+	})
+	if err != nil {
+		return 0, model.NewAppError("SqlJobStore.GetActiveCountByTeamAndType", "store.sql_job.get_active_count.app_error", nil, "teamId="+teamId+", "+err.Error(), http.StatusInternalServerError)
+	}
+
+	return count, nil
+}
@@ -7,6 +7,7 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/clear-ness/qa-discussion/mlog"
@@ -19,8 +20,13 @@ import (
 // TODO: このファイル全体でteam,group,member再考
 type SqlPostStore struct {
 	store.Store
-	maxPostSizeOnce   sync.Once
-	maxPostSizeCached int
+	// リクエストごとの複製とも共有する
+	maxPostSize *maxPostSizeCache
+}
+
+type maxPostSizeCache struct {
+	once sync.Once
+	size int
 }
 
 func tagSliceColumns() []string {
@@ -40,8 +46,8 @@ func tagToSlice(tag *model.Tag) []interface{} {
 
 func NewSqlPostStore(sqlStore store.Store) store.PostStore {
 	s := &SqlPostStore{
-		Store:             sqlStore,
-		maxPostSizeCached: model.POST_CONTENT_MAX_RUNES,
+		Store:       sqlStore,
+		maxPostSize: &maxPostSizeCache{size: model.POST_CONTENT_MAX_RUNES},
 	}
 
 	for _, db := range sqlStore.GetAllConns() {
@@ -143,19 +149,120 @@ func (s *SqlPostStore) saveQuestion(transaction *gorp.Transaction, post *model.P
 		Points:   model.USER_POINT_FOR_CREATE_QUESTION,
 		CreateAt: curTime,
 	}
-	s.SaveUserPointHistory(user_point_history)
+	if _, err := s.saveUserPointHistory(transaction, user_point_history); err != nil {
+		return err
+	}
 
 	return nil
 }
 
 func (s *SqlPostStore) SaveUserPointHistory(history *model.UserPointHistory) (*model.UserPointHistory, *model.AppError) {
-	if err := s.GetMaster().Insert(history); err != nil {
-		return nil, model.NewAppError("SqlPostStore.SaveUserPointHistory", "store.sql_post.save_user_point_history.app_error", nil, err.Error(), http.StatusInternalServerError)
+	return s.saveUserPointHistory(s.GetMaster(), history)
+}
+
+// ポイントを更新するトランザクションと同じexecutorで保存する
+func (s *SqlPostStore) saveUserPointHistory(executor gorp.SqlExecutor, history *model.UserPointHistory) (*model.UserPointHistory, *model.AppError) {
+	// botはポイントの対象外。teamのメンバーではないのでTeamMembersのポイントも更新されない
+	if count, err := executor.SelectInt("SELECT COUNT(*) FROM Users WHERE Id = :Id AND Type = :Type", map[string]interface{}{"Id": history.UserId, "Type": model.USER_TYPE_BOT}); err != nil {
+		return nil, model.NewAppError("SqlPostStore.SaveUserPointHistory", "store.sql_post.save_user_point_history.app_error", nil, err.Error(), http.StatusInternalServerError).Wrap(err)
+	} else if count > 0 {
+		return nil, nil
+	}
+
+	if err := executor.Insert(history); err != nil {
+		return nil, model.NewAppError("SqlPostStore.SaveUserPointHistory", "store.sql_post.save_user_point_history.app_error", nil, err.Error(), http.StatusInternalServerError).Wrap(err)
 	}
 
 	return history, nil
 }
 
+// importでは作成日時などを元データのまま保存し、ポイントの付与やシステムレビューの作成は行わない。
+// OriginalIdが指定された場合は編集履歴として保存する。
+func (s *SqlPostStore) ImportPost(post *model.Post) (*model.Post, *model.AppError) {
+	if len(post.Id) > 0 {
+		return nil, model.NewAppError("SqlPostStore.ImportPost", "store.sql_post.import_post.existing.app_error", nil, "id="+post.Id, http.StatusBadRequest)
+	}
+
+	post.Id = model.NewId()
+	if post.CreateAt == 0 {
+		post.CreateAt = model.GetMillis()
+	}
+	if post.UpdateAt == 0 {
+		post.UpdateAt = post.CreateAt
+	}
+	post.MakeNonNil()
+
+	if err := post.IsValid(s.GetMaxPostSize()); err != nil {
+		return nil, err
+	}
+
+	transaction, err := s.GetMaster().Begin()
+	if err != nil {
+		return nil, model.NewAppError("SqlPostStore.ImportPost", "store.sql_post.import_post.open_transaction.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	defer finalizeTransaction(transaction)
+
+	if err := transaction.Insert(post); err != nil {
+		return nil, model.NewAppError("SqlPostStore.ImportPost", "store.sql_post.import_post.app_error", nil, "id="+post.Id+", "+err.Error(), http.StatusInternalServerError)
+	}
+
+	if len(post.OriginalId) == 0 {
+		switch post.Type {
+		case model.POST_TYPE_QUESTION:
+			if addedTags := strings.Fields(post.Tags); len(addedTags) > 0 {
+				sql, args, err := s.buildInsertTagsQuery(addedTags, post.CreateAt, post.TeamId)
+				if err != nil {
+					return nil, model.NewAppError("SqlPostStore.ImportPost", "store.sql_post.import_post.app_error", nil, err.Error(), http.StatusInternalServerError)
+				}
+
+				if _, err := transaction.Exec(sql, args...); err != nil {
+					return nil, model.NewAppError("SqlPostStore.ImportPost", "store.sql_post.import_post.insert_tags.app_error", nil, err.Error(), http.StatusInternalServerError)
+				}
+			}
+		case model.POST_TYPE_ANSWER:
+			if _, err := transaction.Exec("UPDATE Posts SET AnswerCount = AnswerCount + 1 WHERE Id = :Id", map[string]interface{}{"Id": post.ParentId}); err != nil {
+				return nil, model.NewAppError("SqlPostStore.ImportPost", "store.sql_post.import_post.updating.app_error", nil, err.Error(), http.StatusInternalServerError)
+			}
+		}
+	}
+
+	if err := transaction.Commit(); err != nil {
+		return nil, model.NewAppError("SqlPostStore.ImportPost", "store.sql_post.import_post.commit_transaction.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	return post, nil
+}
+
+// 再実行時に重複して取り込まないよう、元データのidをPropsに持つ投稿(編集履歴を含む)を返す。
+// Contentなどは読み込まない。
+func (s *SqlPostStore) GetImportedPosts(teamId string, propKey string, propPrefix string) ([]*model.Post, *model.AppError) {
+	pattern := "%" + sanitizeSearchTerm(`"`+propKey+`":"`+propPrefix, "*") + "%"
+
+	var posts []*model.Post
+	if _, err := s.GetReplica().Select(&posts, `
+		SELECT
+			Id, Type, RootId, ParentId, OriginalId, UserId, Props
+		FROM
+			Posts
+		WHERE
+			TeamId = :TeamId
+			AND Props LIKE :Pattern escape '*'`, map[string]interface{}{"TeamId": teamId, "Pattern": pattern}); err != nil {
+		return nil, model.NewAppError("SqlPostStore.GetImportedPosts", "store.sql_post.get_imported_posts.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	return posts, nil
+}
+
+// 投票者の分からない投票数を取り込むため、加算ではなく値をそのまま設定する
+func (s *SqlPostStore) SetVoteCounts(postId string, upVotes int, downVotes int) *model.AppError {
+	if _, err := s.GetMaster().Exec("UPDATE Posts SET UpVotes = :UpVotes, DownVotes = :DownVotes, Points = :Points WHERE Id = :Id",
+		map[string]interface{}{"UpVotes": upVotes, "DownVotes": downVotes, "Points": upVotes - downVotes, "Id": postId}); err != nil {
+		return model.NewAppError("SqlPostStore.SetVoteCounts", "store.sql_post.set_vote_counts.app_error", nil, "id="+postId+", "+err.Error(), http.StatusInternalServerError)
+	}
+
+	return nil
+}
+
 func (s *SqlPostStore) SaveAnswer(post *model.Post) (*model.Post, *model.AppError) {
 	var parent *model.Post
 	if err := s.GetReplica().SelectOne(&parent, "SELECT * FROM Posts WHERE Id = :Id AND DeleteAt = 0", map[string]interface{}{"Id": post.ParentId}); err != nil {
@@ -253,7 +360,9 @@ func (s *SqlPostStore) saveAnswer(transaction *gorp.Transaction, post *model.Pos
 		Points:   model.USER_POINT_FOR_CREATE_ANSWER,
 		CreateAt: curTime,
 	}
-	s.SaveUserPointHistory(user_point_history)
+	if _, err := s.saveUserPointHistory(transaction, user_point_history); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -386,7 +495,7 @@ func (s *SqlPostStore) buildInsertTagsQuery(addedTags []string, time int64, team
 		return "", args, model.NewAppError("SqlPostStore.buildInsertTagsQuery", "store.sql_post.inset_tags.app_error", nil, err.Error(), http.StatusInternalServerError)
 	}
 
-	return sql + " ON DUPLICATE KEY UPDATE PostCount = VALUES(PostCount) + 1, UpdateAt = VALUES(UpdateAt)", args, nil
+	return sql + tagsUpsertClause(s.DriverName()), args, nil
 }
 
 func (s *SqlPostStore) GetSingle(id string, includeDeleted bool) (*model.Post, *model.AppError) {
@@ -521,6 +630,7 @@ func (s *SqlPostStore) GetPosts(options *model.GetPostsOptions, getCount bool) (
 		TeamId:         options.TeamId,
 		IncludeDeleted: options.IncludeDeleted,
 		OriginalId:     options.OriginalId,
+		Cursor:         options.Cursor,
 	}
 
 	if options.Title != "" {
@@ -570,6 +680,10 @@ func (s *SqlPostStore) GetPosts(options *model.GetPostsOptions, getCount bool) (
 		return nil, int64(0), model.NewAppError("SqlPostStore.GetPostContext", "store.sql_post.get_posts.select.app_error", nil, err.Error(), http.StatusInternalServerError)
 	}
 
+	if options.Cursor != nil && options.Cursor.Before {
+		utils.ReverseSlice(posts)
+	}
+
 	totalCount := int64(0)
 	if getCount {
 		queryString, args, err = s.searchPosts(searchOptions, true).ToSql()
@@ -811,14 +925,9 @@ func (s *SqlPostStore) searchPosts(options *model.SearchPostsOptions, countQuery
 		})
 	}
 
-	var orderBy = "CreateAt DESC"
-	if options.SortType == model.POST_SORT_TYPE_ACTIVE {
-		orderBy = "UpdateAt DESC"
-	} else if options.SortType == model.POST_SORT_TYPE_VOTES {
-		orderBy = "Points DESC"
-	} else if options.SortType == model.POST_SORT_TYPE_ANSWERS {
-		orderBy = "AnswerCount DESC"
-	} else if options.TermsType == model.TERMS_TYPE_SIMILAR && options.SortType == model.POST_SORT_TYPE_RELEVANCE {
+	keys := postKeyset(options.SortType)
+	var orderBy = keys.orderBy(false)
+	if options.TermsType == model.TERMS_TYPE_SIMILAR && options.SortType == model.POST_SORT_TYPE_RELEVANCE {
 		// mysqlではデフォルトで似ている順になるため。
 		orderBy = ""
 	}
@@ -834,47 +943,57 @@ func (s *SqlPostStore) searchPosts(options *model.SearchPostsOptions, countQuery
 	}
 
 	if options.TermsType != "" {
-		searchColumns := ""
+		var searchColumns []string
 		if options.TermsType == model.TERMS_TYPE_TAG {
-			searchColumns = "Tags"
+			searchColumns = []string{"Tags"}
 		} else if options.TermsType == model.TERMS_TYPE_SIMILAR {
-			searchColumns = "Title, Tags"
+			searchColumns = []string{"Title", "Tags"}
 		} else if options.TermsType == model.TERMS_TYPE_PLAIN {
-			searchColumns = "Title, Tags, Content"
+			searchColumns = []string{"Title", "Tags", "Content"}
 		} else if options.TermsType == model.TERMS_TYPE_TITLE {
-			searchColumns = "Title"
+			searchColumns = []string{"Title"}
 		} else if options.TermsType == model.TERMS_TYPE_BODY || options.TermsType == model.TERMS_TYPE_LINK {
-			searchColumns = "Content"
+			searchColumns = []string{"Content"}
 		} else {
-			searchColumns = "Title, Tags, Content"
+			searchColumns = []string{"Title", "Tags", "Content"}
 		}
-		fulltextClause := fmt.Sprintf("MATCH(%s) AGAINST (? IN BOOLEAN MODE)", searchColumns)
 
-		excludeClause := ""
-		if excludedTerms != "" {
-			excludeClause = " -(" + excludedTerms + ")"
+		fulltext := fulltextTerms{
+			excluded: strings.Fields(excludedTerms),
+			phrase:   options.TermsType == model.TERMS_TYPE_LINK,
 		}
-
-		splitTerms := []string{}
 		for _, t := range strings.Fields(terms) {
 			if len(t) >= model.TAG_MIN_RUNES {
-				if options.TermsType == model.TERMS_TYPE_SIMILAR {
-					splitTerms = append(splitTerms, t)
-				} else if options.TermsType == model.TERMS_TYPE_LINK {
-					splitTerms = append(splitTerms, "\""+t+"\"")
+				if options.TermsType == model.TERMS_TYPE_SIMILAR || options.TermsType == model.TERMS_TYPE_LINK {
+					fulltext.optional = append(fulltext.optional, t)
 				} else {
-					splitTerms = append(splitTerms, "+"+t)
+					fulltext.required = append(fulltext.required, t)
 				}
 			}
 		}
-		terms = strings.Join(splitTerms, " ") + excludeClause
+		terms = fulltext.query(s.DriverName())
 
 		query = query.Where(sq.And{
-			sq.Expr(fulltextClause, terms),
+			sq.Expr(fulltextClause(s.DriverName(), searchColumns, "?"), terms),
 		})
+
+		// postgresは似ている順に並ばないので明示的に並べる
+		if orderBy == "" && s.DriverName() == model.DATABASE_DRIVER_POSTGRES && !countQuery {
+			query = query.OrderByClause(fmt.Sprintf("ts_rank(%s, to_tsquery('simple', ?)) DESC", tsvectorExpr(searchColumns)), terms)
+		}
 	}
 
 	if !countQuery {
+		if cursor := options.Cursor; cursor != nil {
+			if cursor.IsOffset() {
+				offset = cursor.Offset
+			} else {
+				query = query.Where(keys.where(cursorValues(cursor, true), cursor.Before))
+				orderBy = keys.orderBy(cursor.Before)
+				offset = 0
+			}
+		}
+
 		if orderBy != "" {
 			query = query.OrderBy(orderBy)
 		}
@@ -884,6 +1003,20 @@ func (s *SqlPostStore) searchPosts(options *model.SearchPostsOptions, countQuery
 	return query
 }
 
+// model.Post.SortKeyと同じ列で並べる
+func postKeyset(sortType string) keyset {
+	switch sortType {
+	case model.POST_SORT_TYPE_ACTIVE:
+		return keyset{columns: []string{"UpdateAt", "Id"}, desc: true}
+	case model.POST_SORT_TYPE_VOTES:
+		return keyset{columns: []string{"Points", "Id"}, desc: true}
+	case model.POST_SORT_TYPE_ANSWERS:
+		return keyset{columns: []string{"AnswerCount", "Id"}, desc: true}
+	default:
+		return keyset{columns: []string{"CreateAt", "Id"}, desc: true}
+	}
+}
+
 func (s *SqlPostStore) DeleteQuestion(postId string, time int64, deleteById string) *model.AppError {
 	appErr := func(errMsg string) *model.AppError {
 		return model.NewAppError("SqlPostStore.DeleteQuestion", "store.sql_post.delete_question.app_error", nil, "id="+postId+", err="+errMsg, http.StatusInternalServerError)
@@ -895,45 +1028,33 @@ func (s *SqlPostStore) DeleteQuestion(postId string, time int64, deleteById stri
 		return appErr(err.Error())
 	}
 
-	transaction, err := s.GetMaster().Begin()
-	if err != nil {
-		return model.NewAppError("SqlPostStore.DeleteQuestion", "store.sql_post.delete_question.open_transaction.app_error", nil, err.Error(), http.StatusInternalServerError)
-	}
-
-	defer finalizeTransaction(transaction)
-	if upsertErr := s.deleteQuestion(transaction, post, time, deleteById); upsertErr != nil {
-		return upsertErr
-	}
-
-	if err := transaction.Commit(); err != nil {
-		return model.NewAppError("SqlPostStore.DeleteQuestion", "store.sql_post.delete_question.commit_transaction.app_error", nil, err.Error(), http.StatusInternalServerError)
-	}
-
-	return nil
+	return runInTransaction(s.Store, "SqlPostStore.DeleteQuestion", "store.sql_post.delete_question", func(transaction *gorp.Transaction) *model.AppError {
+		return s.deleteQuestion(transaction, post, time, deleteById)
+	})
 }
 
 func (s *SqlPostStore) deleteQuestion(transaction *gorp.Transaction, post *model.Post, time int64, deleteById string) *model.AppError {
 	post.AddProp(model.POST_PROPS_DELETE_BY, deleteById)
 
 	if _, err := transaction.Exec("UPDATE Posts SET DeleteAt = :DeleteAt, UpdateAt = :UpdateAt, Props = :Props WHERE Id = :Id", map[string]interface{}{"DeleteAt": time, "UpdateAt": time, "Id": post.Id, "Props": model.StringInterfaceToJson(post.Props)}); err != nil {
-		return model.NewAppError("SqlPostStore.deleteQuestion", "store.sql_post.delete_question.updating.app_error", nil, err.Error(), http.StatusInternalServerError)
+		return model.NewAppError("SqlPostStore.deleteQuestion", "store.sql_post.delete_question.updating.app_error", nil, err.Error(), http.StatusInternalServerError).Wrap(err)
 	}
 
 	tagContents := strings.Fields(post.Tags)
 	for _, tagContent := range tagContents {
 		if _, err := transaction.Exec("UPDATE Tags SET PostCount = PostCount - 1 WHERE Content = :Content AND TeamId = :TeamId",
 			map[string]interface{}{"Content": tagContent, "TeamId": post.TeamId}); err != nil {
-			return model.NewAppError("SqlPostStore.deleteQuestion", "store.sql_post.delete_question.updating.app_error", nil, err.Error(), http.StatusInternalServerError)
+			return model.NewAppError("SqlPostStore.deleteQuestion", "store.sql_post.delete_question.updating.app_error", nil, err.Error(), http.StatusInternalServerError).Wrap(err)
 		}
 	}
 
 	if len(post.TeamId) == 0 {
 		if _, err := transaction.Exec("UPDATE Users SET Points = Points - :PointForCreateQuestion, UpdateAt = :UpdateAt WHERE Id = :Id", map[string]interface{}{"PointForCreateQuestion": model.USER_POINT_FOR_CREATE_QUESTION, "UpdateAt": time, "Id": post.UserId}); err != nil {
-			return model.NewAppError("SqlPostStore.deleteQuestion", "store.sql_post.delete_question.updating.app_error", nil, err.Error(), http.StatusInternalServerError)
+			return model.NewAppError("SqlPostStore.deleteQuestion", "store.sql_post.delete_question.updating.app_error", nil, err.Error(), http.StatusInternalServerError).Wrap(err)
 		}
 	} else {
 		if _, err := transaction.Exec("UPDATE TeamMembers SET Points = Points - :PointForCreateQuestion WHERE TeamId = :TeamId AND UserId = :UserId AND DeleteAt = 0", map[string]interface{}{"PointForCreateQuestion": model.USER_POINT_FOR_CREATE_QUESTION, "TeamId": post.TeamId, "UserId": post.UserId}); err != nil {
-			return model.NewAppError("SqlPostStore.deleteQuestion", "store.sql_post.delete_question.updating.app_error", nil, err.Error(), http.StatusInternalServerError)
+			return model.NewAppError("SqlPostStore.deleteQuestion", "store.sql_post.delete_question.updating.app_error", nil, err.Error(), http.StatusInternalServerError).Wrap(err)
 		}
 	}
 
@@ -952,7 +1073,9 @@ func (s *SqlPostStore) deleteQuestion(transaction *gorp.Transaction, post *model
 		Points:   -(model.USER_POINT_FOR_CREATE_QUESTION),
 		CreateAt: time,
 	}
-	s.SaveUserPointHistory(user_point_history)
+	if _, err := s.saveUserPointHistory(transaction, user_point_history); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -961,11 +1084,11 @@ func (s *SqlPostStore) invalidateReviewsForPost(transaction *gorp.Transaction, p
 	var rev int64
 	var err *model.AppError
 	if rev, err = s.GetCurrentRevisionForPost(postId, teamId); err != nil {
-		return model.NewAppError("SqlPostStore.invalidateReviewsForPost", "store.sql_post.invalidate_reviews_for_post.get_revision.app_error", nil, err.Error(), http.StatusInternalServerError)
+		return model.NewAppError("SqlPostStore.invalidateReviewsForPost", "store.sql_post.invalidate_reviews_for_post.get_revision.app_error", nil, err.Error(), http.StatusInternalServerError).Wrap(err)
 	}
 
-	if _, err := s.GetMaster().Exec("UPDATE Votes SET InvalidateAt = :InvalidateAt, LastPostRev = :LastPostRev WHERE PostId = :PostId AND Type IN (:Type1, :Type2, :Type3) AND InvalidateAt = 0  AND CompletedAt = 0 AND RejectedAt = 0", map[string]interface{}{"InvalidateAt": time, "LastPostRev": rev, "PostId": postId, "Type1": model.VOTE_TYPE_REVIEW, "Type2": model.VOTE_TYPE_FLAG, "Type3": model.VOTE_TYPE_SYSTEM}); err != nil {
-		return model.NewAppError("SqlPostStore.invalidateReviewsForPost", "store.sql_post.invalidate_reviews_for_post.inserting.app_error", nil, err.Error(), http.StatusInternalServerError)
+	if _, err := transaction.Exec("UPDATE Votes SET InvalidateAt = :InvalidateAt, LastPostRev = :LastPostRev WHERE PostId = :PostId AND Type IN (:Type1, :Type2, :Type3) AND InvalidateAt = 0  AND CompletedAt = 0 AND RejectedAt = 0", map[string]interface{}{"InvalidateAt": time, "LastPostRev": rev, "PostId": postId, "Type1": model.VOTE_TYPE_REVIEW, "Type2": model.VOTE_TYPE_FLAG, "Type3": model.VOTE_TYPE_SYSTEM}); err != nil {
+		return model.NewAppError("SqlPostStore.invalidateReviewsForPost", "store.sql_post.invalidate_reviews_for_post.inserting.app_error", nil, err.Error(), http.StatusInternalServerError).Wrap(err)
 	}
 
 	return nil
@@ -987,44 +1110,32 @@ func (s *SqlPostStore) DeleteAnswer(postId string, time int64, deleteById string
 		return appErr(err.Error())
 	}
 
-	transaction, err := s.GetMaster().Begin()
-	if err != nil {
-		return model.NewAppError("SqlPostStore.DeleteAnswer", "store.sql_post.delete_answer.open_transaction.app_error", nil, err.Error(), http.StatusInternalServerError)
-	}
-
-	defer finalizeTransaction(transaction)
-	if upsertErr := s.deleteAnswer(transaction, post, parent, time, deleteById); upsertErr != nil {
-		return upsertErr
-	}
-
-	if err := transaction.Commit(); err != nil {
-		return model.NewAppError("SqlPostStore.DeleteAnswer", "store.sql_post.delete_answer.commit_transaction.app_error", nil, err.Error(), http.StatusInternalServerError)
-	}
-
-	return nil
+	return runInTransaction(s.Store, "SqlPostStore.DeleteAnswer", "store.sql_post.delete_answer", func(transaction *gorp.Transaction) *model.AppError {
+		return s.deleteAnswer(transaction, post, parent, time, deleteById)
+	})
 }
 
 func (s *SqlPostStore) deleteAnswer(transaction *gorp.Transaction, post *model.Post, parent *model.Post, time int64, deleteById string) *model.AppError {
 	post.AddProp(model.POST_PROPS_DELETE_BY, deleteById)
 
 	if _, err := transaction.Exec("UPDATE Posts SET DeleteAt = :DeleteAt, UpdateAt = :UpdateAt, Props = :Props WHERE Id = :Id", map[string]interface{}{"DeleteAt": time, "UpdateAt": time, "Id": post.Id, "Props": model.StringInterfaceToJson(post.Props)}); err != nil {
-		return model.NewAppError("SqlPostStore.deleteAnswer", "store.sql_post.delete_answer.updating.app_error", nil, err.Error(), http.StatusInternalServerError)
+		return model.NewAppError("SqlPostStore.deleteAnswer", "store.sql_post.delete_answer.updating.app_error", nil, err.Error(), http.StatusInternalServerError).Wrap(err)
 	}
 
 	if _, err := transaction.Exec("UPDATE Posts SET AnswerCount = AnswerCount - 1 WHERE Id = :Id AND Type = :Type",
 		map[string]interface{}{"Id": post.ParentId, "Type": model.POST_TYPE_QUESTION}); err != nil {
-		return model.NewAppError("SqlPostStore.deleteAnswer", "store.sql_post.delete_answer.updating.app_error", nil, err.Error(), http.StatusInternalServerError)
+		return model.NewAppError("SqlPostStore.deleteAnswer", "store.sql_post.delete_answer.updating.app_error", nil, err.Error(), http.StatusInternalServerError).Wrap(err)
 	}
 
 	curTime := model.GetMillis()
 
 	if len(post.TeamId) == 0 {
 		if _, err := transaction.Exec("UPDATE Users SET Points = Points - :PointForCreateAnswer, UpdateAt = :UpdateAt WHERE Id = :Id", map[string]interface{}{"PointForCreateAnswer": model.USER_POINT_FOR_CREATE_ANSWER, "UpdateAt": curTime, "Id": post.UserId}); err != nil {
-			return model.NewAppError("SqlPostStore.deleteAnswer", "store.sql_post.delete_answer.updating.app_error", nil, err.Error(), http.StatusInternalServerError)
+			return model.NewAppError("SqlPostStore.deleteAnswer", "store.sql_post.delete_answer.updating.app_error", nil, err.Error(), http.StatusInternalServerError).Wrap(err)
 		}
 	} else {
 		if _, err := transaction.Exec("UPDATE TeamMembers SET Points = Points - :PointForCreateAnswer WHERE TeamId = :TeamId AND UserId = :UserId AND DeleteAt = 0", map[string]interface{}{"PointForCreateAnswer": model.USER_POINT_FOR_CREATE_ANSWER, "TeamId": post.TeamId, "UserId": post.UserId}); err != nil {
-			return model.NewAppError("SqlPostStore.deleteAnswer", "store.sql_post.delete_answer.updating.app_error", nil, err.Error(), http.StatusInternalServerError)
+			return model.NewAppError("SqlPostStore.deleteAnswer", "store.sql_post.delete_answer.updating.app_error", nil, err.Error(), http.StatusInternalServerError).Wrap(err)
 		}
 	}
 
@@ -1043,7 +1154,9 @@ func (s *SqlPostStore) deleteAnswer(transaction *gorp.Transaction, post *model.P
 		Points:   -(model.USER_POINT_FOR_CREATE_ANSWER),
 		CreateAt: curTime,
 	}
-	s.SaveUserPointHistory(user_point_history)
+	if _, err := s.saveUserPointHistory(transaction, user_point_history); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -1061,23 +1174,104 @@ func (s *SqlPostStore) DeleteComment(postId string, time int64, deleteById strin
 
 	post.AddProp(model.POST_PROPS_DELETE_BY, deleteById)
 
-	transaction, err := s.GetMaster().Begin()
-	if err != nil {
-		return model.NewAppError("SqlPostStore.DeleteComment", "store.sql_post.delete_comment.open_transaction.app_error", nil, err.Error(), http.StatusInternalServerError)
+	return runInTransaction(s.Store, "SqlPostStore.DeleteComment", "store.sql_post.delete_comment", func(transaction *gorp.Transaction) *model.AppError {
+		if _, err := transaction.Exec("UPDATE Posts SET DeleteAt = :DeleteAt, UpdateAt = :UpdateAt, Props = :Props WHERE Id = :Id", map[string]interface{}{"DeleteAt": time, "UpdateAt": time, "Id": postId, "Props": model.StringInterfaceToJson(post.Props)}); err != nil {
+			return model.NewAppError("SqlPostStore.DeleteComment", "store.sql_post.delete_comment.updating.app_error", nil, err.Error(), http.StatusInternalServerError).Wrap(err)
+		}
+
+		return s.invalidateReviewsForPost(transaction, postId, time, post.TeamId)
+	})
+}
+
+// 削除した投稿を戻し、削除で減らしたタグの投稿数、回答数、ポイントを戻す。
+// 無効にしたレビューは戻さない。回答とコメントは親が削除されていれば戻せない
+func (s *SqlPostStore) RestorePost(postId string, time int64) *model.AppError {
+	appErr := func(errMsg string) *model.AppError {
+		return model.NewAppError("SqlPostStore.RestorePost", "store.sql_post.restore_post.app_error", nil, "id="+postId+", err="+errMsg, http.StatusInternalServerError)
 	}
 
-	defer finalizeTransaction(transaction)
+	var post *model.Post
+	// 編集履歴も削除済みの投稿として残っているので除く
+	if err := s.GetMaster().SelectOne(&post, "SELECT * FROM Posts WHERE Id = :Id AND DeleteAt != 0 AND OriginalId = ''", map[string]interface{}{"Id": postId}); err != nil {
+		if err == sql.ErrNoRows {
+			return model.NewAppError("SqlPostStore.RestorePost", "store.sql_post.restore_post.not_deleted.app_error", nil, "id="+postId, http.StatusBadRequest)
+		}
+		return appErr(err.Error())
+	}
 
-	if _, err := transaction.Exec("UPDATE Posts SET DeleteAt = :DeleteAt, UpdateAt = :UpdateAt, Props = :Props WHERE Id = :Id", map[string]interface{}{"DeleteAt": time, "UpdateAt": time, "Id": postId, "Props": model.StringInterfaceToJson(post.Props)}); err != nil {
-		return model.NewAppError("SqlPostStore.DeleteComment", "store.sql_post.delete_comment.updating.app_error", nil, err.Error(), http.StatusInternalServerError)
+	var parent *model.Post
+	if post.Type != model.POST_TYPE_QUESTION {
+		if err := s.GetMaster().SelectOne(&parent, "SELECT * FROM Posts WHERE Id = :Id AND DeleteAt = 0", map[string]interface{}{"Id": post.ParentId}); err != nil {
+			if err == sql.ErrNoRows {
+				return model.NewAppError("SqlPostStore.RestorePost", "store.sql_post.restore_post.parent_deleted.app_error", nil, "id="+postId, http.StatusBadRequest)
+			}
+			return appErr(err.Error())
+		}
 	}
 
-	if err := s.invalidateReviewsForPost(transaction, postId, time, post.TeamId); err != nil {
-		return err
+	post.DelProp(model.POST_PROPS_DELETE_BY)
+
+	return runInTransaction(s.Store, "SqlPostStore.RestorePost", "store.sql_post.restore_post", func(transaction *gorp.Transaction) *model.AppError {
+		return s.restorePost(transaction, post, parent, time)
+	})
+}
+
+func (s *SqlPostStore) restorePost(transaction *gorp.Transaction, post *model.Post, parent *model.Post, time int64) *model.AppError {
+	updatingErr := func(err error) *model.AppError {
+		return model.NewAppError("SqlPostStore.restorePost", "store.sql_post.restore_post.updating.app_error", nil, err.Error(), http.StatusInternalServerError).Wrap(err)
 	}
 
-	if err := transaction.Commit(); err != nil {
-		return model.NewAppError("SqlPostStore.DeleteComment", "store.sql_post.delete_comment.commit_transaction.app_error", nil, err.Error(), http.StatusInternalServerError)
+	if _, err := transaction.Exec("UPDATE Posts SET DeleteAt = 0, UpdateAt = :UpdateAt, Props = :Props WHERE Id = :Id", map[string]interface{}{"UpdateAt": time, "Id": post.Id, "Props": model.StringInterfaceToJson(post.Props)}); err != nil {
+		return updatingErr(err)
+	}
+
+	var points int
+	var pointType, tags string
+	switch post.Type {
+	case model.POST_TYPE_QUESTION:
+		for _, tagContent := range strings.Fields(post.Tags) {
+			if _, err := transaction.Exec("UPDATE Tags SET PostCount = PostCount + 1 WHERE Content = :Content AND TeamId = :TeamId",
+				map[string]interface{}{"Content": tagContent, "TeamId": post.TeamId}); err != nil {
+				return updatingErr(err)
+			}
+		}
+
+		points, pointType, tags = model.USER_POINT_FOR_CREATE_QUESTION, model.USER_POINT_TYPE_RESTORE_QUESTION, post.Tags
+	case model.POST_TYPE_ANSWER:
+		if _, err := transaction.Exec("UPDATE Posts SET AnswerCount = AnswerCount + 1 WHERE Id = :Id AND Type = :Type",
+			map[string]interface{}{"Id": post.ParentId, "Type": model.POST_TYPE_QUESTION}); err != nil {
+			return updatingErr(err)
+		}
+
+		points, pointType, tags = model.USER_POINT_FOR_CREATE_ANSWER, model.USER_POINT_TYPE_RESTORE_ANSWER, parent.Tags
+	default:
+		// コメントはポイントを付与しない
+		return nil
+	}
+
+	if len(post.TeamId) == 0 {
+		if _, err := transaction.Exec("UPDATE Users SET Points = Points + :Points, UpdateAt = :UpdateAt WHERE Id = :Id", map[string]interface{}{"Points": points, "UpdateAt": time, "Id": post.UserId}); err != nil {
+			return updatingErr(err)
+		}
+	} else {
+		if _, err := transaction.Exec("UPDATE TeamMembers SET Points = Points + :Points WHERE TeamId = :TeamId AND UserId = :UserId AND DeleteAt = 0", map[string]interface{}{"Points": points, "TeamId": post.TeamId, "UserId": post.UserId}); err != nil {
+			return updatingErr(err)
+		}
+	}
+
+	user_point_history := &model.UserPointHistory{
+		Id:       model.NewId(),
+		TeamId:   post.TeamId,
+		UserId:   post.UserId,
+		Type:     pointType,
+		PostId:   post.Id,
+		PostType: post.Type,
+		Tags:     tags,
+		Points:   points,
+		CreateAt: time,
+	}
+	if _, err := s.saveUserPointHistory(transaction, user_point_history); err != nil {
+		return err
 	}
 
 	return nil
@@ -1108,21 +1302,9 @@ func (s *SqlPostStore) SelectBestAnswer(postId, bestId string) *model.AppError {
 		return model.NewAppError("SqlPostStore.SelectBestAnswer", "store.sql_post.select_best_answer.invalid_answer.app_error", nil, "", http.StatusInternalServerError)
 	}
 
-	transaction, err := s.GetMaster().Begin()
-	if err != nil {
-		return model.NewAppError("SqlPostStore.SelectBestAnswer", "store.sql_post.select_best_answer.open_transaction.app_error", nil, err.Error(), http.StatusInternalServerError)
-	}
-
-	defer finalizeTransaction(transaction)
-	if upsertErr := s.selectBestAnswer(transaction, post, ans); upsertErr != nil {
-		return upsertErr
-	}
-
-	if err = transaction.Commit(); err != nil {
-		return model.NewAppError("SqlPostStore.SelectBestAnswer", "store.sql_post.select_best_answer.commit_transaction.app_error", nil, err.Error(), http.StatusInternalServerError)
-	}
-
-	return nil
+	return runInTransaction(s.Store, "SqlPostStore.SelectBestAnswer", "store.sql_post.select_best_answer", func(transaction *gorp.Transaction) *model.AppError {
+		return s.selectBestAnswer(transaction, post, ans)
+	})
 }
 
 func (s *SqlPostStore) selectBestAnswer(transaction *gorp.Transaction, post *model.Post, ans *model.Post) *model.AppError {
@@ -1130,7 +1312,7 @@ func (s *SqlPostStore) selectBestAnswer(transaction *gorp.Transaction, post *mod
 
 	if _, err := transaction.Exec("UPDATE Posts SET BestId = :BestId, UpdateAt = :UpdateAt WHERE Id = :Id AND Type = :Type",
 		map[string]interface{}{"BestId": ans.Id, "UpdateAt": curTime, "Id": post.Id, "Type": model.POST_TYPE_QUESTION}); err != nil {
-		return model.NewAppError("SqlPostStore.selectBestAnswer", "store.sql_post.select_best_answer.updating.app_error", nil, err.Error(), http.StatusInternalServerError)
+		return model.NewAppError("SqlPostStore.selectBestAnswer", "store.sql_post.select_best_answer.updating.app_error", nil, err.Error(), http.StatusInternalServerError).Wrap(err)
 	}
 
 	// prevent self point gain
@@ -1140,19 +1322,19 @@ func (s *SqlPostStore) selectBestAnswer(transaction *gorp.Transaction, post *mod
 
 	if len(post.TeamId) == 0 {
 		if _, err := transaction.Exec("UPDATE Users SET Points = Points + :PointForSelectAnswer, UpdateAt = :UpdateAt WHERE Id = :Id", map[string]interface{}{"PointForSelectAnswer": model.USER_POINT_FOR_SELECT_ANSWER, "UpdateAt": curTime, "Id": post.UserId}); err != nil {
-			return model.NewAppError("SqlPostStore.selectBestAnswer", "store.sql_post.select_best_answer.updating.app_error", nil, err.Error(), http.StatusInternalServerError)
+			return model.NewAppError("SqlPostStore.selectBestAnswer", "store.sql_post.select_best_answer.updating.app_error", nil, err.Error(), http.StatusInternalServerError).Wrap(err)
 		}
 
 		if _, err := transaction.Exec("UPDATE Users SET Points = Points + :PointForSelectedAnswer, UpdateAt = :UpdateAt WHERE Id = :Id", map[string]interface{}{"PointForSelectedAnswer": model.USER_POINT_FOR_SELECTED_ANSWER, "UpdateAt": curTime, "Id": ans.UserId}); err != nil {
-			return model.NewAppError("SqlPostStore.selectBestAnswer", "store.sql_post.select_best_answer.updating.app_error", nil, err.Error(), http.StatusInternalServerError)
+			return model.NewAppError("SqlPostStore.selectBestAnswer", "store.sql_post.select_best_answer.updating.app_error", nil, err.Error(), http.StatusInternalServerError).Wrap(err)
 		}
 	} else {
 		if _, err := transaction.Exec("UPDATE TeamMembers SET Points = Points + :PointForSelectAnswer WHERE TeamId = :TeamId AND UserId = :UserId AND DeleteAt = 0", map[string]interface{}{"PointForSelectAnswer": model.USER_POINT_FOR_SELECT_ANSWER, "TeamId": post.TeamId, "UserId": post.UserId}); err != nil {
-			return model.NewAppError("SqlPostStore.selectBestAnswer", "store.sql_post.select_best_answer.updating.app_error", nil, err.Error(), http.StatusInternalServerError)
+			return model.NewAppError("SqlPostStore.selectBestAnswer", "store.sql_post.select_best_answer.updating.app_error", nil, err.Error(), http.StatusInternalServerError).Wrap(err)
 		}
 
 		if _, err := transaction.Exec("UPDATE TeamMembers SET Points = Points + :PointForSelectedAnswer WHERE TeamId = :TeamId AND UserId = :UserId AND DeleteAt = 0", map[string]interface{}{"PointForSelectedAnswer": model.USER_POINT_FOR_SELECTED_ANSWER, "TeamId": ans.TeamId, "UserId": ans.UserId}); err != nil {
-			return model.NewAppError("SqlPostStore.selectBestAnswer", "store.sql_post.select_best_answer.updating.app_error", nil, err.Error(), http.StatusInternalServerError)
+			return model.NewAppError("SqlPostStore.selectBestAnswer", "store.sql_post.select_best_answer.updating.app_error", nil, err.Error(), http.StatusInternalServerError).Wrap(err)
 		}
 	}
 
@@ -1167,7 +1349,9 @@ func (s *SqlPostStore) selectBestAnswer(transaction *gorp.Transaction, post *mod
 		Points:   model.USER_POINT_FOR_SELECT_ANSWER,
 		CreateAt: curTime,
 	}
-	s.SaveUserPointHistory(user_point_history)
+	if _, err := s.saveUserPointHistory(transaction, user_point_history); err != nil {
+		return err
+	}
 
 	user_point_history2 := &model.UserPointHistory{
 		Id:       model.NewId(),
@@ -1180,31 +1364,39 @@ func (s *SqlPostStore) selectBestAnswer(transaction *gorp.Transaction, post *mod
 		Points:   model.USER_POINT_FOR_SELECTED_ANSWER,
 		CreateAt: curTime,
 	}
-	s.SaveUserPointHistory(user_point_history2)
+	if _, err := s.saveUserPointHistory(transaction, user_point_history2); err != nil {
+		return err
+	}
 
 	return nil
 }
 
 func (s *SqlPostStore) GetMaxPostSize() int {
-	s.maxPostSizeOnce.Do(func() {
-		s.maxPostSizeCached = s.determineMaxPostSize()
+	s.maxPostSize.once.Do(func() {
+		s.maxPostSize.size = s.determineMaxPostSize()
 	})
 
-	return s.maxPostSizeCached
+	return s.maxPostSize.size
 }
 
 func (s *SqlPostStore) determineMaxPostSize() int {
 	var maxPostSizeBytes int32
 
+	// postgresではテーブル名、カラム名が小文字になっている
+	schema, table, column := "DATABASE()", "Posts", "Content"
+	if s.DriverName() == model.DATABASE_DRIVER_POSTGRES {
+		schema, table, column = "current_schema()", "posts", "content"
+	}
+
 	if err := s.GetReplica().SelectOne(&maxPostSizeBytes, `
 		SELECT
 			COALESCE(CHARACTER_MAXIMUM_LENGTH, 0)
 		FROM
 			INFORMATION_SCHEMA.COLUMNS
 		WHERE
-			table_schema = DATABASE()
-		AND table_name = 'Posts'
-		AND column_name = 'Content'
+			table_schema = `+schema+`
+		AND table_name = '`+table+`'
+		AND column_name = '`+column+`'
 		LIMIT 1
 	`); err != nil {
 		mlog.Error("Unable to determine the maximum supported post size", mlog.Err(err))
@@ -1274,21 +1466,13 @@ func (s *SqlPostStore) UpVotePost(postId string, userId string) (*model.Vote, *m
 		return nil, appErr(err.Error())
 	}
 
-	transaction, err := s.GetMaster().Begin()
-	if err != nil {
-		return nil, model.NewAppError("SqlPostStore.UpVotePost", "store.sql_post.upvote_post.open_transaction.app_error", nil, err.Error(), http.StatusInternalServerError)
-	}
-
-	defer finalizeTransaction(transaction)
-
 	var vote *model.Vote
-	var upsertErr *model.AppError
-	if vote, upsertErr = s.upvotePost(transaction, post, userId); upsertErr != nil {
-		return nil, upsertErr
-	}
-
-	if err := transaction.Commit(); err != nil {
-		return nil, model.NewAppError("SqlPostStore.UpVotePost", "store.sql_post.upvote_post.commit_transaction.app_error", nil, err.Error(), http.StatusInternalServerError)
+	if err := runInTransaction(s.Store, "SqlPostStore.UpVotePost", "store.sql_post.upvote_post", func(transaction *gorp.Transaction) *model.AppError {
+		var upsertErr *model.AppError
+		vote, upsertErr = s.upvotePost(transaction, post, userId)
+		return upsertErr
+	}); err != nil {
+		return nil, err
 	}
 
 	return vote, nil
@@ -1306,11 +1490,11 @@ func (s *SqlPostStore) upvotePost(transaction *gorp.Transaction, post *model.Pos
 	}
 
 	if err := transaction.Insert(vote); err != nil {
-		return nil, model.NewAppError("SqlPostStore.upvotePost", "store.sql_post.upvotePost.inserting.app_error", nil, err.Error(), http.StatusInternalServerError)
+		return nil, model.NewAppError("SqlPostStore.upvotePost", "store.sql_post.upvotePost.inserting.app_error", nil, err.Error(), http.StatusInternalServerError).Wrap(err)
 	}
 
 	if _, err := transaction.Exec("UPDATE Posts SET UpVotes = UpVotes + 1, Points = Points + 1, UpdateAt = :UpdateAt WHERE Id = :Id", map[string]interface{}{"UpdateAt": curTime, "Id": post.Id}); err != nil {
-		return nil, model.NewAppError("SqlPostStore.upvotePost", "store.sql_post.upvotePost.updating.app_error", nil, err.Error(), http.StatusInternalServerError)
+		return nil, model.NewAppError("SqlPostStore.upvotePost", "store.sql_post.upvotePost.updating.app_error", nil, err.Error(), http.StatusInternalServerError).Wrap(err)
 	}
 
 	// prevent self point gain
@@ -1320,11 +1504,11 @@ func (s *SqlPostStore) upvotePost(transaction *gorp.Transaction, post *model.Pos
 
 	if len(post.TeamId) == 0 {
 		if _, err := transaction.Exec("UPDATE Users SET Points = Points + :PointForVoted, UpdateAt = :UpdateAt WHERE Id = :Id", map[string]interface{}{"PointForVoted": model.USER_POINT_FOR_VOTED, "UpdateAt": curTime, "Id": post.UserId}); err != nil {
-			return nil, model.NewAppError("SqlPostStore.upvotePost", "store.sql_post.upvotePost.updating.app_error", nil, err.Error(), http.StatusInternalServerError)
+			return nil, model.NewAppError("SqlPostStore.upvotePost", "store.sql_post.upvotePost.updating.app_error", nil, err.Error(), http.StatusInternalServerError).Wrap(err)
 		}
 	} else {
 		if _, err := transaction.Exec("UPDATE TeamMembers SET Points = Points + :PointForVoted WHERE TeamId = :TeamId AND UserId = :UserId AND DeleteAt = 0", map[string]interface{}{"PointForVoted": model.USER_POINT_FOR_VOTED, "TeamId": post.TeamId, "UserId": post.UserId}); err != nil {
-			return nil, model.NewAppError("SqlPostStore.upvotePost", "store.sql_post.upvotePost.updating.app_error", nil, err.Error(), http.StatusInternalServerError)
+			return nil, model.NewAppError("SqlPostStore.upvotePost", "store.sql_post.upvotePost.updating.app_error", nil, err.Error(), http.StatusInternalServerError).Wrap(err)
 		}
 	}
 
@@ -1345,7 +1529,9 @@ func (s *SqlPostStore) upvotePost(transaction *gorp.Transaction, post *model.Pos
 		Points:   model.USER_POINT_FOR_VOTED,
 		CreateAt: curTime,
 	}
-	s.SaveUserPointHistory(user_point_history)
+	if _, err := s.saveUserPointHistory(transaction, user_point_history); err != nil {
+		return nil, err
+	}
 
 	return vote, nil
 }
@@ -1393,20 +1579,10 @@ func (s *SqlPostStore) CancelUpVotePost(postId string, userId string) (*model.Vo
 		return nil, model.NewAppError("SqlPostStore.CancelUpvotePost", "store.sql_post.cancel_upvote_post.select.app_error", nil, "", http.StatusInternalServerError)
 	}
 
-	transaction, err := s.GetMaster().Begin()
-	if err != nil {
-		return nil, model.NewAppError("SqlPostStore.CancelUpVotePost", "store.sql_post.cancel_upvote_post.open_transaction.app_error", nil, err.Error(), http.StatusInternalServerError)
-	}
-
-	defer finalizeTransaction(transaction)
-
-	var upsertErr *model.AppError
-	if upsertErr = s.cancelUpvotePost(transaction, vote, post, userId); upsertErr != nil {
-		return nil, upsertErr
-	}
-
-	if err = transaction.Commit(); err != nil {
-		return nil, model.NewAppError("SqlPostStore.CancelUpVotePost", "store.sql_post.cancel_upvote_post.commit_transaction.app_error", nil, err.Error(), http.StatusInternalServerError)
+	if err := runInTransaction(s.Store, "SqlPostStore.CancelUpVotePost", "store.sql_post.cancel_upvote_post", func(transaction *gorp.Transaction) *model.AppError {
+		return s.cancelUpvotePost(transaction, vote, post, userId)
+	}); err != nil {
+		return nil, err
 	}
 
 	return vote, nil
@@ -1414,17 +1590,14 @@ func (s *SqlPostStore) CancelUpVotePost(postId string, userId string) (*model.Vo
 
 func (s *SqlPostStore) cancelUpvotePost(transaction *gorp.Transaction, vote *model.Vote, post *model.Post, userId string) *model.AppError {
 	if _, err := transaction.Delete(vote); err != nil {
-		return model.NewAppError("SqlPostStore.CancelUpVotePost", "store.sql_post.cancel_upvote_post.deleting.app_error", nil, err.Error(), http.StatusInternalServerError)
+		return model.NewAppError("SqlPostStore.CancelUpVotePost", "store.sql_post.cancel_upvote_post.deleting.app_error", nil, err.Error(), http.StatusInternalServerError).Wrap(err)
 	}
 
 	curTime := model.GetMillis()
 
-	post.UpVotes = post.UpVotes - 1
-	if post.UpVotes < 0 {
-		post.UpVotes = 0
-	}
-	if _, err := transaction.Exec("UPDATE Posts SET UpVotes = :UpVotes, Points = Points - 1, UpdateAt = :UpdateAt WHERE Id = :Id", map[string]interface{}{"UpVotes": post.UpVotes, "UpdateAt": curTime, "Id": post.Id}); err != nil {
-		return model.NewAppError("SqlPostStore.CancelUpVotePost", "store.sql_post.cancel_upvote_post.updating.app_error", nil, err.Error(), http.StatusInternalServerError)
+	// やり直しで再度呼ばれても二重に減らさないよう、取得済みのpostではなくDBの値から減らす
+	if _, err := transaction.Exec("UPDATE Posts SET UpVotes = GREATEST(UpVotes - 1, 0), Points = Points - 1, UpdateAt = :UpdateAt WHERE Id = :Id", map[string]interface{}{"UpdateAt": curTime, "Id": post.Id}); err != nil {
+		return model.NewAppError("SqlPostStore.CancelUpVotePost", "store.sql_post.cancel_upvote_post.updating.app_error", nil, err.Error(), http.StatusInternalServerError).Wrap(err)
 	}
 
 	// prevent self point gain
@@ -1434,11 +1607,11 @@ func (s *SqlPostStore) cancelUpvotePost(transaction *gorp.Transaction, vote *mod
 
 	if len(post.TeamId) == 0 {
 		if _, err := transaction.Exec("UPDATE Users SET Points = Points - :PointForVoted, UpdateAt = :UpdateAt WHERE Id = :Id", map[string]interface{}{"PointForVoted": model.USER_POINT_FOR_VOTED, "UpdateAt": curTime, "Id": post.UserId}); err != nil {
-			return model.NewAppError("SqlPostStore.CancelUpvotePost", "store.sql_post.cancel_upvote_post.updating.app_error", nil, err.Error(), http.StatusInternalServerError)
+			return model.NewAppError("SqlPostStore.CancelUpvotePost", "store.sql_post.cancel_upvote_post.updating.app_error", nil, err.Error(), http.StatusInternalServerError).Wrap(err)
 		}
 	} else {
 		if _, err := transaction.Exec("UPDATE TeamMembers SET Points = Points - :PointForVoted WHERE TeamId = :TeamId AND UserId = :UserId AND DeleteAt = 0", map[string]interface{}{"PointForVoted": model.USER_POINT_FOR_VOTED, "TeamId": post.TeamId, "UserId": post.UserId}); err != nil {
-			return model.NewAppError("SqlPostStore.CancelUpvotePost", "store.sql_post.cancel_upvote_post.updating.app_error", nil, err.Error(), http.StatusInternalServerError)
+			return model.NewAppError("SqlPostStore.CancelUpvotePost", "store.sql_post.cancel_upvote_post.updating.app_error", nil, err.Error(), http.StatusInternalServerError).Wrap(err)
 		}
 	}
 
@@ -1459,7 +1632,9 @@ func (s *SqlPostStore) cancelUpvotePost(transaction *gorp.Transaction, vote *mod
 		Points:   -(model.USER_POINT_FOR_VOTED),
 		CreateAt: curTime,
 	}
-	s.SaveUserPointHistory(user_point_history)
+	if _, err := s.saveUserPointHistory(transaction, user_point_history); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -1475,21 +1650,13 @@ func (s *SqlPostStore) DownVotePost(postId string, userId string) (*model.Vote,
 		return nil, appErr(err.Error())
 	}
 
-	transaction, err := s.GetMaster().Begin()
-	if err != nil {
-		return nil, model.NewAppError("SqlPostStore.DownVotePost", "store.sql_post.downvote_post.open_transaction.app_error", nil, err.Error(), http.StatusInternalServerError)
-	}
-
-	defer finalizeTransaction(transaction)
-
 	var vote *model.Vote
-	var upsertErr *model.AppError
-	if vote, upsertErr = s.downvotePost(transaction, post, userId); upsertErr != nil {
-		return nil, upsertErr
-	}
-
-	if err := transaction.Commit(); err != nil {
-		return nil, model.NewAppError("SqlPostStore.DownVotePost", "store.sql_post.downvote_post.commit_transaction.app_error", nil, err.Error(), http.StatusInternalServerError)
+	if err := runInTransaction(s.Store, "SqlPostStore.DownVotePost", "store.sql_post.downvote_post", func(transaction *gorp.Transaction) *model.AppError {
+		var upsertErr *model.AppError
+		vote, upsertErr = s.downvotePost(transaction, post, userId)
+		return upsertErr
+	}); err != nil {
+		return nil, err
 	}
 
 	return vote, nil
@@ -1507,11 +1674,11 @@ func (s *SqlPostStore) downvotePost(transaction *gorp.Transaction, post *model.P
 	}
 
 	if err := transaction.Insert(vote); err != nil {
-		return nil, model.NewAppError("SqlPostStore.downvotePost", "store.sql_post.downvotePost.inserting.app_error", nil, err.Error(), http.StatusInternalServerError)
+		return nil, model.NewAppError("SqlPostStore.downvotePost", "store.sql_post.downvotePost.inserting.app_error", nil, err.Error(), http.StatusInternalServerError).Wrap(err)
 	}
 
 	if _, err := transaction.Exec("UPDATE Posts SET DownVotes = DownVotes + 1, Points = Points - 1, UpdateAt = :UpdateAt WHERE Id = :Id", map[string]interface{}{"UpdateAt": curTime, "Id": post.Id}); err != nil {
-		return nil, model.NewAppError("SqlPostStore.downvotePost", "store.sql_post.downvotePost.updating.app_error", nil, err.Error(), http.StatusInternalServerError)
+		return nil, model.NewAppError("SqlPostStore.downvotePost", "store.sql_post.downvotePost.updating.app_error", nil, err.Error(), http.StatusInternalServerError).Wrap(err)
 	}
 
 	// prevent self point gain
@@ -1521,11 +1688,11 @@ func (s *SqlPostStore) downvotePost(transaction *gorp.Transaction, post *model.P
 
 	if len(post.TeamId) == 0 {
 		if _, err := transaction.Exec("UPDATE Users SET Points = Points + :PointForDownVoted, UpdateAt = :UpdateAt WHERE Id = :Id", map[string]interface{}{"PointForDownVoted": model.USER_POINT_FOR_DOWN_VOTED, "UpdateAt": curTime, "Id": post.UserId}); err != nil {
-			return nil, model.NewAppError("SqlPostStore.downvotePost", "store.sql_post.downvotePost.updating.app_error", nil, err.Error(), http.StatusInternalServerError)
+			return nil, model.NewAppError("SqlPostStore.downvotePost", "store.sql_post.downvotePost.updating.app_error", nil, err.Error(), http.StatusInternalServerError).Wrap(err)
 		}
 	} else {
 		if _, err := transaction.Exec("UPDATE TeamMembers SET Points = Points + :PointForDownVoted WHERE TeamId = :TeamId AND UserId = :UserId AND DeleteAt = 0", map[string]interface{}{"PointForDownVoted": model.USER_POINT_FOR_DOWN_VOTED, "TeamId": post.TeamId, "UserId": post.UserId}); err != nil {
-			return nil, model.NewAppError("SqlPostStore.downvotePost", "store.sql_post.downvotePost.updating.app_error", nil, err.Error(), http.StatusInternalServerError)
+			return nil, model.NewAppError("SqlPostStore.downvotePost", "store.sql_post.downvotePost.updating.app_error", nil, err.Error(), http.StatusInternalServerError).Wrap(err)
 		}
 	}
 
@@ -1546,7 +1713,9 @@ func (s *SqlPostStore) downvotePost(transaction *gorp.Transaction, post *model.P
 		Points:   model.USER_POINT_FOR_DOWN_VOTED,
 		CreateAt: curTime,
 	}
-	s.SaveUserPointHistory(user_point_history)
+	if _, err := s.saveUserPointHistory(transaction, user_point_history); err != nil {
+		return nil, err
+	}
 
 	return vote, nil
 }
@@ -1574,18 +1743,10 @@ func (s *SqlPostStore) CancelDownVotePost(postId string, userId string) (*model.
 		return nil, model.NewAppError("SqlPostStore.CancelDownvotePost", "store.sql_post.cancel_downvote_post.select.app_error", nil, "", http.StatusInternalServerError)
 	}
 
-	transaction, err := s.GetMaster().Begin()
-	if err != nil {
-		return nil, model.NewAppError("SqlPostStore.CancelDownVotePost", "store.sql_post.cancel_downvote_post.open_transaction.app_error", nil, err.Error(), http.StatusInternalServerError)
-	}
-
-	defer finalizeTransaction(transaction)
-	if upsertErr := s.cancelDownvotePost(transaction, vote, post, userId); upsertErr != nil {
-		return nil, upsertErr
-	}
-
-	if err = transaction.Commit(); err != nil {
-		return nil, model.NewAppError("SqlPostStore.CancelDownVotePost", "store.sql_post.cancel_downvote_post.commit_transaction.app_error", nil, err.Error(), http.StatusInternalServerError)
+	if err := runInTransaction(s.Store, "SqlPostStore.CancelDownVotePost", "store.sql_post.cancel_downvote_post", func(transaction *gorp.Transaction) *model.AppError {
+		return s.cancelDownvotePost(transaction, vote, post, userId)
+	}); err != nil {
+		return nil, err
 	}
 
 	return vote, nil
@@ -1593,17 +1754,14 @@ func (s *SqlPostStore) CancelDownVotePost(postId string, userId string) (*model.
 
 func (s *SqlPostStore) cancelDownvotePost(transaction *gorp.Transaction, vote *model.Vote, post *model.Post, userId string) *model.AppError {
 	if _, err := transaction.Delete(vote); err != nil {
-		return model.NewAppError("SqlPostStore.CancelDownVotePost", "store.sql_post.cancel_downvote_post.deleting.app_error", nil, err.Error(), http.StatusInternalServerError)
+		return model.NewAppError("SqlPostStore.CancelDownVotePost", "store.sql_post.cancel_downvote_post.deleting.app_error", nil, err.Error(), http.StatusInternalServerError).Wrap(err)
 	}
 
 	curTime := model.GetMillis()
 
-	post.DownVotes = post.DownVotes - 1
-	if post.DownVotes < 0 {
-		post.DownVotes = 0
-	}
-	if _, err := transaction.Exec("UPDATE Posts SET DownVotes = :DownVotes, Points = Points + 1, UpdateAt = :UpdateAt WHERE Id = :Id", map[string]interface{}{"DownVotes": post.DownVotes, "UpdateAt": curTime, "Id": post.Id}); err != nil {
-		return model.NewAppError("SqlPostStore.CancelDownVotePost", "store.sql_post.cancel_downvote_post.updating.app_error", nil, err.Error(), http.StatusInternalServerError)
+	// やり直しで再度呼ばれても二重に減らさないよう、取得済みのpostではなくDBの値から減らす
+	if _, err := transaction.Exec("UPDATE Posts SET DownVotes = GREATEST(DownVotes - 1, 0), Points = Points + 1, UpdateAt = :UpdateAt WHERE Id = :Id", map[string]interface{}{"UpdateAt": curTime, "Id": post.Id}); err != nil {
+		return model.NewAppError("SqlPostStore.CancelDownVotePost", "store.sql_post.cancel_downvote_post.updating.app_error", nil, err.Error(), http.StatusInternalServerError).Wrap(err)
 	}
 
 	// prevent self point gain
@@ -1613,11 +1771,11 @@ func (s *SqlPostStore) cancelDownvotePost(transaction *gorp.Transaction, vote *m
 
 	if len(post.TeamId) == 0 {
 		if _, err := transaction.Exec("UPDATE Users SET Points = Points - :PointForDownVoted, UpdateAt = :UpdateAt WHERE Id = :Id", map[string]interface{}{"PointForDownVoted": model.USER_POINT_FOR_DOWN_VOTED, "UpdateAt": curTime, "Id": post.UserId}); err != nil {
-			return model.NewAppError("SqlPostStore.CancelDownvotePost", "store.sql_post.cancel_downvote_post.updating.app_error", nil, err.Error(), http.StatusInternalServerError)
+			return model.NewAppError("SqlPostStore.CancelDownvotePost", "store.sql_post.cancel_downvote_post.updating.app_error", nil, err.Error(), http.StatusInternalServerError).Wrap(err)
 		}
 	} else {
 		if _, err := transaction.Exec("UPDATE TeamMembers SET Points = Points - :PointForDownVoted WHERE TeamId = :TeamId AND UserId = :UserId AND DeleteAt = 0", map[string]interface{}{"PointForDownVoted": model.USER_POINT_FOR_DOWN_VOTED, "TeamId": post.TeamId, "UserId": post.UserId}); err != nil {
-			return model.NewAppError("SqlPostStore.CancelDownvotePost", "store.sql_post.cancel_downvote_post.updating.app_error", nil, err.Error(), http.StatusInternalServerError)
+			return model.NewAppError("SqlPostStore.CancelDownvotePost", "store.sql_post.cancel_downvote_post.updating.app_error", nil, err.Error(), http.StatusInternalServerError).Wrap(err)
 		}
 	}
 
@@ -1638,7 +1796,9 @@ func (s *SqlPostStore) cancelDownvotePost(transaction *gorp.Transaction, vote *m
 		Points:   -(model.USER_POINT_FOR_DOWN_VOTED),
 		CreateAt: curTime,
 	}
-	s.SaveUserPointHistory(user_point_history)
+	if _, err := s.saveUserPointHistory(transaction, user_point_history); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -1654,21 +1814,13 @@ func (s *SqlPostStore) FlagPost(postId string, userId string) (*model.Vote, *mod
 		return nil, appErr(err.Error())
 	}
 
-	transaction, err := s.GetMaster().Begin()
-	if err != nil {
-		return nil, model.NewAppError("SqlPostStore.FlagPost", "store.sql_post.flag_post.open_transaction.app_error", nil, err.Error(), http.StatusInternalServerError)
-	}
-
-	defer finalizeTransaction(transaction)
-
 	var vote *model.Vote
-	var upsertErr *model.AppError
-	if vote, upsertErr = s.flagPost(transaction, post, userId); upsertErr != nil {
-		return nil, upsertErr
-	}
-
-	if err := transaction.Commit(); err != nil {
-		return nil, model.NewAppError("SqlPostStore.FlagPost", "store.sql_post.flag_post.commit_transaction.app_error", nil, err.Error(), http.StatusInternalServerError)
+	if err := runInTransaction(s.Store, "SqlPostStore.FlagPost", "store.sql_post.flag_post", func(transaction *gorp.Transaction) *model.AppError {
+		var upsertErr *model.AppError
+		vote, upsertErr = s.flagPost(transaction, post, userId)
+		return upsertErr
+	}); err != nil {
+		return nil, err
 	}
 
 	return vote, nil
@@ -1680,7 +1832,7 @@ func (s *SqlPostStore) flagPost(transaction *gorp.Transaction, post *model.Post,
 	var rev int64
 	var err *model.AppError
 	if rev, err = s.GetCurrentRevisionForPost(post.Id, post.TeamId); err != nil {
-		return nil, model.NewAppError("SqlPostStore.flagPost", "store.sql_post.flagPost.get_revision.app_error", nil, err.Error(), http.StatusInternalServerError)
+		return nil, model.NewAppError("SqlPostStore.flagPost", "store.sql_post.flagPost.get_revision.app_error", nil, err.Error(), http.StatusInternalServerError).Wrap(err)
 	}
 
 	flag := &model.Vote{
@@ -1693,20 +1845,20 @@ func (s *SqlPostStore) flagPost(transaction *gorp.Transaction, post *model.Post,
 	}
 
 	if err := transaction.Insert(flag); err != nil {
-		return nil, model.NewAppError("SqlPostStore.flagPost", "store.sql_post.flagPost.inserting.app_error", nil, err.Error(), http.StatusInternalServerError)
+		return nil, model.NewAppError("SqlPostStore.flagPost", "store.sql_post.flagPost.inserting.app_error", nil, err.Error(), http.StatusInternalServerError).Wrap(err)
 	}
 
 	if _, err := transaction.Exec("UPDATE Posts SET FlagCount = FlagCount + 1, UpdateAt = :UpdateAt WHERE Id = :Id", map[string]interface{}{"UpdateAt": curTime, "Id": post.Id}); err != nil {
-		return nil, model.NewAppError("SqlPostStore.flagPost", "store.sql_post.flagPost.updating.app_error", nil, err.Error(), http.StatusInternalServerError)
+		return nil, model.NewAppError("SqlPostStore.flagPost", "store.sql_post.flagPost.updating.app_error", nil, err.Error(), http.StatusInternalServerError).Wrap(err)
 	}
 
 	if len(post.TeamId) == 0 {
 		if _, err := transaction.Exec("UPDATE Users SET Points = Points + :PointForFlagged, UpdateAt = :UpdateAt WHERE Id = :Id", map[string]interface{}{"PointForFlagged": model.USER_POINT_FOR_FLAGGED, "UpdateAt": curTime, "Id": post.UserId}); err != nil {
-			return nil, model.NewAppError("SqlPostStore.flagPost", "store.sql_post.flagPost.updating.app_error", nil, err.Error(), http.StatusInternalServerError)
+			return nil, model.NewAppError("SqlPostStore.flagPost", "store.sql_post.flagPost.updating.app_error", nil, err.Error(), http.StatusInternalServerError).Wrap(err)
 		}
 	} else {
 		if _, err := transaction.Exec("UPDATE TeamMembers SET Points = Points + :PointForFlagged WHERE TeamId = :TeamId AND UserId = :UserId AND DeleteAt = 0", map[string]interface{}{"PointForFlagged": model.USER_POINT_FOR_FLAGGED, "TeamId": post.TeamId, "UserId": post.UserId}); err != nil {
-			return nil, model.NewAppError("SqlPostStore.flagPost", "store.sql_post.flagPost.updating.app_error", nil, err.Error(), http.StatusInternalServerError)
+			return nil, model.NewAppError("SqlPostStore.flagPost", "store.sql_post.flagPost.updating.app_error", nil, err.Error(), http.StatusInternalServerError).Wrap(err)
 		}
 	}
 
@@ -1727,7 +1879,9 @@ func (s *SqlPostStore) flagPost(transaction *gorp.Transaction, post *model.Post,
 		Points:   model.USER_POINT_FOR_FLAGGED,
 		CreateAt: curTime,
 	}
-	s.SaveUserPointHistory(user_point_history)
+	if _, err := s.saveUserPointHistory(transaction, user_point_history); err != nil {
+		return nil, err
+	}
 
 	return flag, nil
 }
@@ -1755,18 +1909,10 @@ func (s *SqlPostStore) CancelFlagPost(postId string, userId string) (*model.Vote
 		return nil, model.NewAppError("SqlPostStore.CancelFlagPost", "store.sql_post.cancel_flag_post.select.app_error", nil, "", http.StatusInternalServerError)
 	}
 
-	transaction, err := s.GetMaster().Begin()
-	if err != nil {
-		return nil, model.NewAppError("SqlPostStore.CancelFlagPost", "store.sql_post.cancel_flag_post.open_transaction.app_error", nil, err.Error(), http.StatusInternalServerError)
-	}
-
-	defer finalizeTransaction(transaction)
-	if upsertErr := s.cancelFlagPost(transaction, flag, post, userId); upsertErr != nil {
-		return nil, upsertErr
-	}
-
-	if err = transaction.Commit(); err != nil {
-		return nil, model.NewAppError("SqlPostStore.CancelFlagPost", "store.sql_post.cancel_flag_post.commit_transaction.app_error", nil, err.Error(), http.StatusInternalServerError)
+	if err := runInTransaction(s.Store, "SqlPostStore.CancelFlagPost", "store.sql_post.cancel_flag_post", func(transaction *gorp.Transaction) *model.AppError {
+		return s.cancelFlagPost(transaction, flag, post, userId)
+	}); err != nil {
+		return nil, err
 	}
 
 	return flag, nil
@@ -1774,22 +1920,22 @@ func (s *SqlPostStore) CancelFlagPost(postId string, userId string) (*model.Vote
 
 func (s *SqlPostStore) cancelFlagPost(transaction *gorp.Transaction, flag *model.Vote, post *model.Post, userId string) *model.AppError {
 	if _, err := transaction.Delete(flag); err != nil {
-		return model.NewAppError("SqlPostStore.CancelFlagPost", "store.sql_post.cancel_flag_post.deleting.app_error", nil, err.Error(), http.StatusInternalServerError)
+		return model.NewAppError("SqlPostStore.CancelFlagPost", "store.sql_post.cancel_flag_post.deleting.app_error", nil, err.Error(), http.StatusInternalServerError).Wrap(err)
 	}
 
 	curTime := model.GetMillis()
 
 	if _, err := transaction.Exec("UPDATE Posts SET FlagCount = FlagCount - 1, UpdateAt = :UpdateAt WHERE Id = :Id", map[string]interface{}{"UpdateAt": curTime, "Id": post.Id}); err != nil {
-		return model.NewAppError("SqlPostStore.cancelFlagPost", "store.sql_post.cancelFlagPost.updating.app_error", nil, err.Error(), http.StatusInternalServerError)
+		return model.NewAppError("SqlPostStore.cancelFlagPost", "store.sql_post.cancelFlagPost.updating.app_error", nil, err.Error(), http.StatusInternalServerError).Wrap(err)
 	}
 
 	if len(post.TeamId) == 0 {
 		if _, err := transaction.Exec("UPDATE Users SET Points = Points - :PointForFlagged, UpdateAt = :UpdateAt WHERE Id = :Id", map[string]interface{}{"PointForFlagged": model.USER_POINT_FOR_FLAGGED, "UpdateAt": curTime, "Id": post.UserId}); err != nil {
-			return model.NewAppError("SqlPostStore.cancelFlagPost", "store.sql_post.cancelFlagPost.updating.app_error", nil, err.Error(), http.StatusInternalServerError)
+			return model.NewAppError("SqlPostStore.cancelFlagPost", "store.sql_post.cancelFlagPost.updating.app_error", nil, err.Error(), http.StatusInternalServerError).Wrap(err)
 		}
 	} else {
 		if _, err := transaction.Exec("UPDATE TeamMembers SET Points = Points - :PointForFlagged WHERE TeamId = :TeamId AND UserId = :UserId AND DeleteAt = 0", map[string]interface{}{"PointForFlagged": model.USER_POINT_FOR_FLAGGED, "TeamId": post.TeamId, "UserId": post.UserId}); err != nil {
-			return model.NewAppError("SqlPostStore.cancelFlagPost", "store.sql_post.cancelFlagPost.updating.app_error", nil, err.Error(), http.StatusInternalServerError)
+			return model.NewAppError("SqlPostStore.cancelFlagPost", "store.sql_post.cancelFlagPost.updating.app_error", nil, err.Error(), http.StatusInternalServerError).Wrap(err)
 		}
 	}
 
@@ -1810,7 +1956,9 @@ func (s *SqlPostStore) cancelFlagPost(transaction *gorp.Transaction, flag *model
 		Points:   -(model.USER_POINT_FOR_FLAGGED),
 		CreateAt: curTime,
 	}
-	s.SaveUserPointHistory(user_point_history)
+	if _, err := s.saveUserPointHistory(transaction, user_point_history); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -1927,6 +2075,34 @@ func (s *SqlPostStore) SavePostViewsHistory(postId string, teamId string, userId
 	return post_views_history, nil
 }
 
+func (s *SqlPostStore) FlushBufferedViews(limit int) (int, *model.AppError) {
+	return 0, nil
+}
+
+// バッファを持たない場合は、書き出された閲覧数を日ごとに合計する
+func (s *SqlPostStore) GetDailyUniqueViews(postId string, days int) (model.Analytics, *model.AppError) {
+	start := utils.MillisFromTime(utils.StartOfDay(time.Now().AddDate(0, 0, -(days - 1))))
+
+	var rows model.Analytics
+	if _, err := s.GetReplica().Select(&rows, `
+		SELECT
+			`+dateFromMillisExpr(s.DriverName(), "CreateAt")+` AS Name,
+			SUM(ViewsCount) AS Value
+		FROM
+			PostViewsHistory
+		WHERE
+			PostId = :PostId
+			AND CreateAt >= :StartTime
+		GROUP BY
+			`+dateFromMillisExpr(s.DriverName(), "CreateAt")+`
+		ORDER BY
+			Name DESC`, map[string]interface{}{"PostId": postId, "StartTime": start}); err != nil {
+		return nil, model.NewAppError("SqlPostStore.GetDailyUniqueViews", "store.sql_post.get_daily_unique_views.app_error", nil, "post_id="+postId+", "+err.Error(), http.StatusInternalServerError)
+	}
+
+	return rows, nil
+}
+
 func (s *SqlPostStore) RelatedSearch(term string, limit int) ([]*model.RelatedPostSearchResult, *model.AppError) {
 	return nil, nil
 }
@@ -2039,7 +2215,7 @@ func (s *SqlPostStore) GetAnsweredRate(teamId string) (float64, *model.AppError)
 func (s *SqlPostStore) AnalyticsPostCounts(teamId string) (model.Analytics, *model.AppError) {
 	query :=
 		`SELECT
-		        DATE(FROM_UNIXTIME(Posts.CreateAt / 1000)) AS Name,
+		        ` + dateFromMillisExpr(s.DriverName(), "Posts.CreateAt") + ` AS Name,
 		        COUNT(Posts.Id) AS Value
 		    FROM Posts`
 
@@ -2051,7 +2227,7 @@ func (s *SqlPostStore) AnalyticsPostCounts(teamId string) (model.Analytics, *mod
 
 	query += ` Posts.CreateAt <= :EndTime
 		            AND Posts.CreateAt >= :StartTime
-		GROUP BY DATE(FROM_UNIXTIME(Posts.CreateAt / 1000))
+		GROUP BY ` + dateFromMillisExpr(s.DriverName(), "Posts.CreateAt") + `
 		ORDER BY Name DESC
 		LIMIT 30`
 
@@ -2073,7 +2249,7 @@ func (s *SqlPostStore) AnalyticsPostCounts(teamId string) (model.Analytics, *mod
 func (s *SqlPostStore) AnalyticsActiveAuthorCounts(teamId string) (model.Analytics, *model.AppError) {
 	query :=
 		`SELECT
-		        DATE(FROM_UNIXTIME(Posts.CreateAt / 1000)) AS Name,
+		        ` + dateFromMillisExpr(s.DriverName(), "Posts.CreateAt") + ` AS Name,
 		        COUNT(DISTINCT Posts.UserId) AS Value
 		    FROM Posts`
 
@@ -2085,7 +2261,7 @@ func (s *SqlPostStore) AnalyticsActiveAuthorCounts(teamId string) (model.Analyti
 
 	query += ` Posts.CreateAt <= :EndTime
 		            AND Posts.CreateAt >= :StartTime
-		GROUP BY DATE(FROM_UNIXTIME(Posts.CreateAt / 1000))
+		GROUP BY ` + dateFromMillisExpr(s.DriverName(), "Posts.CreateAt") + `
 		ORDER BY Name DESC
 		LIMIT 30`
 
@@ -2103,3 +2279,149 @@ func (s *SqlPostStore) AnalyticsActiveAuthorCounts(teamId string) (model.Analyti
 
 	return rows, nil
 }
+
+// 削除済みの投稿と、親が削除済みの投稿は移行対象外とする
+func (s *SqlPostStore) GetPostsForExport(teamId string, postType string, afterId string, limit int) ([]*model.Post, *model.AppError) {
+	parentFilter := ""
+	if postType != model.POST_TYPE_QUESTION {
+		parentFilter = "AND EXISTS (SELECT 1 FROM Posts Parent WHERE Parent.Id = Posts.ParentId AND Parent.DeleteAt = 0)"
+	}
+
+	var posts []*model.Post
+	if _, err := s.GetReplica().Select(&posts, `
+		SELECT
+			*
+		FROM
+			Posts
+		WHERE
+			TeamId = :TeamId
+			AND Type = :Type
+			AND OriginalId = ''
+			AND DeleteAt = 0
+			AND Id > :AfterId
+			`+parentFilter+`
+		ORDER BY
+			Id ASC
+		LIMIT :Limit`, map[string]interface{}{"TeamId": teamId, "Type": postType, "AfterId": afterId, "Limit": limit}); err != nil {
+		return nil, model.NewAppError("SqlPostStore.GetPostsForExport", "store.sql_post.get_posts_for_export.app_error", nil, "team_id="+teamId+", "+err.Error(), http.StatusInternalServerError)
+	}
+
+	return posts, nil
+}
+
+func (s *SqlPostStore) GetRevisionsForExport(teamId string, afterId string, limit int) ([]*model.Post, *model.AppError) {
+	var posts []*model.Post
+	if _, err := s.GetReplica().Select(&posts, `
+		SELECT
+			Revisions.*
+		FROM
+			Posts Revisions
+		INNER JOIN
+			Posts ON Posts.Id = Revisions.OriginalId
+		WHERE
+			Revisions.TeamId = :TeamId
+			AND Revisions.OriginalId != ''
+			AND Revisions.Id > :AfterId
+			AND Posts.DeleteAt = 0
+		ORDER BY
+			Revisions.Id ASC
+		LIMIT :Limit`, map[string]interface{}{"TeamId": teamId, "AfterId": afterId, "Limit": limit}); err != nil {
+		return nil, model.NewAppError("SqlPostStore.GetRevisionsForExport", "store.sql_post.get_revisions_for_export.app_error", nil, "team_id="+teamId+", "+err.Error(), http.StatusInternalServerError)
+	}
+
+	return posts, nil
+}
+
+type postCountersRow struct {
+	Id                  string
+	AnswerCount         int64
+	UpVotes             int64
+	DownVotes           int64
+	Points              int64
+	FlagCount           int64
+	Views               int64
+	ExpectedAnswerCount int64
+	ExpectedUpVotes     int64
+	ExpectedDownVotes   int64
+	ExpectedFlagCount   int64
+	ExpectedViews       int64
+	Imported            int
+}
+
+// 投稿のカウンタを、Votes・子の投稿・PostViewsHistoryから数え直した値と比べる。
+// importedPropKeyのPropsを持つ投稿は投票者が分からないので、投票数は照合しない。
+func (s *SqlPostStore) GetCounterDrifts(after *model.CounterCursor, limit int, importedPropKey string) (*model.CounterDriftBatch, *model.AppError) {
+	afterId := ""
+	if after != nil {
+		afterId = after.Key
+	}
+
+	params := map[string]interface{}{
+		"AfterId":  afterId,
+		"Limit":    limit,
+		"Answer":   model.POST_TYPE_ANSWER,
+		"UpVote":   model.VOTE_TYPE_UP_VOTE,
+		"DownVote": model.VOTE_TYPE_DOWN_VOTE,
+		"Flag":     model.VOTE_TYPE_FLAG,
+	}
+	imported := importedPropsCondition("p.Props", importedPropKey, params)
+
+	var rows []*postCountersRow
+	if _, err := s.GetReplica().Select(&rows, `
+		SELECT
+			p.Id, p.AnswerCount, p.UpVotes, p.DownVotes, p.Points, p.FlagCount, p.Views,
+			(SELECT COUNT(*) FROM Posts a WHERE a.ParentId = p.Id AND a.Type = :Answer AND a.OriginalId = '' AND a.DeleteAt = 0) AS ExpectedAnswerCount,
+			(SELECT COUNT(*) FROM Votes v WHERE v.PostId = p.Id AND v.Type = :UpVote) AS ExpectedUpVotes,
+			(SELECT COUNT(*) FROM Votes v WHERE v.PostId = p.Id AND v.Type = :DownVote) AS ExpectedDownVotes,
+			(SELECT COUNT(*) FROM Votes v WHERE v.PostId = p.Id AND v.Type = :Flag) AS ExpectedFlagCount,
+			(SELECT COALESCE(SUM(h.ViewsCount), 0) FROM PostViewsHistory h WHERE h.PostId = p.Id) AS ExpectedViews,
+			CASE WHEN `+imported+` THEN 1 ELSE 0 END AS Imported
+		FROM
+			Posts p
+		WHERE
+			p.Id > :AfterId
+			AND p.OriginalId = ''
+			AND p.DeleteAt = 0
+		ORDER BY
+			p.Id ASC
+		LIMIT :Limit`, params); err != nil {
+		return nil, model.NewAppError("SqlPostStore.GetCounterDrifts", "store.sql_post.get_counter_drifts.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	batch := &model.CounterDriftBatch{Checked: len(rows)}
+	for _, row := range rows {
+		drifts := appendCounterDrift(batch.Drifts, model.COUNTER_TABLE_POSTS, "", row.Id, model.COUNTER_COLUMN_ANSWER_COUNT, row.AnswerCount, row.ExpectedAnswerCount)
+
+		expectedUpVotes, expectedDownVotes := row.UpVotes, row.DownVotes
+		if row.Imported == 0 {
+			expectedUpVotes, expectedDownVotes = row.ExpectedUpVotes, row.ExpectedDownVotes
+		}
+		drifts = appendCounterDrift(drifts, model.COUNTER_TABLE_POSTS, "", row.Id, model.COUNTER_COLUMN_UP_VOTES, row.UpVotes, expectedUpVotes)
+		drifts = appendCounterDrift(drifts, model.COUNTER_TABLE_POSTS, "", row.Id, model.COUNTER_COLUMN_DOWN_VOTES, row.DownVotes, expectedDownVotes)
+		drifts = appendCounterDrift(drifts, model.COUNTER_TABLE_POSTS, "", row.Id, model.COUNTER_COLUMN_POINTS, row.Points, expectedUpVotes-expectedDownVotes)
+
+		drifts = appendCounterDrift(drifts, model.COUNTER_TABLE_POSTS, "", row.Id, model.COUNTER_COLUMN_FLAG_COUNT, row.FlagCount, row.ExpectedFlagCount)
+		batch.Drifts = appendCounterDrift(drifts, model.COUNTER_TABLE_POSTS, "", row.Id, model.COUNTER_COLUMN_VIEWS, row.Views, row.ExpectedViews)
+	}
+
+	if len(rows) == limit {
+		batch.Next = &model.CounterCursor{Key: rows[len(rows)-1].Id}
+	}
+
+	return batch, nil
+}
+
+func (s *SqlPostStore) FixCounterDrift(drift *model.CounterDrift) (bool, *model.AppError) {
+	switch drift.Column {
+	case model.COUNTER_COLUMN_ANSWER_COUNT, model.COUNTER_COLUMN_UP_VOTES, model.COUNTER_COLUMN_DOWN_VOTES, model.COUNTER_COLUMN_POINTS, model.COUNTER_COLUMN_FLAG_COUNT, model.COUNTER_COLUMN_VIEWS:
+	default:
+		return false, model.NewAppError("SqlPostStore.FixCounterDrift", "store.sql_post.fix_counter_drift.column.app_error", nil, "column="+drift.Column, http.StatusBadRequest)
+	}
+
+	fixed, err := updateCounterIfUnchanged(s.GetMaster(), "Posts", "Id = :Id", map[string]interface{}{"Id": drift.Key}, drift)
+	if err != nil {
+		return false, model.NewAppError("SqlPostStore.FixCounterDrift", "store.sql_post.fix_counter_drift.app_error", nil, "id="+drift.Key+", "+err.Error(), http.StatusInternalServerError)
+	}
+
+	return fixed, nil
+}
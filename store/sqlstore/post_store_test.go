@@ -0,0 +1,87 @@
+package sqlstore
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/go-gorp/gorp"
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 投票の取り消しがデッドロックでやり直されても、投票数を二重に減らさないこと
+func TestCancelVotePostRetry(t *testing.T) {
+	ss := setupSqlSupplier(t)
+	defer ss.Close()
+	ss.DropAllTables()
+
+	ps := ss.Post().(*SqlPostStore)
+
+	team, err := ss.Team().Save(&model.Team{Name: "z" + model.NewId(), Email: model.NewId() + "@localhost", Type: model.TEAM_TYPE_PUBLIC})
+	require.Nil(t, err)
+
+	makeMember := func() *model.User {
+		user, err := ss.User().Save(&model.User{Email: model.NewId() + "@localhost", Type: model.USER_TYPE_NORMAL})
+		require.Nil(t, err)
+		_, err = ss.Team().SaveMember(&model.TeamMember{TeamId: team.Id, UserId: user.Id, Type: model.TEAM_MEMBER_TYPE_NORMAL}, -1)
+		require.Nil(t, err)
+		return user
+	}
+
+	author := makeMember()
+	upVoter := makeMember()
+	downVoter := makeMember()
+	other := makeMember()
+
+	question, err := ps.SaveQuestion(&model.Post{Type: model.POST_TYPE_QUESTION, UserId: author.Id, TeamId: team.Id, Title: "question title", Content: "question content", Tags: "golang"})
+	require.Nil(t, err)
+
+	_, err = ps.UpVotePost(question.Id, upVoter.Id)
+	require.Nil(t, err)
+	_, err = ps.DownVotePost(question.Id, downVoter.Id)
+	require.Nil(t, err)
+	// 取得済みのpostの値から減らすと、やり直しのたびにずれる
+	_, err = ps.UpVotePost(question.Id, other.Id)
+	require.Nil(t, err)
+	_, err = ps.DownVotePost(question.Id, makeMember().Id)
+	require.Nil(t, err)
+
+	// 最初の試行だけ、取り消しの処理を終えた後にデッドロックで失敗させる
+	cancelWithRetry := func(voteType string, userId string, cancel func(*gorp.Transaction, *model.Vote, *model.Post, string) *model.AppError) int {
+		var vote *model.Vote
+		require.NoError(t, ss.GetMaster().SelectOne(&vote, "SELECT * FROM Votes WHERE PostId = :PostId AND Type = :Type AND UserId = :UserId", map[string]interface{}{"PostId": question.Id, "Type": voteType, "UserId": userId}))
+		post, appErr := ps.GetSingle(question.Id, false)
+		require.Nil(t, appErr)
+
+		attempts := 0
+		appErr = runInTransaction(ss, "TestCancelVotePostRetry", "store.sql_post.test", func(transaction *gorp.Transaction) *model.AppError {
+			attempts++
+			if appErr := cancel(transaction, vote, post, userId); appErr != nil {
+				return appErr
+			}
+			if attempts == 1 {
+				deadlock := &mysql.MySQLError{Number: mysqlErrDeadlock}
+				return model.NewAppError("TestCancelVotePostRetry", "store.sql_post.test.app_error", nil, deadlock.Error(), http.StatusInternalServerError).Wrap(deadlock)
+			}
+			return nil
+		})
+		require.Nil(t, appErr)
+
+		return attempts
+	}
+
+	assert.Equal(t, 2, cancelWithRetry(model.VOTE_TYPE_UP_VOTE, upVoter.Id, ps.cancelUpvotePost))
+	assert.Equal(t, 2, cancelWithRetry(model.VOTE_TYPE_DOWN_VOTE, downVoter.Id, ps.cancelDownvotePost))
+
+	got, err := ps.GetSingle(question.Id, false)
+	require.Nil(t, err)
+	assert.Equal(t, 1, got.UpVotes)
+	assert.Equal(t, 1, got.DownVotes)
+	assert.Equal(t, 0, got.Points)
+
+	member, err := ss.Team().GetMember(team.Id, author.Id)
+	require.Nil(t, err)
+	assert.Equal(t, model.USER_POINT_FOR_CREATE_QUESTION+model.USER_POINT_FOR_VOTED+model.USER_POINT_FOR_DOWN_VOTED, member.Points)
+}
@@ -27,7 +27,8 @@ func NewSqlPostViewsHistoryStore(sqlStore store.Store) store.PostViewsHistorySto
 }
 
 func (s *SqlPostViewsHistoryStore) GetViewsHistoryCount(teamId string, fromDate int64, toDate int64) (int64, *model.AppError) {
-	query := s.GetQueryBuilder().Select("COUNT(*)")
+	// 1行に複数の閲覧がまとめて書き出されるので、行数ではなくViewsCountを合計する
+	query := s.GetQueryBuilder().Select("COALESCE(SUM(ViewsCount), 0)")
 	query = query.From("PostViewsHistory h")
 
 	if teamId != "" {
@@ -66,8 +67,8 @@ func (s *SqlPostViewsHistoryStore) GetViewsHistoryCount(teamId string, fromDate
 func (s *SqlPostViewsHistoryStore) AnalyticsPostViewsHistoryCounts(teamId string) (model.Analytics, *model.AppError) {
 	query :=
 		`SELECT
-		        DATE(FROM_UNIXTIME(PostViewsHistory.CreateAt / 1000)) AS Name,
-		        COUNT(PostViewsHistory.Id) AS Value
+		        ` + dateFromMillisExpr(s.DriverName(), "PostViewsHistory.CreateAt") + ` AS Name,
+		        SUM(PostViewsHistory.ViewsCount) AS Value
 		    FROM PostViewsHistory`
 
 	if len(teamId) > 0 {
@@ -78,7 +79,7 @@ func (s *SqlPostViewsHistoryStore) AnalyticsPostViewsHistoryCounts(teamId string
 
 	query += ` PostViewsHistory.CreateAt <= :EndTime
 		            AND PostViewsHistory.CreateAt >= :StartTime
-		GROUP BY DATE(FROM_UNIXTIME(PostViewsHistory.CreateAt / 1000))
+		GROUP BY ` + dateFromMillisExpr(s.DriverName(), "PostViewsHistory.CreateAt") + `
 		ORDER BY Name DESC
 		LIMIT 30`
 
@@ -94,9 +95,5 @@ func (s *SqlPostViewsHistoryStore) AnalyticsPostViewsHistoryCounts(teamId string
 		return nil, model.NewAppError("SqlPostViewsHistoryStore.AnalyticsPostViewsHistoryCounts", "store.sql_post_views_history.analytics_post_views_history_counts.app_error", nil, err.Error(), http.StatusInternalServerError)
 	}
 
-	for _, row := range rows {
-		row.Value = row.Value * model.POST_COUNTER_MAX
-	}
-
 	return rows, nil
 }
@@ -0,0 +1,72 @@
+package sqlstore
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/go-sql-driver/mysql"
+)
+
+const (
+	mysqlQueryTimeoutParam    = "max_execution_time"
+	postgresQueryTimeoutParam = "statement_timeout"
+)
+
+// gorpはクエリごとのタイムアウトを持たないので、QueryTimeoutを接続時のセッション変数でDBに設定し、
+// 1文ごとにDB側で打ち切らせる。mysqlのmax_execution_timeはSELECTにだけ効く。
+// データソースで既に指定されていればそちらを使う
+func dataSourceWithQueryTimeout(driverName string, dataSource string, timeout time.Duration) (string, error) {
+	if timeout <= 0 {
+		return dataSource, nil
+	}
+
+	millis := strconv.FormatInt(int64(timeout/time.Millisecond), 10)
+
+	switch driverName {
+	case model.DATABASE_DRIVER_MYSQL:
+		config, err := mysql.ParseDSN(dataSource)
+		if err != nil {
+			return "", err
+		}
+
+		if _, ok := config.Params[mysqlQueryTimeoutParam]; ok {
+			return dataSource, nil
+		}
+
+		if config.Params == nil {
+			config.Params = map[string]string{}
+		}
+		config.Params[mysqlQueryTimeoutParam] = millis
+
+		return config.FormatDSN(), nil
+	case model.DATABASE_DRIVER_POSTGRES:
+		// lib/pqは知らないパラメータを起動時のランタイムパラメータとしてサーバーに送る
+		if strings.HasPrefix(dataSource, "postgres://") || strings.HasPrefix(dataSource, "postgresql://") {
+			u, err := url.Parse(dataSource)
+			if err != nil {
+				return "", err
+			}
+
+			query := u.Query()
+			if query.Get(postgresQueryTimeoutParam) != "" {
+				return dataSource, nil
+			}
+			query.Set(postgresQueryTimeoutParam, millis)
+			u.RawQuery = query.Encode()
+
+			return u.String(), nil
+		}
+
+		// key=value形式
+		if strings.Contains(dataSource, postgresQueryTimeoutParam+"=") {
+			return dataSource, nil
+		}
+
+		return strings.TrimSpace(dataSource + " " + postgresQueryTimeoutParam + "=" + millis), nil
+	}
+
+	return dataSource, nil
+}
@@ -0,0 +1,175 @@
+package sqlstore
+
+import (
+	"context"
+	dbsql "database/sql"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/clear-ness/qa-discussion/mlog"
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/go-gorp/gorp"
+)
+
+// 主側でpg_last_wal_receive_lsn()はNULLになるので、遅延0として扱う
+const postgresReplicaLagQuery = `
+	SELECT CASE
+		WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+		ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+	END`
+
+// レプリカごとの遅延を定期的に測り、MaxReplicaLagSecondsを超えたものや
+// 接続できないものをGetReplicaの対象から外す
+type replicaLagMonitor struct {
+	replicas   []*gorp.DbMap
+	driverName string
+	maxLag     time.Duration
+	interval   time.Duration
+	timeout    time.Duration
+
+	// replicasと同じ順で、1なら読み込みに使える
+	available []int32
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	stopped  chan struct{}
+}
+
+func newReplicaLagMonitor(replicas []*gorp.DbMap, settings *model.SqlSettings) *replicaLagMonitor {
+	m := &replicaLagMonitor{
+		replicas:   replicas,
+		driverName: *settings.DriverName,
+		maxLag:     time.Duration(*settings.MaxReplicaLagSeconds) * time.Second,
+		interval:   time.Duration(*settings.ReplicaLagCheckIntervalSeconds) * time.Second,
+		timeout:    time.Duration(*settings.QueryTimeout) * time.Second,
+		available:  make([]int32, len(replicas)),
+		stop:       make(chan struct{}),
+		stopped:    make(chan struct{}),
+	}
+
+	// 最初の計測が終わるまでは全て使う
+	for i := range m.available {
+		m.available[i] = 1
+	}
+
+	return m
+}
+
+func (m *replicaLagMonitor) start() {
+	go func() {
+		defer close(m.stopped)
+
+		m.check()
+
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				m.check()
+			case <-m.stop:
+				return
+			}
+		}
+	}()
+}
+
+func (m *replicaLagMonitor) close() {
+	m.stopOnce.Do(func() {
+		close(m.stop)
+		<-m.stopped
+	})
+}
+
+func (m *replicaLagMonitor) isAvailable(i int) bool {
+	return atomic.LoadInt32(&m.available[i]) == 1
+}
+
+func (m *replicaLagMonitor) check() {
+	for i, replica := range m.replicas {
+		lag, err := m.measure(replica)
+
+		var available int32
+		if err == nil && lag <= m.maxLag {
+			available = 1
+		}
+
+		if atomic.SwapInt32(&m.available[i], available) == available {
+			continue
+		}
+
+		if available == 1 {
+			mlog.Info("Replica is back in rotation", mlog.Int("replica", i), mlog.Duration("lag", lag))
+		} else {
+			mlog.Warn("Replica is lagging and removed from rotation", mlog.Int("replica", i), mlog.Duration("lag", lag), mlog.Duration("max_lag", m.maxLag), mlog.Err(err))
+		}
+	}
+}
+
+func (m *replicaLagMonitor) measure(replica *gorp.DbMap) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), m.timeout)
+	defer cancel()
+
+	if m.driverName == model.DATABASE_DRIVER_POSTGRES {
+		var seconds float64
+		if err := replica.Db.QueryRowContext(ctx, postgresReplicaLagQuery).Scan(&seconds); err != nil {
+			return 0, err
+		}
+
+		return time.Duration(seconds * float64(time.Second)), nil
+	}
+
+	return measureMySQLReplicaLag(ctx, replica.Db)
+}
+
+// SHOW SLAVE STATUSは列が多くバージョンでも変わるので、遅延の列だけを名前で探す
+func measureMySQLReplicaLag(ctx context.Context, db *dbsql.DB) (time.Duration, error) {
+	rows, err := db.QueryContext(ctx, "SHOW SLAVE STATUS")
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	// レプリケーションを設定していないサーバーは遅延しない
+	if !rows.Next() {
+		return 0, rows.Err()
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+
+	values := make([]dbsql.RawBytes, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return 0, err
+	}
+
+	for i, column := range columns {
+		if column != "Seconds_Behind_Master" && column != "Seconds_Behind_Source" {
+			continue
+		}
+
+		// レプリケーションが止まっているとNULLになる
+		if values[i] == nil {
+			return 0, errors.New("replication is not running")
+		}
+
+		seconds, err := strconv.ParseInt(string(values[i]), 10, 64)
+		if err != nil {
+			return 0, err
+		}
+
+		return time.Duration(seconds) * time.Second, nil
+	}
+
+	return 0, errors.New("replica lag column not found")
+}
@@ -0,0 +1,65 @@
+package sqlstore
+
+import (
+	"context"
+	dbsql "database/sql"
+	"testing"
+
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/clear-ness/qa-discussion/store"
+	"github.com/go-gorp/gorp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetReplica(t *testing.T) {
+	master := &gorp.DbMap{Db: &dbsql.DB{}}
+	replicas := []*gorp.DbMap{{Db: &dbsql.DB{}}, {Db: &dbsql.DB{}}}
+
+	supplier := &SqlSupplier{
+		rCounter:   new(int64),
+		master:     master,
+		replicas:   replicas,
+		settings:   &model.SqlSettings{DriverName: model.NewString(model.DATABASE_DRIVER_MYSQL)},
+		lagMonitor: &replicaLagMonitor{available: []int32{1, 1}},
+	}
+	supplier.initStores()
+
+	t.Run("round robin", func(t *testing.T) {
+		first := supplier.GetReplica()
+		second := supplier.GetReplica()
+		assert.NotSame(t, first, second)
+		assert.NotSame(t, master, first)
+		assert.NotSame(t, master, second)
+	})
+
+	t.Run("skip lagging replica", func(t *testing.T) {
+		supplier.lagMonitor.available[0] = 0
+		defer func() { supplier.lagMonitor.available[0] = 1 }()
+
+		for i := 0; i < 4; i++ {
+			assert.Same(t, replicas[1], supplier.GetReplica())
+		}
+	})
+
+	t.Run("all replicas lagging", func(t *testing.T) {
+		supplier.lagMonitor.available = []int32{0, 0}
+		defer func() { supplier.lagMonitor.available = []int32{1, 1} }()
+
+		assert.Same(t, master, supplier.GetReplica())
+	})
+
+	t.Run("master reads", func(t *testing.T) {
+		s := supplier.WithContext(context.Background())
+		assert.NotSame(t, master.Db, s.GetReplica().Db)
+
+		s = supplier.WithContext(store.WithMasterReads(context.Background()))
+		assert.Same(t, master.Db, s.GetReplica().Db)
+	})
+
+	t.Run("sub stores use the copy", func(t *testing.T) {
+		s := supplier.WithContext(context.Background())
+		post := s.Post().(*SqlPostStore)
+		assert.Same(t, s, post.Store)
+		assert.Same(t, supplier.Post().(*SqlPostStore).maxPostSize, post.maxPostSize)
+	})
+}
@@ -110,3 +110,21 @@ func (me SqlSessionStore) GetSessions(userId string) ([]*model.Session, *model.A
 	}
 	return sessions, nil
 }
+
+func (me SqlSessionStore) UpdateLastActivityAt(sessionId string, time int64) *model.AppError {
+	_, err := me.GetMaster().Exec("UPDATE Sessions SET LastActivityAt = :LastActivityAt WHERE Id = :Id", map[string]interface{}{"LastActivityAt": time, "Id": sessionId})
+	if err != nil {
+		return model.NewAppError("SqlSessionStore.UpdateLastActivityAt", "store.sql_session.update_last_activity.app_error", nil, "sessionId="+sessionId+", err="+err.Error(), http.StatusInternalServerError)
+	}
+
+	return nil
+}
+
+func (me SqlSessionStore) UpdateExpiresAt(sessionId string, time int64) *model.AppError {
+	_, err := me.GetMaster().Exec("UPDATE Sessions SET ExpiresAt = :ExpiresAt WHERE Id = :Id", map[string]interface{}{"ExpiresAt": time, "Id": sessionId})
+	if err != nil {
+		return model.NewAppError("SqlSessionStore.UpdateExpiresAt", "store.sql_session.update_expires_at.app_error", nil, "sessionId="+sessionId+", err="+err.Error(), http.StatusInternalServerError)
+	}
+
+	return nil
+}
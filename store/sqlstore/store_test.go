@@ -0,0 +1,33 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/clear-ness/qa-discussion/store/storetest"
+)
+
+// DBに接続できない環境ではテストをスキップする
+func setupSqlSupplier(t *testing.T) *SqlSupplier {
+	if testing.Short() {
+		t.Skip("skipping sql store tests in short mode")
+	}
+
+	settings := storetest.MakeSqlSettings()
+
+	// NewSqlSupplierは接続できないとプロセスを終了するので、先に確認する
+	db, err := sql.Open(*settings.DriverName, *settings.DataSource)
+	if err == nil {
+		err = db.Ping()
+		db.Close()
+	}
+	if err != nil {
+		t.Skipf("database is not available: %v", err)
+	}
+
+	return NewSqlSupplier(*settings, nil)
+}
+
+func TestSqlStore(t *testing.T) {
+	storetest.StoreTest(t, setupSqlSupplier(t))
+}
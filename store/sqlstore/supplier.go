@@ -14,9 +14,11 @@ import (
 	sq "github.com/Masterminds/squirrel"
 	"github.com/clear-ness/qa-discussion/mlog"
 	"github.com/clear-ness/qa-discussion/model"
+	"github.com/clear-ness/qa-discussion/services/metrics"
 	"github.com/clear-ness/qa-discussion/store"
 	"github.com/go-gorp/gorp"
 	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
 )
 
 const (
@@ -77,50 +79,39 @@ type SqlSupplierStores struct {
 	postViewsHistory    store.PostViewsHistoryStore
 	webhook             store.WebhookStore
 	webhooksHistory     store.WebhooksHistoryStore
+	webhookDelivery     store.WebhookDeliveryStore
+	incomingWebhook     store.IncomingWebhookStore
+	bot                 store.BotStore
 	audit               store.AuditStore
 	oauth               store.OAuthStore
 	status              store.StatusStore
+	job                 store.JobStore
+	system              store.SystemStore
 }
 
 type SqlSupplier struct {
-	rCounter       int64
-	master         *gorp.DbMap
-	replicas       []*gorp.DbMap
-	stores         SqlSupplierStores
-	settings       *model.SqlSettings
-	lockedToMaster bool
-}
-
-func NewSqlSupplier(settings model.SqlSettings) *SqlSupplier {
+	// WithContextで作った複製とも共有する
+	rCounter   *int64
+	master     *gorp.DbMap
+	replicas   []*gorp.DbMap
+	stores     SqlSupplierStores
+	settings   *model.SqlSettings
+	lagMonitor *replicaLagMonitor
+	metrics    metrics.MetricsInterface
+	// trueならGetReplicaもmasterを返す
+	readFromMaster bool
+}
+
+// metricsは計測が無効ならnil
+func NewSqlSupplier(settings model.SqlSettings, metrics metrics.MetricsInterface) *SqlSupplier {
 	supplier := &SqlSupplier{
-		rCounter: 0,
+		rCounter: new(int64),
 		settings: &settings,
+		metrics:  metrics,
 	}
 
 	supplier.initConnection()
-
-	supplier.stores.team = NewSqlTeamStore(supplier)
-	supplier.stores.teamMemberHistory = NewSqlTeamMemberHistoryStore(supplier)
-	supplier.stores.userGroup = NewSqlUserGroupStore(supplier)
-	supplier.stores.groupMemberHistory = NewSqlGroupMemberHistoryStore(supplier)
-	supplier.stores.collection = NewSqlCollectionStore(supplier)
-	supplier.stores.user = NewSqlUserStore(supplier)
-	supplier.stores.token = NewSqlTokenStore(supplier)
-	supplier.stores.session = NewSqlSessionStore(supplier)
-	supplier.stores.post = NewSqlPostStore(supplier)
-	supplier.stores.tag = NewSqlTagStore(supplier)
-	supplier.stores.vote = NewSqlVoteStore(supplier)
-	supplier.stores.userPointHistory = NewSqlUserPointHistoryStore(supplier)
-	supplier.stores.userFavoritePost = NewSqlUserFavoritePostStore(supplier)
-	supplier.stores.inboxMessage = NewSqlInboxMessageStore(supplier)
-	supplier.stores.fileInfo = NewSqlFileInfoStore(supplier)
-	supplier.stores.notificationSetting = NewSqlNotificationSettingStore(supplier)
-	supplier.stores.webhook = NewSqlWebhookStore(supplier)
-	supplier.stores.webhooksHistory = NewSqlWebhooksHistoryStore(supplier)
-	supplier.stores.postViewsHistory = NewSqlPostViewsHistoryStore(supplier)
-	supplier.stores.audit = NewSqlAuditStore(supplier)
-	supplier.stores.oauth = NewSqlOAuthStore(supplier)
-	supplier.stores.status = NewSqlStatusStore(supplier)
+	supplier.initStores()
 
 	return supplier
 }
@@ -215,6 +206,13 @@ func (me customConverter) FromDb(target interface{}) (gorp.CustomScanner, bool)
 }
 
 func setupConnection(con_type string, dataSource string, settings *model.SqlSettings) *gorp.DbMap {
+	dataSource, err := dataSourceWithQueryTimeout(*settings.DriverName, dataSource, time.Duration(*settings.QueryTimeout)*time.Second)
+	if err != nil {
+		mlog.Critical("Failed to parse SQL data source.", mlog.String("database", con_type), mlog.Err(err))
+		time.Sleep(time.Second)
+		os.Exit(EXIT_DB_OPEN)
+	}
+
 	db, err := dbsql.Open(*settings.DriverName, dataSource)
 	if err != nil {
 		mlog.Critical("Failed to open SQL connection to err.", mlog.Err(err))
@@ -247,10 +245,11 @@ func setupConnection(con_type string, dataSource string, settings *model.SqlSett
 
 	var dbmap *gorp.DbMap
 
-	//connectionTimeout := time.Duration(*settings.QueryTimeout) * time.Second
-
 	if *settings.DriverName == model.DATABASE_DRIVER_MYSQL {
 		dbmap = &gorp.DbMap{Db: db, TypeConverter: customConverter{}, Dialect: gorp.MySQLDialect{Engine: "InnoDB", Encoding: "UTF8MB4"}}
+	} else if *settings.DriverName == model.DATABASE_DRIVER_POSTGRES {
+		// migrationではテーブル名、カラム名をクォートせずに作るため、postgres上では小文字になっている
+		dbmap = &gorp.DbMap{Db: db, TypeConverter: customConverter{}, Dialect: gorp.PostgresDialect{LowercaseFields: true}}
 	} else {
 		mlog.Critical("Failed to create dialect specific driver")
 		time.Sleep(time.Second)
@@ -264,6 +263,148 @@ func setupConnection(con_type string, dataSource string, settings *model.SqlSett
 	return dbmap
 }
 
+func (s *SqlSupplier) initStores() {
+	s.stores.team = NewSqlTeamStore(s)
+	s.stores.teamMemberHistory = NewSqlTeamMemberHistoryStore(s)
+	s.stores.userGroup = NewSqlUserGroupStore(s)
+	s.stores.groupMemberHistory = NewSqlGroupMemberHistoryStore(s)
+	s.stores.collection = NewSqlCollectionStore(s)
+	s.stores.user = NewSqlUserStore(s)
+	s.stores.token = NewSqlTokenStore(s)
+	s.stores.session = NewSqlSessionStore(s)
+	s.stores.post = NewSqlPostStore(s)
+	s.stores.tag = NewSqlTagStore(s)
+	s.stores.vote = NewSqlVoteStore(s)
+	s.stores.userPointHistory = NewSqlUserPointHistoryStore(s)
+	s.stores.userFavoritePost = NewSqlUserFavoritePostStore(s)
+	s.stores.inboxMessage = NewSqlInboxMessageStore(s)
+	s.stores.fileInfo = NewSqlFileInfoStore(s)
+	s.stores.notificationSetting = NewSqlNotificationSettingStore(s)
+	s.stores.webhook = NewSqlWebhookStore(s)
+	s.stores.webhooksHistory = NewSqlWebhooksHistoryStore(s)
+	s.stores.webhookDelivery = NewSqlWebhookDeliveryStore(s)
+	s.stores.incomingWebhook = NewSqlIncomingWebhookStore(s)
+	s.stores.bot = NewSqlBotStore(s)
+	s.stores.postViewsHistory = NewSqlPostViewsHistoryStore(s)
+	s.stores.audit = NewSqlAuditStore(s)
+	s.stores.oauth = NewSqlOAuthStore(s)
+	s.stores.status = NewSqlStatusStore(s)
+	s.stores.job = NewSqlJobStore(s)
+	s.stores.system = NewSqlSystemStore(s)
+}
+
+// 登録済みのテーブルを使い、接続の取得先だけをこのstoreに差し替えたsub storeを持たせる。
+// sub storeのコンストラクタはテーブルを登録するので、リクエストごとには呼ばない
+func (s *SqlSupplier) rebindStores(stores SqlSupplierStores) {
+	team := *stores.team.(*SqlTeamStore)
+	team.Store = s
+	s.stores.team = &team
+
+	teamMemberHistory := *stores.teamMemberHistory.(*SqlTeamMemberHistoryStore)
+	teamMemberHistory.Store = s
+	s.stores.teamMemberHistory = &teamMemberHistory
+
+	userGroup := *stores.userGroup.(*SqlUserGroupStore)
+	userGroup.Store = s
+	s.stores.userGroup = &userGroup
+
+	groupMemberHistory := *stores.groupMemberHistory.(*SqlGroupMemberHistoryStore)
+	groupMemberHistory.Store = s
+	s.stores.groupMemberHistory = &groupMemberHistory
+
+	collection := *stores.collection.(*SqlCollectionStore)
+	collection.Store = s
+	s.stores.collection = &collection
+
+	user := *stores.user.(*SqlUserStore)
+	user.Store = s
+	s.stores.user = &user
+
+	token := *stores.token.(*SqlTokenStore)
+	token.Store = s
+	s.stores.token = &token
+
+	session := *stores.session.(*SqlSessionStore)
+	session.Store = s
+	s.stores.session = &session
+
+	post := *stores.post.(*SqlPostStore)
+	post.Store = s
+	s.stores.post = &post
+
+	tag := *stores.tag.(*SqlTagStore)
+	tag.Store = s
+	s.stores.tag = &tag
+
+	vote := *stores.vote.(*SqlVoteStore)
+	vote.Store = s
+	s.stores.vote = &vote
+
+	userPointHistory := *stores.userPointHistory.(*SqlUserPointHistoryStore)
+	userPointHistory.Store = s
+	s.stores.userPointHistory = &userPointHistory
+
+	userFavoritePost := *stores.userFavoritePost.(*SqlUserFavoritePostStore)
+	userFavoritePost.Store = s
+	s.stores.userFavoritePost = &userFavoritePost
+
+	inboxMessage := *stores.inboxMessage.(*SqlInboxMessageStore)
+	inboxMessage.Store = s
+	s.stores.inboxMessage = &inboxMessage
+
+	fileInfo := *stores.fileInfo.(*SqlFileInfoStore)
+	fileInfo.Store = s
+	s.stores.fileInfo = &fileInfo
+
+	notificationSetting := *stores.notificationSetting.(*SqlNotificationSettingStore)
+	notificationSetting.Store = s
+	s.stores.notificationSetting = &notificationSetting
+
+	webhook := *stores.webhook.(*SqlWebhookStore)
+	webhook.Store = s
+	s.stores.webhook = &webhook
+
+	webhooksHistory := *stores.webhooksHistory.(*SqlWebhooksHistoryStore)
+	webhooksHistory.Store = s
+	s.stores.webhooksHistory = &webhooksHistory
+
+	webhookDelivery := *stores.webhookDelivery.(*SqlWebhookDeliveryStore)
+	webhookDelivery.Store = s
+	s.stores.webhookDelivery = &webhookDelivery
+
+	incomingWebhook := *stores.incomingWebhook.(*SqlIncomingWebhookStore)
+	incomingWebhook.Store = s
+	s.stores.incomingWebhook = &incomingWebhook
+
+	bot := *stores.bot.(*SqlBotStore)
+	bot.Store = s
+	s.stores.bot = &bot
+
+	postViewsHistory := *stores.postViewsHistory.(*SqlPostViewsHistoryStore)
+	postViewsHistory.Store = s
+	s.stores.postViewsHistory = &postViewsHistory
+
+	audit := *stores.audit.(*SqlAuditStore)
+	audit.Store = s
+	s.stores.audit = &audit
+
+	oauth := *stores.oauth.(*SqlOAuthStore)
+	oauth.Store = s
+	s.stores.oauth = &oauth
+
+	status := *stores.status.(*SqlStatusStore)
+	status.Store = s
+	s.stores.status = &status
+
+	job := *stores.job.(*SqlJobStore)
+	job.Store = s
+	s.stores.job = &job
+
+	system := *stores.system.(*SqlSystemStore)
+	system.Store = s
+	s.stores.system = &system
+}
+
 func (s *SqlSupplier) initConnection() {
 	s.master = setupConnection("master", *s.settings.DataSource, s.settings)
 
@@ -272,6 +413,11 @@ func (s *SqlSupplier) initConnection() {
 		for i, replica := range s.settings.DataSourceReplicas {
 			s.replicas[i] = setupConnection(fmt.Sprintf("replica-%v", i), replica, s.settings)
 		}
+
+		if *s.settings.MaxReplicaLagSeconds > 0 {
+			s.lagMonitor = newReplicaLagMonitor(s.replicas, s.settings)
+			s.lagMonitor.start()
+		}
 	}
 }
 
@@ -284,12 +430,19 @@ func (ss *SqlSupplier) GetMaster() *gorp.DbMap {
 }
 
 func (ss *SqlSupplier) GetReplica() *gorp.DbMap {
-	if len(ss.settings.DataSourceReplicas) == 0 || ss.lockedToMaster {
+	if len(ss.replicas) == 0 || ss.readFromMaster {
 		return ss.GetMaster()
 	}
 
-	rrNum := atomic.AddInt64(&ss.rCounter, 1) % int64(len(ss.replicas))
-	return ss.replicas[rrNum]
+	// 遅延しているレプリカを飛ばし、全て遅延していればmasterを読む
+	for range ss.replicas {
+		rrNum := atomic.AddInt64(ss.rCounter, 1) % int64(len(ss.replicas))
+		if ss.lagMonitor == nil || ss.lagMonitor.isAvailable(int(rrNum)) {
+			return ss.replicas[rrNum]
+		}
+	}
+
+	return ss.GetMaster()
 }
 
 func (ss *SqlSupplier) TotalMasterDbConnections() int {
@@ -318,6 +471,9 @@ func (ss *SqlSupplier) GetAllConns() []*gorp.DbMap {
 
 func (ss *SqlSupplier) Close() {
 	mlog.Info("Closing SqlStore")
+	if ss.lagMonitor != nil {
+		ss.lagMonitor.close()
+	}
 	ss.master.Db.Close()
 	for _, replica := range ss.replicas {
 		replica.Db.Close()
@@ -328,6 +484,29 @@ func (ss *SqlSupplier) DropAllTables() {
 	ss.master.TruncateTables()
 }
 
+// 接続を共有したまま、クエリにctxを渡す複製を返す。
+// ctxがキャンセルされると実行中のクエリも中断される
+func (ss *SqlSupplier) WithContext(ctx context.Context) store.Store {
+	child := &SqlSupplier{
+		rCounter:   ss.rCounter,
+		master:     ss.master.WithContext(ctx).(*gorp.DbMap),
+		replicas:   make([]*gorp.DbMap, len(ss.replicas)),
+		settings:   ss.settings,
+		lagMonitor: ss.lagMonitor,
+		metrics:    ss.metrics,
+		// 書き込んだ直後のユーザーの読み込みはmasterから行う
+		readFromMaster: ss.readFromMaster || store.IsMasterReads(ctx),
+	}
+
+	for i, replica := range ss.replicas {
+		child.replicas[i] = replica.WithContext(ctx).(*gorp.DbMap)
+	}
+
+	child.rebindStores(ss.stores)
+
+	return child
+}
+
 func (ss *SqlSupplier) Team() store.TeamStore {
 	return ss.stores.team
 }
@@ -404,6 +583,18 @@ func (ss *SqlSupplier) WebhooksHistory() store.WebhooksHistoryStore {
 	return ss.stores.webhooksHistory
 }
 
+func (ss *SqlSupplier) WebhookDelivery() store.WebhookDeliveryStore {
+	return ss.stores.webhookDelivery
+}
+
+func (ss *SqlSupplier) IncomingWebhook() store.IncomingWebhookStore {
+	return ss.stores.incomingWebhook
+}
+
+func (ss *SqlSupplier) Bot() store.BotStore {
+	return ss.stores.bot
+}
+
 func (ss *SqlSupplier) Audit() store.AuditStore {
 	return ss.stores.audit
 }
@@ -416,6 +607,14 @@ func (ss *SqlSupplier) Status() store.StatusStore {
 	return ss.stores.status
 }
 
+func (ss *SqlSupplier) Job() store.JobStore {
+	return ss.stores.job
+}
+
+func (ss *SqlSupplier) System() store.SystemStore {
+	return ss.stores.system
+}
+
 type JSONSerializable interface {
 	ToJson() string
 }
@@ -427,6 +626,10 @@ func IsUniqueConstraintError(err error, indexName []string) bool {
 		unique = true
 	}
 
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+		unique = true
+	}
+
 	field := false
 	for _, contain := range indexName {
 		if strings.Contains(err.Error(), contain) {
@@ -440,5 +643,9 @@ func IsUniqueConstraintError(err error, indexName []string) bool {
 
 func (ss *SqlSupplier) GetQueryBuilder() sq.StatementBuilderType {
 	builder := sq.StatementBuilder.PlaceholderFormat(sq.Question)
+	if ss.DriverName() == model.DATABASE_DRIVER_POSTGRES {
+		builder = builder.PlaceholderFormat(sq.Dollar)
+	}
+
 	return builder
 }
@@ -0,0 +1,48 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/clear-ness/qa-discussion/store"
+)
+
+type SqlSystemStore struct {
+	store.Store
+}
+
+func NewSqlSystemStore(sqlStore store.Store) store.SystemStore {
+	s := &SqlSystemStore{
+		Store: sqlStore,
+	}
+
+	for _, db := range sqlStore.GetAllConns() {
+		db.AddTableWithName(model.System{}, "Systems").SetKeys(false, "Name")
+	}
+
+	return s
+}
+
+// 書き込んだ直後に読まれることが多いのでmasterから読む
+func (s SqlSystemStore) GetByName(name string) (*model.System, *model.AppError) {
+	var system model.System
+	if err := s.GetMaster().SelectOne(&system, "SELECT * FROM Systems WHERE Name = :Name", map[string]interface{}{"Name": name}); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.NewAppError("SqlSystemStore.GetByName", store.MISSING_SYSTEM_ERROR, nil, "name="+name, http.StatusNotFound)
+		}
+
+		return nil, model.NewAppError("SqlSystemStore.GetByName", "store.sql_system.get_by_name.app_error", nil, "name="+name+", "+err.Error(), http.StatusInternalServerError)
+	}
+
+	return &system, nil
+}
+
+func (s SqlSystemStore) SaveOrUpdate(system *model.System) *model.AppError {
+	query := "INSERT INTO Systems (Name, Value) VALUES (:Name, :Value)" + systemsUpsertClause(s.DriverName())
+	if _, err := s.GetMaster().Exec(query, map[string]interface{}{"Name": system.Name, "Value": system.Value}); err != nil {
+		return model.NewAppError("SqlSystemStore.SaveOrUpdate", "store.sql_system.save_or_update.app_error", nil, "name="+system.Name+", "+err.Error(), http.StatusInternalServerError)
+	}
+
+	return nil
+}
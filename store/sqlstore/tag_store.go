@@ -140,6 +140,26 @@ func (s *SqlTagStore) CreateTags(addedTags []string, time int64, teamId string,
 	return nil
 }
 
+// 投稿数は投稿を取り込む時に数えるので0で作る。既にあればそのまま
+func (s *SqlTagStore) ImportTag(tag *model.Tag) *model.AppError {
+	tag.PostCount = 0
+	tag.PreSave()
+	if err := tag.IsValid(); err != nil {
+		return err
+	}
+
+	sql, args, err := s.GetQueryBuilder().Insert("Tags").Columns(tagSliceColumns()...).Values(tagToSlice(tag)...).ToSql()
+	if err != nil {
+		return model.NewAppError("SqlTagStore.ImportTag", "store.sql_tag.import_tag.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	if _, err := s.GetMaster().Exec(sql+tagsInsertIgnoreClause(s.DriverName()), args...); err != nil {
+		return model.NewAppError("SqlTagStore.ImportTag", "store.sql_tag.import_tag.app_error", nil, "content="+tag.Content+", "+err.Error(), http.StatusInternalServerError)
+	}
+
+	return nil
+}
+
 func (s *SqlTagStore) buildInsertTagsQuery(addedTags []string, time int64, teamId string, tagType string, updateOnDuplicate bool) (string, []interface{}, error) {
 	query := s.GetQueryBuilder().Insert("Tags").Columns(tagSliceColumns()...)
 
@@ -167,8 +187,47 @@ func (s *SqlTagStore) buildInsertTagsQuery(addedTags []string, time int64, teamI
 	}
 
 	if updateOnDuplicate {
-		return sql + " ON DUPLICATE KEY UPDATE PostCount = VALUES(PostCount) + 1, UpdateAt = VALUES(UpdateAt)", args, nil
+		return sql + tagsUpsertClause(s.DriverName()), args, nil
 	} else {
 		return sql, args, nil
 	}
 }
+
+// 投稿に付いたタグ(Typeが空)を、TeamIdとContentの順に返す
+func (s *SqlTagStore) GetPostTagsAfter(after *model.CounterCursor, limit int) (model.Tags, *model.AppError) {
+	params := map[string]interface{}{"AfterTeamId": "", "AfterContent": "", "Limit": limit}
+	if after != nil {
+		params["AfterTeamId"] = after.TeamId
+		params["AfterContent"] = after.Key
+	}
+
+	var tags model.Tags
+	if _, err := s.GetReplica().Select(&tags, `
+		SELECT
+			*
+		FROM
+			Tags
+		WHERE
+			Type = ''
+			AND (TeamId > :AfterTeamId OR (TeamId = :AfterTeamId AND Content > :AfterContent))
+		ORDER BY
+			TeamId ASC, Content ASC
+		LIMIT :Limit`, params); err != nil {
+		return nil, model.NewAppError("SqlTagStore.GetPostTagsAfter", "store.sql_tag.get_post_tags_after.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	return tags, nil
+}
+
+func (s *SqlTagStore) FixCounterDrift(drift *model.CounterDrift) (bool, *model.AppError) {
+	if drift.Column != model.COUNTER_COLUMN_POST_COUNT {
+		return false, model.NewAppError("SqlTagStore.FixCounterDrift", "store.sql_tag.fix_counter_drift.column.app_error", nil, "column="+drift.Column, http.StatusBadRequest)
+	}
+
+	fixed, err := updateCounterIfUnchanged(s.GetMaster(), "Tags", "Content = :Content AND TeamId = :TeamId AND Type = ''", map[string]interface{}{"Content": drift.Key, "TeamId": drift.TeamId}, drift)
+	if err != nil {
+		return false, model.NewAppError("SqlTagStore.FixCounterDrift", "store.sql_tag.fix_counter_drift.app_error", nil, "content="+drift.Key+", "+err.Error(), http.StatusInternalServerError)
+	}
+
+	return fixed, nil
+}
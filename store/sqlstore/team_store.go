@@ -10,6 +10,7 @@ import (
 	sq "github.com/Masterminds/squirrel"
 	"github.com/clear-ness/qa-discussion/model"
 	"github.com/clear-ness/qa-discussion/store"
+	"github.com/clear-ness/qa-discussion/utils"
 	"github.com/go-gorp/gorp"
 )
 
@@ -200,7 +201,7 @@ func (s SqlTeamStore) SaveMultipleMembers(members []*model.TeamMember, maxUsersP
 	}
 
 	if _, err := s.GetMaster().Exec(sql, args...); err != nil {
-		if IsUniqueConstraintError(err, []string{"TeamId", "PRIMARY"}) {
+		if IsUniqueConstraintError(err, []string{"TeamId", "PRIMARY", "teammembers_pkey"}) {
 			return nil, model.NewAppError("SqlTeamStore.SaveMember", TEAM_MEMBER_EXISTS_ERROR, nil, err.Error(), http.StatusBadRequest)
 		}
 		return nil, model.NewAppError("SqlTeamStore.SaveMember", "store.sql_team.save_member.save.app_error", nil, err.Error(), http.StatusInternalServerError)
@@ -318,12 +319,25 @@ func (s SqlTeamStore) GetMembers(teamId string, offset int, limit int, teamMembe
 	query := s.membersQuery.
 		Where(sq.Eq{"TeamMembers.TeamId": teamId}).
 		Where(sq.Eq{"TeamMembers.DeleteAt": 0}).
-		Limit(uint64(limit)).
-		Offset(uint64(offset))
+		Limit(uint64(limit))
 
-	if teamMembersGetOptions == nil || teamMembersGetOptions.Sort == "" {
-		query = query.OrderBy("UserId")
+	keys := keyset{columns: []string{"TeamMembers.UserId"}}
+	var cursor *model.Cursor
+	if teamMembersGetOptions != nil {
+		if teamMembersGetOptions.Sort == model.TEAM_MEMBER_SORT_TYPE_USERNAME {
+			keys.columns = []string{"Users.Username", "TeamMembers.UserId"}
+		}
+		cursor = teamMembersGetOptions.Cursor
+	}
+
+	before := false
+	if cursor != nil {
+		query = query.Where(keys.where(cursorValues(cursor, false), cursor.Before))
+		before = cursor.Before
+	} else {
+		query = query.Offset(uint64(offset))
 	}
+	query = query.OrderBy(keys.orderBy(before))
 
 	if teamMembersGetOptions != nil {
 		if teamMembersGetOptions.Type == model.TEAM_MEMBER_TYPE_NORMAL || teamMembersGetOptions.Type == model.TEAM_MEMBER_TYPE_ADMIN {
@@ -337,10 +351,6 @@ func (s SqlTeamStore) GetMembers(teamId string, offset int, limit int, teamMembe
 		if teamMembersGetOptions.ExcludeDeletedUsers {
 			query = query.Where(sq.Eq{"Users.DeleteAt": 0})
 		}
-
-		if teamMembersGetOptions.Sort == model.TEAM_MEMBER_SORT_TYPE_USERNAME {
-			query = query.OrderBy(model.TEAM_MEMBER_SORT_TYPE_USERNAME)
-		}
 	}
 
 	queryString, args, err := query.ToSql()
@@ -354,6 +364,10 @@ func (s SqlTeamStore) GetMembers(teamId string, offset int, limit int, teamMembe
 		return nil, model.NewAppError("SqlTeamStore.GetMembers", "store.sql_team.get_members.app_error", nil, "teamId="+teamId+" "+err.Error(), http.StatusInternalServerError)
 	}
 
+	if before {
+		utils.ReverseSlice(members)
+	}
+
 	return members, nil
 }
 
@@ -413,6 +427,15 @@ func (s SqlTeamStore) AutocompletePublic(name string) ([]*model.Team, *model.App
 	return teams, nil
 }
 
+func (s SqlTeamStore) GetAllWithAllowedDomains() ([]*model.Team, *model.AppError) {
+	var teams []*model.Team
+	if _, err := s.GetReplica().Select(&teams, "SELECT * FROM Teams WHERE AllowedDomains != '' AND DeleteAt = 0"); err != nil {
+		return nil, model.NewAppError("SqlTeamStore.GetAllWithAllowedDomains", "store.sql_team.get_all_with_allowed_domains.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	return teams, nil
+}
+
 func (s SqlTeamStore) UpdateMultipleMembers(members []*model.TeamMember) ([]*model.TeamMember, *model.AppError) {
 	for _, member := range members {
 		if err := member.IsValid(); err != nil {
@@ -460,3 +483,56 @@ func (s SqlTeamStore) UpdateMember(member *model.TeamMember) (*model.TeamMember,
 
 	return updatedMembers[0], nil
 }
+
+// チーム内のポイントを、そのチームのUserPointHistoryの合計と比べる
+func (s SqlTeamStore) GetMemberPointsDrifts(after *model.CounterCursor, limit int, importedPropKey string) (*model.CounterDriftBatch, *model.AppError) {
+	params := map[string]interface{}{"AfterTeamId": "", "AfterUserId": "", "Limit": limit}
+	if after != nil {
+		params["AfterTeamId"] = after.TeamId
+		params["AfterUserId"] = after.Key
+	}
+	imported := importedPropsCondition("u.Props", importedPropKey, params)
+
+	var rows []*pointsRow
+	if _, err := s.GetReplica().Select(&rows, `
+		SELECT
+			tm.TeamId, tm.UserId, tm.Points,
+			(SELECT COALESCE(SUM(h.Points), 0) FROM UserPointHistory h WHERE h.UserId = tm.UserId AND h.TeamId = tm.TeamId) AS ExpectedPoints
+		FROM
+			TeamMembers tm
+			INNER JOIN Users u ON u.Id = tm.UserId
+		WHERE
+			(tm.TeamId > :AfterTeamId OR (tm.TeamId = :AfterTeamId AND tm.UserId > :AfterUserId))
+			AND tm.DeleteAt = 0
+			AND NOT (`+imported+`)
+		ORDER BY
+			tm.TeamId ASC, tm.UserId ASC
+		LIMIT :Limit`, params); err != nil {
+		return nil, model.NewAppError("SqlTeamStore.GetMemberPointsDrifts", "store.sql_team.get_member_points_drifts.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	batch := &model.CounterDriftBatch{Checked: len(rows)}
+	for _, row := range rows {
+		batch.Drifts = appendCounterDrift(batch.Drifts, model.COUNTER_TABLE_TEAM_MEMBERS, row.TeamId, row.UserId, model.COUNTER_COLUMN_POINTS, row.Points, row.ExpectedPoints)
+	}
+
+	if len(rows) == limit {
+		last := rows[len(rows)-1]
+		batch.Next = &model.CounterCursor{TeamId: last.TeamId, Key: last.UserId}
+	}
+
+	return batch, nil
+}
+
+func (s SqlTeamStore) FixMemberPointsDrift(drift *model.CounterDrift) (bool, *model.AppError) {
+	if drift.Column != model.COUNTER_COLUMN_POINTS {
+		return false, model.NewAppError("SqlTeamStore.FixMemberPointsDrift", "store.sql_team.fix_member_points_drift.column.app_error", nil, "column="+drift.Column, http.StatusBadRequest)
+	}
+
+	fixed, err := updateCounterIfUnchanged(s.GetMaster(), "TeamMembers", "TeamId = :TeamId AND UserId = :UserId AND DeleteAt = 0", map[string]interface{}{"TeamId": drift.TeamId, "UserId": drift.Key}, drift)
+	if err != nil {
+		return false, model.NewAppError("SqlTeamStore.FixMemberPointsDrift", "store.sql_team.fix_member_points_drift.app_error", nil, "team_id="+drift.TeamId+", user_id="+drift.Key+", "+err.Error(), http.StatusInternalServerError)
+	}
+
+	return fixed, nil
+}
@@ -0,0 +1,115 @@
+package sqlstore
+
+import (
+	"errors"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/clear-ness/qa-discussion/mlog"
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/clear-ness/qa-discussion/store"
+	"github.com/go-gorp/gorp"
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+)
+
+const (
+	TRANSACTION_MAX_ATTEMPTS    = 4
+	TRANSACTION_RETRY_BASE_WAIT = 20 * time.Millisecond
+)
+
+// やり直せば成功しうるエラー。
+// mysqlのロック待ちのタイムアウトは文だけがロールバックされるが、トランザクションごと捨ててやり直す
+const (
+	mysqlErrLockWaitTimeout = 1205
+	mysqlErrDeadlock        = 1213
+
+	pqErrSerializationFailure = "40001"
+	pqErrDeadlockDetected     = "40P01"
+)
+
+// 同じ投稿への投票が集中すると、Posts・Votes・Users・UserPointHistoryの行ロックの順序が
+// トランザクションごとに異なってデッドロックすることがある。
+// fをトランザクション内で実行してコミットし、デッドロックなどで失敗した場合は間隔を空けて最初からやり直す。
+// やり直すとfが再度呼ばれるので、fはトランザクションの外に副作用を残さないこと。
+// 開始・コミットの失敗は errIdPrefix + ".open_transaction.app_error" などのidで返す。
+func runInTransaction(s store.Store, where string, errIdPrefix string, f func(transaction *gorp.Transaction) *model.AppError) *model.AppError {
+	for attempt := 1; ; attempt++ {
+		appErr, retryable := runTransactionOnce(s, where, errIdPrefix, f)
+		if appErr == nil || !retryable {
+			return appErr
+		}
+
+		if attempt >= TRANSACTION_MAX_ATTEMPTS {
+			mlog.Warn("Transaction failed after retries", mlog.String("where", where), mlog.Int("attempts", attempt), mlog.Err(appErr))
+			if r, ok := s.(transactionRetryRecorder); ok {
+				r.recordTransactionRetriesExhausted(where)
+			}
+			return appErr
+		}
+
+		if r, ok := s.(transactionRetryRecorder); ok {
+			r.recordTransactionRetry(where)
+		}
+		time.Sleep(transactionRetryWait(attempt))
+	}
+}
+
+func runTransactionOnce(s store.Store, where string, errIdPrefix string, f func(transaction *gorp.Transaction) *model.AppError) (*model.AppError, bool) {
+	transaction, err := s.GetMaster().Begin()
+	if err != nil {
+		return model.NewAppError(where, errIdPrefix+".open_transaction.app_error", nil, err.Error(), http.StatusInternalServerError), false
+	}
+
+	defer finalizeTransaction(transaction)
+
+	if appErr := f(transaction); appErr != nil {
+		return appErr, isRetryableError(appErr)
+	}
+
+	if err := transaction.Commit(); err != nil {
+		return model.NewAppError(where, errIdPrefix+".commit_transaction.app_error", nil, err.Error(), http.StatusInternalServerError).Wrap(err), isRetryableError(err)
+	}
+
+	return nil, false
+}
+
+// 同時にやり直したトランザクションが再び衝突しないよう、待ち時間をばらつかせる
+func transactionRetryWait(attempt int) time.Duration {
+	max := TRANSACTION_RETRY_BASE_WAIT << uint(attempt-1)
+	return max/2 + time.Duration(rand.Int63n(int64(max/2)+1))
+}
+
+// トランザクション内の処理はドライバのエラーをAppErrorにWrapして返すので、
+// 包まれる前のドライバのエラーで判定する
+func isRetryableError(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == mysqlErrDeadlock || mysqlErr.Number == mysqlErrLockWaitTimeout
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == pqErrSerializationFailure || pqErr.Code == pqErrDeadlockDetected
+	}
+
+	return false
+}
+
+type transactionRetryRecorder interface {
+	recordTransactionRetry(where string)
+	recordTransactionRetriesExhausted(where string)
+}
+
+func (ss *SqlSupplier) recordTransactionRetry(where string) {
+	if ss.metrics != nil {
+		ss.metrics.IncrementStoreTransactionRetry(where)
+	}
+}
+
+func (ss *SqlSupplier) recordTransactionRetriesExhausted(where string) {
+	if ss.metrics != nil {
+		ss.metrics.IncrementStoreTransactionRetriesExhausted(where)
+	}
+}
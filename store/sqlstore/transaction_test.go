@@ -0,0 +1,53 @@
+package sqlstore
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsRetryableError(t *testing.T) {
+	assert.True(t, isRetryableError(&mysql.MySQLError{Number: mysqlErrDeadlock}))
+	assert.True(t, isRetryableError(&mysql.MySQLError{Number: mysqlErrLockWaitTimeout}))
+	assert.False(t, isRetryableError(&mysql.MySQLError{Number: 1062}))
+	assert.True(t, isRetryableError(&pq.Error{Code: pqErrDeadlockDetected}))
+	assert.True(t, isRetryableError(&pq.Error{Code: pqErrSerializationFailure}))
+	assert.False(t, isRetryableError(&pq.Error{Code: "23505"}))
+	assert.False(t, isRetryableError(errors.New("connection refused")))
+}
+
+func TestIsRetryableAppError(t *testing.T) {
+	appErr := func(err error) *model.AppError {
+		return model.NewAppError("SqlPostStore.upvotePost", "store.sql_post.upvotePost.updating.app_error", nil, err.Error(), http.StatusInternalServerError).Wrap(err)
+	}
+
+	assert.True(t, isRetryableError(appErr(&mysql.MySQLError{Number: mysqlErrDeadlock})))
+	assert.True(t, isRetryableError(appErr(&mysql.MySQLError{Number: mysqlErrLockWaitTimeout})))
+	assert.True(t, isRetryableError(appErr(&pq.Error{Code: pqErrDeadlockDetected})))
+	assert.True(t, isRetryableError(appErr(&pq.Error{Code: pqErrSerializationFailure})))
+	assert.False(t, isRetryableError(appErr(&mysql.MySQLError{Number: 1062})))
+
+	// 内部の関数のAppErrorをさらに包んでも判定できる
+	assert.True(t, isRetryableError(appErr(appErr(&mysql.MySQLError{Number: mysqlErrDeadlock}))))
+
+	// メッセージの書式ではなく、ドライバのエラーの種類で判定する
+	assert.False(t, isRetryableError(model.NewAppError("SqlPostStore.upvotePost", "store.sql_post.upvotePost.updating.app_error", nil, "Error 1213: Deadlock found", http.StatusInternalServerError)))
+}
+
+func TestTransactionRetryWait(t *testing.T) {
+	for attempt := 1; attempt < TRANSACTION_MAX_ATTEMPTS; attempt++ {
+		max := TRANSACTION_RETRY_BASE_WAIT << uint(attempt-1)
+		for i := 0; i < 10; i++ {
+			wait := transactionRetryWait(attempt)
+			assert.True(t, wait >= max/2 && wait <= max, "attempt=%d wait=%v", attempt, wait)
+		}
+	}
+
+	assert.True(t, transactionRetryWait(TRANSACTION_MAX_ATTEMPTS-1) <= 500*time.Millisecond)
+}
@@ -7,6 +7,7 @@ import (
 	sq "github.com/Masterminds/squirrel"
 	"github.com/clear-ness/qa-discussion/model"
 	"github.com/clear-ness/qa-discussion/store"
+	"github.com/clear-ness/qa-discussion/utils"
 )
 
 type SqlUserFavoritePostStore struct {
@@ -53,8 +54,8 @@ func (s *SqlUserFavoritePostStore) GetCountByPostId(postId string) (int64, *mode
 	return count, nil
 }
 
-func (s *SqlUserFavoritePostStore) GetUserFavoritePostsBeforeTime(time int64, userId string, page, perPage int, getCount bool, teamId string) ([]*model.UserFavoritePost, int64, *model.AppError) {
-	queryString, args, err := s.getUserFavoritePostsBeforeTime(time, userId, page, perPage, false, teamId).ToSql()
+func (s *SqlUserFavoritePostStore) GetUserFavoritePostsBeforeTime(time int64, userId string, page, perPage int, getCount bool, teamId string, cursor *model.Cursor) ([]*model.UserFavoritePost, int64, *model.AppError) {
+	queryString, args, err := s.getUserFavoritePostsBeforeTime(time, userId, page, perPage, false, teamId, cursor).ToSql()
 	if err != nil {
 		return nil, int64(0), model.NewAppError("SqlUserFavoritePostStore.GetUserFavoritePostsBeforeTime", "store.sql_user_favorite_post.get_user_favorite_posts_before_time.get.app_error", nil, "", http.StatusInternalServerError)
 	}
@@ -64,10 +65,14 @@ func (s *SqlUserFavoritePostStore) GetUserFavoritePostsBeforeTime(time int64, us
 		return nil, int64(0), model.NewAppError("SqlUserFavoritePostStore.GetUserFavoritePostsBeforeTime", "store.sql_user_favorite_post.get_user_favorite_posts_before_time.get.app_error", nil, "userId="+userId+", err="+err.Error(), http.StatusInternalServerError)
 	}
 
+	if cursor != nil && cursor.Before {
+		utils.ReverseSlice(favoritePosts)
+	}
+
 	var totalCount int64
 
 	if getCount {
-		queryString, args, err = s.getUserFavoritePostsBeforeTime(time, userId, page, perPage, true, teamId).ToSql()
+		queryString, args, err = s.getUserFavoritePostsBeforeTime(time, userId, page, perPage, true, teamId, nil).ToSql()
 		if err != nil {
 			return nil, int64(0), model.NewAppError("SqlUserFavoritePostStore.GetUserFavoritePostsBeforeTime", "store.sql_user_favorite_post.get_user_favorite_posts_before_time.get.app_error", nil, "", http.StatusInternalServerError)
 		}
@@ -81,7 +86,9 @@ func (s *SqlUserFavoritePostStore) GetUserFavoritePostsBeforeTime(time int64, us
 	return favoritePosts, totalCount, nil
 }
 
-func (s *SqlUserFavoritePostStore) getUserFavoritePostsBeforeTime(time int64, userId string, page, perPage int, countQuery bool, teamId string) sq.SelectBuilder {
+var favoritePostsKeyset = keyset{columns: []string{"CreateAt", "PostId"}, desc: true}
+
+func (s *SqlUserFavoritePostStore) getUserFavoritePostsBeforeTime(time int64, userId string, page, perPage int, countQuery bool, teamId string, cursor *model.Cursor) sq.SelectBuilder {
 	var selectStr string
 	if countQuery {
 		selectStr = "count(*)"
@@ -103,7 +110,14 @@ func (s *SqlUserFavoritePostStore) getUserFavoritePostsBeforeTime(time int64, us
 
 	if !countQuery {
 		offset := page * perPage
-		query = query.OrderBy("CreateAt DESC").
+		before := false
+		if cursor != nil {
+			query = query.Where(favoritePostsKeyset.where(cursorValues(cursor, true), cursor.Before))
+			before = cursor.Before
+			offset = 0
+		}
+
+		query = query.OrderBy(favoritePostsKeyset.orderBy(before)).
 			Limit(uint64(perPage)).
 			Offset(uint64(offset))
 	}
@@ -137,3 +151,25 @@ func (s *SqlUserFavoritePostStore) Delete(postId string, userId string) *model.A
 
 	return nil
 }
+
+func (s *SqlUserFavoritePostStore) GetForExport(teamId string, offset, limit int) ([]*model.UserFavoritePost, *model.AppError) {
+	var favorites []*model.UserFavoritePost
+	if _, err := s.GetReplica().Select(&favorites, `
+		SELECT
+			UserFavoritePosts.*
+		FROM
+			UserFavoritePosts
+		INNER JOIN
+			Posts ON Posts.Id = UserFavoritePosts.PostId
+		WHERE
+			UserFavoritePosts.TeamId = :TeamId
+			AND Posts.DeleteAt = 0
+		ORDER BY
+			UserFavoritePosts.CreateAt ASC, UserFavoritePosts.PostId ASC, UserFavoritePosts.UserId ASC
+		LIMIT :Limit
+		OFFSET :Offset`, map[string]interface{}{"TeamId": teamId, "Limit": limit, "Offset": offset}); err != nil {
+		return nil, model.NewAppError("SqlUserFavoritePostStore.GetForExport", "store.sql_user_favorite_post.get_for_export.app_error", nil, "team_id="+teamId+", "+err.Error(), http.StatusInternalServerError)
+	}
+
+	return favorites, nil
+}
@@ -13,6 +13,7 @@ import (
 	sq "github.com/Masterminds/squirrel"
 	"github.com/clear-ness/qa-discussion/model"
 	"github.com/clear-ness/qa-discussion/store"
+	"github.com/clear-ness/qa-discussion/utils"
 	"github.com/go-gorp/gorp"
 )
 
@@ -88,7 +89,7 @@ func (s SqlUserGroupStore) Save(group *model.UserGroup, maxGroupsPerTeam int64)
 	}
 
 	if err := s.GetMaster().Insert(group); err != nil {
-		if IsUniqueConstraintError(err, []string{"Name", "TeamId"}) {
+		if IsUniqueConstraintError(err, []string{"Name", "TeamId", "usergroups_name_teamid_key"}) {
 			return nil, model.NewAppError("SqlGroupStore.Save", "store.sql_group.save.exists.app_error", nil, err.Error(), http.StatusBadRequest)
 		}
 
@@ -236,9 +237,9 @@ func (s SqlUserGroupStore) buildLIKEClause(term string, searchColumns string) (l
 }
 
 func (s SqlUserGroupStore) GetGroups(teamId string, userId string, includeDeleted bool) (*model.UserGroupList, *model.AppError) {
-	query := "SELECT UserGroups.* FROM UserGroups, GroupMembers WHERE Id = GroupId AND UserId = :UserId AND DeleteAt = 0 AND TeamId = :TeamId ORDER BY Name"
+	query := "SELECT UserGroups.* FROM UserGroups, GroupMembers WHERE UserGroups.Id = GroupMembers.GroupId AND GroupMembers.UserId = :UserId AND UserGroups.DeleteAt = 0 AND UserGroups.TeamId = :TeamId ORDER BY UserGroups.Name"
 	if includeDeleted {
-		query = "SELECT UserGroups.* FROM UserGroups, GroupMembers WHERE UserGroups.Id = GroupMembers.GroupId AND GroupMembers.UserId = :UserId AND UserGroups.TeamId = :TeamId ORDER BY Name"
+		query = "SELECT UserGroups.* FROM UserGroups, GroupMembers WHERE UserGroups.Id = GroupMembers.GroupId AND GroupMembers.UserId = :UserId AND UserGroups.TeamId = :TeamId ORDER BY UserGroups.Name"
 	}
 
 	groups := &model.UserGroupList{}
@@ -309,7 +310,7 @@ func (s SqlUserGroupStore) Update(group *model.UserGroup) (*model.UserGroup, *mo
 
 	count, err := s.GetMaster().Update(group)
 	if err != nil {
-		if IsUniqueConstraintError(err, []string{"Name", "TeamId"}) {
+		if IsUniqueConstraintError(err, []string{"Name", "TeamId", "usergroups_name_teamid_key"}) {
 			return nil, model.NewAppError("SqlUserGroupStore.Update", "store.sql_group.update.uniq.app_error", nil, err.Error(), http.StatusBadRequest)
 		}
 
@@ -340,7 +341,9 @@ func (s SqlUserGroupStore) Delete(groupId string, time int64) *model.AppError {
 	return nil
 }
 
-func (s SqlUserGroupStore) GetMembers(groupId string, memberType string, offset, limit int) (*model.GroupMembers, *model.AppError) {
+var groupMembersKeyset = keyset{columns: []string{"GroupMembers.UserId"}}
+
+func (s SqlUserGroupStore) GetMembers(groupId string, memberType string, offset, limit int, cursor *model.Cursor) (*model.GroupMembers, *model.AppError) {
 	args := map[string]interface{}{}
 	args["GroupId"] = groupId
 	args["Limit"] = limit
@@ -352,6 +355,14 @@ func (s SqlUserGroupStore) GetMembers(groupId string, memberType string, offset,
 		args["MemberType"] = memberType
 	}
 
+	cursorClause := ""
+	before := false
+	if cursor != nil {
+		cursorClause = "AND " + groupMembersKeyset.namedWhere(cursorValues(cursor, false), cursor.Before, args)
+		before = cursor.Before
+		args["Offset"] = 0
+	}
+
 	members := &model.GroupMembers{}
 	_, err := s.GetReplica().Select(members, `
 		SELECT
@@ -364,12 +375,18 @@ func (s SqlUserGroupStore) GetMembers(groupId string, memberType string, offset,
 			UserGroups.DeleteAt = 0 AND
 			GroupMembers.GroupId = :GroupId
 			`+typeClause+`
+			`+cursorClause+`
+		ORDER BY `+groupMembersKeyset.orderBy(before)+`
 		LIMIT :Limit
 		OFFSET :Offset`, args)
 	if err != nil {
 		return nil, model.NewAppError("SqlUserGroupStore.GetMembers", "store.sql_group.get_members.app_error", nil, "group_id="+groupId+","+err.Error(), http.StatusInternalServerError)
 	}
 
+	if before {
+		utils.ReverseSlice(*members)
+	}
+
 	return members, nil
 }
 
@@ -45,6 +45,9 @@ func (us SqlUserStore) Save(user *model.User) (*model.User, *model.AppError) {
 		if IsUniqueConstraintError(err, []string{"Email", "users_email_key", "idx_users_email_unique"}) {
 			return nil, model.NewAppError("SqlUserStore.Save", "store.sql_user.save.email_exists.app_error", nil, "user_id="+user.Id+", "+err.Error(), http.StatusBadRequest)
 		}
+		if IsUniqueConstraintError(err, []string{"AuthData", "idx_users_auth_data_unique"}) {
+			return nil, model.NewAppError("SqlUserStore.Save", "store.sql_user.save.auth_data_exists.app_error", nil, "user_id="+user.Id+", "+err.Error(), http.StatusBadRequest)
+		}
 		return nil, model.NewAppError("SqlUserStore.Save", "store.sql_user.save.app_error", nil, "user_id="+user.Id+", "+err.Error(), http.StatusInternalServerError)
 	}
 
@@ -76,6 +79,8 @@ func (us SqlUserStore) Update(user *model.User, trustedUpdateData bool) (*model.
 	user.FailedAttempts = oldUser.FailedAttempts
 	user.Points = oldUser.Points
 	user.LastInboxMessageViewed = oldUser.LastInboxMessageViewed
+	user.AuthService = oldUser.AuthService
+	user.AuthData = oldUser.AuthData
 
 	if !trustedUpdateData {
 		user.Type = oldUser.Type
@@ -159,6 +164,104 @@ func (us SqlUserStore) GetByEmail(email string) (*model.User, *model.AppError) {
 	return &user, nil
 }
 
+func (us SqlUserStore) GetByUsername(username string) (*model.User, *model.AppError) {
+	query := us.usersQuery.Where("Username = ?", model.NormalizeUsername(username))
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return nil, model.NewAppError("SqlUserStore.GetByUsername", "store.sql_user.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	user := model.User{}
+	if err := us.GetReplica().SelectOne(&user, queryString, args...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.NewAppError("SqlUserStore.GetByUsername", store.MISSING_ACCOUNT_ERROR, nil, "username="+username+", "+err.Error(), http.StatusNotFound)
+		}
+		return nil, model.NewAppError("SqlUserStore.GetByUsername", "store.sql_user.get_by_username.app_error", nil, "username="+username+", "+err.Error(), http.StatusInternalServerError)
+	}
+
+	return &user, nil
+}
+
+// インポートした投稿・投票数からポイントを計算し直す。
+// teamIdが空の場合はUsers、それ以外はTeamMembersのポイントを更新する。
+func (us SqlUserStore) RecomputePoints(teamId string, userIds []string) *model.AppError {
+	if len(userIds) == 0 {
+		return nil
+	}
+
+	keys, params := MapStringsToQueryParams(userIds, "UserId")
+	params["TeamId"] = teamId
+	params["Question"] = model.POST_TYPE_QUESTION
+	params["Answer"] = model.POST_TYPE_ANSWER
+	params["CreateQuestion"] = model.USER_POINT_FOR_CREATE_QUESTION
+	params["CreateAnswer"] = model.USER_POINT_FOR_CREATE_ANSWER
+	params["SelectAnswer"] = model.USER_POINT_FOR_SELECT_ANSWER
+	params["SelectedAnswer"] = model.USER_POINT_FOR_SELECTED_ANSWER
+	params["Voted"] = model.USER_POINT_FOR_VOTED
+	params["DownVoted"] = model.USER_POINT_FOR_DOWN_VOTED
+	params["Flagged"] = model.USER_POINT_FOR_FLAGGED
+
+	points := `
+		(SELECT
+			COALESCE(SUM(
+				CASE WHEN p.Type = :Question THEN :CreateQuestion WHEN p.Type = :Answer THEN :CreateAnswer ELSE 0 END
+				+ CASE WHEN p.Type = :Question AND p.BestId != '' THEN :SelectAnswer ELSE 0 END
+				+ p.UpVotes * :Voted + p.DownVotes * :DownVoted + p.FlagCount * :Flagged
+			), 0)
+		FROM
+			Posts p
+		WHERE
+			p.UserId = %[1]s
+			AND p.TeamId = :TeamId
+			AND p.DeleteAt = 0)
+		+ (SELECT
+			COUNT(*) * :SelectedAnswer
+		FROM
+			Posts a
+			INNER JOIN Posts q ON q.BestId = a.Id
+		WHERE
+			a.UserId = %[1]s
+			AND a.TeamId = :TeamId
+			AND a.DeleteAt = 0
+			AND q.UserId != a.UserId)`
+
+	var query string
+	if len(teamId) == 0 {
+		query = "UPDATE Users SET Points = " + fmt.Sprintf(points, "Users.Id") + " WHERE Id IN " + keys
+	} else {
+		query = "UPDATE TeamMembers SET Points = " + fmt.Sprintf(points, "TeamMembers.UserId") + " WHERE TeamId = :TeamId AND UserId IN " + keys
+	}
+
+	if _, err := us.GetMaster().Exec(query, params); err != nil {
+		return model.NewAppError("SqlUserStore.RecomputePoints", "store.sql_user.recompute_points.app_error", nil, "team_id="+teamId+", "+err.Error(), http.StatusInternalServerError)
+	}
+
+	return nil
+}
+
+func (us SqlUserStore) GetByAuth(authData *string, authService string) (*model.User, *model.AppError) {
+	if authData == nil || *authData == "" {
+		return nil, model.NewAppError("SqlUserStore.GetByAuth", store.MISSING_AUTH_ACCOUNT_ERROR, nil, "authData='', authService="+authService, http.StatusBadRequest)
+	}
+
+	query := us.usersQuery.Where("AuthData = ?", authData).Where("AuthService = ?", authService)
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return nil, model.NewAppError("SqlUserStore.GetByAuth", "store.sql_user.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	user := model.User{}
+	if err := us.GetReplica().SelectOne(&user, queryString, args...); err == sql.ErrNoRows {
+		return nil, model.NewAppError("SqlUserStore.GetByAuth", store.MISSING_AUTH_ACCOUNT_ERROR, nil, "authData="+*authData+", authService="+authService, http.StatusBadRequest)
+	} else if err != nil {
+		return nil, model.NewAppError("SqlUserStore.GetByAuth", "store.sql_user.get_by_auth.other.app_error", nil, "authData="+*authData+", authService="+authService+", "+err.Error(), http.StatusInternalServerError)
+	}
+
+	return &user, nil
+}
+
 func (us SqlUserStore) GetUsersByDates(options *model.GetUsersOptions) ([]*model.User, *model.AppError) {
 	orderBy := "CreateAt DESC"
 	if options.SortType == "votes" {
@@ -180,6 +283,11 @@ func (us SqlUserStore) GetUsersByDates(options *model.GetUsersOptions) ([]*model
 		query = query.Where("CreateAt <= ?", options.ToDate)
 	}
 
+	// botはポイントを持たないので順位に含めない
+	if options.SortType == "votes" {
+		query = query.Where("Type != ?", model.USER_TYPE_BOT)
+	}
+
 	if options.SortType == "votes" && options.Min != nil {
 		query = query.Where("Points >= ?", *options.Min)
 	}
@@ -208,7 +316,7 @@ func (us SqlUserStore) GetUsersByDates(options *model.GetUsersOptions) ([]*model
 
 func (us SqlUserStore) GetForLogin(loginId string) (*model.User, *model.AppError) {
 	query := us.usersQuery
-	query = query.Where("Email = ?", loginId)
+	query = query.Where("Email = ?", loginId).Where("Type != ?", model.USER_TYPE_BOT)
 
 	queryString, args, err := query.ToSql()
 	if err != nil {
@@ -382,3 +490,85 @@ func (us SqlUserStore) UpdateLastPictureUpdate(userId string, time int64) *model
 
 	return nil
 }
+
+// チームに所属したことのあるユーザーをId順に返す(退会済みのメンバーも含む)
+func (us SqlUserStore) GetTeamUsersForExport(teamId string, afterId string, limit int) ([]*model.User, *model.AppError) {
+	var users []*model.User
+	if _, err := us.GetReplica().Select(&users, `
+		SELECT
+			Users.*
+		FROM
+			Users
+		INNER JOIN
+			TeamMembers ON TeamMembers.UserId = Users.Id
+		WHERE
+			TeamMembers.TeamId = :TeamId
+			AND Users.Id > :AfterId
+		ORDER BY
+			Users.Id ASC
+		LIMIT :Limit`, map[string]interface{}{"TeamId": teamId, "AfterId": afterId, "Limit": limit}); err != nil {
+		return nil, model.NewAppError("SqlUserStore.GetTeamUsersForExport", "store.sql_user.get_team_users_for_export.app_error", nil, "team_id="+teamId+", "+err.Error(), http.StatusInternalServerError)
+	}
+
+	return users, nil
+}
+
+type pointsRow struct {
+	TeamId         string
+	UserId         string
+	Points         int64
+	ExpectedPoints int64
+}
+
+// 公開サイトのポイントを、UserPointHistoryの合計と比べる。
+// importedPropKeyのPropsを持つユーザーは履歴無しでポイントを計算しているので照合しない。
+func (us SqlUserStore) GetPointsDrifts(after *model.CounterCursor, limit int, importedPropKey string) (*model.CounterDriftBatch, *model.AppError) {
+	afterId := ""
+	if after != nil {
+		afterId = after.Key
+	}
+
+	params := map[string]interface{}{"AfterId": afterId, "Limit": limit}
+	imported := importedPropsCondition("u.Props", importedPropKey, params)
+
+	var rows []*pointsRow
+	if _, err := us.GetReplica().Select(&rows, `
+		SELECT
+			u.Id AS UserId, u.Points,
+			(SELECT COALESCE(SUM(h.Points), 0) FROM UserPointHistory h WHERE h.UserId = u.Id AND h.TeamId = '') AS ExpectedPoints
+		FROM
+			Users u
+		WHERE
+			u.Id > :AfterId
+			AND u.DeleteAt = 0
+			AND NOT (`+imported+`)
+		ORDER BY
+			u.Id ASC
+		LIMIT :Limit`, params); err != nil {
+		return nil, model.NewAppError("SqlUserStore.GetPointsDrifts", "store.sql_user.get_points_drifts.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	batch := &model.CounterDriftBatch{Checked: len(rows)}
+	for _, row := range rows {
+		batch.Drifts = appendCounterDrift(batch.Drifts, model.COUNTER_TABLE_USERS, "", row.UserId, model.COUNTER_COLUMN_POINTS, row.Points, row.ExpectedPoints)
+	}
+
+	if len(rows) == limit {
+		batch.Next = &model.CounterCursor{Key: rows[len(rows)-1].UserId}
+	}
+
+	return batch, nil
+}
+
+func (us SqlUserStore) FixPointsDrift(drift *model.CounterDrift) (bool, *model.AppError) {
+	if drift.Column != model.COUNTER_COLUMN_POINTS {
+		return false, model.NewAppError("SqlUserStore.FixPointsDrift", "store.sql_user.fix_points_drift.column.app_error", nil, "column="+drift.Column, http.StatusBadRequest)
+	}
+
+	fixed, err := updateCounterIfUnchanged(us.GetMaster(), "Users", "Id = :Id", map[string]interface{}{"Id": drift.Key}, drift)
+	if err != nil {
+		return false, model.NewAppError("SqlUserStore.FixPointsDrift", "store.sql_user.fix_points_drift.app_error", nil, "user_id="+drift.Key+", "+err.Error(), http.StatusInternalServerError)
+	}
+
+	return fixed, nil
+}
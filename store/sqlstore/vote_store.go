@@ -2,7 +2,6 @@ package sqlstore
 
 import (
 	"database/sql"
-	"fmt"
 	"net/http"
 	"strings"
 
@@ -28,8 +27,8 @@ func NewSqlVoteStore(sqlStore store.Store) store.VoteStore {
 	return s
 }
 
-func (s *SqlVoteStore) GetVotesBeforeTime(time int64, userId string, page, perPage int, excludeFlag bool, getCount bool, teamId string) ([]*model.Vote, int64, *model.AppError) {
-	queryString, args, err := s.getVotesBeforeTime(time, userId, page, perPage, excludeFlag, false, teamId).ToSql()
+func (s *SqlVoteStore) GetVotesBeforeTime(time int64, userId string, page, perPage int, excludeFlag bool, getCount bool, teamId string, cursor *model.Cursor) ([]*model.Vote, int64, *model.AppError) {
+	queryString, args, err := s.getVotesBeforeTime(time, userId, page, perPage, excludeFlag, false, teamId, cursor).ToSql()
 	if err != nil {
 		return nil, int64(0), model.NewAppError("SqlPostStore.GetVotesBeforeTime", "store.sql_post.get_votes_before_time.get.app_error", nil, "", http.StatusInternalServerError)
 	}
@@ -40,10 +39,14 @@ func (s *SqlVoteStore) GetVotesBeforeTime(time int64, userId string, page, perPa
 		return nil, int64(0), model.NewAppError("SqlVoteStore.GetVotesBeforeTime", "store.sql_vote.get_votes_before_time.get.app_error", nil, "", http.StatusInternalServerError)
 	}
 
+	if cursor != nil && cursor.Before {
+		utils.ReverseSlice(votes)
+	}
+
 	var totalCount int64
 
 	if getCount {
-		queryString, args, err = s.getVotesBeforeTime(time, userId, page, perPage, excludeFlag, true, teamId).ToSql()
+		queryString, args, err = s.getVotesBeforeTime(time, userId, page, perPage, excludeFlag, true, teamId, nil).ToSql()
 		if err != nil {
 			return nil, int64(0), model.NewAppError("SqlVoteStore.GetVotesBeforeTime", "store.sql_vote.get_votes_before_time.get.app_error", nil, "", http.StatusInternalServerError)
 		}
@@ -57,7 +60,10 @@ func (s *SqlVoteStore) GetVotesBeforeTime(time int64, userId string, page, perPa
 	return votes, totalCount, nil
 }
 
-func (s *SqlVoteStore) getVotesBeforeTime(time int64, userId string, page, perPage int, excludeFlag bool, countQuery bool, teamId string) sq.SelectBuilder {
+// 同じ時刻の投票はPostIdとTypeで区別する
+var votesKeyset = keyset{columns: []string{"CreateAt", "PostId", "Type"}, desc: true}
+
+func (s *SqlVoteStore) getVotesBeforeTime(time int64, userId string, page, perPage int, excludeFlag bool, countQuery bool, teamId string, cursor *model.Cursor) sq.SelectBuilder {
 	var selectStr string
 	if countQuery {
 		selectStr = "count(*)"
@@ -94,7 +100,14 @@ func (s *SqlVoteStore) getVotesBeforeTime(time int64, userId string, page, perPa
 
 	if !countQuery {
 		offset := page * perPage
-		query = query.OrderBy("CreateAt DESC").
+		before := false
+		if cursor != nil {
+			query = query.Where(votesKeyset.where(cursorValues(cursor, true), cursor.Before))
+			before = cursor.Before
+			offset = 0
+		}
+
+		query = query.OrderBy(votesKeyset.orderBy(before)).
 			Limit(uint64(perPage)).
 			Offset(uint64(offset))
 	}
@@ -302,19 +315,15 @@ func (s *SqlVoteStore) searchReviews(options *model.SearchReviewsOptions, countQ
 	}
 
 	if options.Tagged != "" {
-		searchColumns := "Tags"
-		fulltextClause := fmt.Sprintf("MATCH(%s) AGAINST (? IN BOOLEAN MODE)", searchColumns)
-
-		splitTerms := []string{}
+		fulltext := fulltextTerms{}
 		for _, t := range strings.Fields(terms) {
 			if len(t) >= model.TAG_MIN_RUNES {
-				splitTerms = append(splitTerms, "+"+t)
+				fulltext.required = append(fulltext.required, t)
 			}
 		}
-		terms = strings.Join(splitTerms, " ")
 
 		query = query.Where(sq.And{
-			sq.Expr(fulltextClause, terms),
+			sq.Expr(fulltextClause(s.DriverName(), []string{"Tags"}, "?"), fulltext.query(s.DriverName())),
 		})
 	}
 
@@ -329,7 +338,7 @@ func (s *SqlVoteStore) searchReviews(options *model.SearchReviewsOptions, countQ
 func (s *SqlVoteStore) AnalyticsVoteCounts(teamId string, voteType string) (model.Analytics, *model.AppError) {
 	query :=
 		`SELECT
-		        DATE(FROM_UNIXTIME(Votes.CreateAt / 1000)) AS Name,
+		        ` + dateFromMillisExpr(s.DriverName(), "Votes.CreateAt") + ` AS Name,
 		        COUNT(Votes.PostId) AS Value
 		    FROM Votes`
 
@@ -345,7 +354,7 @@ func (s *SqlVoteStore) AnalyticsVoteCounts(teamId string, voteType string) (mode
 
 	query += ` Votes.CreateAt <= :EndTime
 		            AND Votes.CreateAt >= :StartTime
-		GROUP BY DATE(FROM_UNIXTIME(Votes.CreateAt / 1000))
+		GROUP BY ` + dateFromMillisExpr(s.DriverName(), "Votes.CreateAt") + `
 		ORDER BY Name DESC
 		LIMIT 30`
 
@@ -363,3 +372,34 @@ func (s *SqlVoteStore) AnalyticsVoteCounts(teamId string, voteType string) (mode
 
 	return rows, nil
 }
+
+// レビューやシステムの投票は移行対象外とし、ユーザーによる投票とフラグのみ返す
+func (s *SqlVoteStore) GetVotesForExport(teamId string, offset, limit int) ([]*model.Vote, *model.AppError) {
+	var votes []*model.Vote
+	if _, err := s.GetReplica().Select(&votes, `
+		SELECT
+			Votes.*
+		FROM
+			Votes
+		INNER JOIN
+			Posts ON Posts.Id = Votes.PostId
+		WHERE
+			Votes.TeamId = :TeamId
+			AND Votes.Type IN (:UpVote, :DownVote, :Flag)
+			AND Posts.DeleteAt = 0
+		ORDER BY
+			Votes.CreateAt ASC, Votes.PostId ASC, Votes.UserId ASC, Votes.Type ASC
+		LIMIT :Limit
+		OFFSET :Offset`, map[string]interface{}{
+		"TeamId":   teamId,
+		"UpVote":   model.VOTE_TYPE_UP_VOTE,
+		"DownVote": model.VOTE_TYPE_DOWN_VOTE,
+		"Flag":     model.VOTE_TYPE_FLAG,
+		"Limit":    limit,
+		"Offset":   offset,
+	}); err != nil {
+		return nil, model.NewAppError("SqlVoteStore.GetVotesForExport", "store.sql_vote.get_votes_for_export.app_error", nil, "team_id="+teamId+", "+err.Error(), http.StatusInternalServerError)
+	}
+
+	return votes, nil
+}
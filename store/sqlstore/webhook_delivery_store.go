@@ -0,0 +1,122 @@
+package sqlstore
+
+import (
+	"net/http"
+
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/clear-ness/qa-discussion/store"
+)
+
+type SqlWebhookDeliveryStore struct {
+	store.Store
+}
+
+func NewSqlWebhookDeliveryStore(sqlStore store.Store) store.WebhookDeliveryStore {
+	s := &SqlWebhookDeliveryStore{
+		Store: sqlStore,
+	}
+
+	for _, db := range sqlStore.GetAllConns() {
+		db.AddTableWithName(model.WebhookDelivery{}, "WebhookDeliveries").SetKeys(false, "Id")
+	}
+
+	return s
+}
+
+func (s SqlWebhookDeliveryStore) Save(delivery *model.WebhookDelivery) (*model.WebhookDelivery, *model.AppError) {
+	if len(delivery.Id) > 0 {
+		return nil, model.NewAppError("SqlWebhookDeliveryStore.Save", "store.sql_webhook_delivery.save.existing.app_error", nil, "id="+delivery.Id, http.StatusBadRequest)
+	}
+
+	delivery.PreSave()
+	if err := delivery.IsValid(); err != nil {
+		return nil, err
+	}
+
+	if err := s.GetMaster().Insert(delivery); err != nil {
+		return nil, model.NewAppError("SqlWebhookDeliveryStore.Save", "store.sql_webhook_delivery.save.app_error", nil, "id="+delivery.Id+", "+err.Error(), http.StatusInternalServerError)
+	}
+
+	return delivery, nil
+}
+
+// 送信時刻を過ぎた未完了のdeliveryを古い順に返す
+func (s SqlWebhookDeliveryStore) GetDue(now int64, limit int) ([]*model.WebhookDelivery, *model.AppError) {
+	var deliveries []*model.WebhookDelivery
+	if _, err := s.GetMaster().Select(&deliveries, `
+		SELECT
+			*
+		FROM
+			WebhookDeliveries
+		WHERE
+			Status = :Status
+			AND NextAttemptAt <= :Now
+		ORDER BY NextAttemptAt ASC
+		LIMIT :Limit`, map[string]interface{}{"Status": model.WEBHOOK_DELIVERY_STATUS_PENDING, "Now": now, "Limit": limit}); err != nil {
+		return nil, model.NewAppError("SqlWebhookDeliveryStore.GetDue", "store.sql_webhook_delivery.get_due.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	return deliveries, nil
+}
+
+// 複数サーバーが同じdeliveryを二重に送らないよう、NextAttemptAtを楽観的に書き換えて確保する。
+// 送信中にプロセスが落ちた場合はlockUntilを過ぎると再び取得対象になる。
+func (s SqlWebhookDeliveryStore) Claim(delivery *model.WebhookDelivery, lockUntil int64) (bool, *model.AppError) {
+	result, err := s.GetMaster().Exec(`
+		UPDATE
+			WebhookDeliveries
+		SET
+			NextAttemptAt = :LockUntil
+		WHERE
+			Id = :Id
+			AND Status = :Status
+			AND NextAttemptAt = :NextAttemptAt`, map[string]interface{}{
+		"LockUntil":     lockUntil,
+		"Id":            delivery.Id,
+		"Status":        model.WEBHOOK_DELIVERY_STATUS_PENDING,
+		"NextAttemptAt": delivery.NextAttemptAt,
+	})
+	if err != nil {
+		return false, model.NewAppError("SqlWebhookDeliveryStore.Claim", "store.sql_webhook_delivery.claim.app_error", nil, "id="+delivery.Id+", "+err.Error(), http.StatusInternalServerError)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, model.NewAppError("SqlWebhookDeliveryStore.Claim", "store.sql_webhook_delivery.claim.app_error", nil, "id="+delivery.Id+", "+err.Error(), http.StatusInternalServerError)
+	}
+
+	if rows != 1 {
+		return false, nil
+	}
+
+	delivery.NextAttemptAt = lockUntil
+	return true, nil
+}
+
+func (s SqlWebhookDeliveryStore) Update(delivery *model.WebhookDelivery) (*model.WebhookDelivery, *model.AppError) {
+	delivery.UpdateAt = model.GetMillis()
+	if err := delivery.IsValid(); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.GetMaster().Update(delivery); err != nil {
+		return nil, model.NewAppError("SqlWebhookDeliveryStore.Update", "store.sql_webhook_delivery.update.app_error", nil, "id="+delivery.Id+", "+err.Error(), http.StatusInternalServerError)
+	}
+
+	return delivery, nil
+}
+
+// 送信が終わった(成功・失敗が確定した)deliveryのみ削除し、再送待ちのものは残す
+func (s SqlWebhookDeliveryStore) PermanentDeleteFinishedBefore(time int64, limit int) (int64, *model.AppError) {
+	result, err := s.GetMaster().Exec(deleteWithLimitQuery(s.DriverName(), "WebhookDeliveries", "Status != :Status AND UpdateAt < :Time"), map[string]interface{}{"Status": model.WEBHOOK_DELIVERY_STATUS_PENDING, "Time": time, "Limit": limit})
+	if err != nil {
+		return 0, model.NewAppError("SqlWebhookDeliveryStore.PermanentDeleteFinishedBefore", "store.sql_webhook_delivery.permanent_delete_finished_before.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, model.NewAppError("SqlWebhookDeliveryStore.PermanentDeleteFinishedBefore", "store.sql_webhook_delivery.permanent_delete_finished_before.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	return rows, nil
+}
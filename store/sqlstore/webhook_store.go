@@ -1,6 +1,7 @@
 package sqlstore
 
 import (
+	"database/sql"
 	"net/http"
 
 	sq "github.com/Masterminds/squirrel"
@@ -74,6 +75,9 @@ func (s SqlWebhookStore) Save(webhook *model.Webhook) (*model.Webhook, *model.Ap
 func (s SqlWebhookStore) Get(id string) (*model.Webhook, *model.AppError) {
 	var webhook model.Webhook
 	if err := s.GetReplica().SelectOne(&webhook, "SELECT * FROM Webhooks WHERE Id = :Id AND DeleteAt = 0", map[string]interface{}{"Id": id}); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.NewAppError("SqlWebhookStore.Get", "store.sql_webhooks.get.missing.app_error", nil, "id="+id, http.StatusNotFound)
+		}
 		return nil, model.NewAppError("SqlWebhookStore.Get", "store.sql_webhooks.get.app_error", nil, "id="+id+", err="+err.Error(), http.StatusInternalServerError)
 	}
 
@@ -97,3 +101,33 @@ func (s SqlWebhookStore) Delete(webhookId string, time int64) *model.AppError {
 
 	return nil
 }
+
+func (s SqlWebhookStore) IncrementFailureCount(webhookId string) (int, *model.AppError) {
+	if _, err := s.GetMaster().Exec("UPDATE Webhooks SET FailureCount = FailureCount + 1 WHERE Id = :Id", map[string]interface{}{"Id": webhookId}); err != nil {
+		return 0, model.NewAppError("SqlWebhookStore.IncrementFailureCount", "store.sql_webhooks.increment_failure_count.app_error", nil, "id="+webhookId+", err="+err.Error(), http.StatusInternalServerError)
+	}
+
+	count, err := s.GetMaster().SelectInt("SELECT FailureCount FROM Webhooks WHERE Id = :Id", map[string]interface{}{"Id": webhookId})
+	if err != nil {
+		return 0, model.NewAppError("SqlWebhookStore.IncrementFailureCount", "store.sql_webhooks.increment_failure_count.app_error", nil, "id="+webhookId+", err="+err.Error(), http.StatusInternalServerError)
+	}
+
+	return int(count), nil
+}
+
+func (s SqlWebhookStore) ResetFailureCount(webhookId string) *model.AppError {
+	// 成功のたびに書き込みが発生しないよう、失敗が記録されている場合のみ更新する
+	if _, err := s.GetMaster().Exec("UPDATE Webhooks SET FailureCount = 0 WHERE Id = :Id AND FailureCount > 0", map[string]interface{}{"Id": webhookId}); err != nil {
+		return model.NewAppError("SqlWebhookStore.ResetFailureCount", "store.sql_webhooks.reset_failure_count.app_error", nil, "id="+webhookId+", err="+err.Error(), http.StatusInternalServerError)
+	}
+
+	return nil
+}
+
+func (s SqlWebhookStore) Disable(webhookId string, time int64) *model.AppError {
+	if _, err := s.GetMaster().Exec("UPDATE Webhooks SET DisabledAt = :DisabledAt, UpdateAt = :UpdateAt WHERE Id = :Id AND DisabledAt = 0", map[string]interface{}{"DisabledAt": time, "UpdateAt": time, "Id": webhookId}); err != nil {
+		return model.NewAppError("SqlWebhookStore.Disable", "store.sql_webhooks.disable.app_error", nil, "id="+webhookId+", err="+err.Error(), http.StatusInternalServerError)
+	}
+
+	return nil
+}
@@ -1,12 +1,15 @@
 package sqlstore
 
 import (
+	"database/sql"
 	"net/http"
 
+	sq "github.com/Masterminds/squirrel"
 	"github.com/pkg/errors"
 
 	"github.com/clear-ness/qa-discussion/model"
 	"github.com/clear-ness/qa-discussion/store"
+	"github.com/clear-ness/qa-discussion/utils"
 )
 
 type SqlWebhooksHistoryStore struct {
@@ -33,20 +36,85 @@ func (s SqlWebhooksHistoryStore) LogWebhookEvent(history *model.WebhooksHistory)
 	return nil
 }
 
-func (s SqlWebhooksHistoryStore) GetWebhooksHistoriesPage(teamId string, offset, limit int) ([]*model.WebhooksHistory, *model.AppError) {
-	var histories []*model.WebhooksHistory
-	_, err := s.GetReplica().Select(histories, `
-		SELECT
-			WebhooksHistory.*
-		FROM
-			WebhooksHistory
-		WHERE
-			WebhooksHistory.TeamId = :TeamId
-		LIMIT :Limit
-		OFFSET :Offset`, map[string]interface{}{"TeamId": teamId, "Limit": limit, "Offset": offset})
+func (s SqlWebhooksHistoryStore) Get(id string) (*model.WebhooksHistory, *model.AppError) {
+	var history model.WebhooksHistory
+	if err := s.GetReplica().SelectOne(&history, "SELECT * FROM WebhooksHistory WHERE Id = :Id", map[string]interface{}{"Id": id}); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.NewAppError("SqlWebhooksHistoryStore.Get", "store.sql_webhooks_history.get.missing.app_error", nil, "id="+id, http.StatusNotFound)
+		}
+		return nil, model.NewAppError("SqlWebhooksHistoryStore.Get", "store.sql_webhooks_history.get.app_error", nil, "id="+id+", "+err.Error(), http.StatusInternalServerError)
+	}
+
+	return &history, nil
+}
+
+var webhooksHistoryKeyset = keyset{columns: []string{"CreateAt", "Id"}, desc: true}
+
+func (s SqlWebhooksHistoryStore) Search(options *model.SearchWebhooksHistoryOptions) ([]*model.WebhooksHistory, *model.AppError) {
+	query := s.GetQueryBuilder().
+		Select("*").
+		From("WebhooksHistory").
+		Where(sq.Eq{"TeamId": options.TeamId})
+
+	if options.WebhookId != "" {
+		query = query.Where(sq.Eq{"WebhookId": options.WebhookId})
+	}
+	if options.PostId != "" {
+		query = query.Where(sq.Eq{"PostId": options.PostId})
+	}
+	if options.MinStatus != 0 {
+		query = query.Where(sq.GtOrEq{"ResponseStatus": options.MinStatus})
+	}
+	if options.MaxStatus != 0 {
+		query = query.Where(sq.LtOrEq{"ResponseStatus": options.MaxStatus})
+	}
+	if options.FromDate != 0 {
+		query = query.Where(sq.GtOrEq{"CreateAt": options.FromDate})
+	}
+	if options.ToDate != 0 {
+		query = query.Where(sq.LtOrEq{"CreateAt": options.ToDate})
+	}
+
+	offset := options.Page * options.PerPage
+	before := false
+	if options.Cursor != nil {
+		query = query.Where(webhooksHistoryKeyset.where(cursorValues(options.Cursor, true), options.Cursor.Before))
+		before = options.Cursor.Before
+		offset = 0
+	}
+
+	query = query.OrderBy(webhooksHistoryKeyset.orderBy(before)).
+		Limit(uint64(options.PerPage)).
+		Offset(uint64(offset))
+
+	queryString, args, err := query.ToSql()
 	if err != nil {
-		return nil, model.NewAppError("SqlWebhooksHistoryStore.GetWebhooksHistoriesPage", "store.sql_webhooks_history.get_page.app_error", nil, err.Error(), http.StatusInternalServerError)
+		return nil, model.NewAppError("SqlWebhooksHistoryStore.Search", "store.sql_webhooks_history.search.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	var histories []*model.WebhooksHistory
+	if _, err := s.GetReplica().Select(&histories, queryString, args...); err != nil {
+		return nil, model.NewAppError("SqlWebhooksHistoryStore.Search", "store.sql_webhooks_history.search.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	if before {
+		utils.ReverseSlice(histories)
 	}
 
 	return histories, nil
 }
+
+// 長時間ロックしないよう、limit件ずつ削除する
+func (s SqlWebhooksHistoryStore) PermanentDeleteBefore(time int64, limit int) (int64, *model.AppError) {
+	result, err := s.GetMaster().Exec(deleteWithLimitQuery(s.DriverName(), "WebhooksHistory", "CreateAt < :Time"), map[string]interface{}{"Time": time, "Limit": limit})
+	if err != nil {
+		return 0, model.NewAppError("SqlWebhooksHistoryStore.PermanentDeleteBefore", "store.sql_webhooks_history.permanent_delete_before.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, model.NewAppError("SqlWebhooksHistoryStore.PermanentDeleteBefore", "store.sql_webhooks_history.permanent_delete_before.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	return rows, nil
+}
@@ -1,6 +1,10 @@
+//go:generate go run layer_generators/main.go
+
 package store
 
 import (
+	"context"
+
 	sq "github.com/Masterminds/squirrel"
 	"github.com/clear-ness/qa-discussion/model"
 	"github.com/go-gorp/gorp"
@@ -14,8 +18,6 @@ type StoreResult struct {
 type Store interface {
 	DriverName() string
 	GetMaster() *gorp.DbMap
-	//LockToMaster()
-	//UnlockFromMaster()
 	GetReplica() *gorp.DbMap
 	TotalMasterDbConnections() int
 	TotalReadDbConnections() int
@@ -23,6 +25,8 @@ type Store interface {
 	GetAllConns() []*gorp.DbMap
 	GetQueryBuilder() sq.StatementBuilderType
 	DropAllTables()
+	// リクエストのcontextを下位レイヤーまで伝えたstoreを返す
+	WithContext(ctx context.Context) Store
 
 	Team() TeamStore
 	TeamMemberHistory() TeamMemberHistoryStore
@@ -43,9 +47,14 @@ type Store interface {
 	PostViewsHistory() PostViewsHistoryStore
 	Webhook() WebhookStore
 	WebhooksHistory() WebhooksHistoryStore
+	WebhookDelivery() WebhookDeliveryStore
+	IncomingWebhook() IncomingWebhookStore
+	Bot() BotStore
 	Audit() AuditStore
 	OAuth() OAuthStore
 	Status() StatusStore
+	Job() JobStore
+	System() SystemStore
 }
 
 type TeamStore interface {
@@ -67,6 +76,9 @@ type TeamStore interface {
 	RemoveAllMembersByTeam(teamId string) *model.AppError
 	UpdateLastTeamIconUpdate(teamId string, curTime int64) *model.AppError
 	AutocompletePublic(name string) ([]*model.Team, *model.AppError)
+	GetAllWithAllowedDomains() ([]*model.Team, *model.AppError)
+	GetMemberPointsDrifts(after *model.CounterCursor, limit int, importedPropKey string) (*model.CounterDriftBatch, *model.AppError)
+	FixMemberPointsDrift(drift *model.CounterDrift) (bool, *model.AppError)
 }
 
 type TeamMemberHistoryStore interface {
@@ -86,7 +98,7 @@ type UserGroupStore interface {
 	GetAllGroupMembersForUser(userId string) (map[string]string, *model.AppError)
 	Update(group *model.UserGroup) (*model.UserGroup, *model.AppError)
 	Delete(groupId string, time int64) *model.AppError
-	GetMembers(groupId string, memberType string, offset, limit int) (*model.GroupMembers, *model.AppError)
+	GetMembers(groupId string, memberType string, offset, limit int, cursor *model.Cursor) (*model.GroupMembers, *model.AppError)
 	GetMember(groupId string, userId string) (*model.GroupMember, *model.AppError)
 	UpdateMember(member *model.GroupMember) (*model.GroupMember, *model.AppError)
 	UpdateMultipleMembers(members []*model.GroupMember) ([]*model.GroupMember, *model.AppError)
@@ -103,7 +115,7 @@ type CollectionStore interface {
 	Get(id string) (*model.Collection, *model.AppError)
 	GetPost(collectionId string, postId string) (*model.CollectionPost, *model.AppError)
 	GetPosts(collectionId string, offset, limit int) (*model.CollectionPosts, *model.AppError)
-	GetCollectionsForTeam(teamId string, offset int, limit int, title string) (*model.CollectionList, *model.AppError)
+	GetCollectionsForTeam(teamId string, offset int, limit int, title string, cursor *model.Cursor) (*model.CollectionList, *model.AppError)
 	GetTeamCollections(teamId string) (*model.CollectionList, *model.AppError)
 	Save(collection *model.Collection, maxCollectionsPerTeam int64) (*model.Collection, *model.AppError)
 	SavePost(colPost *model.CollectionPost) (*model.CollectionPost, *model.AppError)
@@ -119,6 +131,9 @@ type UserStore interface {
 	Get(id string) (*model.User, *model.AppError)
 	GetByIds(userIds []string) ([]*model.User, *model.AppError)
 	GetByEmail(email string) (*model.User, *model.AppError)
+	GetByUsername(username string) (*model.User, *model.AppError)
+	GetByAuth(authData *string, authService string) (*model.User, *model.AppError)
+	RecomputePoints(teamId string, userIds []string) *model.AppError
 	GetUsersByDates(options *model.GetUsersOptions) ([]*model.User, *model.AppError)
 	GetForLogin(loginId string) (*model.User, *model.AppError)
 	GetByInboxInterval(fromUserId string, inboxInterval string, limit int) ([]*model.User, *model.AppError)
@@ -130,6 +145,9 @@ type UserStore interface {
 	UpdateFailedPasswordAttempts(userId string, attempts int) *model.AppError
 	Count(options *model.UserCountOptions) (int64, *model.AppError)
 	UpdateLastPictureUpdate(userId string, time int64) *model.AppError
+	GetTeamUsersForExport(teamId string, afterId string, limit int) ([]*model.User, *model.AppError)
+	GetPointsDrifts(after *model.CounterCursor, limit int, importedPropKey string) (*model.CounterDriftBatch, *model.AppError)
+	FixPointsDrift(drift *model.CounterDrift) (bool, *model.AppError)
 }
 
 type TokenStore interface {
@@ -144,12 +162,17 @@ type SessionStore interface {
 	Remove(sessionIdOrToken string) *model.AppError
 	RemoveByUserId(userId string) *model.AppError
 	GetSessions(userId string) ([]*model.Session, *model.AppError)
+	UpdateLastActivityAt(sessionId string, time int64) *model.AppError
+	UpdateExpiresAt(sessionId string, time int64) *model.AppError
 }
 
 type PostStore interface {
 	SaveQuestion(post *model.Post) (*model.Post, *model.AppError)
 	SaveAnswer(post *model.Post) (*model.Post, *model.AppError)
 	SaveComment(post *model.Post) (*model.Post, *model.AppError)
+	ImportPost(post *model.Post) (*model.Post, *model.AppError)
+	GetImportedPosts(teamId string, propKey string, propPrefix string) ([]*model.Post, *model.AppError)
+	SetVoteCounts(postId string, upVotes int, downVotes int) *model.AppError
 	Update(newPost *model.Post, oldPost *model.Post) (*model.Post, *model.AppError)
 	GetSingle(id string, includeDeleted bool) (*model.Post, *model.AppError)
 	GetSingleByType(id string, postType string) (*model.Post, *model.AppError)
@@ -162,6 +185,7 @@ type PostStore interface {
 	DeleteQuestion(postId string, time int64, deleteById string) *model.AppError
 	DeleteAnswer(postId string, time int64, deleteById string) *model.AppError
 	DeleteComment(postId string, time int64, deleteById string) *model.AppError
+	RestorePost(postId string, time int64) *model.AppError
 	SelectBestAnswer(postId, bestId string) *model.AppError
 	UpVotePost(postId string, userId string) (*model.Vote, *model.AppError)
 	CancelUpVotePost(postId string, userId string) (*model.Vote, *model.AppError)
@@ -174,6 +198,10 @@ type PostStore interface {
 	ProtectPost(postId string, time int64, userId string) *model.AppError
 	CancelProtectPost(postId string, userId string) *model.AppError
 	ViewPost(postId string, teamId string, userId string, ipAddress string, count int) *model.AppError
+	// 溜めておいた閲覧数を書き出し、書き出した投稿の数を返す。バッファを持たない層では何もしない
+	FlushBufferedViews(limit int) (int, *model.AppError)
+	// 新しい日付から1日ごとの閲覧数を返す
+	GetDailyUniqueViews(postId string, days int) (model.Analytics, *model.AppError)
 	SavePostViewsHistory(postId string, teamId string, userId string, ipAddress string, count int, time int64) (*model.PostViewsHistory, *model.AppError)
 	RelatedSearch(term string, limit int) ([]*model.RelatedPostSearchResult, *model.AppError)
 	HotSearch(interval string, teamId string, limit int) ([]string, *model.AppError)
@@ -183,16 +211,23 @@ type PostStore interface {
 	AnalyticsPostCounts(teamId string) (model.Analytics, *model.AppError)
 	AnalyticsActiveAuthorCounts(teamId string) (model.Analytics, *model.AppError)
 	SaveUserPointHistory(history *model.UserPointHistory) (*model.UserPointHistory, *model.AppError)
+	GetPostsForExport(teamId string, postType string, afterId string, limit int) ([]*model.Post, *model.AppError)
+	GetRevisionsForExport(teamId string, afterId string, limit int) ([]*model.Post, *model.AppError)
+	GetCounterDrifts(after *model.CounterCursor, limit int, importedPropKey string) (*model.CounterDriftBatch, *model.AppError)
+	FixCounterDrift(drift *model.CounterDrift) (bool, *model.AppError)
 }
 
 type TagStore interface {
 	GetTags(options *model.GetTagsOptions) (model.Tags, *model.AppError)
 	GetTagsCount(options *model.GetTagsOptions) (int64, *model.AppError)
 	CreateTags(addedTags []string, time int64, teamId string, tagType string) *model.AppError
+	ImportTag(tag *model.Tag) *model.AppError
+	GetPostTagsAfter(after *model.CounterCursor, limit int) (model.Tags, *model.AppError)
+	FixCounterDrift(drift *model.CounterDrift) (bool, *model.AppError)
 }
 
 type VoteStore interface {
-	GetVotesBeforeTime(time int64, userId string, page, perPage int, excludeFlag bool, getCount bool, teamId string) ([]*model.Vote, int64, *model.AppError)
+	GetVotesBeforeTime(time int64, userId string, page, perPage int, excludeFlag bool, getCount bool, teamId string, cursor *model.Cursor) ([]*model.Vote, int64, *model.AppError)
 	GetByPostIdForUser(userId string, postId string, voteType string) (*model.Vote, *model.AppError)
 	GetVoteTypesForPost(userId string, postId string) ([]string, *model.AppError)
 	CreateReviewVote(post *model.Post, userId string, tagContents string, revision int64) (*model.Vote, *model.AppError)
@@ -201,6 +236,7 @@ type VoteStore interface {
 	CompleteReviewsForPost(postId string, completedBy string, revision int64) *model.AppError
 	GetReviews(options *model.SearchReviewsOptions, getCount bool) ([]*model.Vote, int64, *model.AppError)
 	AnalyticsVoteCounts(teamId string, voteType string) (model.Analytics, *model.AppError)
+	GetVotesForExport(teamId string, offset, limit int) ([]*model.Vote, *model.AppError)
 }
 
 type UserPointHistoryStore interface {
@@ -212,7 +248,7 @@ type UserPointHistoryStore interface {
 
 type InboxMessageStore interface {
 	GetSingle(id string) (*model.InboxMessage, *model.AppError)
-	GetInboxMessages(time int64, userId string, direction string, page, perPage int, teamId string) ([]*model.InboxMessage, *model.AppError)
+	GetInboxMessages(time int64, userId string, direction string, page, perPage int, teamId string, cursor *model.Cursor) ([]*model.InboxMessage, *model.AppError)
 	GetInboxMessagesUnreadCount(userId string, fromDate int64, teamId string) (int64, *model.AppError)
 	SaveInboxMessage(inboxMessage *model.InboxMessage) (*model.InboxMessage, *model.AppError)
 	SaveMultipleInboxMessages(inboxMessages []*model.InboxMessage) ([]*model.InboxMessage, *model.AppError)
@@ -221,9 +257,10 @@ type InboxMessageStore interface {
 type UserFavoritePostStore interface {
 	GetByPostIdForUser(userId string, postId string) (*model.UserFavoritePost, *model.AppError)
 	GetCountByPostId(postId string) (int64, *model.AppError)
-	GetUserFavoritePostsBeforeTime(time int64, userId string, page, perPage int, getCount bool, teamId string) ([]*model.UserFavoritePost, int64, *model.AppError)
+	GetUserFavoritePostsBeforeTime(time int64, userId string, page, perPage int, getCount bool, teamId string, cursor *model.Cursor) ([]*model.UserFavoritePost, int64, *model.AppError)
 	Save(postId string, userId string, teamId string) *model.AppError
 	Delete(postId string, userId string) *model.AppError
+	GetForExport(teamId string, offset, limit int) ([]*model.UserFavoritePost, *model.AppError)
 }
 
 type FileInfoStore interface {
@@ -231,6 +268,7 @@ type FileInfoStore interface {
 	DeleteForPost(postId string) (string, *model.AppError)
 	Get(id string) (*model.FileInfo, *model.AppError)
 	AttachToPost(fileId, postId, userId string) *model.AppError
+	GetForPosts(postIds []string) ([]*model.FileInfo, *model.AppError)
 }
 
 type NotificationSettingStore interface {
@@ -249,16 +287,47 @@ type WebhookStore interface {
 	Get(id string) (*model.Webhook, *model.AppError)
 	Update(hook *model.Webhook) (*model.Webhook, *model.AppError)
 	Delete(webhookId string, time int64) *model.AppError
+	IncrementFailureCount(webhookId string) (int, *model.AppError)
+	ResetFailureCount(webhookId string) *model.AppError
+	Disable(webhookId string, time int64) *model.AppError
 }
 
 type WebhooksHistoryStore interface {
 	LogWebhookEvent(history *model.WebhooksHistory) error
-	GetWebhooksHistoriesPage(teamId string, offset, limit int) ([]*model.WebhooksHistory, *model.AppError)
+	Get(id string) (*model.WebhooksHistory, *model.AppError)
+	Search(options *model.SearchWebhooksHistoryOptions) ([]*model.WebhooksHistory, *model.AppError)
+	PermanentDeleteBefore(time int64, limit int) (int64, *model.AppError)
+}
+
+type WebhookDeliveryStore interface {
+	Save(delivery *model.WebhookDelivery) (*model.WebhookDelivery, *model.AppError)
+	GetDue(now int64, limit int) ([]*model.WebhookDelivery, *model.AppError)
+	Claim(delivery *model.WebhookDelivery, lockUntil int64) (bool, *model.AppError)
+	Update(delivery *model.WebhookDelivery) (*model.WebhookDelivery, *model.AppError)
+	PermanentDeleteFinishedBefore(time int64, limit int) (int64, *model.AppError)
+}
+
+type IncomingWebhookStore interface {
+	Save(hook *model.IncomingWebhook) (*model.IncomingWebhook, *model.AppError)
+	Get(id string) (*model.IncomingWebhook, *model.AppError)
+	GetByToken(token string) (*model.IncomingWebhook, *model.AppError)
+	GetByTeam(teamId string, offset, limit int) ([]*model.IncomingWebhook, *model.AppError)
+	Update(hook *model.IncomingWebhook) (*model.IncomingWebhook, *model.AppError)
+	Delete(hookId string, time int64) *model.AppError
+	DeleteByBot(botUserId string, time int64) *model.AppError
+}
+
+type BotStore interface {
+	Save(bot *model.Bot) (*model.Bot, *model.AppError)
+	Get(userId string) (*model.Bot, *model.AppError)
+	GetByTeam(teamId string, offset, limit int) ([]*model.Bot, *model.AppError)
+	Update(bot *model.Bot) (*model.Bot, *model.AppError)
 }
 
 type AuditStore interface {
 	Get(user_id string, offset int, limit int) (model.Audits, *model.AppError)
 	Save(audit *model.Audit) *model.AppError
+	Search(options *model.SearchAuditsOptions) (model.Audits, *model.AppError)
 	PermanentDeleteByUser(userId string) *model.AppError
 }
 
@@ -283,9 +352,21 @@ type OAuthStore interface {
 	DeleteAuthorizedApp(userId string, clientId string) *model.AppError
 }
 
+type SystemStore interface {
+	GetByName(name string) (*model.System, *model.AppError)
+	SaveOrUpdate(system *model.System) *model.AppError
+}
+
 type StatusStore interface {
 	Get(userId string) (*model.Status, *model.AppError)
 	GetByIds(userIds []string) ([]*model.Status, error)
 	SaveOrUpdate(status *model.Status) error
 	UpdateLastActivityAt(userId string, lastActivityAt int64) error
 }
+
+type JobStore interface {
+	Save(job *model.Job) (*model.Job, *model.AppError)
+	Get(id string) (*model.Job, *model.AppError)
+	Update(job *model.Job) (*model.Job, *model.AppError)
+	GetActiveCountByTeamAndType(teamId string, jobType string) (int64, *model.AppError)
+}
@@ -0,0 +1,52 @@
+package storetest
+
+import (
+	"testing"
+
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/clear-ness/qa-discussion/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectionStore(t *testing.T, ss store.Store) {
+	t.Run("SaveAndDelete", func(t *testing.T) { testCollectionStoreSaveAndDelete(t, ss) })
+}
+
+func testCollectionStoreSaveAndDelete(t *testing.T, ss store.Store) {
+	team := makeTeam(t, ss)
+	user := makeMember(t, ss, team.Id)
+	question := makeQuestion(t, ss, team.Id, user.Id, "golang")
+
+	col, err := ss.Collection().Save(&model.Collection{TeamId: team.Id, Title: "collection title", UserId: user.Id}, 1)
+	require.Nil(t, err)
+
+	_, err = ss.Collection().Save(&model.Collection{TeamId: team.Id, Title: "collection title", UserId: user.Id}, 1)
+	require.NotNil(t, err)
+	assert.Equal(t, "store.sql_collection.save.too_many.app_error", err.Id)
+
+	_, err = ss.Collection().SavePost(&model.CollectionPost{CollectionId: col.Id, PostId: question.Id})
+	require.Nil(t, err)
+
+	_, err = ss.Collection().GetPost(col.Id, question.Id)
+	require.Nil(t, err)
+
+	posts, err := ss.Collection().GetPosts(col.Id, 0, 10)
+	require.Nil(t, err)
+	assert.Len(t, *posts, 1)
+
+	require.Nil(t, ss.Collection().Delete(col.Id, model.GetMillis()))
+	require.NotNil(t, ss.Collection().Delete(col.Id, model.GetMillis()), "should not delete twice")
+
+	got, err := ss.Collection().Get(col.Id)
+	require.Nil(t, err)
+	assert.NotZero(t, got.DeleteAt)
+
+	cols, err := ss.Collection().GetCollectionsForTeam(team.Id, 0, 10, "", nil)
+	require.Nil(t, err)
+	assert.Len(t, *cols, 0)
+
+	// 削除済みのコレクションは上限に含めない
+	_, err = ss.Collection().Save(&model.Collection{TeamId: team.Id, Title: "collection title", UserId: user.Id}, 1)
+	require.Nil(t, err)
+}
@@ -0,0 +1,172 @@
+package storetest
+
+import (
+	"testing"
+
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/clear-ness/qa-discussion/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testImportedPropKey = "imported_id"
+
+func TestCounterDrift(t *testing.T, ss store.Store) {
+	t.Run("Posts", func(t *testing.T) { testCounterDriftPosts(t, ss) })
+	t.Run("Tags", func(t *testing.T) { testCounterDriftTags(t, ss) })
+	t.Run("Points", func(t *testing.T) { testCounterDriftPoints(t, ss) })
+}
+
+// 全バッチを走査し、指定したキーのずれだけを返す
+func collectDrifts(t *testing.T, ss store.Store, get func(*model.CounterCursor, int, string) (*model.CounterDriftBatch, *model.AppError), key string) []*model.CounterDrift {
+	var drifts []*model.CounterDrift
+	var cursor *model.CounterCursor
+	for {
+		batch, err := get(cursor, 2, testImportedPropKey)
+		require.Nil(t, err)
+		require.True(t, batch.Checked <= 2)
+
+		for _, drift := range batch.Drifts {
+			if drift.Key == key {
+				drifts = append(drifts, drift)
+			}
+		}
+
+		if batch.Next == nil {
+			return drifts
+		}
+		cursor = batch.Next
+	}
+}
+
+func testCounterDriftPosts(t *testing.T, ss store.Store) {
+	team := makeTeam(t, ss)
+	user := makeMember(t, ss, team.Id)
+	voter := makeMember(t, ss, team.Id)
+
+	question := makeQuestion(t, ss, team.Id, user.Id, "golang")
+	makeAnswer(t, ss, question, voter.Id)
+	makeQuestion(t, ss, team.Id, user.Id, "golang")
+
+	_, err := ss.Post().UpVotePost(question.Id, voter.Id)
+	require.Nil(t, err)
+	require.Nil(t, ss.Post().ViewPost(question.Id, team.Id, voter.Id, "127.0.0.1", 1))
+
+	assert.Empty(t, collectDrifts(t, ss, ss.Post().GetCounterDrifts, question.Id))
+
+	require.Nil(t, ss.Post().SetVoteCounts(question.Id, 5, 0))
+
+	// SetVoteCountsはPointsも書き換える
+	drifts := collectDrifts(t, ss, ss.Post().GetCounterDrifts, question.Id)
+	require.Len(t, drifts, 2)
+	assert.Equal(t, model.COUNTER_TABLE_POSTS, drifts[0].Table)
+	assert.Equal(t, model.COUNTER_COLUMN_UP_VOTES, drifts[0].Column)
+	assert.Equal(t, int64(5), drifts[0].Actual)
+	assert.Equal(t, int64(1), drifts[0].Expected)
+	assert.Equal(t, model.COUNTER_COLUMN_POINTS, drifts[1].Column)
+
+	// 照合した後に値が変わっていれば修正しない
+	stale := *drifts[0]
+	stale.Actual = 4
+	fixed, err := ss.Post().FixCounterDrift(&stale)
+	require.Nil(t, err)
+	assert.False(t, fixed)
+
+	for _, drift := range drifts {
+		fixed, err = ss.Post().FixCounterDrift(drift)
+		require.Nil(t, err)
+		assert.True(t, fixed)
+	}
+	assert.Empty(t, collectDrifts(t, ss, ss.Post().GetCounterDrifts, question.Id))
+
+	_, err = ss.Post().FixCounterDrift(&model.CounterDrift{Key: question.Id, Column: "Title"})
+	require.NotNil(t, err)
+}
+
+func testCounterDriftTags(t *testing.T, ss store.Store) {
+	team := makeTeam(t, ss)
+	user := makeMember(t, ss, team.Id)
+
+	makeQuestion(t, ss, team.Id, user.Id, "golang rust")
+	makeQuestion(t, ss, team.Id, user.Id, "golang")
+
+	var tags model.Tags
+	var cursor *model.CounterCursor
+	for {
+		page, err := ss.Tag().GetPostTagsAfter(cursor, 1)
+		require.Nil(t, err)
+		require.True(t, len(page) <= 1)
+		if len(page) == 0 {
+			break
+		}
+
+		if page[0].TeamId == team.Id {
+			tags = append(tags, page...)
+		}
+		cursor = &model.CounterCursor{TeamId: page[0].TeamId, Key: page[0].Content}
+	}
+
+	require.Len(t, tags, 2)
+	assert.Equal(t, "golang", tags[0].Content)
+	assert.Equal(t, 2, tags[0].PostCount)
+	assert.Equal(t, "rust", tags[1].Content)
+
+	drift := &model.CounterDrift{
+		Table:    model.COUNTER_TABLE_TAGS,
+		TeamId:   team.Id,
+		Key:      "golang",
+		Column:   model.COUNTER_COLUMN_POST_COUNT,
+		Actual:   3,
+		Expected: 1,
+	}
+	fixed, err := ss.Tag().FixCounterDrift(drift)
+	require.Nil(t, err)
+	assert.False(t, fixed)
+
+	drift.Actual = 2
+	fixed, err = ss.Tag().FixCounterDrift(drift)
+	require.Nil(t, err)
+	assert.True(t, fixed)
+
+	tags, err = ss.Tag().GetPostTagsAfter(&model.CounterCursor{TeamId: team.Id}, 1)
+	require.Nil(t, err)
+	require.Len(t, tags, 1)
+	assert.Equal(t, "golang", tags[0].Content)
+	assert.Equal(t, 1, tags[0].PostCount)
+}
+
+func testCounterDriftPoints(t *testing.T, ss store.Store) {
+	team := makeTeam(t, ss)
+	user := makeMember(t, ss, team.Id)
+
+	imported := makeUser(t, ss)
+	imported.Props = model.StringMap{testImportedPropKey: "1"}
+	imported.Points = 100
+	_, err := ss.User().Update(imported, true)
+	require.Nil(t, err)
+
+	assert.Empty(t, collectDrifts(t, ss, ss.User().GetPointsDrifts, user.Id))
+	// インポートしたユーザーは履歴を持たないので照合しない
+	assert.Empty(t, collectDrifts(t, ss, ss.User().GetPointsDrifts, imported.Id))
+	assert.Empty(t, collectDrifts(t, ss, ss.Team().GetMemberPointsDrifts, user.Id))
+
+	member, err := ss.Team().GetMember(team.Id, user.Id)
+	require.Nil(t, err)
+	member.Points += 10
+	_, err = ss.Team().UpdateMember(member)
+	require.Nil(t, err)
+
+	drifts := collectDrifts(t, ss, ss.Team().GetMemberPointsDrifts, user.Id)
+	require.Len(t, drifts, 1)
+	assert.Equal(t, team.Id, drifts[0].TeamId)
+	assert.Equal(t, int64(member.Points), drifts[0].Actual)
+	assert.Equal(t, int64(member.Points-10), drifts[0].Expected)
+
+	fixed, err := ss.Team().FixMemberPointsDrift(drifts[0])
+	require.Nil(t, err)
+	assert.True(t, fixed)
+
+	fixed, err = ss.Team().FixMemberPointsDrift(drifts[0])
+	require.Nil(t, err)
+	assert.False(t, fixed)
+}
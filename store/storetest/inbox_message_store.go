@@ -0,0 +1,59 @@
+package storetest
+
+import (
+	"testing"
+
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/clear-ness/qa-discussion/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInboxMessageStore(t *testing.T, ss store.Store) {
+	t.Run("Unread", func(t *testing.T) { testInboxMessageStoreUnread(t, ss) })
+}
+
+func testInboxMessageStoreUnread(t *testing.T, ss store.Store) {
+	team := makeTeam(t, ss)
+	user := makeMember(t, ss, team.Id)
+	sender := makeMember(t, ss, team.Id)
+	question := makeQuestion(t, ss, team.Id, user.Id, "golang")
+
+	makeMessage := func(createAt int64) *model.InboxMessage {
+		message, err := ss.InboxMessage().SaveInboxMessage(&model.InboxMessage{
+			Type:       model.INBOX_MESSAGE_TYPE_ANSWER,
+			Content:    "answer content",
+			UserId:     user.Id,
+			SenderId:   sender.Id,
+			QuestionId: question.Id,
+			Title:      question.Title,
+			TeamId:     team.Id,
+			CreateAt:   createAt,
+		})
+		require.Nil(t, err)
+		return message
+	}
+
+	now := model.GetMillis()
+	first := makeMessage(now - 2)
+	makeMessage(now - 1)
+
+	count, err := ss.InboxMessage().GetInboxMessagesUnreadCount(user.Id, 0, team.Id)
+	require.Nil(t, err)
+	assert.Equal(t, int64(2), count)
+
+	require.Nil(t, ss.User().UpdateLastInboxMessageViewed(first, user.Id))
+
+	count, err = ss.InboxMessage().GetInboxMessagesUnreadCount(user.Id, 0, team.Id)
+	require.Nil(t, err)
+	assert.Equal(t, int64(1), count)
+
+	messages, err := ss.InboxMessage().GetInboxMessages(now, user.Id, "<=", 0, 10, team.Id, nil)
+	require.Nil(t, err)
+	require.Len(t, messages, 2)
+	assert.True(t, messages[0].IsUnread)
+	assert.False(t, messages[1].IsUnread)
+
+	_, err = ss.InboxMessage().GetInboxMessages(now, user.Id, "=", 0, 10, team.Id, nil)
+	require.NotNil(t, err)
+}
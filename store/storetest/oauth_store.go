@@ -0,0 +1,59 @@
+package storetest
+
+import (
+	"testing"
+
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/clear-ness/qa-discussion/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOAuthStore(t *testing.T, ss store.Store) {
+	t.Run("AccessData", func(t *testing.T) { testOAuthStoreAccessData(t, ss) })
+}
+
+func testOAuthStoreAccessData(t *testing.T, ss store.Store) {
+	user := makeUser(t, ss)
+
+	app, err := ss.OAuth().SaveApp(&model.OAuthApp{
+		UserId:   user.Id,
+		Name:     "app",
+		URLs:     []string{"https://example.com/callback"},
+		Homepage: "https://example.com",
+	})
+	require.Nil(t, err)
+
+	accessData, err := ss.OAuth().SaveAccessData(&model.AccessData{
+		ClientId:     app.Id,
+		UserId:       user.Id,
+		Token:        model.NewId(),
+		RefreshToken: model.NewId(),
+		RedirectUri:  "https://example.com/callback",
+	})
+	require.Nil(t, err)
+
+	previous, err := ss.OAuth().GetPreviousAccessData(user.Id, app.Id)
+	require.Nil(t, err)
+	require.NotNil(t, previous)
+	assert.Equal(t, accessData.Token, previous.Token)
+
+	oldToken := accessData.Token
+	accessData.Token = model.NewId()
+	accessData.RefreshToken = model.NewId()
+	_, err = ss.OAuth().UpdateAccessData(accessData)
+	require.Nil(t, err)
+
+	_, err = ss.OAuth().GetAccessData(oldToken)
+	require.NotNil(t, err)
+
+	got, err := ss.OAuth().GetAccessDataByRefreshToken(accessData.RefreshToken)
+	require.Nil(t, err)
+	assert.Equal(t, accessData.Token, got.Token)
+
+	require.Nil(t, ss.OAuth().DeleteApp(app.Id))
+
+	previous, err = ss.OAuth().GetPreviousAccessData(user.Id, app.Id)
+	require.Nil(t, err)
+	assert.Nil(t, previous)
+}
@@ -0,0 +1,407 @@
+package storetest
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/clear-ness/qa-discussion/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostStore(t *testing.T, ss store.Store) {
+	t.Run("SaveQuestion", func(t *testing.T) { testPostStoreSaveQuestion(t, ss) })
+	t.Run("SaveAnswer", func(t *testing.T) { testPostStoreSaveAnswer(t, ss) })
+	t.Run("Update", func(t *testing.T) { testPostStoreUpdate(t, ss) })
+	t.Run("DeleteQuestion", func(t *testing.T) { testPostStoreDeleteQuestion(t, ss) })
+	t.Run("RestorePost", func(t *testing.T) { testPostStoreRestorePost(t, ss) })
+	t.Run("UpVotePost", func(t *testing.T) { testPostStoreUpVotePost(t, ss) })
+	t.Run("UpVotePostConcurrently", func(t *testing.T) { testPostStoreUpVotePostConcurrently(t, ss) })
+	t.Run("ViewPost", func(t *testing.T) { testPostStoreViewPost(t, ss) })
+	t.Run("GetPostsCursor", func(t *testing.T) { testPostStoreGetPostsCursor(t, ss) })
+}
+
+func getTag(t *testing.T, ss store.Store, teamId string, content string) *model.Tag {
+	tags, err := ss.Tag().GetTags(&model.GetTagsOptions{TeamId: teamId, Content: content, PerPage: 10})
+	require.Nil(t, err)
+	require.Len(t, tags, 1)
+
+	return &tags[0]
+}
+
+func testPostStoreSaveQuestion(t *testing.T, ss store.Store) {
+	team := makeTeam(t, ss)
+	user := makeMember(t, ss, team.Id)
+
+	question := makeQuestion(t, ss, team.Id, user.Id, "golang mysql")
+
+	got, err := ss.Post().GetSingle(question.Id, false)
+	require.Nil(t, err)
+	assert.Equal(t, question.Title, got.Title)
+	assert.Equal(t, "golang mysql", got.Tags)
+
+	assert.Equal(t, model.USER_POINT_FOR_CREATE_QUESTION, memberPoints(t, ss, team.Id, user.Id))
+	assert.Equal(t, 1, getTag(t, ss, team.Id, "golang").PostCount)
+
+	makeQuestion(t, ss, team.Id, user.Id, "golang")
+	assert.Equal(t, 2*model.USER_POINT_FOR_CREATE_QUESTION, memberPoints(t, ss, team.Id, user.Id))
+	assert.Equal(t, 2, getTag(t, ss, team.Id, "golang").PostCount)
+	assert.Equal(t, 1, getTag(t, ss, team.Id, "mysql").PostCount)
+
+	count, err := ss.Post().GetPostCount(model.POST_TYPE_QUESTION, user.Id, team.Id, 0, 0)
+	require.Nil(t, err)
+	assert.Equal(t, int64(2), count)
+
+	_, err = ss.Post().SaveQuestion(question)
+	require.NotNil(t, err, "should not save an existing post")
+}
+
+func testPostStoreSaveAnswer(t *testing.T, ss store.Store) {
+	team := makeTeam(t, ss)
+	asker := makeMember(t, ss, team.Id)
+	answerer := makeMember(t, ss, team.Id)
+
+	question := makeQuestion(t, ss, team.Id, asker.Id, "golang")
+	makeAnswer(t, ss, question, answerer.Id)
+	// 自分の質問への回答ではポイントは増えない
+	makeAnswer(t, ss, question, asker.Id)
+
+	got, err := ss.Post().GetSingle(question.Id, false)
+	require.Nil(t, err)
+	assert.Equal(t, 2, got.AnswerCount)
+
+	assert.Equal(t, model.USER_POINT_FOR_CREATE_QUESTION, memberPoints(t, ss, team.Id, asker.Id))
+	assert.Equal(t, model.USER_POINT_FOR_CREATE_ANSWER, memberPoints(t, ss, team.Id, answerer.Id))
+
+	count, err := ss.Post().GetChildPostsCount(question.Id)
+	require.Nil(t, err)
+	assert.Equal(t, int64(2), count)
+
+	_, err = ss.Post().SaveAnswer(&model.Post{
+		Type:     model.POST_TYPE_ANSWER,
+		UserId:   answerer.Id,
+		TeamId:   team.Id,
+		ParentId: model.NewId(),
+		RootId:   question.Id,
+		Content:  "answer content",
+	})
+	require.NotNil(t, err, "should not answer a missing question")
+}
+
+func testPostStoreUpdate(t *testing.T, ss store.Store) {
+	team := makeTeam(t, ss)
+	user := makeMember(t, ss, team.Id)
+
+	question := makeQuestion(t, ss, team.Id, user.Id, "golang mysql")
+
+	revision, err := ss.Post().GetCurrentRevisionForPost(question.Id, team.Id)
+	require.Nil(t, err)
+	assert.Equal(t, int64(1), revision)
+
+	oldPost := question.Clone()
+	newPost := question.Clone()
+	newPost.Content = "updated content"
+	newPost.Tags = "golang redis"
+
+	_, err = ss.Post().Update(newPost, oldPost)
+	require.Nil(t, err)
+
+	got, err := ss.Post().GetSingle(question.Id, false)
+	require.Nil(t, err)
+	assert.Equal(t, "updated content", got.Content)
+	assert.Equal(t, "golang redis", got.Tags)
+
+	// 更新前の投稿はOriginalIdを持つ削除済みの投稿として残る
+	revision, err = ss.Post().GetCurrentRevisionForPost(question.Id, team.Id)
+	require.Nil(t, err)
+	assert.Equal(t, int64(2), revision)
+
+	old, err := ss.Post().GetRevisionPost(question.Id, team.Id, 0)
+	require.Nil(t, err)
+	assert.Equal(t, oldPost.Id, old.Id)
+	assert.NotEqual(t, question.Id, old.Id)
+	assert.Equal(t, question.Id, old.OriginalId)
+	assert.Equal(t, "question content", old.Content)
+	assert.NotZero(t, old.DeleteAt)
+
+	_, err = ss.Post().GetSingle(old.Id, false)
+	require.NotNil(t, err)
+
+	assert.Equal(t, 1, getTag(t, ss, team.Id, "golang").PostCount)
+	assert.Equal(t, 0, getTag(t, ss, team.Id, "mysql").PostCount)
+	assert.Equal(t, 1, getTag(t, ss, team.Id, "redis").PostCount)
+}
+
+func testPostStoreDeleteQuestion(t *testing.T, ss store.Store) {
+	team := makeTeam(t, ss)
+	user := makeMember(t, ss, team.Id)
+	moderator := makeMember(t, ss, team.Id)
+
+	question := makeQuestion(t, ss, team.Id, user.Id, "golang")
+
+	deleteAt := model.GetMillis()
+	require.Nil(t, ss.Post().DeleteQuestion(question.Id, deleteAt, moderator.Id))
+
+	_, err := ss.Post().GetSingle(question.Id, false)
+	require.NotNil(t, err)
+	assert.Equal(t, http.StatusNotFound, err.StatusCode)
+
+	got, err := ss.Post().GetSingle(question.Id, true)
+	require.Nil(t, err)
+	assert.Equal(t, deleteAt, got.DeleteAt)
+	assert.Equal(t, moderator.Id, got.Props[model.POST_PROPS_DELETE_BY])
+
+	assert.Equal(t, 0, memberPoints(t, ss, team.Id, user.Id))
+	assert.Equal(t, 0, getTag(t, ss, team.Id, "golang").PostCount)
+
+	require.NotNil(t, ss.Post().DeleteQuestion(question.Id, model.GetMillis(), moderator.Id), "should not delete twice")
+}
+
+func testPostStoreRestorePost(t *testing.T, ss store.Store) {
+	team := makeTeam(t, ss)
+	user := makeMember(t, ss, team.Id)
+	moderator := makeMember(t, ss, team.Id)
+
+	question := makeQuestion(t, ss, team.Id, user.Id, "golang")
+	answer := makeAnswer(t, ss, question, user.Id)
+	points := memberPoints(t, ss, team.Id, user.Id)
+
+	require.Nil(t, ss.Post().DeleteAnswer(answer.Id, model.GetMillis(), moderator.Id))
+
+	got, err := ss.Post().GetSingle(question.Id, false)
+	require.Nil(t, err)
+	assert.Equal(t, 0, got.AnswerCount)
+
+	require.Nil(t, ss.Post().RestorePost(answer.Id, model.GetMillis()))
+
+	got, err = ss.Post().GetSingle(answer.Id, false)
+	require.Nil(t, err)
+	assert.Nil(t, got.Props[model.POST_PROPS_DELETE_BY])
+
+	got, err = ss.Post().GetSingle(question.Id, false)
+	require.Nil(t, err)
+	assert.Equal(t, 1, got.AnswerCount)
+	assert.Equal(t, points, memberPoints(t, ss, team.Id, user.Id))
+
+	err = ss.Post().RestorePost(answer.Id, model.GetMillis())
+	require.NotNil(t, err, "should not restore a post which is not deleted")
+	assert.Equal(t, http.StatusBadRequest, err.StatusCode)
+
+	require.Nil(t, ss.Post().DeleteAnswer(answer.Id, model.GetMillis(), moderator.Id))
+	require.Nil(t, ss.Post().DeleteQuestion(question.Id, model.GetMillis(), moderator.Id))
+
+	err = ss.Post().RestorePost(answer.Id, model.GetMillis())
+	require.NotNil(t, err, "should not restore an answer of a deleted question")
+	assert.Equal(t, http.StatusBadRequest, err.StatusCode)
+
+	require.Nil(t, ss.Post().RestorePost(question.Id, model.GetMillis()))
+
+	_, err = ss.Post().GetSingle(question.Id, false)
+	require.Nil(t, err)
+	assert.Equal(t, points-model.USER_POINT_FOR_CREATE_ANSWER, memberPoints(t, ss, team.Id, user.Id))
+	assert.Equal(t, 1, getTag(t, ss, team.Id, "golang").PostCount)
+
+	oldPost := question.Clone()
+	newPost := question.Clone()
+	newPost.Content = "updated content"
+	_, err = ss.Post().Update(newPost, oldPost)
+	require.Nil(t, err)
+
+	// 編集履歴は削除済みでも戻せない
+	err = ss.Post().RestorePost(oldPost.Id, model.GetMillis())
+	require.NotNil(t, err, "should not restore a revision")
+	assert.Equal(t, http.StatusBadRequest, err.StatusCode)
+
+	got, err = ss.Post().GetSingle(question.Id, false)
+	require.Nil(t, err)
+	assert.Equal(t, "updated content", got.Content)
+	assert.Equal(t, 1, getTag(t, ss, team.Id, "golang").PostCount)
+}
+
+func testPostStoreUpVotePost(t *testing.T, ss store.Store) {
+	team := makeTeam(t, ss)
+	user := makeMember(t, ss, team.Id)
+	voter := makeMember(t, ss, team.Id)
+
+	question := makeQuestion(t, ss, team.Id, user.Id, "golang")
+
+	vote, err := ss.Post().UpVotePost(question.Id, voter.Id)
+	require.Nil(t, err)
+	assert.Equal(t, model.VOTE_TYPE_UP_VOTE, vote.Type)
+
+	_, err = ss.Post().UpVotePost(question.Id, voter.Id)
+	require.NotNil(t, err, "should not vote twice")
+
+	got, err := ss.Post().GetSingle(question.Id, false)
+	require.Nil(t, err)
+	assert.Equal(t, 1, got.UpVotes)
+	assert.Equal(t, 1, got.Points)
+	assert.Equal(t, model.USER_POINT_FOR_CREATE_QUESTION+model.USER_POINT_FOR_VOTED, memberPoints(t, ss, team.Id, user.Id))
+
+	// 自分の投稿への投票ではポイントは増えない
+	_, err = ss.Post().UpVotePost(question.Id, user.Id)
+	require.Nil(t, err)
+	assert.Equal(t, model.USER_POINT_FOR_CREATE_QUESTION+model.USER_POINT_FOR_VOTED, memberPoints(t, ss, team.Id, user.Id))
+
+	_, err = ss.Post().CancelUpVotePost(question.Id, voter.Id)
+	require.Nil(t, err)
+
+	got, err = ss.Post().GetSingle(question.Id, false)
+	require.Nil(t, err)
+	assert.Equal(t, 1, got.UpVotes)
+	assert.Equal(t, model.USER_POINT_FOR_CREATE_QUESTION, memberPoints(t, ss, team.Id, user.Id))
+
+	_, err = ss.Post().CancelUpVotePost(question.Id, voter.Id)
+	require.NotNil(t, err, "should not cancel a missing vote")
+}
+
+// 同じ投稿に投票が集中してもデッドロックで失敗せず、カウンタがずれないこと
+func testPostStoreUpVotePostConcurrently(t *testing.T, ss store.Store) {
+	team := makeTeam(t, ss)
+	author1 := makeMember(t, ss, team.Id)
+	author2 := makeMember(t, ss, team.Id)
+	question1 := makeQuestion(t, ss, team.Id, author1.Id, "golang")
+	question2 := makeQuestion(t, ss, team.Id, author2.Id, "golang")
+
+	voterCount := 20
+	voters := make([]*model.User, voterCount)
+	for i := range voters {
+		voters[i] = makeMember(t, ss, team.Id)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan *model.AppError, voterCount*2)
+	for i, voter := range voters {
+		// 投票する順序を入れ替えて、行ロックの順序が逆になるトランザクションを混ぜる
+		postIds := []string{question1.Id, question2.Id}
+		if i%2 == 1 {
+			postIds = []string{question2.Id, question1.Id}
+		}
+
+		wg.Add(1)
+		go func(voterId string, postIds []string) {
+			defer wg.Done()
+			for _, postId := range postIds {
+				if _, err := ss.Post().UpVotePost(postId, voterId); err != nil {
+					errs <- err
+				}
+			}
+		}(voter.Id, postIds)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		assert.Nil(t, err)
+	}
+
+	for _, question := range []*model.Post{question1, question2} {
+		got, err := ss.Post().GetSingle(question.Id, false)
+		require.Nil(t, err)
+		assert.Equal(t, voterCount, got.UpVotes)
+		assert.Equal(t, voterCount, got.Points)
+		assert.Equal(t, model.USER_POINT_FOR_CREATE_QUESTION+voterCount*model.USER_POINT_FOR_VOTED, memberPoints(t, ss, team.Id, question.UserId))
+	}
+}
+
+func testPostStoreViewPost(t *testing.T, ss store.Store) {
+	team := makeTeam(t, ss)
+	user := makeMember(t, ss, team.Id)
+	question := makeQuestion(t, ss, team.Id, user.Id, "golang")
+
+	before, err := ss.PostViewsHistory().GetViewsHistoryCount(team.Id, 0, 0)
+	require.Nil(t, err)
+
+	// まとめて書き出された閲覧はViewsCountに入る
+	require.Nil(t, ss.Post().ViewPost(question.Id, team.Id, "", "", 3))
+	require.Nil(t, ss.Post().ViewPost(question.Id, team.Id, user.Id, "", 1))
+
+	post, err := ss.Post().GetSingle(question.Id, false)
+	require.Nil(t, err)
+	assert.Equal(t, 4, post.Views)
+
+	count, err := ss.PostViewsHistory().GetViewsHistoryCount(team.Id, 0, 0)
+	require.Nil(t, err)
+	assert.Equal(t, before+4, count)
+
+	rows, err := ss.Post().GetDailyUniqueViews(question.Id, 7)
+	require.Nil(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, float64(4), rows[0].Value)
+
+	rows, err = ss.Post().GetDailyUniqueViews(model.NewId(), 7)
+	require.Nil(t, err)
+	assert.Empty(t, rows)
+}
+
+func testPostStoreGetPostsCursor(t *testing.T, ss store.Store) {
+	team := makeTeam(t, ss)
+	user := makeMember(t, ss, team.Id)
+
+	// 並びのキーが同じ質問をIdで区別できるか確かめる
+	var questions []*model.Post
+	for i := 0; i < 5; i++ {
+		questions = append(questions, makeQuestion(t, ss, team.Id, user.Id, "golang"))
+	}
+	require.Nil(t, ss.Post().SetVoteCounts(questions[1].Id, 2, 0))
+	require.Nil(t, ss.Post().SetVoteCounts(questions[3].Id, 2, 0))
+	makeAnswer(t, ss, questions[2], user.Id)
+
+	getPosts := func(sortType string, page int, perPage int, cursor *model.Cursor) model.Posts {
+		posts, _, err := ss.Post().GetPosts(&model.GetPostsOptions{
+			TeamId:   team.Id,
+			PostType: model.POST_TYPE_QUESTION,
+			SortType: sortType,
+			Page:     page,
+			PerPage:  perPage,
+			Cursor:   cursor,
+		}, false)
+		require.Nil(t, err)
+		return posts
+	}
+
+	ids := func(posts model.Posts) []string {
+		result := []string{}
+		for _, post := range posts {
+			result = append(result, post.Id)
+		}
+		return result
+	}
+
+	for _, sortType := range []string{"", model.POST_SORT_TYPE_CREATION, model.POST_SORT_TYPE_ACTIVE, model.POST_SORT_TYPE_VOTES, model.POST_SORT_TYPE_ANSWERS} {
+		t.Run("sort="+sortType, func(t *testing.T) {
+			all := ids(getPosts(sortType, 0, 100, nil))
+			require.Len(t, all, 5)
+
+			// オフセットで取得した場合と同じ順に辿れる
+			cursorOf := func(post *model.Post) *model.Cursor {
+				return model.NewCursor(model.CURSOR_TYPE_POSTS, sortType, post.SortKey(sortType), post.Id)
+			}
+
+			forward := []string{}
+			var last *model.Post
+			var cursor *model.Cursor
+			for {
+				page := getPosts(sortType, 0, 2, cursor)
+				forward = append(forward, ids(page)...)
+				if len(page) < 2 {
+					break
+				}
+				last = page[len(page)-1]
+				cursor = cursorOf(last)
+			}
+			assert.Equal(t, all, forward)
+			assert.Equal(t, ids(getPosts(sortType, 1, 2, nil)), ids(getPosts(sortType, 0, 2, cursorOf(getPosts(sortType, 0, 2, nil)[1]))))
+
+			// 前のページは表示順のまま返る
+			cursor = cursorOf(last)
+			cursor.Before = true
+			assert.Equal(t, all[1:3], ids(getPosts(sortType, 0, 2, cursor)))
+		})
+	}
+
+	posts := getPosts(model.POST_SORT_TYPE_RELEVANCE, 0, 2, model.NewOffsetCursor(model.CURSOR_TYPE_POSTS, model.POST_SORT_TYPE_RELEVANCE, 4))
+	assert.Len(t, posts, 1)
+}
@@ -3,6 +3,7 @@ package storetest
 import (
 	"flag"
 	"fmt"
+	"net/url"
 	"os"
 
 	"github.com/clear-ness/qa-discussion/model"
@@ -10,8 +11,9 @@ import (
 )
 
 const (
-	defaultMysqlDSN     = "root@tcp(localhost:3306)/qa_discussion_test?charset=utf8mb4,utf8\u0026readTimeout=30s\u0026writeTimeout=30s"
-	defaultMysqlRootPWD = ""
+	defaultMysqlDSN      = "root@tcp(localhost:3306)/qa_discussion_test?charset=utf8mb4,utf8\u0026readTimeout=30s\u0026writeTimeout=30s"
+	defaultMysqlRootPWD  = ""
+	defaultPostgresqlDSN = "postgres://postgres:@localhost:5432/qa_discussion_test?sslmode=disable\u0026connect_timeout=10"
 )
 
 func getEnv(name, defaultValue string) string {
@@ -38,14 +40,18 @@ func log(message string) {
 
 func databaseSettings(driver, dataSource string) *model.SqlSettings {
 	settings := &model.SqlSettings{
-		DriverName:                  &driver,
-		DataSource:                  &dataSource,
-		DataSourceReplicas:          []string{},
-		MaxIdleConns:                new(int),
-		ConnMaxLifetimeMilliseconds: new(int),
-		MaxOpenConns:                new(int),
-		Trace:                       model.NewBool(false),
-		QueryTimeout:                new(int),
+		DriverName:                     &driver,
+		DataSource:                     &dataSource,
+		DataSourceReplicas:             []string{},
+		MaxIdleConns:                   new(int),
+		ConnMaxLifetimeMilliseconds:    new(int),
+		MaxOpenConns:                   new(int),
+		Trace:                          model.NewBool(false),
+		QueryTimeout:                   new(int),
+		MaxReplicaLagSeconds:           model.NewInt(0),
+		ReplicaLagCheckIntervalSeconds: model.NewInt(5),
+		ReadYourWritesSeconds:          model.NewInt(0),
+		AutoMigrate:                    model.NewBool(false),
 	}
 	*settings.MaxIdleConns = 10
 	*settings.ConnMaxLifetimeMilliseconds = 3600000
@@ -65,10 +71,29 @@ func MySQLSettings() *model.SqlSettings {
 	return databaseSettings("mysql", cfg.FormatDSN())
 }
 
+func PostgreSQLSettings() *model.SqlSettings {
+	dsn := getEnv("TEST_DATABASE_POSTGRESQL_DSN", defaultPostgresqlDSN)
+	if _, err := url.Parse(dsn); err != nil {
+		panic("failed to parse dsn " + dsn + ": " + err.Error())
+	}
+
+	return databaseSettings("postgres", dsn)
+}
+
+// TEST_DATABASE_DRIVERで、どちらのDBに対してテストするかを切り替える
 func MakeSqlSettings() *model.SqlSettings {
 	var settings *model.SqlSettings
 
-	settings = MySQLSettings()
+	driver := getEnv("TEST_DATABASE_DRIVER", model.DATABASE_DRIVER_MYSQL)
+	switch driver {
+	case model.DATABASE_DRIVER_MYSQL:
+		settings = MySQLSettings()
+	case model.DATABASE_DRIVER_POSTGRES:
+		settings = PostgreSQLSettings()
+	default:
+		panic("unsupported driver " + driver)
+	}
+	log("Using " + driver + " for tests")
 
 	return settings
 }
@@ -0,0 +1,108 @@
+package storetest
+
+import (
+	"testing"
+
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/clear-ness/qa-discussion/store"
+	"github.com/stretchr/testify/require"
+)
+
+// sqlstoreとmemstoreの両方に同じテストを実行し、実装がずれないようにする
+func StoreTest(t *testing.T, ss store.Store) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, ss store.Store)
+	}{
+		{"Team", TestTeamStore},
+		{"User", TestUserStore},
+		{"Post", TestPostStore},
+		{"Vote", TestVoteStore},
+		{"Tag", TestTagStore},
+		{"UserGroup", TestUserGroupStore},
+		{"Collection", TestCollectionStore},
+		{"InboxMessage", TestInboxMessageStore},
+		{"OAuth", TestOAuthStore},
+		{"CounterDrift", TestCounterDrift},
+		{"System", TestSystemStore},
+		{"WebhooksHistory", TestWebhooksHistoryStore},
+	}
+
+	for _, test := range tests {
+		fn := test.fn
+		t.Run(test.name, func(t *testing.T) {
+			ss.DropAllTables()
+			fn(t, ss)
+		})
+	}
+}
+
+func makeTeam(t *testing.T, ss store.Store) *model.Team {
+	team, err := ss.Team().Save(&model.Team{
+		Name:  "z" + model.NewId(),
+		Email: model.NewId() + "@localhost",
+		Type:  model.TEAM_TYPE_PUBLIC,
+	})
+	require.Nil(t, err)
+
+	return team
+}
+
+func makeUser(t *testing.T, ss store.Store) *model.User {
+	user, err := ss.User().Save(&model.User{
+		Email: model.NewId() + "@localhost",
+		Type:  model.USER_TYPE_NORMAL,
+	})
+	require.Nil(t, err)
+
+	return user
+}
+
+// チームに参加したユーザーを作る
+func makeMember(t *testing.T, ss store.Store, teamId string) *model.User {
+	user := makeUser(t, ss)
+
+	_, err := ss.Team().SaveMember(&model.TeamMember{
+		TeamId: teamId,
+		UserId: user.Id,
+		Type:   model.TEAM_MEMBER_TYPE_NORMAL,
+	}, -1)
+	require.Nil(t, err)
+
+	return user
+}
+
+func makeQuestion(t *testing.T, ss store.Store, teamId string, userId string, tags string) *model.Post {
+	post, err := ss.Post().SaveQuestion(&model.Post{
+		Type:    model.POST_TYPE_QUESTION,
+		UserId:  userId,
+		TeamId:  teamId,
+		Title:   "question title",
+		Content: "question content",
+		Tags:    tags,
+	})
+	require.Nil(t, err)
+
+	return post
+}
+
+func makeAnswer(t *testing.T, ss store.Store, question *model.Post, userId string) *model.Post {
+	post, err := ss.Post().SaveAnswer(&model.Post{
+		Type:     model.POST_TYPE_ANSWER,
+		UserId:   userId,
+		TeamId:   question.TeamId,
+		ParentId: question.Id,
+		RootId:   question.Id,
+		Content:  "answer content",
+	})
+	require.Nil(t, err)
+
+	return post
+}
+
+func memberPoints(t *testing.T, ss store.Store, teamId string, userId string) int {
+	member, err := ss.Team().GetMember(teamId, userId)
+	require.Nil(t, err)
+
+	return member.Points
+}
@@ -0,0 +1,36 @@
+package storetest
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/clear-ness/qa-discussion/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSystemStore(t *testing.T, ss store.Store) {
+	t.Run("SaveOrUpdate", func(t *testing.T) { testSystemStoreSaveOrUpdate(t, ss) })
+}
+
+func testSystemStoreSaveOrUpdate(t *testing.T, ss store.Store) {
+	name := "test_" + model.NewId()
+
+	_, err := ss.System().GetByName(name)
+	require.NotNil(t, err)
+	assert.Equal(t, http.StatusNotFound, err.StatusCode)
+
+	require.Nil(t, ss.System().SaveOrUpdate(&model.System{Name: name, Value: "1"}))
+
+	system, err := ss.System().GetByName(name)
+	require.Nil(t, err)
+	assert.Equal(t, "1", system.Value)
+
+	// 既にあれば値を上書きする
+	require.Nil(t, ss.System().SaveOrUpdate(&model.System{Name: name, Value: "2"}))
+
+	system, err = ss.System().GetByName(name)
+	require.Nil(t, err)
+	assert.Equal(t, "2", system.Value)
+}
@@ -0,0 +1,81 @@
+package storetest
+
+import (
+	"testing"
+
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/clear-ness/qa-discussion/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTagStore(t *testing.T, ss store.Store) {
+	t.Run("CreateTags", func(t *testing.T) { testTagStoreCreateTags(t, ss) })
+	t.Run("ImportTag", func(t *testing.T) { testTagStoreImportTag(t, ss) })
+}
+
+func testTagStoreCreateTags(t *testing.T, ss store.Store) {
+	team := makeTeam(t, ss)
+
+	require.Nil(t, ss.Tag().CreateTags([]string{"golang", "gorm", "mysql"}, model.GetMillis(), team.Id, model.TAG_TYPE_REVIEW))
+	require.NotNil(t, ss.Tag().CreateTags([]string{"redis", "golang"}, model.GetMillis(), team.Id, model.TAG_TYPE_REVIEW), "should not create a duplicated tag")
+	require.NotNil(t, ss.Tag().CreateTags([]string{}, model.GetMillis(), team.Id, model.TAG_TYPE_REVIEW))
+
+	options := &model.GetTagsOptions{
+		TeamId:   team.Id,
+		Type:     model.TAG_TYPE_REVIEW,
+		SortType: model.POST_SORT_TYPE_NAME,
+		PerPage:  2,
+	}
+
+	tags, err := ss.Tag().GetTags(options)
+	require.Nil(t, err)
+	require.Len(t, tags, 2)
+	assert.Equal(t, "golang", tags[0].Content)
+	assert.Equal(t, "gorm", tags[1].Content)
+
+	options.Page = 1
+	tags, err = ss.Tag().GetTags(options)
+	require.Nil(t, err)
+	require.Len(t, tags, 1)
+	assert.Equal(t, "mysql", tags[0].Content)
+
+	options.Page = 0
+	options.InName = "go"
+	count, err := ss.Tag().GetTagsCount(options)
+	require.Nil(t, err)
+	assert.Equal(t, int64(2), count)
+
+	// 種類の違うタグは別のタグとして扱う
+	count, err = ss.Tag().GetTagsCount(&model.GetTagsOptions{TeamId: team.Id, Type: model.TAG_TYPE_SYSTEM})
+	require.Nil(t, err)
+	assert.Equal(t, int64(0), count)
+}
+
+func testTagStoreImportTag(t *testing.T, ss store.Store) {
+	team := makeTeam(t, ss)
+	user := makeMember(t, ss, team.Id)
+
+	createAt := model.GetMillis() - 1000
+	require.Nil(t, ss.Tag().ImportTag(&model.Tag{Content: "golang", TeamId: team.Id, CreateAt: createAt}))
+	require.NotNil(t, ss.Tag().ImportTag(&model.Tag{Content: "go", TeamId: team.Id, CreateAt: createAt}))
+
+	options := &model.GetTagsOptions{TeamId: team.Id, Content: "golang", PerPage: 10}
+	tags, err := ss.Tag().GetTags(options)
+	require.Nil(t, err)
+	require.Len(t, tags, 1)
+	assert.Equal(t, 0, tags[0].PostCount)
+	assert.Equal(t, createAt, tags[0].CreateAt)
+
+	// 投稿数は投稿の保存時に数える
+	makeQuestion(t, ss, team.Id, user.Id, "golang")
+
+	// 既にあるタグは上書きしない
+	require.Nil(t, ss.Tag().ImportTag(&model.Tag{Content: "golang", TeamId: team.Id, CreateAt: model.GetMillis()}))
+
+	tags, err = ss.Tag().GetTags(options)
+	require.Nil(t, err)
+	require.Len(t, tags, 1)
+	assert.Equal(t, 1, tags[0].PostCount)
+	assert.Equal(t, createAt, tags[0].CreateAt)
+}
@@ -0,0 +1,132 @@
+package storetest
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/clear-ness/qa-discussion/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTeamStore(t *testing.T, ss store.Store) {
+	t.Run("Save", func(t *testing.T) { testTeamStoreSave(t, ss) })
+	t.Run("SaveMember", func(t *testing.T) { testTeamStoreSaveMember(t, ss) })
+	t.Run("GetActiveMemberCount", func(t *testing.T) { testTeamStoreGetActiveMemberCount(t, ss) })
+	t.Run("GetMembersCursor", func(t *testing.T) { testTeamStoreGetMembersCursor(t, ss) })
+}
+
+func testTeamStoreSave(t *testing.T, ss store.Store) {
+	team := makeTeam(t, ss)
+
+	got, err := ss.Team().Get(team.Id)
+	require.Nil(t, err)
+	assert.Equal(t, team.Name, got.Name)
+	assert.Equal(t, team.InviteId, got.InviteId)
+
+	_, err = ss.Team().Save(team)
+	require.NotNil(t, err, "should not save an existing team")
+
+	_, err = ss.Team().Save(&model.Team{
+		Name:  team.Name,
+		Email: model.NewId() + "@localhost",
+		Type:  model.TEAM_TYPE_PUBLIC,
+	})
+	require.NotNil(t, err, "should not save a duplicated name")
+	assert.Equal(t, "store.sql_team.save.domain_exists.app_error", err.Id)
+
+	_, err = ss.Team().Get(model.NewId())
+	require.NotNil(t, err)
+	assert.Equal(t, http.StatusNotFound, err.StatusCode)
+}
+
+func testTeamStoreSaveMember(t *testing.T, ss store.Store) {
+	team := makeTeam(t, ss)
+	user := makeMember(t, ss, team.Id)
+
+	member, err := ss.Team().GetMember(team.Id, user.Id)
+	require.Nil(t, err)
+	assert.Equal(t, model.TEAM_MEMBER_TYPE_NORMAL, member.Type)
+	assert.Equal(t, 0, member.Points)
+
+	_, err = ss.Team().SaveMember(member, -1)
+	require.NotNil(t, err, "should not save a duplicated member")
+
+	other := makeUser(t, ss)
+	_, err = ss.Team().SaveMember(&model.TeamMember{TeamId: team.Id, UserId: other.Id, Type: model.TEAM_MEMBER_TYPE_NORMAL}, 1)
+	require.NotNil(t, err, "should not exceed max users per team")
+	assert.Equal(t, "store.sql_user.save.max_accounts.app_error", err.Id)
+
+	// 1件でも保存できなければ全て保存しない
+	_, err = ss.Team().SaveMultipleMembers([]*model.TeamMember{
+		{TeamId: team.Id, UserId: other.Id, Type: model.TEAM_MEMBER_TYPE_NORMAL},
+		{TeamId: team.Id, UserId: user.Id, Type: model.TEAM_MEMBER_TYPE_NORMAL},
+	}, -1)
+	require.NotNil(t, err)
+
+	_, err = ss.Team().GetMember(team.Id, other.Id)
+	require.NotNil(t, err)
+	assert.Equal(t, http.StatusNotFound, err.StatusCode)
+}
+
+func testTeamStoreGetActiveMemberCount(t *testing.T, ss store.Store) {
+	team := makeTeam(t, ss)
+	user1 := makeMember(t, ss, team.Id)
+	user2 := makeMember(t, ss, team.Id)
+	makeMember(t, ss, team.Id)
+
+	count, err := ss.Team().GetActiveMemberCount(team.Id)
+	require.Nil(t, err)
+	assert.Equal(t, int64(3), count)
+
+	member, err := ss.Team().GetMember(team.Id, user1.Id)
+	require.Nil(t, err)
+	member.DeleteAt = model.GetMillis()
+	_, err = ss.Team().UpdateMember(member)
+	require.Nil(t, err)
+
+	require.Nil(t, ss.User().Delete(user2.Id, model.GetMillis(), user2.Id))
+
+	count, err = ss.Team().GetActiveMemberCount(team.Id)
+	require.Nil(t, err)
+	assert.Equal(t, int64(1), count)
+}
+
+func testTeamStoreGetMembersCursor(t *testing.T, ss store.Store) {
+	team := makeTeam(t, ss)
+	for i := 0; i < 5; i++ {
+		makeMember(t, ss, team.Id)
+	}
+
+	for _, sortType := range []string{"", model.TEAM_MEMBER_SORT_TYPE_USERNAME} {
+		t.Run("sort="+sortType, func(t *testing.T) {
+			all, err := ss.Team().GetMembers(team.Id, 0, 100, &model.TeamMembersGetOptions{Sort: sortType})
+			require.Nil(t, err)
+			require.Len(t, all, 5)
+
+			cursorOf := func(member *model.TeamMember) *model.Cursor {
+				if sortType != model.TEAM_MEMBER_SORT_TYPE_USERNAME {
+					return model.NewCursor(model.CURSOR_TYPE_TEAM_MEMBERS, sortType, 0, member.UserId)
+				}
+				user, err := ss.User().Get(member.UserId)
+				require.Nil(t, err)
+				return model.NewCursor(model.CURSOR_TYPE_TEAM_MEMBERS, sortType, 0, user.Username, member.UserId)
+			}
+
+			members, err := ss.Team().GetMembers(team.Id, 0, 2, &model.TeamMembersGetOptions{Sort: sortType, Cursor: cursorOf(all[1])})
+			require.Nil(t, err)
+			require.Len(t, members, 2)
+			assert.Equal(t, all[2].UserId, members[0].UserId)
+			assert.Equal(t, all[3].UserId, members[1].UserId)
+
+			cursor := cursorOf(all[4])
+			cursor.Before = true
+			members, err = ss.Team().GetMembers(team.Id, 0, 2, &model.TeamMembersGetOptions{Sort: sortType, Cursor: cursor})
+			require.Nil(t, err)
+			require.Len(t, members, 2)
+			assert.Equal(t, all[2].UserId, members[0].UserId)
+			assert.Equal(t, all[3].UserId, members[1].UserId)
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package storetest
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/clear-ness/qa-discussion/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserGroupStore(t *testing.T, ss store.Store) {
+	t.Run("Save", func(t *testing.T) { testUserGroupStoreSave(t, ss) })
+	t.Run("Delete", func(t *testing.T) { testUserGroupStoreDelete(t, ss) })
+}
+
+func makeGroup(t *testing.T, ss store.Store, teamId, userId, name string) *model.UserGroup {
+	group, err := ss.UserGroup().Save(&model.UserGroup{
+		Type:   model.GROUP_TYPE_PUBLIC,
+		TeamId: teamId,
+		Name:   name,
+		UserId: userId,
+	}, -1)
+	require.Nil(t, err)
+
+	return group
+}
+
+func testUserGroupStoreSave(t *testing.T, ss store.Store) {
+	team := makeTeam(t, ss)
+	user := makeMember(t, ss, team.Id)
+
+	group := makeGroup(t, ss, team.Id, user.Id, "backend")
+
+	got, err := ss.UserGroup().Get(group.Id)
+	require.Nil(t, err)
+	assert.Equal(t, "backend", got.Name)
+
+	_, err = ss.UserGroup().Save(&model.UserGroup{TeamId: team.Id, Name: "backend", UserId: user.Id}, -1)
+	require.NotNil(t, err, "should not save a duplicated name")
+	assert.Equal(t, "store.sql_group.save.exists.app_error", err.Id)
+
+	_, err = ss.UserGroup().Save(&model.UserGroup{TeamId: team.Id, Name: "frontend", UserId: user.Id}, 1)
+	require.NotNil(t, err)
+	assert.Equal(t, "store.sql_group.save.too_many.app_error", err.Id)
+
+	_, err = ss.UserGroup().Get(model.NewId())
+	require.NotNil(t, err)
+	assert.Equal(t, http.StatusNotFound, err.StatusCode)
+}
+
+func testUserGroupStoreDelete(t *testing.T, ss store.Store) {
+	team := makeTeam(t, ss)
+	user := makeMember(t, ss, team.Id)
+
+	group := makeGroup(t, ss, team.Id, user.Id, "backend")
+	other := makeGroup(t, ss, team.Id, user.Id, "admins")
+
+	_, err := ss.UserGroup().SaveMultipleMembers([]*model.GroupMember{
+		{GroupId: group.Id, UserId: user.Id, Type: model.GROUP_MEMBER_TYPE_ADMIN},
+		{GroupId: other.Id, UserId: user.Id, Type: model.GROUP_MEMBER_TYPE_NORMAL},
+	})
+	require.Nil(t, err)
+
+	groups, err := ss.UserGroup().GetGroups(team.Id, user.Id, false)
+	require.Nil(t, err)
+	require.Len(t, *groups, 2)
+	assert.Equal(t, "admins", (*groups)[0].Name)
+	assert.Equal(t, "backend", (*groups)[1].Name)
+
+	require.Nil(t, ss.UserGroup().Delete(group.Id, model.GetMillis()))
+	require.NotNil(t, ss.UserGroup().Delete(group.Id, model.GetMillis()), "should not delete twice")
+
+	// 論理削除なので取得はできる
+	got, err := ss.UserGroup().Get(group.Id)
+	require.Nil(t, err)
+	assert.NotZero(t, got.DeleteAt)
+
+	groups, err = ss.UserGroup().GetGroups(team.Id, user.Id, false)
+	require.Nil(t, err)
+	require.Len(t, *groups, 1)
+	assert.Equal(t, other.Id, (*groups)[0].Id)
+
+	groups, err = ss.UserGroup().GetGroups(team.Id, user.Id, true)
+	require.Nil(t, err)
+	assert.Len(t, *groups, 2)
+
+	// 削除済みのグループと同じ名前は使えない
+	_, err = ss.UserGroup().Save(&model.UserGroup{TeamId: team.Id, Name: "backend", UserId: user.Id}, -1)
+	require.NotNil(t, err)
+}
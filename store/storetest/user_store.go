@@ -0,0 +1,70 @@
+package storetest
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/clear-ness/qa-discussion/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserStore(t *testing.T, ss store.Store) {
+	t.Run("Save", func(t *testing.T) { testUserStoreSave(t, ss) })
+	t.Run("Delete", func(t *testing.T) { testUserStoreDelete(t, ss) })
+}
+
+func testUserStoreSave(t *testing.T, ss store.Store) {
+	user := makeUser(t, ss)
+
+	got, err := ss.User().GetByEmail(strings.ToUpper(user.Email))
+	require.Nil(t, err)
+	assert.Equal(t, user.Id, got.Id)
+
+	got, err = ss.User().GetByUsername(user.Username)
+	require.Nil(t, err)
+	assert.Equal(t, user.Id, got.Id)
+
+	_, err = ss.User().Save(&model.User{
+		Email: strings.ToUpper(user.Email),
+		Type:  model.USER_TYPE_NORMAL,
+	})
+	require.NotNil(t, err, "should not save a duplicated email")
+	assert.Equal(t, "store.sql_user.save.email_exists.app_error", err.Id)
+}
+
+func testUserStoreDelete(t *testing.T, ss store.Store) {
+	before, err := ss.User().Count(&model.UserCountOptions{})
+	require.Nil(t, err)
+
+	user1 := makeUser(t, ss)
+	user2 := makeUser(t, ss)
+
+	count, err := ss.User().Count(&model.UserCountOptions{})
+	require.Nil(t, err)
+	assert.Equal(t, before+2, count)
+
+	require.Nil(t, ss.User().Delete(user1.Id, model.GetMillis(), user2.Id))
+
+	_, err = ss.User().Get(user1.Id)
+	require.NotNil(t, err)
+	assert.Equal(t, http.StatusNotFound, err.StatusCode)
+
+	// 論理削除なのでメールアドレスでは引ける
+	got, err := ss.User().GetByEmail(user1.Email)
+	require.Nil(t, err)
+	assert.NotZero(t, got.DeleteAt)
+	assert.Equal(t, user2.Id, got.Props[model.USER_PROPS_DELETE_BY])
+
+	count, err = ss.User().Count(&model.UserCountOptions{})
+	require.Nil(t, err)
+	assert.Equal(t, before+1, count)
+
+	count, err = ss.User().Count(&model.UserCountOptions{IncludeDeleted: true})
+	require.Nil(t, err)
+	assert.Equal(t, before+2, count)
+
+	require.NotNil(t, ss.User().Delete(user1.Id, model.GetMillis(), user2.Id), "should not delete twice")
+}
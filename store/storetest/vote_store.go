@@ -0,0 +1,98 @@
+package storetest
+
+import (
+	"testing"
+
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/clear-ness/qa-discussion/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVoteStore(t *testing.T, ss store.Store) {
+	t.Run("GetVotes", func(t *testing.T) { testVoteStoreGetVotes(t, ss) })
+	t.Run("Reviews", func(t *testing.T) { testVoteStoreReviews(t, ss) })
+}
+
+func testVoteStoreGetVotes(t *testing.T, ss store.Store) {
+	team := makeTeam(t, ss)
+	user := makeMember(t, ss, team.Id)
+	voter := makeMember(t, ss, team.Id)
+
+	question := makeQuestion(t, ss, team.Id, user.Id, "golang")
+
+	vote, err := ss.Vote().GetByPostIdForUser(voter.Id, question.Id, model.VOTE_TYPE_UP_VOTE)
+	require.Nil(t, err)
+	assert.Nil(t, vote)
+
+	_, err = ss.Post().UpVotePost(question.Id, voter.Id)
+	require.Nil(t, err)
+	_, err = ss.Post().FlagPost(question.Id, voter.Id)
+	require.Nil(t, err)
+
+	vote, err = ss.Vote().GetByPostIdForUser(voter.Id, question.Id, model.VOTE_TYPE_UP_VOTE)
+	require.Nil(t, err)
+	require.NotNil(t, vote)
+	assert.Equal(t, team.Id, vote.TeamId)
+
+	types, err := ss.Vote().GetVoteTypesForPost(voter.Id, question.Id)
+	require.Nil(t, err)
+	assert.ElementsMatch(t, []string{model.VOTE_TYPE_UP_VOTE, model.VOTE_TYPE_FLAG}, types)
+
+	votes, count, err := ss.Vote().GetVotesBeforeTime(model.GetMillis(), voter.Id, 0, 10, false, true, team.Id, nil)
+	require.Nil(t, err)
+	assert.Len(t, votes, 2)
+	assert.Equal(t, int64(2), count)
+
+	votes, count, err = ss.Vote().GetVotesBeforeTime(model.GetMillis(), voter.Id, 0, 10, true, true, team.Id, nil)
+	require.Nil(t, err)
+	require.Len(t, votes, 1)
+	assert.Equal(t, model.VOTE_TYPE_UP_VOTE, votes[0].Type)
+	assert.Equal(t, int64(1), count)
+}
+
+func testVoteStoreReviews(t *testing.T, ss store.Store) {
+	team := makeTeam(t, ss)
+	user := makeMember(t, ss, team.Id)
+	reviewer := makeMember(t, ss, team.Id)
+
+	// 最初の投稿にはシステムのレビューが付くので、2つ目の投稿を使う
+	makeQuestion(t, ss, team.Id, user.Id, "golang")
+	question := makeQuestion(t, ss, team.Id, user.Id, "golang")
+
+	review, err := ss.Vote().CreateReviewVote(question, reviewer.Id, "golang", 1)
+	require.Nil(t, err)
+	assert.Equal(t, model.VOTE_TYPE_REVIEW, review.Type)
+
+	_, err = ss.Vote().CreateReviewVote(question, reviewer.Id, "golang", 1)
+	require.NotNil(t, err, "should not review twice")
+
+	options := &model.SearchReviewsOptions{
+		TeamId:     team.Id,
+		ReviewType: model.VOTE_TYPE_REVIEW,
+		PerPage:    10,
+	}
+
+	reviews, count, err := ss.Vote().GetReviews(options, true)
+	require.Nil(t, err)
+	require.Len(t, reviews, 1)
+	assert.Equal(t, question.Id, reviews[0].PostId)
+	assert.Equal(t, int64(1), count)
+
+	require.Nil(t, ss.Vote().RejectReviewsForPost(question.Id, reviewer.Id, 1))
+
+	rejected, err := ss.Vote().GetRejectedReviewsCount(question.Id, 1)
+	require.Nil(t, err)
+	assert.Equal(t, int64(1), rejected)
+
+	reviews, count, err = ss.Vote().GetReviews(options, true)
+	require.Nil(t, err)
+	assert.Len(t, reviews, 0)
+	assert.Equal(t, int64(0), count)
+
+	options.IncludeRejected = true
+	reviews, _, err = ss.Vote().GetReviews(options, false)
+	require.Nil(t, err)
+	require.Len(t, reviews, 1)
+	assert.Equal(t, reviewer.Id, reviews[0].RejectedBy)
+}
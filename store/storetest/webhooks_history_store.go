@@ -0,0 +1,143 @@
+package storetest
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/clear-ness/qa-discussion/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhooksHistoryStore(t *testing.T, ss store.Store) {
+	t.Run("Get", func(t *testing.T) { testWebhooksHistoryStoreGet(t, ss) })
+	t.Run("Search", func(t *testing.T) { testWebhooksHistoryStoreSearch(t, ss) })
+	t.Run("PermanentDeleteBefore", func(t *testing.T) { testWebhooksHistoryStorePermanentDeleteBefore(t, ss) })
+}
+
+func makeWebhooksHistory(t *testing.T, ss store.Store, teamId string, webhookId string, status int, createAt int64) *model.WebhooksHistory {
+	history := &model.WebhooksHistory{
+		Id:             model.NewId(),
+		WebhookId:      webhookId,
+		TeamId:         teamId,
+		URL:            "https://example.com/hook",
+		ContentType:    "application/json",
+		RequestBody:    `{"event":"ping"}`,
+		ResponseStatus: status,
+		RequestHeaders: model.StringMap{},
+		DeliveryId:     model.NewId(),
+		Attempt:        1,
+		CreateAt:       createAt,
+	}
+	require.NoError(t, ss.WebhooksHistory().LogWebhookEvent(history))
+
+	return history
+}
+
+func historyIds(histories []*model.WebhooksHistory) []string {
+	ids := []string{}
+	for _, history := range histories {
+		ids = append(ids, history.Id)
+	}
+
+	return ids
+}
+
+func testWebhooksHistoryStoreGet(t *testing.T, ss store.Store) {
+	history := makeWebhooksHistory(t, ss, model.NewId(), model.NewId(), http.StatusOK, model.GetMillis())
+
+	got, err := ss.WebhooksHistory().Get(history.Id)
+	require.Nil(t, err)
+	assert.Equal(t, history.DeliveryId, got.DeliveryId)
+	assert.Equal(t, http.StatusOK, got.ResponseStatus)
+
+	_, err = ss.WebhooksHistory().Get(model.NewId())
+	require.NotNil(t, err)
+	assert.Equal(t, http.StatusNotFound, err.StatusCode)
+}
+
+func testWebhooksHistoryStoreSearch(t *testing.T, ss store.Store) {
+	teamId := model.NewId()
+	hookId := model.NewId()
+	otherHookId := model.NewId()
+	now := model.GetMillis()
+
+	ok := makeWebhooksHistory(t, ss, teamId, hookId, http.StatusOK, now-3000)
+	notFound := makeWebhooksHistory(t, ss, teamId, hookId, http.StatusNotFound, now-2000)
+	serverError := makeWebhooksHistory(t, ss, teamId, hookId, http.StatusInternalServerError, now-1000)
+	other := makeWebhooksHistory(t, ss, teamId, otherHookId, http.StatusOK, now)
+	// 別チームの履歴は含まない
+	makeWebhooksHistory(t, ss, model.NewId(), hookId, http.StatusOK, now)
+
+	search := func(options model.SearchWebhooksHistoryOptions) []string {
+		options.TeamId = teamId
+		options.PerPage = 10
+		histories, err := ss.WebhooksHistory().Search(&options)
+		require.Nil(t, err)
+		return historyIds(histories)
+	}
+
+	t.Run("team", func(t *testing.T) {
+		// 新しい順
+		assert.Equal(t, []string{other.Id, serverError.Id, notFound.Id, ok.Id}, search(model.SearchWebhooksHistoryOptions{}))
+	})
+
+	t.Run("webhook id", func(t *testing.T) {
+		assert.Equal(t, []string{serverError.Id, notFound.Id, ok.Id}, search(model.SearchWebhooksHistoryOptions{WebhookId: hookId}))
+		assert.Equal(t, []string{other.Id}, search(model.SearchWebhooksHistoryOptions{WebhookId: otherHookId}))
+		assert.Empty(t, search(model.SearchWebhooksHistoryOptions{WebhookId: model.NewId()}))
+	})
+
+	t.Run("status range", func(t *testing.T) {
+		assert.Equal(t, []string{serverError.Id, notFound.Id}, search(model.SearchWebhooksHistoryOptions{WebhookId: hookId, MinStatus: 400}))
+		assert.Equal(t, []string{notFound.Id, ok.Id}, search(model.SearchWebhooksHistoryOptions{WebhookId: hookId, MaxStatus: 499}))
+		// 範囲の両端を含む
+		assert.Equal(t, []string{notFound.Id}, search(model.SearchWebhooksHistoryOptions{WebhookId: hookId, MinStatus: 404, MaxStatus: 404}))
+	})
+
+	t.Run("time window", func(t *testing.T) {
+		assert.Equal(t, []string{serverError.Id, notFound.Id}, search(model.SearchWebhooksHistoryOptions{WebhookId: hookId, FromDate: now - 2000}))
+		assert.Equal(t, []string{notFound.Id, ok.Id}, search(model.SearchWebhooksHistoryOptions{WebhookId: hookId, ToDate: now - 2000}))
+		assert.Equal(t, []string{notFound.Id}, search(model.SearchWebhooksHistoryOptions{FromDate: now - 2000, ToDate: now - 2000}))
+	})
+
+	t.Run("paging", func(t *testing.T) {
+		histories, err := ss.WebhooksHistory().Search(&model.SearchWebhooksHistoryOptions{TeamId: teamId, Page: 1, PerPage: 3})
+		require.Nil(t, err)
+		assert.Equal(t, []string{ok.Id}, historyIds(histories))
+	})
+}
+
+func testWebhooksHistoryStorePermanentDeleteBefore(t *testing.T, ss store.Store) {
+	teamId := model.NewId()
+	hookId := model.NewId()
+	now := model.GetMillis()
+	retention := int64(30 * 24 * 60 * 60 * 1000)
+
+	oldest := makeWebhooksHistory(t, ss, teamId, hookId, http.StatusOK, now-retention-2000)
+	old := makeWebhooksHistory(t, ss, teamId, hookId, http.StatusOK, now-retention-1000)
+	recent := makeWebhooksHistory(t, ss, teamId, hookId, http.StatusOK, now-retention+1000)
+
+	// limit件ずつ古いものから削除する
+	deleted, err := ss.WebhooksHistory().PermanentDeleteBefore(now-retention, 1)
+	require.Nil(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	_, err = ss.WebhooksHistory().Get(oldest.Id)
+	require.NotNil(t, err)
+	_, err = ss.WebhooksHistory().Get(old.Id)
+	require.Nil(t, err)
+
+	deleted, err = ss.WebhooksHistory().PermanentDeleteBefore(now-retention, 10)
+	require.Nil(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	deleted, err = ss.WebhooksHistory().PermanentDeleteBefore(now-retention, 10)
+	require.Nil(t, err)
+	assert.Equal(t, int64(0), deleted)
+
+	histories, err := ss.WebhooksHistory().Search(&model.SearchWebhooksHistoryOptions{TeamId: teamId, PerPage: 10})
+	require.Nil(t, err)
+	assert.Equal(t, []string{recent.Id}, historyIds(histories))
+}
@@ -0,0 +1,3562 @@
+// Code generated by store/layer_generators. DO NOT EDIT.
+
+package timerlayer
+
+import (
+	"context"
+	timemodule "time"
+
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/clear-ness/qa-discussion/services/metrics"
+	"github.com/clear-ness/qa-discussion/store"
+)
+
+type TimerLayer struct {
+	store.Store
+	Metrics                  metrics.MetricsInterface
+	TeamStore                store.TeamStore
+	TeamMemberHistoryStore   store.TeamMemberHistoryStore
+	UserGroupStore           store.UserGroupStore
+	GroupMemberHistoryStore  store.GroupMemberHistoryStore
+	CollectionStore          store.CollectionStore
+	UserStore                store.UserStore
+	TokenStore               store.TokenStore
+	SessionStore             store.SessionStore
+	PostStore                store.PostStore
+	TagStore                 store.TagStore
+	VoteStore                store.VoteStore
+	UserPointHistoryStore    store.UserPointHistoryStore
+	InboxMessageStore        store.InboxMessageStore
+	UserFavoritePostStore    store.UserFavoritePostStore
+	FileInfoStore            store.FileInfoStore
+	NotificationSettingStore store.NotificationSettingStore
+	PostViewsHistoryStore    store.PostViewsHistoryStore
+	WebhookStore             store.WebhookStore
+	WebhooksHistoryStore     store.WebhooksHistoryStore
+	WebhookDeliveryStore     store.WebhookDeliveryStore
+	IncomingWebhookStore     store.IncomingWebhookStore
+	BotStore                 store.BotStore
+	AuditStore               store.AuditStore
+	OAuthStore               store.OAuthStore
+	StatusStore              store.StatusStore
+	JobStore                 store.JobStore
+	SystemStore              store.SystemStore
+}
+
+func (s *TimerLayer) Team() store.TeamStore {
+	return s.TeamStore
+}
+
+func (s *TimerLayer) TeamMemberHistory() store.TeamMemberHistoryStore {
+	return s.TeamMemberHistoryStore
+}
+
+func (s *TimerLayer) UserGroup() store.UserGroupStore {
+	return s.UserGroupStore
+}
+
+func (s *TimerLayer) GroupMemberHistory() store.GroupMemberHistoryStore {
+	return s.GroupMemberHistoryStore
+}
+
+func (s *TimerLayer) Collection() store.CollectionStore {
+	return s.CollectionStore
+}
+
+func (s *TimerLayer) User() store.UserStore {
+	return s.UserStore
+}
+
+func (s *TimerLayer) Token() store.TokenStore {
+	return s.TokenStore
+}
+
+func (s *TimerLayer) Session() store.SessionStore {
+	return s.SessionStore
+}
+
+func (s *TimerLayer) Post() store.PostStore {
+	return s.PostStore
+}
+
+func (s *TimerLayer) Tag() store.TagStore {
+	return s.TagStore
+}
+
+func (s *TimerLayer) Vote() store.VoteStore {
+	return s.VoteStore
+}
+
+func (s *TimerLayer) UserPointHistory() store.UserPointHistoryStore {
+	return s.UserPointHistoryStore
+}
+
+func (s *TimerLayer) InboxMessage() store.InboxMessageStore {
+	return s.InboxMessageStore
+}
+
+func (s *TimerLayer) UserFavoritePost() store.UserFavoritePostStore {
+	return s.UserFavoritePostStore
+}
+
+func (s *TimerLayer) FileInfo() store.FileInfoStore {
+	return s.FileInfoStore
+}
+
+func (s *TimerLayer) NotificationSetting() store.NotificationSettingStore {
+	return s.NotificationSettingStore
+}
+
+func (s *TimerLayer) PostViewsHistory() store.PostViewsHistoryStore {
+	return s.PostViewsHistoryStore
+}
+
+func (s *TimerLayer) Webhook() store.WebhookStore {
+	return s.WebhookStore
+}
+
+func (s *TimerLayer) WebhooksHistory() store.WebhooksHistoryStore {
+	return s.WebhooksHistoryStore
+}
+
+func (s *TimerLayer) WebhookDelivery() store.WebhookDeliveryStore {
+	return s.WebhookDeliveryStore
+}
+
+func (s *TimerLayer) IncomingWebhook() store.IncomingWebhookStore {
+	return s.IncomingWebhookStore
+}
+
+func (s *TimerLayer) Bot() store.BotStore {
+	return s.BotStore
+}
+
+func (s *TimerLayer) Audit() store.AuditStore {
+	return s.AuditStore
+}
+
+func (s *TimerLayer) OAuth() store.OAuthStore {
+	return s.OAuthStore
+}
+
+func (s *TimerLayer) Status() store.StatusStore {
+	return s.StatusStore
+}
+
+func (s *TimerLayer) Job() store.JobStore {
+	return s.JobStore
+}
+
+func (s *TimerLayer) System() store.SystemStore {
+	return s.SystemStore
+}
+
+func (s *TimerLayer) WithContext(ctx context.Context) store.Store {
+	return New(s.Store.WithContext(ctx), s.Metrics)
+}
+
+type TimerLayerTeamStore struct {
+	store.TeamStore
+	Root *TimerLayer
+}
+
+type TimerLayerTeamMemberHistoryStore struct {
+	store.TeamMemberHistoryStore
+	Root *TimerLayer
+}
+
+type TimerLayerUserGroupStore struct {
+	store.UserGroupStore
+	Root *TimerLayer
+}
+
+type TimerLayerGroupMemberHistoryStore struct {
+	store.GroupMemberHistoryStore
+	Root *TimerLayer
+}
+
+type TimerLayerCollectionStore struct {
+	store.CollectionStore
+	Root *TimerLayer
+}
+
+type TimerLayerUserStore struct {
+	store.UserStore
+	Root *TimerLayer
+}
+
+type TimerLayerTokenStore struct {
+	store.TokenStore
+	Root *TimerLayer
+}
+
+type TimerLayerSessionStore struct {
+	store.SessionStore
+	Root *TimerLayer
+}
+
+type TimerLayerPostStore struct {
+	store.PostStore
+	Root *TimerLayer
+}
+
+type TimerLayerTagStore struct {
+	store.TagStore
+	Root *TimerLayer
+}
+
+type TimerLayerVoteStore struct {
+	store.VoteStore
+	Root *TimerLayer
+}
+
+type TimerLayerUserPointHistoryStore struct {
+	store.UserPointHistoryStore
+	Root *TimerLayer
+}
+
+type TimerLayerInboxMessageStore struct {
+	store.InboxMessageStore
+	Root *TimerLayer
+}
+
+type TimerLayerUserFavoritePostStore struct {
+	store.UserFavoritePostStore
+	Root *TimerLayer
+}
+
+type TimerLayerFileInfoStore struct {
+	store.FileInfoStore
+	Root *TimerLayer
+}
+
+type TimerLayerNotificationSettingStore struct {
+	store.NotificationSettingStore
+	Root *TimerLayer
+}
+
+type TimerLayerPostViewsHistoryStore struct {
+	store.PostViewsHistoryStore
+	Root *TimerLayer
+}
+
+type TimerLayerWebhookStore struct {
+	store.WebhookStore
+	Root *TimerLayer
+}
+
+type TimerLayerWebhooksHistoryStore struct {
+	store.WebhooksHistoryStore
+	Root *TimerLayer
+}
+
+type TimerLayerWebhookDeliveryStore struct {
+	store.WebhookDeliveryStore
+	Root *TimerLayer
+}
+
+type TimerLayerIncomingWebhookStore struct {
+	store.IncomingWebhookStore
+	Root *TimerLayer
+}
+
+type TimerLayerBotStore struct {
+	store.BotStore
+	Root *TimerLayer
+}
+
+type TimerLayerAuditStore struct {
+	store.AuditStore
+	Root *TimerLayer
+}
+
+type TimerLayerOAuthStore struct {
+	store.OAuthStore
+	Root *TimerLayer
+}
+
+type TimerLayerStatusStore struct {
+	store.StatusStore
+	Root *TimerLayer
+}
+
+type TimerLayerJobStore struct {
+	store.JobStore
+	Root *TimerLayer
+}
+
+type TimerLayerSystemStore struct {
+	store.SystemStore
+	Root *TimerLayer
+}
+
+func (s *TimerLayerTeamStore) Get(id string) (*model.Team, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.TeamStore.Get(id)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("TeamStore.Get", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerTeamStore) GetByInviteId(inviteId string) (*model.Team, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.TeamStore.GetByInviteId(inviteId)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("TeamStore.GetByInviteId", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerTeamStore) Save(team *model.Team) (*model.Team, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.TeamStore.Save(team)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("TeamStore.Save", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerTeamStore) GetMember(teamId string, userId string) (*model.TeamMember, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.TeamStore.GetMember(teamId, userId)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("TeamStore.GetMember", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerTeamStore) GetMembers(teamId string, offset int, limit int, teamMembersGetOptions *model.TeamMembersGetOptions) ([]*model.TeamMember, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.TeamStore.GetMembers(teamId, offset, limit, teamMembersGetOptions)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("TeamStore.GetMembers", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerTeamStore) GetMembersByIds(teamId string, userIds []string) ([]*model.TeamMember, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.TeamStore.GetMembersByIds(teamId, userIds)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("TeamStore.GetMembersByIds", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerTeamStore) SaveMember(member *model.TeamMember, maxUsersPerTeam int) (*model.TeamMember, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.TeamStore.SaveMember(member, maxUsersPerTeam)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("TeamStore.SaveMember", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerTeamStore) SaveMultipleMembers(members []*model.TeamMember, maxUsersPerTeam int) ([]*model.TeamMember, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.TeamStore.SaveMultipleMembers(members, maxUsersPerTeam)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("TeamStore.SaveMultipleMembers", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerTeamStore) GetActiveMemberCount(teamId string) (int64, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.TeamStore.GetActiveMemberCount(teamId)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("TeamStore.GetActiveMemberCount", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerTeamStore) UpdateMember(member *model.TeamMember) (*model.TeamMember, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.TeamStore.UpdateMember(member)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("TeamStore.UpdateMember", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerTeamStore) UpdateMultipleMembers(members []*model.TeamMember) ([]*model.TeamMember, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.TeamStore.UpdateMultipleMembers(members)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("TeamStore.UpdateMultipleMembers", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerTeamStore) GetTeamsByUserId(userId string) ([]*model.Team, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.TeamStore.GetTeamsByUserId(userId)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("TeamStore.GetTeamsByUserId", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerTeamStore) GetTeamsForUser(userId string) ([]*model.TeamMember, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.TeamStore.GetTeamsForUser(userId)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("TeamStore.GetTeamsForUser", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerTeamStore) Update(team *model.Team) (*model.Team, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.TeamStore.Update(team)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("TeamStore.Update", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerTeamStore) PermanentDelete(teamId string) *model.AppError {
+	start := timemodule.Now()
+
+	err := s.TeamStore.PermanentDelete(teamId)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("TeamStore.PermanentDelete", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerTeamStore) RemoveAllMembersByTeam(teamId string) *model.AppError {
+	start := timemodule.Now()
+
+	err := s.TeamStore.RemoveAllMembersByTeam(teamId)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("TeamStore.RemoveAllMembersByTeam", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerTeamStore) UpdateLastTeamIconUpdate(teamId string, curTime int64) *model.AppError {
+	start := timemodule.Now()
+
+	err := s.TeamStore.UpdateLastTeamIconUpdate(teamId, curTime)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("TeamStore.UpdateLastTeamIconUpdate", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerTeamStore) AutocompletePublic(name string) ([]*model.Team, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.TeamStore.AutocompletePublic(name)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("TeamStore.AutocompletePublic", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerTeamStore) GetAllWithAllowedDomains() ([]*model.Team, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.TeamStore.GetAllWithAllowedDomains()
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("TeamStore.GetAllWithAllowedDomains", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerTeamStore) GetMemberPointsDrifts(after *model.CounterCursor, limit int, importedPropKey string) (*model.CounterDriftBatch, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.TeamStore.GetMemberPointsDrifts(after, limit, importedPropKey)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("TeamStore.GetMemberPointsDrifts", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerTeamStore) FixMemberPointsDrift(drift *model.CounterDrift) (bool, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.TeamStore.FixMemberPointsDrift(drift)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("TeamStore.FixMemberPointsDrift", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerTeamMemberHistoryStore) LogJoinEvent(userId string, teamId string, joinTime int64) error {
+	start := timemodule.Now()
+
+	err := s.TeamMemberHistoryStore.LogJoinEvent(userId, teamId, joinTime)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("TeamMemberHistoryStore.LogJoinEvent", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerTeamMemberHistoryStore) LogLeaveEvent(userId string, teamId string, leaveTime int64) error {
+	start := timemodule.Now()
+
+	err := s.TeamMemberHistoryStore.LogLeaveEvent(userId, teamId, leaveTime)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("TeamMemberHistoryStore.LogLeaveEvent", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerUserGroupStore) GetTeamGroups(teamId string) (*model.UserGroupList, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.UserGroupStore.GetTeamGroups(teamId)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("UserGroupStore.GetTeamGroups", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerUserGroupStore) Save(group *model.UserGroup, maxGroupsPerTeam int64) (*model.UserGroup, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.UserGroupStore.Save(group, maxGroupsPerTeam)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("UserGroupStore.Save", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerUserGroupStore) SaveMember(member *model.GroupMember) (*model.GroupMember, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.UserGroupStore.SaveMember(member)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("UserGroupStore.SaveMember", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerUserGroupStore) SaveMultipleMembers(members []*model.GroupMember) ([]*model.GroupMember, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.UserGroupStore.SaveMultipleMembers(members)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("UserGroupStore.SaveMultipleMembers", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerUserGroupStore) GetGroupsForTeam(teamId string, groupType string, offset int, limit int) (*model.UserGroupList, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.UserGroupStore.GetGroupsForTeam(teamId, groupType, offset, limit)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("UserGroupStore.GetGroupsForTeam", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerUserGroupStore) AutocompleteInTeam(teamId string, term string, groupType string, includeDeleted bool) (*model.UserGroupList, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.UserGroupStore.AutocompleteInTeam(teamId, term, groupType, includeDeleted)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("UserGroupStore.AutocompleteInTeam", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerUserGroupStore) GetGroups(teamId string, userId string, includeDeleted bool) (*model.UserGroupList, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.UserGroupStore.GetGroups(teamId, userId, includeDeleted)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("UserGroupStore.GetGroups", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerUserGroupStore) Get(id string) (*model.UserGroup, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.UserGroupStore.Get(id)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("UserGroupStore.Get", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerUserGroupStore) GetAllGroupMembersForUser(userId string) (map[string]string, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.UserGroupStore.GetAllGroupMembersForUser(userId)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("UserGroupStore.GetAllGroupMembersForUser", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerUserGroupStore) Update(group *model.UserGroup) (*model.UserGroup, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.UserGroupStore.Update(group)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("UserGroupStore.Update", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerUserGroupStore) Delete(groupId string, time int64) *model.AppError {
+	start := timemodule.Now()
+
+	err := s.UserGroupStore.Delete(groupId, time)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("UserGroupStore.Delete", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerUserGroupStore) GetMembers(groupId string, memberType string, offset int, limit int, cursor *model.Cursor) (*model.GroupMembers, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.UserGroupStore.GetMembers(groupId, memberType, offset, limit, cursor)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("UserGroupStore.GetMembers", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerUserGroupStore) GetMember(groupId string, userId string) (*model.GroupMember, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.UserGroupStore.GetMember(groupId, userId)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("UserGroupStore.GetMember", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerUserGroupStore) UpdateMember(member *model.GroupMember) (*model.GroupMember, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.UserGroupStore.UpdateMember(member)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("UserGroupStore.UpdateMember", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerUserGroupStore) UpdateMultipleMembers(members []*model.GroupMember) ([]*model.GroupMember, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.UserGroupStore.UpdateMultipleMembers(members)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("UserGroupStore.UpdateMultipleMembers", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerUserGroupStore) RemoveMembers(groupId string, userIds []string) *model.AppError {
+	start := timemodule.Now()
+
+	err := s.UserGroupStore.RemoveMembers(groupId, userIds)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("UserGroupStore.RemoveMembers", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerUserGroupStore) RemoveMember(groupId string, userId string) *model.AppError {
+	start := timemodule.Now()
+
+	err := s.UserGroupStore.RemoveMember(groupId, userId)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("UserGroupStore.RemoveMember", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerGroupMemberHistoryStore) LogJoinEvent(userId string, groupId string, joinTime int64) error {
+	start := timemodule.Now()
+
+	err := s.GroupMemberHistoryStore.LogJoinEvent(userId, groupId, joinTime)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("GroupMemberHistoryStore.LogJoinEvent", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerGroupMemberHistoryStore) LogLeaveEvent(userId string, groupId string, leaveTime int64) error {
+	start := timemodule.Now()
+
+	err := s.GroupMemberHistoryStore.LogLeaveEvent(userId, groupId, leaveTime)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("GroupMemberHistoryStore.LogLeaveEvent", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerCollectionStore) Get(id string) (*model.Collection, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.CollectionStore.Get(id)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("CollectionStore.Get", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerCollectionStore) GetPost(collectionId string, postId string) (*model.CollectionPost, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.CollectionStore.GetPost(collectionId, postId)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("CollectionStore.GetPost", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerCollectionStore) GetPosts(collectionId string, offset int, limit int) (*model.CollectionPosts, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.CollectionStore.GetPosts(collectionId, offset, limit)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("CollectionStore.GetPosts", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerCollectionStore) GetCollectionsForTeam(teamId string, offset int, limit int, title string, cursor *model.Cursor) (*model.CollectionList, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.CollectionStore.GetCollectionsForTeam(teamId, offset, limit, title, cursor)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("CollectionStore.GetCollectionsForTeam", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerCollectionStore) GetTeamCollections(teamId string) (*model.CollectionList, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.CollectionStore.GetTeamCollections(teamId)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("CollectionStore.GetTeamCollections", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerCollectionStore) Save(collection *model.Collection, maxCollectionsPerTeam int64) (*model.Collection, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.CollectionStore.Save(collection, maxCollectionsPerTeam)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("CollectionStore.Save", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerCollectionStore) SavePost(colPost *model.CollectionPost) (*model.CollectionPost, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.CollectionStore.SavePost(colPost)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("CollectionStore.SavePost", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerCollectionStore) SaveMultiplePosts(colPosts []*model.CollectionPost) ([]*model.CollectionPost, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.CollectionStore.SaveMultiplePosts(colPosts)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("CollectionStore.SaveMultiplePosts", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerCollectionStore) RemovePost(collectionId string, postId string) *model.AppError {
+	start := timemodule.Now()
+
+	err := s.CollectionStore.RemovePost(collectionId, postId)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("CollectionStore.RemovePost", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerCollectionStore) RemovePosts(collectionId string, postIds []string) *model.AppError {
+	start := timemodule.Now()
+
+	err := s.CollectionStore.RemovePosts(collectionId, postIds)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("CollectionStore.RemovePosts", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerCollectionStore) Delete(collectionId string, time int64) *model.AppError {
+	start := timemodule.Now()
+
+	err := s.CollectionStore.Delete(collectionId, time)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("CollectionStore.Delete", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerUserStore) Save(user *model.User) (*model.User, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.UserStore.Save(user)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("UserStore.Save", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerUserStore) Update(user *model.User, trustedUpdateData bool) (*model.UserUpdate, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.UserStore.Update(user, trustedUpdateData)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("UserStore.Update", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerUserStore) Get(id string) (*model.User, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.UserStore.Get(id)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("UserStore.Get", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerUserStore) GetByIds(userIds []string) ([]*model.User, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.UserStore.GetByIds(userIds)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("UserStore.GetByIds", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerUserStore) GetByEmail(email string) (*model.User, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.UserStore.GetByEmail(email)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("UserStore.GetByEmail", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerUserStore) GetByUsername(username string) (*model.User, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.UserStore.GetByUsername(username)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("UserStore.GetByUsername", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerUserStore) GetByAuth(authData *string, authService string) (*model.User, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.UserStore.GetByAuth(authData, authService)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("UserStore.GetByAuth", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerUserStore) RecomputePoints(teamId string, userIds []string) *model.AppError {
+	start := timemodule.Now()
+
+	err := s.UserStore.RecomputePoints(teamId, userIds)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("UserStore.RecomputePoints", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerUserStore) GetUsersByDates(options *model.GetUsersOptions) ([]*model.User, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.UserStore.GetUsersByDates(options)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("UserStore.GetUsersByDates", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerUserStore) GetForLogin(loginId string) (*model.User, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.UserStore.GetForLogin(loginId)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("UserStore.GetForLogin", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerUserStore) GetByInboxInterval(fromUserId string, inboxInterval string, limit int) ([]*model.User, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.UserStore.GetByInboxInterval(fromUserId, inboxInterval, limit)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("UserStore.GetByInboxInterval", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerUserStore) VerifyEmail(userId string, email string) (string, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.UserStore.VerifyEmail(userId, email)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("UserStore.VerifyEmail", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerUserStore) UpdateLastInboxMessageViewed(message *model.InboxMessage, userId string) *model.AppError {
+	start := timemodule.Now()
+
+	err := s.UserStore.UpdateLastInboxMessageViewed(message, userId)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("UserStore.UpdateLastInboxMessageViewed", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerUserStore) SuspendUser(userId string, suspendSpan string, moderatorId string) *model.AppError {
+	start := timemodule.Now()
+
+	err := s.UserStore.SuspendUser(userId, suspendSpan, moderatorId)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("UserStore.SuspendUser", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerUserStore) Delete(userId string, time int64, deleteById string) *model.AppError {
+	start := timemodule.Now()
+
+	err := s.UserStore.Delete(userId, time, deleteById)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("UserStore.Delete", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerUserStore) UpdatePassword(userId string, hashedPassword string) *model.AppError {
+	start := timemodule.Now()
+
+	err := s.UserStore.UpdatePassword(userId, hashedPassword)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("UserStore.UpdatePassword", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerUserStore) UpdateFailedPasswordAttempts(userId string, attempts int) *model.AppError {
+	start := timemodule.Now()
+
+	err := s.UserStore.UpdateFailedPasswordAttempts(userId, attempts)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("UserStore.UpdateFailedPasswordAttempts", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerUserStore) Count(options *model.UserCountOptions) (int64, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.UserStore.Count(options)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("UserStore.Count", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerUserStore) UpdateLastPictureUpdate(userId string, time int64) *model.AppError {
+	start := timemodule.Now()
+
+	err := s.UserStore.UpdateLastPictureUpdate(userId, time)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("UserStore.UpdateLastPictureUpdate", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerUserStore) GetTeamUsersForExport(teamId string, afterId string, limit int) ([]*model.User, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.UserStore.GetTeamUsersForExport(teamId, afterId, limit)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("UserStore.GetTeamUsersForExport", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerUserStore) GetPointsDrifts(after *model.CounterCursor, limit int, importedPropKey string) (*model.CounterDriftBatch, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.UserStore.GetPointsDrifts(after, limit, importedPropKey)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("UserStore.GetPointsDrifts", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerUserStore) FixPointsDrift(drift *model.CounterDrift) (bool, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.UserStore.FixPointsDrift(drift)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("UserStore.FixPointsDrift", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerTokenStore) Save(recovery *model.Token) *model.AppError {
+	start := timemodule.Now()
+
+	err := s.TokenStore.Save(recovery)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("TokenStore.Save", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerTokenStore) GetByToken(token string) (*model.Token, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.TokenStore.GetByToken(token)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("TokenStore.GetByToken", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerTokenStore) Delete(token string) *model.AppError {
+	start := timemodule.Now()
+
+	err := s.TokenStore.Delete(token)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("TokenStore.Delete", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerSessionStore) Get(sessionIdOrToken string) (*model.Session, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.SessionStore.Get(sessionIdOrToken)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("SessionStore.Get", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerSessionStore) Save(session *model.Session) (*model.Session, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.SessionStore.Save(session)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("SessionStore.Save", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerSessionStore) Remove(sessionIdOrToken string) *model.AppError {
+	start := timemodule.Now()
+
+	err := s.SessionStore.Remove(sessionIdOrToken)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("SessionStore.Remove", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerSessionStore) RemoveByUserId(userId string) *model.AppError {
+	start := timemodule.Now()
+
+	err := s.SessionStore.RemoveByUserId(userId)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("SessionStore.RemoveByUserId", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerSessionStore) GetSessions(userId string) ([]*model.Session, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.SessionStore.GetSessions(userId)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("SessionStore.GetSessions", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerSessionStore) UpdateLastActivityAt(sessionId string, time int64) *model.AppError {
+	start := timemodule.Now()
+
+	err := s.SessionStore.UpdateLastActivityAt(sessionId, time)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("SessionStore.UpdateLastActivityAt", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerSessionStore) UpdateExpiresAt(sessionId string, time int64) *model.AppError {
+	start := timemodule.Now()
+
+	err := s.SessionStore.UpdateExpiresAt(sessionId, time)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("SessionStore.UpdateExpiresAt", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerPostStore) SaveQuestion(post *model.Post) (*model.Post, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.PostStore.SaveQuestion(post)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("PostStore.SaveQuestion", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerPostStore) SaveAnswer(post *model.Post) (*model.Post, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.PostStore.SaveAnswer(post)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("PostStore.SaveAnswer", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerPostStore) SaveComment(post *model.Post) (*model.Post, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.PostStore.SaveComment(post)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("PostStore.SaveComment", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerPostStore) ImportPost(post *model.Post) (*model.Post, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.PostStore.ImportPost(post)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("PostStore.ImportPost", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerPostStore) GetImportedPosts(teamId string, propKey string, propPrefix string) ([]*model.Post, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.PostStore.GetImportedPosts(teamId, propKey, propPrefix)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("PostStore.GetImportedPosts", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerPostStore) SetVoteCounts(postId string, upVotes int, downVotes int) *model.AppError {
+	start := timemodule.Now()
+
+	err := s.PostStore.SetVoteCounts(postId, upVotes, downVotes)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("PostStore.SetVoteCounts", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerPostStore) Update(newPost *model.Post, oldPost *model.Post) (*model.Post, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.PostStore.Update(newPost, oldPost)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("PostStore.Update", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerPostStore) GetSingle(id string, includeDeleted bool) (*model.Post, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.PostStore.GetSingle(id, includeDeleted)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("PostStore.GetSingle", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerPostStore) GetSingleByType(id string, postType string) (*model.Post, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.PostStore.GetSingleByType(id, postType)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("PostStore.GetSingleByType", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerPostStore) GetPostCount(postType string, userId string, teamId string, fromDate int64, toDate int64) (int64, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.PostStore.GetPostCount(postType, userId, teamId, fromDate, toDate)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("PostStore.GetPostCount", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerPostStore) GetPostsByIds(postIds []string) (model.Posts, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.PostStore.GetPostsByIds(postIds)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("PostStore.GetPostsByIds", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerPostStore) GetPosts(options *model.GetPostsOptions, getCount bool) (model.Posts, int64, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, result1, err := s.PostStore.GetPosts(options, getCount)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("PostStore.GetPosts", success, elapsed)
+	}
+
+	return result0, result1, err
+}
+
+func (s *TimerLayerPostStore) SearchPosts(paramsList []*model.SearchParams, sortType string, page int, perPage int, teamId string) (model.Posts, int64, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, result1, err := s.PostStore.SearchPosts(paramsList, sortType, page, perPage, teamId)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("PostStore.SearchPosts", success, elapsed)
+	}
+
+	return result0, result1, err
+}
+
+func (s *TimerLayerPostStore) GetChildPostsCount(id string) (int64, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.PostStore.GetChildPostsCount(id)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("PostStore.GetChildPostsCount", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerPostStore) GetCommentsForPost(postId string, limit int) ([]*model.Post, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.PostStore.GetCommentsForPost(postId, limit)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("PostStore.GetCommentsForPost", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerPostStore) DeleteQuestion(postId string, time int64, deleteById string) *model.AppError {
+	start := timemodule.Now()
+
+	err := s.PostStore.DeleteQuestion(postId, time, deleteById)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("PostStore.DeleteQuestion", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerPostStore) DeleteAnswer(postId string, time int64, deleteById string) *model.AppError {
+	start := timemodule.Now()
+
+	err := s.PostStore.DeleteAnswer(postId, time, deleteById)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("PostStore.DeleteAnswer", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerPostStore) DeleteComment(postId string, time int64, deleteById string) *model.AppError {
+	start := timemodule.Now()
+
+	err := s.PostStore.DeleteComment(postId, time, deleteById)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("PostStore.DeleteComment", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerPostStore) RestorePost(postId string, time int64) *model.AppError {
+	start := timemodule.Now()
+
+	err := s.PostStore.RestorePost(postId, time)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("PostStore.RestorePost", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerPostStore) SelectBestAnswer(postId string, bestId string) *model.AppError {
+	start := timemodule.Now()
+
+	err := s.PostStore.SelectBestAnswer(postId, bestId)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("PostStore.SelectBestAnswer", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerPostStore) UpVotePost(postId string, userId string) (*model.Vote, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.PostStore.UpVotePost(postId, userId)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("PostStore.UpVotePost", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerPostStore) CancelUpVotePost(postId string, userId string) (*model.Vote, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.PostStore.CancelUpVotePost(postId, userId)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("PostStore.CancelUpVotePost", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerPostStore) DownVotePost(postId string, userId string) (*model.Vote, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.PostStore.DownVotePost(postId, userId)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("PostStore.DownVotePost", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerPostStore) CancelDownVotePost(postId string, userId string) (*model.Vote, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.PostStore.CancelDownVotePost(postId, userId)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("PostStore.CancelDownVotePost", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerPostStore) FlagPost(postId string, userId string) (*model.Vote, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.PostStore.FlagPost(postId, userId)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("PostStore.FlagPost", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerPostStore) CancelFlagPost(postId string, userId string) (*model.Vote, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.PostStore.CancelFlagPost(postId, userId)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("PostStore.CancelFlagPost", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerPostStore) LockPost(postId string, time int64, userId string) *model.AppError {
+	start := timemodule.Now()
+
+	err := s.PostStore.LockPost(postId, time, userId)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("PostStore.LockPost", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerPostStore) CancelLockPost(postId string, userId string) *model.AppError {
+	start := timemodule.Now()
+
+	err := s.PostStore.CancelLockPost(postId, userId)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("PostStore.CancelLockPost", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerPostStore) ProtectPost(postId string, time int64, userId string) *model.AppError {
+	start := timemodule.Now()
+
+	err := s.PostStore.ProtectPost(postId, time, userId)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("PostStore.ProtectPost", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerPostStore) CancelProtectPost(postId string, userId string) *model.AppError {
+	start := timemodule.Now()
+
+	err := s.PostStore.CancelProtectPost(postId, userId)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("PostStore.CancelProtectPost", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerPostStore) ViewPost(postId string, teamId string, userId string, ipAddress string, count int) *model.AppError {
+	start := timemodule.Now()
+
+	err := s.PostStore.ViewPost(postId, teamId, userId, ipAddress, count)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("PostStore.ViewPost", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerPostStore) FlushBufferedViews(limit int) (int, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.PostStore.FlushBufferedViews(limit)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("PostStore.FlushBufferedViews", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerPostStore) GetDailyUniqueViews(postId string, days int) (model.Analytics, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.PostStore.GetDailyUniqueViews(postId, days)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("PostStore.GetDailyUniqueViews", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerPostStore) SavePostViewsHistory(postId string, teamId string, userId string, ipAddress string, count int, time int64) (*model.PostViewsHistory, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.PostStore.SavePostViewsHistory(postId, teamId, userId, ipAddress, count, time)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("PostStore.SavePostViewsHistory", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerPostStore) RelatedSearch(term string, limit int) ([]*model.RelatedPostSearchResult, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.PostStore.RelatedSearch(term, limit)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("PostStore.RelatedSearch", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerPostStore) HotSearch(interval string, teamId string, limit int) ([]string, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.PostStore.HotSearch(interval, teamId, limit)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("PostStore.HotSearch", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerPostStore) GetCurrentRevisionForPost(postId string, teamId string) (int64, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.PostStore.GetCurrentRevisionForPost(postId, teamId)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("PostStore.GetCurrentRevisionForPost", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerPostStore) GetRevisionPost(postId string, teamId string, offset int) (*model.Post, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.PostStore.GetRevisionPost(postId, teamId, offset)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("PostStore.GetRevisionPost", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerPostStore) GetAnsweredRate(teamId string) (float64, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.PostStore.GetAnsweredRate(teamId)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("PostStore.GetAnsweredRate", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerPostStore) AnalyticsPostCounts(teamId string) (model.Analytics, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.PostStore.AnalyticsPostCounts(teamId)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("PostStore.AnalyticsPostCounts", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerPostStore) AnalyticsActiveAuthorCounts(teamId string) (model.Analytics, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.PostStore.AnalyticsActiveAuthorCounts(teamId)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("PostStore.AnalyticsActiveAuthorCounts", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerPostStore) SaveUserPointHistory(history *model.UserPointHistory) (*model.UserPointHistory, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.PostStore.SaveUserPointHistory(history)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("PostStore.SaveUserPointHistory", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerPostStore) GetPostsForExport(teamId string, postType string, afterId string, limit int) ([]*model.Post, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.PostStore.GetPostsForExport(teamId, postType, afterId, limit)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("PostStore.GetPostsForExport", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerPostStore) GetRevisionsForExport(teamId string, afterId string, limit int) ([]*model.Post, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.PostStore.GetRevisionsForExport(teamId, afterId, limit)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("PostStore.GetRevisionsForExport", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerPostStore) GetCounterDrifts(after *model.CounterCursor, limit int, importedPropKey string) (*model.CounterDriftBatch, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.PostStore.GetCounterDrifts(after, limit, importedPropKey)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("PostStore.GetCounterDrifts", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerPostStore) FixCounterDrift(drift *model.CounterDrift) (bool, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.PostStore.FixCounterDrift(drift)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("PostStore.FixCounterDrift", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerTagStore) GetTags(options *model.GetTagsOptions) (model.Tags, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.TagStore.GetTags(options)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("TagStore.GetTags", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerTagStore) GetTagsCount(options *model.GetTagsOptions) (int64, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.TagStore.GetTagsCount(options)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("TagStore.GetTagsCount", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerTagStore) CreateTags(addedTags []string, time int64, teamId string, tagType string) *model.AppError {
+	start := timemodule.Now()
+
+	err := s.TagStore.CreateTags(addedTags, time, teamId, tagType)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("TagStore.CreateTags", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerTagStore) ImportTag(tag *model.Tag) *model.AppError {
+	start := timemodule.Now()
+
+	err := s.TagStore.ImportTag(tag)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("TagStore.ImportTag", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerTagStore) GetPostTagsAfter(after *model.CounterCursor, limit int) (model.Tags, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.TagStore.GetPostTagsAfter(after, limit)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("TagStore.GetPostTagsAfter", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerTagStore) FixCounterDrift(drift *model.CounterDrift) (bool, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.TagStore.FixCounterDrift(drift)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("TagStore.FixCounterDrift", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerVoteStore) GetVotesBeforeTime(time int64, userId string, page int, perPage int, excludeFlag bool, getCount bool, teamId string, cursor *model.Cursor) ([]*model.Vote, int64, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, result1, err := s.VoteStore.GetVotesBeforeTime(time, userId, page, perPage, excludeFlag, getCount, teamId, cursor)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("VoteStore.GetVotesBeforeTime", success, elapsed)
+	}
+
+	return result0, result1, err
+}
+
+func (s *TimerLayerVoteStore) GetByPostIdForUser(userId string, postId string, voteType string) (*model.Vote, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.VoteStore.GetByPostIdForUser(userId, postId, voteType)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("VoteStore.GetByPostIdForUser", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerVoteStore) GetVoteTypesForPost(userId string, postId string) ([]string, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.VoteStore.GetVoteTypesForPost(userId, postId)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("VoteStore.GetVoteTypesForPost", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerVoteStore) CreateReviewVote(post *model.Post, userId string, tagContents string, revision int64) (*model.Vote, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.VoteStore.CreateReviewVote(post, userId, tagContents, revision)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("VoteStore.CreateReviewVote", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerVoteStore) GetRejectedReviewsCount(postId string, currentRevision int64) (int64, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.VoteStore.GetRejectedReviewsCount(postId, currentRevision)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("VoteStore.GetRejectedReviewsCount", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerVoteStore) RejectReviewsForPost(postId string, rejectedBy string, revision int64) *model.AppError {
+	start := timemodule.Now()
+
+	err := s.VoteStore.RejectReviewsForPost(postId, rejectedBy, revision)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("VoteStore.RejectReviewsForPost", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerVoteStore) CompleteReviewsForPost(postId string, completedBy string, revision int64) *model.AppError {
+	start := timemodule.Now()
+
+	err := s.VoteStore.CompleteReviewsForPost(postId, completedBy, revision)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("VoteStore.CompleteReviewsForPost", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerVoteStore) GetReviews(options *model.SearchReviewsOptions, getCount bool) ([]*model.Vote, int64, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, result1, err := s.VoteStore.GetReviews(options, getCount)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("VoteStore.GetReviews", success, elapsed)
+	}
+
+	return result0, result1, err
+}
+
+func (s *TimerLayerVoteStore) AnalyticsVoteCounts(teamId string, voteType string) (model.Analytics, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.VoteStore.AnalyticsVoteCounts(teamId, voteType)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("VoteStore.AnalyticsVoteCounts", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerVoteStore) GetVotesForExport(teamId string, offset int, limit int) ([]*model.Vote, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.VoteStore.GetVotesForExport(teamId, offset, limit)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("VoteStore.GetVotesForExport", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerUserPointHistoryStore) GetUserPointHistoryBeforeTime(time int64, userId string, page int, perPage int, teamId string) ([]*model.UserPointHistory, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.UserPointHistoryStore.GetUserPointHistoryBeforeTime(time, userId, page, perPage, teamId)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("UserPointHistoryStore.GetUserPointHistoryBeforeTime", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerUserPointHistoryStore) TopAskersByTag(interval string, teamId string, tag string, limit int) ([]*model.TopUserByTagResult, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.UserPointHistoryStore.TopAskersByTag(interval, teamId, tag, limit)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("UserPointHistoryStore.TopAskersByTag", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerUserPointHistoryStore) TopAnswerersByTag(interval string, teamId string, tag string, limit int) ([]*model.TopUserByTagResult, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.UserPointHistoryStore.TopAnswerersByTag(interval, teamId, tag, limit)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("UserPointHistoryStore.TopAnswerersByTag", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerUserPointHistoryStore) TopAnswersByTag(interval string, teamId string, tag string, limit int) ([]*model.TopPostByTagResult, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.UserPointHistoryStore.TopAnswersByTag(interval, teamId, tag, limit)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("UserPointHistoryStore.TopAnswersByTag", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerInboxMessageStore) GetSingle(id string) (*model.InboxMessage, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.InboxMessageStore.GetSingle(id)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("InboxMessageStore.GetSingle", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerInboxMessageStore) GetInboxMessages(time int64, userId string, direction string, page int, perPage int, teamId string, cursor *model.Cursor) ([]*model.InboxMessage, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.InboxMessageStore.GetInboxMessages(time, userId, direction, page, perPage, teamId, cursor)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("InboxMessageStore.GetInboxMessages", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerInboxMessageStore) GetInboxMessagesUnreadCount(userId string, fromDate int64, teamId string) (int64, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.InboxMessageStore.GetInboxMessagesUnreadCount(userId, fromDate, teamId)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("InboxMessageStore.GetInboxMessagesUnreadCount", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerInboxMessageStore) SaveInboxMessage(inboxMessage *model.InboxMessage) (*model.InboxMessage, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.InboxMessageStore.SaveInboxMessage(inboxMessage)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("InboxMessageStore.SaveInboxMessage", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerInboxMessageStore) SaveMultipleInboxMessages(inboxMessages []*model.InboxMessage) ([]*model.InboxMessage, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.InboxMessageStore.SaveMultipleInboxMessages(inboxMessages)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("InboxMessageStore.SaveMultipleInboxMessages", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerUserFavoritePostStore) GetByPostIdForUser(userId string, postId string) (*model.UserFavoritePost, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.UserFavoritePostStore.GetByPostIdForUser(userId, postId)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("UserFavoritePostStore.GetByPostIdForUser", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerUserFavoritePostStore) GetCountByPostId(postId string) (int64, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.UserFavoritePostStore.GetCountByPostId(postId)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("UserFavoritePostStore.GetCountByPostId", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerUserFavoritePostStore) GetUserFavoritePostsBeforeTime(time int64, userId string, page int, perPage int, getCount bool, teamId string, cursor *model.Cursor) ([]*model.UserFavoritePost, int64, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, result1, err := s.UserFavoritePostStore.GetUserFavoritePostsBeforeTime(time, userId, page, perPage, getCount, teamId, cursor)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("UserFavoritePostStore.GetUserFavoritePostsBeforeTime", success, elapsed)
+	}
+
+	return result0, result1, err
+}
+
+func (s *TimerLayerUserFavoritePostStore) Save(postId string, userId string, teamId string) *model.AppError {
+	start := timemodule.Now()
+
+	err := s.UserFavoritePostStore.Save(postId, userId, teamId)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("UserFavoritePostStore.Save", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerUserFavoritePostStore) Delete(postId string, userId string) *model.AppError {
+	start := timemodule.Now()
+
+	err := s.UserFavoritePostStore.Delete(postId, userId)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("UserFavoritePostStore.Delete", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerUserFavoritePostStore) GetForExport(teamId string, offset int, limit int) ([]*model.UserFavoritePost, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.UserFavoritePostStore.GetForExport(teamId, offset, limit)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("UserFavoritePostStore.GetForExport", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerFileInfoStore) Save(info *model.FileInfo) (*model.FileInfo, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.FileInfoStore.Save(info)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("FileInfoStore.Save", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerFileInfoStore) DeleteForPost(postId string) (string, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.FileInfoStore.DeleteForPost(postId)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("FileInfoStore.DeleteForPost", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerFileInfoStore) Get(id string) (*model.FileInfo, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.FileInfoStore.Get(id)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("FileInfoStore.Get", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerFileInfoStore) AttachToPost(fileId string, postId string, userId string) *model.AppError {
+	start := timemodule.Now()
+
+	err := s.FileInfoStore.AttachToPost(fileId, postId, userId)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("FileInfoStore.AttachToPost", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerFileInfoStore) GetForPosts(postIds []string) ([]*model.FileInfo, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.FileInfoStore.GetForPosts(postIds)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("FileInfoStore.GetForPosts", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerNotificationSettingStore) Get(userId string) (*model.NotificationSetting, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.NotificationSettingStore.Get(userId)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("NotificationSettingStore.Get", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerNotificationSettingStore) Save(userId string, inboxInterval string) *model.AppError {
+	start := timemodule.Now()
+
+	err := s.NotificationSettingStore.Save(userId, inboxInterval)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("NotificationSettingStore.Save", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerPostViewsHistoryStore) GetViewsHistoryCount(teamId string, fromDate int64, toDate int64) (int64, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.PostViewsHistoryStore.GetViewsHistoryCount(teamId, fromDate, toDate)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("PostViewsHistoryStore.GetViewsHistoryCount", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerPostViewsHistoryStore) AnalyticsPostViewsHistoryCounts(teamId string) (model.Analytics, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.PostViewsHistoryStore.AnalyticsPostViewsHistoryCounts(teamId)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("PostViewsHistoryStore.AnalyticsPostViewsHistoryCounts", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerWebhookStore) GetByTeam(teamId string, userId string, offset int, limit int) ([]*model.Webhook, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.WebhookStore.GetByTeam(teamId, userId, offset, limit)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("WebhookStore.GetByTeam", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerWebhookStore) Save(webhook *model.Webhook) (*model.Webhook, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.WebhookStore.Save(webhook)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("WebhookStore.Save", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerWebhookStore) Get(id string) (*model.Webhook, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.WebhookStore.Get(id)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("WebhookStore.Get", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerWebhookStore) Update(hook *model.Webhook) (*model.Webhook, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.WebhookStore.Update(hook)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("WebhookStore.Update", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerWebhookStore) Delete(webhookId string, time int64) *model.AppError {
+	start := timemodule.Now()
+
+	err := s.WebhookStore.Delete(webhookId, time)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("WebhookStore.Delete", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerWebhookStore) IncrementFailureCount(webhookId string) (int, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.WebhookStore.IncrementFailureCount(webhookId)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("WebhookStore.IncrementFailureCount", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerWebhookStore) ResetFailureCount(webhookId string) *model.AppError {
+	start := timemodule.Now()
+
+	err := s.WebhookStore.ResetFailureCount(webhookId)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("WebhookStore.ResetFailureCount", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerWebhookStore) Disable(webhookId string, time int64) *model.AppError {
+	start := timemodule.Now()
+
+	err := s.WebhookStore.Disable(webhookId, time)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("WebhookStore.Disable", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerWebhooksHistoryStore) LogWebhookEvent(history *model.WebhooksHistory) error {
+	start := timemodule.Now()
+
+	err := s.WebhooksHistoryStore.LogWebhookEvent(history)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("WebhooksHistoryStore.LogWebhookEvent", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerWebhooksHistoryStore) Get(id string) (*model.WebhooksHistory, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.WebhooksHistoryStore.Get(id)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("WebhooksHistoryStore.Get", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerWebhooksHistoryStore) Search(options *model.SearchWebhooksHistoryOptions) ([]*model.WebhooksHistory, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.WebhooksHistoryStore.Search(options)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("WebhooksHistoryStore.Search", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerWebhooksHistoryStore) PermanentDeleteBefore(time int64, limit int) (int64, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.WebhooksHistoryStore.PermanentDeleteBefore(time, limit)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("WebhooksHistoryStore.PermanentDeleteBefore", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerWebhookDeliveryStore) Save(delivery *model.WebhookDelivery) (*model.WebhookDelivery, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.WebhookDeliveryStore.Save(delivery)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("WebhookDeliveryStore.Save", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerWebhookDeliveryStore) GetDue(now int64, limit int) ([]*model.WebhookDelivery, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.WebhookDeliveryStore.GetDue(now, limit)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("WebhookDeliveryStore.GetDue", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerWebhookDeliveryStore) Claim(delivery *model.WebhookDelivery, lockUntil int64) (bool, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.WebhookDeliveryStore.Claim(delivery, lockUntil)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("WebhookDeliveryStore.Claim", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerWebhookDeliveryStore) Update(delivery *model.WebhookDelivery) (*model.WebhookDelivery, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.WebhookDeliveryStore.Update(delivery)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("WebhookDeliveryStore.Update", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerWebhookDeliveryStore) PermanentDeleteFinishedBefore(time int64, limit int) (int64, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.WebhookDeliveryStore.PermanentDeleteFinishedBefore(time, limit)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("WebhookDeliveryStore.PermanentDeleteFinishedBefore", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerIncomingWebhookStore) Save(hook *model.IncomingWebhook) (*model.IncomingWebhook, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.IncomingWebhookStore.Save(hook)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("IncomingWebhookStore.Save", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerIncomingWebhookStore) Get(id string) (*model.IncomingWebhook, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.IncomingWebhookStore.Get(id)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("IncomingWebhookStore.Get", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerIncomingWebhookStore) GetByToken(token string) (*model.IncomingWebhook, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.IncomingWebhookStore.GetByToken(token)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("IncomingWebhookStore.GetByToken", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerIncomingWebhookStore) GetByTeam(teamId string, offset int, limit int) ([]*model.IncomingWebhook, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.IncomingWebhookStore.GetByTeam(teamId, offset, limit)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("IncomingWebhookStore.GetByTeam", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerIncomingWebhookStore) Update(hook *model.IncomingWebhook) (*model.IncomingWebhook, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.IncomingWebhookStore.Update(hook)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("IncomingWebhookStore.Update", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerIncomingWebhookStore) Delete(hookId string, time int64) *model.AppError {
+	start := timemodule.Now()
+
+	err := s.IncomingWebhookStore.Delete(hookId, time)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("IncomingWebhookStore.Delete", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerIncomingWebhookStore) DeleteByBot(botUserId string, time int64) *model.AppError {
+	start := timemodule.Now()
+
+	err := s.IncomingWebhookStore.DeleteByBot(botUserId, time)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("IncomingWebhookStore.DeleteByBot", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerBotStore) Save(bot *model.Bot) (*model.Bot, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.BotStore.Save(bot)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("BotStore.Save", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerBotStore) Get(userId string) (*model.Bot, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.BotStore.Get(userId)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("BotStore.Get", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerBotStore) GetByTeam(teamId string, offset int, limit int) ([]*model.Bot, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.BotStore.GetByTeam(teamId, offset, limit)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("BotStore.GetByTeam", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerBotStore) Update(bot *model.Bot) (*model.Bot, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.BotStore.Update(bot)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("BotStore.Update", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerAuditStore) Get(user_id string, offset int, limit int) (model.Audits, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.AuditStore.Get(user_id, offset, limit)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("AuditStore.Get", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerAuditStore) Save(audit *model.Audit) *model.AppError {
+	start := timemodule.Now()
+
+	err := s.AuditStore.Save(audit)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("AuditStore.Save", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerAuditStore) Search(options *model.SearchAuditsOptions) (model.Audits, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.AuditStore.Search(options)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("AuditStore.Search", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerAuditStore) PermanentDeleteByUser(userId string) *model.AppError {
+	start := timemodule.Now()
+
+	err := s.AuditStore.PermanentDeleteByUser(userId)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("AuditStore.PermanentDeleteByUser", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerOAuthStore) SaveApp(app *model.OAuthApp) (*model.OAuthApp, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.OAuthStore.SaveApp(app)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("OAuthStore.SaveApp", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerOAuthStore) UpdateApp(app *model.OAuthApp) (*model.OAuthApp, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.OAuthStore.UpdateApp(app)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("OAuthStore.UpdateApp", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerOAuthStore) GetApp(id string) (*model.OAuthApp, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.OAuthStore.GetApp(id)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("OAuthStore.GetApp", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerOAuthStore) GetAppByUserId(userId string, offset int, limit int) ([]*model.OAuthApp, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.OAuthStore.GetAppByUserId(userId, offset, limit)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("OAuthStore.GetAppByUserId", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerOAuthStore) DeleteApp(id string) *model.AppError {
+	start := timemodule.Now()
+
+	err := s.OAuthStore.DeleteApp(id)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("OAuthStore.DeleteApp", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerOAuthStore) GetAuthorizedApps(userId string, offset int, limit int) ([]*model.OAuthApp, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.OAuthStore.GetAuthorizedApps(userId, offset, limit)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("OAuthStore.GetAuthorizedApps", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerOAuthStore) SaveAuthData(authData *model.AuthData) (*model.AuthData, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.OAuthStore.SaveAuthData(authData)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("OAuthStore.SaveAuthData", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerOAuthStore) SaveAuthorizedApp(app *model.OAuthAuthorizedApp) *model.AppError {
+	start := timemodule.Now()
+
+	err := s.OAuthStore.SaveAuthorizedApp(app)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("OAuthStore.SaveAuthorizedApp", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerOAuthStore) GetAccessData(token string) (*model.AccessData, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.OAuthStore.GetAccessData(token)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("OAuthStore.GetAccessData", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerOAuthStore) RemoveAccessData(token string) *model.AppError {
+	start := timemodule.Now()
+
+	err := s.OAuthStore.RemoveAccessData(token)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("OAuthStore.RemoveAccessData", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerOAuthStore) SaveAccessData(accessData *model.AccessData) (*model.AccessData, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.OAuthStore.SaveAccessData(accessData)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("OAuthStore.SaveAccessData", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerOAuthStore) GetAuthData(code string) (*model.AuthData, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.OAuthStore.GetAuthData(code)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("OAuthStore.GetAuthData", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerOAuthStore) RemoveAuthData(code string) *model.AppError {
+	start := timemodule.Now()
+
+	err := s.OAuthStore.RemoveAuthData(code)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("OAuthStore.RemoveAuthData", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerOAuthStore) GetPreviousAccessData(userId string, clientId string) (*model.AccessData, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.OAuthStore.GetPreviousAccessData(userId, clientId)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("OAuthStore.GetPreviousAccessData", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerOAuthStore) UpdateAccessData(accessData *model.AccessData) (*model.AccessData, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.OAuthStore.UpdateAccessData(accessData)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("OAuthStore.UpdateAccessData", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerOAuthStore) GetAccessDataByRefreshToken(token string) (*model.AccessData, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.OAuthStore.GetAccessDataByRefreshToken(token)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("OAuthStore.GetAccessDataByRefreshToken", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerOAuthStore) GetAccessDataByUserForApp(userId string, clientId string) ([]*model.AccessData, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.OAuthStore.GetAccessDataByUserForApp(userId, clientId)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("OAuthStore.GetAccessDataByUserForApp", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerOAuthStore) DeleteAuthorizedApp(userId string, clientId string) *model.AppError {
+	start := timemodule.Now()
+
+	err := s.OAuthStore.DeleteAuthorizedApp(userId, clientId)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("OAuthStore.DeleteAuthorizedApp", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerStatusStore) Get(userId string) (*model.Status, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.StatusStore.Get(userId)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("StatusStore.Get", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerStatusStore) GetByIds(userIds []string) ([]*model.Status, error) {
+	start := timemodule.Now()
+
+	result0, err := s.StatusStore.GetByIds(userIds)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("StatusStore.GetByIds", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerStatusStore) SaveOrUpdate(status *model.Status) error {
+	start := timemodule.Now()
+
+	err := s.StatusStore.SaveOrUpdate(status)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("StatusStore.SaveOrUpdate", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerStatusStore) UpdateLastActivityAt(userId string, lastActivityAt int64) error {
+	start := timemodule.Now()
+
+	err := s.StatusStore.UpdateLastActivityAt(userId, lastActivityAt)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("StatusStore.UpdateLastActivityAt", success, elapsed)
+	}
+
+	return err
+}
+
+func (s *TimerLayerJobStore) Save(job *model.Job) (*model.Job, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.JobStore.Save(job)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("JobStore.Save", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerJobStore) Get(id string) (*model.Job, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.JobStore.Get(id)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("JobStore.Get", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerJobStore) Update(job *model.Job) (*model.Job, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.JobStore.Update(job)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("JobStore.Update", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerJobStore) GetActiveCountByTeamAndType(teamId string, jobType string) (int64, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.JobStore.GetActiveCountByTeamAndType(teamId, jobType)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("JobStore.GetActiveCountByTeamAndType", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerSystemStore) GetByName(name string) (*model.System, *model.AppError) {
+	start := timemodule.Now()
+
+	result0, err := s.SystemStore.GetByName(name)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("SystemStore.GetByName", success, elapsed)
+	}
+
+	return result0, err
+}
+
+func (s *TimerLayerSystemStore) SaveOrUpdate(system *model.System) *model.AppError {
+	start := timemodule.Now()
+
+	err := s.SystemStore.SaveOrUpdate(system)
+
+	elapsed := float64(timemodule.Since(start)) / float64(timemodule.Second)
+	if s.Root.Metrics != nil {
+		success := err == nil
+		s.Root.Metrics.ObserveStoreMethodDuration("SystemStore.SaveOrUpdate", success, elapsed)
+	}
+
+	return err
+}
+
+func New(childStore store.Store, metrics metrics.MetricsInterface) *TimerLayer {
+	newStore := TimerLayer{
+		Store:   childStore,
+		Metrics: metrics,
+	}
+
+	newStore.TeamStore = &TimerLayerTeamStore{TeamStore: childStore.Team(), Root: &newStore}
+	newStore.TeamMemberHistoryStore = &TimerLayerTeamMemberHistoryStore{TeamMemberHistoryStore: childStore.TeamMemberHistory(), Root: &newStore}
+	newStore.UserGroupStore = &TimerLayerUserGroupStore{UserGroupStore: childStore.UserGroup(), Root: &newStore}
+	newStore.GroupMemberHistoryStore = &TimerLayerGroupMemberHistoryStore{GroupMemberHistoryStore: childStore.GroupMemberHistory(), Root: &newStore}
+	newStore.CollectionStore = &TimerLayerCollectionStore{CollectionStore: childStore.Collection(), Root: &newStore}
+	newStore.UserStore = &TimerLayerUserStore{UserStore: childStore.User(), Root: &newStore}
+	newStore.TokenStore = &TimerLayerTokenStore{TokenStore: childStore.Token(), Root: &newStore}
+	newStore.SessionStore = &TimerLayerSessionStore{SessionStore: childStore.Session(), Root: &newStore}
+	newStore.PostStore = &TimerLayerPostStore{PostStore: childStore.Post(), Root: &newStore}
+	newStore.TagStore = &TimerLayerTagStore{TagStore: childStore.Tag(), Root: &newStore}
+	newStore.VoteStore = &TimerLayerVoteStore{VoteStore: childStore.Vote(), Root: &newStore}
+	newStore.UserPointHistoryStore = &TimerLayerUserPointHistoryStore{UserPointHistoryStore: childStore.UserPointHistory(), Root: &newStore}
+	newStore.InboxMessageStore = &TimerLayerInboxMessageStore{InboxMessageStore: childStore.InboxMessage(), Root: &newStore}
+	newStore.UserFavoritePostStore = &TimerLayerUserFavoritePostStore{UserFavoritePostStore: childStore.UserFavoritePost(), Root: &newStore}
+	newStore.FileInfoStore = &TimerLayerFileInfoStore{FileInfoStore: childStore.FileInfo(), Root: &newStore}
+	newStore.NotificationSettingStore = &TimerLayerNotificationSettingStore{NotificationSettingStore: childStore.NotificationSetting(), Root: &newStore}
+	newStore.PostViewsHistoryStore = &TimerLayerPostViewsHistoryStore{PostViewsHistoryStore: childStore.PostViewsHistory(), Root: &newStore}
+	newStore.WebhookStore = &TimerLayerWebhookStore{WebhookStore: childStore.Webhook(), Root: &newStore}
+	newStore.WebhooksHistoryStore = &TimerLayerWebhooksHistoryStore{WebhooksHistoryStore: childStore.WebhooksHistory(), Root: &newStore}
+	newStore.WebhookDeliveryStore = &TimerLayerWebhookDeliveryStore{WebhookDeliveryStore: childStore.WebhookDelivery(), Root: &newStore}
+	newStore.IncomingWebhookStore = &TimerLayerIncomingWebhookStore{IncomingWebhookStore: childStore.IncomingWebhook(), Root: &newStore}
+	newStore.BotStore = &TimerLayerBotStore{BotStore: childStore.Bot(), Root: &newStore}
+	newStore.AuditStore = &TimerLayerAuditStore{AuditStore: childStore.Audit(), Root: &newStore}
+	newStore.OAuthStore = &TimerLayerOAuthStore{OAuthStore: childStore.OAuth(), Root: &newStore}
+	newStore.StatusStore = &TimerLayerStatusStore{StatusStore: childStore.Status(), Root: &newStore}
+	newStore.JobStore = &TimerLayerJobStore{JobStore: childStore.Job(), Root: &newStore}
+	newStore.SystemStore = &TimerLayerSystemStore{SystemStore: childStore.System(), Root: &newStore}
+
+	return &newStore
+}
@@ -0,0 +1,3099 @@
+// Code generated by store/layer_generators. DO NOT EDIT.
+
+package tracinglayer
+
+import (
+	"context"
+
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/clear-ness/qa-discussion/services/tracing"
+	"github.com/clear-ness/qa-discussion/store"
+)
+
+type TracingLayer struct {
+	store.Store
+	ctx                      context.Context
+	TeamStore                store.TeamStore
+	TeamMemberHistoryStore   store.TeamMemberHistoryStore
+	UserGroupStore           store.UserGroupStore
+	GroupMemberHistoryStore  store.GroupMemberHistoryStore
+	CollectionStore          store.CollectionStore
+	UserStore                store.UserStore
+	TokenStore               store.TokenStore
+	SessionStore             store.SessionStore
+	PostStore                store.PostStore
+	TagStore                 store.TagStore
+	VoteStore                store.VoteStore
+	UserPointHistoryStore    store.UserPointHistoryStore
+	InboxMessageStore        store.InboxMessageStore
+	UserFavoritePostStore    store.UserFavoritePostStore
+	FileInfoStore            store.FileInfoStore
+	NotificationSettingStore store.NotificationSettingStore
+	PostViewsHistoryStore    store.PostViewsHistoryStore
+	WebhookStore             store.WebhookStore
+	WebhooksHistoryStore     store.WebhooksHistoryStore
+	WebhookDeliveryStore     store.WebhookDeliveryStore
+	IncomingWebhookStore     store.IncomingWebhookStore
+	BotStore                 store.BotStore
+	AuditStore               store.AuditStore
+	OAuthStore               store.OAuthStore
+	StatusStore              store.StatusStore
+	JobStore                 store.JobStore
+	SystemStore              store.SystemStore
+}
+
+func (s *TracingLayer) Team() store.TeamStore {
+	return s.TeamStore
+}
+
+func (s *TracingLayer) TeamMemberHistory() store.TeamMemberHistoryStore {
+	return s.TeamMemberHistoryStore
+}
+
+func (s *TracingLayer) UserGroup() store.UserGroupStore {
+	return s.UserGroupStore
+}
+
+func (s *TracingLayer) GroupMemberHistory() store.GroupMemberHistoryStore {
+	return s.GroupMemberHistoryStore
+}
+
+func (s *TracingLayer) Collection() store.CollectionStore {
+	return s.CollectionStore
+}
+
+func (s *TracingLayer) User() store.UserStore {
+	return s.UserStore
+}
+
+func (s *TracingLayer) Token() store.TokenStore {
+	return s.TokenStore
+}
+
+func (s *TracingLayer) Session() store.SessionStore {
+	return s.SessionStore
+}
+
+func (s *TracingLayer) Post() store.PostStore {
+	return s.PostStore
+}
+
+func (s *TracingLayer) Tag() store.TagStore {
+	return s.TagStore
+}
+
+func (s *TracingLayer) Vote() store.VoteStore {
+	return s.VoteStore
+}
+
+func (s *TracingLayer) UserPointHistory() store.UserPointHistoryStore {
+	return s.UserPointHistoryStore
+}
+
+func (s *TracingLayer) InboxMessage() store.InboxMessageStore {
+	return s.InboxMessageStore
+}
+
+func (s *TracingLayer) UserFavoritePost() store.UserFavoritePostStore {
+	return s.UserFavoritePostStore
+}
+
+func (s *TracingLayer) FileInfo() store.FileInfoStore {
+	return s.FileInfoStore
+}
+
+func (s *TracingLayer) NotificationSetting() store.NotificationSettingStore {
+	return s.NotificationSettingStore
+}
+
+func (s *TracingLayer) PostViewsHistory() store.PostViewsHistoryStore {
+	return s.PostViewsHistoryStore
+}
+
+func (s *TracingLayer) Webhook() store.WebhookStore {
+	return s.WebhookStore
+}
+
+func (s *TracingLayer) WebhooksHistory() store.WebhooksHistoryStore {
+	return s.WebhooksHistoryStore
+}
+
+func (s *TracingLayer) WebhookDelivery() store.WebhookDeliveryStore {
+	return s.WebhookDeliveryStore
+}
+
+func (s *TracingLayer) IncomingWebhook() store.IncomingWebhookStore {
+	return s.IncomingWebhookStore
+}
+
+func (s *TracingLayer) Bot() store.BotStore {
+	return s.BotStore
+}
+
+func (s *TracingLayer) Audit() store.AuditStore {
+	return s.AuditStore
+}
+
+func (s *TracingLayer) OAuth() store.OAuthStore {
+	return s.OAuthStore
+}
+
+func (s *TracingLayer) Status() store.StatusStore {
+	return s.StatusStore
+}
+
+func (s *TracingLayer) Job() store.JobStore {
+	return s.JobStore
+}
+
+func (s *TracingLayer) System() store.SystemStore {
+	return s.SystemStore
+}
+
+func (s *TracingLayer) WithContext(ctx context.Context) store.Store {
+	return New(s.Store.WithContext(ctx), ctx)
+}
+
+type TracingLayerTeamStore struct {
+	store.TeamStore
+	Root *TracingLayer
+}
+
+type TracingLayerTeamMemberHistoryStore struct {
+	store.TeamMemberHistoryStore
+	Root *TracingLayer
+}
+
+type TracingLayerUserGroupStore struct {
+	store.UserGroupStore
+	Root *TracingLayer
+}
+
+type TracingLayerGroupMemberHistoryStore struct {
+	store.GroupMemberHistoryStore
+	Root *TracingLayer
+}
+
+type TracingLayerCollectionStore struct {
+	store.CollectionStore
+	Root *TracingLayer
+}
+
+type TracingLayerUserStore struct {
+	store.UserStore
+	Root *TracingLayer
+}
+
+type TracingLayerTokenStore struct {
+	store.TokenStore
+	Root *TracingLayer
+}
+
+type TracingLayerSessionStore struct {
+	store.SessionStore
+	Root *TracingLayer
+}
+
+type TracingLayerPostStore struct {
+	store.PostStore
+	Root *TracingLayer
+}
+
+type TracingLayerTagStore struct {
+	store.TagStore
+	Root *TracingLayer
+}
+
+type TracingLayerVoteStore struct {
+	store.VoteStore
+	Root *TracingLayer
+}
+
+type TracingLayerUserPointHistoryStore struct {
+	store.UserPointHistoryStore
+	Root *TracingLayer
+}
+
+type TracingLayerInboxMessageStore struct {
+	store.InboxMessageStore
+	Root *TracingLayer
+}
+
+type TracingLayerUserFavoritePostStore struct {
+	store.UserFavoritePostStore
+	Root *TracingLayer
+}
+
+type TracingLayerFileInfoStore struct {
+	store.FileInfoStore
+	Root *TracingLayer
+}
+
+type TracingLayerNotificationSettingStore struct {
+	store.NotificationSettingStore
+	Root *TracingLayer
+}
+
+type TracingLayerPostViewsHistoryStore struct {
+	store.PostViewsHistoryStore
+	Root *TracingLayer
+}
+
+type TracingLayerWebhookStore struct {
+	store.WebhookStore
+	Root *TracingLayer
+}
+
+type TracingLayerWebhooksHistoryStore struct {
+	store.WebhooksHistoryStore
+	Root *TracingLayer
+}
+
+type TracingLayerWebhookDeliveryStore struct {
+	store.WebhookDeliveryStore
+	Root *TracingLayer
+}
+
+type TracingLayerIncomingWebhookStore struct {
+	store.IncomingWebhookStore
+	Root *TracingLayer
+}
+
+type TracingLayerBotStore struct {
+	store.BotStore
+	Root *TracingLayer
+}
+
+type TracingLayerAuditStore struct {
+	store.AuditStore
+	Root *TracingLayer
+}
+
+type TracingLayerOAuthStore struct {
+	store.OAuthStore
+	Root *TracingLayer
+}
+
+type TracingLayerStatusStore struct {
+	store.StatusStore
+	Root *TracingLayer
+}
+
+type TracingLayerJobStore struct {
+	store.JobStore
+	Root *TracingLayer
+}
+
+type TracingLayerSystemStore struct {
+	store.SystemStore
+	Root *TracingLayer
+}
+
+func (s *TracingLayerTeamStore) Get(id string) (*model.Team, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "TeamStore.Get")
+	defer span.End()
+
+	result0, err := s.TeamStore.Get(id)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerTeamStore) GetByInviteId(inviteId string) (*model.Team, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "TeamStore.GetByInviteId")
+	defer span.End()
+
+	result0, err := s.TeamStore.GetByInviteId(inviteId)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerTeamStore) Save(team *model.Team) (*model.Team, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "TeamStore.Save")
+	defer span.End()
+
+	result0, err := s.TeamStore.Save(team)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerTeamStore) GetMember(teamId string, userId string) (*model.TeamMember, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "TeamStore.GetMember")
+	defer span.End()
+
+	result0, err := s.TeamStore.GetMember(teamId, userId)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerTeamStore) GetMembers(teamId string, offset int, limit int, teamMembersGetOptions *model.TeamMembersGetOptions) ([]*model.TeamMember, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "TeamStore.GetMembers")
+	defer span.End()
+
+	result0, err := s.TeamStore.GetMembers(teamId, offset, limit, teamMembersGetOptions)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerTeamStore) GetMembersByIds(teamId string, userIds []string) ([]*model.TeamMember, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "TeamStore.GetMembersByIds")
+	defer span.End()
+
+	result0, err := s.TeamStore.GetMembersByIds(teamId, userIds)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerTeamStore) SaveMember(member *model.TeamMember, maxUsersPerTeam int) (*model.TeamMember, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "TeamStore.SaveMember")
+	defer span.End()
+
+	result0, err := s.TeamStore.SaveMember(member, maxUsersPerTeam)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerTeamStore) SaveMultipleMembers(members []*model.TeamMember, maxUsersPerTeam int) ([]*model.TeamMember, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "TeamStore.SaveMultipleMembers")
+	defer span.End()
+
+	result0, err := s.TeamStore.SaveMultipleMembers(members, maxUsersPerTeam)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerTeamStore) GetActiveMemberCount(teamId string) (int64, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "TeamStore.GetActiveMemberCount")
+	defer span.End()
+
+	result0, err := s.TeamStore.GetActiveMemberCount(teamId)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerTeamStore) UpdateMember(member *model.TeamMember) (*model.TeamMember, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "TeamStore.UpdateMember")
+	defer span.End()
+
+	result0, err := s.TeamStore.UpdateMember(member)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerTeamStore) UpdateMultipleMembers(members []*model.TeamMember) ([]*model.TeamMember, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "TeamStore.UpdateMultipleMembers")
+	defer span.End()
+
+	result0, err := s.TeamStore.UpdateMultipleMembers(members)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerTeamStore) GetTeamsByUserId(userId string) ([]*model.Team, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "TeamStore.GetTeamsByUserId")
+	defer span.End()
+
+	result0, err := s.TeamStore.GetTeamsByUserId(userId)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerTeamStore) GetTeamsForUser(userId string) ([]*model.TeamMember, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "TeamStore.GetTeamsForUser")
+	defer span.End()
+
+	result0, err := s.TeamStore.GetTeamsForUser(userId)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerTeamStore) Update(team *model.Team) (*model.Team, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "TeamStore.Update")
+	defer span.End()
+
+	result0, err := s.TeamStore.Update(team)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerTeamStore) PermanentDelete(teamId string) *model.AppError {
+	span, _ := tracing.StartSpan(s.Root.ctx, "TeamStore.PermanentDelete")
+	defer span.End()
+
+	err := s.TeamStore.PermanentDelete(teamId)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerTeamStore) RemoveAllMembersByTeam(teamId string) *model.AppError {
+	span, _ := tracing.StartSpan(s.Root.ctx, "TeamStore.RemoveAllMembersByTeam")
+	defer span.End()
+
+	err := s.TeamStore.RemoveAllMembersByTeam(teamId)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerTeamStore) UpdateLastTeamIconUpdate(teamId string, curTime int64) *model.AppError {
+	span, _ := tracing.StartSpan(s.Root.ctx, "TeamStore.UpdateLastTeamIconUpdate")
+	defer span.End()
+
+	err := s.TeamStore.UpdateLastTeamIconUpdate(teamId, curTime)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerTeamStore) AutocompletePublic(name string) ([]*model.Team, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "TeamStore.AutocompletePublic")
+	defer span.End()
+
+	result0, err := s.TeamStore.AutocompletePublic(name)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerTeamStore) GetAllWithAllowedDomains() ([]*model.Team, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "TeamStore.GetAllWithAllowedDomains")
+	defer span.End()
+
+	result0, err := s.TeamStore.GetAllWithAllowedDomains()
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerTeamStore) GetMemberPointsDrifts(after *model.CounterCursor, limit int, importedPropKey string) (*model.CounterDriftBatch, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "TeamStore.GetMemberPointsDrifts")
+	defer span.End()
+
+	result0, err := s.TeamStore.GetMemberPointsDrifts(after, limit, importedPropKey)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerTeamStore) FixMemberPointsDrift(drift *model.CounterDrift) (bool, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "TeamStore.FixMemberPointsDrift")
+	defer span.End()
+
+	result0, err := s.TeamStore.FixMemberPointsDrift(drift)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerTeamMemberHistoryStore) LogJoinEvent(userId string, teamId string, joinTime int64) error {
+	span, _ := tracing.StartSpan(s.Root.ctx, "TeamMemberHistoryStore.LogJoinEvent")
+	defer span.End()
+
+	err := s.TeamMemberHistoryStore.LogJoinEvent(userId, teamId, joinTime)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerTeamMemberHistoryStore) LogLeaveEvent(userId string, teamId string, leaveTime int64) error {
+	span, _ := tracing.StartSpan(s.Root.ctx, "TeamMemberHistoryStore.LogLeaveEvent")
+	defer span.End()
+
+	err := s.TeamMemberHistoryStore.LogLeaveEvent(userId, teamId, leaveTime)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerUserGroupStore) GetTeamGroups(teamId string) (*model.UserGroupList, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "UserGroupStore.GetTeamGroups")
+	defer span.End()
+
+	result0, err := s.UserGroupStore.GetTeamGroups(teamId)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerUserGroupStore) Save(group *model.UserGroup, maxGroupsPerTeam int64) (*model.UserGroup, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "UserGroupStore.Save")
+	defer span.End()
+
+	result0, err := s.UserGroupStore.Save(group, maxGroupsPerTeam)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerUserGroupStore) SaveMember(member *model.GroupMember) (*model.GroupMember, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "UserGroupStore.SaveMember")
+	defer span.End()
+
+	result0, err := s.UserGroupStore.SaveMember(member)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerUserGroupStore) SaveMultipleMembers(members []*model.GroupMember) ([]*model.GroupMember, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "UserGroupStore.SaveMultipleMembers")
+	defer span.End()
+
+	result0, err := s.UserGroupStore.SaveMultipleMembers(members)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerUserGroupStore) GetGroupsForTeam(teamId string, groupType string, offset int, limit int) (*model.UserGroupList, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "UserGroupStore.GetGroupsForTeam")
+	defer span.End()
+
+	result0, err := s.UserGroupStore.GetGroupsForTeam(teamId, groupType, offset, limit)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerUserGroupStore) AutocompleteInTeam(teamId string, term string, groupType string, includeDeleted bool) (*model.UserGroupList, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "UserGroupStore.AutocompleteInTeam")
+	defer span.End()
+
+	result0, err := s.UserGroupStore.AutocompleteInTeam(teamId, term, groupType, includeDeleted)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerUserGroupStore) GetGroups(teamId string, userId string, includeDeleted bool) (*model.UserGroupList, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "UserGroupStore.GetGroups")
+	defer span.End()
+
+	result0, err := s.UserGroupStore.GetGroups(teamId, userId, includeDeleted)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerUserGroupStore) Get(id string) (*model.UserGroup, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "UserGroupStore.Get")
+	defer span.End()
+
+	result0, err := s.UserGroupStore.Get(id)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerUserGroupStore) GetAllGroupMembersForUser(userId string) (map[string]string, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "UserGroupStore.GetAllGroupMembersForUser")
+	defer span.End()
+
+	result0, err := s.UserGroupStore.GetAllGroupMembersForUser(userId)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerUserGroupStore) Update(group *model.UserGroup) (*model.UserGroup, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "UserGroupStore.Update")
+	defer span.End()
+
+	result0, err := s.UserGroupStore.Update(group)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerUserGroupStore) Delete(groupId string, time int64) *model.AppError {
+	span, _ := tracing.StartSpan(s.Root.ctx, "UserGroupStore.Delete")
+	defer span.End()
+
+	err := s.UserGroupStore.Delete(groupId, time)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerUserGroupStore) GetMembers(groupId string, memberType string, offset int, limit int, cursor *model.Cursor) (*model.GroupMembers, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "UserGroupStore.GetMembers")
+	defer span.End()
+
+	result0, err := s.UserGroupStore.GetMembers(groupId, memberType, offset, limit, cursor)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerUserGroupStore) GetMember(groupId string, userId string) (*model.GroupMember, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "UserGroupStore.GetMember")
+	defer span.End()
+
+	result0, err := s.UserGroupStore.GetMember(groupId, userId)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerUserGroupStore) UpdateMember(member *model.GroupMember) (*model.GroupMember, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "UserGroupStore.UpdateMember")
+	defer span.End()
+
+	result0, err := s.UserGroupStore.UpdateMember(member)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerUserGroupStore) UpdateMultipleMembers(members []*model.GroupMember) ([]*model.GroupMember, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "UserGroupStore.UpdateMultipleMembers")
+	defer span.End()
+
+	result0, err := s.UserGroupStore.UpdateMultipleMembers(members)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerUserGroupStore) RemoveMembers(groupId string, userIds []string) *model.AppError {
+	span, _ := tracing.StartSpan(s.Root.ctx, "UserGroupStore.RemoveMembers")
+	defer span.End()
+
+	err := s.UserGroupStore.RemoveMembers(groupId, userIds)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerUserGroupStore) RemoveMember(groupId string, userId string) *model.AppError {
+	span, _ := tracing.StartSpan(s.Root.ctx, "UserGroupStore.RemoveMember")
+	defer span.End()
+
+	err := s.UserGroupStore.RemoveMember(groupId, userId)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerGroupMemberHistoryStore) LogJoinEvent(userId string, groupId string, joinTime int64) error {
+	span, _ := tracing.StartSpan(s.Root.ctx, "GroupMemberHistoryStore.LogJoinEvent")
+	defer span.End()
+
+	err := s.GroupMemberHistoryStore.LogJoinEvent(userId, groupId, joinTime)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerGroupMemberHistoryStore) LogLeaveEvent(userId string, groupId string, leaveTime int64) error {
+	span, _ := tracing.StartSpan(s.Root.ctx, "GroupMemberHistoryStore.LogLeaveEvent")
+	defer span.End()
+
+	err := s.GroupMemberHistoryStore.LogLeaveEvent(userId, groupId, leaveTime)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerCollectionStore) Get(id string) (*model.Collection, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "CollectionStore.Get")
+	defer span.End()
+
+	result0, err := s.CollectionStore.Get(id)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerCollectionStore) GetPost(collectionId string, postId string) (*model.CollectionPost, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "CollectionStore.GetPost")
+	defer span.End()
+
+	result0, err := s.CollectionStore.GetPost(collectionId, postId)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerCollectionStore) GetPosts(collectionId string, offset int, limit int) (*model.CollectionPosts, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "CollectionStore.GetPosts")
+	defer span.End()
+
+	result0, err := s.CollectionStore.GetPosts(collectionId, offset, limit)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerCollectionStore) GetCollectionsForTeam(teamId string, offset int, limit int, title string, cursor *model.Cursor) (*model.CollectionList, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "CollectionStore.GetCollectionsForTeam")
+	defer span.End()
+
+	result0, err := s.CollectionStore.GetCollectionsForTeam(teamId, offset, limit, title, cursor)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerCollectionStore) GetTeamCollections(teamId string) (*model.CollectionList, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "CollectionStore.GetTeamCollections")
+	defer span.End()
+
+	result0, err := s.CollectionStore.GetTeamCollections(teamId)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerCollectionStore) Save(collection *model.Collection, maxCollectionsPerTeam int64) (*model.Collection, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "CollectionStore.Save")
+	defer span.End()
+
+	result0, err := s.CollectionStore.Save(collection, maxCollectionsPerTeam)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerCollectionStore) SavePost(colPost *model.CollectionPost) (*model.CollectionPost, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "CollectionStore.SavePost")
+	defer span.End()
+
+	result0, err := s.CollectionStore.SavePost(colPost)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerCollectionStore) SaveMultiplePosts(colPosts []*model.CollectionPost) ([]*model.CollectionPost, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "CollectionStore.SaveMultiplePosts")
+	defer span.End()
+
+	result0, err := s.CollectionStore.SaveMultiplePosts(colPosts)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerCollectionStore) RemovePost(collectionId string, postId string) *model.AppError {
+	span, _ := tracing.StartSpan(s.Root.ctx, "CollectionStore.RemovePost")
+	defer span.End()
+
+	err := s.CollectionStore.RemovePost(collectionId, postId)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerCollectionStore) RemovePosts(collectionId string, postIds []string) *model.AppError {
+	span, _ := tracing.StartSpan(s.Root.ctx, "CollectionStore.RemovePosts")
+	defer span.End()
+
+	err := s.CollectionStore.RemovePosts(collectionId, postIds)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerCollectionStore) Delete(collectionId string, time int64) *model.AppError {
+	span, _ := tracing.StartSpan(s.Root.ctx, "CollectionStore.Delete")
+	defer span.End()
+
+	err := s.CollectionStore.Delete(collectionId, time)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerUserStore) Save(user *model.User) (*model.User, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "UserStore.Save")
+	defer span.End()
+
+	result0, err := s.UserStore.Save(user)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerUserStore) Update(user *model.User, trustedUpdateData bool) (*model.UserUpdate, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "UserStore.Update")
+	defer span.End()
+
+	result0, err := s.UserStore.Update(user, trustedUpdateData)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerUserStore) Get(id string) (*model.User, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "UserStore.Get")
+	defer span.End()
+
+	result0, err := s.UserStore.Get(id)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerUserStore) GetByIds(userIds []string) ([]*model.User, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "UserStore.GetByIds")
+	defer span.End()
+
+	result0, err := s.UserStore.GetByIds(userIds)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerUserStore) GetByEmail(email string) (*model.User, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "UserStore.GetByEmail")
+	defer span.End()
+
+	result0, err := s.UserStore.GetByEmail(email)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerUserStore) GetByUsername(username string) (*model.User, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "UserStore.GetByUsername")
+	defer span.End()
+
+	result0, err := s.UserStore.GetByUsername(username)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerUserStore) GetByAuth(authData *string, authService string) (*model.User, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "UserStore.GetByAuth")
+	defer span.End()
+
+	result0, err := s.UserStore.GetByAuth(authData, authService)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerUserStore) RecomputePoints(teamId string, userIds []string) *model.AppError {
+	span, _ := tracing.StartSpan(s.Root.ctx, "UserStore.RecomputePoints")
+	defer span.End()
+
+	err := s.UserStore.RecomputePoints(teamId, userIds)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerUserStore) GetUsersByDates(options *model.GetUsersOptions) ([]*model.User, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "UserStore.GetUsersByDates")
+	defer span.End()
+
+	result0, err := s.UserStore.GetUsersByDates(options)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerUserStore) GetForLogin(loginId string) (*model.User, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "UserStore.GetForLogin")
+	defer span.End()
+
+	result0, err := s.UserStore.GetForLogin(loginId)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerUserStore) GetByInboxInterval(fromUserId string, inboxInterval string, limit int) ([]*model.User, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "UserStore.GetByInboxInterval")
+	defer span.End()
+
+	result0, err := s.UserStore.GetByInboxInterval(fromUserId, inboxInterval, limit)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerUserStore) VerifyEmail(userId string, email string) (string, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "UserStore.VerifyEmail")
+	defer span.End()
+
+	result0, err := s.UserStore.VerifyEmail(userId, email)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerUserStore) UpdateLastInboxMessageViewed(message *model.InboxMessage, userId string) *model.AppError {
+	span, _ := tracing.StartSpan(s.Root.ctx, "UserStore.UpdateLastInboxMessageViewed")
+	defer span.End()
+
+	err := s.UserStore.UpdateLastInboxMessageViewed(message, userId)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerUserStore) SuspendUser(userId string, suspendSpan string, moderatorId string) *model.AppError {
+	span, _ := tracing.StartSpan(s.Root.ctx, "UserStore.SuspendUser")
+	defer span.End()
+
+	err := s.UserStore.SuspendUser(userId, suspendSpan, moderatorId)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerUserStore) Delete(userId string, time int64, deleteById string) *model.AppError {
+	span, _ := tracing.StartSpan(s.Root.ctx, "UserStore.Delete")
+	defer span.End()
+
+	err := s.UserStore.Delete(userId, time, deleteById)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerUserStore) UpdatePassword(userId string, hashedPassword string) *model.AppError {
+	span, _ := tracing.StartSpan(s.Root.ctx, "UserStore.UpdatePassword")
+	defer span.End()
+
+	err := s.UserStore.UpdatePassword(userId, hashedPassword)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerUserStore) UpdateFailedPasswordAttempts(userId string, attempts int) *model.AppError {
+	span, _ := tracing.StartSpan(s.Root.ctx, "UserStore.UpdateFailedPasswordAttempts")
+	defer span.End()
+
+	err := s.UserStore.UpdateFailedPasswordAttempts(userId, attempts)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerUserStore) Count(options *model.UserCountOptions) (int64, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "UserStore.Count")
+	defer span.End()
+
+	result0, err := s.UserStore.Count(options)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerUserStore) UpdateLastPictureUpdate(userId string, time int64) *model.AppError {
+	span, _ := tracing.StartSpan(s.Root.ctx, "UserStore.UpdateLastPictureUpdate")
+	defer span.End()
+
+	err := s.UserStore.UpdateLastPictureUpdate(userId, time)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerUserStore) GetTeamUsersForExport(teamId string, afterId string, limit int) ([]*model.User, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "UserStore.GetTeamUsersForExport")
+	defer span.End()
+
+	result0, err := s.UserStore.GetTeamUsersForExport(teamId, afterId, limit)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerUserStore) GetPointsDrifts(after *model.CounterCursor, limit int, importedPropKey string) (*model.CounterDriftBatch, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "UserStore.GetPointsDrifts")
+	defer span.End()
+
+	result0, err := s.UserStore.GetPointsDrifts(after, limit, importedPropKey)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerUserStore) FixPointsDrift(drift *model.CounterDrift) (bool, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "UserStore.FixPointsDrift")
+	defer span.End()
+
+	result0, err := s.UserStore.FixPointsDrift(drift)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerTokenStore) Save(recovery *model.Token) *model.AppError {
+	span, _ := tracing.StartSpan(s.Root.ctx, "TokenStore.Save")
+	defer span.End()
+
+	err := s.TokenStore.Save(recovery)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerTokenStore) GetByToken(token string) (*model.Token, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "TokenStore.GetByToken")
+	defer span.End()
+
+	result0, err := s.TokenStore.GetByToken(token)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerTokenStore) Delete(token string) *model.AppError {
+	span, _ := tracing.StartSpan(s.Root.ctx, "TokenStore.Delete")
+	defer span.End()
+
+	err := s.TokenStore.Delete(token)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerSessionStore) Get(sessionIdOrToken string) (*model.Session, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "SessionStore.Get")
+	defer span.End()
+
+	result0, err := s.SessionStore.Get(sessionIdOrToken)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerSessionStore) Save(session *model.Session) (*model.Session, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "SessionStore.Save")
+	defer span.End()
+
+	result0, err := s.SessionStore.Save(session)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerSessionStore) Remove(sessionIdOrToken string) *model.AppError {
+	span, _ := tracing.StartSpan(s.Root.ctx, "SessionStore.Remove")
+	defer span.End()
+
+	err := s.SessionStore.Remove(sessionIdOrToken)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerSessionStore) RemoveByUserId(userId string) *model.AppError {
+	span, _ := tracing.StartSpan(s.Root.ctx, "SessionStore.RemoveByUserId")
+	defer span.End()
+
+	err := s.SessionStore.RemoveByUserId(userId)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerSessionStore) GetSessions(userId string) ([]*model.Session, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "SessionStore.GetSessions")
+	defer span.End()
+
+	result0, err := s.SessionStore.GetSessions(userId)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerSessionStore) UpdateLastActivityAt(sessionId string, time int64) *model.AppError {
+	span, _ := tracing.StartSpan(s.Root.ctx, "SessionStore.UpdateLastActivityAt")
+	defer span.End()
+
+	err := s.SessionStore.UpdateLastActivityAt(sessionId, time)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerSessionStore) UpdateExpiresAt(sessionId string, time int64) *model.AppError {
+	span, _ := tracing.StartSpan(s.Root.ctx, "SessionStore.UpdateExpiresAt")
+	defer span.End()
+
+	err := s.SessionStore.UpdateExpiresAt(sessionId, time)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerPostStore) SaveQuestion(post *model.Post) (*model.Post, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "PostStore.SaveQuestion")
+	defer span.End()
+
+	result0, err := s.PostStore.SaveQuestion(post)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerPostStore) SaveAnswer(post *model.Post) (*model.Post, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "PostStore.SaveAnswer")
+	defer span.End()
+
+	result0, err := s.PostStore.SaveAnswer(post)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerPostStore) SaveComment(post *model.Post) (*model.Post, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "PostStore.SaveComment")
+	defer span.End()
+
+	result0, err := s.PostStore.SaveComment(post)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerPostStore) ImportPost(post *model.Post) (*model.Post, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "PostStore.ImportPost")
+	defer span.End()
+
+	result0, err := s.PostStore.ImportPost(post)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerPostStore) GetImportedPosts(teamId string, propKey string, propPrefix string) ([]*model.Post, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "PostStore.GetImportedPosts")
+	defer span.End()
+
+	result0, err := s.PostStore.GetImportedPosts(teamId, propKey, propPrefix)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerPostStore) SetVoteCounts(postId string, upVotes int, downVotes int) *model.AppError {
+	span, _ := tracing.StartSpan(s.Root.ctx, "PostStore.SetVoteCounts")
+	defer span.End()
+
+	err := s.PostStore.SetVoteCounts(postId, upVotes, downVotes)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerPostStore) Update(newPost *model.Post, oldPost *model.Post) (*model.Post, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "PostStore.Update")
+	defer span.End()
+
+	result0, err := s.PostStore.Update(newPost, oldPost)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerPostStore) GetSingle(id string, includeDeleted bool) (*model.Post, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "PostStore.GetSingle")
+	defer span.End()
+
+	result0, err := s.PostStore.GetSingle(id, includeDeleted)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerPostStore) GetSingleByType(id string, postType string) (*model.Post, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "PostStore.GetSingleByType")
+	defer span.End()
+
+	result0, err := s.PostStore.GetSingleByType(id, postType)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerPostStore) GetPostCount(postType string, userId string, teamId string, fromDate int64, toDate int64) (int64, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "PostStore.GetPostCount")
+	defer span.End()
+
+	result0, err := s.PostStore.GetPostCount(postType, userId, teamId, fromDate, toDate)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerPostStore) GetPostsByIds(postIds []string) (model.Posts, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "PostStore.GetPostsByIds")
+	defer span.End()
+
+	result0, err := s.PostStore.GetPostsByIds(postIds)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerPostStore) GetPosts(options *model.GetPostsOptions, getCount bool) (model.Posts, int64, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "PostStore.GetPosts")
+	defer span.End()
+
+	result0, result1, err := s.PostStore.GetPosts(options, getCount)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, result1, err
+}
+
+func (s *TracingLayerPostStore) SearchPosts(paramsList []*model.SearchParams, sortType string, page int, perPage int, teamId string) (model.Posts, int64, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "PostStore.SearchPosts")
+	defer span.End()
+
+	result0, result1, err := s.PostStore.SearchPosts(paramsList, sortType, page, perPage, teamId)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, result1, err
+}
+
+func (s *TracingLayerPostStore) GetChildPostsCount(id string) (int64, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "PostStore.GetChildPostsCount")
+	defer span.End()
+
+	result0, err := s.PostStore.GetChildPostsCount(id)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerPostStore) GetCommentsForPost(postId string, limit int) ([]*model.Post, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "PostStore.GetCommentsForPost")
+	defer span.End()
+
+	result0, err := s.PostStore.GetCommentsForPost(postId, limit)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerPostStore) DeleteQuestion(postId string, time int64, deleteById string) *model.AppError {
+	span, _ := tracing.StartSpan(s.Root.ctx, "PostStore.DeleteQuestion")
+	defer span.End()
+
+	err := s.PostStore.DeleteQuestion(postId, time, deleteById)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerPostStore) DeleteAnswer(postId string, time int64, deleteById string) *model.AppError {
+	span, _ := tracing.StartSpan(s.Root.ctx, "PostStore.DeleteAnswer")
+	defer span.End()
+
+	err := s.PostStore.DeleteAnswer(postId, time, deleteById)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerPostStore) DeleteComment(postId string, time int64, deleteById string) *model.AppError {
+	span, _ := tracing.StartSpan(s.Root.ctx, "PostStore.DeleteComment")
+	defer span.End()
+
+	err := s.PostStore.DeleteComment(postId, time, deleteById)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerPostStore) RestorePost(postId string, time int64) *model.AppError {
+	span, _ := tracing.StartSpan(s.Root.ctx, "PostStore.RestorePost")
+	defer span.End()
+
+	err := s.PostStore.RestorePost(postId, time)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerPostStore) SelectBestAnswer(postId string, bestId string) *model.AppError {
+	span, _ := tracing.StartSpan(s.Root.ctx, "PostStore.SelectBestAnswer")
+	defer span.End()
+
+	err := s.PostStore.SelectBestAnswer(postId, bestId)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerPostStore) UpVotePost(postId string, userId string) (*model.Vote, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "PostStore.UpVotePost")
+	defer span.End()
+
+	result0, err := s.PostStore.UpVotePost(postId, userId)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerPostStore) CancelUpVotePost(postId string, userId string) (*model.Vote, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "PostStore.CancelUpVotePost")
+	defer span.End()
+
+	result0, err := s.PostStore.CancelUpVotePost(postId, userId)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerPostStore) DownVotePost(postId string, userId string) (*model.Vote, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "PostStore.DownVotePost")
+	defer span.End()
+
+	result0, err := s.PostStore.DownVotePost(postId, userId)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerPostStore) CancelDownVotePost(postId string, userId string) (*model.Vote, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "PostStore.CancelDownVotePost")
+	defer span.End()
+
+	result0, err := s.PostStore.CancelDownVotePost(postId, userId)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerPostStore) FlagPost(postId string, userId string) (*model.Vote, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "PostStore.FlagPost")
+	defer span.End()
+
+	result0, err := s.PostStore.FlagPost(postId, userId)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerPostStore) CancelFlagPost(postId string, userId string) (*model.Vote, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "PostStore.CancelFlagPost")
+	defer span.End()
+
+	result0, err := s.PostStore.CancelFlagPost(postId, userId)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerPostStore) LockPost(postId string, time int64, userId string) *model.AppError {
+	span, _ := tracing.StartSpan(s.Root.ctx, "PostStore.LockPost")
+	defer span.End()
+
+	err := s.PostStore.LockPost(postId, time, userId)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerPostStore) CancelLockPost(postId string, userId string) *model.AppError {
+	span, _ := tracing.StartSpan(s.Root.ctx, "PostStore.CancelLockPost")
+	defer span.End()
+
+	err := s.PostStore.CancelLockPost(postId, userId)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerPostStore) ProtectPost(postId string, time int64, userId string) *model.AppError {
+	span, _ := tracing.StartSpan(s.Root.ctx, "PostStore.ProtectPost")
+	defer span.End()
+
+	err := s.PostStore.ProtectPost(postId, time, userId)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerPostStore) CancelProtectPost(postId string, userId string) *model.AppError {
+	span, _ := tracing.StartSpan(s.Root.ctx, "PostStore.CancelProtectPost")
+	defer span.End()
+
+	err := s.PostStore.CancelProtectPost(postId, userId)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerPostStore) ViewPost(postId string, teamId string, userId string, ipAddress string, count int) *model.AppError {
+	span, _ := tracing.StartSpan(s.Root.ctx, "PostStore.ViewPost")
+	defer span.End()
+
+	err := s.PostStore.ViewPost(postId, teamId, userId, ipAddress, count)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerPostStore) FlushBufferedViews(limit int) (int, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "PostStore.FlushBufferedViews")
+	defer span.End()
+
+	result0, err := s.PostStore.FlushBufferedViews(limit)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerPostStore) GetDailyUniqueViews(postId string, days int) (model.Analytics, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "PostStore.GetDailyUniqueViews")
+	defer span.End()
+
+	result0, err := s.PostStore.GetDailyUniqueViews(postId, days)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerPostStore) SavePostViewsHistory(postId string, teamId string, userId string, ipAddress string, count int, time int64) (*model.PostViewsHistory, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "PostStore.SavePostViewsHistory")
+	defer span.End()
+
+	result0, err := s.PostStore.SavePostViewsHistory(postId, teamId, userId, ipAddress, count, time)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerPostStore) RelatedSearch(term string, limit int) ([]*model.RelatedPostSearchResult, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "PostStore.RelatedSearch")
+	defer span.End()
+
+	result0, err := s.PostStore.RelatedSearch(term, limit)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerPostStore) HotSearch(interval string, teamId string, limit int) ([]string, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "PostStore.HotSearch")
+	defer span.End()
+
+	result0, err := s.PostStore.HotSearch(interval, teamId, limit)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerPostStore) GetCurrentRevisionForPost(postId string, teamId string) (int64, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "PostStore.GetCurrentRevisionForPost")
+	defer span.End()
+
+	result0, err := s.PostStore.GetCurrentRevisionForPost(postId, teamId)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerPostStore) GetRevisionPost(postId string, teamId string, offset int) (*model.Post, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "PostStore.GetRevisionPost")
+	defer span.End()
+
+	result0, err := s.PostStore.GetRevisionPost(postId, teamId, offset)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerPostStore) GetAnsweredRate(teamId string) (float64, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "PostStore.GetAnsweredRate")
+	defer span.End()
+
+	result0, err := s.PostStore.GetAnsweredRate(teamId)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerPostStore) AnalyticsPostCounts(teamId string) (model.Analytics, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "PostStore.AnalyticsPostCounts")
+	defer span.End()
+
+	result0, err := s.PostStore.AnalyticsPostCounts(teamId)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerPostStore) AnalyticsActiveAuthorCounts(teamId string) (model.Analytics, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "PostStore.AnalyticsActiveAuthorCounts")
+	defer span.End()
+
+	result0, err := s.PostStore.AnalyticsActiveAuthorCounts(teamId)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerPostStore) SaveUserPointHistory(history *model.UserPointHistory) (*model.UserPointHistory, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "PostStore.SaveUserPointHistory")
+	defer span.End()
+
+	result0, err := s.PostStore.SaveUserPointHistory(history)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerPostStore) GetPostsForExport(teamId string, postType string, afterId string, limit int) ([]*model.Post, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "PostStore.GetPostsForExport")
+	defer span.End()
+
+	result0, err := s.PostStore.GetPostsForExport(teamId, postType, afterId, limit)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerPostStore) GetRevisionsForExport(teamId string, afterId string, limit int) ([]*model.Post, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "PostStore.GetRevisionsForExport")
+	defer span.End()
+
+	result0, err := s.PostStore.GetRevisionsForExport(teamId, afterId, limit)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerPostStore) GetCounterDrifts(after *model.CounterCursor, limit int, importedPropKey string) (*model.CounterDriftBatch, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "PostStore.GetCounterDrifts")
+	defer span.End()
+
+	result0, err := s.PostStore.GetCounterDrifts(after, limit, importedPropKey)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerPostStore) FixCounterDrift(drift *model.CounterDrift) (bool, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "PostStore.FixCounterDrift")
+	defer span.End()
+
+	result0, err := s.PostStore.FixCounterDrift(drift)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerTagStore) GetTags(options *model.GetTagsOptions) (model.Tags, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "TagStore.GetTags")
+	defer span.End()
+
+	result0, err := s.TagStore.GetTags(options)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerTagStore) GetTagsCount(options *model.GetTagsOptions) (int64, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "TagStore.GetTagsCount")
+	defer span.End()
+
+	result0, err := s.TagStore.GetTagsCount(options)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerTagStore) CreateTags(addedTags []string, time int64, teamId string, tagType string) *model.AppError {
+	span, _ := tracing.StartSpan(s.Root.ctx, "TagStore.CreateTags")
+	defer span.End()
+
+	err := s.TagStore.CreateTags(addedTags, time, teamId, tagType)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerTagStore) ImportTag(tag *model.Tag) *model.AppError {
+	span, _ := tracing.StartSpan(s.Root.ctx, "TagStore.ImportTag")
+	defer span.End()
+
+	err := s.TagStore.ImportTag(tag)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerTagStore) GetPostTagsAfter(after *model.CounterCursor, limit int) (model.Tags, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "TagStore.GetPostTagsAfter")
+	defer span.End()
+
+	result0, err := s.TagStore.GetPostTagsAfter(after, limit)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerTagStore) FixCounterDrift(drift *model.CounterDrift) (bool, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "TagStore.FixCounterDrift")
+	defer span.End()
+
+	result0, err := s.TagStore.FixCounterDrift(drift)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerVoteStore) GetVotesBeforeTime(time int64, userId string, page int, perPage int, excludeFlag bool, getCount bool, teamId string, cursor *model.Cursor) ([]*model.Vote, int64, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "VoteStore.GetVotesBeforeTime")
+	defer span.End()
+
+	result0, result1, err := s.VoteStore.GetVotesBeforeTime(time, userId, page, perPage, excludeFlag, getCount, teamId, cursor)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, result1, err
+}
+
+func (s *TracingLayerVoteStore) GetByPostIdForUser(userId string, postId string, voteType string) (*model.Vote, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "VoteStore.GetByPostIdForUser")
+	defer span.End()
+
+	result0, err := s.VoteStore.GetByPostIdForUser(userId, postId, voteType)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerVoteStore) GetVoteTypesForPost(userId string, postId string) ([]string, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "VoteStore.GetVoteTypesForPost")
+	defer span.End()
+
+	result0, err := s.VoteStore.GetVoteTypesForPost(userId, postId)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerVoteStore) CreateReviewVote(post *model.Post, userId string, tagContents string, revision int64) (*model.Vote, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "VoteStore.CreateReviewVote")
+	defer span.End()
+
+	result0, err := s.VoteStore.CreateReviewVote(post, userId, tagContents, revision)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerVoteStore) GetRejectedReviewsCount(postId string, currentRevision int64) (int64, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "VoteStore.GetRejectedReviewsCount")
+	defer span.End()
+
+	result0, err := s.VoteStore.GetRejectedReviewsCount(postId, currentRevision)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerVoteStore) RejectReviewsForPost(postId string, rejectedBy string, revision int64) *model.AppError {
+	span, _ := tracing.StartSpan(s.Root.ctx, "VoteStore.RejectReviewsForPost")
+	defer span.End()
+
+	err := s.VoteStore.RejectReviewsForPost(postId, rejectedBy, revision)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerVoteStore) CompleteReviewsForPost(postId string, completedBy string, revision int64) *model.AppError {
+	span, _ := tracing.StartSpan(s.Root.ctx, "VoteStore.CompleteReviewsForPost")
+	defer span.End()
+
+	err := s.VoteStore.CompleteReviewsForPost(postId, completedBy, revision)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerVoteStore) GetReviews(options *model.SearchReviewsOptions, getCount bool) ([]*model.Vote, int64, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "VoteStore.GetReviews")
+	defer span.End()
+
+	result0, result1, err := s.VoteStore.GetReviews(options, getCount)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, result1, err
+}
+
+func (s *TracingLayerVoteStore) AnalyticsVoteCounts(teamId string, voteType string) (model.Analytics, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "VoteStore.AnalyticsVoteCounts")
+	defer span.End()
+
+	result0, err := s.VoteStore.AnalyticsVoteCounts(teamId, voteType)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerVoteStore) GetVotesForExport(teamId string, offset int, limit int) ([]*model.Vote, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "VoteStore.GetVotesForExport")
+	defer span.End()
+
+	result0, err := s.VoteStore.GetVotesForExport(teamId, offset, limit)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerUserPointHistoryStore) GetUserPointHistoryBeforeTime(time int64, userId string, page int, perPage int, teamId string) ([]*model.UserPointHistory, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "UserPointHistoryStore.GetUserPointHistoryBeforeTime")
+	defer span.End()
+
+	result0, err := s.UserPointHistoryStore.GetUserPointHistoryBeforeTime(time, userId, page, perPage, teamId)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerUserPointHistoryStore) TopAskersByTag(interval string, teamId string, tag string, limit int) ([]*model.TopUserByTagResult, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "UserPointHistoryStore.TopAskersByTag")
+	defer span.End()
+
+	result0, err := s.UserPointHistoryStore.TopAskersByTag(interval, teamId, tag, limit)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerUserPointHistoryStore) TopAnswerersByTag(interval string, teamId string, tag string, limit int) ([]*model.TopUserByTagResult, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "UserPointHistoryStore.TopAnswerersByTag")
+	defer span.End()
+
+	result0, err := s.UserPointHistoryStore.TopAnswerersByTag(interval, teamId, tag, limit)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerUserPointHistoryStore) TopAnswersByTag(interval string, teamId string, tag string, limit int) ([]*model.TopPostByTagResult, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "UserPointHistoryStore.TopAnswersByTag")
+	defer span.End()
+
+	result0, err := s.UserPointHistoryStore.TopAnswersByTag(interval, teamId, tag, limit)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerInboxMessageStore) GetSingle(id string) (*model.InboxMessage, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "InboxMessageStore.GetSingle")
+	defer span.End()
+
+	result0, err := s.InboxMessageStore.GetSingle(id)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerInboxMessageStore) GetInboxMessages(time int64, userId string, direction string, page int, perPage int, teamId string, cursor *model.Cursor) ([]*model.InboxMessage, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "InboxMessageStore.GetInboxMessages")
+	defer span.End()
+
+	result0, err := s.InboxMessageStore.GetInboxMessages(time, userId, direction, page, perPage, teamId, cursor)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerInboxMessageStore) GetInboxMessagesUnreadCount(userId string, fromDate int64, teamId string) (int64, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "InboxMessageStore.GetInboxMessagesUnreadCount")
+	defer span.End()
+
+	result0, err := s.InboxMessageStore.GetInboxMessagesUnreadCount(userId, fromDate, teamId)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerInboxMessageStore) SaveInboxMessage(inboxMessage *model.InboxMessage) (*model.InboxMessage, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "InboxMessageStore.SaveInboxMessage")
+	defer span.End()
+
+	result0, err := s.InboxMessageStore.SaveInboxMessage(inboxMessage)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerInboxMessageStore) SaveMultipleInboxMessages(inboxMessages []*model.InboxMessage) ([]*model.InboxMessage, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "InboxMessageStore.SaveMultipleInboxMessages")
+	defer span.End()
+
+	result0, err := s.InboxMessageStore.SaveMultipleInboxMessages(inboxMessages)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerUserFavoritePostStore) GetByPostIdForUser(userId string, postId string) (*model.UserFavoritePost, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "UserFavoritePostStore.GetByPostIdForUser")
+	defer span.End()
+
+	result0, err := s.UserFavoritePostStore.GetByPostIdForUser(userId, postId)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerUserFavoritePostStore) GetCountByPostId(postId string) (int64, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "UserFavoritePostStore.GetCountByPostId")
+	defer span.End()
+
+	result0, err := s.UserFavoritePostStore.GetCountByPostId(postId)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerUserFavoritePostStore) GetUserFavoritePostsBeforeTime(time int64, userId string, page int, perPage int, getCount bool, teamId string, cursor *model.Cursor) ([]*model.UserFavoritePost, int64, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "UserFavoritePostStore.GetUserFavoritePostsBeforeTime")
+	defer span.End()
+
+	result0, result1, err := s.UserFavoritePostStore.GetUserFavoritePostsBeforeTime(time, userId, page, perPage, getCount, teamId, cursor)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, result1, err
+}
+
+func (s *TracingLayerUserFavoritePostStore) Save(postId string, userId string, teamId string) *model.AppError {
+	span, _ := tracing.StartSpan(s.Root.ctx, "UserFavoritePostStore.Save")
+	defer span.End()
+
+	err := s.UserFavoritePostStore.Save(postId, userId, teamId)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerUserFavoritePostStore) Delete(postId string, userId string) *model.AppError {
+	span, _ := tracing.StartSpan(s.Root.ctx, "UserFavoritePostStore.Delete")
+	defer span.End()
+
+	err := s.UserFavoritePostStore.Delete(postId, userId)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerUserFavoritePostStore) GetForExport(teamId string, offset int, limit int) ([]*model.UserFavoritePost, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "UserFavoritePostStore.GetForExport")
+	defer span.End()
+
+	result0, err := s.UserFavoritePostStore.GetForExport(teamId, offset, limit)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerFileInfoStore) Save(info *model.FileInfo) (*model.FileInfo, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "FileInfoStore.Save")
+	defer span.End()
+
+	result0, err := s.FileInfoStore.Save(info)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerFileInfoStore) DeleteForPost(postId string) (string, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "FileInfoStore.DeleteForPost")
+	defer span.End()
+
+	result0, err := s.FileInfoStore.DeleteForPost(postId)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerFileInfoStore) Get(id string) (*model.FileInfo, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "FileInfoStore.Get")
+	defer span.End()
+
+	result0, err := s.FileInfoStore.Get(id)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerFileInfoStore) AttachToPost(fileId string, postId string, userId string) *model.AppError {
+	span, _ := tracing.StartSpan(s.Root.ctx, "FileInfoStore.AttachToPost")
+	defer span.End()
+
+	err := s.FileInfoStore.AttachToPost(fileId, postId, userId)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerFileInfoStore) GetForPosts(postIds []string) ([]*model.FileInfo, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "FileInfoStore.GetForPosts")
+	defer span.End()
+
+	result0, err := s.FileInfoStore.GetForPosts(postIds)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerNotificationSettingStore) Get(userId string) (*model.NotificationSetting, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "NotificationSettingStore.Get")
+	defer span.End()
+
+	result0, err := s.NotificationSettingStore.Get(userId)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerNotificationSettingStore) Save(userId string, inboxInterval string) *model.AppError {
+	span, _ := tracing.StartSpan(s.Root.ctx, "NotificationSettingStore.Save")
+	defer span.End()
+
+	err := s.NotificationSettingStore.Save(userId, inboxInterval)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerPostViewsHistoryStore) GetViewsHistoryCount(teamId string, fromDate int64, toDate int64) (int64, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "PostViewsHistoryStore.GetViewsHistoryCount")
+	defer span.End()
+
+	result0, err := s.PostViewsHistoryStore.GetViewsHistoryCount(teamId, fromDate, toDate)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerPostViewsHistoryStore) AnalyticsPostViewsHistoryCounts(teamId string) (model.Analytics, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "PostViewsHistoryStore.AnalyticsPostViewsHistoryCounts")
+	defer span.End()
+
+	result0, err := s.PostViewsHistoryStore.AnalyticsPostViewsHistoryCounts(teamId)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerWebhookStore) GetByTeam(teamId string, userId string, offset int, limit int) ([]*model.Webhook, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "WebhookStore.GetByTeam")
+	defer span.End()
+
+	result0, err := s.WebhookStore.GetByTeam(teamId, userId, offset, limit)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerWebhookStore) Save(webhook *model.Webhook) (*model.Webhook, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "WebhookStore.Save")
+	defer span.End()
+
+	result0, err := s.WebhookStore.Save(webhook)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerWebhookStore) Get(id string) (*model.Webhook, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "WebhookStore.Get")
+	defer span.End()
+
+	result0, err := s.WebhookStore.Get(id)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerWebhookStore) Update(hook *model.Webhook) (*model.Webhook, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "WebhookStore.Update")
+	defer span.End()
+
+	result0, err := s.WebhookStore.Update(hook)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerWebhookStore) Delete(webhookId string, time int64) *model.AppError {
+	span, _ := tracing.StartSpan(s.Root.ctx, "WebhookStore.Delete")
+	defer span.End()
+
+	err := s.WebhookStore.Delete(webhookId, time)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerWebhookStore) IncrementFailureCount(webhookId string) (int, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "WebhookStore.IncrementFailureCount")
+	defer span.End()
+
+	result0, err := s.WebhookStore.IncrementFailureCount(webhookId)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerWebhookStore) ResetFailureCount(webhookId string) *model.AppError {
+	span, _ := tracing.StartSpan(s.Root.ctx, "WebhookStore.ResetFailureCount")
+	defer span.End()
+
+	err := s.WebhookStore.ResetFailureCount(webhookId)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerWebhookStore) Disable(webhookId string, time int64) *model.AppError {
+	span, _ := tracing.StartSpan(s.Root.ctx, "WebhookStore.Disable")
+	defer span.End()
+
+	err := s.WebhookStore.Disable(webhookId, time)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerWebhooksHistoryStore) LogWebhookEvent(history *model.WebhooksHistory) error {
+	span, _ := tracing.StartSpan(s.Root.ctx, "WebhooksHistoryStore.LogWebhookEvent")
+	defer span.End()
+
+	err := s.WebhooksHistoryStore.LogWebhookEvent(history)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerWebhooksHistoryStore) Get(id string) (*model.WebhooksHistory, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "WebhooksHistoryStore.Get")
+	defer span.End()
+
+	result0, err := s.WebhooksHistoryStore.Get(id)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerWebhooksHistoryStore) Search(options *model.SearchWebhooksHistoryOptions) ([]*model.WebhooksHistory, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "WebhooksHistoryStore.Search")
+	defer span.End()
+
+	result0, err := s.WebhooksHistoryStore.Search(options)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerWebhooksHistoryStore) PermanentDeleteBefore(time int64, limit int) (int64, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "WebhooksHistoryStore.PermanentDeleteBefore")
+	defer span.End()
+
+	result0, err := s.WebhooksHistoryStore.PermanentDeleteBefore(time, limit)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerWebhookDeliveryStore) Save(delivery *model.WebhookDelivery) (*model.WebhookDelivery, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "WebhookDeliveryStore.Save")
+	defer span.End()
+
+	result0, err := s.WebhookDeliveryStore.Save(delivery)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerWebhookDeliveryStore) GetDue(now int64, limit int) ([]*model.WebhookDelivery, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "WebhookDeliveryStore.GetDue")
+	defer span.End()
+
+	result0, err := s.WebhookDeliveryStore.GetDue(now, limit)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerWebhookDeliveryStore) Claim(delivery *model.WebhookDelivery, lockUntil int64) (bool, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "WebhookDeliveryStore.Claim")
+	defer span.End()
+
+	result0, err := s.WebhookDeliveryStore.Claim(delivery, lockUntil)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerWebhookDeliveryStore) Update(delivery *model.WebhookDelivery) (*model.WebhookDelivery, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "WebhookDeliveryStore.Update")
+	defer span.End()
+
+	result0, err := s.WebhookDeliveryStore.Update(delivery)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerWebhookDeliveryStore) PermanentDeleteFinishedBefore(time int64, limit int) (int64, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "WebhookDeliveryStore.PermanentDeleteFinishedBefore")
+	defer span.End()
+
+	result0, err := s.WebhookDeliveryStore.PermanentDeleteFinishedBefore(time, limit)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerIncomingWebhookStore) Save(hook *model.IncomingWebhook) (*model.IncomingWebhook, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "IncomingWebhookStore.Save")
+	defer span.End()
+
+	result0, err := s.IncomingWebhookStore.Save(hook)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerIncomingWebhookStore) Get(id string) (*model.IncomingWebhook, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "IncomingWebhookStore.Get")
+	defer span.End()
+
+	result0, err := s.IncomingWebhookStore.Get(id)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerIncomingWebhookStore) GetByToken(token string) (*model.IncomingWebhook, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "IncomingWebhookStore.GetByToken")
+	defer span.End()
+
+	result0, err := s.IncomingWebhookStore.GetByToken(token)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerIncomingWebhookStore) GetByTeam(teamId string, offset int, limit int) ([]*model.IncomingWebhook, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "IncomingWebhookStore.GetByTeam")
+	defer span.End()
+
+	result0, err := s.IncomingWebhookStore.GetByTeam(teamId, offset, limit)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerIncomingWebhookStore) Update(hook *model.IncomingWebhook) (*model.IncomingWebhook, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "IncomingWebhookStore.Update")
+	defer span.End()
+
+	result0, err := s.IncomingWebhookStore.Update(hook)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerIncomingWebhookStore) Delete(hookId string, time int64) *model.AppError {
+	span, _ := tracing.StartSpan(s.Root.ctx, "IncomingWebhookStore.Delete")
+	defer span.End()
+
+	err := s.IncomingWebhookStore.Delete(hookId, time)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerIncomingWebhookStore) DeleteByBot(botUserId string, time int64) *model.AppError {
+	span, _ := tracing.StartSpan(s.Root.ctx, "IncomingWebhookStore.DeleteByBot")
+	defer span.End()
+
+	err := s.IncomingWebhookStore.DeleteByBot(botUserId, time)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerBotStore) Save(bot *model.Bot) (*model.Bot, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "BotStore.Save")
+	defer span.End()
+
+	result0, err := s.BotStore.Save(bot)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerBotStore) Get(userId string) (*model.Bot, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "BotStore.Get")
+	defer span.End()
+
+	result0, err := s.BotStore.Get(userId)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerBotStore) GetByTeam(teamId string, offset int, limit int) ([]*model.Bot, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "BotStore.GetByTeam")
+	defer span.End()
+
+	result0, err := s.BotStore.GetByTeam(teamId, offset, limit)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerBotStore) Update(bot *model.Bot) (*model.Bot, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "BotStore.Update")
+	defer span.End()
+
+	result0, err := s.BotStore.Update(bot)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerAuditStore) Get(user_id string, offset int, limit int) (model.Audits, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "AuditStore.Get")
+	defer span.End()
+
+	result0, err := s.AuditStore.Get(user_id, offset, limit)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerAuditStore) Save(audit *model.Audit) *model.AppError {
+	span, _ := tracing.StartSpan(s.Root.ctx, "AuditStore.Save")
+	defer span.End()
+
+	err := s.AuditStore.Save(audit)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerAuditStore) Search(options *model.SearchAuditsOptions) (model.Audits, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "AuditStore.Search")
+	defer span.End()
+
+	result0, err := s.AuditStore.Search(options)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerAuditStore) PermanentDeleteByUser(userId string) *model.AppError {
+	span, _ := tracing.StartSpan(s.Root.ctx, "AuditStore.PermanentDeleteByUser")
+	defer span.End()
+
+	err := s.AuditStore.PermanentDeleteByUser(userId)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerOAuthStore) SaveApp(app *model.OAuthApp) (*model.OAuthApp, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "OAuthStore.SaveApp")
+	defer span.End()
+
+	result0, err := s.OAuthStore.SaveApp(app)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerOAuthStore) UpdateApp(app *model.OAuthApp) (*model.OAuthApp, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "OAuthStore.UpdateApp")
+	defer span.End()
+
+	result0, err := s.OAuthStore.UpdateApp(app)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerOAuthStore) GetApp(id string) (*model.OAuthApp, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "OAuthStore.GetApp")
+	defer span.End()
+
+	result0, err := s.OAuthStore.GetApp(id)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerOAuthStore) GetAppByUserId(userId string, offset int, limit int) ([]*model.OAuthApp, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "OAuthStore.GetAppByUserId")
+	defer span.End()
+
+	result0, err := s.OAuthStore.GetAppByUserId(userId, offset, limit)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerOAuthStore) DeleteApp(id string) *model.AppError {
+	span, _ := tracing.StartSpan(s.Root.ctx, "OAuthStore.DeleteApp")
+	defer span.End()
+
+	err := s.OAuthStore.DeleteApp(id)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerOAuthStore) GetAuthorizedApps(userId string, offset int, limit int) ([]*model.OAuthApp, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "OAuthStore.GetAuthorizedApps")
+	defer span.End()
+
+	result0, err := s.OAuthStore.GetAuthorizedApps(userId, offset, limit)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerOAuthStore) SaveAuthData(authData *model.AuthData) (*model.AuthData, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "OAuthStore.SaveAuthData")
+	defer span.End()
+
+	result0, err := s.OAuthStore.SaveAuthData(authData)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerOAuthStore) SaveAuthorizedApp(app *model.OAuthAuthorizedApp) *model.AppError {
+	span, _ := tracing.StartSpan(s.Root.ctx, "OAuthStore.SaveAuthorizedApp")
+	defer span.End()
+
+	err := s.OAuthStore.SaveAuthorizedApp(app)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerOAuthStore) GetAccessData(token string) (*model.AccessData, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "OAuthStore.GetAccessData")
+	defer span.End()
+
+	result0, err := s.OAuthStore.GetAccessData(token)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerOAuthStore) RemoveAccessData(token string) *model.AppError {
+	span, _ := tracing.StartSpan(s.Root.ctx, "OAuthStore.RemoveAccessData")
+	defer span.End()
+
+	err := s.OAuthStore.RemoveAccessData(token)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerOAuthStore) SaveAccessData(accessData *model.AccessData) (*model.AccessData, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "OAuthStore.SaveAccessData")
+	defer span.End()
+
+	result0, err := s.OAuthStore.SaveAccessData(accessData)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerOAuthStore) GetAuthData(code string) (*model.AuthData, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "OAuthStore.GetAuthData")
+	defer span.End()
+
+	result0, err := s.OAuthStore.GetAuthData(code)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerOAuthStore) RemoveAuthData(code string) *model.AppError {
+	span, _ := tracing.StartSpan(s.Root.ctx, "OAuthStore.RemoveAuthData")
+	defer span.End()
+
+	err := s.OAuthStore.RemoveAuthData(code)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerOAuthStore) GetPreviousAccessData(userId string, clientId string) (*model.AccessData, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "OAuthStore.GetPreviousAccessData")
+	defer span.End()
+
+	result0, err := s.OAuthStore.GetPreviousAccessData(userId, clientId)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerOAuthStore) UpdateAccessData(accessData *model.AccessData) (*model.AccessData, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "OAuthStore.UpdateAccessData")
+	defer span.End()
+
+	result0, err := s.OAuthStore.UpdateAccessData(accessData)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerOAuthStore) GetAccessDataByRefreshToken(token string) (*model.AccessData, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "OAuthStore.GetAccessDataByRefreshToken")
+	defer span.End()
+
+	result0, err := s.OAuthStore.GetAccessDataByRefreshToken(token)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerOAuthStore) GetAccessDataByUserForApp(userId string, clientId string) ([]*model.AccessData, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "OAuthStore.GetAccessDataByUserForApp")
+	defer span.End()
+
+	result0, err := s.OAuthStore.GetAccessDataByUserForApp(userId, clientId)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerOAuthStore) DeleteAuthorizedApp(userId string, clientId string) *model.AppError {
+	span, _ := tracing.StartSpan(s.Root.ctx, "OAuthStore.DeleteAuthorizedApp")
+	defer span.End()
+
+	err := s.OAuthStore.DeleteAuthorizedApp(userId, clientId)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerStatusStore) Get(userId string) (*model.Status, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "StatusStore.Get")
+	defer span.End()
+
+	result0, err := s.StatusStore.Get(userId)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerStatusStore) GetByIds(userIds []string) ([]*model.Status, error) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "StatusStore.GetByIds")
+	defer span.End()
+
+	result0, err := s.StatusStore.GetByIds(userIds)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerStatusStore) SaveOrUpdate(status *model.Status) error {
+	span, _ := tracing.StartSpan(s.Root.ctx, "StatusStore.SaveOrUpdate")
+	defer span.End()
+
+	err := s.StatusStore.SaveOrUpdate(status)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerStatusStore) UpdateLastActivityAt(userId string, lastActivityAt int64) error {
+	span, _ := tracing.StartSpan(s.Root.ctx, "StatusStore.UpdateLastActivityAt")
+	defer span.End()
+
+	err := s.StatusStore.UpdateLastActivityAt(userId, lastActivityAt)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func (s *TracingLayerJobStore) Save(job *model.Job) (*model.Job, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "JobStore.Save")
+	defer span.End()
+
+	result0, err := s.JobStore.Save(job)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerJobStore) Get(id string) (*model.Job, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "JobStore.Get")
+	defer span.End()
+
+	result0, err := s.JobStore.Get(id)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerJobStore) Update(job *model.Job) (*model.Job, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "JobStore.Update")
+	defer span.End()
+
+	result0, err := s.JobStore.Update(job)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerJobStore) GetActiveCountByTeamAndType(teamId string, jobType string) (int64, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "JobStore.GetActiveCountByTeamAndType")
+	defer span.End()
+
+	result0, err := s.JobStore.GetActiveCountByTeamAndType(teamId, jobType)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerSystemStore) GetByName(name string) (*model.System, *model.AppError) {
+	span, _ := tracing.StartSpan(s.Root.ctx, "SystemStore.GetByName")
+	defer span.End()
+
+	result0, err := s.SystemStore.GetByName(name)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return result0, err
+}
+
+func (s *TracingLayerSystemStore) SaveOrUpdate(system *model.System) *model.AppError {
+	span, _ := tracing.StartSpan(s.Root.ctx, "SystemStore.SaveOrUpdate")
+	defer span.End()
+
+	err := s.SystemStore.SaveOrUpdate(system)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+	}
+
+	return err
+}
+
+func New(childStore store.Store, ctx context.Context) *TracingLayer {
+	newStore := TracingLayer{
+		Store: childStore,
+		ctx:   ctx,
+	}
+
+	newStore.TeamStore = &TracingLayerTeamStore{TeamStore: childStore.Team(), Root: &newStore}
+	newStore.TeamMemberHistoryStore = &TracingLayerTeamMemberHistoryStore{TeamMemberHistoryStore: childStore.TeamMemberHistory(), Root: &newStore}
+	newStore.UserGroupStore = &TracingLayerUserGroupStore{UserGroupStore: childStore.UserGroup(), Root: &newStore}
+	newStore.GroupMemberHistoryStore = &TracingLayerGroupMemberHistoryStore{GroupMemberHistoryStore: childStore.GroupMemberHistory(), Root: &newStore}
+	newStore.CollectionStore = &TracingLayerCollectionStore{CollectionStore: childStore.Collection(), Root: &newStore}
+	newStore.UserStore = &TracingLayerUserStore{UserStore: childStore.User(), Root: &newStore}
+	newStore.TokenStore = &TracingLayerTokenStore{TokenStore: childStore.Token(), Root: &newStore}
+	newStore.SessionStore = &TracingLayerSessionStore{SessionStore: childStore.Session(), Root: &newStore}
+	newStore.PostStore = &TracingLayerPostStore{PostStore: childStore.Post(), Root: &newStore}
+	newStore.TagStore = &TracingLayerTagStore{TagStore: childStore.Tag(), Root: &newStore}
+	newStore.VoteStore = &TracingLayerVoteStore{VoteStore: childStore.Vote(), Root: &newStore}
+	newStore.UserPointHistoryStore = &TracingLayerUserPointHistoryStore{UserPointHistoryStore: childStore.UserPointHistory(), Root: &newStore}
+	newStore.InboxMessageStore = &TracingLayerInboxMessageStore{InboxMessageStore: childStore.InboxMessage(), Root: &newStore}
+	newStore.UserFavoritePostStore = &TracingLayerUserFavoritePostStore{UserFavoritePostStore: childStore.UserFavoritePost(), Root: &newStore}
+	newStore.FileInfoStore = &TracingLayerFileInfoStore{FileInfoStore: childStore.FileInfo(), Root: &newStore}
+	newStore.NotificationSettingStore = &TracingLayerNotificationSettingStore{NotificationSettingStore: childStore.NotificationSetting(), Root: &newStore}
+	newStore.PostViewsHistoryStore = &TracingLayerPostViewsHistoryStore{PostViewsHistoryStore: childStore.PostViewsHistory(), Root: &newStore}
+	newStore.WebhookStore = &TracingLayerWebhookStore{WebhookStore: childStore.Webhook(), Root: &newStore}
+	newStore.WebhooksHistoryStore = &TracingLayerWebhooksHistoryStore{WebhooksHistoryStore: childStore.WebhooksHistory(), Root: &newStore}
+	newStore.WebhookDeliveryStore = &TracingLayerWebhookDeliveryStore{WebhookDeliveryStore: childStore.WebhookDelivery(), Root: &newStore}
+	newStore.IncomingWebhookStore = &TracingLayerIncomingWebhookStore{IncomingWebhookStore: childStore.IncomingWebhook(), Root: &newStore}
+	newStore.BotStore = &TracingLayerBotStore{BotStore: childStore.Bot(), Root: &newStore}
+	newStore.AuditStore = &TracingLayerAuditStore{AuditStore: childStore.Audit(), Root: &newStore}
+	newStore.OAuthStore = &TracingLayerOAuthStore{OAuthStore: childStore.OAuth(), Root: &newStore}
+	newStore.StatusStore = &TracingLayerStatusStore{StatusStore: childStore.Status(), Root: &newStore}
+	newStore.JobStore = &TracingLayerJobStore{JobStore: childStore.Job(), Root: &newStore}
+	newStore.SystemStore = &TracingLayerSystemStore{SystemStore: childStore.System(), Root: &newStore}
+
+	return &newStore
+}
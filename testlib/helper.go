@@ -44,7 +44,7 @@ func (h *MainHelper) Main(m *testing.M) {
 func (h *MainHelper) setupStore() {
 	h.Settings = storetest.MakeSqlSettings()
 
-	h.SQLSupplier = sqlstore.NewSqlSupplier(*h.Settings)
+	h.SQLSupplier = sqlstore.NewSqlSupplier(*h.Settings, nil)
 
 	h.Store = TestStore{h.SQLSupplier}
 }
@@ -54,9 +54,10 @@ func (h *MainHelper) Close() error {
 		h.SQLSupplier.Close()
 	}
 
-	dbStore := h.GetStore()
 	// clear tables when test packages ends
-	dbStore.DropAllTables()
+	if h.Store != nil {
+		h.Store.DropAllTables()
+	}
 
 	if r := recover(); r != nil {
 		log.Fatalln(r)
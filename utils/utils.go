@@ -3,6 +3,7 @@ package utils
 import (
 	"net"
 	"net/http"
+	"reflect"
 	"strings"
 )
 
@@ -58,3 +59,12 @@ func StringArrayIntersection(arr1, arr2 []string) []string {
 
 	return result
 }
+
+// スライスの要素をその場で逆順に並べ替える
+func ReverseSlice(slice interface{}) {
+	swap := reflect.Swapper(slice)
+	length := reflect.ValueOf(slice).Len()
+	for i := 0; i < length/2; i++ {
+		swap(i, length-1-i)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/clear-ness/qa-discussion/app"
+	"github.com/clear-ness/qa-discussion/audit"
 	"github.com/clear-ness/qa-discussion/mlog"
 	"github.com/clear-ness/qa-discussion/model"
 	"github.com/clear-ness/qa-discussion/utils"
@@ -20,6 +21,26 @@ type Context struct {
 	siteURLHeader string
 }
 
+// リクエストのパス、セッション、クライアント、IPを埋めたaudit recordを作る。
+// ハンドラーの最後でLogAuditRecに渡す。
+func (c *Context) MakeAuditRecord(event string, initialStatus string) *audit.Record {
+	return &audit.Record{
+		APIPath:   c.App.Path,
+		Event:     event,
+		Status:    initialStatus,
+		UserID:    c.App.Session.UserId,
+		SessionID: c.App.Session.Id,
+		Client:    c.App.UserAgent,
+		IPAddress: c.App.IpAddress,
+		Meta:      audit.Meta{},
+	}
+}
+
+// c.Errがセットされている場合は失敗として記録する
+func (c *Context) LogAuditRec(rec *audit.Record) {
+	c.App.LogAuditRec(rec, c.Err)
+}
+
 func (c *Context) SetInvalidParam(parameter string) {
 	c.Err = NewInvalidParamError(parameter)
 }
@@ -38,6 +59,21 @@ func NewInvalidUrlParamError(parameter string) *model.AppError {
 	return err
 }
 
+// cursorパラメータを一覧の種類と並び順に合わせて読み取る。指定が無ければnilを返し、pageとper_pageで取得する
+func (c *Context) CursorParam(cursorType string, sort string) *model.Cursor {
+	if c.Params.Cursor == "" {
+		return nil
+	}
+
+	cursor, err := model.DecodeCursor(c.Params.Cursor, cursorType, sort)
+	if err != nil {
+		c.SetInvalidUrlParam("cursor")
+		return nil
+	}
+
+	return cursor
+}
+
 func (c *Context) RemoveSessionCookie(w http.ResponseWriter, r *http.Request) {
 	subpath, _ := utils.GetSubpathFromConfig(c.App.Config())
 
@@ -203,6 +239,18 @@ func (c *Context) RequireTeamId() *Context {
 	return c
 }
 
+func (c *Context) RequireJobId() *Context {
+	if c.Err != nil {
+		return c
+	}
+
+	if len(c.Params.JobId) != 26 {
+		c.SetInvalidUrlParam("job_id")
+	}
+
+	return c
+}
+
 func (c *Context) RequireGroupId() *Context {
 	if c.Err != nil {
 		return c
@@ -275,6 +323,54 @@ func (c *Context) RequireHookId() *Context {
 	return c
 }
 
+func (c *Context) RequireHistoryId() *Context {
+	if c.Err != nil {
+		return c
+	}
+
+	if len(c.Params.HistoryId) != 26 {
+		c.SetInvalidUrlParam("history_id")
+	}
+
+	return c
+}
+
+func (c *Context) RequireIncomingHookId() *Context {
+	if c.Err != nil {
+		return c
+	}
+
+	if len(c.Params.IncomingHookId) != 26 {
+		c.SetInvalidUrlParam("incoming_hook_id")
+	}
+
+	return c
+}
+
+func (c *Context) RequireHookToken() *Context {
+	if c.Err != nil {
+		return c
+	}
+
+	if len(c.Params.HookToken) != 26 {
+		c.SetInvalidUrlParam("token")
+	}
+
+	return c
+}
+
+func (c *Context) RequireBotUserId() *Context {
+	if c.Err != nil {
+		return c
+	}
+
+	if len(c.Params.BotUserId) != 26 {
+		c.SetInvalidUrlParam("bot_user_id")
+	}
+
+	return c
+}
+
 func (c *Context) RequireAppId() *Context {
 	if c.Err != nil {
 		return c
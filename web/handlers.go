@@ -1,16 +1,20 @@
 package web
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"reflect"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/clear-ness/qa-discussion/app"
 	"github.com/clear-ness/qa-discussion/mlog"
 	"github.com/clear-ness/qa-discussion/model"
+	"github.com/clear-ness/qa-discussion/services/tracing"
 	"github.com/clear-ness/qa-discussion/utils"
+	"go.opentelemetry.io/otel/api/trace"
 )
 
 func GetHandlerName(h func(*Context, http.ResponseWriter, *http.Request)) string {
@@ -30,13 +34,15 @@ type Handler struct {
 	TrustRequester      bool
 	IsStatic            bool
 	DisableWhenBusy     bool
+	// POSTでもDBに書き込まない(検索や閲覧数の記録など)。read-your-writesの書き込みとして記録しない
+	ReadOnly bool
 
 	cspShaDirective string
 }
 
 func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	now := time.Now()
 	requestID := model.NewId()
-	mlog.Debug("Received HTTP request", mlog.String("method", r.Method), mlog.String("url", r.URL.Path), mlog.String("request_id", requestID))
 
 	c := &Context{}
 	c.App = app.New(
@@ -44,6 +50,36 @@ func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	)
 	c.App.RequestId = requestID
 
+	ctx := context.Background()
+	if c.App.Srv.Tracer != nil {
+		var span trace.Span
+		span, ctx = tracing.StartServerSpan(r, "api:"+h.HandlerName)
+		defer func() {
+			statusCode := http.StatusOK
+			if c.Err != nil {
+				statusCode = c.Err.StatusCode
+			}
+			tracing.EndServerSpan(span, statusCode)
+		}()
+	}
+
+	ctx, stop := cancelOnDisconnect(ctx, r)
+	defer stop()
+
+	c.App.SetContext(ctx)
+	if c.App.Srv.Tracer != nil {
+		// store、redis、ESの呼び出しをこのリクエストのspanにぶら下げる
+		c.App.SetStore(c.App.Srv.TracedStore(ctx))
+
+		if traceId := c.App.TraceId(); traceId != "" {
+			w.Header().Set(model.HEADER_TRACE_ID, traceId)
+		}
+	} else {
+		c.App.SetStore(c.App.Srv.Store.WithContext(ctx))
+	}
+
+	mlog.Debug("Received HTTP request", mlog.String("method", r.Method), mlog.String("url", r.URL.Path), mlog.String("request_id", requestID), mlog.String("trace_id", c.App.TraceId()))
+
 	c.App.IpAddress = utils.GetIpAddress(r, c.App.Config().ServiceSettings.TrustedProxyIPHeader)
 
 	c.App.UserAgent = r.UserAgent()
@@ -82,10 +118,16 @@ func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			c.Err = model.NewAppError("ServeHTTP", "api.context.token_provided.app_error", nil, "token="+token, http.StatusUnauthorized)
 		} else {
 			c.App.Session = *session
+
+			background := c.App.Detached()
+			c.App.Srv.Go(func() {
+				background.UpdateLastActivityAtIfNeeded(*session)
+				background.ExtendSessionExpiryIfNeeded(session)
+			})
 		}
 
 		// rate limit also by userId
-		if c.App.Srv.RateLimiter != nil && c.App.Srv.RateLimiter.UserIdRateLimit(c.App.Session.UserId, w) {
+		if rateLimiter := c.App.Srv.GetRateLimiter(); rateLimiter != nil && rateLimiter.UserIdRateLimit(c.App.Session.UserId, w) {
 			return
 		}
 
@@ -104,6 +146,10 @@ func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		c.SessionRequired()
 	}
 
+	if c.Err == nil {
+		c.App.SetupReadYourWrites(!h.ReadOnly && r.Method != http.MethodGet && r.Method != http.MethodHead)
+	}
+
 	if c.Err == nil {
 		h.HandleFunc(c, w, r)
 	}
@@ -112,6 +158,10 @@ func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		c.Err.RequestId = c.App.RequestId
 		c.Err.Where = r.URL.Path
 
+		if c.Err.StatusCode >= http.StatusInternalServerError {
+			mlog.Error("API request failed", mlog.String("handler", h.HandlerName), mlog.String("request_id", c.App.RequestId), mlog.String("trace_id", c.App.TraceId()), mlog.Err(c.Err))
+		}
+
 		if !*c.App.Config().ServiceSettings.EnableDeveloper {
 			// hide internal error details
 			c.Err.DetailedError = ""
@@ -119,11 +169,47 @@ func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			c.Err.Where = ""
 		}
 
-		if IsApiCall(c.App, r) || IsOAuthApiCall(c.App, r) {
+		if IsApiCall(c.App, r) || IsOAuthApiCall(c.App, r) || IsOpenIdCall(c.App, r) {
 			w.WriteHeader(c.Err.StatusCode)
 			w.Write([]byte(c.Err.ToJson()))
 		}
 	}
+
+	if c.App.Metrics() != nil {
+		// ResponseWriterをラップするとwebSocketのhijackができなくなるので、
+		// ステータスコードはc.Errから判断する
+		statusCode := http.StatusOK
+		if c.Err != nil {
+			statusCode = c.Err.StatusCode
+		}
+
+		elapsed := float64(time.Since(now)) / float64(time.Second)
+		c.App.Metrics().IncrementHttpRequest(h.HandlerName, r.Method, statusCode)
+		c.App.Metrics().ObserveHttpRequestDuration(h.HandlerName, r.Method, elapsed)
+	}
+}
+
+// ハンドラの処理中にクライアントが切断したら、store、redis、ESへの呼び出しを中断するcontextを返す。
+// r.Context()はハンドラが返ると必ずキャンセルされるので、そのままは使わない。
+// stopを呼んだ後はキャンセルしないので、リクエストから起動した非同期処理は中断されない
+func cancelOnDisconnect(parent context.Context, r *http.Request) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(parent)
+	done := make(chan struct{})
+
+	go func() {
+		select {
+		case <-r.Context().Done():
+			// ハンドラが返った後のキャンセルと区別する
+			select {
+			case <-done:
+			default:
+				cancel()
+			}
+		case <-done:
+		}
+	}()
+
+	return ctx, func() { close(done) }
 }
 
 func (h *Handler) checkCSRFToken(c *Context, r *http.Request, token string, tokenLocation app.TokenLocation, session *model.Session) (checked bool, passed bool) {
@@ -0,0 +1,163 @@
+package web
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/api/trace"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/clear-ness/qa-discussion/app"
+	"github.com/clear-ness/qa-discussion/mlog"
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/clear-ness/qa-discussion/services/tracing"
+	"github.com/clear-ness/qa-discussion/store/memstore"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// DB・Redis・ESなしで動かすため、memstoreを層で包まずに使う
+func setupServer(t *testing.T) (*app.Server, func()) {
+	tempDir, err := ioutil.TempDir("", "webtest")
+	require.NoError(t, err)
+
+	s, err := app.NewServer(
+		app.Config(filepath.Join(tempDir, "config.json"), false),
+		app.StoreOverride(memstore.New()),
+		app.SkipStoreLayers(),
+		app.SkipBackgroundJobs(),
+	)
+	if err != nil {
+		os.RemoveAll(tempDir)
+		require.NoError(t, err)
+	}
+
+	return s, func() {
+		s.Shutdown()
+		os.RemoveAll(tempDir)
+	}
+}
+
+func enableTracing(t *testing.T, s *app.Server) {
+	s.UpdateConfig(func(cfg *model.Config) {
+		*cfg.TracingSettings.Enable = true
+		*cfg.TracingSettings.Exporter = model.TRACING_EXPORTER_STDOUT
+		*cfg.TracingSettings.SampleRate = 1
+	})
+
+	tracer, err := tracing.New(&s.Config().TracingSettings)
+	require.NoError(t, err)
+	s.Tracer = tracer
+}
+
+// mlogのglobalな出力先を差し替え、指定したメッセージのフィールドを集める
+func captureLog(logFunc *mlog.LogFunc, message string) (map[string]string, func()) {
+	fields := map[string]string{}
+	original := *logFunc
+
+	*logFunc = func(msg string, fs ...mlog.Field) {
+		if msg != message {
+			return
+		}
+		for _, f := range fs {
+			if f.Type == zapcore.StringType {
+				fields[f.Key] = f.String
+			}
+		}
+	}
+
+	return fields, func() { *logFunc = original }
+}
+
+func serve(s *app.Server, handleFunc func(*Context, http.ResponseWriter, *http.Request), header http.Header) *httptest.ResponseRecorder {
+	handler := Handler{
+		GetGlobalAppOptions: s.AppOptions,
+		HandleFunc:          handleFunc,
+		HandlerName:         "test",
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/api/v4/test", nil)
+	for key, values := range header {
+		r.Header[key] = values
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	return w
+}
+
+func TestHandlerTracing(t *testing.T) {
+	s, tearDown := setupServer(t)
+	defer tearDown()
+
+	t.Run("disabled", func(t *testing.T) {
+		fields, restore := captureLog(&mlog.Debug, "Received HTTP request")
+		defer restore()
+
+		var traceId string
+		w := serve(s, func(c *Context, w http.ResponseWriter, r *http.Request) {
+			traceId = c.App.TraceId()
+		}, nil)
+
+		// 無効な場合はspanを作らず、ヘッダーもtrace idも付けない
+		assert.Nil(t, s.Tracer)
+		assert.Equal(t, "", traceId)
+		_, ok := w.Header()[model.HEADER_TRACE_ID]
+		assert.False(t, ok)
+
+		assert.Equal(t, w.Header().Get(model.HEADER_REQUEST_ID), fields["request_id"])
+		assert.Equal(t, "", fields["trace_id"])
+	})
+
+	enableTracing(t, s)
+	defer global.SetTracerProvider(trace.NoopTracerProvider())
+
+	t.Run("enabled", func(t *testing.T) {
+		fields, restore := captureLog(&mlog.Debug, "Received HTTP request")
+		defer restore()
+
+		var traceId string
+		w := serve(s, func(c *Context, w http.ResponseWriter, r *http.Request) {
+			traceId = c.App.TraceId()
+		}, nil)
+
+		assert.Len(t, traceId, 32)
+		assert.Equal(t, traceId, w.Header().Get(model.HEADER_TRACE_ID))
+
+		assert.Equal(t, w.Header().Get(model.HEADER_REQUEST_ID), fields["request_id"])
+		assert.Equal(t, traceId, fields["trace_id"])
+		assert.Equal(t, "/api/v4/test", fields["url"])
+	})
+
+	t.Run("incoming traceparent", func(t *testing.T) {
+		parentTraceId := "4bf92f3577b34da6a3ce929d0e0e4736"
+		header := http.Header{}
+		header.Set("traceparent", "00-"+parentTraceId+"-00f067aa0ba902b7-01")
+
+		w := serve(s, func(c *Context, w http.ResponseWriter, r *http.Request) {}, header)
+
+		// 呼び出し元のtraceを引き継ぐ
+		assert.Equal(t, parentTraceId, w.Header().Get(model.HEADER_TRACE_ID))
+	})
+
+	t.Run("error log", func(t *testing.T) {
+		fields, restore := captureLog(&mlog.Error, "API request failed")
+		defer restore()
+
+		w := serve(s, func(c *Context, w http.ResponseWriter, r *http.Request) {
+			c.Err = model.NewAppError("test", "api.test.app_error", nil, "", http.StatusInternalServerError)
+		}, nil)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.NotEmpty(t, fields["trace_id"])
+		assert.Equal(t, w.Header().Get(model.HEADER_TRACE_ID), fields["trace_id"])
+		assert.Equal(t, w.Header().Get(model.HEADER_REQUEST_ID), fields["request_id"])
+		assert.Equal(t, "test", fields["handler"])
+	})
+}
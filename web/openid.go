@@ -0,0 +1,127 @@
+package web
+
+import (
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/clear-ness/qa-discussion/app"
+	"github.com/clear-ness/qa-discussion/audit"
+	"github.com/clear-ness/qa-discussion/model"
+	"github.com/clear-ness/qa-discussion/services/configservice"
+	"github.com/clear-ness/qa-discussion/utils"
+)
+
+func (w *Web) InitOpenId() {
+	// stateを発行し、IdPの認可エンドポイントへリダイレクトする
+	w.MainRouter.Handle("/login/openid", w.ApiHandler(loginWithOpenId)).Methods("GET")
+
+	// IdPからのコールバック。codeをid tokenと交換してログインさせる
+	w.MainRouter.Handle(app.OPENID_COMPLETE_PATH, w.ApiHandler(completeOpenIdLogin)).Methods("GET")
+}
+
+func IsOpenIdCall(config configservice.ConfigService, r *http.Request) bool {
+	subpath, _ := utils.GetSubpathFromConfig(config.Config())
+
+	return r.URL.Path == path.Join(subpath, "login", "openid") ||
+		r.URL.Path == path.Join(subpath, app.OPENID_COMPLETE_PATH)
+}
+
+// オープンリダイレクトを防ぐため、サイト内の相対パスのみ許可する
+func isValidOpenIdRedirect(redirectTo string) bool {
+	if len(redirectTo) == 0 {
+		return true
+	}
+
+	return strings.HasPrefix(redirectTo, "/") && !strings.HasPrefix(redirectTo, "//") && !strings.HasPrefix(redirectTo, "/\\")
+}
+
+func loginWithOpenId(c *Context, w http.ResponseWriter, r *http.Request) {
+	redirectTo := r.URL.Query().Get("redirect_to")
+	if !isValidOpenIdRedirect(redirectTo) {
+		c.SetInvalidUrlParam("redirect_to")
+		return
+	}
+
+	authUrl, state, err := c.App.GetOpenIdLoginURL(redirectTo)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	subpath, _ := utils.GetSubpathFromConfig(c.App.Config())
+
+	// CSRF対策として、stateをブラウザにも保持させてコールバック時に照合する
+	http.SetCookie(w, &http.Cookie{
+		Name:     model.SESSION_COOKIE_OPENID,
+		Value:    state,
+		Path:     subpath,
+		MaxAge:   app.OPENID_STATE_EXPIRY_TIME / 1000,
+		HttpOnly: true,
+		Secure:   app.GetProtocol(r) == "https",
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	http.Redirect(w, r, authUrl, http.StatusFound)
+}
+
+func completeOpenIdLogin(c *Context, w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	auditRec := c.MakeAuditRecord("completeOpenIdLogin", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("auth_service", model.USER_AUTH_SERVICE_OPENID)
+
+	if idpErr := query.Get("error"); len(idpErr) > 0 {
+		auditRec.AddMeta("idp_error", idpErr)
+		c.Err = model.NewAppError("completeOpenIdLogin", "api.openid.complete.idp_error.app_error", nil, "error="+idpErr+", description="+query.Get("error_description"), http.StatusUnauthorized)
+		return
+	}
+
+	code := query.Get("code")
+	if len(code) == 0 {
+		c.SetInvalidUrlParam("code")
+		return
+	}
+
+	state := query.Get("state")
+	if len(state) != model.TOKEN_SIZE {
+		c.SetInvalidUrlParam("state")
+		return
+	}
+
+	stateCookie, cookieErr := r.Cookie(model.SESSION_COOKIE_OPENID)
+	if cookieErr != nil || stateCookie.Value != state {
+		c.Err = model.NewAppError("completeOpenIdLogin", "api.openid.complete.state_mismatch.app_error", nil, "", http.StatusBadRequest)
+		return
+	}
+
+	subpath, _ := utils.GetSubpathFromConfig(c.App.Config())
+	http.SetCookie(w, &http.Cookie{
+		Name:     model.SESSION_COOKIE_OPENID,
+		Value:    "",
+		Path:     subpath,
+		MaxAge:   -1,
+		HttpOnly: true,
+	})
+
+	user, redirectTo, err := c.App.CompleteOpenIdLogin(code, state)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	auditRec.UserID = user.Id
+
+	if err := c.App.DoLogin(w, r, user); err != nil {
+		c.Err = err
+		return
+	}
+
+	auditRec.SessionID = c.App.Session.Id
+	auditRec.Success()
+
+	c.App.AttachSessionCookies(w, r)
+
+	http.Redirect(w, r, strings.TrimSuffix(c.App.GetSiteURL(), "/")+redirectTo, http.StatusFound)
+}
@@ -0,0 +1,84 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/clear-ness/qa-discussion/audit"
+	"github.com/clear-ness/qa-discussion/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type auditCapture struct {
+	records chan *audit.Record
+}
+
+func (c *auditCapture) Log(rec *audit.Record) error {
+	c.records <- rec
+	return nil
+}
+
+func (c *auditCapture) Shutdown() error {
+	return nil
+}
+
+// Auditは別のgoroutineで書き出すので、届くまで待つ
+func (c *auditCapture) next(t *testing.T) *audit.Record {
+	select {
+	case rec := <-c.records:
+		return rec
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "audit record was not logged")
+		return nil
+	}
+}
+
+func TestCompleteOpenIdLoginAudit(t *testing.T) {
+	s, tearDown := setupServer(t)
+	defer tearDown()
+
+	capture := &auditCapture{records: make(chan *audit.Record, 10)}
+	s.Audit.AddTarget(capture)
+
+	complete := func(query string, cookie *http.Cookie) *httptest.ResponseRecorder {
+		handler := Handler{
+			GetGlobalAppOptions: s.AppOptions,
+			HandleFunc:          completeOpenIdLogin,
+			HandlerName:         "completeOpenIdLogin",
+		}
+
+		r := httptest.NewRequest(http.MethodGet, "/login/openid/complete?"+query, nil)
+		if cookie != nil {
+			r.AddCookie(cookie)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		return w
+	}
+
+	t.Run("idp error", func(t *testing.T) {
+		w := complete("error=access_denied", nil)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		rec := capture.next(t)
+		assert.Equal(t, "completeOpenIdLogin", rec.Event)
+		assert.Equal(t, audit.Fail, rec.Status)
+		assert.Equal(t, "access_denied", rec.Meta["idp_error"])
+		assert.Equal(t, "api.openid.complete.idp_error.app_error", rec.Meta["err"])
+	})
+
+	t.Run("state mismatch", func(t *testing.T) {
+		state := model.NewRandomString(model.TOKEN_SIZE)
+		w := complete("code=code&state="+state, &http.Cookie{Name: model.SESSION_COOKIE_OPENID, Value: model.NewRandomString(model.TOKEN_SIZE)})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		rec := capture.next(t)
+		assert.Equal(t, audit.Fail, rec.Status)
+		assert.Equal(t, "api.openid.complete.state_mismatch.app_error", rec.Meta["err"])
+	})
+}
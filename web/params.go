@@ -20,6 +20,7 @@ type Params struct {
 	BestId                  string
 	Page                    int
 	PerPage                 int
+	Cursor                  string
 	FromDate                int64
 	ToDate                  int64
 	Min                     *int
@@ -45,7 +46,12 @@ type Params struct {
 	ReviewType              string
 	TopUsersOrPostsInterval string
 	HookId                  string
+	HistoryId               string
+	IncomingHookId          string
+	HookToken               string
+	BotUserId               string
 	AppId                   string
+	JobId                   string
 }
 
 func ParamsFromRequest(r *http.Request) *Params {
@@ -81,6 +87,8 @@ func ParamsFromRequest(r *http.Request) *Params {
 		params.Max = &val
 	}
 
+	params.Cursor = query.Get("cursor")
+
 	if val, err := strconv.Atoi(query.Get("page")); err != nil || val < 0 {
 		params.Page = PAGE_DEFAULT
 	} else {
@@ -236,9 +244,29 @@ func ParamsFromRequest(r *http.Request) *Params {
 		params.HookId = val
 	}
 
+	if val, ok := props["history_id"]; ok {
+		params.HistoryId = val
+	}
+
+	if val, ok := props["incoming_hook_id"]; ok {
+		params.IncomingHookId = val
+	}
+
+	if val, ok := props["token"]; ok {
+		params.HookToken = val
+	}
+
+	if val, ok := props["bot_user_id"]; ok {
+		params.BotUserId = val
+	}
+
 	if val, ok := props["app_id"]; ok {
 		params.AppId = val
 	}
 
+	if val, ok := props["job_id"]; ok {
+		params.JobId = val
+	}
+
 	return params
 }
@@ -26,6 +26,7 @@ func New(config configservice.ConfigService, globalOptions app.AppOptionCreator,
 	}
 
 	web.InitOAuth()
+	web.InitOpenId()
 
 	return web
 }
@@ -36,6 +37,16 @@ func ReturnStatusOK(w http.ResponseWriter) {
 	w.Write([]byte(model.MapToJson(m)))
 }
 
+// 前後のページのカーソルをヘッダーで返す。配列をそのまま返すAPIでもレスポンスの形を変えずに済む
+func WriteCursorHeaders(w http.ResponseWriter, page model.CursorPage) {
+	if page.NextCursor != "" {
+		w.Header().Set(model.HEADER_NEXT_CURSOR, page.NextCursor)
+	}
+	if page.PrevCursor != "" {
+		w.Header().Set(model.HEADER_PREV_CURSOR, page.PrevCursor)
+	}
+}
+
 func IsApiCall(config configservice.ConfigService, r *http.Request) bool {
 	subpath, _ := utils.GetSubpathFromConfig(config.Config())
 